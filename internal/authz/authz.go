@@ -0,0 +1,229 @@
+// Package authz decides which agent roles may run destructive gt commands.
+//
+// Agents run gt with GT_ROLE (and BD_ACTOR) set by their session. Without an
+// authorization check, a polecat could halt the town or stop other agents'
+// sessions. Commands run without either variable are the human overseer and
+// are always allowed.
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// ErrDenied is returned (wrapped) when a role may not run a command.
+var ErrDenied = errors.New("permission denied")
+
+// RoleOverseer is the human at the terminal: no GT_ROLE or BD_ACTOR.
+const RoleOverseer session.Role = "overseer"
+
+// Role sets for policies.
+var (
+	// townOperators may halt the town or stop town-level services.
+	townOperators = []session.Role{session.RoleMayor}
+
+	// agentSupervisors may stop and restart any agent's session. The
+	// witness may also stop its own rig's polecats (see witnessScoped).
+	agentSupervisors = []session.Role{session.RoleMayor, session.RoleDeacon}
+)
+
+// policy maps a command path (without the leading "gt ") to the agent roles
+// allowed to run it. The overseer may run anything; commands not listed are
+// open to every role.
+var policy = map[string][]session.Role{
+	// Halting the town
+	"down":           townOperators,
+	"shutdown":       townOperators,
+	"daemon stop":    townOperators,
+	"mayor stop":     townOperators,
+	"mayor restart":  townOperators,
+	"deacon stop":    townOperators,
+	"deacon restart": townOperators,
+	"rig remove":     townOperators,
+	"rig shutdown":   townOperators,
+	"rig stop":       townOperators,
+	"rig reboot":     townOperators,
+
+	// Stopping other agents' sessions. The deacon force-kills agents that
+	// fail its health checks, so force-kill is a supervisor's, not only
+	// the town operator's.
+	"deacon force-kill":  agentSupervisors,
+	"narrator stop":      agentSupervisors,
+	"rig restart":        agentSupervisors,
+	"witness stop":       agentSupervisors,
+	"witness restart":    agentSupervisors,
+	"refinery stop":      agentSupervisors,
+	"refinery restart":   agentSupervisors,
+	"session stop":       agentSupervisors,
+	"session restart":    agentSupervisors,
+	"polecat nuke":       agentSupervisors,
+	"polecat remove":     agentSupervisors,
+	"crew stop":          agentSupervisors,
+	"crew restart":       agentSupervisors,
+	"crew remove":        agentSupervisors,
+	"orphans kill":       agentSupervisors,
+	"orphans procs kill": agentSupervisors,
+}
+
+// witnessScoped lists the commands a witness may run on its own rig's
+// polecats, with the rig each argument targets ("" if it names none).
+var witnessScoped = map[string]func(arg string) string{
+	"polecat nuke":    targetRig, // <rig>/<polecat> or <rig> --all
+	"polecat remove":  targetRig,
+	"session stop":    addressRig, // <rig>/<polecat>
+	"session restart": addressRig,
+}
+
+// targetRig returns the rig of a <rig>/<polecat> address or bare <rig>.
+func targetRig(arg string) string {
+	rig, _, _ := strings.Cut(arg, "/")
+	return rig
+}
+
+// addressRig returns the rig of a <rig>/<polecat> address. A bare polecat
+// name is resolved against the working directory, so it names no rig here.
+func addressRig(arg string) string {
+	rig, _, found := strings.Cut(arg, "/")
+	if !found {
+		return ""
+	}
+	return rig
+}
+
+// Caller identifies who is running a command.
+type Caller struct {
+	Role  session.Role // RoleOverseer for the human
+	Actor string       // GT_ROLE or BD_ACTOR as given, "" for the human
+	Rig   string       // the caller's rig, "" for town-level roles
+}
+
+// CallerFromEnv identifies the caller from GT_ROLE, falling back to BD_ACTOR.
+// The rig comes from the address given, or else GT_RIG.
+func CallerFromEnv() Caller {
+	for _, key := range []string{"GT_ROLE", "BD_ACTOR"} {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			caller := Caller{Role: ParseRole(value), Actor: value, Rig: parseRig(value)}
+			if caller.Rig == "" {
+				caller.Rig = strings.TrimSpace(os.Getenv("GT_RIG"))
+			}
+			return caller
+		}
+	}
+	return Caller{Role: RoleOverseer}
+}
+
+// ParseRole extracts the role from a GT_ROLE or BD_ACTOR value, which may be
+// a bare role ("polecat") or an address ("gastown/polecats/Toast",
+// "gastown/crew/max", "gastown/witness", "mayor/"). Unrecognized values
+// return the value itself, which no policy allows.
+func ParseRole(value string) session.Role {
+	parts := strings.Split(strings.Trim(value, "/"), "/")
+	switch {
+	case len(parts) == 1:
+		if parts[0] == "polecats" {
+			return session.RolePolecat
+		}
+		return session.Role(parts[0])
+	case len(parts) == 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return session.Role(parts[1])
+	case len(parts) >= 2 && parts[1] == "crew":
+		return session.RoleCrew
+	case len(parts) >= 2 && parts[1] == "polecats":
+		return session.RolePolecat
+	case len(parts) == 2:
+		// <rig>/<name> is a polecat address
+		return session.RolePolecat
+	}
+	return session.Role(value)
+}
+
+// parseRig returns the rig of a rig-level address ("gastown/witness",
+// "gastown/polecats/Toast"), or "" for a bare role or town-level address.
+func parseRig(value string) string {
+	parts := strings.Split(strings.Trim(value, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
+
+// Check reports whether caller may run command, the command path without
+// the leading "gt " (e.g. "polecat nuke"), on args. The error wraps
+// ErrDenied.
+func Check(caller Caller, command string, args []string) error {
+	if caller.Role == RoleOverseer {
+		return nil
+	}
+	allowed, restricted := policy[command]
+	if !restricted {
+		return nil
+	}
+	for _, role := range allowed {
+		if caller.Role == role {
+			return nil
+		}
+	}
+	if rigOf, ok := witnessScoped[command]; ok && caller.Role == session.RoleWitness {
+		return checkOwnRig(caller, command, args, rigOf)
+	}
+	return fmt.Errorf("%w: %s agents may not run 'gt %s' (allowed: overseer, %s)",
+		ErrDenied, caller.Role, command, joinRoles(allowed))
+}
+
+// checkOwnRig allows a witness command only if every argument targets a
+// polecat in the witness's own rig.
+func checkOwnRig(caller Caller, command string, args []string, rigOf func(string) string) error {
+	if caller.Rig == "" {
+		return fmt.Errorf("%w: witness has no rig (GT_RIG unset), so may not run 'gt %s'", ErrDenied, command)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: witnesses may only run 'gt %s' on %s's polecats", ErrDenied, command, caller.Rig)
+	}
+	for _, arg := range args {
+		rig := rigOf(arg)
+		if rig == "" {
+			return fmt.Errorf("%w: witnesses must name the rig for 'gt %s' (use %s/%s)", ErrDenied, command, caller.Rig, arg)
+		}
+		if rig != caller.Rig {
+			return fmt.Errorf("%w: the %s witness may not run 'gt %s' on %s's polecats (%s)",
+				ErrDenied, caller.Rig, command, rig, arg)
+		}
+	}
+	return nil
+}
+
+// Authorize checks the caller from the environment and records a denial as
+// an audit event.
+func Authorize(command string, args []string) error {
+	caller := CallerFromEnv()
+	err := Check(caller, command, args)
+	if err != nil {
+		_ = events.LogAudit(events.TypeAuthzDenied, caller.Actor,
+			events.AuthzDeniedPayload("gt "+command, string(caller.Role), err.Error()))
+	}
+	return err
+}
+
+// Restricted returns the restricted command paths, sorted.
+func Restricted() []string {
+	commands := make([]string, 0, len(policy))
+	for command := range policy {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+func joinRoles(roles []session.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return strings.Join(names, ", ")
+}
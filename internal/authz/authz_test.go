@@ -0,0 +1,136 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		value string
+		want  session.Role
+	}{
+		{"polecat", session.RolePolecat},
+		{"mayor", session.RoleMayor},
+		{"mayor/", session.RoleMayor},
+		{"deacon/", session.RoleDeacon},
+		{"gastown/witness", session.RoleWitness},
+		{"gastown/refinery", session.RoleRefinery},
+		{"gastown/crew/max", session.RoleCrew},
+		{"gastown/polecats/Toast", session.RolePolecat},
+		{"gastown/Toast", session.RolePolecat},
+	}
+	for _, tt := range tests {
+		if got := ParseRole(tt.value); got != tt.want {
+			t.Errorf("ParseRole(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		role    session.Role
+		command string
+		allowed bool
+	}{
+		{RoleOverseer, "down", true},
+		{session.RoleMayor, "down", true},
+		{session.RoleDeacon, "down", false},
+		{session.RolePolecat, "down", false},
+		{session.RolePolecat, "shutdown", false},
+		{session.RoleDeacon, "rig reboot", false},
+		{session.RoleMayor, "rig reboot", true},
+		{session.RoleDeacon, "polecat nuke", true},
+		{session.RolePolecat, "polecat nuke", false},
+		{session.RoleCrew, "session stop", false},
+		{session.RoleRefinery, "witness stop", false},
+		{session.RoleWitness, "orphans procs kill", false},
+		{session.RoleDeacon, "orphans procs kill", true},
+		{session.RolePolecat, "deacon force-kill", false},
+		{session.RoleCrew, "deacon force-kill", false},
+		{session.RoleWitness, "deacon force-kill", false},
+		{session.RoleDeacon, "deacon force-kill", true},
+		{session.RoleMayor, "deacon force-kill", true},
+		{session.RolePolecat, "narrator stop", false},
+		{session.RoleCrew, "narrator stop", false},
+		{session.RoleDeacon, "narrator stop", true},
+		{session.RoleMayor, "narrator stop", true},
+		{session.RolePolecat, "mail send", true},
+		{session.RolePolecat, "done", true},
+		{"bogus", "crew stop", false},
+	}
+	for _, tt := range tests {
+		err := Check(Caller{Role: tt.role}, tt.command, nil)
+		if tt.allowed && err != nil {
+			t.Errorf("Check(%s, %q) = %v, want allowed", tt.role, tt.command, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrDenied) {
+			t.Errorf("Check(%s, %q) = %v, want ErrDenied", tt.role, tt.command, err)
+		}
+	}
+}
+
+func TestCheck_WitnessOwnRig(t *testing.T) {
+	witness := Caller{Role: session.RoleWitness, Actor: "gastown/witness", Rig: "gastown"}
+	tests := []struct {
+		command string
+		args    []string
+		allowed bool
+	}{
+		{"session stop", []string{"gastown/Toast"}, true},
+		{"session restart", []string{"gastown/Toast"}, true},
+		{"polecat nuke", []string{"gastown/Toast", "gastown/Nux"}, true},
+		{"polecat nuke", []string{"gastown"}, true}, // <rig> --all
+		{"polecat remove", []string{"gastown/Toast"}, true},
+		{"session stop", []string{"beads/Toast"}, false},
+		{"session stop", []string{"Toast"}, false}, // rig inferred from cwd
+		{"polecat nuke", []string{"gastown/Toast", "beads/Nux"}, false},
+		{"polecat nuke", []string{"beads"}, false},
+		{"polecat nuke", nil, false},
+		{"crew stop", []string{"max"}, false},
+		{"crew remove", []string{"max"}, false},
+		{"witness stop", []string{"gastown"}, false},
+		{"rig restart", []string{"gastown"}, false},
+	}
+	for _, tt := range tests {
+		err := Check(witness, tt.command, tt.args)
+		if tt.allowed && err != nil {
+			t.Errorf("Check(witness, %q, %v) = %v, want allowed", tt.command, tt.args, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrDenied) {
+			t.Errorf("Check(witness, %q, %v) = %v, want ErrDenied", tt.command, tt.args, err)
+		}
+	}
+
+	if err := Check(Caller{Role: session.RoleWitness}, "session stop", []string{"gastown/Toast"}); !errors.Is(err, ErrDenied) {
+		t.Errorf("witness without a rig: err = %v, want ErrDenied", err)
+	}
+}
+
+func TestCallerFromEnv(t *testing.T) {
+	t.Setenv("GT_ROLE", "")
+	t.Setenv("BD_ACTOR", "")
+	t.Setenv("GT_RIG", "")
+	if caller := CallerFromEnv(); caller.Role != RoleOverseer {
+		t.Errorf("no env: role = %q, want overseer", caller.Role)
+	}
+
+	t.Setenv("BD_ACTOR", "gastown/polecats/Toast")
+	if caller := CallerFromEnv(); caller.Role != session.RolePolecat || caller.Actor != "gastown/polecats/Toast" || caller.Rig != "gastown" {
+		t.Errorf("BD_ACTOR: caller = %+v", caller)
+	}
+
+	t.Setenv("GT_ROLE", "gastown/witness")
+	if caller := CallerFromEnv(); caller.Role != session.RoleWitness {
+		t.Errorf("GT_ROLE wins over BD_ACTOR: role = %q", caller.Role)
+	}
+
+	// Sessions set a bare GT_ROLE and the rig in GT_RIG
+	t.Setenv("GT_ROLE", "witness")
+	t.Setenv("GT_RIG", "beads")
+	if caller := CallerFromEnv(); caller.Role != session.RoleWitness || caller.Rig != "beads" {
+		t.Errorf("GT_RIG: caller = %+v, want the beads witness", caller)
+	}
+}
@@ -0,0 +1,194 @@
+// Package backoff computes exponential wait intervals for loops that poll
+// less often while the town is idle: the daemon heartbeat and patrol
+// agents awaiting a signal.
+package backoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultMultiplier is the growth factor when a policy doesn't set one.
+const DefaultMultiplier = 2
+
+// Policy describes an exponential backoff. The interval starts at Base and
+// is multiplied by Multiplier for each idle cycle, up to Max.
+type Policy struct {
+	Base       time.Duration
+	Max        time.Duration // 0 means no cap
+	Multiplier float64       // 0 means DefaultMultiplier
+
+	// Jitter randomizes each interval by up to this fraction either way
+	// (0.1 is ±10%), so loops started together drift apart.
+	Jitter float64
+
+	// ResetOnActivity drops back to Base when activity is newer than the
+	// last seen.
+	ResetOnActivity bool
+}
+
+// Fixed returns a policy that always waits interval.
+func Fixed(interval time.Duration) Policy {
+	return Policy{Base: interval, Max: interval}
+}
+
+// FromConfig builds a policy from its settings, with base used when the
+// settings don't give one. A nil cfg is a fixed interval of base.
+func FromConfig(cfg *config.BackoffConfig, base time.Duration) (Policy, error) {
+	if cfg == nil {
+		return Fixed(base), nil
+	}
+	p := Policy{
+		Base:            base,
+		Multiplier:      cfg.Multiplier,
+		Jitter:          cfg.Jitter,
+		ResetOnActivity: cfg.ResetOnActivity == nil || *cfg.ResetOnActivity,
+	}
+	var err error
+	if cfg.Base != "" {
+		if p.Base, err = time.ParseDuration(cfg.Base); err != nil {
+			return Policy{}, fmt.Errorf("invalid backoff base: %w", err)
+		}
+	}
+	if cfg.Max != "" {
+		if p.Max, err = time.ParseDuration(cfg.Max); err != nil {
+			return Policy{}, fmt.Errorf("invalid backoff max: %w", err)
+		}
+	}
+	return p, p.Validate()
+}
+
+// Validate checks that the policy describes a usable backoff.
+func (p Policy) Validate() error {
+	switch {
+	case p.Base <= 0:
+		return errors.New("backoff base must be positive")
+	case p.Max < 0:
+		return errors.New("backoff max must not be negative")
+	case p.Multiplier != 0 && p.Multiplier < 1:
+		return fmt.Errorf("backoff multiplier %v is less than 1", p.Multiplier)
+	case p.Jitter < 0 || p.Jitter >= 1:
+		return fmt.Errorf("backoff jitter %v is outside [0, 1)", p.Jitter)
+	}
+	return nil
+}
+
+// Interval returns the wait after level idle cycles, without jitter:
+// min(Base * Multiplier^level, Max).
+func (p Policy) Interval(level int) time.Duration {
+	mult := p.Multiplier
+	if mult == 0 {
+		mult = DefaultMultiplier
+	}
+	d := float64(p.Base) * math.Pow(mult, float64(level))
+	if p.Max > 0 && d > float64(p.Max) {
+		return p.Max
+	}
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// capped reports whether level has reached the policy's maximum interval,
+// beyond which backing off further changes nothing.
+func (p Policy) capped(level int) bool {
+	return p.Max > 0 && p.Interval(level) >= p.Max
+}
+
+// State is a backoff's progress, persisted between runs.
+type State struct {
+	// Level is how many times the last interval handed out was backed
+	// off from Base.
+	Level int `json:"level"`
+
+	// LastActivity is the newest activity seen; newer activity resets the
+	// level under ResetOnActivity.
+	LastActivity time.Time `json:"last_activity,omitempty"`
+
+	// Interval is the last interval handed out ("" before the first).
+	Interval string `json:"interval,omitempty"`
+}
+
+// LoadState reads backoff state from path. A missing or unreadable file is
+// a fresh start.
+func LoadState(path string) *State {
+	s := &State{}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed by the caller
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return &State{}
+	}
+	return s
+}
+
+// Save writes the state to path.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, s)
+}
+
+// Backoff applies a policy cycle by cycle, tracking its state.
+type Backoff struct {
+	Policy Policy
+	State  *State
+
+	rand func() float64 // in [0, 1); replaced in tests
+}
+
+// New returns a backoff following policy from state (nil for a fresh
+// start).
+func New(policy Policy, state *State) *Backoff {
+	if state == nil {
+		state = &State{}
+	}
+	return &Backoff{Policy: policy, State: state, rand: rand.Float64} //nolint:gosec // G404: jitter needn't be cryptographic
+}
+
+// NextInterval returns how long to wait before the next cycle, given the
+// newest activity observed (zero if unknown). The first interval is Base;
+// each later one backs off a level, unless the activity is newer than any
+// seen before and the policy resets on activity.
+func (b *Backoff) NextInterval(lastActivity time.Time) time.Duration {
+	active := lastActivity.After(b.State.LastActivity)
+	if active {
+		b.State.LastActivity = lastActivity
+	}
+	switch {
+	case active && b.Policy.ResetOnActivity:
+		b.State.Level = 0
+	case b.State.Interval != "" && !b.Policy.capped(b.State.Level):
+		b.State.Level++
+	}
+	d := b.jitter(b.Policy.Interval(b.State.Level))
+	b.State.Interval = d.String()
+	return d
+}
+
+// Reset makes the next interval Base again, e.g. when the loop is woken
+// by a signal.
+func (b *Backoff) Reset() {
+	b.State.Level = 0
+	b.State.Interval = ""
+}
+
+func (b *Backoff) jitter(d time.Duration) time.Duration {
+	if b.Policy.Jitter == 0 {
+		return d
+	}
+	spread := b.Policy.Jitter * (2*b.rand() - 1)
+	return time.Duration(float64(d) * (1 + spread))
+}
@@ -0,0 +1,130 @@
+package backoff
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPolicyInterval(t *testing.T) {
+	p := Policy{Base: 30 * time.Second, Max: 5 * time.Minute}
+	tests := []struct {
+		level int
+		want  time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{3, 4 * time.Minute},
+		{4, 5 * time.Minute}, // capped
+		{1000, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := p.Interval(tt.level); got != tt.want {
+			t.Errorf("Interval(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+
+	p = Policy{Base: time.Second, Multiplier: 1.5}
+	if got := p.Interval(2); got != 2250*time.Millisecond {
+		t.Errorf("Interval(2) with multiplier 1.5 = %v, want 2.25s", got)
+	}
+	if got := p.Interval(10000); got <= 0 {
+		t.Errorf("uncapped Interval(10000) = %v, want it to saturate, not overflow", got)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	p, err := FromConfig(nil, 3*time.Minute)
+	if err != nil || p.Interval(5) != 3*time.Minute {
+		t.Errorf("nil config = %+v, %v; want fixed 3m", p, err)
+	}
+
+	off := false
+	p, err = FromConfig(&config.BackoffConfig{Max: "30m", Multiplier: 3, Jitter: 0.1, ResetOnActivity: &off}, 3*time.Minute)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	if p.Base != 3*time.Minute || p.Max != 30*time.Minute || p.Multiplier != 3 || p.ResetOnActivity {
+		t.Errorf("policy = %+v", p)
+	}
+
+	for _, cfg := range []*config.BackoffConfig{
+		{Base: "soon"},
+		{Max: "later"},
+		{Multiplier: 0.5},
+		{Jitter: 1},
+	} {
+		if _, err := FromConfig(cfg, time.Minute); err == nil {
+			t.Errorf("FromConfig(%+v) accepted an invalid policy", cfg)
+		}
+	}
+}
+
+func TestNextInterval(t *testing.T) {
+	b := New(Policy{Base: time.Minute, Max: 4 * time.Minute, ResetOnActivity: true}, nil)
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var got []time.Duration
+	for i := 0; i < 4; i++ {
+		got = append(got, b.NextInterval(t0))
+	}
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("idle intervals = %v, want %v", got, want)
+		}
+	}
+	if b.State.Level != 2 {
+		t.Errorf("level = %d, want 2 (capped)", b.State.Level)
+	}
+
+	// New activity drops back to base; the same activity again doesn't
+	if d := b.NextInterval(t0.Add(time.Minute)); d != time.Minute {
+		t.Errorf("after activity = %v, want base", d)
+	}
+	if d := b.NextInterval(t0.Add(time.Minute)); d != 2*time.Minute {
+		t.Errorf("after stale activity = %v, want backed off", d)
+	}
+
+	b.Policy.ResetOnActivity = false
+	if d := b.NextInterval(t0.Add(time.Hour)); d != 4*time.Minute {
+		t.Errorf("without reset on activity = %v, want backed off", d)
+	}
+	b.Reset()
+	if d := b.NextInterval(time.Time{}); d != time.Minute {
+		t.Errorf("after Reset = %v, want base", d)
+	}
+}
+
+func TestNextInterval_Jitter(t *testing.T) {
+	b := New(Policy{Base: 100 * time.Second, Jitter: 0.1}, nil)
+	b.rand = func() float64 { return 0 }
+	if d := b.NextInterval(time.Time{}); d != 90*time.Second {
+		t.Errorf("low jitter = %v, want 90s", d)
+	}
+	b.Reset()
+	b.rand = func() float64 { return 0.5 }
+	if d := b.NextInterval(time.Time{}); d != 100*time.Second {
+		t.Errorf("middle jitter = %v, want 100s", d)
+	}
+}
+
+func TestStateSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon", "backoff.json")
+	if s := LoadState(path); s.Level != 0 || s.Interval != "" {
+		t.Errorf("missing state = %+v, want fresh", s)
+	}
+
+	b := New(Policy{Base: time.Minute}, nil)
+	b.NextInterval(time.Time{})
+	b.NextInterval(time.Time{})
+	if err := b.State.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	resumed := New(b.Policy, LoadState(path))
+	if d := resumed.NextInterval(time.Time{}); d != 4*time.Minute {
+		t.Errorf("resumed interval = %v, want 4m", d)
+	}
+}
@@ -0,0 +1,254 @@
+// Package blackboard is a small key/value store for transient coordination
+// state shared between agents: who holds a lock-ish claim, which rig is
+// mid-deploy, the current release candidate, and the like. It replaces
+// encoding such state in mail subjects or scratch files.
+//
+// Keys live in namespaces, one JSON file per namespace under
+// .runtime/blackboard/ in the town root. Entries may carry a TTL, after
+// which they read as absent. Writers serialize on a per-namespace advisory
+// lock, so concurrent gt processes don't lose updates.
+package blackboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultNamespace is the namespace used when none is given.
+const DefaultNamespace = "town"
+
+// lockTimeout bounds how long a writer waits for another process.
+const lockTimeout = 5 * time.Second
+
+// ErrNotFound is returned for keys that are unset or expired.
+var ErrNotFound = errors.New("key not found")
+
+var nameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Entry is a value on the blackboard.
+type Entry struct {
+	Key       string     `json:"key"`
+	Value     string     `json:"value"`
+	SetBy     string     `json:"set_by,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Version   int        `json:"version"` // bumped on every set
+}
+
+// Expired reports whether the entry's TTL has passed at now.
+func (e *Entry) Expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// Store is a town's blackboard.
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// New returns the blackboard for a town.
+func New(townRoot string) *Store {
+	return &Store{dir: filepath.Join(townRoot, ".runtime", "blackboard"), now: time.Now}
+}
+
+func (s *Store) path(namespace string) string {
+	return filepath.Join(s.dir, namespace+".json")
+}
+
+func validateNamespace(namespace string) error {
+	if !nameRe.MatchString(namespace) {
+		return fmt.Errorf("invalid namespace %q", namespace)
+	}
+	return nil
+}
+
+func validate(namespace, key string) error {
+	if err := validateNamespace(namespace); err != nil {
+		return err
+	}
+	if !nameRe.MatchString(key) {
+		return fmt.Errorf("invalid key %q", key)
+	}
+	return nil
+}
+
+// load reads a namespace, dropping expired entries.
+func (s *Store) load(namespace string) (map[string]*Entry, error) {
+	data, err := os.ReadFile(s.path(namespace)) //nolint:gosec // G304: namespace is validated
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]*Entry), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]*Entry)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing blackboard namespace %s: %w", namespace, err)
+	}
+	now := s.now()
+	for key, e := range entries {
+		if e.Expired(now) {
+			delete(entries, key)
+		}
+	}
+	return entries, nil
+}
+
+// update applies fn to a namespace under its lock and saves the result.
+func (s *Store) update(namespace string, fn func(map[string]*Entry) error) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating blackboard dir: %w", err)
+	}
+	lock := flock.New(s.path(namespace) + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+	if err != nil || !locked {
+		return fmt.Errorf("locking blackboard namespace %s: timed out", namespace)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := s.load(namespace)
+	if err != nil {
+		return err
+	}
+	if err := fn(entries); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(s.path(namespace), entries)
+}
+
+// Get returns the entry for key, or ErrNotFound.
+func (s *Store) Get(namespace, key string) (*Entry, error) {
+	if err := validate(namespace, key); err != nil {
+		return nil, err
+	}
+	entries, err := s.load(namespace)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return e, nil
+}
+
+// Set stores value under key. ttl > 0 makes the entry expire after ttl.
+func (s *Store) Set(namespace, key, value, setBy string, ttl time.Duration) (*Entry, error) {
+	if err := validate(namespace, key); err != nil {
+		return nil, err
+	}
+	var entry *Entry
+	err := s.update(namespace, func(entries map[string]*Entry) error {
+		now := s.now().UTC()
+		version := 1
+		if old, ok := entries[key]; ok {
+			version = old.Version + 1
+		}
+		entry = &Entry{Key: key, Value: value, SetBy: setBy, UpdatedAt: now, Version: version}
+		if ttl > 0 {
+			expires := now.Add(ttl)
+			entry.ExpiresAt = &expires
+		}
+		entries[key] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Delete removes key. Deleting an absent key returns ErrNotFound.
+func (s *Store) Delete(namespace, key string) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	return s.update(namespace, func(entries map[string]*Entry) error {
+		if _, ok := entries[key]; !ok {
+			return ErrNotFound
+		}
+		delete(entries, key)
+		return nil
+	})
+}
+
+// List returns a namespace's live entries sorted by key.
+func (s *Store) List(namespace string) ([]*Entry, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	entries, err := s.load(namespace)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list, nil
+}
+
+// Namespaces returns the namespaces that have been written, sorted.
+func (s *Store) Namespaces() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(p), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Watch polls key every interval and calls fn with the entry whenever it
+// changes, including the first poll. A nil entry means the key is unset or
+// expired. Watch returns when ctx is done (nil error) or fn returns an
+// error.
+func (s *Store) Watch(ctx context.Context, namespace, key string, interval time.Duration, fn func(*Entry) error) error {
+	if err := validate(namespace, key); err != nil {
+		return err
+	}
+	first := true
+	var last *Entry
+	for {
+		e, err := s.Get(namespace, key)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if first || changed(last, e) {
+			if err := fn(e); err != nil {
+				return err
+			}
+			first = false
+			last = e
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func changed(old, cur *Entry) bool {
+	if old == nil || cur == nil {
+		return old != cur
+	}
+	return old.Version != cur.Version || !old.UpdatedAt.Equal(cur.UpdatedAt)
+}
@@ -0,0 +1,172 @@
+package blackboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetGetDelete(t *testing.T) {
+	s := New(t.TempDir())
+
+	if _, err := s.Get("town", "deploy-window"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on empty store: err = %v, want ErrNotFound", err)
+	}
+
+	e, err := s.Set("town", "deploy-window", "open", "mayor", 0)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if e.Version != 1 || e.ExpiresAt != nil {
+		t.Errorf("first Set = %+v, want version 1 and no expiry", e)
+	}
+	e, err = s.Set("town", "deploy-window", "closed", "gastown/witness", 0)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if e.Version != 2 {
+		t.Errorf("second Set version = %d, want 2", e.Version)
+	}
+
+	got, err := s.Get("town", "deploy-window")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Value != "closed" || got.SetBy != "gastown/witness" {
+		t.Errorf("Get = %+v", got)
+	}
+
+	// Namespaces are independent
+	if _, err := s.Get("gastown", "deploy-window"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get from other namespace: err = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Delete("town", "deploy-window"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete("town", "deploy-window"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	s := New(t.TempDir())
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Set("town", "claim", "Toast", "gastown/Toast", 10*time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := s.Set("town", "sticky", "yes", "mayor", 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := s.Get("town", "claim"); err != nil {
+		t.Fatalf("Get before expiry: %v", err)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if _, err := s.Get("town", "claim"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after expiry: err = %v, want ErrNotFound", err)
+	}
+	list, err := s.List("town")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 || list[0].Key != "sticky" {
+		t.Errorf("List after expiry = %+v, want only sticky", list)
+	}
+
+	// Setting an expired key starts it over
+	e, err := s.Set("town", "claim", "nux", "gastown/nux", 0)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if e.Version != 1 {
+		t.Errorf("Set after expiry version = %d, want 1", e.Version)
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	s := New(t.TempDir())
+	for _, tc := range []struct{ ns, key string }{
+		{"../etc", "key"},
+		{"town", "a/b"},
+		{"town", ""},
+		{"", "key"},
+	} {
+		if _, err := s.Set(tc.ns, tc.key, "v", "", 0); err == nil {
+			t.Errorf("Set(%q, %q) should fail", tc.ns, tc.key)
+		}
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	s := New(t.TempDir())
+	for _, ns := range []string{"town", "gastown", "beads"} {
+		if _, err := s.Set(ns, "k", "v", "", 0); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	got, err := s.Namespaces()
+	if err != nil {
+		t.Fatalf("Namespaces: %v", err)
+	}
+	want := []string{"beads", "gastown", "town"}
+	if len(got) != len(want) {
+		t.Fatalf("Namespaces = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Namespaces = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := New(t.TempDir())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	seen := make(chan *Entry, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Watch(ctx, "town", "rc", 5*time.Millisecond, func(e *Entry) error {
+			seen <- e
+			return nil
+		})
+	}()
+
+	next := func() *Entry {
+		t.Helper()
+		select {
+		case e := <-seen:
+			return e
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for watch")
+			return nil
+		}
+	}
+
+	if e := next(); e != nil {
+		t.Errorf("initial watch value = %+v, want nil", e)
+	}
+	if _, err := s.Set("town", "rc", "v1.4.0-rc1", "mayor", 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if e := next(); e == nil || e.Value != "v1.4.0-rc1" {
+		t.Errorf("watch after Set = %+v", e)
+	}
+	if err := s.Delete("town", "rc"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if e := next(); e != nil {
+		t.Errorf("watch after Delete = %+v, want nil", e)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch returned %v after cancel", err)
+	}
+}
@@ -3,10 +3,16 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +29,8 @@ var (
 	activityIssue     string
 	activityTo        string
 	activityCount     int
+	activityCommit    string
+	activityMR        string
 )
 
 var activityCmd = &cobra.Command{
@@ -49,12 +57,21 @@ Supported event types for witness patrol:
   escalation_sent  - When witness escalates to Mayor/Deacon
   patrol_complete  - When patrol cycle finishes
 
+Witness patrol events also build a structured patrol report for the rig
+(<rig>/witness/patrol/), which 'gt status' and 'gt witness report' read.
+Pass --reason with polecat_checked to record a stuck or idle diagnosis.
+
 Supported event types for refinery:
   merge_started    - When refinery starts a merge
-  merge_complete   - When merge succeeds
+  merged           - When merge succeeds
   merge_failed     - When merge fails
+  merge_skipped    - When an MR is skipped (e.g. superseded)
   queue_processed  - When refinery finishes processing queue
 
+For merge events, --target is the source branch, --polecat the worker and
+--issue the source issue. With --commit, the event also records the files
+touched, line counts and branch commits, read from the rig's repo.
+
 Common options:
   --actor    Who is emitting the event (e.g., greenplace/witness)
   --rig      Which rig the event is about
@@ -63,9 +80,11 @@ Common options:
 Examples:
   gt activity emit patrol_started --rig greenplace --count 3
   gt activity emit polecat_checked --rig greenplace --polecat Toast --status working --issue gp-xyz
+  gt activity emit polecat_checked --rig greenplace --polecat Nux --status stuck --reason "no output for 30m"
   gt activity emit polecat_nudged --rig greenplace --polecat Toast --reason "idle for 10 minutes"
   gt activity emit escalation_sent --rig greenplace --target Toast --to mayor --reason "unresponsive"
-  gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"`,
+  gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"
+  gt activity emit merged --rig greenplace --mr gp-mr1 --target polecat/Toast --polecat Toast --commit 3f2a9c1`,
 	Args: cobra.ExactArgs(1),
 	RunE: runActivityEmit,
 }
@@ -82,6 +101,8 @@ func init() {
 	activityEmitCmd.Flags().StringVar(&activityIssue, "issue", "", "Issue ID (for polecat_checked)")
 	activityEmitCmd.Flags().StringVar(&activityTo, "to", "", "Escalation target (for escalation_sent: mayor, deacon)")
 	activityEmitCmd.Flags().IntVar(&activityCount, "count", 0, "Polecat count (for patrol events)")
+	activityEmitCmd.Flags().StringVar(&activityCommit, "commit", "", "Merge commit SHA (for merge events; adds diffstat and commit list)")
+	activityEmitCmd.Flags().StringVar(&activityMR, "mr", "", "Merge-request bead ID (for merge events)")
 
 	activityCmd.AddCommand(activityEmitCmd)
 	rootCmd.AddCommand(activityCmd)
@@ -91,7 +112,7 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 	eventType := args[0]
 
 	// Validate we're in a Gas Town workspace
-	_, err := workspace.FindFromCwdOrError()
+	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
@@ -134,20 +155,22 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 		payload = events.EscalationPayload(activityRig, activityTarget, activityTo, activityReason)
 
 	case events.TypeMergeStarted, events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
-		// Refinery events - flexible payload
-		payload = make(map[string]interface{})
-		if activityRig != "" {
-			payload["rig"] = activityRig
+		// Refinery events - typed merge payload, with diffstat and commits
+		// read from the rig's repo when --commit names the merge commit
+		info := events.MergeInfo{
+			Rig:         activityRig,
+			MR:          activityMR,
+			Branch:      activityTarget,
+			Worker:      activityPolecat,
+			SourceIssue: activityIssue,
+			MergeCommit: activityCommit,
+			Reason:      activityReason,
+			Message:     activityMessage,
 		}
-		if activityMessage != "" {
-			payload["message"] = activityMessage
-		}
-		if activityTarget != "" {
-			payload["branch"] = activityTarget
-		}
-		if activityReason != "" {
-			payload["reason"] = activityReason
+		if activityCommit != "" && activityRig != "" {
+			refinery.DescribeMerge(git.NewGit(rigRepoDir(townRoot, activityRig)), &info)
 		}
+		payload = info.Map()
 
 	default:
 		// Generic event - use whatever flags are provided
@@ -186,6 +209,13 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("emitting event: %w", err)
 	}
 
+	// Witness patrol events also build the rig's structured patrol report
+	if activityRig != "" {
+		if err := recordPatrolActivity(filepath.Join(townRoot, activityRig), eventType); err != nil {
+			fmt.Printf("%s patrol report not updated: %v\n", style.Warning.Render("⚠"), err)
+		}
+	}
+
 	// Print confirmation
 	payloadJSON, _ := json.Marshal(payload)
 	fmt.Printf("%s Emitted %s event\n", style.Success.Render("✓"), style.Bold.Render(eventType))
@@ -195,4 +225,43 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// recordPatrolActivity folds a witness patrol event into the rig's patrol
+// report (see witness.PatrolReport). Other event types are ignored.
+func recordPatrolActivity(rigPath, eventType string) error {
+	now := time.Now()
+	switch eventType {
+	case events.TypePatrolStarted:
+		return witness.BeginPatrol(rigPath, activityRig, now)
+	case events.TypePolecatChecked:
+		return witness.RecordPolecat(rigPath, activityRig, witness.PolecatReport{
+			Name:      activityPolecat,
+			Status:    activityStatus,
+			Issue:     activityIssue,
+			Diagnosis: activityReason,
+		})
+	case events.TypePolecatNudged:
+		return witness.RecordAction(rigPath, activityRig, witness.PatrolAction{
+			Time: now, Kind: witness.ActionNudge, Target: activityPolecat, Reason: activityReason,
+		})
+	case events.TypeEscalationSent:
+		return witness.RecordAction(rigPath, activityRig, witness.PatrolAction{
+			Time: now, Kind: witness.ActionEscalate, Target: activityTarget, To: activityTo, Reason: activityReason,
+		})
+	case events.TypePatrolComplete:
+		_, err := witness.CompletePatrol(rigPath, activityRig, activityMessage, now)
+		return err
+	}
+	return nil
+}
+
+// rigRepoDir returns the clone used to inspect a rig's merges: the
+// refinery's worktree if present, otherwise the mayor's.
+func rigRepoDir(townRoot, rigName string) string {
+	refineryRig := filepath.Join(townRoot, rigName, "refinery", "rig")
+	if _, err := os.Stat(refineryRig); err == nil {
+		return refineryRig
+	}
+	return filepath.Join(townRoot, rigName, "mayor", "rig")
+}
+
 // Note: detectActor is defined in sling.go and reused here
@@ -403,22 +403,13 @@ func formatTownlogSummary(e townlog.Event) string {
 func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
 	var entries []AuditEntry
 
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
+	records, err := events.ReadAll(townRoot)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No events file yet
-		}
 		return nil, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var e events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
-			continue // Skip malformed lines
-		}
+	for _, r := range records {
+		e := r.Event
 
 		// Apply actor filter
 		if actor != "" && !matchesActor(e.Actor, actor) {
@@ -455,6 +446,9 @@ func formatFeedSummary(e events.Event) string {
 		return "Slung work"
 	case events.TypeMerged:
 		if branch, ok := e.Payload["branch"].(string); ok {
+			if stat := events.DecodeMergeInfo(e.Payload).Diffstat(); stat != "" {
+				return fmt.Sprintf("Merged %s (%s)", branch, stat)
+			}
 			return fmt.Sprintf("Merged %s", branch)
 		}
 		return "Merged work"
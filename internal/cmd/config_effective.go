@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configEffectiveRig  string
+	configEffectiveJSON bool
+)
+
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Show a rig's configuration after inheritance",
+	Long: `Show a rig's effective configuration: its own settings overlaid on the
+town's, with where each value came from.
+
+Precedence, highest first:
+  1. rig     <rig>/settings/config.json
+  2. town    settings/config.json, mayor/overseer.json (notifications),
+             mayor/daemon.json (patrols)
+  3. default built-in defaults
+
+role_agents and patrols are merged key by key.
+
+Examples:
+  gt config effective --rig gastown
+  gt config effective --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEffective,
+}
+
+func init() {
+	configEffectiveCmd.Flags().StringVar(&configEffectiveRig, "rig", "", "Rig to resolve (default: inferred from current directory)")
+	configEffectiveCmd.Flags().BoolVar(&configEffectiveJSON, "json", false, "Output as JSON")
+	configCmd.AddCommand(configEffectiveCmd)
+}
+
+func runConfigEffective(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigName := configEffectiveRig
+	if rigName == "" {
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("could not determine rig (use --rig): %w", err)
+		}
+	}
+	if _, _, err := getRig(rigName); err != nil {
+		return err
+	}
+
+	eff, err := config.ResolveEffectiveRigConfig(townRoot, rigName)
+	if err != nil {
+		return err
+	}
+
+	if configEffectiveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(eff)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Effective config for "+rigName))
+	for _, s := range eff.Settings {
+		source := s.Source
+		if s.Source == config.SourceRig {
+			source = style.Bold.Render(source)
+		} else {
+			source = style.Dim.Render(source)
+		}
+		fmt.Printf("  %-32s %-20s %s\n", s.Key, s.Value, source)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+	"golang.org/x/term"
+)
+
+// destructivePlan lists what a destructive command is about to affect, so a
+// mistyped rig or agent name is caught before anything is stopped.
+type destructivePlan struct {
+	action     string   // e.g. "Stop rig gastown"
+	sessions   []string // tmux sessions to kill
+	processes  []string // processes to stop, described
+	stateFiles []string // files and directories to remove
+}
+
+func (p *destructivePlan) empty() bool {
+	return len(p.sessions) == 0 && len(p.processes) == 0 && len(p.stateFiles) == 0
+}
+
+// print shows the plan. dryRun words it as a preview.
+func (p *destructivePlan) print(dryRun bool) {
+	verb := "will"
+	if dryRun {
+		verb = "would"
+	}
+	fmt.Printf("%s %s affect:\n", style.Bold.Render(p.action), verb)
+	if p.empty() {
+		fmt.Printf("  %s\n", style.Dim.Render("nothing running"))
+	}
+	printPlanSection("Sessions", p.sessions)
+	printPlanSection("Processes", p.processes)
+	printPlanSection("State", p.stateFiles)
+}
+
+func printPlanSection(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("  %s\n", style.Dim.Render(title+":"))
+	for _, item := range items {
+		fmt.Printf("    - %s\n", item)
+	}
+}
+
+// stdinIsTerminal reports whether a human can answer a prompt.
+// Variable for tests.
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// confirmDestructive prints the plan and decides whether to proceed.
+// With dryRun it only prints. With yes, or when stdin is not a terminal
+// (scripts and agent sessions, which authz already restricts), it proceeds
+// after printing; otherwise it asks.
+func confirmDestructive(p *destructivePlan, dryRun, yes bool) bool {
+	p.print(dryRun)
+	if dryRun {
+		return false
+	}
+	if yes || p.empty() || !stdinIsTerminal() {
+		return true
+	}
+
+	fmt.Printf("Proceed? [y/N] ")
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// rigSessionsToStop lists the running sessions that stopping a rig kills:
+// its polecats, refinery and witness.
+func rigSessionsToStop(t *tmux.Tmux, r *rig.Rig) []string {
+	var sessions []string
+	if infos, err := polecat.NewSessionManager(t, r).List(); err == nil {
+		for _, info := range infos {
+			sessions = append(sessions, info.SessionID)
+		}
+	}
+	for _, name := range []string{refinery.NewManager(r).SessionName(), witness.NewManager(r).SessionName()} {
+		if has, _ := t.HasSession(name); has {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions
+}
+
+// confirmRigs builds one plan covering every named rig and confirms it.
+// Unknown names contribute nothing; the caller reports them. Returns false
+// with a nil error for a dry run.
+func confirmRigs(action string, rigMgr *rig.Manager, t *tmux.Tmux, rigNames []string, dryRun, yes bool) (bool, error) {
+	plan := &destructivePlan{action: action + " " + strings.Join(rigNames, ", ")}
+	for _, name := range rigNames {
+		if r, err := rigMgr.GetRig(name); err == nil {
+			plan.sessions = append(plan.sessions, rigSessionsToStop(t, r)...)
+		}
+	}
+	if confirmDestructive(plan, dryRun, yes) {
+		return true, nil
+	}
+	if dryRun {
+		return false, nil
+	}
+	return false, fmt.Errorf("%s canceled", strings.ToLower(action))
+}
+
+// polecatNukePlan lists the running sessions and worktrees a nuke removes.
+func polecatNukePlan(t *tmux.Tmux, targets []polecatTarget) *destructivePlan {
+	plan := &destructivePlan{action: fmt.Sprintf("Nuke %d polecat(s)", len(targets))}
+	for _, p := range targets {
+		name := session.PolecatSessionName(p.rigName, p.polecatName)
+		if has, _ := t.HasSession(name); has {
+			plan.sessions = append(plan.sessions, name)
+		}
+		plan.stateFiles = append(plan.stateFiles, filepath.Join(p.r.Path, "polecats", p.polecatName))
+	}
+	return plan
+}
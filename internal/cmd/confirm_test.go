@@ -0,0 +1,39 @@
+package cmd
+
+import "testing"
+
+func TestConfirmDestructive(t *testing.T) {
+	orig := stdinIsTerminal
+	defer func() { stdinIsTerminal = orig }()
+	stdinIsTerminal = func() bool { return false }
+
+	plan := &destructivePlan{action: "Stop rig gastown", sessions: []string{"gt-gastown-witness"}}
+
+	if confirmDestructive(plan, true, false) {
+		t.Error("dry run should not proceed")
+	}
+	if !confirmDestructive(plan, false, true) {
+		t.Error("--yes should proceed")
+	}
+	if !confirmDestructive(plan, false, false) {
+		t.Error("non-interactive stdin should proceed without prompting")
+	}
+	if !confirmDestructive(&destructivePlan{action: "Stop rig empty"}, false, false) {
+		t.Error("empty plan should proceed")
+	}
+}
+
+func TestRigRebootFlagsAreItsOwn(t *testing.T) {
+	defer func() { rigRebootYes, rigRebootDryRun = false, false }()
+	for _, name := range []string{"yes", "dry-run"} {
+		if err := rigRebootCmd.Flags().Set(name, "true"); err != nil {
+			t.Fatalf("setting reboot --%s: %v", name, err)
+		}
+	}
+	if !rigRebootYes || !rigRebootDryRun {
+		t.Errorf("reboot flags not set: yes=%v dry-run=%v", rigRebootYes, rigRebootDryRun)
+	}
+	if rigShutdownYes || rigShutdownDryRun {
+		t.Error("reboot flags leaked into shutdown's")
+	}
+}
@@ -833,7 +833,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 	if townRoot == "" {
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	// Execute bd create from town root
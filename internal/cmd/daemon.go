@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -47,15 +49,23 @@ var daemonStopCmd = &cobra.Command{
 var daemonStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show daemon status",
-	Long:  `Show the current status of the Gas Town daemon.`,
-	RunE:  runDaemonStatus,
+	Long: `Show the current status of the Gas Town daemon.
+
+With --json, prints a machine-readable report including the heartbeat
+interval and backoff level, the last recorded activity in each workspace,
+and the supervision state of each service the daemon manages (deacon,
+witnesses, refineries): running, stopped, suspended, disabled or blocked.`,
+	RunE: runDaemonStatus,
 }
 
 var daemonLogsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "View daemon logs",
-	Long:  `View the daemon log file.`,
-	RunE:  runDaemonLogs,
+	Long: `View the daemon log file.
+
+Structured logs from the gt commands the daemon runs are written as JSON
+lines to daemon/gt.log.`,
+	RunE: runDaemonLogs,
 }
 
 var daemonRunCmd = &cobra.Command{
@@ -66,8 +76,9 @@ var daemonRunCmd = &cobra.Command{
 }
 
 var (
-	daemonLogLines int
-	daemonLogFollow bool
+	daemonLogLines   int
+	daemonLogFollow  bool
+	daemonStatusJSON bool
 )
 
 func init() {
@@ -79,6 +90,7 @@ func init() {
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonStatusCmd.Flags().BoolVar(&daemonStatusJSON, "json", false, "Output as JSON")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -170,6 +182,19 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if daemonStatusJSON {
+		report, err := daemon.CollectStatus(townRoot, tmux.NewTmux())
+		if err != nil {
+			return fmt.Errorf("collecting daemon status: %w", err)
+		}
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding daemon status: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
 	running, pid, err := daemon.IsRunning(townRoot)
 	if err != nil {
 		return fmt.Errorf("checking daemon status: %w", err)
@@ -190,6 +215,9 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 					state.LastHeartbeat.Format("15:04:05"),
 					state.HeartbeatCount)
 			}
+			if state.HeartbeatInterval != "" {
+				fmt.Printf("  Heartbeat interval: %s\n", state.HeartbeatInterval)
+			}
 
 			// Check if binary is newer than process
 			if binaryModTime, err := getBinaryModTime(); err == nil {
@@ -16,7 +16,9 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/health"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -414,6 +416,11 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 	if err := t.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		return fmt.Errorf("waiting for deacon to start: %w", err)
 	}
+
+	// Accept runtime startup dialogs (e.g., bypass permissions warning) if they appear.
+	if promptConfig, err := config.ResolveRoleAgentConfigWithOverride("deacon", townRoot, "", agentOverride); err == nil {
+		_ = runtime.AcceptStartupPrompts(t, sessionName, promptConfig)
+	}
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	runtimeConfig := config.LoadRuntimeConfig("")
@@ -464,6 +471,11 @@ func runDeaconStop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
+	// Keep an on-failure restart policy from bringing it back
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		_ = daemon.MarkStopped(townRoot, "deacon")
+	}
+
 	fmt.Printf("%s Deacon session stopped.\n", style.Bold.Render("✓"))
 	return nil
 }
@@ -612,6 +624,12 @@ func runDeaconHeartbeat(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Heartbeat updated\n", style.Bold.Render("✓"))
 	}
 
+	// The heartbeat doubles as the Deacon's health probe
+	probe := &health.Probe{Role: "deacon", Session: session.DeaconSessionName(), Phase: health.PhasePatrol, Detail: action}
+	if err := health.NewManager(townRoot).Report(probe); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: writing health probe: %v\n", err)
+	}
+
 	return nil
 }
 
@@ -32,6 +32,12 @@ Workspace checks:
   - rigs-registry-valid      Check registered rigs exist (fixable)
   - mayor-exists             Check mayor/ directory structure
 
+Environment checks:
+  - tmux-available           Check tmux 3.0+ is installed
+  - beads-available          Check bd is installed and compatible
+  - claude-available         Check claude CLI is installed
+  - runtime-dirs-writable    Check .runtime/ and daemon/ are writable (fixable)
+
 Town root protection:
   - town-git                 Verify town root is under version control
   - town-root-branch         Verify town root is on main branch (fixable)
@@ -47,6 +53,7 @@ Cleanup checks (fixable):
   - orphan-sessions          Detect orphaned tmux sessions
   - orphan-processes         Detect orphaned Claude processes
   - wisp-gc                  Detect and clean abandoned wisps (>1h)
+  - stale-keepalives         Detect workspaces with no gt activity in 24h
 
 Clone divergence checks:
   - persistent-role-branches Detect crew/witness/refinery not on main
@@ -80,6 +87,8 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
   - patrol-roles-have-prompts Verify role prompts exist
 
+Packages can contribute their own checks with doctor.RegisterCheck.
+
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.`,
 	RunE: runDoctor,
@@ -116,6 +125,12 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	d.Register(doctor.NewGlobalStateCheck())
 
+	// Register environment checks
+	d.Register(doctor.NewTmuxCheck())
+	d.Register(doctor.NewBeadsCLICheck())
+	d.Register(doctor.NewClaudeCLICheck())
+	d.Register(doctor.NewRuntimeDirsCheck())
+
 	// Register built-in checks
 	d.Register(doctor.NewStaleBinaryCheck())
 	d.Register(doctor.NewSqlite3Check())
@@ -138,6 +153,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewZombieSessionCheck())
 	d.Register(doctor.NewOrphanProcessCheck())
 	d.Register(doctor.NewWispGCCheck())
+	d.Register(doctor.NewStaleKeepaliveCheck())
 	d.Register(doctor.NewBranchCheck())
 	d.Register(doctor.NewBeadsSyncOrphanCheck())
 	d.Register(doctor.NewCloneDivergenceCheck())
@@ -182,6 +198,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewHookSingletonCheck())
 	d.Register(doctor.NewOrphanedAttachmentsCheck())
 
+	// Checks contributed by other packages
+	d.RegisterAll(doctor.RegisteredChecks()...)
+
 	// Rig-specific checks (only when --rig is specified)
 	if doctorRig != "" {
 		d.RegisterAll(doctor.RigChecks()...)
@@ -5,14 +5,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/lifecycle"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
@@ -458,6 +461,31 @@ func runDone(cmd *cobra.Command, args []string) error {
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
 
+	// Run post-done lifecycle hooks while the worktree still exists
+	if roleInfo, err := GetRoleWithContext(cwd, townRoot); err == nil && roleInfo.Role == RolePolecat {
+		// Add the bead to the polecat's persistent identity so the next
+		// polecat spawned under this name inherits the history
+		if issueID != "" {
+			if _, err := polecat.RecordCompletion(filepath.Join(townRoot, roleInfo.Rig), roleInfo.Polecat, issueID, exitType, time.Now()); err != nil {
+				style.PrintWarning("could not record completion in polecat identity: %v", err)
+			}
+		}
+		lifecycle.Warn(lifecycle.PostDone, lifecycle.Agent{
+			TownRoot: townRoot,
+			RigPath:  filepath.Join(townRoot, roleInfo.Rig),
+			Role:     string(RolePolecat),
+			Rig:      roleInfo.Rig,
+			Name:     roleInfo.Polecat,
+			Session:  session.PolecatSessionName(roleInfo.Rig, roleInfo.Polecat),
+			WorkDir:  cwd,
+			Env: map[string]string{
+				"GT_DONE_ISSUE":  issueID,
+				"GT_DONE_BRANCH": branch,
+				"GT_DONE_EXIT":   exitType,
+			},
+		})
+	}
+
 	// Self-cleaning: Nuke our own sandbox and session (if we're a polecat)
 	// This is the self-cleaning model - polecats clean up after themselves
 	// "done means gone" - both worktree and session are terminated
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -48,6 +49,10 @@ Infrastructure agents stopped:
   • Deacon     - Health orchestrator
   • Daemon     - Go background process
 
+With --polecats, polecats with work on their hook are drained first:
+nudged to commit and hand off, then given --drain-timeout (default: the
+town's drain_timeout setting, 2m). --force skips the drain.
+
 This is a "pause" operation - use 'gt start' to bring everything back up.
 For permanent cleanup (removing worktrees), use 'gt shutdown' instead.
 
@@ -64,16 +69,20 @@ var (
 	downAll      bool
 	downNuke     bool
 	downDryRun   bool
+	downYes      bool
 	downPolecats bool
+	downDrain    time.Duration
 )
 
 func init() {
 	downCmd.Flags().BoolVarP(&downQuiet, "quiet", "q", false, "Only show errors")
 	downCmd.Flags().BoolVarP(&downForce, "force", "f", false, "Force kill without graceful shutdown")
 	downCmd.Flags().BoolVarP(&downPolecats, "polecats", "p", false, "Also stop all polecat sessions")
+	downCmd.Flags().DurationVar(&downDrain, "drain-timeout", 0, "With --polecats, how long polecats with hooked work get to hand off (default: town drain_timeout, 2m)")
 	downCmd.Flags().BoolVarP(&downAll, "all", "a", false, "Stop bd daemons/activity and verify shutdown")
 	downCmd.Flags().BoolVar(&downNuke, "nuke", false, "Kill entire tmux server (DESTRUCTIVE - kills non-GT sessions!)")
 	downCmd.Flags().BoolVar(&downDryRun, "dry-run", false, "Preview what would be stopped without taking action")
+	downCmd.Flags().BoolVarP(&downYes, "yes", "y", false, "Skip confirmation prompt")
 	rootCmd.AddCommand(downCmd)
 }
 
@@ -88,6 +97,11 @@ func runDown(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("tmux not available (is tmux installed and on PATH?)")
 	}
 
+	// Show what will stop and confirm before touching anything
+	if !downDryRun && !confirmDestructive(downPlan(t, townRoot), false, downYes) {
+		return fmt.Errorf("down canceled")
+	}
+
 	// Phase 0: Acquire shutdown lock (skip for dry-run)
 	if !downDryRun {
 		lock, err := acquireShutdownLock(townRoot)
@@ -121,6 +135,9 @@ func runDown(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Println("Stopping polecats...")
 		}
+		if !downForce && !downDryRun {
+			drainAllPolecats(t, townRoot, rigs, downDrain)
+		}
 		polecatsStopped := stopAllPolecats(t, townRoot, rigs, downForce, downDryRun)
 		if downDryRun {
 			if polecatsStopped > 0 {
@@ -321,6 +338,66 @@ func runDown(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// downPlan lists the sessions, processes and state files gt down affects
+// with the current flags.
+func downPlan(t *tmux.Tmux, townRoot string) *destructivePlan {
+	plan := &destructivePlan{action: "Stop Gas Town"}
+	sessionSet, _ := t.GetSessionSet()
+	rigs := discoverRigs(townRoot)
+
+	if downPolecats {
+		rigSet := make(map[string]bool, len(rigs))
+		for _, rigName := range rigs {
+			rigSet[rigName] = true
+		}
+		names := sessionSet.Names()
+		sort.Strings(names)
+		for _, name := range names {
+			if id, err := session.ParseSessionName(name); err == nil && id.Role == session.RolePolecat && rigSet[id.Rig] {
+				plan.sessions = append(plan.sessions, name)
+			}
+		}
+	}
+	for _, rigName := range rigs {
+		for _, name := range []string{session.RefinerySessionName(rigName), session.WitnessSessionName(rigName)} {
+			if sessionSet.Has(name) {
+				plan.sessions = append(plan.sessions, name)
+			}
+		}
+	}
+	for _, ts := range session.TownSessions() {
+		if sessionSet.Has(ts.SessionID) {
+			plan.sessions = append(plan.sessions, ts.SessionID)
+		}
+	}
+
+	if running, pid, err := daemon.IsRunning(townRoot); err == nil && running {
+		plan.processes = append(plan.processes, fmt.Sprintf("gt daemon (PID %d)", pid))
+		plan.stateFiles = append(plan.stateFiles, filepath.Join(townRoot, "daemon", "daemon.pid"))
+	}
+	if downAll {
+		plan.processes = append(plan.processes, "bd daemon and bd activity processes")
+	}
+	if downNuke {
+		plan.processes = append(plan.processes, "tmux server (ALL sessions, including non-Gas Town)")
+	}
+	return plan
+}
+
+// drainAllPolecats drains the polecats with hooked work across rigNames in
+// one window before their sessions are stopped.
+func drainAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, flag time.Duration) {
+	var targets []polecatTarget
+	for _, rigName := range rigNames {
+		if rigTargets, err := resolvePolecatTargets([]string{rigName}, true); err == nil {
+			targets = append(targets, rigTargets...)
+		}
+	}
+	if holding := drainPolecats(t, targets, drainWindow(townRoot, flag)); len(holding) > 0 {
+		fmt.Printf("  %s Stopping anyway; hooked work stays on the hook for re-sling\n", style.Warning.Render("⚠"))
+	}
+}
+
 // stopAllPolecats stops all polecat sessions across all rigs.
 // Returns the number of polecats stopped (or would be stopped in dry-run).
 func stopAllPolecats(t *tmux.Tmux, townRoot string, rigNames []string, force bool, dryRun bool) int {
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// drainPollInterval is how often a drain rechecks hooks. Variable for tests.
+var drainPollInterval = 5 * time.Second
+
+// drainWindow returns the drain window: the flag value if set, otherwise the
+// town's drain_timeout setting.
+func drainWindow(townRoot string, flag time.Duration) time.Duration {
+	if flag > 0 {
+		return flag
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return config.DefaultDrainTimeout
+	}
+	return settings.GetDrainTimeout()
+}
+
+// hookedWork returns the open bead on a polecat's hook, or "" if its hook is
+// empty or the bead is closed.
+func hookedWork(p polecatTarget) string {
+	bd := beads.New(p.r.Path)
+	issue, fields, err := bd.GetAgentBead(polecatBeadIDForRig(p.r, p.rigName, p.polecatName))
+	if err != nil || issue == nil {
+		return ""
+	}
+	hookBead := issue.HookBead
+	if hookBead == "" && fields != nil {
+		hookBead = fields.HookBead
+	}
+	if hookBead == "" {
+		return ""
+	}
+	if hooked, err := bd.Show(hookBead); err == nil && hooked != nil && hooked.Status == "closed" {
+		return ""
+	}
+	return hookBead
+}
+
+// drainPolecats gives running polecats with hooked work a chance to commit
+// and hand off before they are killed. Each is nudged, then the drain waits
+// up to window for the hook to clear or the session to exit. Returns the
+// polecats ("rig/name") still holding work when the drain ends, including
+// those whose session exited without releasing it.
+func drainPolecats(t *tmux.Tmux, targets []polecatTarget, window time.Duration) []string {
+	actor := detectSender()
+	hooked := make(map[string]string) // rig/name -> bead
+	sessions := make(map[string]string)
+	byName := make(map[string]polecatTarget)
+	var pending []string
+
+	for _, p := range targets {
+		sessionName := session.PolecatSessionName(p.rigName, p.polecatName)
+		if running, _ := t.HasSession(sessionName); !running {
+			continue
+		}
+		bead := hookedWork(p)
+		if bead == "" {
+			continue
+		}
+		name := p.rigName + "/" + p.polecatName
+		hooked[name] = bead
+		sessions[name] = sessionName
+		byName[name] = p
+		pending = append(pending, name)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Draining %d polecat(s) with hooked work (up to %s)...\n", len(pending), window)
+	for _, name := range pending {
+		msg := fmt.Sprintf("DRAIN: this session will be stopped in %s. Commit and push your work on %s now, then run 'gt done' or 'gt handoff' to hand it off.",
+			window, hooked[name])
+		if err := t.NudgeSession(sessions[name], msg); err != nil {
+			fmt.Printf("  %s %s: nudge failed: %v\n", style.Warning.Render("⚠"), name, err)
+		}
+		_ = events.LogFeed(events.TypeDrain, actor, events.DrainPayload(name, hooked[name], events.DrainRequested))
+	}
+
+	released := func(name string) bool {
+		if running, _ := t.HasSession(sessions[name]); !running {
+			return true
+		}
+		return hookedWork(byName[name]) == ""
+	}
+	waitForDrain(pending, released, window)
+
+	var holding []string
+	for _, name := range pending {
+		running, _ := t.HasSession(sessions[name])
+		outcome := drainOutcome(running, hookedWork(byName[name]) != "")
+		switch outcome {
+		case events.DrainHandedOff:
+			fmt.Printf("  %s %s handed off %s\n", style.Success.Render("✓"), name, hooked[name])
+		case events.DrainExited:
+			fmt.Printf("  %s %s exited still holding %s\n", style.Warning.Render("⚠"), name, hooked[name])
+		default:
+			fmt.Printf("  %s %s still holds %s\n", style.Warning.Render("⚠"), name, hooked[name])
+		}
+		if outcome != events.DrainHandedOff {
+			holding = append(holding, name)
+		}
+		_ = events.LogFeed(events.TypeDrain, actor, events.DrainPayload(name, hooked[name], outcome))
+	}
+	return holding
+}
+
+// drainOutcome classifies a drained polecat once the drain ends: handed off
+// only if its hook is clear, exited if its session ended still holding
+// work, and timed out if it is still running with work hooked.
+func drainOutcome(running, stillHooked bool) string {
+	switch {
+	case !stillHooked:
+		return events.DrainHandedOff
+	case !running:
+		return events.DrainExited
+	default:
+		return events.DrainTimedOut
+	}
+}
+
+// drainRig drains a rig's polecats with hooked work before its sessions are
+// stopped. The sessions are stopped either way; work still hooked stays on
+// the hook for re-sling.
+func drainRig(t *tmux.Tmux, townRoot, rigName string, flag time.Duration) {
+	targets, err := resolvePolecatTargets([]string{rigName}, true)
+	if err != nil {
+		return
+	}
+	if holding := drainPolecats(t, targets, drainWindow(townRoot, flag)); len(holding) > 0 {
+		fmt.Printf("  %s Stopping anyway; hooked work stays on the hook for re-sling\n", style.Warning.Render("⚠"))
+	}
+}
+
+// waitForDrain polls released for each pending name until all are released
+// or window elapses. Returns the names not released, in input order.
+func waitForDrain(pending []string, released func(string) bool, window time.Duration) []string {
+	deadline := time.Now().Add(window)
+	for {
+		var remaining []string
+		for _, name := range pending {
+			if !released(name) {
+				remaining = append(remaining, name)
+			}
+		}
+		pending = remaining
+		if len(pending) == 0 || !time.Now().Before(deadline) {
+			return pending
+		}
+		wait := drainPollInterval
+		if left := time.Until(deadline); left < wait {
+			wait = left
+		}
+		time.Sleep(wait)
+	}
+}
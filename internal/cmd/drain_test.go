@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestWaitForDrain(t *testing.T) {
+	orig := drainPollInterval
+	defer func() { drainPollInterval = orig }()
+	drainPollInterval = time.Millisecond
+
+	checks := make(map[string]int)
+	released := func(name string) bool {
+		checks[name]++
+		return name == "gastown/Toast" && checks[name] >= 3
+	}
+
+	remaining := waitForDrain([]string{"gastown/Toast", "gastown/Nux"}, released, 50*time.Millisecond)
+	if want := []string{"gastown/Nux"}; !reflect.DeepEqual(remaining, want) {
+		t.Errorf("remaining = %v, want %v", remaining, want)
+	}
+	if checks["gastown/Toast"] != 3 {
+		t.Errorf("Toast checked %d times after release, want 3", checks["gastown/Toast"])
+	}
+}
+
+func TestWaitForDrain_AllReleased(t *testing.T) {
+	start := time.Now()
+	remaining := waitForDrain([]string{"gastown/Toast"}, func(string) bool { return true }, time.Hour)
+	if len(remaining) != 0 {
+		t.Errorf("remaining = %v, want none", remaining)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("waitForDrain waited after every polecat was released")
+	}
+}
+
+func TestDrainOutcome(t *testing.T) {
+	tests := []struct {
+		running, hooked bool
+		want            string
+	}{
+		{running: true, hooked: false, want: events.DrainHandedOff},
+		{running: false, hooked: false, want: events.DrainHandedOff},
+		{running: false, hooked: true, want: events.DrainExited},
+		{running: true, hooked: true, want: events.DrainTimedOut},
+	}
+	for _, tt := range tests {
+		if got := drainOutcome(tt.running, tt.hooked); got != tt.want {
+			t.Errorf("drainOutcome(running=%v, hooked=%v) = %q, want %q", tt.running, tt.hooked, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,20 @@ package cmd
 import (
 	"errors"
 	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // SilentExitError signals that the command should exit with a specific code
@@ -34,3 +48,99 @@ func IsSilentExit(err error) (int, bool) {
 	}
 	return 0, false
 }
+
+// ErrInvalidUsage indicates a command was invoked with invalid arguments or flags.
+// Cobra argument and flag errors are wrapped with it so they map to
+// constants.ExitValidation.
+var ErrInvalidUsage = errors.New("invalid usage")
+
+// usageError wraps a cobra argument or flag error without changing its message.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+
+func (e *usageError) Unwrap() error { return e.err }
+
+func (e *usageError) Is(target error) bool { return target == ErrInvalidUsage }
+
+// exitClasses maps sentinel errors from internal packages to exit codes.
+// Checked in order with errors.Is; the first match wins.
+var exitClasses = []struct {
+	code int
+	errs []error
+}{
+	{constants.ExitWorkspaceNotFound, []error{
+		workspace.ErrNotFound,
+	}},
+	{constants.ExitAlreadyRunning, []error{
+		tmux.ErrSessionExists,
+		mayor.ErrAlreadyRunning,
+		deacon.ErrAlreadyRunning,
+		witness.ErrAlreadyRunning,
+		refinery.ErrAlreadyRunning,
+		polecat.ErrSessionRunning,
+		crew.ErrSessionRunning,
+	}},
+	{constants.ExitNotRunning, []error{
+		tmux.ErrNoServer,
+		tmux.ErrSessionNotFound,
+		mayor.ErrNotRunning,
+		deacon.ErrNotRunning,
+		witness.ErrNotRunning,
+		refinery.ErrNotRunning,
+		polecat.ErrSessionNotFound,
+		crew.ErrSessionNotFound,
+	}},
+	{constants.ExitTransport, []error{
+		mail.ErrTransport,
+		beads.ErrNotInstalled,
+	}},
+	{constants.ExitValidation, []error{
+		ErrInvalidUsage,
+		config.ErrInvalidVersion,
+		config.ErrInvalidType,
+		config.ErrMissingField,
+		crew.ErrInvalidCrewName,
+	}},
+}
+
+// ExitCodeFor returns the process exit code for an error returned by a command.
+// Silent exits keep their explicit code; otherwise the error is classified
+// against the known sentinel errors, falling back to constants.ExitError.
+func ExitCodeFor(err error) int {
+	if err == nil {
+		return constants.ExitSuccess
+	}
+	if code, ok := IsSilentExit(err); ok {
+		return code
+	}
+	for _, class := range exitClasses {
+		for _, target := range class.errs {
+			if errors.Is(err, target) {
+				return class.code
+			}
+		}
+	}
+	return constants.ExitError
+}
+
+// markUsageErrors wraps argument validators on cmd and its subcommands so
+// that cobra's argument errors are classified as ErrInvalidUsage.
+// Flag errors are handled by the root command's flag error func, which
+// subcommands inherit.
+func markUsageErrors(cmd *cobra.Command) {
+	if cmd.Args != nil {
+		validate := cmd.Args
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			if err := validate(c, args); err != nil {
+				return &usageError{err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
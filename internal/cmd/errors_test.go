@@ -4,6 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 func TestSilentExitError_Error(t *testing.T) {
@@ -90,3 +99,45 @@ func TestSilentExitError_Is(t *testing.T) {
 		t.Errorf("errors.As extracted code = %d, want 1", target.Code)
 	}
 }
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil error", nil, constants.ExitSuccess},
+		{"generic error", errors.New("boom"), constants.ExitError},
+		{"silent exit keeps code", NewSilentExit(2), 2},
+		{"workspace not found", fmt.Errorf("not in a Gas Town workspace: %w", workspace.ErrNotFound), constants.ExitWorkspaceNotFound},
+		{"mayor already running", mayor.ErrAlreadyRunning, constants.ExitAlreadyRunning},
+		{"tmux session exists", fmt.Errorf("starting: %w", tmux.ErrSessionExists), constants.ExitAlreadyRunning},
+		{"witness not running", witness.ErrNotRunning, constants.ExitNotRunning},
+		{"bd not installed", beads.ErrNotInstalled, constants.ExitTransport},
+		{"mail transport", fmt.Errorf("sending: %w", mail.ErrTransport), constants.ExitTransport},
+		{"invalid usage", &usageError{err: errors.New("accepts 1 arg(s), received 0")}, constants.ExitValidation},
+		{"config missing field", fmt.Errorf("%w: name", config.ErrMissingField), constants.ExitValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCodeFor(tt.err); got != tt.want {
+				t.Errorf("ExitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageErrorPreservesMessage(t *testing.T) {
+	inner := errors.New("unknown flag: --bogus")
+	err := &usageError{err: inner}
+	if err.Error() != inner.Error() {
+		t.Errorf("usageError.Error() = %q, want %q", err.Error(), inner.Error())
+	}
+	if !errors.Is(err, ErrInvalidUsage) {
+		t.Error("usageError should match ErrInvalidUsage")
+	}
+	if !errors.Is(err, inner) {
+		t.Error("usageError should unwrap to the original error")
+	}
+}
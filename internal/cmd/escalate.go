@@ -10,6 +10,7 @@ var (
 	escalateReason      string
 	escalateSource      string
 	escalateRelatedBead string
+	escalateThread      string
 	escalateJSON        bool
 	escalateListJSON    bool
 	escalateListAll     bool
@@ -48,10 +49,15 @@ CONFIGURATION:
   - stale_threshold: When unacked escalations are re-escalated (default: 4h)
   - max_reescalations: How many times to bump severity (default: 2)
 
+PRIORITY INHERITANCE:
+  With --related or --thread, unread mail about the same bead or thread is
+  raised to the escalation's priority and its recipients are re-notified.
+
 Examples:
   gt escalate "Build failing" --severity critical --reason "CI blocked"
   gt escalate "Need API credentials" --severity high --source "plugin:rebuild-gt"
   gt escalate "Code review requested" --reason "PR #123 ready"
+  gt escalate "Merge blocked" --severity high --related gt-abc --thread thread-1234
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
   gt escalate close hq-abc123 --reason "Fixed in commit abc"
@@ -141,6 +147,7 @@ func init() {
 	escalateCmd.Flags().StringVarP(&escalateReason, "reason", "r", "", "Detailed reason for escalation")
 	escalateCmd.Flags().StringVar(&escalateSource, "source", "", "Source identifier (e.g., plugin:rebuild-gt, patrol:deacon)")
 	escalateCmd.Flags().StringVar(&escalateRelatedBead, "related", "", "Related bead ID (task, bug, etc.)")
+	escalateCmd.Flags().StringVar(&escalateThread, "thread", "", "Related mail thread ID")
 	escalateCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 	escalateCmd.Flags().BoolVarP(&escalateDryRun, "dry-run", "n", false, "Show what would be done without executing")
 
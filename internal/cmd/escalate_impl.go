@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -90,23 +91,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	router := mail.NewRouter(townRoot)
 	for _, target := range targets {
 		msg := &mail.Message{
-			From:    agentID,
-			To:      target,
-			Subject: fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
-			Body:    formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
-			Type:    mail.TypeTask,
-		}
-
-		// Set priority based on severity
-		switch severity {
-		case config.SeverityCritical:
-			msg.Priority = mail.PriorityUrgent
-		case config.SeverityHigh:
-			msg.Priority = mail.PriorityHigh
-		case config.SeverityMedium:
-			msg.Priority = mail.PriorityNormal
-		default:
-			msg.Priority = mail.PriorityLow
+			From:     agentID,
+			To:       target,
+			Subject:  fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
+			Body:     formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+			Type:     mail.TypeTask,
+			Priority: escalation.Priority(severity),
+			ThreadID: escalateThread,
 		}
 
 		if err := router.Send(msg); err != nil {
@@ -114,17 +105,38 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Raise queued mail about the same bead or thread to this urgency
+	bumped, err := router.EscalateRelated(mail.EscalationRef{
+		EscalationID: issue.ID,
+		ThreadID:     escalateThread,
+		BeadID:       escalateRelatedBead,
+	}, escalation.Priority(severity))
+	if err != nil {
+		style.PrintWarning("could not raise priority of related mail: %v", err)
+	}
+
 	// Process external notification actions (email:, sms:, slack)
 	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
 
-	// Log to activity feed
-	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
-	payload["severity"] = severity
-	payload["actions"] = strings.Join(actions, ",")
-	if escalateSource != "" {
-		payload["source"] = escalateSource
+	// Record the escalation (starting its SLA timer) and log it to the feed
+	payload := map[string]interface{}{"actions": strings.Join(actions, ",")}
+	if len(bumped) > 0 {
+		payload["bumped"] = len(bumped)
+	}
+	if _, err := escalation.Open(townRoot, escalation.Options{
+		ID:       issue.ID,
+		Bead:     true,
+		Severity: severity,
+		Summary:  description,
+		Reason:   escalateReason,
+		Source:   escalateSource,
+		Related:  escalateRelatedBead,
+		OpenedBy: agentID,
+		To:       targets,
+		Payload:  payload,
+	}); err != nil {
+		style.PrintWarning("could not record escalation: %v", err)
 	}
-	_ = events.LogFeed(events.TypeEscalationSent, agentID, payload)
 
 	// Output
 	if escalateJSON {
@@ -137,6 +149,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if len(bumped) > 0 {
+			ids := make([]string, 0, len(bumped))
+			for _, m := range bumped {
+				ids = append(ids, m.ID)
+			}
+			result["bumped"] = ids
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
@@ -147,6 +166,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  Source: %s\n", escalateSource)
 		}
 		fmt.Printf("  Routed to: %s\n", strings.Join(targets, ", "))
+		if len(bumped) > 0 {
+			fmt.Printf("  Raised %d related message(s) to %s priority\n", len(bumped), escalation.Priority(severity))
+		}
 	}
 
 	return nil
@@ -224,17 +246,10 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 		ackedBy = "unknown"
 	}
 
-	bd := beads.New(beads.ResolveBeadsDir(townRoot))
-	if err := bd.AckEscalation(escalationID, ackedBy); err != nil {
-		return fmt.Errorf("acknowledging escalation: %w", err)
+	if err := ackEscalation(townRoot, escalationID, true, ackedBy); err != nil {
+		return err
 	}
 
-	// Log to activity feed
-	_ = events.LogFeed(events.TypeEscalationAcked, ackedBy, map[string]interface{}{
-		"escalation_id": escalationID,
-		"acked_by":      ackedBy,
-	})
-
 	fmt.Printf("%s Escalation acknowledged: %s\n", style.Bold.Render("✓"), escalationID)
 	return nil
 }
@@ -253,18 +268,10 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 		closedBy = "unknown"
 	}
 
-	bd := beads.New(beads.ResolveBeadsDir(townRoot))
-	if err := bd.CloseEscalation(escalationID, closedBy, escalateCloseReason); err != nil {
-		return fmt.Errorf("closing escalation: %w", err)
+	if err := closeEscalation(townRoot, escalationID, true, closedBy, escalateCloseReason); err != nil {
+		return err
 	}
 
-	// Log to activity feed
-	_ = events.LogFeed(events.TypeEscalationClosed, closedBy, map[string]interface{}{
-		"escalation_id": escalationID,
-		"closed_by":     closedBy,
-		"reason":        escalateCloseReason,
-	})
-
 	fmt.Printf("%s Escalation closed: %s\n", style.Bold.Render("✓"), escalationID)
 	fmt.Printf("  Reason: %s\n", escalateCloseReason)
 	return nil
@@ -0,0 +1,520 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	escalationJSON        bool
+	escalationAll         bool
+	escalationCloseReason string
+)
+
+var escalationCmd = &cobra.Command{
+	Use:     "escalation",
+	Aliases: []string{"escalations"},
+	GroupID: GroupComm,
+	Short:   "Escalation dashboard: list, inspect, ack and close escalations",
+	Long: `Manage the escalation workflow from one place.
+
+The dashboard is built from the event log, so it covers both escalations
+created with 'gt escalate' (backed by escalation beads) and those raised by
+witnesses with 'gt activity emit escalation_sent' (identified by event ID).
+
+Each escalation shows its age, target, and SLA status against the
+stale_threshold in settings/escalation.json:
+  ok        open and within the threshold
+  breached  open and unacknowledged past the threshold
+  met       acknowledged or closed
+
+Escalations created with 'gt escalate' are also recorded in
+daemon/escalations.json. While one stays unacknowledged past the threshold,
+the daemon mails its targets a reminder, up to max_reescalations times.
+
+Examples:
+  gt escalation                        # Open escalations
+  gt escalation list --all             # Include closed
+  gt escalation show hq-abc123         # Details and history
+  gt escalation ack 412                # Ack a witness escalation
+  gt escalation close hq-abc123 --reason "Fixed"`,
+	RunE: runEscalationList,
+}
+
+var escalationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List escalations with age and SLA status",
+	RunE:  runEscalationList,
+}
+
+var escalationShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show an escalation and its history",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalationShow,
+}
+
+var escalationAckCmd = &cobra.Command{
+	Use:   "ack <id>",
+	Short: "Acknowledge an escalation",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalationAck,
+}
+
+var escalationCloseCmd = &cobra.Command{
+	Use:   "close <id>",
+	Short: "Close a resolved escalation",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalationClose,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{escalationCmd, escalationListCmd} {
+		c.Flags().BoolVar(&escalationJSON, "json", false, "Output as JSON")
+		c.Flags().BoolVar(&escalationAll, "all", false, "Include closed escalations")
+	}
+	escalationShowCmd.Flags().BoolVar(&escalationJSON, "json", false, "Output as JSON")
+	escalationCloseCmd.Flags().StringVar(&escalationCloseReason, "reason", "", "Resolution reason")
+	_ = escalationCloseCmd.MarkFlagRequired("reason")
+
+	escalationCmd.AddCommand(escalationListCmd)
+	escalationCmd.AddCommand(escalationShowCmd)
+	escalationCmd.AddCommand(escalationAckCmd)
+	escalationCmd.AddCommand(escalationCloseCmd)
+	rootCmd.AddCommand(escalationCmd)
+}
+
+// Escalation statuses and SLA states shown by the dashboard.
+const (
+	escalationOpen   = "open"
+	escalationAcked  = "acked"
+	escalationClosed = "closed"
+
+	slaOK       = "ok"
+	slaBreached = "breached"
+	slaMet      = "met"
+)
+
+// escalationView is an escalation as reconstructed from the event log.
+type escalationView struct {
+	ID       string              `json:"id"`
+	Bead     bool                `json:"bead"` // backed by an escalation bead
+	Severity string              `json:"severity,omitempty"`
+	Rig      string              `json:"rig,omitempty"`
+	Target   string              `json:"target,omitempty"` // what the escalation is about
+	To       string              `json:"to,omitempty"`     // who it was routed to
+	Reason   string              `json:"reason,omitempty"`
+	OpenedBy string              `json:"opened_by"`
+	OpenedAt time.Time           `json:"opened_at"`
+	Status   string              `json:"status"`
+	AckedBy  string              `json:"acked_by,omitempty"`
+	AckedAt  *time.Time          `json:"acked_at,omitempty"`
+	ClosedBy string              `json:"closed_by,omitempty"`
+	ClosedAt *time.Time          `json:"closed_at,omitempty"`
+	Deadline *time.Time          `json:"deadline,omitempty"` // next reminder, from the escalation record
+	Reminded int                 `json:"reminded,omitempty"` // reminders sent for missing the SLA
+	SLA      string              `json:"sla"`
+	Age      string              `json:"age"`
+	History  []escalationHistory `json:"history"`
+}
+
+// escalationHistory is one event in an escalation's life.
+type escalationHistory struct {
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Actor  string    `json:"actor"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// buildEscalations folds escalation events into one view per escalation,
+// oldest first. Escalations from 'gt escalate' are keyed by their bead ID;
+// witness escalations have no bead and are keyed by their event ID.
+func buildEscalations(records []events.Record) []*escalationView {
+	byID := make(map[string]*escalationView)
+	var order []string
+
+	for _, rec := range records {
+		ts, err := time.Parse(time.RFC3339, rec.Timestamp)
+		if err != nil {
+			continue
+		}
+		p := rec.Payload
+		id := getPayloadString(p, "escalation_id")
+
+		switch rec.Type {
+		case events.TypeEscalationSent:
+			if id == "" && getPayloadString(p, "severity") != "" {
+				// Older 'gt escalate' events carried the bead ID in "rig"
+				id = getPayloadString(p, "rig")
+			}
+			if e := byID[id]; id != "" && e != nil && p["renotified"] != nil {
+				// SLA reminder from the daemon
+				e.Reminded++
+				e.History = append(e.History, escalationHistory{Time: ts, Type: "reminded", Actor: rec.Actor,
+					Detail: getPayloadString(p, "to")})
+				continue
+			}
+			if e := byID[id]; id != "" && e != nil {
+				// Re-escalation of a known escalation
+				if sev := getPayloadString(p, "new_severity"); sev != "" {
+					e.Severity = sev
+				}
+				if to := getPayloadString(p, "targets"); to != "" {
+					e.To = to
+				}
+				e.History = append(e.History, escalationHistory{Time: ts, Type: "re-escalated", Actor: rec.Actor,
+					Detail: strings.TrimSpace(getPayloadString(p, "old_severity") + " → " + e.Severity)})
+				continue
+			}
+			e := &escalationView{
+				ID:       id,
+				Bead:     id != "",
+				Severity: getPayloadString(p, "severity"),
+				Target:   getPayloadString(p, "target"),
+				To:       getPayloadString(p, "to"),
+				Reason:   getPayloadString(p, "reason"),
+				OpenedBy: rec.Actor,
+				OpenedAt: ts,
+				Status:   escalationOpen,
+			}
+			if e.Bead {
+				// 'gt escalate' puts the sender in "target" and the description in "reason"
+				e.Target = ""
+			} else {
+				e.ID = rec.ID()
+				e.Rig = getPayloadString(p, "rig")
+			}
+			e.History = append(e.History, escalationHistory{Time: ts, Type: "sent", Actor: rec.Actor, Detail: e.Reason})
+			byID[e.ID] = e
+			order = append(order, e.ID)
+
+		case events.TypeEscalationAcked:
+			e := byID[id]
+			if e == nil {
+				continue
+			}
+			if e.Status == escalationOpen {
+				e.Status = escalationAcked
+			}
+			e.AckedBy = rec.Actor
+			e.AckedAt = &ts
+			e.History = append(e.History, escalationHistory{Time: ts, Type: "acked", Actor: rec.Actor})
+
+		case events.TypeEscalationClosed:
+			e := byID[id]
+			if e == nil {
+				continue
+			}
+			e.Status = escalationClosed
+			e.ClosedBy = rec.Actor
+			e.ClosedAt = &ts
+			e.History = append(e.History, escalationHistory{Time: ts, Type: "closed", Actor: rec.Actor,
+				Detail: getPayloadString(p, "reason")})
+		}
+	}
+
+	views := make([]*escalationView, 0, len(order))
+	for _, id := range order {
+		views = append(views, byID[id])
+	}
+	sort.SliceStable(views, func(i, j int) bool { return views[i].OpenedAt.Before(views[j].OpenedAt) })
+	return views
+}
+
+// escalationSLA returns an escalation's SLA state: met once acknowledged or
+// closed, breached if still unacknowledged past threshold.
+func escalationSLA(e *escalationView, threshold time.Duration, now time.Time) string {
+	if e.Status != escalationOpen {
+		return slaMet
+	}
+	if threshold > 0 && now.Sub(e.OpenedAt) > threshold {
+		return slaBreached
+	}
+	return slaOK
+}
+
+// loadEscalations reads the event log and returns every escalation with its
+// SLA state and age filled in.
+func loadEscalations(townRoot string) ([]*escalationView, error) {
+	records, err := events.ReadAll(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	threshold := 4 * time.Hour
+	if cfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot)); err == nil {
+		threshold = cfg.GetStaleThreshold()
+	}
+
+	stored, err := escalation.List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	deadlines := make(map[string]time.Time, len(stored))
+	for _, r := range stored {
+		if !r.Deadline.IsZero() {
+			deadlines[r.ID] = r.Deadline
+		}
+	}
+
+	now := time.Now()
+	views := buildEscalations(records)
+	for _, e := range views {
+		e.SLA = escalationSLA(e, threshold, now)
+		e.Age = formatDuration(now.Sub(e.OpenedAt))
+		if d, ok := deadlines[e.ID]; ok && e.Status == escalationOpen {
+			e.Deadline = &d
+		}
+	}
+	return views, nil
+}
+
+// findEscalation returns the escalation with id, or an error naming it.
+func findEscalation(townRoot, id string) (*escalationView, error) {
+	views, err := loadEscalations(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range views {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("escalation not found: %s", id)
+}
+
+func runEscalationList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	views, err := loadEscalations(townRoot)
+	if err != nil {
+		return err
+	}
+
+	shown := make([]*escalationView, 0, len(views))
+	for _, e := range views {
+		if escalationAll || e.Status != escalationClosed {
+			shown = append(shown, e)
+		}
+	}
+
+	if escalationJSON {
+		out, _ := json.MarshalIndent(shown, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(shown) == 0 {
+		fmt.Println("No escalations found")
+		return nil
+	}
+
+	breached := 0
+	for _, e := range shown {
+		if e.SLA == slaBreached {
+			breached++
+		}
+	}
+	fmt.Printf("%s (%d", style.Bold.Render("Escalations"), len(shown))
+	if breached > 0 {
+		fmt.Printf(", %s", style.Error.Render(fmt.Sprintf("%d past SLA", breached)))
+	}
+	fmt.Printf("):\n\n")
+
+	for _, e := range shown {
+		fmt.Printf("  %s %s [%s] %s\n", severityEmoji(e.Severity), e.ID, e.Status, escalationTitle(e))
+		line := fmt.Sprintf("     Age: %s | SLA: %s | From: %s", e.Age, formatSLA(e.SLA), e.OpenedBy)
+		if e.To != "" {
+			line += " | To: " + e.To
+		}
+		fmt.Println(line)
+		if e.AckedBy != "" {
+			fmt.Printf("     Acked by: %s\n", e.AckedBy)
+		}
+		if e.Reminded > 0 {
+			fmt.Printf("     Reminded: %d time(s)\n", e.Reminded)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runEscalationShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e, err := findEscalation(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	if escalationJSON {
+		out, _ := json.MarshalIndent(e, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Printf("%s Escalation: %s\n", severityEmoji(e.Severity), e.ID)
+	fmt.Printf("  Summary: %s\n", escalationTitle(e))
+	fmt.Printf("  Status: %s\n", e.Status)
+	if e.Severity != "" {
+		fmt.Printf("  Severity: %s\n", e.Severity)
+	}
+	if e.Rig != "" {
+		fmt.Printf("  Rig: %s\n", e.Rig)
+	}
+	if e.Target != "" {
+		fmt.Printf("  Target: %s\n", e.Target)
+	}
+	if e.To != "" {
+		fmt.Printf("  Routed to: %s\n", e.To)
+	}
+	fmt.Printf("  Opened: %s ago by %s\n", e.Age, e.OpenedBy)
+	fmt.Printf("  SLA: %s\n", formatSLA(e.SLA))
+	if e.Deadline != nil {
+		fmt.Printf("  Next reminder: %s\n", e.Deadline.Local().Format("2006-01-02 15:04"))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("History:"))
+	for _, h := range e.History {
+		line := fmt.Sprintf("  %s  %-12s %s", h.Time.Local().Format("2006-01-02 15:04"), h.Type, h.Actor)
+		if h.Detail != "" {
+			line += style.Dim.Render(" — " + h.Detail)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runEscalationAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e, err := findEscalation(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if e.Status != escalationOpen {
+		return fmt.Errorf("escalation %s is already %s", e.ID, e.Status)
+	}
+
+	ackedBy := detectSender()
+	if ackedBy == "" {
+		ackedBy = "unknown"
+	}
+	if err := ackEscalation(townRoot, e.ID, e.Bead, ackedBy); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Escalation acknowledged: %s\n", style.Bold.Render("✓"), e.ID)
+	return nil
+}
+
+func runEscalationClose(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e, err := findEscalation(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if e.Status == escalationClosed {
+		return fmt.Errorf("escalation %s is already closed", e.ID)
+	}
+
+	closedBy := detectSender()
+	if closedBy == "" {
+		closedBy = "unknown"
+	}
+	if err := closeEscalation(townRoot, e.ID, e.Bead, closedBy, escalationCloseReason); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Escalation closed: %s\n", style.Bold.Render("✓"), e.ID)
+	fmt.Printf("  Reason: %s\n", escalationCloseReason)
+	return nil
+}
+
+// ackEscalation acknowledges an escalation through its record. Escalations
+// without one (witness escalations, and those from before records were
+// kept) are acknowledged on their bead, if any, and in the event log.
+func ackEscalation(townRoot, id string, bead bool, ackedBy string) error {
+	_, err := escalation.Ack(townRoot, id, ackedBy)
+	if !errors.Is(err, escalation.ErrNotFound) {
+		return err
+	}
+	if bead {
+		bd := beads.New(beads.ResolveBeadsDir(townRoot))
+		if err := bd.AckEscalation(id, ackedBy); err != nil {
+			return fmt.Errorf("acknowledging escalation: %w", err)
+		}
+	}
+	_ = events.LogFeed(events.TypeEscalationAcked, ackedBy, map[string]interface{}{
+		"escalation_id": id,
+		"acked_by":      ackedBy,
+	})
+	return nil
+}
+
+// closeEscalation closes an escalation through its record, like
+// ackEscalation.
+func closeEscalation(townRoot, id string, bead bool, closedBy, reason string) error {
+	_, err := escalation.Close(townRoot, id, closedBy, reason)
+	if !errors.Is(err, escalation.ErrNotFound) {
+		return err
+	}
+	if bead {
+		bd := beads.New(beads.ResolveBeadsDir(townRoot))
+		if err := bd.CloseEscalation(id, closedBy, reason); err != nil {
+			return fmt.Errorf("closing escalation: %w", err)
+		}
+	}
+	_ = events.LogFeed(events.TypeEscalationClosed, closedBy, map[string]interface{}{
+		"escalation_id": id,
+		"closed_by":     closedBy,
+		"reason":        reason,
+	})
+	return nil
+}
+
+// escalationTitle is a one-line description of what was escalated.
+func escalationTitle(e *escalationView) string {
+	if e.Bead {
+		return e.Reason
+	}
+	title := e.Target
+	if e.Rig != "" {
+		title = e.Rig + "/" + title
+	}
+	if e.Reason != "" {
+		title += ": " + e.Reason
+	}
+	return title
+}
+
+func formatSLA(sla string) string {
+	switch sla {
+	case slaBreached:
+		return style.Error.Render(sla)
+	case slaMet:
+		return style.Success.Render(sla)
+	default:
+		return sla
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func escalationRecord(seq int, ts time.Time, typ, actor string, payload map[string]interface{}) events.Record {
+	return events.Record{Seq: seq, Event: events.Event{
+		Timestamp: ts.Format(time.RFC3339),
+		Type:      typ,
+		Actor:     actor,
+		Payload:   payload,
+	}}
+}
+
+func TestBuildEscalations(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	records := []events.Record{
+		escalationRecord(1, t0, events.TypeEscalationSent, "gastown/crew/max", map[string]interface{}{
+			"escalation_id": "hq-esc1", "rig": "hq-esc1", "target": "gastown/crew/max",
+			"to": "mayor", "reason": "CI blocked", "severity": "high",
+		}),
+		escalationRecord(2, t0.Add(time.Minute), events.TypeEscalationSent, "greenplace/witness", map[string]interface{}{
+			"rig": "greenplace", "target": "Toast", "to": "mayor", "reason": "unresponsive",
+		}),
+		escalationRecord(3, t0.Add(2*time.Minute), events.TypeEscalationAcked, "mayor", map[string]interface{}{
+			"escalation_id": "hq-esc1",
+		}),
+		escalationRecord(4, t0.Add(3*time.Minute), events.TypeEscalationSent, "deacon", map[string]interface{}{
+			"escalation_id": "hq-esc1", "reescalated": true, "old_severity": "high", "new_severity": "critical",
+		}),
+		escalationRecord(5, t0.Add(4*time.Minute), events.TypeEscalationClosed, "mayor", map[string]interface{}{
+			"escalation_id": "2", "reason": "restarted",
+		}),
+	}
+
+	views := buildEscalations(records)
+	if len(views) != 2 {
+		t.Fatalf("got %d escalations, want 2", len(views))
+	}
+
+	bead := views[0]
+	if bead.ID != "hq-esc1" || !bead.Bead {
+		t.Errorf("bead escalation = %+v", bead)
+	}
+	if bead.Status != escalationAcked || bead.AckedBy != "mayor" {
+		t.Errorf("bead status = %s acked by %q, want acked by mayor", bead.Status, bead.AckedBy)
+	}
+	if bead.Severity != "critical" {
+		t.Errorf("severity after re-escalation = %q, want critical", bead.Severity)
+	}
+	if len(bead.History) != 3 {
+		t.Errorf("history = %+v, want sent, acked, re-escalated", bead.History)
+	}
+
+	witness := views[1]
+	if witness.ID != "2" || witness.Bead {
+		t.Errorf("witness escalation = %+v, want event ID 2", witness)
+	}
+	if witness.Rig != "greenplace" || witness.Target != "Toast" {
+		t.Errorf("witness escalation rig/target = %s/%s", witness.Rig, witness.Target)
+	}
+	if witness.Status != escalationClosed || witness.ClosedBy != "mayor" {
+		t.Errorf("witness status = %s closed by %q", witness.Status, witness.ClosedBy)
+	}
+}
+
+func TestBuildEscalations_Reminders(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	records := []events.Record{
+		escalationRecord(1, t0, events.TypeEscalationSent, "deacon", map[string]interface{}{
+			"escalation_id": "esc-1", "rig": "esc-1", "target": "deacon",
+			"to": "mayor/", "reason": "Refinery down", "severity": "critical",
+		}),
+		escalationRecord(2, t0.Add(5*time.Hour), events.TypeEscalationSent, "daemon", map[string]interface{}{
+			"escalation_id": "esc-1", "renotified": 1, "to": "mayor/",
+		}),
+	}
+
+	views := buildEscalations(records)
+	if len(views) != 1 {
+		t.Fatalf("got %d escalations, want 1", len(views))
+	}
+	e := views[0]
+	if e.Reminded != 1 || e.Severity != "critical" || e.Status != escalationOpen {
+		t.Errorf("escalation = %+v, want open, critical, reminded once", e)
+	}
+	if len(e.History) != 2 || e.History[1].Type != "reminded" {
+		t.Errorf("history = %+v, want sent then reminded", e.History)
+	}
+}
+
+func TestEscalationSLA(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status string
+		age    time.Duration
+		want   string
+	}{
+		{escalationOpen, time.Hour, slaOK},
+		{escalationOpen, 5 * time.Hour, slaBreached},
+		{escalationAcked, 5 * time.Hour, slaMet},
+		{escalationClosed, 5 * time.Hour, slaMet},
+	}
+	for _, tt := range tests {
+		e := &escalationView{Status: tt.status, OpenedAt: now.Add(-tt.age)}
+		if got := escalationSLA(e, 4*time.Hour, now); got != tt.want {
+			t.Errorf("escalationSLA(%s, %s) = %s, want %s", tt.status, tt.age, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,1019 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events command flags
+var (
+	eventsQueryLimit        int
+	eventsQueryType         string
+	eventsQueryJSON         bool
+	eventsQueryAnnotated    bool
+	eventsQueryRig          string
+	eventsQuerySignificance string
+	eventsQuerySince        string
+	eventsQueryUntil        string
+	eventsQueryFollow       bool
+	eventsQuerySubscriber   string
+	eventsQueryActor        string
+	eventsQueryPayload      []string
+	eventsAudit             bool
+
+	eventsAnnotateNote   string
+	eventsAnnotateAuthor string
+
+	eventsPruneBefore           string
+	eventsPruneKeepSignificance string
+
+	eventsRotateForce bool
+
+	eventsIndexRebuild bool
+
+	eventsExportFormat string
+	eventsExportOutput string
+	eventsExportType   string
+	eventsExportSince  string
+	eventsExportFields []string
+
+	eventsImportRenames []string
+	eventsImportDryRun  bool
+	eventsImportJSON    bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query and annotate the raw events log",
+	Long: `Query and annotate the raw events log (~/gt/.events.jsonl).
+
+Every event is addressed by its ID: its sequence number (line number) in the
+log, prefixed with the rig for events in a per-rig shard (e.g. "gastown:42").
+Shards are written to ~/gt/.events/<rig>.jsonl when settings/config.json sets
+"events": {"shard": "rig"}; query merges them with the town-wide log.
+Human annotations are stored beside the log in ~/gt/.events.annotations.jsonl
+and shown with the events they describe.
+
+Subcommands:
+  query      List events with their IDs and annotations
+  chain      Show the lifecycle of one piece of work
+  annotate   Attach a note to an event
+  prune      Remove events older than a cutoff
+  compact    Remove torn and malformed lines
+  dedupe     Remove events logged more than once
+  rotate     Archive logs past their size or age limit
+  export     Export events as CSV or Parquet for analysis
+  index      Build or update the SQLite index used by query
+  subscribers  List durable event broker subscribers
+
+Queries show events as the feed sees them: audit-only events are left out
+and payloads are redacted by "events": {"redact": [...]} in
+settings/config.json (by default, credentials such as tokens, secrets and
+passwords are stripped). The narrator and webhooks get the same treatment.
+--audit shows the raw audit stream instead: every event, as written.
+
+Examples:
+  gt events --audit                    # Last 20 events, unredacted
+  gt events query --audit --type authz_denied`,
+	RunE: runEvents,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "List events with IDs and annotations",
+	Long: `List events from the raw events log, newest last.
+
+Filters combine: --rig matches the rig an event is about, --significance
+keeps events at or above a level (low, medium, high), --since/--until
+take an RFC3339 time or an age such as 2h or 7d, and --payload matches a
+payload value by JSON path (repeatable).
+
+When the town has an events index (see 'gt events index') the query runs
+against it; otherwise the logs are scanned.
+
+--follow prints the matching events already in the log (up to --limit),
+then keeps printing new ones as they are written until interrupted. With
+--json, followed events are printed one JSON object per line. New events
+come from the daemon's event broker when it is running, and from the logs
+directly otherwise. --subscriber NAME follows as a durable broker
+subscriber instead: no backlog is printed, and a later follow under the same
+name resumes after the last event printed (see 'gt events subscribers').
+
+Examples:
+  gt events query                  # Last 20 events
+  gt events query -n 100           # Last 100 events
+  gt events query --type merge_failed
+  gt events query --rig gastown --significance high --since 2h
+  gt events query --since 2026-01-01T00:00:00Z --until 2026-01-02T00:00:00Z
+  gt events query --actor gastown/witness --payload reason=idle
+  gt events query --type merged --payload '$.merge.branch=polecat/Toast'
+  gt events query --annotated      # Only events with annotations
+  gt events query --follow --rig gastown
+  gt events query --follow --type done --subscriber release-notes
+  gt events query --json
+  gt events query --audit          # Include audit-only events, unredacted`,
+	RunE: runEventsQuery,
+}
+
+var eventsChainCmd = &cobra.Command{
+	Use:   "chain <work-id>",
+	Short: "Show the lifecycle of one piece of work",
+	Args:  cobra.ExactArgs(1),
+	Long: `Show every event about one work item, oldest first: sling, spawn, hook,
+done, merge_started, merged and so on.
+
+The work ID is the bead that was slung. Events carry it as their bead,
+their merge's source_issue, or an explicit work payload key. Merge events
+that name only a branch or merge request are linked through the done
+event that submitted the branch.
+
+Examples:
+  gt events chain gt-abc12
+  gt events chain gt-abc12 --json`,
+	RunE: runEventsChain,
+}
+
+var eventsAnnotateCmd = &cobra.Command{
+	Use:   "annotate <event-id>",
+	Short: "Attach a human note to an event",
+	Long: `Attach a human note to an event in the raw events log.
+
+Annotations add context the automatic classification can't infer, such as
+marking the event that was the root cause of an incident. They are shown by
+'gt events query' and are available to anything reading the log via the
+events package.
+
+Examples:
+  gt events annotate 1423 --note "this was the root cause"
+  gt events annotate 1423 --note "flaky CI, not a real failure" --author overseer
+  gt events annotate gastown:87 --note "first sign of the outage"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsAnnotate,
+}
+
+var eventsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove events older than a cutoff",
+	Long: `Remove events older than a cutoff from the events log and rig shards.
+
+--before takes an RFC3339 time or an age such as 720h or 30d. Annotated
+events are always kept; --keep-significance also keeps events at or above a
+significance level (low, medium, high).
+
+Logs are rewritten under the writers' lock via a temp file and rename.
+Sequence numbers are renumbered; annotations follow their events, and the
+renumbering is recorded in ~/gt/.events.remap.jsonl so readers that
+checkpoint a line offset can translate it.
+
+Examples:
+  gt events prune --before 30d
+  gt events prune --before 2026-01-01T00:00:00Z --keep-significance high`,
+	RunE: runEventsPrune,
+}
+
+var eventsCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Remove torn and malformed lines",
+	Long: `Rewrite the events log and rig shards without blank, torn or malformed
+lines. Readers already skip such lines; compacting reclaims the space and
+keeps sequence numbers dense. Renumbering is recorded the same way as for
+'gt events prune'.`,
+	RunE: runEventsCompact,
+}
+
+var eventsDedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Remove events logged more than once",
+	Long: `Rewrite the events log and rig shards keeping only the first copy of each
+event. Events are identified by their hash of type, actor, timestamp and
+payload; events logged before hashes were written are hashed as they are
+read. Readers already drop repeats; deduplicating reclaims the space.
+Annotated events are always kept. Renumbering is recorded the same way as
+for 'gt events prune'.`,
+	RunE: runEventsDedupe,
+}
+
+var eventsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Archive logs past their size or age limit",
+	Long: `Move the events log and rig shards into gzipped archives once they reach
+the limits in settings/config.json:
+
+  "events": {"rotate": {"max_size_mb": 100, "max_age": "168h"}}
+
+Archives are written beside each log as .events.YYYYMMDD.jsonl.gz (or
+.events/<rig>.YYYYMMDD.jsonl.gz) and the log starts afresh. Annotated events
+stay in the live log. The daemon rotates automatically on each heartbeat
+when limits are configured; --force rotates every non-empty log now.
+
+Rotations are recorded in ~/gt/.events.remap.jsonl, so readers replaying
+from an old checkpoint read the archived events too.`,
+	Args: cobra.NoArgs,
+	RunE: runEventsRotate,
+}
+
+var eventsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export events as CSV, Parquet or NDJSON",
+	Long: `Export events as a flat table for notebooks and BI tools, or whole.
+
+CSV and Parquet rows have the columns id, timestamp, type, actor, rig, role,
+significance and visibility, followed by one column per selected payload
+field. --field replaces the default payload fields (bead, target, agent,
+branch, status, reason); missing fields are empty.
+
+Parquet files have one row group of UTF-8 string columns, readable by
+pandas, DuckDB, Spark and most BI tools.
+
+NDJSON writes each event whole, one JSON object per line as in the events
+log, payload included. It is the format 'gt events import' reads, to merge
+one town's history into another.
+
+Examples:
+  gt events export --format csv > events.csv
+  gt events export --format parquet -o events.parquet
+  gt events export --since 7d --type merge_failed --field branch --field reason
+  gt events export --format ndjson --since 30d -o oldtown.ndjson`,
+	Args: cobra.NoArgs,
+	RunE: runEventsExport,
+}
+
+var eventsImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import events exported from another town",
+	Long: `Append events exported from another town to this town's logs.
+
+The file is an NDJSON export ('gt events export --format ndjson') or an
+events log itself; with no file, or "-", events are read from stdin.
+CSV and Parquet exports drop payloads and can't be imported.
+
+--rename-rig old=new renames a rig as events are imported: the payload's
+rig and the rig part of the actor and of agent addresses (target, agent,
+to, from, ...) such as old/Toast. Other payload values, like branches, are
+left alone.
+Events are written to the shard of their (renamed) rig when the town
+shards its logs.
+
+Events already in this town's logs are skipped by content hash, so running
+the same import twice imports nothing the second time. Imported events are
+appended; readers merge the logs in timestamp order.
+
+Examples:
+  gt events import oldtown.ndjson
+  gt events import oldtown.ndjson --rename-rig gastown=gastown-old --dry-run
+  ssh oldhost gt events export --format ndjson | gt events import`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEventsImport,
+}
+
+var eventsIndexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Build or update the SQLite index used by query",
+	Long: `Build or update the events index (~/gt/.events.db).
+
+The index lets 'gt events query' filter by type, rig, actor, time and
+payload without replaying the whole log. Queries only read it, reading
+events appended since the last update from the logs; with
+settings/config.json "events": {"index": true} the daemon updates it on
+each heartbeat, and otherwise rerun this command. Delete the file to go
+back to scanning. Requires the sqlite3 CLI; encrypted logs are never indexed.
+
+Examples:
+  gt events index
+  gt events index --rebuild`,
+	Args: cobra.NoArgs,
+	RunE: runEventsIndex,
+}
+
+var eventsSubscribersCmd = &cobra.Command{
+	Use:   "subscribers",
+	Short: "List durable event broker subscribers",
+	Long: `List the named subscribers of the event broker and how far each has read.
+
+The daemon runs an event broker that reads the events logs once and pushes
+new events to subscribers over a unix socket (~/gt/.runtime/events.sock),
+so followers don't each rescan the logs. A subscriber registers a filter
+and acks each event it has handled; a named subscriber's acked offsets are
+kept in ~/gt/.runtime/event-subscriptions/ and it resumes after them when
+it reconnects, so no event is missed (an unacked event may be delivered
+twice). Delete a subscriber's file to forget it.
+
+Examples:
+  gt events subscribers
+  gt events subscribers --json`,
+	Args: cobra.NoArgs,
+	RunE: runEventsSubscribers,
+}
+
+func init() {
+	eventsQueryCmd.Flags().IntVarP(&eventsQueryLimit, "limit", "n", 20, "Maximum number of events to show (0 for all)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryType, "type", "", "Only show events of this type")
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON")
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryAnnotated, "annotated", false, "Only show annotated events")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryRig, "rig", "", "Only show events about this rig")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySignificance, "significance", "", "Only show events at or above this significance (low, medium, high)")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySince, "since", "", "Only show events at or after this time or age (e.g. 2h)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryUntil, "until", "", "Only show events before this time or age")
+	eventsQueryCmd.Flags().BoolVarP(&eventsQueryFollow, "follow", "f", false, "Keep printing new events as they are written")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySubscriber, "subscriber", "", "Follow as this durable event broker subscriber, resuming where it left off")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryActor, "actor", "", "Only show events by this actor")
+	eventsQueryCmd.Flags().StringArrayVar(&eventsQueryPayload, "payload", nil, "Only show events whose payload path has this value (path=value, repeatable)")
+	eventsChainCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON")
+	for _, c := range []*cobra.Command{eventsCmd, eventsQueryCmd, eventsChainCmd} {
+		c.Flags().BoolVar(&eventsAudit, "audit", false, "Show the raw audit stream: audit-only events included, nothing redacted")
+	}
+
+	eventsAnnotateCmd.Flags().StringVar(&eventsAnnotateNote, "note", "", "Annotation text (required)")
+	eventsAnnotateCmd.Flags().StringVar(&eventsAnnotateAuthor, "author", "", "Who is annotating (auto-detected if not set)")
+	_ = eventsAnnotateCmd.MarkFlagRequired("note")
+
+	eventsPruneCmd.Flags().StringVar(&eventsPruneBefore, "before", "", "Remove events older than this time or age (required)")
+	eventsPruneCmd.Flags().StringVar(&eventsPruneKeepSignificance, "keep-significance", "", "Keep events at or above this significance (low, medium, high)")
+	_ = eventsPruneCmd.MarkFlagRequired("before")
+
+	eventsRotateCmd.Flags().BoolVar(&eventsRotateForce, "force", false, "Rotate every non-empty log regardless of limits")
+
+	eventsExportCmd.Flags().StringVar(&eventsExportFormat, "format", events.FormatCSV, "Output format: csv, parquet or ndjson")
+	eventsExportCmd.Flags().StringVarP(&eventsExportOutput, "output", "o", "", "Write to this file instead of stdout")
+	eventsExportCmd.Flags().StringVar(&eventsExportType, "type", "", "Only export events of this type")
+	eventsExportCmd.Flags().StringVar(&eventsExportSince, "since", "", "Only export events at or after this time or age (e.g. 7d)")
+	eventsExportCmd.Flags().StringArrayVar(&eventsExportFields, "field", nil, "Payload field to export as a column (repeatable)")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	eventsCmd.AddCommand(eventsChainCmd)
+	eventsCmd.AddCommand(eventsAnnotateCmd)
+	eventsCmd.AddCommand(eventsPruneCmd)
+	eventsCmd.AddCommand(eventsCompactCmd)
+	eventsCmd.AddCommand(eventsDedupeCmd)
+	eventsCmd.AddCommand(eventsRotateCmd)
+	eventsCmd.AddCommand(eventsExportCmd)
+
+	eventsImportCmd.Flags().StringArrayVar(&eventsImportRenames, "rename-rig", nil, "Rename a rig as events are imported (old=new, repeatable)")
+	eventsImportCmd.Flags().BoolVar(&eventsImportDryRun, "dry-run", false, "Count what would be imported without writing")
+	eventsImportCmd.Flags().BoolVar(&eventsImportJSON, "json", false, "Output as JSON")
+	eventsCmd.AddCommand(eventsImportCmd)
+
+	eventsIndexCmd.Flags().BoolVar(&eventsIndexRebuild, "rebuild", false, "Discard the index and rebuild it from the logs")
+	eventsCmd.AddCommand(eventsIndexCmd)
+
+	eventsSubscribersCmd.Flags().BoolVar(&eventsQueryJSON, "json", false, "Output as JSON")
+	eventsCmd.AddCommand(eventsSubscribersCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+// runEvents shows the audit stream for 'gt events --audit'; otherwise a
+// subcommand is required.
+func runEvents(cmd *cobra.Command, args []string) error {
+	if eventsAudit && len(args) == 0 {
+		return runEventsQuery(cmd, args)
+	}
+	return requireSubcommand(cmd, args)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	filter := events.Filter{
+		Rig:          eventsQueryRig,
+		Type:         eventsQueryType,
+		Actor:        eventsQueryActor,
+		Significance: eventsQuerySignificance,
+	}
+	for _, kv := range eventsQueryPayload {
+		path, value, ok := strings.Cut(kv, "=")
+		if !ok || path == "" {
+			return &usageError{err: fmt.Errorf("invalid --payload %q (want path=value)", kv)}
+		}
+		if filter.Payload == nil {
+			filter.Payload = make(map[string]string)
+		}
+		filter.Payload[path] = value
+	}
+	var err error
+	if eventsQuerySince != "" {
+		if filter.Since, err = parseCutoff("--since", eventsQuerySince); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	if eventsQueryUntil != "" {
+		if filter.Until, err = parseCutoff("--until", eventsQueryUntil); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return &usageError{err: err}
+	}
+	if eventsQueryFollow && eventsQueryAnnotated {
+		return &usageError{err: fmt.Errorf("--follow can't be combined with --annotated")}
+	}
+	if eventsQuerySubscriber != "" && !eventsQueryFollow {
+		return &usageError{err: fmt.Errorf("--subscriber requires --follow")}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if filter.Significance != "" {
+		if filter.Rules, err = events.LoadSignificanceRules(townRoot); err != nil {
+			return err
+		}
+	}
+	audience := events.AudienceFeed
+	if eventsAudit {
+		audience = events.AudienceAudit
+	}
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return err
+	}
+
+	// Start following before reading so no event falls between the two
+	var follower *eventFollower
+	if eventsQueryFollow {
+		if follower, err = startFollowing(townRoot, filter); err != nil {
+			return fmt.Errorf("following events: %w", err)
+		}
+		defer follower.Close()
+		if eventsQuerySubscriber != "" {
+			return followEvents(townRoot, follower, filter, policy, audience, nil)
+		}
+	}
+
+	records, err := events.Query(townRoot, filter)
+	if err != nil {
+		return err
+	}
+
+	var filtered []events.Record
+	for _, r := range records {
+		if eventsQueryAnnotated && len(r.Annotations) == 0 {
+			continue
+		}
+		var visible bool
+		if r.Event, visible = policy.Expose(r.Event, audience); !visible {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	if eventsQueryLimit > 0 && len(filtered) > eventsQueryLimit {
+		filtered = filtered[len(filtered)-eventsQueryLimit:]
+	}
+
+	if follower != nil {
+		return followEvents(townRoot, follower, filter, policy, audience, filtered)
+	}
+
+	if eventsQueryJSON {
+		if filtered == nil {
+			filtered = []events.Record{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(filtered)
+	}
+
+	if len(filtered) == 0 {
+		fmt.Println(style.Dim.Render("No events found"))
+		return nil
+	}
+
+	for _, r := range filtered {
+		printEventRecord(r)
+	}
+
+	return nil
+}
+
+// eventFollower is where followed events come from: a subscription to the
+// event broker, or a tailer of the logs when no broker is running.
+type eventFollower struct {
+	sub    *events.Subscription
+	tailer *events.Tailer
+}
+
+// startFollowing subscribes to the event broker for new events matching
+// filter, falling back to tailing the logs. A --subscriber follow requires
+// the broker.
+func startFollowing(townRoot string, filter events.Filter) (*eventFollower, error) {
+	req := events.SubscribeRequest{Name: eventsQuerySubscriber, Filter: filter, From: events.FromNow}
+	if eventsQuerySubscriber != "" {
+		req.From = events.FromSaved
+	}
+	sub, err := events.Subscribe(townRoot, req)
+	if err == nil {
+		return &eventFollower{sub: sub}, nil
+	}
+	if eventsQuerySubscriber != "" || !errors.Is(err, events.ErrNoBroker) {
+		return nil, err
+	}
+	tailer, err := events.NewTailer(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &eventFollower{tailer: tailer}, nil
+}
+
+func (f *eventFollower) Close() {
+	if f.sub != nil {
+		_ = f.sub.Close()
+	} else {
+		_ = f.tailer.Close()
+	}
+}
+
+// followEvents prints the backlog, then new matching events from follower
+// as audience sees them until interrupted.
+func followEvents(townRoot string, follower *eventFollower, filter events.Filter, policy *events.Policy, audience string, backlog []events.Record) error {
+	enc := json.NewEncoder(os.Stdout)
+	show := func(r events.Record) error {
+		var visible bool
+		if r.Event, visible = policy.Expose(r.Event, audience); !visible {
+			return nil
+		}
+		if eventsQueryJSON {
+			return enc.Encode(r)
+		}
+		printEventRecord(r)
+		return nil
+	}
+	for _, r := range backlog {
+		if eventsQueryJSON {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		} else {
+			printEventRecord(r)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if follower.sub != nil {
+		// The broker filters; events are acked once printed
+		received := make(chan events.Record)
+		failed := make(chan error, 1)
+		go func() {
+			for {
+				r, err := follower.sub.Next()
+				if err != nil {
+					failed <- err
+					return
+				}
+				received <- r
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-failed:
+				if errors.Is(err, io.EOF) {
+					return fmt.Errorf("event broker closed the subscription")
+				}
+				return err
+			case r := <-received:
+				if err := show(r); err != nil {
+					return err
+				}
+				if err := follower.sub.Ack(r); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	tailer := follower.tailer
+	dec := events.NewDecoder(townRoot)
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, line := range tailer.Poll() {
+				event, err := dec.Parse([]byte(line))
+				if err != nil || !filter.Match(event) {
+					continue
+				}
+				var visible bool
+				if event, visible = policy.Expose(event, audience); !visible {
+					continue
+				}
+				// Followed events have no sequence number yet
+				if eventsQueryJSON {
+					if err := enc.Encode(event); err != nil {
+						return err
+					}
+				} else {
+					printEventRecord(events.Record{Event: event})
+				}
+			}
+		}
+	}
+}
+
+// printEventRecord prints one event with its payload and annotations.
+// Records without a sequence number print without an ID.
+func printEventRecord(r events.Record) {
+	id := ""
+	if r.Seq > 0 {
+		id = r.ID()
+	}
+	fmt.Printf("%s %s %s %s\n",
+		style.Dim.Render(fmt.Sprintf("%6s", id)),
+		style.Dim.Render(r.Timestamp),
+		style.Bold.Render(r.Type),
+		r.Actor)
+	if summary := formatEventPayload(r.Payload); summary != "" {
+		fmt.Printf("       %s\n", style.Dim.Render(summary))
+	}
+	for _, ann := range r.Annotations {
+		author := ann.Author
+		if author == "" {
+			author = "unknown"
+		}
+		fmt.Printf("       %s %s (%s)\n", style.ArrowPrefix, ann.Note, author)
+	}
+}
+
+func runEventsChain(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	audience := events.AudienceFeed
+	if eventsAudit {
+		audience = events.AudienceAudit
+	}
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return err
+	}
+	chain, err := events.Chain(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	visible := []events.Record{}
+	for _, r := range chain {
+		var ok bool
+		if r.Event, ok = policy.Expose(r.Event, audience); ok {
+			visible = append(visible, r)
+		}
+	}
+
+	if eventsQueryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(visible)
+	}
+	if len(visible) == 0 {
+		fmt.Println(style.Dim.Render("No events found for " + args[0]))
+		return nil
+	}
+	for _, r := range visible {
+		printEventRecord(r)
+	}
+	return nil
+}
+
+func runEventsAnnotate(cmd *cobra.Command, args []string) error {
+	shard, seq, err := events.ParseEventID(args[0])
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	author := eventsAnnotateAuthor
+	if author == "" {
+		author = detectActor()
+	}
+
+	ann, err := events.Annotate(townRoot, shard, seq, eventsAnnotateNote, author)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Annotated event %s: %s\n", style.Bold.Render("✓"), events.EventID(ann.Shard, ann.Seq), ann.Note)
+	return nil
+}
+
+func runEventsPrune(cmd *cobra.Command, args []string) error {
+	before, err := parseEventsCutoff(eventsPruneBefore)
+	if err != nil {
+		return &usageError{err: err}
+	}
+	if eventsPruneKeepSignificance != "" {
+		if err := events.ValidSignificance(eventsPruneKeepSignificance); err != nil {
+			return &usageError{err: err}
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results, err := events.Prune(townRoot, before, eventsPruneKeepSignificance)
+	printRewriteResults("Pruned", results)
+	return err
+}
+
+func runEventsCompact(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results, err := events.Compact(townRoot)
+	printRewriteResults("Compacted", results)
+	return err
+}
+
+func runEventsDedupe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results, err := events.Deduplicate(townRoot)
+	printRewriteResults("Deduplicated", results)
+	return err
+}
+
+func runEventsRotate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rotator := events.NewRotator(townRoot)
+	if !rotator.Enabled() && !eventsRotateForce {
+		fmt.Println(style.Dim.Render("No rotation limits configured (settings/config.json events.rotate); use --force to rotate now"))
+		return nil
+	}
+	results, err := rotator.Rotate(time.Now(), eventsRotateForce)
+	if len(results) == 0 && err == nil {
+		fmt.Println(style.Dim.Render("No logs due for rotation"))
+	}
+	for _, r := range results {
+		fmt.Printf("%s Rotated %d events to %s (kept %d annotated)\n",
+			style.Bold.Render("✓"), r.Dropped, r.Archive, r.Kept)
+	}
+	return err
+}
+
+func runEventsIndex(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if eventsIndexRebuild {
+		if err := os.Remove(events.IndexPath(townRoot)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing index: %w", err)
+		}
+	}
+	added, err := events.UpdateIndex(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Indexed %d new events in %s\n", style.Bold.Render("✓"), added, events.IndexPath(townRoot))
+	return nil
+}
+
+func runEventsSubscribers(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	states, err := events.Subscribers(townRoot)
+	if err != nil {
+		return err
+	}
+	if eventsQueryJSON {
+		if states == nil {
+			states = []events.SubscriberState{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(states)
+	}
+	if len(states) == 0 {
+		fmt.Println(style.Dim.Render("No event subscribers"))
+		return nil
+	}
+	for _, st := range states {
+		fmt.Printf("%s %s\n", style.Bold.Render(st.Name), style.Dim.Render("updated "+st.Updated.Local().Format("2006-01-02 15:04:05")))
+		shards := make([]string, 0, len(st.Offsets))
+		for shard := range st.Offsets {
+			shards = append(shards, shard)
+		}
+		sort.Strings(shards)
+		for _, shard := range shards {
+			name := shard
+			if name == "" {
+				name = "(town)"
+			}
+			fmt.Printf("  %-20s acked through line %d\n", name, st.Offsets[shard].Lines)
+		}
+	}
+	return nil
+}
+
+func runEventsExport(cmd *cobra.Command, args []string) error {
+	switch eventsExportFormat {
+	case events.FormatCSV, events.FormatParquet, events.FormatNDJSON:
+	default:
+		return &usageError{err: fmt.Errorf("invalid --format %q: want csv, parquet or ndjson", eventsExportFormat)}
+	}
+	filter := events.Filter{Type: eventsExportType}
+	if eventsExportSince != "" {
+		var err error
+		filter.Since, err = parseCutoff("--since", eventsExportSince)
+		if err != nil {
+			return &usageError{err: err}
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return err
+	}
+	records, err := events.ReadAll(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var filtered []events.Record
+	for _, r := range records {
+		if filter.Match(r.Event) {
+			filtered = append(filtered, r)
+		}
+	}
+
+	fields := eventsExportFields
+	if len(fields) == 0 {
+		fields = events.DefaultExportFields
+	}
+
+	if eventsExportOutput == "" {
+		return events.Export(os.Stdout, eventsExportFormat, filtered, fields, rules)
+	}
+	f, err := os.Create(eventsExportOutput)
+	if err != nil {
+		return err
+	}
+	if err := events.Export(f, eventsExportFormat, filtered, fields, rules); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Exported %d events to %s\n", style.Bold.Render("✓"), len(filtered), eventsExportOutput)
+	return nil
+}
+
+func runEventsImport(cmd *cobra.Command, args []string) error {
+	renames := make(map[string]string, len(eventsImportRenames))
+	for _, pair := range eventsImportRenames {
+		from, to, ok := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" || strings.Contains(from+to, "/") {
+			return &usageError{err: fmt.Errorf("invalid --rename-rig %q: expected old=new", pair)}
+		}
+		renames[from] = to
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	in := io.Reader(os.Stdin)
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	evts, invalid, err := events.ReadImport(townRoot, in)
+	if err != nil {
+		return err
+	}
+	result, err := events.Import(townRoot, evts, renames, eventsImportDryRun)
+	if err != nil {
+		return err
+	}
+	result.Invalid = invalid
+
+	if eventsImportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	verb := "Imported"
+	if eventsImportDryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %s %d events\n", style.Bold.Render("✓"), verb, result.Imported)
+	if result.Renamed > 0 {
+		fmt.Printf("  %d with a renamed rig\n", result.Renamed)
+	}
+	if result.Duplicates > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d already in this town, skipped", result.Duplicates)))
+	}
+	if result.Invalid > 0 {
+		fmt.Printf("  %s\n", style.Warning.Render(fmt.Sprintf("%d invalid lines skipped", result.Invalid)))
+	}
+	return nil
+}
+
+// parseEventsCutoff parses an RFC3339 time or an age (e.g. 30d) before now.
+func parseEventsCutoff(s string) (time.Time, error) {
+	return parseCutoff("--before", s)
+}
+
+// parseCutoff parses a flag's date (2006-01-02, local time; or today or
+// yesterday, from midnight), RFC3339 time or age (e.g. 30d) before now.
+func parseCutoff(flag, s string) (time.Time, error) {
+	switch s {
+	case "today", "yesterday":
+		now := time.Now()
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		if s == "yesterday" {
+			midnight = midnight.AddDate(0, 0, -1)
+		}
+		return midnight, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	age, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: want date, today, yesterday, RFC3339 time or age like 30d", flag, s)
+	}
+	return time.Now().Add(-age), nil
+}
+
+// printRewriteResults reports what prune, compact or dedupe changed per log file.
+func printRewriteResults(verb string, results []events.RewriteResult) {
+	if len(results) == 0 {
+		fmt.Println(style.Dim.Render("Nothing to remove"))
+		return
+	}
+	for _, r := range results {
+		name := events.EventsFile
+		if r.Shard != "" {
+			name = r.Shard + " shard"
+		}
+		fmt.Printf("%s %s %s: removed %d, kept %d (generation %d)\n",
+			style.Bold.Render("✓"), verb, name, r.Dropped, r.Kept, r.Generation)
+	}
+}
+
+// formatEventPayload renders a payload as sorted key=value pairs for display.
+func formatEventPayload(payload map[string]interface{}) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, payload[k]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -94,7 +94,7 @@ func runFeed(cmd *cobra.Command, args []string) error {
 	// Must be in a Gas Town workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
-		return fmt.Errorf("not in a Gas Town workspace (run from ~/gt or a rig directory)")
+		return fmt.Errorf("%w (run from ~/gt or a rig directory)", workspace.ErrNotFound)
 	}
 
 	// Determine working directory
@@ -63,6 +63,11 @@ const HQGitignore = `# Gas Town HQ .gitignore
 **/*.lock
 **/registry.json
 
+# =============================================================================
+# Secrets (the events key decrypts the event logs committed beside it)
+# =============================================================================
+.events.key
+
 # =============================================================================
 # Rig git worktrees (recreate with 'gt sling' or 'gt rig add')
 # =============================================================================
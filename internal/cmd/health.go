@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	healthAll  bool
+	healthJSON bool
+
+	healthReportRole     string
+	healthReportPhase    string
+	healthReportDetail   string
+	healthReportQueue    int
+	healthReportActivity string
+	healthReportInterval string
+)
+
+var healthCmd = &cobra.Command{
+	Use:     "health [role]",
+	GroupID: GroupDiag,
+	Short:   "Show agents that report themselves unhealthy",
+	Long: `Summarize agent health from the probes agents write as they cycle.
+
+Each agent writes .runtime/health/<role>.json in the town root (see
+'gt health report') with its phase, when it last did something, and how
+much work is queued for it. An agent is unhealthy if it has missed three
+probes, reports itself stuck or in error, has had work queued with no
+activity for 30 minutes, or its session is no longer running.
+
+Only unhealthy agents are listed unless --all is given; with a role, that
+agent's health is shown. Exits 1 if any agent shown is unhealthy.
+
+Examples:
+  gt health
+  gt health --all
+  gt health gastown/witness --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHealth,
+}
+
+var healthReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write this agent's health probe",
+	Long: `Write the calling agent's health probe to .runtime/health/<role>.json.
+
+Agents run this each cycle (a patrol, a merge, a step of work). The role
+defaults to the caller's address (from GT_ROLE or the working directory).
+--activity is when the agent last made progress, as an RFC3339 time or an
+age such as 10m; it defaults to now. --interval says how often the agent
+probes, so a missed probe can be noticed (default 5m).
+
+Examples:
+  gt health report --phase patrol --queue 3
+  gt health report --phase working --detail gt-42 --interval 10m
+  gt health report --phase stuck --detail "merge conflict on main"`,
+	Args: cobra.NoArgs,
+	RunE: runHealthReport,
+}
+
+func init() {
+	healthCmd.Flags().BoolVar(&healthAll, "all", false, "Show healthy agents too")
+	healthCmd.Flags().BoolVar(&healthJSON, "json", false, "Output as JSON")
+
+	healthReportCmd.Flags().StringVar(&healthReportRole, "role", "", "Agent address to report for (default: this agent)")
+	healthReportCmd.Flags().StringVar(&healthReportPhase, "phase", health.PhaseWorking, "What the agent is doing (starting, idle, working, patrol, stuck, error)")
+	healthReportCmd.Flags().StringVar(&healthReportDetail, "detail", "", "What the phase is about, e.g. the bead being worked")
+	healthReportCmd.Flags().IntVar(&healthReportQueue, "queue", 0, "Work items waiting on the agent")
+	healthReportCmd.Flags().StringVar(&healthReportActivity, "activity", "", "When the agent last made progress (RFC3339 time or age; default now)")
+	healthReportCmd.Flags().StringVar(&healthReportInterval, "interval", "", "How often the agent probes (default 5m)")
+
+	healthCmd.AddCommand(healthReportCmd)
+	rootCmd.AddCommand(healthCmd)
+}
+
+// newHealthManager returns a probe manager that checks sessions in tmux.
+func newHealthManager(townRoot string) *health.Manager {
+	m := health.NewManager(townRoot)
+	t := tmux.NewTmux()
+	m.SessionAlive = func(session string) bool {
+		running, err := t.HasSession(session)
+		return err == nil && running && t.IsClaudeRunning(session)
+	}
+	return m
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	m := newHealthManager(townRoot)
+
+	var agents []*health.AgentHealth
+	if len(args) == 1 {
+		h, err := m.HealthOf(args[0])
+		if err != nil {
+			return err
+		}
+		agents = append(agents, h)
+	} else {
+		all, err := m.All()
+		if err != nil {
+			return err
+		}
+		for _, h := range all {
+			if healthAll || !h.Healthy {
+				agents = append(agents, h)
+			}
+		}
+	}
+	unhealthy := 0
+	for _, h := range agents {
+		if !h.Healthy {
+			unhealthy++
+		}
+	}
+
+	if healthJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if agents == nil {
+			agents = []*health.AgentHealth{}
+		}
+		if err := enc.Encode(agents); err != nil {
+			return err
+		}
+	} else {
+		if len(agents) == 0 {
+			fmt.Printf("%s All reporting agents healthy\n", style.Success.Render("✓"))
+			return nil
+		}
+		for _, h := range agents {
+			printAgentHealth(h)
+		}
+	}
+	if unhealthy > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// printAgentHealth prints one agent's health and its last probe.
+func printAgentHealth(h *health.AgentHealth) {
+	if h.Healthy {
+		fmt.Printf("%s %s\n", style.Success.Render("✓"), h.Role)
+	} else {
+		fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), h.Role, h.Reason)
+	}
+	if p := h.Probe; p != nil {
+		line := fmt.Sprintf("phase %s", p.Phase)
+		if p.Detail != "" {
+			line += " (" + p.Detail + ")"
+		}
+		line += fmt.Sprintf(", queue %d, active %s ago, probed %s ago",
+			p.QueueDepth, time.Since(p.LastActivity).Round(time.Second), time.Since(p.WrittenAt).Round(time.Second))
+		fmt.Printf("  %s\n", style.Dim.Render(line))
+	}
+}
+
+func runHealthReport(cmd *cobra.Command, args []string) error {
+	if healthReportQueue < 0 {
+		return &usageError{err: errors.New("--queue must be non-negative")}
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	role := healthReportRole
+	self := role == ""
+	if self {
+		role = detectSender()
+	}
+
+	probe := &health.Probe{
+		Role:       role,
+		Phase:      healthReportPhase,
+		Detail:     healthReportDetail,
+		QueueDepth: healthReportQueue,
+		Interval:   healthReportInterval,
+	}
+	// Only our own session can be vouched for
+	if self && os.Getenv("TMUX") != "" {
+		if name, err := getCurrentTmuxSession(); err == nil {
+			probe.Session = name
+		}
+	}
+	if healthReportActivity != "" {
+		at, err := parseCutoff("--activity", healthReportActivity)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		probe.LastActivity = at
+	}
+	if err := newHealthManager(townRoot).Report(probe); err != nil {
+		return fmt.Errorf("writing health probe: %w", err)
+	}
+	fmt.Printf("%s Reported %s: %s\n", style.Success.Render("✓"), probe.Role, probe.Phase)
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/shell"
 	"github.com/steveyegge/gastown/internal/state"
@@ -276,6 +277,14 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   ✓ Created settings/escalation.json\n")
 	}
 
+	// Scaffold the event log, significance rules, narrator and mail templates
+	// so those subsystems work without first creating their paths by hand.
+	if err := scaffoldTown(absPath); err != nil {
+		fmt.Printf("   %s Could not scaffold town files: %v\n", style.Dim.Render("⚠"), err)
+	} else {
+		fmt.Printf("   ✓ Created .events.jsonl, settings/significance.json, narrator/, mail/templates/, town.yaml\n")
+	}
+
 	// Provision town-level slash commands (.claude/commands/)
 	// All agents inherit these via Claude's directory traversal - no per-workspace copies needed.
 	if err := templates.ProvisionCommands(absPath); err != nil {
@@ -344,6 +353,31 @@ Full context is injected by ` + "`gt prime`" + ` at session start.
 	return os.WriteFile(claudePath, []byte(bootstrap), 0644)
 }
 
+// scaffoldTown creates the town files the events, narrator and mail
+// subsystems read. Existing files are left alone, so reinstalling with
+// --force keeps local edits.
+func scaffoldTown(townRoot string) error {
+	logPath := events.LogPath(townRoot, "")
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", events.EventsFile, err)
+	}
+	_ = f.Close()
+
+	rulesPath := events.SignificanceRulesPath(townRoot)
+	if _, err := os.Stat(rulesPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(rulesPath), 0755); err != nil {
+			return fmt.Errorf("creating settings directory: %w", err)
+		}
+		if err := writeJSON(rulesPath, events.DefaultSignificanceRules()); err != nil {
+			return fmt.Errorf("writing significance rules: %w", err)
+		}
+	}
+
+	_, err = templates.ProvisionTown(townRoot)
+	return err
+}
+
 func writeJSON(path string, data interface{}) error {
 	content, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
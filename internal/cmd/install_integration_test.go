@@ -49,6 +49,13 @@ func TestInstallCreatesCorrectStructure(t *testing.T) {
 		t.Errorf("town.json name = %q, want %q", townConfig.Name, "test-town")
 	}
 
+	// Verify town scaffolding for events, narrator and mail
+	assertFileExists(t, filepath.Join(hqPath, ".events.jsonl"), ".events.jsonl")
+	assertFileExists(t, filepath.Join(hqPath, "settings", "significance.json"), "settings/significance.json")
+	assertFileExists(t, filepath.Join(hqPath, "town.yaml"), "town.yaml")
+	assertDirExists(t, filepath.Join(hqPath, "narrator", "styles"), "narrator/styles/")
+	assertDirExists(t, filepath.Join(hqPath, "mail", "templates"), "mail/templates/")
+
 	// Verify mayor/rigs.json
 	rigsPath := filepath.Join(hqPath, "mayor", "rigs.json")
 	assertFileExists(t, rigsPath, "mayor/rigs.json")
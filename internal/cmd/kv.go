@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/blackboard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	kvNamespace string
+	kvTTL       time.Duration
+	kvJSON      bool
+	kvInterval  time.Duration
+)
+
+var kvCmd = &cobra.Command{
+	Use:     "kv",
+	GroupID: GroupComm,
+	Short:   "Shared blackboard for cross-agent coordination state",
+	Long: `Read and write the town blackboard: a small key/value store for
+transient state agents need to share, such as claims, deploy windows or the
+current release candidate.
+
+Keys live in namespaces (--ns, default "town"); use a rig name or a
+feature name to keep unrelated state apart. Entries set with --ttl expire
+on their own.
+
+Examples:
+  gt kv set deploy-window open --ttl 30m
+  gt kv get deploy-window
+  gt kv set --ns gastown release-candidate v1.4.0-rc2
+  gt kv watch --ns gastown release-candidate
+  gt kv list --ns gastown
+  gt kv del deploy-window`,
+	RunE: requireSubcommand,
+}
+
+var kvGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a key's value (exit 1 if unset)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runKVGet,
+}
+
+var kvSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a key",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runKVSet,
+}
+
+var kvDelCmd = &cobra.Command{
+	Use:     "del <key>",
+	Aliases: []string{"delete", "rm"},
+	Short:   "Delete a key",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runKVDel,
+}
+
+var kvListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List keys in a namespace",
+	Args:    cobra.NoArgs,
+	RunE:    runKVList,
+}
+
+var kvWatchCmd = &cobra.Command{
+	Use:   "watch <key>",
+	Short: "Print a key's value each time it changes",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runKVWatch,
+}
+
+func init() {
+	kvCmd.PersistentFlags().StringVar(&kvNamespace, "ns", blackboard.DefaultNamespace, "Namespace")
+	kvGetCmd.Flags().BoolVar(&kvJSON, "json", false, "Output the full entry as JSON")
+	kvSetCmd.Flags().DurationVar(&kvTTL, "ttl", 0, "Expire the key after this long (e.g. 30m)")
+	kvListCmd.Flags().BoolVar(&kvJSON, "json", false, "Output as JSON")
+	kvWatchCmd.Flags().BoolVar(&kvJSON, "json", false, "Output entries as JSON lines")
+	kvWatchCmd.Flags().DurationVar(&kvInterval, "interval", time.Second, "Poll interval")
+
+	kvCmd.AddCommand(kvGetCmd)
+	kvCmd.AddCommand(kvSetCmd)
+	kvCmd.AddCommand(kvDelCmd)
+	kvCmd.AddCommand(kvListCmd)
+	kvCmd.AddCommand(kvWatchCmd)
+	rootCmd.AddCommand(kvCmd)
+}
+
+func kvStore() (*blackboard.Store, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return blackboard.New(townRoot), nil
+}
+
+func runKVGet(cmd *cobra.Command, args []string) error {
+	store, err := kvStore()
+	if err != nil {
+		return err
+	}
+	entry, err := store.Get(kvNamespace, args[0])
+	if errors.Is(err, blackboard.ErrNotFound) {
+		return NewSilentExit(1)
+	}
+	if err != nil {
+		return err
+	}
+	if kvJSON {
+		out, _ := json.MarshalIndent(entry, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Println(entry.Value)
+	return nil
+}
+
+func runKVSet(cmd *cobra.Command, args []string) error {
+	store, err := kvStore()
+	if err != nil {
+		return err
+	}
+	if kvTTL < 0 {
+		return fmt.Errorf("--ttl must not be negative")
+	}
+	entry, err := store.Set(kvNamespace, args[0], args[1], detectSender(), kvTTL)
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("%s %s/%s = %s", style.Bold.Render("✓"), kvNamespace, entry.Key, entry.Value)
+	if entry.ExpiresAt != nil {
+		msg += style.Dim.Render(fmt.Sprintf(" (expires in %s)", kvTTL))
+	}
+	fmt.Println(msg)
+	return nil
+}
+
+func runKVDel(cmd *cobra.Command, args []string) error {
+	store, err := kvStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(kvNamespace, args[0]); err != nil {
+		if errors.Is(err, blackboard.ErrNotFound) {
+			return fmt.Errorf("%s/%s: %w", kvNamespace, args[0], err)
+		}
+		return err
+	}
+	fmt.Printf("%s Deleted %s/%s\n", style.Bold.Render("✓"), kvNamespace, args[0])
+	return nil
+}
+
+func runKVList(cmd *cobra.Command, args []string) error {
+	store, err := kvStore()
+	if err != nil {
+		return err
+	}
+	entries, err := store.List(kvNamespace)
+	if err != nil {
+		return err
+	}
+	if kvJSON {
+		out, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No keys in namespace %s\n", kvNamespace)
+		return nil
+	}
+	now := time.Now()
+	for _, e := range entries {
+		detail := fmt.Sprintf("by %s, %s ago", e.SetBy, formatDuration(now.Sub(e.UpdatedAt)))
+		if e.ExpiresAt != nil {
+			detail += fmt.Sprintf(", expires in %s", formatDuration(e.ExpiresAt.Sub(now)))
+		}
+		fmt.Printf("  %-24s %s  %s\n", e.Key, e.Value, style.Dim.Render(detail))
+	}
+	return nil
+}
+
+func runKVWatch(cmd *cobra.Command, args []string) error {
+	store, err := kvStore()
+	if err != nil {
+		return err
+	}
+	if kvInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return store.Watch(ctx, kvNamespace, args[0], kvInterval, func(e *blackboard.Entry) error {
+		if kvJSON {
+			out, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+		ts := time.Now().Format("15:04:05")
+		if e == nil {
+			fmt.Printf("%s %s\n", style.Dim.Render(ts), style.Dim.Render("(unset)"))
+			return nil
+		}
+		fmt.Printf("%s %s %s\n", style.Dim.Render(ts), e.Value, style.Dim.Render("by "+e.SetBy))
+		return nil
+	})
+}
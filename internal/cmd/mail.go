@@ -15,9 +15,15 @@ var (
 	mailPermanent     bool
 	mailType          string
 	mailReplyTo       string
+	mailNewThread     bool
 	mailNotify        bool
 	mailSendSelf      bool
+	mailSendTo        string
 	mailCC            []string // CC recipients
+	mailReceipt       bool
+	mailSendAt        string
+	mailSendTemplate  string
+	mailSendVars      []string
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -26,6 +32,7 @@ var (
 	mailCheckJSON     bool
 	mailCheckIdentity string
 	mailThreadJSON    bool
+	mailThreadsJSON   bool
 	mailReplySubject  string
 	mailReplyMessage  string
 
@@ -35,12 +42,32 @@ var (
 	mailSearchBody    bool
 	mailSearchArchive bool
 	mailSearchJSON    bool
+	mailSearchSince   string
+	mailSearchUntil   string
+	mailSearchThread  string
+	mailSearchType    string
 
 	// Announces flags
 	mailAnnouncesJSON bool
 
 	// Clear flags
 	mailClearAll bool
+
+	// Dead-letter queue flags
+	mailDLQJSON     bool
+	mailDLQRetryAll bool
+
+	// Scheduled mail flags
+	mailScheduledJSON bool
+
+	// Compact flags
+	mailCompactArchiveAfter int
+	mailCompactPurgeAfter   int
+	mailCompactDryRun       bool
+	mailCompactJSON         bool
+
+	// Templates flags
+	mailTemplatesJSON bool
 )
 
 var mailCmd = &cobra.Command{
@@ -86,7 +113,7 @@ COMMANDS:
 }
 
 var mailSendCmd = &cobra.Command{
-	Use:   "send <address>",
+	Use:   "send [address]",
 	Short: "Send a message",
 	Long: `Send a message to an agent.
 
@@ -95,12 +122,20 @@ Addresses:
   <rig>/refinery   - Send to a rig's Refinery
   <rig>/<polecat>  - Send to a specific polecat
   <rig>/           - Broadcast to a rig
+  <rig>/*          - Broadcast to every agent in a rig
+  all-polecats     - Broadcast to every polecat in every rig
+                     (also all-crew, all-witnesses, all-refineries, all-dogs)
   list:<name>      - Send to a mailing list (fans out to all members)
+  <name>           - A mailing list by name, if no group, queue or channel has it
 
 Mailing lists are defined in ~/gt/config/messaging.json and allow
 sending to multiple recipients at once. Each recipient gets their
 own copy of the message.
 
+Broadcasts share one thread, and each copy records the group address it
+was sent to, so recipients can see the message went to more than them.
+The address can be given as an argument or with --to.
+
 Message types:
   task          - Required processing
   scavenge      - Optional first-come work
@@ -116,6 +151,30 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Priority sets how the recipient is told. By default urgent mail nudges
+their session at once and again every 10 minutes until read, high mail
+nudges at once, normal mail is mentioned in their next nudge (the daemon
+sends one each heartbeat), and low mail never interrupts. Each role's
+policy can be changed under "notify" in config/messaging.json.
+
+Threading: without --reply-to, a message joins the most recent thread from
+the last 72 hours with the same subject (ignoring Re:/Fwd: prefixes) that
+involved the sender and a recipient. Use --new-thread to always start fresh.
+
+Use --receipt to be mailed an acknowledgment when the recipient marks the
+message read ('gt mail mark-read').
+
+Use --at to send later: the message is saved, with recipients resolved now,
+and the daemon sends it on the first heartbeat after the time comes. --at
+takes a delay (2h, 3d), a time of day (9am, 14:30), a day with an optional
+time (tomorrow, "tomorrow 9am", "fri 10:00") or a date ("2026-03-01 14:00").
+A day alone means 9am. See 'gt mail scheduled' to list or cancel.
+
+Use --template to write the message from a mail template: its front-matter
+sets the subject, priority and type, and --var key=value fills in its
+variables (from, to, and -s/-m as subject/body, are always set). --priority,
+--urgent and --type still override the template. See 'gt mail templates'.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -125,7 +184,12 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send greenplace/Toast -s "Deploy freeze" -m "Hold merges" --receipt
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send --to all-polecats -s "Freeze" -m "Stop merging until the fix lands"
+  gt mail send gastown/* -s "Rebase" -m "main was force-pushed"
+  gt mail send mayor/ -s "Standup" -m "Yesterday: gt-abc" --at "tomorrow 9am"
+  gt mail send greenplace/Toast --template merge-failed --var worker=Toast --var bead=gt-42`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -142,7 +206,8 @@ Examples:
   gt mail inbox                       # Current context (auto-detected)
   gt mail inbox mayor/                # Mayor's inbox
   gt mail inbox greenplace/Toast         # Polecat's inbox
-  gt mail inbox --identity greenplace/Toast  # Explicit polecat identity`,
+  gt mail inbox --identity greenplace/Toast  # Explicit polecat identity
+  gt mail inbox --unread              # Only messages not yet marked read`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailInbox,
 }
@@ -201,6 +266,8 @@ var mailMarkReadCmd = &cobra.Command{
 
 This adds a 'read' label to the message, which is reflected in the inbox display.
 The message remains in your inbox (unlike archive which closes/removes it).
+If the sender asked for a receipt (gt mail send --receipt), one is mailed
+back to them the first time the message is marked read.
 
 Use case: You've read a message but want to keep it visible in your inbox
 for reference or follow-up.
@@ -226,6 +293,26 @@ Examples:
 	RunE: runMailMarkUnread,
 }
 
+var mailSnoozeCmd = &cobra.Command{
+	Use:   "snooze <message-id> <when>",
+	Short: "Hide a message until later",
+	Long: `Snooze a message in your inbox until later.
+
+The message is marked read now. When the time comes, the daemon marks it
+unread again and notifies you as if it had just arrived. <when> takes the
+same forms as 'gt mail send --at': a delay (2h), a time of day (9am), a day
+with an optional time ("tomorrow 9am", monday) or a date.
+
+Snoozes are listed, and can be cancelled, with 'gt mail scheduled'.
+
+Examples:
+  gt mail snooze hq-abc123 2h
+  gt mail snooze hq-abc123 "tomorrow 9am"
+  gt mail snooze hq-abc123 monday`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMailSnooze,
+}
+
 var mailCheckCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Check for new mail (for hooks)",
@@ -261,6 +348,21 @@ Examples:
 	RunE: runMailThread,
 }
 
+var mailThreadsCmd = &cobra.Command{
+	Use:   "threads",
+	Short: "List conversations in your inbox",
+	Long: `List your inbox grouped into conversation threads.
+
+Threads are shown most recently active first, with their message and
+unread counts. View one with 'gt mail thread <thread-id>'.
+
+Examples:
+  gt mail threads
+  gt mail threads --json`,
+	Args: cobra.NoArgs,
+	RunE: runMailThreads,
+}
+
 var mailReplyCmd = &cobra.Command{
 	Use:   "reply <message-id>",
 	Short: "Reply to a message",
@@ -356,15 +458,22 @@ Examples:
 var mailSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search messages by content",
-	Long: `Search inbox for messages matching a pattern.
+	Long: `Search inbox and archive for messages containing words.
 
 SYNTAX:
   gt mail search <query> [flags]
 
-The query is a regular expression pattern. Search is case-insensitive by default.
+The query is one or more words; a message matches when it contains every
+word, each matching the start of a word in the message (so "deploy" finds
+"deployment"). Search is case-insensitive. Words are looked up in a search
+index kept next to the mailbox, updated with new mail on each search.
 
 FLAGS:
   --from <sender>   Filter by sender address (substring match)
+  --since <time>    Only messages sent at or after (date, RFC3339 or age like 7d)
+  --until <time>    Only messages sent before (date, RFC3339 or age)
+  --thread <id>     Only messages in this thread
+  --type <type>     Only messages of this type (task, scavenge, notification, reply)
   --subject         Only search subject lines
   --body            Only search message body
   --archive         Include archived (closed) messages
@@ -374,9 +483,11 @@ By default, searches both subject and body text.
 
 Examples:
   gt mail search "urgent"                    # Find messages with "urgent"
-  gt mail search "status.*check" --subject   # Regex in subjects only
+  gt mail search "status check" --subject    # Both words in the subject
   gt mail search "error" --from witness      # From witness, containing "error"
-  gt mail search "handoff" --archive         # Include archived messages
+  gt mail search "handoff" --since 7d        # Handoffs from the last week
+  gt mail search "" --thread thread-abc123   # Every message in a thread
+  gt mail search "" --type task --since 2026-01-01
   gt mail search "" --from mayor/            # All messages from mayor`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailSearch,
@@ -414,21 +525,147 @@ Examples:
 	RunE: runMailAnnounces,
 }
 
+var mailDLQCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect and retry undeliverable mail",
+	Long: `Inspect and retry mail that could not be delivered.
+
+Sends that fail are retried with backoff. A message that still can't be
+stored (for example because bd keeps erroring) is saved to the town's
+dead-letter queue in .runtime/mail-dlq/ instead of being lost.
+
+Examples:
+  gt mail dlq list
+  gt mail dlq retry dl-1a2b3c4d5e6f7a8b
+  gt mail dlq retry --all`,
+	RunE: requireSubcommand,
+}
+
+var mailDLQListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List undeliverable messages",
+	Args:  cobra.NoArgs,
+	RunE:  runMailDLQList,
+}
+
+var mailDLQRetryCmd = &cobra.Command{
+	Use:   "retry [dead-letter-id...]",
+	Short: "Retry delivering dead-lettered messages",
+	Long: `Retry delivering dead-lettered messages.
+
+Delivered messages leave the queue. Messages that fail again stay queued
+with their latest error.`,
+	RunE: runMailDLQRetry,
+}
+
+var mailScheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List and cancel scheduled and snoozed mail",
+	Long: `List and cancel mail waiting for its time.
+
+Messages sent with 'gt mail send --at' and messages snoozed with
+'gt mail snooze' are kept in the town's .runtime/mail-scheduled/ until the
+daemon's heartbeat finds them due. Cancelling a scheduled send drops the
+message; cancelling a snooze leaves the message read.
+
+Examples:
+  gt mail scheduled
+  gt mail scheduled list --json
+  gt mail scheduled cancel sched-1a2b3c4d5e6f7a8b`,
+	Args: cobra.NoArgs,
+	RunE: runMailScheduledList,
+}
+
+var mailTemplatesCmd = &cobra.Command{
+	Use:   "templates [name]",
+	Short: "List mail templates or show one",
+	Long: `List the mail templates 'gt mail send --template' can use, or show one.
+
+A template is a Markdown file whose YAML front-matter gives the subject,
+priority, type and optional variables with their defaults; the body
+follows. Subject and body are Go templates over the variables:
+
+  ---
+  subject: "Merge failed: {{.bead}}"
+  priority: high
+  type: task
+  vars:
+    reason: ""
+  ---
+  {{.worker}}, the merge of {{.bead}} failed. {{.reason}}
+
+Templates in the town's mail/templates/ directory override the built-in
+ones of the same name.
+
+Examples:
+  gt mail templates
+  gt mail templates merge-failed`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMailTemplates,
+}
+
+var mailCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Archive and purge old mail per the retention policy",
+	Long: `Apply the town's mail retention policy.
+
+Read messages older than the archive age are exported to the town's
+mail/archive/ directory, one JSONL file per month sent, and deleted from
+their beads database, so old mail stops piling up there. Pinned and snoozed
+messages are kept. Archived messages older than the purge age are then
+dropped from the archive.
+
+The ages come from retention in config/messaging.json; the flags override
+them for one run (0 skips a step). With retention.daemon set, the daemon
+compacts once a day:
+
+  "retention": {"archive_after_days": 14, "purge_after_days": 365, "daemon": true}
+
+Examples:
+  gt mail compact
+  gt mail compact --dry-run
+  gt mail compact --archive-after 7 --purge-after 0`,
+	Args: cobra.NoArgs,
+	RunE: runMailCompact,
+}
+
+var mailScheduledListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled and snoozed mail, soonest first",
+	Args:  cobra.NoArgs,
+	RunE:  runMailScheduledList,
+}
+
+var mailScheduledCancelCmd = &cobra.Command{
+	Use:   "cancel <schedule-id> [schedule-id...]",
+	Short: "Cancel scheduled sends and snoozes",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMailScheduledCancel,
+}
+
 func init() {
 	// Send flags
-	mailSendCmd.Flags().StringVarP(&mailSubject, "subject", "s", "", "Message subject (required)")
+	mailSendCmd.Flags().StringVarP(&mailSubject, "subject", "s", "", "Message subject (required unless --template is given)")
 	mailSendCmd.Flags().StringVarP(&mailBody, "message", "m", "", "Message body")
 	mailSendCmd.Flags().IntVar(&mailPriority, "priority", 2, "Message priority (0=urgent, 1=high, 2=normal, 3=low, 4=backlog)")
 	mailSendCmd.Flags().BoolVar(&mailUrgent, "urgent", false, "Set priority=0 (urgent)")
 	mailSendCmd.Flags().StringVar(&mailType, "type", "notification", "Message type (task, scavenge, notification, reply)")
 	mailSendCmd.Flags().StringVar(&mailReplyTo, "reply-to", "", "Message ID this is replying to")
+	mailSendCmd.Flags().BoolVar(&mailNewThread, "new-thread", false, "Start a new thread instead of joining a matching recent one")
 	mailSendCmd.Flags().BoolVarP(&mailNotify, "notify", "n", false, "Send tmux notification to recipient")
 	mailSendCmd.Flags().BoolVar(&mailPinned, "pinned", false, "Pin message (for handoff context that persists)")
 	mailSendCmd.Flags().BoolVar(&mailWisp, "wisp", true, "Send as wisp (ephemeral, default)")
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
+	mailSendCmd.Flags().StringVar(&mailSendTo, "to", "", "Recipient address (alternative to the address argument)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
-	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
+	mailSendCmd.Flags().BoolVar(&mailReceipt, "receipt", false, "Request a receipt when the recipient marks the message read")
+	mailSendCmd.Flags().StringVar(&mailSendAt, "at", "", "Send later, e.g. 2h, 9am or \"tomorrow 9am\" (sent by the daemon)")
+	mailSendCmd.Flags().StringVar(&mailSendTemplate, "template", "", "Write the message from a mail template (see 'gt mail templates')")
+	mailSendCmd.Flags().StringArrayVar(&mailSendVars, "var", nil, "Template variable as key=value (can be used multiple times)")
+
+	// Templates flags
+	mailTemplatesCmd.Flags().BoolVar(&mailTemplatesJSON, "json", false, "Output as JSON")
 
 	// Inbox flags
 	mailInboxCmd.Flags().BoolVar(&mailInboxJSON, "json", false, "Output as JSON")
@@ -447,6 +684,7 @@ func init() {
 
 	// Thread flags
 	mailThreadCmd.Flags().BoolVar(&mailThreadJSON, "json", false, "Output as JSON")
+	mailThreadsCmd.Flags().BoolVar(&mailThreadsJSON, "json", false, "Output as JSON")
 
 	// Reply flags
 	mailReplyCmd.Flags().StringVarP(&mailReplySubject, "subject", "s", "", "Override reply subject (default: Re: <original>)")
@@ -459,6 +697,10 @@ func init() {
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
 	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
+	mailSearchCmd.Flags().StringVar(&mailSearchSince, "since", "", "Only messages sent at or after this date, time or age (e.g. 7d)")
+	mailSearchCmd.Flags().StringVar(&mailSearchUntil, "until", "", "Only messages sent before this date, time or age")
+	mailSearchCmd.Flags().StringVar(&mailSearchThread, "thread", "", "Only messages in this thread")
+	mailSearchCmd.Flags().StringVar(&mailSearchType, "type", "", "Only messages of this type (task, scavenge, notification, reply)")
 
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
@@ -466,8 +708,27 @@ func init() {
 	// Clear flags
 	mailClearCmd.Flags().BoolVar(&mailClearAll, "all", false, "Clear all messages (default behavior)")
 
+	// Dead-letter queue flags
+	mailDLQListCmd.Flags().BoolVar(&mailDLQJSON, "json", false, "Output as JSON")
+	mailDLQRetryCmd.Flags().BoolVar(&mailDLQRetryAll, "all", false, "Retry every dead letter")
+	mailDLQCmd.AddCommand(mailDLQListCmd)
+	mailDLQCmd.AddCommand(mailDLQRetryCmd)
+
+	// Scheduled mail
+	mailScheduledCmd.Flags().BoolVar(&mailScheduledJSON, "json", false, "Output as JSON")
+	mailScheduledListCmd.Flags().BoolVar(&mailScheduledJSON, "json", false, "Output as JSON")
+	mailScheduledCmd.AddCommand(mailScheduledListCmd)
+	mailScheduledCmd.AddCommand(mailScheduledCancelCmd)
+
+	// Compact flags
+	mailCompactCmd.Flags().IntVar(&mailCompactArchiveAfter, "archive-after", 0, "Archive read mail older than this many days (default: retention.archive_after_days)")
+	mailCompactCmd.Flags().IntVar(&mailCompactPurgeAfter, "purge-after", 0, "Purge archived mail older than this many days (default: retention.purge_after_days)")
+	mailCompactCmd.Flags().BoolVar(&mailCompactDryRun, "dry-run", false, "Show what would be archived and purged without changing anything")
+	mailCompactCmd.Flags().BoolVar(&mailCompactJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	mailCmd.AddCommand(mailSendCmd)
+	mailCmd.AddCommand(mailTemplatesCmd)
 	mailCmd.AddCommand(mailInboxCmd)
 	mailCmd.AddCommand(mailReadCmd)
 	mailCmd.AddCommand(mailPeekCmd)
@@ -477,12 +738,17 @@ func init() {
 	mailCmd.AddCommand(mailMarkUnreadCmd)
 	mailCmd.AddCommand(mailCheckCmd)
 	mailCmd.AddCommand(mailThreadCmd)
+	mailCmd.AddCommand(mailThreadsCmd)
 	mailCmd.AddCommand(mailReplyCmd)
 	mailCmd.AddCommand(mailClaimCmd)
 	mailCmd.AddCommand(mailReleaseCmd)
 	mailCmd.AddCommand(mailClearCmd)
 	mailCmd.AddCommand(mailSearchCmd)
 	mailCmd.AddCommand(mailAnnouncesCmd)
+	mailCmd.AddCommand(mailDLQCmd)
+	mailCmd.AddCommand(mailSnoozeCmd)
+	mailCmd.AddCommand(mailScheduledCmd)
+	mailCmd.AddCommand(mailCompactCmd)
 
 	rootCmd.AddCommand(mailCmd)
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+func runMailCompact(cmd *cobra.Command, args []string) error {
+	if mailCompactArchiveAfter < 0 || mailCompactPurgeAfter < 0 {
+		return &usageError{err: errors.New("--archive-after and --purge-after must be non-negative")}
+	}
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var policy config.MailRetention
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return err
+	}
+	if err == nil && cfg.Retention != nil {
+		policy = *cfg.Retention
+	}
+	if cmd.Flags().Changed("archive-after") {
+		policy.ArchiveAfterDays = mailCompactArchiveAfter
+	}
+	if cmd.Flags().Changed("purge-after") {
+		policy.PurgeAfterDays = mailCompactPurgeAfter
+	}
+	if policy.ArchiveAfterDays == 0 && policy.PurgeAfterDays == 0 {
+		return &usageError{err: errors.New("no retention policy: set retention in config/messaging.json or pass --archive-after/--purge-after")}
+	}
+
+	res, err := mail.NewRouterWithTownRoot(townRoot, townRoot).Compact(policy, time.Now(), mailCompactDryRun)
+	if res == nil {
+		return err
+	}
+
+	if mailCompactJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(res); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+
+	verb := "Archived"
+	purged := "purged"
+	if res.DryRun {
+		verb = "Would archive"
+		purged = "would purge"
+	}
+	fmt.Printf("%s %s %d message(s), %s %d\n", style.Success.Render("✓"), verb, res.Archived, purged, res.Purged)
+	for _, f := range res.Files {
+		rel, relErr := filepath.Rel(townRoot, f)
+		if relErr != nil {
+			rel = f
+		}
+		fmt.Printf("  %s\n", style.Dim.Render(rel))
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+func runMailDLQList(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	letters, err := mail.NewRouter(workDir).DeadLetters()
+	if err != nil {
+		return fmt.Errorf("reading dead-letter queue: %w", err)
+	}
+
+	if mailDLQJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(letters)
+	}
+
+	if len(letters) == 0 {
+		fmt.Printf("%s No undeliverable mail\n", style.Success.Render("✓"))
+		return nil
+	}
+
+	fmt.Printf("%s %d undeliverable message(s)\n\n", style.Bold.Render("📭"), len(letters))
+	for _, dl := range letters {
+		fmt.Printf("  %s %s\n", style.Bold.Render(dl.ID), dl.Message.Subject)
+		fmt.Printf("    %s from %s to %s, %d attempts, last %s\n",
+			style.Dim.Render("↳"), dl.Message.From, dl.Message.To, dl.Attempts,
+			dl.FailedAt.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("    %s\n", style.Dim.Render(dl.Error))
+	}
+	return nil
+}
+
+func runMailDLQRetry(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !mailDLQRetryAll {
+		return fmt.Errorf("specify dead-letter IDs or --all")
+	}
+
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	ids := args
+	if mailDLQRetryAll {
+		letters, err := router.DeadLetters()
+		if err != nil {
+			return fmt.Errorf("reading dead-letter queue: %w", err)
+		}
+		ids = nil
+		for _, dl := range letters {
+			ids = append(ids, dl.ID)
+		}
+	}
+
+	failed := 0
+	for _, id := range ids {
+		if err := router.RetryDeadLetter(id); err != nil {
+			fmt.Printf("%s %v\n", style.Error.Render("✗"), err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Delivered %s\n", style.Success.Render("✓"), id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d message(s) still undeliverable", failed, len(ids))
+	}
+	return nil
+}
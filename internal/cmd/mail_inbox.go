@@ -92,9 +92,13 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
-		fmt.Printf("    %s from %s\n",
+		broadcastMarker := ""
+		if msg.Broadcast != "" {
+			broadcastMarker = " " + style.Dim.Render("via "+msg.Broadcast)
+		}
+		fmt.Printf("    %s from %s%s\n",
 			style.Dim.Render(msg.ID),
-			msg.From)
+			msg.From, broadcastMarker)
 		fmt.Printf("    %s\n",
 			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
 	}
@@ -147,9 +151,23 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s %s%s%s\n\n", style.Bold.Render("Subject:"), msg.Subject, typeStr, priorityStr)
 	fmt.Printf("From: %s\n", msg.From)
-	fmt.Printf("To: %s\n", msg.To)
+	if msg.Broadcast != "" {
+		fmt.Printf("To: %s %s\n", msg.To, style.Dim.Render("(broadcast to "+msg.Broadcast+")"))
+	} else {
+		fmt.Printf("To: %s\n", msg.To)
+	}
 	fmt.Printf("Date: %s\n", msg.Timestamp.Format("2006-01-02 15:04:05"))
 	fmt.Printf("ID: %s\n", style.Dim.Render(msg.ID))
+	if msg.Read && msg.ReadAt != nil {
+		readBy := msg.ReadBy
+		if readBy == "" {
+			readBy = "unknown"
+		}
+		fmt.Printf("Read: %s by %s\n", msg.ReadAt.Local().Format("2006-01-02 15:04:05"), style.Dim.Render(readBy))
+	}
+	if msg.RequestReceipt && !msg.Read {
+		fmt.Printf("Receipt: %s\n", style.Dim.Render("requested (sent on 'gt mail mark-read')"))
+	}
 
 	if msg.ThreadID != "" {
 		fmt.Printf("Thread: %s\n", style.Dim.Render(msg.ThreadID))
@@ -280,16 +298,18 @@ func runMailMarkRead(cmd *cobra.Command, args []string) error {
 	// Determine which inbox
 	address := detectSender()
 
-	mailbox, err := getMailbox(address)
+	workDir, err := findMailWorkDir()
 	if err != nil {
-		return err
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	// Marked through the router, which mails any requested receipts
+	router := mail.NewRouter(workDir)
 
 	// Mark all specified messages as read
 	marked := 0
 	var errors []string
 	for _, msgID := range args {
-		if err := mailbox.MarkReadOnly(msgID); err != nil {
+		if err := router.MarkRead(address, msgID); err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", msgID, err))
 		} else {
 			marked++
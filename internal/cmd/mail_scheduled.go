@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+func runMailSnooze(cmd *cobra.Command, args []string) error {
+	msgID := args[0]
+	until, err := mail.ParseWhen(args[1], time.Now())
+	if err != nil {
+		return &usageError{err: err}
+	}
+	if !until.After(time.Now()) {
+		return &usageError{err: fmt.Errorf("%q is in the past", args[1])}
+	}
+
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	address := detectSender()
+	if _, err := mail.NewRouter(workDir).Snooze(address, msgID, until); err != nil {
+		return fmt.Errorf("snoozing %s: %w", msgID, err)
+	}
+	fmt.Printf("%s Snoozed %s until %s\n", style.Success.Render("✓"), msgID, until.Local().Format("Mon 2006-01-02 15:04"))
+	return nil
+}
+
+func runMailScheduledList(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	all, err := mail.NewRouter(workDir).ScheduledMail()
+	if err != nil {
+		return fmt.Errorf("reading scheduled mail: %w", err)
+	}
+
+	if mailScheduledJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+
+	if len(all) == 0 {
+		fmt.Printf("%s No scheduled or snoozed mail\n", style.Success.Render("✓"))
+		return nil
+	}
+
+	fmt.Printf("%s %d scheduled message(s)\n\n", style.Bold.Render("⏰"), len(all))
+	for _, s := range all {
+		due := s.Due.Local().Format("Mon 2006-01-02 15:04")
+		fmt.Printf("  %s %s\n", style.Bold.Render(s.ID), s.Message.Subject)
+		if s.Kind == mail.ScheduleSnooze {
+			fmt.Printf("    %s %s snoozed in %s until %s\n", style.Dim.Render("↳"), s.Message.ID, s.Address, due)
+		} else {
+			fmt.Printf("    %s from %s to %s at %s\n", style.Dim.Render("↳"), s.Message.From, s.Message.To, due)
+		}
+		if s.Error != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(s.Error))
+		}
+	}
+	return nil
+}
+
+func runMailScheduledCancel(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	failed := 0
+	for _, id := range args {
+		if err := router.CancelScheduled(id); err != nil {
+			fmt.Printf("%s %v\n", style.Error.Render("✗"), err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Cancelled %s\n", style.Success.Render("✓"), id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cancellation(s) failed", failed, len(args))
+	}
+	return nil
+}
@@ -14,6 +14,32 @@ import (
 func runMailSearch(cmd *cobra.Command, args []string) error {
 	query := args[0]
 
+	// Build search options
+	opts := mail.SearchOptions{
+		Query:       query,
+		FromFilter:  mailSearchFrom,
+		SubjectOnly: mailSearchSubject,
+		BodyOnly:    mailSearchBody,
+		ThreadID:    mailSearchThread,
+		Type:        mail.MessageType(mailSearchType),
+	}
+	var err error
+	if mailSearchSince != "" {
+		if opts.Since, err = parseCutoff("--since", mailSearchSince); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	if mailSearchUntil != "" {
+		if opts.Until, err = parseCutoff("--until", mailSearchUntil); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	switch opts.Type {
+	case "", mail.TypeTask, mail.TypeScavenge, mail.TypeNotification, mail.TypeReply:
+	default:
+		return &usageError{err: fmt.Errorf("invalid --type %q (want task, scavenge, notification or reply)", mailSearchType)}
+	}
+
 	// Determine which inbox to search
 	address := detectSender()
 
@@ -30,14 +56,6 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting mailbox: %w", err)
 	}
 
-	// Build search options
-	opts := mail.SearchOptions{
-		Query:       query,
-		FromFilter:  mailSearchFrom,
-		SubjectOnly: mailSearchSubject,
-		BodyOnly:    mailSearchBody,
-	}
-
 	// Execute search
 	messages, err := mailbox.Search(opts)
 	if err != nil {
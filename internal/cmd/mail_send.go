@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -18,7 +19,38 @@ import (
 func runMailSend(cmd *cobra.Command, args []string) error {
 	var to string
 
-	if mailSendSelf {
+	if mailSendTo != "" && (mailSendSelf || len(args) > 0) {
+		return fmt.Errorf("--to cannot be combined with an address argument or --self")
+	}
+	if mailSubject == "" && mailSendTemplate == "" {
+		return &usageError{err: fmt.Errorf("--subject is required (or use --template)")}
+	}
+	if len(mailSendVars) > 0 && mailSendTemplate == "" {
+		return &usageError{err: fmt.Errorf("--var needs --template")}
+	}
+
+	// With --at the message is saved for the daemon to send when due
+	var due time.Time
+	if mailSendAt != "" {
+		var err error
+		if due, err = mail.ParseWhen(mailSendAt, time.Now()); err != nil {
+			return &usageError{err: fmt.Errorf("invalid --at: %w", err)}
+		}
+		if !due.After(time.Now()) {
+			return &usageError{err: fmt.Errorf("--at %q is in the past", mailSendAt)}
+		}
+	}
+	send := func(router *mail.Router, m *mail.Message) error {
+		if due.IsZero() {
+			return router.Send(m)
+		}
+		_, err := router.Schedule(m, due)
+		return err
+	}
+
+	if mailSendTo != "" {
+		to = mailSendTo
+	} else if mailSendSelf {
 		// Auto-detect identity from cwd
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -26,7 +58,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		}
 		townRoot, err := workspace.FindFromCwd()
 		if err != nil || townRoot == "" {
-			return fmt.Errorf("not in a Gas Town workspace")
+			return workspace.ErrNotFound
 		}
 		roleInfo, err := GetRoleWithContext(cwd, townRoot)
 		if err != nil {
@@ -46,7 +78,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	} else if len(args) > 0 {
 		to = args[0]
 	} else {
-		return fmt.Errorf("address required (or use --self)")
+		return fmt.Errorf("address required (or use --to or --self)")
 	}
 
 	// All mail uses town beads (two-level architecture)
@@ -72,13 +104,21 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	} else {
 		msg.Priority = mail.PriorityFromInt(mailPriority)
 	}
-	if mailNotify && msg.Priority == mail.PriorityNormal {
-		msg.Priority = mail.PriorityHigh
-	}
 
 	// Set message type
 	msg.Type = mail.ParseMessageType(mailType)
 
+	// A template supplies the subject and body, and its priority and type
+	// unless given on the command line
+	if mailSendTemplate != "" {
+		if err := applyMailTemplate(cmd, msg); err != nil {
+			return err
+		}
+	}
+	if mailNotify && msg.Priority == mail.PriorityNormal {
+		msg.Priority = mail.PriorityHigh
+	}
+
 	// Set pinned flag
 	msg.Pinned = mailPinned
 
@@ -88,6 +128,9 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Ask for an acknowledgment when read
+	msg.RequestReceipt = mailReceipt
+
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
 		msg.ReplyTo = mailReplyTo
@@ -105,6 +148,15 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Join a recent conversation with the same subject and participants,
+	// so forgetting --reply-to doesn't fragment the thread
+	if msg.ThreadID == "" && !mailNewThread {
+		router := mail.NewRouter(workDir)
+		if threadID, _ := router.DetectThread(msg); threadID != "" {
+			msg.ThreadID = threadID
+		}
+	}
+
 	// Generate thread ID for new threads
 	if msg.ThreadID == "" {
 		msg.ThreadID = generateThreadID()
@@ -119,12 +171,10 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		// Fall back to legacy routing if resolver fails
 		router := mail.NewRouter(workDir)
-		if err := router.Send(msg); err != nil {
+		if err := send(router, msg); err != nil {
 			return fmt.Errorf("sending message: %w", err)
 		}
-		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
-		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
-		fmt.Printf("  Subject: %s\n", mailSubject)
+		printMailSent(from, to, msg.Subject, due)
 		return nil
 	}
 
@@ -137,7 +187,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		case mail.RecipientQueue:
 			// Queue messages: single message, workers claim
 			msg.To = rec.Address
-			if err := router.Send(msg); err != nil {
+			if err := send(router, msg); err != nil {
 				return fmt.Errorf("sending to queue: %w", err)
 			}
 			recipientAddrs = append(recipientAddrs, rec.Address)
@@ -145,7 +195,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		case mail.RecipientChannel:
 			// Channel messages: single message, broadcast
 			msg.To = rec.Address
-			if err := router.Send(msg); err != nil {
+			if err := send(router, msg); err != nil {
 				return fmt.Errorf("sending to channel: %w", err)
 			}
 			recipientAddrs = append(recipientAddrs, rec.Address)
@@ -154,18 +204,17 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 			// Direct/agent messages: fan out to each recipient
 			msgCopy := *msg
 			msgCopy.To = rec.Address
-			if err := router.Send(&msgCopy); err != nil {
+			if len(recipients) > 1 {
+				msgCopy.Broadcast = to
+			}
+			if err := send(router, &msgCopy); err != nil {
 				return fmt.Errorf("sending to %s: %w", rec.Address, err)
 			}
 			recipientAddrs = append(recipientAddrs, rec.Address)
 		}
 	}
 
-	// Log mail event to activity feed
-	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
-
-	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
-	fmt.Printf("  Subject: %s\n", mailSubject)
+	printMailSent(from, to, msg.Subject, due)
 
 	// Show resolved recipients if fan-out occurred
 	if len(recipientAddrs) > 1 || (len(recipientAddrs) == 1 && recipientAddrs[0] != to) {
@@ -182,6 +231,18 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printMailSent logs a sent message to the activity feed and reports it, or
+// reports when it will be sent if it was scheduled for due.
+func printMailSent(from, to, subject string, due time.Time) {
+	if due.IsZero() {
+		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, subject))
+		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
+	} else {
+		fmt.Printf("%s Message to %s scheduled for %s\n", style.Bold.Render("✓"), to, due.Local().Format("Mon 2006-01-02 15:04"))
+	}
+	fmt.Printf("  Subject: %s\n", subject)
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
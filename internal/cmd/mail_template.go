@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// mailTemplateVars returns the variables a mail template is rendered with:
+// from and to, -s and -m as subject and body if given, then each --var
+// key=value, which may override them.
+func mailTemplateVars(from, to, subject, body string, pairs []string) (map[string]string, error) {
+	vars := map[string]string{"from": from, "to": to}
+	if subject != "" {
+		vars["subject"] = subject
+	}
+	if body != "" {
+		vars["body"] = body
+	}
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected key=value", pair)
+		}
+		vars[strings.TrimSpace(k)] = v
+	}
+	return vars, nil
+}
+
+// mailTemplatePriority converts a template's front-matter priority to a
+// mail priority. Empty means normal.
+func mailTemplatePriority(p string) mail.Priority {
+	if n, err := strconv.Atoi(p); err == nil {
+		return mail.PriorityFromInt(n)
+	}
+	if p == "backlog" {
+		return mail.PriorityLow
+	}
+	return mail.ParsePriority(p)
+}
+
+// applyMailTemplate renders --template into msg. The template's priority
+// and type apply unless --priority, --urgent or --type were given.
+func applyMailTemplate(cmd *cobra.Command, msg *mail.Message) error {
+	townRoot, _ := workspace.FindFromCwd()
+	tmpl, err := templates.LoadMailTemplate(townRoot, mailSendTemplate)
+	if err != nil {
+		return err
+	}
+	vars, err := mailTemplateVars(msg.From, msg.To, mailSubject, mailBody, mailSendVars)
+	if err != nil {
+		return &usageError{err: err}
+	}
+	rendered, err := tmpl.Render(vars)
+	if err != nil {
+		return fmt.Errorf("%w (set template variables with --var key=value)", err)
+	}
+
+	msg.Subject = rendered.Subject
+	msg.Body = rendered.Body
+	if rendered.Priority != "" && !cmd.Flags().Changed("priority") && !mailUrgent {
+		msg.Priority = mailTemplatePriority(rendered.Priority)
+	}
+	if rendered.Type != "" && !cmd.Flags().Changed("type") {
+		msg.Type = mail.ParseMessageType(rendered.Type)
+	}
+	return nil
+}
+
+func runMailTemplates(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+
+	if len(args) == 1 {
+		tmpl, err := templates.LoadMailTemplate(townRoot, args[0])
+		if err != nil {
+			return err
+		}
+		if mailTemplatesJSON {
+			return printMailTemplatesJSON(tmpl)
+		}
+		fmt.Printf("%s\n", style.Bold.Render(tmpl.Name))
+		fmt.Printf("  Subject:  %s\n", tmpl.Subject)
+		fmt.Printf("  Priority: %s\n", valueOr(tmpl.Priority, "normal"))
+		fmt.Printf("  Type:     %s\n", valueOr(tmpl.Type, "notification"))
+		keys := make([]string, 0, len(tmpl.Vars))
+		for k := range tmpl.Vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("  Var:      %s (default %q)\n", k, tmpl.Vars[k])
+		}
+		fmt.Printf("\n%s\n", tmpl.Body)
+		return nil
+	}
+
+	names, err := templates.MailTemplateNames(townRoot)
+	if err != nil {
+		return err
+	}
+	var list []*templates.MailTemplate
+	for _, name := range names {
+		tmpl, err := templates.LoadMailTemplate(townRoot, name)
+		if err != nil {
+			// Show the rest; a broken town template shouldn't hide them
+			fmt.Fprintf(os.Stderr, "%s %v\n", style.Warning.Render("⚠"), err)
+			continue
+		}
+		list = append(list, tmpl)
+	}
+	if mailTemplatesJSON {
+		return printMailTemplatesJSON(list)
+	}
+	if len(list) == 0 {
+		fmt.Printf("%s No mail templates\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, tmpl := range list {
+		fmt.Printf("  %-16s %s\n", tmpl.Name, style.Dim.Render(tmpl.Subject))
+	}
+	return nil
+}
+
+func printMailTemplatesJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
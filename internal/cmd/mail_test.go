@@ -7,6 +7,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 // TestClaimPatternMatching tests claim pattern matching via the beads package.
@@ -296,3 +297,44 @@ func TestAnnounceMessageParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestMailTemplateVars(t *testing.T) {
+	vars, err := mailTemplateVars("gastown/refinery", "gastown/Toast", "", "see log", []string{"worker=Toast", "bead=gt-42", "reason=a=b"})
+	if err != nil {
+		t.Fatalf("mailTemplateVars: %v", err)
+	}
+	want := map[string]string{
+		"from": "gastown/refinery", "to": "gastown/Toast", "body": "see log",
+		"worker": "Toast", "bead": "gt-42", "reason": "a=b",
+	}
+	if fmt.Sprint(vars) != fmt.Sprint(want) {
+		t.Errorf("vars = %v, want %v", vars, want)
+	}
+	if _, ok := vars["subject"]; ok {
+		t.Error("empty -s should not set subject")
+	}
+
+	for _, bad := range []string{"worker", "=Toast"} {
+		if _, err := mailTemplateVars("a", "b", "", "", []string{bad}); err == nil {
+			t.Errorf("--var %q: expected error", bad)
+		}
+	}
+}
+
+func TestMailTemplatePriority(t *testing.T) {
+	tests := map[string]mail.Priority{
+		"":        mail.PriorityNormal,
+		"urgent":  mail.PriorityUrgent,
+		"high":    mail.PriorityHigh,
+		"low":     mail.PriorityLow,
+		"backlog": mail.PriorityLow,
+		"0":       mail.PriorityUrgent,
+		"1":       mail.PriorityHigh,
+		"4":       mail.PriorityLow,
+	}
+	for in, want := range tests {
+		if got := mailTemplatePriority(in); got != want {
+			t.Errorf("mailTemplatePriority(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -79,6 +79,54 @@ func runMailThread(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runMailThreads(cmd *cobra.Command, args []string) error {
+	// All mail uses town beads (two-level architecture)
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	address := detectSender()
+	router := mail.NewRouter(workDir)
+	mailbox, err := router.GetMailbox(address)
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+
+	threads, err := mailbox.Threads()
+	if err != nil {
+		return fmt.Errorf("listing threads: %w", err)
+	}
+
+	if mailThreadsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(threads)
+	}
+
+	fmt.Printf("%s Threads for %s (%d)\n\n", style.Bold.Render("🧵"), address, len(threads))
+	if len(threads) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no conversations)"))
+		return nil
+	}
+
+	for _, th := range threads {
+		marker := "○"
+		if th.Unread > 0 {
+			marker = style.Bold.Render("●")
+		}
+		fmt.Printf("  %s %s\n", marker, th.Subject)
+		counts := fmt.Sprintf("%d message(s)", len(th.Messages))
+		if th.Unread > 0 {
+			counts += fmt.Sprintf(", %d unread", th.Unread)
+		}
+		fmt.Printf("    %s %s · %s\n", style.Dim.Render(th.ID), counts, strings.Join(th.Participants, ", "))
+		fmt.Printf("    %s\n", style.Dim.Render(th.LastActivity.Format("2006-01-02 15:04")))
+	}
+
+	return nil
+}
+
 func runMailReply(cmd *cobra.Command, args []string) error {
 	msgID := args[0]
 
@@ -103,43 +151,18 @@ func runMailReply(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting message: %w", err)
 	}
 
-	// Build reply subject
-	subject := mailReplySubject
-	if subject == "" {
-		if strings.HasPrefix(original.Subject, "Re: ") {
-			subject = original.Subject
-		} else {
-			subject = "Re: " + original.Subject
-		}
-	}
-
-	// Create reply message
 	reply := &mail.Message{
-		From:     from,
-		To:       original.From, // Reply to sender
-		Subject:  subject,
-		Body:     mailReplyMessage,
-		Type:     mail.TypeReply,
-		Priority: mail.PriorityNormal,
-		ReplyTo:  msgID,
-		ThreadID: original.ThreadID,
+		From:    from,
+		Subject: mailReplySubject,
+		Body:    mailReplyMessage,
 	}
-
-	// If original has no thread ID, create one
-	if reply.ThreadID == "" {
-		reply.ThreadID = generateThreadID()
-	}
-
-	// Send the reply
-	if err := router.Send(reply); err != nil {
+	if err := router.Reply(original, reply); err != nil {
 		return fmt.Errorf("sending reply: %w", err)
 	}
 
 	fmt.Printf("%s Reply sent to %s\n", style.Bold.Render("✓"), original.From)
-	fmt.Printf("  Subject: %s\n", subject)
-	if original.ThreadID != "" {
-		fmt.Printf("  Thread: %s\n", style.Dim.Render(original.ThreadID))
-	}
+	fmt.Printf("  Subject: %s\n", reply.Subject)
+	fmt.Printf("  Thread: %s\n", style.Dim.Render(reply.ThreadID))
 
 	return nil
 }
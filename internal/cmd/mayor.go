@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/mayor"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -143,6 +144,11 @@ func runMayorStop(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Keep an on-failure restart policy from bringing it back
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		_ = daemon.MarkStopped(townRoot, "mayor")
+	}
+
 	fmt.Printf("%s Mayor session stopped.\n", style.Bold.Render("✓"))
 	return nil
 }
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var metricsAddr string
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: GroupDiag,
+	Short:   "Export town health metrics",
+	RunE:    requireSubcommand,
+}
+
+var metricsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve Prometheus metrics for the town",
+	Long: `Start an HTTP server exposing town health on /metrics in the
+Prometheus text format.
+
+Metrics are computed on every scrape:
+  gt_events_total{type}             Events in the events log, by type
+  gt_mail_sent_total                Mail messages sent
+  gt_merges_total{result}           Merges succeeded/failed
+  gt_sessions_running               Gas Town tmux sessions running
+  gt_keepalive_age_seconds{agent}   Seconds since each agent last ran gt
+  gt_rig_health_score{rig}          Rig health score (0-100)
+
+Examples:
+  gt metrics serve                  # Listen on :9464
+  gt metrics serve --addr :9100`,
+	Args: cobra.NoArgs,
+	RunE: runMetricsServe,
+}
+
+func init() {
+	metricsServeCmd.Flags().StringVar(&metricsAddr, "addr", ":9464", "Address to listen on")
+	metricsCmd.AddCommand(metricsServeCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetricsServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	collector := &metrics.Collector{TownRoot: townRoot, Sessions: tmux.NewTmux()}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", collector.Handler())
+
+	fmt.Printf("📈 Serving metrics at http://%s/metrics\n", metricsAddr)
+	fmt.Printf("   Press Ctrl+C to stop\n")
+
+	server := &http.Server{
+		Addr:              metricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	return server.ListenAndServe()
+}
@@ -66,7 +66,7 @@ func detectAgentBeadID() (string, error) {
 		return "", fmt.Errorf("finding workspace: %w", err)
 	}
 	if townRoot == "" {
-		return "", fmt.Errorf("not in a Gas Town workspace")
+		return "", workspace.ErrNotFound
 	}
 
 	roleInfo, err := GetRoleWithContext(cwd, townRoot)
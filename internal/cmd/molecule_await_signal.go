@@ -10,7 +10,10 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/backoff"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/keepalive"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -141,6 +144,12 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	// Waiting can take far longer than the daemon's idle threshold, so keep
+	// the agent's keepalive fresh while blocked.
+	if cwd, err := os.Getwd(); err == nil {
+		keepalive.StartHeartbeat(ctx, cwd, keepalive.DefaultHeartbeatInterval)
+	}
+
 	result, err := waitForActivitySignal(ctx, workDir)
 	if err != nil {
 		return fmt.Errorf("feed subscription failed: %w", err)
@@ -205,29 +214,15 @@ func runMoleculeAwaitSignal(cmd *cobra.Command, args []string) error {
 func calculateEffectiveTimeout(idleCycles int) (time.Duration, error) {
 	// If backoff base is set, use backoff mode
 	if awaitSignalBackoffBase != "" {
-		base, err := time.ParseDuration(awaitSignalBackoffBase)
+		policy, err := backoff.FromConfig(&config.BackoffConfig{
+			Base:       awaitSignalBackoffBase,
+			Max:        awaitSignalBackoffMax,
+			Multiplier: float64(awaitSignalBackoffMult),
+		}, 0)
 		if err != nil {
-			return 0, fmt.Errorf("invalid backoff-base: %w", err)
+			return 0, err
 		}
-
-		// Apply exponential backoff: base * multiplier^idleCycles
-		timeout := base
-		for i := 0; i < idleCycles; i++ {
-			timeout *= time.Duration(awaitSignalBackoffMult)
-		}
-
-		// Apply max cap if specified
-		if awaitSignalBackoffMax != "" {
-			maxDur, err := time.ParseDuration(awaitSignalBackoffMax)
-			if err != nil {
-				return 0, fmt.Errorf("invalid backoff-max: %w", err)
-			}
-			if timeout > maxDur {
-				timeout = maxDur
-			}
-		}
-
-		return timeout, nil
+		return policy.Interval(idleCycles), nil
 	}
 
 	// Simple timeout mode
@@ -108,6 +108,8 @@ func runNamepool(cmd *cobra.Command, args []string) error {
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
 	if settings, err := config.LoadRigSettings(settingsPath); err == nil && settings.Namepool != nil {
 		fmt.Printf("(configured in settings/config.json)\n")
+	} else if np := polecat.NamepoolConfigFor(rigPath); np != nil {
+		fmt.Printf("(configured town-wide in settings/config.json)\n")
 	}
 
 	return nil
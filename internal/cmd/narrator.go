@@ -0,0 +1,1912 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/narrator"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	personaName     string
+	personaPronouns string
+	personaTraits   []string
+	personaGags     []string
+	personaJSON     bool
+
+	narratorAgentOverride string
+	narratorName          string
+
+	narratorPauseReason string
+	narratorResumeSkip  bool
+	narratorResumeSumm  bool
+
+	narratorGenSince        string
+	narratorGenUntil        string
+	narratorGenStyle        string
+	narratorGenRig          string
+	narratorGenSignificance string
+	narratorGenAgent        string
+	narratorGenOutput       string
+	narratorGenDryRun       bool
+	narratorGenStrict       bool
+
+	narratorReplayFrom         string
+	narratorReplaySince        string
+	narratorReplayUntil        string
+	narratorReplayStyle        string
+	narratorReplayRig          string
+	narratorReplaySignificance string
+	narratorReplayAgent        string
+	narratorReplayDryRun       bool
+
+	narratorLintJSON   bool
+	narratorStylesJSON bool
+
+	narratorPublishOut   string
+	narratorPublishForce bool
+	narratorPublishJSON  bool
+
+	narratorConfigJSON bool
+
+	narratorDigestSince string
+	narratorDigestUntil string
+	narratorDigestRig   string
+	narratorDigestJSON  bool
+
+	narratorSearchLimit int
+	narratorSearchJSON  bool
+
+	narratorSummStyle  string
+	narratorSummAgent  string
+	narratorSummOutput string
+	narratorSummDryRun bool
+	narratorSummJSON   bool
+
+	narratorRejectReason string
+	narratorPendingJSON  bool
+
+	narratorCommentarySignificance string
+	narratorCommentaryPane         bool
+	narratorCommentaryTarget       string
+	narratorCommentaryJSON         bool
+
+	narratorServeAddr string
+
+	narratorProloRigs       []string
+	narratorProloSince      string
+	narratorProloMaxCommits int
+	narratorProloStyle      string
+	narratorProloAgent      string
+	narratorProloOutput     string
+	narratorProloDryRun     bool
+	narratorProloJSON       bool
+)
+
+var narratorCmd = &cobra.Command{
+	Use:     "narrator",
+	GroupID: GroupDiag,
+	Short:   "Manage the narrator's town history settings",
+	RunE:    requireSubcommand,
+	Long: `Manage what the narrator knows when turning events into history.
+
+Narrator state lives in the narrator/ directory of the town root.`,
+}
+
+var narratorStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the narrator's session",
+	Args:  cobra.NoArgs,
+	Long: `Start the narrator's agent session (hq-narrator), running in narrator/.
+
+The session is brought up by the "narrator" startup steps (see the agent's
+runtime config), and is told to check the backlog and begin narrating. A
+session whose agent has died is replaced. A paused narrator isn't started;
+resume it first.
+
+With --name, a named narrator is started alongside the town's, in its own
+session (hq-narrator-<name>) with its own run state in
+narrator/narrator-<name>.json: one per rig, say, or one writing a
+changelog. Its settings are its entry in narrator.instances in
+settings/config.json (rig, style, agent, output_dir); a name without one
+runs with the defaults. Its session has GT_NARRATOR set to its name, so
+the narrator commands run in it (generate, pause, resume, status) act for
+it without --name.
+
+Examples:
+  gt narrator start
+  gt narrator start --agent codex
+  gt narrator start --name changelog`,
+	RunE: runNarratorStart,
+}
+
+var narratorStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the narrator's session",
+	Args:  cobra.NoArgs,
+	Long: `Stop the narrator's agent session, or with --name a named narrator's.
+
+Examples:
+  gt narrator stop
+  gt narrator stop --name changelog`,
+	RunE: runNarratorStop,
+}
+
+var narratorPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Stop narrating town history until resumed",
+	Args:  cobra.NoArgs,
+	Long: `Pause the narrator. No narration is generated while it is paused; events
+keep being logged and accumulate as a backlog, which 'gt narrator status'
+reports.
+
+A named narrator (--name) is paused on its own; the town's narrator and
+the other instances carry on.
+
+Examples:
+  gt narrator pause
+  gt narrator pause --reason "load test, nothing worth telling"
+  gt narrator pause --name changelog`,
+	RunE: runNarratorPause,
+}
+
+var narratorResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume narrating, catching up on or skipping the backlog",
+	Args:  cobra.NoArgs,
+	Long: `Resume the narrator and deal with the events logged while it was paused.
+
+By default the backlog is written up as a catch-up chapter in narrator/
+(counts by event type and the high-significance highlights). With --skip it
+is left out of the narrative. The default comes from narrator.on_resume in
+settings/config.json ("summarize" or "skip").
+
+A named narrator (--name) always skips its backlog: catch-up chapters are
+the town's narrator's to write.
+
+Examples:
+  gt narrator resume
+  gt narrator resume --skip
+  gt narrator resume --name changelog`,
+	RunE: runNarratorResume,
+}
+
+var narratorGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Narrate a stretch of events in one shot, without a session",
+	Args:  cobra.NoArgs,
+	Long: `Generate a chapter of town history from the CLI, without starting the
+narrator's session.
+
+Events in the time range (optionally one rig's) are scored for significance,
+with bursts counted as incidents and repetitive routine events demoted, and
+those at or above --significance are narrated. The prompt combines the style
+from narrator/styles/ (see 'gt narrator styles'), the personas of the actors
+involved, the glossary in narrator/glossary.json, the story so far, and the
+events, each with any notes attached by 'gt events annotate'. It is handed to the narrating agent non-interactively and the reply
+is written to narrator/chapter-<time>.md (or --output), with the extension
+of the style's format.
+
+The story so far lives in narrator/story.json: the chapter number, a recap
+of the last chapter, open plot threads, and conflicts recurring in each rig.
+The agent ends its reply with a story block saying which threads the
+chapter opened, continued and resolved; it is stripped from the chapter and
+folded into the story, so the next chapter can pick up where this one left
+off.
+
+The reply is checked against the glossary and persona names first (see
+'gt narrator lint'). Issues are reported; with --strict they stop the
+chapter from being written.
+
+The agent is role_agents.narrator in settings/config.json, else the town's
+default agent; --agent overrides both. Use --dry-run to see the prompt and
+command without running the agent.
+
+With --name (or $GT_NARRATOR, in a named narrator's session) the chapter is
+a named narrator's: its entry in narrator.instances supplies the rig, style
+and agent the flags leave unset, and the chapter is written to its
+output_dir, narrator/<name>/ by default. It isn't written while that
+narrator is paused; the town's narrator's pause doesn't stop it.
+
+With narrator.git_output in settings/config.json, each chapter (and each
+catch-up chapter from 'gt narrator resume') is also committed to a branch
+of the town repo ("narrative" unless git_output.branch says otherwise),
+with the chapter number, style, agent, time range and event count in the
+commit message. The working tree and current branch are left alone.
+
+With narrator.illustrations set, each significant scene of the chapter
+(events at or above illustrations.significance, "high" by default) gets an
+image-generation prompt in illustrations/ beside the chapter. If
+illustrations.command is set it is run with sh -c once per scene, with the
+prompt on stdin and in $GT_ILLUSTRATION_PROMPT, to render the image to
+$GT_ILLUSTRATION_IMAGE.
+
+With narrator.styles set ('gt narrator config set styles book,short'), the
+same events are also written up in each of those styles in the same pass:
+an edition of the chapter per style, in narrator/<style>/ or the style's
+own output_dir. The chapter in --style is the chapter of record that the
+story, index, narrative branch and site follow; each edition is an agent
+run counted against narrator.quota.
+
+Each chapter is listed in narrator/chapters.jsonl for 'gt narrator
+publish', and with narrator.publish.auto set the site is rebuilt.
+
+Every agent run is added to the usage ledger in narrator/usage.json, with
+its tokens estimated from the prompt and reply. With narrator.quota set
+(max_per_hour, max_per_day, max_tokens_per_day), a chapter over quota is
+handled as quota.over_quota says: "queue" (the default) holds its events
+back for the next town-wide chapter, "drop-low-significance" narrates only
+its high-significance events if that smaller chapter fits every limit (so
+it only helps with max_tokens_per_day), and "summarize"
+writes narrator/summary-<time>.md, counting its events without the agent.
+'gt narrator status' shows usage against the quota.
+
+Examples:
+  gt narrator generate
+  gt narrator generate --since 24h --style tv-script --rig gastown
+  gt narrator generate --since 7d --significance high -o weekly.md
+  gt narrator generate --since 2h --dry-run`,
+	RunE: runNarratorGenerate,
+}
+
+var narratorReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Narrate a saved events file without touching the town",
+	Args:  cobra.NoArgs,
+	Long: `Run the narration pipeline against an arbitrary events file, for tuning
+significance rules (settings/significance.json), the redaction policy and
+styles.
+
+Events in --from are scored, filtered and rendered into a prompt exactly as
+'gt narrator generate' does, using this town's rules, styles, personas,
+glossary and story so far. With --dry-run the prompt and agent command are
+printed. Without it the agent is run and the chapter printed.
+
+Nothing is written: no chapter, no story update, no events index. The file
+can be an exported or archived log, or another town's .events.jsonl. All of
+its events are replayed unless --since/--until narrow them.
+
+Examples:
+  gt narrator replay --from incident.jsonl --dry-run
+  gt narrator replay --from ~/gt/.events.jsonl --since 2026-03-01 --style tv-script
+  gt narrator replay --from incident.jsonl --significance high --rig gastown`,
+	RunE: runNarratorReplay,
+}
+
+var narratorLintCmd = &cobra.Command{
+	Use:   "lint <file>",
+	Short: "Check a narrative against the glossary and persona names",
+	Args:  cobra.ExactArgs(1),
+	Long: `Check a narrative for terminology problems, as 'gt narrator generate' does
+before writing a chapter:
+
+  variant       a misspelling listed in narrator/glossary.json
+  spelling      a term written another way ("GasTown" for "Gas Town")
+  banned        a word the glossary bans
+  inconsistent  a name spelled two ways, or one letter off a persona name
+
+Inline code and fenced code blocks are not checked. Exits non-zero when
+issues are found.
+
+Examples:
+  gt narrator lint narrator/chapter-20260301-120000.md
+  gt narrator lint draft.md --json`,
+	RunE: runNarratorLint,
+}
+
+var narratorStylesCmd = &cobra.Command{
+	Use:   "styles",
+	Short: "List the narration styles in narrator/styles/",
+	Args:  cobra.NoArgs,
+	Long: `List the styles 'gt narrator generate --style' can use.
+
+A style is a file in narrator/styles/. <name>.md is a plain template: its
+text is the style guide, and chapters are Markdown. <name>.yaml is a style
+definition, which can also set the chapter format, how events are chunked
+and how long a chapter should be:
+
+  description: Two hosts talk through the day in the town
+  format: text            # markdown (default), text, html or fountain
+  prompt: |
+    Write chapter {{.Chapter}} as a podcast transcript between two hosts.
+    {{if .Rig}}The episode is about the {{.Rig}} rig.{{end}}
+  chunking:
+    max_events: 150       # keep the newest events, up to this many
+    group_by: day         # list events under a heading per rig, day or actor
+  length:
+    min_words: 800
+    max_words: 1500
+
+The prompt is a Go template over .Chapter, .Rig, .Since, .Until and
+.Events (the number of events). Length targets are passed to the agent, and
+a chapter that misses them is reported. When both files exist for a name,
+the definition is used.
+
+Examples:
+  gt narrator styles
+  gt narrator styles --json`,
+	RunE: runNarratorStyles,
+}
+
+var narratorPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Build the chapters into a static site",
+	Args:  cobra.NoArgs,
+	Long: `Build every chapter written so far into a static HTML site.
+
+The site has an index of the latest chapters, a page per chapter (with its
+illustrations), a page per rig listing the chapters about it, a
+chronological archive by month, a search page that works opened straight
+from disk, and an Atom feed (feed.xml) of the latest chapters for feed
+readers. Chapters are listed in narrator/chapters.jsonl as they
+are written; chapter and catch-up files in narrator/ from before the index
+was kept are included too.
+
+Publishing is incremental: only the pages of new or changed chapters (and
+their neighbours, whose links change) are rendered again, and only files
+whose content changed are written. --force renders every page.
+
+The site goes to --out, else narrator.publish.dir in settings/config.json,
+else narrator/site. The pages are Go html/templates; a file in
+narrator/site-templates/ replaces the built-in one of the same name
+(layout.html, index.html, chapter.html, rig.html, archive.html,
+search.html, style.css).
+
+The feed's title and author default to the town's name; set them with
+narrator.publish.feed.title and .author. Set narrator.publish.feed.url to
+where the site is served to make the feed's links absolute.
+
+With narrator.publish.auto set, the site is also rebuilt after each
+chapter 'gt narrator generate' writes and each catch-up chapter.
+
+Examples:
+  gt narrator publish
+  gt narrator publish --out ./site
+  gt narrator publish --force --json`,
+	RunE: runNarratorPublish,
+}
+
+var narratorDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Write up routine events as a digest per rig",
+	Args:  cobra.NoArgs,
+	Long: `Write the low- and medium-significance events of a stretch up as one
+digest per rig, without running the agent: counts by event type and the
+medium-significance events listed. Events that belong to no rig go in the
+"town" digest. Digests are written to narrator/digest-<rig>-<time>.md (or
+narrator.output_format's extension), and are committed and published like
+chapters.
+
+With narrator.digest set in settings/config.json, the narrator runs in
+digest mode: 'gt narrator generate' narrates only high-significance events,
+and a town-wide chapter also writes the digests of the rest once
+narrator.digest.interval (default 24h) has passed since the last ones.
+This command writes them on demand; without --since it starts where the
+last digests stopped.
+
+Examples:
+  gt narrator digest
+  gt narrator digest --since yesterday
+  gt narrator digest --since 7d --rig gastown --json`,
+	RunE: runNarratorDigest,
+}
+
+var narratorSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search past chapters",
+	Args:  cobra.MinimumNArgs(1),
+	Long: `Search the town's past chapters, catch-ups and digests, and the
+storylines the narrator recorded with them, for the chapters a query
+recalls, best match first.
+
+Chapters are matched on their words, weighted by how rare each is across
+the town's history, so a query names what happened rather than matching
+a phrase. The same search reminds the narrator of earlier chapters when
+'gt narrator generate' writes a new one. The index is kept in
+narrator/recall.json and is brought up to date with the chapters first.
+
+Examples:
+  gt narrator search "the time the refinery broke"
+  gt narrator search convoy deadlock --limit 3
+  gt narrator search merge queue --json`,
+	RunE: runNarratorSearch,
+}
+
+var narratorSummarizeCmd = &cobra.Command{
+	Use:   "summarize <bead>",
+	Short: "Tell the story of one work item",
+	Args:  cobra.ExactArgs(1),
+	Long: `Tell the story of one work item from start to finish: every event the
+town's logs tie to the bead (its sling, hook, done, merges and whatever
+else shares its work ID), the mail events that name it, and the mail
+threads about it in the mailboxes of those involved.
+
+The story is written to stand alone, for a pull request description or a
+retrospective, to narrator/work-<bead>-<time>.md (or narrator.output_format's
+extension). It isn't a chapter of the town's history: the story so far,
+chapter index and site are left as they are.
+
+Examples:
+  gt narrator summarize gt-abc12
+  gt narrator summarize gt-abc12 --style terse -o PR.md
+  gt narrator summarize gt-abc12 --dry-run`,
+	RunE: runNarratorSummarize,
+}
+
+var narratorPrologueCmd = &cobra.Command{
+	Use:   "prologue",
+	Short: "Begin a new town's story with a prologue from its git history",
+	Args:  cobra.NoArgs,
+	Long: `Write the town's first chapter from its rigs' git history, so a town with
+little or no event history gets a chronicle from day one.
+
+The git log of each rig (its shared .repo.git, else mayor/rig) is mined for
+commits, merges and tags: who wrote what and when, the milestones, and the
+storylines running through it (each branch merged, and each Conventional
+Commits scope seen more than once). The narrating agent tells it as the
+legend the town's story grows out of. The storylines it sets up, or failing
+that those found in the history, become the story's open threads, so the
+chapters that follow carry them on.
+
+The prologue is chapter 1: it is only written before any other chapter,
+and is indexed, committed and published like one. At most --max-commits of
+each rig's newest commits are told.
+
+Examples:
+  gt narrator prologue
+  gt narrator prologue --rig gastown --since 2025-06-01
+  gt narrator prologue --style epic --dry-run`,
+	RunE: runNarratorPrologue,
+}
+
+var narratorPendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List chapters awaiting review",
+	Args:  cobra.NoArgs,
+	Long: `List the chapters waiting in the approval queue.
+
+With narrator.review set ('gt narrator config set review on'), each
+chapter, catch-up and digest is held back from the site and feed when it
+is written, and the human (narrator.review.notify, default the overseer)
+is mailed about it. Approve it with 'gt narrator approve <id>' to publish
+it, or withhold it for good with 'gt narrator reject <id>'.
+
+Examples:
+  gt narrator pending
+  gt narrator pending --json`,
+	RunE: runNarratorPending,
+}
+
+var narratorApproveCmd = &cobra.Command{
+	Use:   "approve <id>",
+	Short: "Publish a chapter awaiting review",
+	Args:  cobra.ExactArgs(1),
+	Long: `Approve a chapter in the approval queue, so it is published to the site
+and feed. With narrator.publish.auto set the site is rebuilt at once;
+otherwise the chapter appears at the next 'gt narrator publish'.
+
+The ID is the chapter's file name without its extension, as
+'gt narrator pending' lists it, or its path.
+
+Examples:
+  gt narrator approve chapter-20260301-120000`,
+	RunE: runNarratorApprove,
+}
+
+var narratorRejectCmd = &cobra.Command{
+	Use:   "reject <id>",
+	Short: "Withhold a chapter awaiting review",
+	Args:  cobra.ExactArgs(1),
+	Long: `Reject a chapter in the approval queue, so it is never published. The
+chapter file is kept; regenerate the range to write a new one for review.
+
+Examples:
+  gt narrator reject chapter-20260301-120000 --reason "names a customer"`,
+	RunE: runNarratorReject,
+}
+
+var narratorCommentaryCmd = &cobra.Command{
+	Use:   "commentary",
+	Short: "Stream live one-line commentary on the town's events",
+	Args:  cobra.NoArgs,
+	Long: `Stream a line of sportscaster commentary for each event as it happens,
+a ticker over the town's activity: what happened, with the actors' persona
+names, called louder the more significant it is. Bursts the significance
+rules make incidents get a line of their own. Runs until interrupted.
+
+Unlike chapters, commentary runs no agent, keeps no checkpoint and writes
+nothing: it is for watching, not for the record.
+
+With --pane the commentary opens in a pane below another tmux session
+instead: the narrator's session by default, or --target (e.g. a dashboard
+session you keep open).
+
+Examples:
+  gt narrator commentary
+  gt narrator commentary --significance high
+  gt narrator commentary --pane
+  gt narrator commentary --pane --target dashboard`,
+	RunE: runNarratorCommentary,
+}
+
+var narratorServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve an HTTP endpoint that narrates on demand",
+	Args:  cobra.NoArgs,
+	Long: `Start an HTTP server that other systems can ask for a chapter right now,
+e.g. a deploy pipeline asking for the release story when it finishes.
+
+  POST /narrate        Narrate the last hours of a rig's events (or the
+                       town's) and reply with the chapter and its URL.
+                       The body is JSON: {"rig": "gastown", "hours": 6,
+                       "style": "release", "significance": "medium"}, every
+                       field optional; hours defaults to 24.
+  GET  /chapters/<id>  A chapter by ID, its file name without extension
+                       (the URL a POST returns). Chapters awaiting review
+                       aren't served.
+
+A POST narrates like 'gt narrator generate': the reply (201 Created, with a
+Location header) waits for the agent. No events in range is 404, over
+narrator.quota is 429 with Retry-After.
+
+Set narrator.trigger.secret (or secret_env, naming an environment variable)
+in settings/config.json to require each POST body to be signed with
+HMAC-SHA256, sent as X-Gastown-Signature: sha256=<hex>, as event webhooks
+are. The server listens on narrator.trigger.addr, else 127.0.0.1:9466;
+narrator.trigger.url is the base of the chapter URLs it returns when
+callers reach it through a proxy.
+
+Examples:
+  gt narrator serve
+  gt narrator serve --addr :9466
+  curl -X POST localhost:9466/narrate -d '{"rig":"gastown","hours":6}'`,
+	RunE: runNarratorServe,
+}
+
+var narratorConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change the narrator's settings",
+	Args:  cobra.NoArgs,
+	Long: `Show the narrator's settings from settings/config.json, or change one
+with 'gt narrator config set'.
+
+Settings:
+  format  Format chapters are rendered to: markdown, html or text. The agent
+          writes in its style's format and the chapter is converted; html
+          is a standalone page in the theme. Unset, chapters are kept as
+          the style writes them.
+  output_dir
+          Where chapters are written: a path template, relative to the town
+          root unless absolute, given {{.Date}}, {{.Time}}, {{.Rig}} ("town"
+          for town-wide chapters), {{.N}} (chapter number) and {{.Style}}.
+          A path with an extension names the chapter file, one without its
+          directory; directories are created as needed. Unset, chapters go
+          in narrator/.
+  intensity
+          How strongly the tone is laid on, from 1 (a hint of it) to 5
+          (never breaking character). Unset, 3.
+  styles  Further styles each chapter is also written in, from the same
+          events: a comma-separated list of style names, each optionally
+          name=output_dir (a path template as output_dir; default
+          narrator/<style>/).
+  review  "on" holds each chapter back from the site and feed until it is
+          approved ('gt narrator pending', 'approve', 'reject'), and mails
+          narrator.review.notify (default the overseer) about it.
+  theme   Theme of HTML chapters: "default", "dark", or a directory in
+          narrator/themes/ holding page.html (a Go html/template given
+          .Town, .Title, .Date, .Body and .CSS, with the chapter in its
+          <main>) and style.css. A file a theme lacks comes from the
+          default theme.
+  tone    Register chapters and summaries are written in: corporate-neutral
+          (for a standup), grimdark, comedic or epic. It is added to the
+          prompt after the style guide, words the headings of catch-ups,
+          digests and over-quota summaries, and is given to style templates
+          as .Tone and .Intensity. Unset, the style sets the register.
+
+Examples:
+  gt narrator config
+  gt narrator config set format html
+  gt narrator config set theme dark
+  gt narrator config set review on
+  gt narrator config set styles 'book,youtube-short=shorts/{{.Date}}/'
+  gt narrator config set tone comedic
+  gt narrator config set intensity 5
+  gt narrator config set output_dir 'history/{{.Date}}/{{.Rig}}/chapter-{{.N}}.md'
+  gt narrator config set format ""   # back to the style's format`,
+	RunE: runNarratorConfig,
+}
+
+var narratorConfigSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a narrator setting (an empty value restores the default)",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runNarratorConfigSet,
+}
+
+var narratorStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the narrator is paused, its backlog and open plot threads",
+	Args:  cobra.NoArgs,
+	Long: `Show whether the narrator is paused, its session, its backlog and the
+story so far. The town's narrator's status also lists the named narrators
+in narrator.instances; --name shows one of them.
+
+Examples:
+  gt narrator status
+  gt narrator status --name changelog`,
+	RunE: runNarratorStatus,
+}
+
+var narratorPersonaCmd = &cobra.Command{
+	Use:   "persona",
+	Short: "Manage how actors are characterized",
+	RunE:  requireSubcommand,
+	Long: `Manage the persona registry in narrator/personas.json.
+
+A persona maps an event actor to the name, pronouns, personality traits and
+running gags the narrator uses for it, so an agent reads as the same
+character across chapters. Actors without a persona appear under their
+address.
+
+Examples:
+  gt narrator persona add gastown/polecats/Toast --name Toast --pronouns they/them \
+      --trait eager --trait "forgets to push" --gag "always asks for more tests"
+  gt narrator persona list
+  gt narrator persona edit gastown/polecats/Toast --trait cautious
+  gt narrator persona remove gastown/polecats/Toast`,
+}
+
+var narratorPersonaAddCmd = &cobra.Command{
+	Use:   "add <actor>",
+	Short: "Register a persona for an actor",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNarratorPersonaAdd,
+}
+
+var narratorPersonaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered personas",
+	Args:  cobra.NoArgs,
+	RunE:  runNarratorPersonaList,
+}
+
+var narratorPersonaEditCmd = &cobra.Command{
+	Use:   "edit <actor>",
+	Short: "Change an actor's persona",
+	Long: `Change an actor's persona. Only the flags given are changed; --trait and
+--gag replace the existing lists.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNarratorPersonaEdit,
+}
+
+var narratorPersonaRemoveCmd = &cobra.Command{
+	Use:   "remove <actor>",
+	Short: "Remove an actor's persona",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNarratorPersonaRemove,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{narratorPersonaAddCmd, narratorPersonaEditCmd} {
+		c.Flags().StringVar(&personaName, "name", "", "Name used in narration")
+		c.Flags().StringVar(&personaPronouns, "pronouns", "", "Pronouns (e.g. they/them)")
+		c.Flags().StringArrayVar(&personaTraits, "trait", nil, "Personality trait (repeatable)")
+		c.Flags().StringArrayVar(&personaGags, "gag", nil, "Running gag (repeatable)")
+	}
+	_ = narratorPersonaAddCmd.MarkFlagRequired("name")
+	narratorPersonaListCmd.Flags().BoolVar(&personaJSON, "json", false, "Output as JSON")
+
+	narratorPersonaCmd.AddCommand(narratorPersonaAddCmd)
+	narratorPersonaCmd.AddCommand(narratorPersonaListCmd)
+	narratorPersonaCmd.AddCommand(narratorPersonaEditCmd)
+	narratorPersonaCmd.AddCommand(narratorPersonaRemoveCmd)
+	narratorStartCmd.Flags().StringVar(&narratorAgentOverride, "agent", "", "Agent alias to run the narrator with (overrides role_agents.narrator)")
+	for _, c := range []*cobra.Command{narratorStartCmd, narratorStopCmd, narratorPauseCmd, narratorResumeCmd, narratorStatusCmd, narratorGenerateCmd} {
+		c.Flags().StringVar(&narratorName, "name", "", "Named narrator instance (default: $GT_NARRATOR, else the town's narrator)")
+	}
+	narratorPauseCmd.Flags().StringVar(&narratorPauseReason, "reason", "", "Reason for pausing the narrator")
+	narratorResumeCmd.Flags().BoolVar(&narratorResumeSkip, "skip", false, "Leave the backlog out of the narrative")
+	narratorResumeCmd.Flags().BoolVar(&narratorResumeSumm, "summarize", false, "Write the backlog up as a catch-up chapter")
+	narratorResumeCmd.MarkFlagsMutuallyExclusive("skip", "summarize")
+
+	narratorGenerateCmd.Flags().StringVar(&narratorGenSince, "since", "24h", "Narrate events since this age (e.g. 24h, 7d) or RFC3339 time")
+	narratorGenerateCmd.Flags().StringVar(&narratorGenUntil, "until", "", "Narrate events before this age or RFC3339 time (default: now)")
+	narratorGenerateCmd.Flags().StringVar(&narratorGenStyle, "style", narrator.DefaultStyle, "Style from narrator/styles/ (see 'gt narrator styles')")
+	narratorGenerateCmd.Flags().StringVar(&narratorGenRig, "rig", "", "Only narrate this rig's events")
+	narratorGenerateCmd.Flags().StringVar(&narratorGenSignificance, "significance", events.SignificanceMedium, "Minimum significance to narrate (low, medium, high)")
+	narratorGenerateCmd.Flags().StringVar(&narratorGenAgent, "agent", "", "Agent to narrate with (overrides role_agents.narrator)")
+	narratorGenerateCmd.Flags().StringVarP(&narratorGenOutput, "output", "o", "", "Chapter file (default: narrator/chapter-<time>.md)")
+	narratorGenerateCmd.Flags().BoolVar(&narratorGenDryRun, "dry-run", false, "Print the prompt and agent command without running it")
+	narratorGenerateCmd.Flags().BoolVar(&narratorGenStrict, "strict", false, "Don't write the chapter if it has terminology issues")
+	narratorReplayCmd.Flags().StringVar(&narratorReplayFrom, "from", "", "Events file (JSONL) to replay (required)")
+	narratorReplayCmd.Flags().StringVar(&narratorReplaySince, "since", "", "Replay events since this age or RFC3339 time (default: all)")
+	narratorReplayCmd.Flags().StringVar(&narratorReplayUntil, "until", "", "Replay events before this age or RFC3339 time (default: all)")
+	narratorReplayCmd.Flags().StringVar(&narratorReplayStyle, "style", narrator.DefaultStyle, "Style from narrator/styles/ (see 'gt narrator styles')")
+	narratorReplayCmd.Flags().StringVar(&narratorReplayRig, "rig", "", "Only replay this rig's events")
+	narratorReplayCmd.Flags().StringVar(&narratorReplaySignificance, "significance", events.SignificanceMedium, "Minimum significance to narrate (low, medium, high)")
+	narratorReplayCmd.Flags().StringVar(&narratorReplayAgent, "agent", "", "Agent to narrate with (overrides role_agents.narrator)")
+	narratorReplayCmd.Flags().BoolVar(&narratorReplayDryRun, "dry-run", false, "Print the prompt and agent command without running it")
+	_ = narratorReplayCmd.MarkFlagRequired("from")
+	narratorLintCmd.Flags().BoolVar(&narratorLintJSON, "json", false, "Output as JSON")
+	narratorStylesCmd.Flags().BoolVar(&narratorStylesJSON, "json", false, "Output as JSON")
+	narratorPublishCmd.Flags().StringVar(&narratorPublishOut, "out", "", "Directory to write the site to (default: narrator.publish.dir or narrator/site)")
+	narratorPublishCmd.Flags().BoolVar(&narratorPublishForce, "force", false, "Render every page, not just those whose chapter changed")
+	narratorPublishCmd.Flags().BoolVar(&narratorPublishJSON, "json", false, "Output as JSON")
+	narratorDigestCmd.Flags().StringVar(&narratorDigestSince, "since", "", "Digest events since this date, age or RFC3339 time (default: since the last digest)")
+	narratorDigestCmd.Flags().StringVar(&narratorDigestUntil, "until", "", "Digest events before this date, age or RFC3339 time (default: now)")
+	narratorDigestCmd.Flags().StringVar(&narratorDigestRig, "rig", "", "Only digest this rig's events")
+	narratorDigestCmd.Flags().BoolVar(&narratorDigestJSON, "json", false, "Output as JSON")
+	narratorSearchCmd.Flags().IntVarP(&narratorSearchLimit, "limit", "n", 5, "Maximum chapters to show")
+	narratorSearchCmd.Flags().BoolVar(&narratorSearchJSON, "json", false, "Output as JSON")
+	narratorSummarizeCmd.Flags().StringVar(&narratorSummStyle, "style", narrator.DefaultStyle, "Style from narrator/styles/ (see 'gt narrator styles')")
+	narratorSummarizeCmd.Flags().StringVar(&narratorSummAgent, "agent", "", "Agent to narrate with (overrides role_agents.narrator)")
+	narratorSummarizeCmd.Flags().StringVarP(&narratorSummOutput, "output", "o", "", "Story file (default: narrator/work-<bead>-<time>.md)")
+	narratorSummarizeCmd.Flags().BoolVar(&narratorSummDryRun, "dry-run", false, "Print the prompt and agent command without running it")
+	narratorSummarizeCmd.Flags().BoolVar(&narratorSummJSON, "json", false, "Output as JSON")
+	narratorPendingCmd.Flags().BoolVar(&narratorPendingJSON, "json", false, "Output as JSON")
+	narratorRejectCmd.Flags().StringVar(&narratorRejectReason, "reason", "", "Why the chapter is withheld")
+	narratorCommentaryCmd.Flags().StringVar(&narratorCommentarySignificance, "significance", events.SignificanceMedium, "Minimum significance to comment on (low, medium, high)")
+	narratorCommentaryCmd.Flags().BoolVar(&narratorCommentaryPane, "pane", false, "Open the commentary in a tmux pane instead")
+	narratorCommentaryCmd.Flags().StringVar(&narratorCommentaryTarget, "target", "", "Tmux session or window to open the pane in (default: the narrator's session)")
+	narratorCommentaryCmd.Flags().BoolVar(&narratorCommentaryJSON, "json", false, "Output each comment as a JSON line")
+	narratorPrologueCmd.Flags().StringSliceVar(&narratorProloRigs, "rig", nil, "Rig to tell the history of (repeatable; default: every rig)")
+	narratorPrologueCmd.Flags().StringVar(&narratorProloSince, "since", "", "Tell commits since this date, age (e.g. 90d) or RFC3339 time (default: all)")
+	narratorPrologueCmd.Flags().IntVar(&narratorProloMaxCommits, "max-commits", narrator.DefaultPrologueCommits, "Newest commits of each rig to tell")
+	narratorPrologueCmd.Flags().StringVar(&narratorProloStyle, "style", narrator.DefaultStyle, "Style from narrator/styles/ (see 'gt narrator styles')")
+	narratorPrologueCmd.Flags().StringVar(&narratorProloAgent, "agent", "", "Agent to narrate with (overrides role_agents.narrator)")
+	narratorPrologueCmd.Flags().StringVarP(&narratorProloOutput, "output", "o", "", "Chapter file (default: where chapters go)")
+	narratorPrologueCmd.Flags().BoolVar(&narratorProloDryRun, "dry-run", false, "Print the prompt and agent command without running it")
+	narratorPrologueCmd.Flags().BoolVar(&narratorProloJSON, "json", false, "Output as JSON")
+	narratorServeCmd.Flags().StringVar(&narratorServeAddr, "addr", "", "Address to listen on (default: narrator.trigger.addr, else 127.0.0.1:9466)")
+	narratorConfigCmd.Flags().BoolVar(&narratorConfigJSON, "json", false, "Output as JSON")
+	narratorConfigCmd.AddCommand(narratorConfigSetCmd)
+
+	narratorCmd.AddCommand(narratorStartCmd)
+	narratorCmd.AddCommand(narratorStopCmd)
+	narratorCmd.AddCommand(narratorPauseCmd)
+	narratorCmd.AddCommand(narratorResumeCmd)
+	narratorCmd.AddCommand(narratorStatusCmd)
+	narratorCmd.AddCommand(narratorGenerateCmd)
+	narratorCmd.AddCommand(narratorReplayCmd)
+	narratorCmd.AddCommand(narratorLintCmd)
+	narratorCmd.AddCommand(narratorStylesCmd)
+	narratorCmd.AddCommand(narratorPublishCmd)
+	narratorCmd.AddCommand(narratorConfigCmd)
+	narratorCmd.AddCommand(narratorDigestCmd)
+	narratorCmd.AddCommand(narratorSearchCmd)
+	narratorCmd.AddCommand(narratorSummarizeCmd)
+	narratorCmd.AddCommand(narratorPrologueCmd)
+	narratorCmd.AddCommand(narratorPendingCmd)
+	narratorCmd.AddCommand(narratorApproveCmd)
+	narratorCmd.AddCommand(narratorRejectCmd)
+	narratorCmd.AddCommand(narratorCommentaryCmd)
+	narratorCmd.AddCommand(narratorServeCmd)
+	narratorCmd.AddCommand(narratorPersonaCmd)
+	rootCmd.AddCommand(narratorCmd)
+}
+
+// updatePersonas loads the town's personas, applies fn, and saves them.
+func updatePersonas(fn func(*narrator.Personas) error) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	personas, err := narrator.LoadPersonas(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(personas); err != nil {
+		return err
+	}
+	return personas.Save(townRoot)
+}
+
+func runNarratorPersonaAdd(cmd *cobra.Command, args []string) error {
+	err := updatePersonas(func(p *narrator.Personas) error {
+		return p.Add(narrator.Persona{
+			Actor:    args[0],
+			Name:     personaName,
+			Pronouns: personaPronouns,
+			Traits:   personaTraits,
+			Gags:     personaGags,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Added persona %s for %s\n", style.Success.Render("✓"), personaName, args[0])
+	return nil
+}
+
+func runNarratorPersonaEdit(cmd *cobra.Command, args []string) error {
+	err := updatePersonas(func(p *narrator.Personas) error {
+		persona, err := p.Get(args[0])
+		if err != nil {
+			return err
+		}
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			persona.Name = personaName
+		}
+		if flags.Changed("pronouns") {
+			persona.Pronouns = personaPronouns
+		}
+		if flags.Changed("trait") {
+			persona.Traits = personaTraits
+		}
+		if flags.Changed("gag") {
+			persona.Gags = personaGags
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Updated persona for %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+func runNarratorPersonaRemove(cmd *cobra.Command, args []string) error {
+	if err := updatePersonas(func(p *narrator.Personas) error { return p.Remove(args[0]) }); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed persona for %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+func runNarratorPersonaList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	personas, err := narrator.LoadPersonas(townRoot)
+	if err != nil {
+		return err
+	}
+	list := personas.List()
+
+	if personaJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No personas registered (add one with 'gt narrator persona add')"))
+		return nil
+	}
+	for _, p := range list {
+		name := p.Name
+		if p.Pronouns != "" {
+			name += " (" + p.Pronouns + ")"
+		}
+		fmt.Printf("%s  %s\n", style.Bold.Render(name), style.Dim.Render(p.Actor))
+		if len(p.Traits) > 0 {
+			fmt.Printf("    traits: %s\n", strings.Join(p.Traits, ", "))
+		}
+		if len(p.Gags) > 0 {
+			fmt.Printf("    gags:   %s\n", strings.Join(p.Gags, "; "))
+		}
+	}
+	return nil
+}
+
+// narratorInstanceName returns the named narrator a command acts on:
+// --name, else $GT_NARRATOR in a named narrator's session, else "" for
+// the town's narrator.
+func narratorInstanceName() (string, error) {
+	name := narratorName
+	if name == "" {
+		name = os.Getenv("GT_NARRATOR")
+	}
+	if name != "" {
+		if err := narrator.CheckInstanceName(name); err != nil {
+			return "", &usageError{err: err}
+		}
+	}
+	return name, nil
+}
+
+// narratorLabel names a narrator in messages.
+func narratorLabel(name string) string {
+	if name == "" {
+		return "Narrator"
+	}
+	return "Narrator " + name
+}
+
+// narratorNameFlag is the --name flag to repeat in a suggested command.
+func narratorNameFlag(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " --name " + name
+}
+
+func runNarratorStart(cmd *cobra.Command, args []string) error {
+	name, err := narratorInstanceName()
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	mgr, err := narrator.NewInstanceManager(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Start(narratorAgentOverride); err != nil {
+		if errors.Is(err, narrator.ErrAlreadyRunning) {
+			return fmt.Errorf("narrator session already running. Attach with: tmux attach -t %s", mgr.SessionName())
+		}
+		return fmt.Errorf("starting narrator: %w", err)
+	}
+	fmt.Printf("%s %s session started. Attach with: %s\n",
+		style.Bold.Render("✓"), narratorLabel(name),
+		style.Dim.Render("tmux attach -t "+mgr.SessionName()))
+	return nil
+}
+
+func runNarratorStop(cmd *cobra.Command, args []string) error {
+	name, err := narratorInstanceName()
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	mgr, err := narrator.NewInstanceManager(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Stop(); err != nil {
+		if errors.Is(err, narrator.ErrNotRunning) {
+			fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+			return nil
+		}
+		return fmt.Errorf("stopping narrator: %w", err)
+	}
+	fmt.Printf("%s %s session stopped\n", style.Bold.Render("✓"), narratorLabel(name))
+	return nil
+}
+
+func runNarratorPause(cmd *cobra.Command, args []string) error {
+	name, err := narratorInstanceName()
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	err = narrator.PauseInstance(townRoot, name, narratorPauseReason, "human")
+	if errors.Is(err, narrator.ErrAlreadyPaused) {
+		fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("pausing narrator: %w", err)
+	}
+
+	fmt.Printf("%s %s paused\n", style.Bold.Render("⏸️"), narratorLabel(name))
+	if narratorPauseReason != "" {
+		fmt.Printf("  Reason: %s\n", narratorPauseReason)
+	}
+	fmt.Printf("Events will accumulate until resumed with: %s\n", style.Dim.Render("gt narrator resume"+narratorNameFlag(name)))
+	return nil
+}
+
+func runNarratorResume(cmd *cobra.Command, args []string) error {
+	name, err := narratorInstanceName()
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if name != "" {
+		if narratorResumeSumm {
+			return &usageError{err: errors.New("--summarize: a named narrator's backlog is always skipped")}
+		}
+		err := narrator.ResumeInstance(townRoot, name)
+		if errors.Is(err, narrator.ErrNotPaused) {
+			fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("resuming narrator: %w", err)
+		}
+		fmt.Printf("%s %s resumed\n", style.Bold.Render("▶️"), narratorLabel(name))
+		return nil
+	}
+	mode := ""
+	switch {
+	case narratorResumeSkip:
+		mode = config.NarratorResumeSkip
+	case narratorResumeSumm:
+		mode = config.NarratorResumeSummarize
+	}
+
+	c, err := narrator.Resume(townRoot, mode)
+	if errors.Is(err, narrator.ErrNotPaused) {
+		fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("resuming narrator: %w", err)
+	}
+
+	fmt.Printf("%s Narrator resumed\n", style.Bold.Render("▶️"))
+	switch {
+	case c.Events == 0:
+		fmt.Printf("  No events while paused\n")
+	case c.Path != "":
+		fmt.Printf("  Caught up on %d event(s): %s\n", c.Events, c.Path)
+		if c.Commit != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("Committed "+c.Commit[:8]+" to the narrative branch"))
+		}
+		if c.Site != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("Published to "+c.Site))
+		}
+	default:
+		fmt.Printf("  Skipped %d event(s)\n", c.Events)
+	}
+	return nil
+}
+
+func runNarratorStatus(cmd *cobra.Command, args []string) error {
+	name, err := narratorInstanceName()
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := narrator.LoadInstanceState(townRoot, name)
+	if err != nil {
+		return err
+	}
+	story, err := narrator.LoadStory(townRoot)
+	if err != nil {
+		return err
+	}
+	usage, err := narrator.LoadUsage(townRoot)
+	if err != nil {
+		return err
+	}
+	mgr, err := narrator.NewInstanceManager(townRoot, name)
+	if err != nil {
+		return err
+	}
+	session := "not running"
+	if running, _ := mgr.IsRunning(); running {
+		session = "running"
+	}
+	if !state.Paused() {
+		fmt.Printf("%s %s running\n", style.Success.Render("●"), narratorLabel(name))
+		fmt.Printf("  Session: %s (%s)\n", mgr.SessionName(), session)
+		if !state.NarratedThrough.IsZero() {
+			fmt.Printf("  Narrated through: %s\n", state.NarratedThrough.Local().Format(time.RFC3339))
+		}
+		if name == "" {
+			printNarratorInstances(townRoot)
+		}
+		printStory(story)
+		printNarratorReview(townRoot)
+		printNarratorUsage(townRoot, state, usage)
+		return nil
+	}
+
+	backlog, err := narrator.InstanceBacklog(townRoot, name)
+	if err != nil {
+		return fmt.Errorf("reading backlog: %w", err)
+	}
+	fmt.Printf("%s %s paused\n", style.Bold.Render("⏸️"), narratorLabel(name))
+	fmt.Printf("  Session: %s (%s)\n", mgr.SessionName(), session)
+	if state.Reason != "" {
+		fmt.Printf("  Reason: %s\n", state.Reason)
+	}
+	fmt.Printf("  Paused at: %s\n", state.PausedAt.Local().Format(time.RFC3339))
+	fmt.Printf("  Paused by: %s\n", state.PausedBy)
+	fmt.Printf("  Backlog: %d event(s)\n", len(backlog))
+	if name == "" {
+		printNarratorInstances(townRoot)
+	}
+	printStory(story)
+	printNarratorReview(townRoot)
+	printNarratorUsage(townRoot, state, usage)
+	return nil
+}
+
+// printNarratorInstances shows the named narrators and whether each is
+// running, if the town has any.
+func printNarratorInstances(townRoot string) {
+	names := narrator.Instances(townRoot)
+	if len(names) == 0 {
+		return
+	}
+	fmt.Printf("  Named narrators:\n")
+	for _, name := range names {
+		status := "not running"
+		if mgr, err := narrator.NewInstanceManager(townRoot, name); err != nil {
+			status = err.Error()
+		} else if running, _ := mgr.IsRunning(); running {
+			status = "running"
+		}
+		if state, err := narrator.LoadInstanceState(townRoot, name); err == nil && state.Paused() {
+			status += ", paused"
+		}
+		fmt.Printf("    %s (%s)\n", name, status)
+	}
+}
+
+// printNarratorReview shows how many chapters await review, if any.
+func printNarratorReview(townRoot string) {
+	if pending, err := narrator.Pending(townRoot); err == nil && len(pending) > 0 {
+		fmt.Printf("  Awaiting review: %d chapter(s) (see 'gt narrator pending')\n", len(pending))
+	}
+}
+
+// printNarratorUsage shows the narrating agent's recent runs against the
+// town's narrator.quota, and any events the quota has queued.
+func printNarratorUsage(townRoot string, state *narrator.State, usage *narrator.Usage) {
+	var quota config.NarratorQuota
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil &&
+		settings.Narrator != nil && settings.Narrator.Quota != nil {
+		quota = *settings.Narrator.Quota
+	}
+	limit := func(n int) string {
+		if n <= 0 {
+			return ""
+		}
+		return fmt.Sprintf(" of %d", n)
+	}
+	now := time.Now()
+	hour, _ := usage.Since(now.Add(-time.Hour))
+	day, tokens := usage.Since(now.Add(-24 * time.Hour))
+	fmt.Printf("  Usage: %d%s chapter(s) in the last hour, %d%s in 24h, ~%d%s tokens in 24h\n",
+		hour, limit(quota.MaxPerHour), day, limit(quota.MaxPerDay), tokens, limit(quota.MaxTokensPerDay))
+	if !state.QueuedSince.IsZero() {
+		fmt.Printf("  %s Events since %s queued by the quota\n", style.Warning.Render("⚠"), state.QueuedSince.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// printStory shows where the narrative stands: the last chapter and the
+// plot threads still open.
+func printStory(story *narrator.Story) {
+	if story.Chapter == 0 {
+		return
+	}
+	fmt.Printf("  Last chapter: %d (%s)\n", story.Chapter, story.LastChapter)
+	for _, t := range story.Threads {
+		fmt.Printf("  %s %s %s\n", style.Dim.Render("○"), t.Title, style.Dim.Render(fmt.Sprintf("[%s, since chapter %d]", t.ID, t.Opened)))
+	}
+}
+
+func runNarratorGenerate(cmd *cobra.Command, args []string) error {
+	name, err := narratorInstanceName()
+	if err != nil {
+		return err
+	}
+	opts := narrator.GenerateOptions{
+		Instance:     name,
+		Style:        narratorGenStyle,
+		Rig:          narratorGenRig,
+		Significance: narratorGenSignificance,
+		Agent:        narratorGenAgent,
+		Output:       narratorGenOutput,
+		Strict:       narratorGenStrict,
+	}
+	if name != "" && !cmd.Flags().Changed("style") {
+		// The named narrator's own style
+		opts.Style = ""
+	}
+	if err := events.ValidSignificance(opts.Significance); err != nil {
+		return &usageError{err: err}
+	}
+	if opts.Since, err = parseCutoff("--since", narratorGenSince); err != nil {
+		return &usageError{err: err}
+	}
+	if narratorGenUntil != "" {
+		if opts.Until, err = parseCutoff("--until", narratorGenUntil); err != nil {
+			return &usageError{err: err}
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if narratorGenDryRun {
+		if _, err := opts.ApplyInstance(townRoot); err != nil {
+			return err
+		}
+		return printNarratorPrompt(townRoot, opts)
+	}
+
+	fmt.Printf("%s Narrating events since %s...\n", style.Dim.Render("○"), opts.Since.Local().Format("2006-01-02 15:04"))
+	chapter, err := narrator.Generate(context.Background(), townRoot, opts)
+	if errors.Is(err, narrator.ErrNoEvents) {
+		fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+		return nil
+	}
+	var lintErr *narrator.LintError
+	if errors.As(err, &lintErr) {
+		printLintIssues("narrative", lintErr.Issues)
+		return fmt.Errorf("%w; chapter not written (drop --strict to keep it)", err)
+	}
+	var quotaErr *narrator.QuotaError
+	if errors.As(err, &quotaErr) {
+		fmt.Printf("%s %v\n", style.Warning.Render("⚠"), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("generating narrative: %w", err)
+	}
+	if chapter.Summary {
+		fmt.Printf("%s Over quota (%s); wrote a summary: %s\n", style.Warning.Render("⚠"), chapter.OverQuota, chapter.Path)
+		fmt.Printf("  %d event(s), %d words\n", chapter.Events, chapter.Words)
+		if chapter.Commit != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("Committed "+chapter.Commit[:8]+" to the narrative branch"))
+		}
+		if chapter.Site != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("Published to "+chapter.Site))
+		}
+		return nil
+	}
+	fmt.Printf("%s Wrote chapter %d: %s\n", style.Success.Render("✓"), chapter.Number, chapter.Path)
+	fmt.Printf("  %d event(s), %d words, style %s, narrated by %s\n", chapter.Events, chapter.Words, chapter.Style, chapter.Agent)
+	if chapter.Length != "" {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), chapter.Length)
+	}
+	if chapter.Commit != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Committed "+chapter.Commit[:8]+" to the narrative branch"))
+	}
+	if chapter.Omitted > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d earlier event(s) omitted to fit the prompt", chapter.Omitted)))
+	}
+	if chapter.OverQuota != "" {
+		fmt.Printf("  %s over quota (%s); %d event(s) below high significance dropped\n",
+			style.Warning.Render("⚠"), chapter.OverQuota, chapter.Dropped)
+	}
+	if len(chapter.Illustrations) > 0 {
+		rendered := 0
+		for _, ill := range chapter.Illustrations {
+			if ill.Image != "" {
+				rendered++
+			}
+		}
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d scene(s) for illustration, %d rendered, in %s",
+			len(chapter.Illustrations), rendered, narrator.IllustrationsDir(chapter.Path))))
+		for _, ill := range chapter.Illustrations {
+			if ill.Error != "" {
+				fmt.Printf("  %s scene %d: %s\n", style.Warning.Render("⚠"), ill.Scene, ill.Error)
+			}
+		}
+	}
+	for _, ed := range chapter.Editions {
+		if ed.Path == "" {
+			fmt.Printf("  %s %s edition over quota (%s); not written\n", style.Warning.Render("⚠"), ed.Style, ed.OverQuota)
+			continue
+		}
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%s edition: %s (%d words)", ed.Style, ed.Path, ed.Words)))
+		if ed.Length != "" {
+			fmt.Printf("  %s %s edition: %s\n", style.Warning.Render("⚠"), ed.Style, ed.Length)
+		}
+		if len(ed.Issues) > 0 {
+			printLintIssues(ed.Path, ed.Issues)
+		}
+	}
+	if chapter.Site != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Published to "+chapter.Site))
+	}
+	for _, path := range chapter.Digests {
+		fmt.Printf("  %s\n", style.Dim.Render("Digest due, wrote "+path))
+	}
+	if len(chapter.Issues) > 0 {
+		fmt.Printf("%s %d terminology issue(s):\n", style.Warning.Render("⚠"), len(chapter.Issues))
+		printLintIssues(chapter.Path, chapter.Issues)
+	}
+	return nil
+}
+
+func runNarratorReplay(cmd *cobra.Command, args []string) error {
+	opts := narrator.GenerateOptions{
+		From:         narratorReplayFrom,
+		Style:        narratorReplayStyle,
+		Rig:          narratorReplayRig,
+		Significance: narratorReplaySignificance,
+		Agent:        narratorReplayAgent,
+	}
+	if err := events.ValidSignificance(opts.Significance); err != nil {
+		return &usageError{err: err}
+	}
+	var err error
+	if narratorReplaySince != "" {
+		if opts.Since, err = parseCutoff("--since", narratorReplaySince); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	if narratorReplayUntil != "" {
+		if opts.Until, err = parseCutoff("--until", narratorReplayUntil); err != nil {
+			return &usageError{err: err}
+		}
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if narratorReplayDryRun {
+		return printNarratorPrompt(townRoot, opts)
+	}
+	text, issues, err := narrator.Replay(context.Background(), townRoot, opts)
+	if err != nil {
+		return fmt.Errorf("replaying %s: %w", opts.From, err)
+	}
+	fmt.Println(text)
+	if len(issues) > 0 {
+		fmt.Printf("\n%s %d terminology issue(s):\n", style.Warning.Render("⚠"), len(issues))
+		printLintIssues("narrative", issues)
+	}
+	return nil
+}
+
+// printNarratorPrompt prints the prompt and agent command a narration
+// would use, without running the agent.
+func printNarratorPrompt(townRoot string, opts narrator.GenerateOptions) error {
+	prompt, err := narrator.BuildPrompt(townRoot, opts)
+	if err != nil {
+		return err
+	}
+	name, rc, err := narrator.ResolveAgent(townRoot, opts.Agent)
+	if err != nil {
+		return err
+	}
+	argv, err := narrator.AgentCommand(name, rc, "<prompt>")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Agent:"), strings.Join(argv, " "))
+	fmt.Printf("%s %d event(s), style %s", style.Bold.Render("Prompt:"), prompt.Events, prompt.Style)
+	if prompt.Omitted > 0 {
+		fmt.Printf(", %d earlier omitted", prompt.Omitted)
+	}
+	fmt.Print("\n\n")
+	fmt.Print(prompt.Text)
+	return nil
+}
+
+func runNarratorStyles(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	styles, err := narrator.LoadStyles(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if narratorStylesJSON {
+		if styles == nil {
+			styles = []*narrator.Style{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(styles)
+	}
+
+	if len(styles) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No styles in narrator/styles/ (add <name>.md or <name>.yaml)"))
+		return nil
+	}
+	for _, s := range styles {
+		fmt.Printf("%s  %s\n", style.Bold.Render(s.Name), style.Dim.Render(filepath.Base(s.Path)))
+		if s.Description != "" {
+			fmt.Printf("    %s\n", s.Description)
+		}
+		details := []string{"format " + s.Format}
+		if s.Chunking.MaxEvents > 0 {
+			details = append(details, fmt.Sprintf("up to %d events", s.Chunking.MaxEvents))
+		}
+		if s.Chunking.GroupBy != "" {
+			details = append(details, "grouped by "+s.Chunking.GroupBy)
+		}
+		switch lo, hi := s.Length.MinWords, s.Length.MaxWords; {
+		case lo > 0 && hi > 0:
+			details = append(details, fmt.Sprintf("%d-%d words", lo, hi))
+		case lo > 0:
+			details = append(details, fmt.Sprintf("at least %d words", lo))
+		case hi > 0:
+			details = append(details, fmt.Sprintf("up to %d words", hi))
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(strings.Join(details, ", ")))
+	}
+	return nil
+}
+
+func runNarratorLint(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	issues, err := narrator.LintText(townRoot, string(data))
+	if err != nil {
+		return err
+	}
+
+	if narratorLintJSON {
+		if issues == nil {
+			issues = []narrator.LintIssue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(issues); err != nil {
+			return err
+		}
+	} else if len(issues) == 0 {
+		fmt.Printf("%s %s: no terminology issues\n", style.Success.Render("✓"), args[0])
+	} else {
+		printLintIssues(args[0], issues)
+	}
+	if len(issues) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// printLintIssues prints terminology issues as file:line:col: message.
+func printLintIssues(file string, issues []narrator.LintIssue) {
+	for _, issue := range issues {
+		fmt.Printf("%s:%s %s\n", file, issue.String(), style.Dim.Render("["+issue.Kind+"]"))
+	}
+}
+
+func runNarratorPublish(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	result, err := narrator.Publish(townRoot, narratorPublishOut, narratorPublishForce)
+	if err != nil {
+		return fmt.Errorf("publishing site: %w", err)
+	}
+
+	if narratorPublishJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	fmt.Printf("%s Published %d chapter(s) to %s\n", style.Success.Render("✓"), result.Chapters, result.Dir)
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d page(s) rendered, %d file(s) written, %d removed",
+		result.Rendered, result.Written, result.Removed)))
+	for _, path := range result.Missing {
+		fmt.Printf("  %s chapter file missing: %s\n", style.Warning.Render("⚠"), path)
+	}
+	if result.Withheld > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d chapter(s) awaiting review or rejected left out", result.Withheld)))
+	}
+	return nil
+}
+
+func runNarratorConfig(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := narrator.Settings(townRoot)
+	if err != nil {
+		return err
+	}
+	if narratorConfigJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(settings)
+	}
+	for _, s := range settings {
+		value := s.Value
+		if value == "" {
+			value = style.Dim.Render("(" + s.Default + ")")
+		}
+		fmt.Printf("%s: %s\n", style.Bold.Render(s.Key), value)
+		fmt.Printf("    %s\n", style.Dim.Render(s.Help))
+	}
+	return nil
+}
+
+func runNarratorConfigSet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := narrator.Set(townRoot, args[0], args[1]); err != nil {
+		return err
+	}
+	if args[1] == "" {
+		fmt.Printf("%s Reset narrator %s to its default\n", style.Success.Render("✓"), args[0])
+		return nil
+	}
+	fmt.Printf("%s Set narrator %s to %s\n", style.Success.Render("✓"), args[0], args[1])
+	return nil
+}
+
+func runNarratorDigest(cmd *cobra.Command, args []string) error {
+	var opts narrator.DigestOptions
+	var err error
+	if narratorDigestSince != "" {
+		if opts.Since, err = parseCutoff("--since", narratorDigestSince); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	if narratorDigestUntil != "" {
+		if opts.Until, err = parseCutoff("--until", narratorDigestUntil); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	opts.Rig = narratorDigestRig
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	digests, err := narrator.WriteDigests(context.Background(), townRoot, opts)
+	if errors.Is(err, narrator.ErrNoEvents) {
+		fmt.Printf("%s No routine events to digest\n", style.Dim.Render("○"))
+		return nil
+	}
+	if err != nil && len(digests) == 0 {
+		return fmt.Errorf("writing digests: %w", err)
+	}
+
+	if narratorDigestJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(digests); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+	for _, d := range digests {
+		fmt.Printf("%s Wrote %s digest: %s\n", style.Success.Render("✓"), d.Rig, d.Path)
+		fmt.Printf("  %d event(s)\n", d.Events)
+		if d.Commit != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("Committed "+d.Commit[:8]+" to the narrative branch"))
+		}
+	}
+	return err
+}
+
+func runNarratorSearch(cmd *cobra.Command, args []string) error {
+	if narratorSearchLimit < 1 {
+		return &usageError{err: fmt.Errorf("--limit must be at least 1")}
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	found, err := narrator.Recall(townRoot, strings.Join(args, " "), narratorSearchLimit)
+	if err != nil {
+		return fmt.Errorf("searching chapters: %w", err)
+	}
+
+	if narratorSearchJSON {
+		if found == nil {
+			found = []narrator.Recollection{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(found)
+	}
+	if len(found) == 0 {
+		fmt.Printf("%s No chapters match\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, r := range found {
+		title := r.Title
+		if r.Number > 0 {
+			title = fmt.Sprintf("Chapter %d: %s", r.Number, r.Title)
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render(title), style.Dim.Render(fmt.Sprintf("(%s, score %.2f)", r.Until.Local().Format("Jan 2, 2006"), r.Score)))
+		if r.Recap != "" {
+			fmt.Printf("  %s\n", r.Recap)
+		}
+		if len(r.Threads) > 0 {
+			fmt.Printf("  Storylines: %s\n", strings.Join(r.Threads, "; "))
+		}
+		fmt.Printf("  %s\n", style.Dim.Render(r.Path))
+	}
+	return nil
+}
+
+func runNarratorSummarize(cmd *cobra.Command, args []string) error {
+	bead := args[0]
+	opts := narrator.WorkOptions{Style: narratorSummStyle, Agent: narratorSummAgent, Output: narratorSummOutput}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if narratorSummDryRun {
+		prompt, err := narrator.BuildWorkPrompt(townRoot, bead, opts)
+		if err != nil {
+			return err
+		}
+		name, rc, err := narrator.ResolveAgent(townRoot, opts.Agent)
+		if err != nil {
+			return err
+		}
+		argv, err := narrator.AgentCommand(name, rc, "<prompt>")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("Agent:"), strings.Join(argv, " "))
+		fmt.Printf("%s %d event(s), %d mail, %d merge(s), style %s\n\n", style.Bold.Render("Prompt:"), prompt.Events, prompt.Mail, prompt.Merges, prompt.Style)
+		fmt.Print(prompt.Text)
+		return nil
+	}
+
+	story, err := narrator.SummarizeWork(context.Background(), townRoot, bead, opts)
+	if errors.Is(err, narrator.ErrNoEvents) {
+		fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+		return nil
+	}
+	var quotaErr *narrator.QuotaError
+	if errors.As(err, &quotaErr) {
+		fmt.Printf("%s %v\n", style.Warning.Render("⚠"), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("summarizing %s: %w", bead, err)
+	}
+
+	if narratorSummJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(story)
+	}
+	fmt.Printf("%s Wrote the story of %s: %s\n", style.Success.Render("✓"), bead, story.Path)
+	fmt.Printf("  %d event(s), %d mail, %d merge(s), %d words, narrated by %s\n", story.Events, story.Mail, story.Merges, story.Words, story.Agent)
+	if len(story.Issues) > 0 {
+		printLintIssues("story", story.Issues)
+	}
+	return nil
+}
+
+func runNarratorPrologue(cmd *cobra.Command, args []string) error {
+	opts := narrator.PrologueOptions{
+		Rigs:       narratorProloRigs,
+		MaxCommits: narratorProloMaxCommits,
+		Style:      narratorProloStyle,
+		Agent:      narratorProloAgent,
+		Output:     narratorProloOutput,
+	}
+	if narratorProloSince != "" {
+		var err error
+		if opts.Since, err = parseCutoff("--since", narratorProloSince); err != nil {
+			return err
+		}
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if narratorProloDryRun {
+		prompt, err := narrator.BuildPrologue(townRoot, opts)
+		if err != nil {
+			return err
+		}
+		name, rc, err := narrator.ResolveAgent(townRoot, opts.Agent)
+		if err != nil {
+			return err
+		}
+		argv, err := narrator.AgentCommand(name, rc, "<prompt>")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("Agent:"), strings.Join(argv, " "))
+		fmt.Printf("%s %d commit(s) in %s, %d storyline(s), style %s\n\n", style.Bold.Render("Prompt:"),
+			prompt.Commits, strings.Join(prompt.Rigs, ", "), len(prompt.Storylines), prompt.Style)
+		fmt.Print(prompt.Text)
+		return nil
+	}
+
+	fmt.Printf("%s Telling the town's prologue from its git history...\n", style.Dim.Render("○"))
+	chapter, err := narrator.Prologue(context.Background(), townRoot, opts)
+	if errors.Is(err, narrator.ErrStoryBegun) || errors.Is(err, narrator.ErrNoHistory) {
+		fmt.Printf("%s %v\n", style.Dim.Render("○"), err)
+		return nil
+	}
+	var quotaErr *narrator.QuotaError
+	if errors.As(err, &quotaErr) {
+		fmt.Printf("%s %v\n", style.Warning.Render("⚠"), err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("writing prologue: %w", err)
+	}
+
+	if narratorProloJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(chapter)
+	}
+	fmt.Printf("%s Wrote the prologue: %s\n", style.Success.Render("✓"), chapter.Path)
+	fmt.Printf("  %d commit(s) in %s, %d words, style %s, narrated by %s\n",
+		chapter.Events, strings.Join(chapter.Rigs, ", "), chapter.Words, chapter.Style, chapter.Agent)
+	if len(chapter.Threads) > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Storylines: "+strings.Join(chapter.Threads, "; ")))
+	}
+	if chapter.Commit != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Committed "+chapter.Commit[:8]+" to the narrative branch"))
+	}
+	if chapter.Site != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Published to "+chapter.Site))
+	}
+	if len(chapter.Issues) > 0 {
+		printLintIssues(chapter.Path, chapter.Issues)
+	}
+	return nil
+}
+
+func runNarratorPending(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	pending, err := narrator.Pending(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if narratorPendingJSON {
+		if pending == nil {
+			pending = []narrator.ChapterRecord{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pending)
+	}
+	if len(pending) == 0 {
+		fmt.Printf("%s No chapters awaiting review\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, rec := range pending {
+		fmt.Printf("%s %s\n", style.Bold.Render(narrator.ChapterID(rec)), style.Dim.Render(fmt.Sprintf("(%d event(s), %s to %s)",
+			rec.Events, rec.Since.Local().Format("2006-01-02 15:04"), rec.Until.Local().Format("2006-01-02 15:04"))))
+		fmt.Printf("  %s\n", rec.Path)
+	}
+	return nil
+}
+
+func runNarratorApprove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rec, site, err := narrator.Approve(townRoot, args[0], "human")
+	if err != nil && rec == nil {
+		return err
+	}
+	fmt.Printf("%s Approved %s\n", style.Success.Render("✓"), narrator.ChapterID(*rec))
+	if site != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("Published to "+site))
+	} else if err == nil {
+		fmt.Printf("  %s\n", style.Dim.Render("Published at the next 'gt narrator publish'"))
+	}
+	return err
+}
+
+func runNarratorReject(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rec, err := narrator.Reject(townRoot, args[0], "human", narratorRejectReason)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Rejected %s; it won't be published\n", style.Bold.Render("✓"), narrator.ChapterID(*rec))
+	return nil
+}
+
+func runNarratorCommentary(cmd *cobra.Command, args []string) error {
+	if err := events.ValidSignificance(narratorCommentarySignificance); err != nil {
+		return &usageError{err: err}
+	}
+	if narratorCommentaryTarget != "" && !narratorCommentaryPane {
+		return &usageError{err: fmt.Errorf("--target needs --pane")}
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if narratorCommentaryPane {
+		pane, err := narrator.NewManager(townRoot).OpenCommentary(narratorCommentaryTarget, narratorCommentarySignificance)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Commentary running in pane %s\n", style.Success.Render("✓"), pane)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	comments, err := narrator.Commentary(ctx, townRoot, narratorCommentarySignificance)
+	if err != nil {
+		return fmt.Errorf("starting commentary: %w", err)
+	}
+	if !narratorCommentaryJSON {
+		fmt.Printf("%s\n", style.Dim.Render("Live from "+filepath.Base(townRoot)+"... (Ctrl-C to stop)"))
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for c := range comments {
+		if narratorCommentaryJSON {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+			continue
+		}
+		text := c.Text
+		switch c.Level {
+		case events.SignificanceHigh:
+			text = style.Error.Render(text)
+		case events.SignificanceMedium:
+			text = style.Info.Render(text)
+		default:
+			text = style.Dim.Render(text)
+		}
+		fmt.Printf("%s %s\n", style.Dim.Render(c.At.Local().Format("15:04:05")), text)
+	}
+	return nil
+}
+
+func runNarratorServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	addr := narratorServeAddr
+	if addr == "" {
+		addr = narrator.TriggerAddr(townRoot)
+	}
+
+	fmt.Printf("%s Narrating on demand at http://%s/narrate\n", style.Success.Render("✓"), addr)
+	fmt.Printf("   Press Ctrl+C to stop\n")
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           narrator.TriggerHandler(townRoot),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		// A POST waits for the narrating agent
+		WriteTimeout: 30 * time.Minute,
+		IdleTimeout:  120 * time.Second,
+	}
+	return server.ListenAndServe()
+}
@@ -11,21 +11,34 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var nudgeMessageFlag string
 var nudgeForceFlag bool
+var nudgeTemplateFlag string
+var nudgeVarFlags []string
+var nudgeListTemplatesFlag bool
+var nudgeRoleFlags []string
+var nudgeRigFlag string
+var nudgeDryRunFlag bool
 
 func init() {
 	rootCmd.AddCommand(nudgeCmd)
 	nudgeCmd.Flags().StringVarP(&nudgeMessageFlag, "message", "m", "", "Message to send")
 	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled")
+	nudgeCmd.Flags().StringVarP(&nudgeTemplateFlag, "template", "t", "", "Render the message from a nudge template")
+	nudgeCmd.Flags().StringArrayVar(&nudgeVarFlags, "var", nil, "Template variable as key=value (repeatable)")
+	nudgeCmd.Flags().BoolVar(&nudgeListTemplatesFlag, "list-templates", false, "List available nudge templates")
+	nudgeCmd.Flags().StringSliceVar(&nudgeRoleFlags, "role", nil, "Nudge every running session with this role instead of a target (repeatable)")
+	nudgeCmd.Flags().StringVar(&nudgeRigFlag, "rig", "", "Nudge every running session in rigs matching this glob instead of a target")
+	nudgeCmd.Flags().BoolVar(&nudgeDryRunFlag, "dry-run", false, "With --role/--rig, show who would be nudged and with what")
 }
 
 var nudgeCmd = &cobra.Command{
-	Use:     "nudge <target> [message]",
+	Use:     "nudge [<target>] [message]",
 	GroupID: GroupComm,
 	Short:   "Send a synchronous message to any Gas Town worker",
 	Long: `Universal synchronous messaging API for Gas Town worker-to-worker communication.
@@ -53,6 +66,20 @@ Channel syntax:
                   ~/gt/config/messaging.json under "nudge_channels".
                   Patterns like "gastown/polecats/*" are expanded.
 
+Templates:
+  --template renders the message from nudges/<name>.tmpl in the town root,
+  falling back to the built-in templates (gt nudge --list-templates).
+  Templates are Go text/templates with variables role, rig and target
+  (derived from the target), reason, and anything set with --var.
+
+Broadcast:
+  --role and --rig replace the target with every running session matching
+  them: --role takes mayor, deacon, witness, refinery, crew or polecat
+  (repeatable), and --rig a glob over rig names. The message is a Go
+  text/template rendered per session with {{.Role}}, {{.Rig}}, {{.Polecat}},
+  {{.Target}} and {{.Session}}; with --template, polecat is added to the
+  template's variables. --dry-run shows each rendered message unsent.
+
 DND (Do Not Disturb):
   If the target has DND enabled (gt dnd on), the nudge is skipped.
   Use --force to override DND and send anyway.
@@ -63,17 +90,41 @@ Examples:
   gt nudge mayor "Status update requested"
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
-  gt nudge channel:workers "New priority work available"`,
-	Args: cobra.RangeArgs(1, 2),
+  gt nudge channel:workers "New priority work available"
+  gt nudge greenplace/furiosa --template stuck-merge --var bead=gt-42
+  gt nudge --role polecat --rig gastown "wrap up, merge window opens in 10m"
+  gt nudge --role polecat,crew --rig 'gas*' "{{.Polecat}}: {{.Rig}} merges in 10m" --dry-run`,
+	Args: cobra.RangeArgs(0, 2),
 	RunE: runNudge,
 }
 
 func runNudge(cmd *cobra.Command, args []string) error {
+	if nudgeListTemplatesFlag {
+		return runNudgeListTemplates()
+	}
+	if len(nudgeRoleFlags) > 0 || nudgeRigFlag != "" {
+		return runNudgeBroadcast(args)
+	}
+	if nudgeDryRunFlag {
+		return fmt.Errorf("--dry-run only applies with --role or --rig")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("target required: gt nudge <target> [message]")
+	}
 	target := args[0]
 
-	// Get message from -m flag or positional arg
+	// Get message from --template, -m flag or positional arg
 	var message string
-	if nudgeMessageFlag != "" {
+	if nudgeTemplateFlag != "" {
+		if nudgeMessageFlag != "" || len(args) >= 2 {
+			return fmt.Errorf("--template cannot be combined with a message")
+		}
+		rendered, err := renderNudgeTemplate(target, nudgeTemplateFlag, nudgeVarFlags)
+		if err != nil {
+			return err
+		}
+		message = rendered
+	} else if nudgeMessageFlag != "" {
 		message = nudgeMessageFlag
 	} else if len(args) >= 2 {
 		message = args[1]
@@ -88,25 +139,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 	}
 
 	// Identify sender for message prefix
-	sender := "unknown"
-	if roleInfo, err := GetRole(); err == nil {
-		switch roleInfo.Role {
-		case RoleMayor:
-			sender = "mayor"
-		case RoleCrew:
-			sender = fmt.Sprintf("%s/crew/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RolePolecat:
-			sender = fmt.Sprintf("%s/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RoleWitness:
-			sender = fmt.Sprintf("%s/witness", roleInfo.Rig)
-		case RoleRefinery:
-			sender = fmt.Sprintf("%s/refinery", roleInfo.Rig)
-		case RoleDeacon:
-			sender = "deacon"
-		default:
-			sender = string(roleInfo.Role)
-		}
-	}
+	sender := nudgeSender()
 
 	// Prefix message with sender
 	message = fmt.Sprintf("[from %s] %s", sender, message)
@@ -235,6 +268,97 @@ func runNudge(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// nudgeSender returns the address nudges from this session are signed
+// with.
+func nudgeSender() string {
+	roleInfo, err := GetRole()
+	if err != nil {
+		return "unknown"
+	}
+	switch roleInfo.Role {
+	case RoleMayor:
+		return "mayor"
+	case RoleCrew:
+		return fmt.Sprintf("%s/crew/%s", roleInfo.Rig, roleInfo.Polecat)
+	case RolePolecat:
+		return fmt.Sprintf("%s/%s", roleInfo.Rig, roleInfo.Polecat)
+	case RoleWitness:
+		return fmt.Sprintf("%s/witness", roleInfo.Rig)
+	case RoleRefinery:
+		return fmt.Sprintf("%s/refinery", roleInfo.Rig)
+	case RoleDeacon:
+		return "deacon"
+	default:
+		return string(roleInfo.Role)
+	}
+}
+
+// runNudgeListTemplates prints the available nudge templates.
+func runNudgeListTemplates() error {
+	townRoot, _ := workspace.FindFromCwd()
+	names, err := templates.NudgeTemplateNames(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing nudge templates: %w", err)
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// renderNudgeTemplate renders a nudge template for target. The role, rig
+// and target variables come from the target address; vars (key=value) add
+// to or override them.
+func renderNudgeTemplate(target, name string, vars []string) (string, error) {
+	return renderNudgeTemplateVars(nudgeTemplateVars(target), name, vars)
+}
+
+// renderNudgeTemplateVars renders a nudge template with data, to which vars
+// (key=value) add or override.
+func renderNudgeTemplateVars(data map[string]string, name string, vars []string) (string, error) {
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return "", fmt.Errorf("invalid --var %q: expected key=value", v)
+		}
+		data[strings.TrimSpace(key)] = value
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	message, err := templates.RenderNudge(townRoot, name, data)
+	if err != nil {
+		return "", fmt.Errorf("%w (set template variables with --var key=value)", err)
+	}
+	return message, nil
+}
+
+// nudgeTemplateVars returns the template variables implied by a nudge target.
+func nudgeTemplateVars(target string) map[string]string {
+	vars := map[string]string{"target": target, "role": "", "rig": "", "reason": ""}
+	switch {
+	case target == "mayor" || target == "deacon":
+		vars["role"] = target
+	case target == "witness" || target == "refinery":
+		vars["role"] = target
+		if roleInfo, err := GetRole(); err == nil {
+			vars["rig"] = roleInfo.Rig
+		}
+	case strings.HasPrefix(target, "channel:"):
+	case strings.Contains(target, "/"):
+		rigName, rest, _ := strings.Cut(target, "/")
+		vars["rig"] = rigName
+		switch {
+		case strings.HasPrefix(rest, "crew/"):
+			vars["role"] = "crew"
+		case rest == "witness" || rest == "refinery":
+			vars["role"] = rest
+		default:
+			vars["role"] = "polecat"
+		}
+	}
+	return vars
+}
+
 // runNudgeChannel nudges all members of a named channel.
 func runNudgeChannel(channelName, message string) error {
 	// Find town root
@@ -261,25 +385,7 @@ func runNudgeChannel(channelName, message string) error {
 	}
 
 	// Identify sender for message prefix
-	sender := "unknown"
-	if roleInfo, err := GetRole(); err == nil {
-		switch roleInfo.Role {
-		case RoleMayor:
-			sender = "mayor"
-		case RoleCrew:
-			sender = fmt.Sprintf("%s/crew/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RolePolecat:
-			sender = fmt.Sprintf("%s/%s", roleInfo.Rig, roleInfo.Polecat)
-		case RoleWitness:
-			sender = fmt.Sprintf("%s/witness", roleInfo.Rig)
-		case RoleRefinery:
-			sender = fmt.Sprintf("%s/refinery", roleInfo.Rig)
-		case RoleDeacon:
-			sender = "deacon"
-		default:
-			sender = string(roleInfo.Role)
-		}
-	}
+	sender := nudgeSender()
 
 	// Prefix message with sender
 	prefixedMessage := fmt.Sprintf("[from %s] %s", sender, message)
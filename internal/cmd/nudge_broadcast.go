@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// nudgeRoles maps the role names accepted by --role to agent types.
+var nudgeRoles = map[string]AgentType{
+	"mayor":    AgentMayor,
+	"deacon":   AgentDeacon,
+	"witness":  AgentWitness,
+	"refinery": AgentRefinery,
+	"crew":     AgentCrew,
+	"polecat":  AgentPolecat,
+}
+
+// nudgeTarget is the data a broadcast message is rendered with for each
+// session it goes to.
+type nudgeTarget struct {
+	Role    string // mayor, deacon, witness, refinery, crew or polecat
+	Rig     string // empty for mayor and deacon
+	Polecat string // polecat or crew name
+	Target  string // address, e.g. gastown/Toast
+	Session string // tmux session name
+}
+
+func newNudgeTarget(agent *AgentSession) nudgeTarget {
+	t := nudgeTarget{
+		Rig:     agent.Rig,
+		Polecat: agent.AgentName,
+		Target:  formatAgentName(agent),
+		Session: agent.Name,
+	}
+	for role, typ := range nudgeRoles {
+		if typ == agent.Type {
+			t.Role = role
+		}
+	}
+	return t
+}
+
+// matchNudgeTargets returns the agents with one of roles (any role if
+// none) in a rig matching rigGlob (any rig if empty). Mayor and deacon
+// have no rig, so a rig glob excludes them.
+func matchNudgeTargets(agents []*AgentSession, roles []string, rigGlob string) ([]*AgentSession, error) {
+	types := make(map[AgentType]bool)
+	for _, role := range roles {
+		typ, ok := nudgeRoles[strings.TrimSpace(role)]
+		if !ok {
+			return nil, fmt.Errorf("unknown role %q (want mayor, deacon, witness, refinery, crew or polecat)", role)
+		}
+		types[typ] = true
+	}
+	if rigGlob != "" {
+		if _, err := path.Match(rigGlob, ""); err != nil {
+			return nil, fmt.Errorf("invalid --rig pattern %q: %w", rigGlob, err)
+		}
+	}
+
+	var matched []*AgentSession
+	for _, agent := range agents {
+		if len(types) > 0 && !types[agent.Type] {
+			continue
+		}
+		if rigGlob != "" {
+			if agent.Rig == "" {
+				continue
+			}
+			if ok, _ := path.Match(rigGlob, agent.Rig); !ok {
+				continue
+			}
+		}
+		matched = append(matched, agent)
+	}
+	return matched, nil
+}
+
+// parseNudgeMessage parses a broadcast message as a text/template over
+// nudgeTarget, e.g. "{{.Polecat}}: merge window opens in 10m".
+func parseNudgeMessage(message string) (*template.Template, error) {
+	tmpl, err := template.New("nudge").Option("missingkey=error").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("parsing message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderNudgeMessage renders a broadcast message for one target. A
+// --template is rendered like a single nudge's, with polecat added to its
+// variables; otherwise message is executed against the target.
+func renderNudgeMessage(tmpl *template.Template, target nudgeTarget) (string, error) {
+	if nudgeTemplateFlag != "" {
+		vars := nudgeTemplateVars(target.Target)
+		vars["role"] = target.Role
+		vars["rig"] = target.Rig
+		vars["polecat"] = target.Polecat
+		return renderNudgeTemplateVars(vars, nudgeTemplateFlag, nudgeVarFlags)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, target); err != nil {
+		return "", fmt.Errorf("rendering message for %s: %w", target.Target, err)
+	}
+	return buf.String(), nil
+}
+
+// runNudgeBroadcast nudges every running session matching --role and
+// --rig, rendering the message for each.
+func runNudgeBroadcast(args []string) error {
+	var message string
+	switch {
+	case nudgeTemplateFlag != "":
+		if nudgeMessageFlag != "" || len(args) > 0 {
+			return fmt.Errorf("--template cannot be combined with a message")
+		}
+	case nudgeMessageFlag != "":
+		if len(args) > 0 {
+			return fmt.Errorf("--role and --rig replace the target: use -m or a positional message, not both")
+		}
+		message = nudgeMessageFlag
+	case len(args) == 1:
+		message = args[0]
+	case len(args) > 1:
+		return fmt.Errorf("--role and --rig replace the target: gt nudge --role <role> [--rig <glob>] <message>")
+	default:
+		return fmt.Errorf("message required: use -m flag or provide as an argument")
+	}
+	tmpl, err := parseNudgeMessage(message)
+	if err != nil {
+		return err
+	}
+
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+	matched, err := matchNudgeTargets(agents, nudgeRoleFlags, nudgeRigFlag)
+	if err != nil {
+		return err
+	}
+
+	// Skip self to avoid interrupting own session
+	self := os.Getenv("BD_ACTOR")
+	sender := nudgeSender()
+	townRoot, _ := workspace.FindFromCwd()
+	var targets []*AgentSession
+	for _, agent := range matched {
+		addr := formatAgentName(agent)
+		if addr == self || addr == sender {
+			continue
+		}
+		if townRoot != "" && !nudgeForceFlag {
+			if ok, level, _ := shouldNudgeTarget(townRoot, addr, false); !ok {
+				fmt.Printf("%s %s has DND enabled (%s) - skipped\n", style.Dim.Render("○"), addr, level)
+				continue
+			}
+		}
+		targets = append(targets, agent)
+	}
+	if len(targets) == 0 {
+		fmt.Println("No running sessions match --role/--rig.")
+		return nil
+	}
+
+	// Render every message before sending any, so a template error doesn't
+	// leave the broadcast half delivered
+	messages := make([]string, len(targets))
+	for i, agent := range targets {
+		if messages[i], err = renderNudgeMessage(tmpl, newNudgeTarget(agent)); err != nil {
+			return err
+		}
+	}
+
+	if nudgeDryRunFlag {
+		fmt.Printf("Would nudge %d agent(s):\n\n", len(targets))
+		for i, agent := range targets {
+			fmt.Printf("  %s %s: %s\n", AgentTypeIcons[agent.Type], formatAgentName(agent), messages[i])
+		}
+		return nil
+	}
+
+	t := tmux.NewTmux()
+	var succeeded, failed int
+	var failures []string
+
+	fmt.Printf("Nudging %d agent(s)...\n\n", len(targets))
+
+	for i, agent := range targets {
+		addr := formatAgentName(agent)
+		prefixed := fmt.Sprintf("[from %s] %s", sender, messages[i])
+		if err := t.NudgeSession(agent.Name, prefixed); err != nil {
+			failed++
+			failures = append(failures, fmt.Sprintf("%s: %v", addr, err))
+			fmt.Printf("  %s %s %s\n", style.ErrorPrefix, AgentTypeIcons[agent.Type], addr)
+		} else {
+			succeeded++
+			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, AgentTypeIcons[agent.Type], addr)
+			if townRoot != "" {
+				_ = LogNudge(townRoot, addr, prefixed)
+			}
+			_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload(agent.Rig, addr, prefixed))
+		}
+
+		// Small delay between nudges to avoid overwhelming tmux
+		if i < len(targets)-1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%s Nudge complete: %d succeeded, %d failed\n",
+			style.WarningPrefix, succeeded, failed)
+		for _, f := range failures {
+			fmt.Printf("  %s\n", style.Dim.Render(f))
+		}
+		return fmt.Errorf("%d nudge(s) failed", failed)
+	}
+
+	fmt.Printf("%s Nudge complete: %d agent(s) nudged\n", style.SuccessPrefix, succeeded)
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -110,3 +111,91 @@ func TestResolveNudgePattern(t *testing.T) {
 		})
 	}
 }
+
+func TestNudgeTemplateVars(t *testing.T) {
+	tests := []struct {
+		target, role, rig string
+	}{
+		{"mayor", "mayor", ""},
+		{"greenplace/furiosa", "polecat", "greenplace"},
+		{"greenplace/crew/max", "crew", "greenplace"},
+		{"greenplace/witness", "witness", "greenplace"},
+		{"channel:workers", "", ""},
+	}
+	for _, tt := range tests {
+		vars := nudgeTemplateVars(tt.target)
+		if vars["role"] != tt.role || vars["rig"] != tt.rig || vars["target"] != tt.target {
+			t.Errorf("nudgeTemplateVars(%q) = %v, want role=%q rig=%q", tt.target, vars, tt.role, tt.rig)
+		}
+		if _, ok := vars["reason"]; !ok {
+			t.Errorf("nudgeTemplateVars(%q) missing optional reason", tt.target)
+		}
+	}
+}
+
+func TestMatchNudgeTargets(t *testing.T) {
+	agents := []*AgentSession{
+		{Name: "hq-mayor", Type: AgentMayor},
+		{Name: "gt-gastown-witness", Type: AgentWitness, Rig: "gastown"},
+		{Name: "gt-gastown-alpha", Type: AgentPolecat, Rig: "gastown", AgentName: "alpha"},
+		{Name: "gt-gasworks-beta", Type: AgentPolecat, Rig: "gasworks", AgentName: "beta"},
+		{Name: "gt-beads-gamma", Type: AgentPolecat, Rig: "beads", AgentName: "gamma"},
+	}
+	names := func(matched []*AgentSession) []string {
+		var out []string
+		for _, a := range matched {
+			out = append(out, a.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		roles    []string
+		rig      string
+		expected []string
+	}{
+		{[]string{"polecat"}, "gastown", []string{"gt-gastown-alpha"}},
+		{[]string{"polecat"}, "gas*", []string{"gt-gastown-alpha", "gt-gasworks-beta"}},
+		{nil, "gastown", []string{"gt-gastown-witness", "gt-gastown-alpha"}},
+		{[]string{"mayor", "witness"}, "", []string{"hq-mayor", "gt-gastown-witness"}},
+	}
+	for _, tt := range tests {
+		matched, err := matchNudgeTargets(agents, tt.roles, tt.rig)
+		if err != nil {
+			t.Fatalf("matchNudgeTargets(%v, %q): %v", tt.roles, tt.rig, err)
+		}
+		if got := names(matched); strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("matchNudgeTargets(%v, %q) = %v, want %v", tt.roles, tt.rig, got, tt.expected)
+		}
+	}
+
+	if _, err := matchNudgeTargets(agents, []string{"dog"}, ""); err == nil {
+		t.Error("unknown role should be rejected")
+	}
+	if _, err := matchNudgeTargets(agents, nil, "[gas"); err == nil {
+		t.Error("malformed rig glob should be rejected")
+	}
+}
+
+func TestRenderNudgeMessage(t *testing.T) {
+	tmpl, err := parseNudgeMessage("{{.Polecat}} in {{.Rig}}: wrap up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := newNudgeTarget(&AgentSession{Name: "gt-gastown-alpha", Type: AgentPolecat, Rig: "gastown", AgentName: "alpha"})
+	if target.Role != "polecat" || target.Target != "gastown/alpha" {
+		t.Errorf("target = %+v", target)
+	}
+	got, err := renderNudgeMessage(tmpl, target)
+	if err != nil || got != "alpha in gastown: wrap up" {
+		t.Errorf("renderNudgeMessage = %q, %v", got, err)
+	}
+
+	if _, err := parseNudgeMessage("{{.Polecat"); err == nil {
+		t.Error("malformed message template should be rejected")
+	}
+	tmpl, _ = parseNudgeMessage("{{.Bead}}")
+	if _, err := renderNudgeMessage(tmpl, target); err == nil {
+		t.Error("unknown field should fail to render")
+	}
+}
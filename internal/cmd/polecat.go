@@ -154,6 +154,8 @@ var (
 	polecatNukeAll           bool
 	polecatNukeDryRun        bool
 	polecatNukeForce         bool
+	polecatNukeYes           bool
+	polecatNukeDrainTimeout  time.Duration
 	polecatCheckRecoveryJSON bool
 )
 
@@ -193,7 +195,11 @@ SAFETY CHECKS: The command refuses to nuke a polecat if:
   - Polecat has an open merge request (MR bead)
   - Polecat has work on its hook
 
-Use --force to bypass safety checks (LOSES WORK).
+Before the safety checks, running polecats with hooked work are drained:
+nudged to commit and hand off, then given --drain-timeout (default: the
+town's drain_timeout setting, 2m) to release their hook.
+
+Use --force to skip the drain and bypass safety checks (LOSES WORK).
 Use --dry-run to see what would happen and safety check status.
 
 Examples:
@@ -302,6 +308,8 @@ func init() {
 	polecatNukeCmd.Flags().BoolVar(&polecatNukeAll, "all", false, "Nuke all polecats in the rig")
 	polecatNukeCmd.Flags().BoolVar(&polecatNukeDryRun, "dry-run", false, "Show what would be nuked without doing it")
 	polecatNukeCmd.Flags().BoolVarP(&polecatNukeForce, "force", "f", false, "Force nuke, bypassing all safety checks (LOSES WORK)")
+	polecatNukeCmd.Flags().BoolVarP(&polecatNukeYes, "yes", "y", false, "Skip the confirmation prompt")
+	polecatNukeCmd.Flags().DurationVar(&polecatNukeDrainTimeout, "drain-timeout", 0, "How long polecats with hooked work get to hand off (default: town drain_timeout, 2m)")
 
 	// Check-recovery flags
 	polecatCheckRecoveryCmd.Flags().BoolVar(&polecatCheckRecoveryJSON, "json", false, "Output as JSON")
@@ -1144,6 +1152,17 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	t := tmux.NewTmux()
+	if !polecatNukeDryRun && !confirmDestructive(polecatNukePlan(t, targets), false, polecatNukeYes) {
+		return fmt.Errorf("nuke canceled")
+	}
+
+	// Drain: give running polecats with hooked work a window to hand off
+	// before the safety checks below (skipped with --force)
+	if !polecatNukeForce && !polecatNukeDryRun {
+		drainPolecats(t, targets, drainWindow(filepath.Dir(targets[0].r.Path), polecatNukeDrainTimeout))
+	}
+
 	// Safety checks: refuse to nuke polecats with active work unless --force is set
 	if !polecatNukeForce && !polecatNukeDryRun {
 		var blocked []*SafetyCheckResult
@@ -1161,7 +1180,6 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 	}
 
 	// Nuke each polecat
-	t := tmux.NewTmux()
 	var nukeErrors []string
 	nuked := 0
 
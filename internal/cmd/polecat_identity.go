@@ -25,6 +25,8 @@ var (
 	polecatIdentityListJSON    bool
 	polecatIdentityShowJSON    bool
 	polecatIdentityRemoveForce bool
+	polecatIdentitySetBible    string
+	polecatIdentitySetPrefs    []string
 )
 
 var polecatIdentityCmd = &cobra.Command{
@@ -137,6 +139,23 @@ Example:
 	RunE: runPolecatIdentityRemove,
 }
 
+var polecatIdentitySetCmd = &cobra.Command{
+	Use:   "set <rig> <name>",
+	Short: "Set a polecat's character bible entry or preferences",
+	Long: `Set fields on a polecat's persistent identity.
+
+Identities outlive individual polecats: a polecat spawned under a name
+inherits that name's history, character bible entry, and preferences.
+
+A preference with an empty value is removed.
+
+Example:
+  gt polecat identity set gastown Toast --bible "Terse, fond of tests"
+  gt polecat identity set gastown Toast --pref editor=vim --pref style=`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPolecatIdentitySet,
+}
+
 func init() {
 	// List flags
 	polecatIdentityListCmd.Flags().BoolVar(&polecatIdentityListJSON, "json", false, "Output as JSON")
@@ -147,12 +166,17 @@ func init() {
 	// Remove flags
 	polecatIdentityRemoveCmd.Flags().BoolVarP(&polecatIdentityRemoveForce, "force", "f", false, "Force removal, bypassing safety checks")
 
+	// Set flags
+	polecatIdentitySetCmd.Flags().StringVar(&polecatIdentitySetBible, "bible", "", "Character bible entry")
+	polecatIdentitySetCmd.Flags().StringArrayVar(&polecatIdentitySetPrefs, "pref", nil, "Preference as key=value (repeatable)")
+
 	// Add subcommands to identity
 	polecatIdentityCmd.AddCommand(polecatIdentityAddCmd)
 	polecatIdentityCmd.AddCommand(polecatIdentityListCmd)
 	polecatIdentityCmd.AddCommand(polecatIdentityShowCmd)
 	polecatIdentityCmd.AddCommand(polecatIdentityRenameCmd)
 	polecatIdentityCmd.AddCommand(polecatIdentityRemoveCmd)
+	polecatIdentityCmd.AddCommand(polecatIdentitySetCmd)
 
 	// Add identity to polecat command
 	polecatCmd.AddCommand(polecatIdentityCmd)
@@ -410,14 +434,21 @@ func runPolecatIdentityShow(cmd *cobra.Command, args []string) error {
 	// Build CV summary with enhanced analytics
 	cv := buildCVSummary(r.Path, rigName, polecatName, beadID, clonePath)
 
+	// Persistent identity (history across spawns)
+	history, err := polecat.GetIdentity(r.Path, polecatName)
+	if err != nil {
+		style.PrintWarning("could not read polecat identity: %v", err)
+	}
+
 	// JSON output - include both identity details and CV
 	if polecatIdentityShowJSON {
 		output := struct {
 			IdentityInfo
-			Title     string     `json:"title"`
-			CreatedAt string     `json:"created_at,omitempty"`
-			UpdatedAt string     `json:"updated_at,omitempty"`
-			CV        *CVSummary `json:"cv,omitempty"`
+			Title     string            `json:"title"`
+			CreatedAt string            `json:"created_at,omitempty"`
+			UpdatedAt string            `json:"updated_at,omitempty"`
+			CV        *CVSummary        `json:"cv,omitempty"`
+			History   *polecat.Identity `json:"history,omitempty"`
 		}{
 			IdentityInfo: IdentityInfo{
 				Rig:            rigName,
@@ -433,6 +464,7 @@ func runPolecatIdentityShow(cmd *cobra.Command, args []string) error {
 			CreatedAt: issue.CreatedAt,
 			UpdatedAt: issue.UpdatedAt,
 			CV:        cv,
+			History:   history,
 		}
 		if output.HookBead == "" {
 			output.HookBead = fields.HookBead
@@ -501,6 +533,11 @@ func runPolecatIdentityShow(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Updated:       %s\n", style.Dim.Render(issue.UpdatedAt))
 	}
 
+	// Persistent identity
+	if history != nil {
+		printIdentityHistory(history)
+	}
+
 	// CV Summary section with enhanced analytics
 	fmt.Printf("\n%s\n", style.Bold.Render("CV Summary:"))
 	fmt.Printf("  Sessions:         %d\n", cv.Sessions)
@@ -611,6 +648,11 @@ func runPolecatIdentityRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("closing old identity bead: %w", err)
 	}
 
+	// Carry the persistent identity over to the new name
+	if err := polecat.RenameIdentity(r.Path, oldName, newName); err != nil {
+		style.PrintWarning("could not rename polecat identity history: %v", err)
+	}
+
 	fmt.Printf("%s Renamed identity:\n", style.SuccessPrefix)
 	fmt.Printf("  Old: %s\n", oldBeadID)
 	fmt.Printf("  New: %s\n", newBeadID)
@@ -703,6 +745,78 @@ func runPolecatIdentityRemove(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runPolecatIdentitySet(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	polecatName := args[1]
+
+	if !cmd.Flags().Changed("bible") && len(polecatIdentitySetPrefs) == 0 {
+		return fmt.Errorf("nothing to set: use --bible or --pref")
+	}
+
+	prefs := make(map[string]string, len(polecatIdentitySetPrefs))
+	for _, pref := range polecatIdentitySetPrefs {
+		key, value, ok := strings.Cut(pref, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid preference %q: expected key=value", pref)
+		}
+		prefs[strings.TrimSpace(key)] = value
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	identity, err := polecat.UpdateIdentity(r.Path, polecatName, func(id *polecat.Identity) {
+		if cmd.Flags().Changed("bible") {
+			id.Bible = polecatIdentitySetBible
+		}
+		for key, value := range prefs {
+			if value == "" {
+				delete(id.Preferences, key)
+				continue
+			}
+			if id.Preferences == nil {
+				id.Preferences = make(map[string]string)
+			}
+			id.Preferences[key] = value
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("updating polecat identity: %w", err)
+	}
+
+	fmt.Printf("%s Updated identity %s/%s\n", style.SuccessPrefix, rigName, polecatName)
+	printIdentityHistory(identity)
+	return nil
+}
+
+// printIdentityHistory prints the persistent part of a polecat's identity.
+func printIdentityHistory(id *polecat.Identity) {
+	fmt.Printf("\n%s\n", style.Bold.Render("History:"))
+	if id.Spawns > 0 {
+		fmt.Printf("  Spawns:        %d (first %s, last %s)\n", id.Spawns,
+			id.FirstSpawn.Local().Format("2006-01-02"), id.LastSpawn.Local().Format("2006-01-02"))
+	}
+	if id.Theme != "" {
+		fmt.Printf("  Theme:         %s\n", id.Theme)
+	}
+	fmt.Printf("  Beads done:    %d\n", id.CompletedCount())
+	if id.Bible != "" {
+		fmt.Printf("  Bible:         %s\n", id.Bible)
+	}
+	if len(id.Preferences) > 0 {
+		keys := make([]string, 0, len(id.Preferences))
+		for k := range id.Preferences {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("  Pref:          %s=%s\n", k, id.Preferences[k])
+		}
+	}
+}
+
 // buildCVSummary constructs the CV summary for a polecat.
 // Returns a partial CV on errors rather than failing - CV data is best-effort.
 func buildCVSummary(rigPath, rigName, polecatName, identityBeadID, clonePath string) *CVSummary {
@@ -109,6 +109,14 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("getting polecat: %w", err)
 	}
 
+	// Carry the name's identity (history, character bible) into this spawn
+	if identity, err := polecatMgr.RecordSpawn(polecatName); err != nil {
+		style.PrintWarning("could not record polecat identity: %v", err)
+	} else if identity.Spawns > 1 {
+		fmt.Printf("Returning polecat %s: spawn #%d, %d bead(s) completed\n",
+			polecatName, identity.Spawns, identity.CompletedCount())
+	}
+
 	// Get polecat object for path info
 	polecatObj, err := polecatMgr.Get(polecatName)
 	if err != nil {
@@ -157,7 +165,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	fmt.Printf("%s Polecat %s spawned\n", style.Bold.Render("✓"), polecatName)
 
 	// Log spawn event to activity feed
-	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
+	_ = events.LogFeed(events.TypeSpawn, "gt", events.WithWork(events.SpawnPayload(rigName, polecatName), opts.HookBead))
 
 	return &SpawnedPolecatInfo{
 		RigName:     rigName,
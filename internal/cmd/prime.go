@@ -114,7 +114,7 @@ func runPrime(cmd *cobra.Command, args []string) error {
 		if !state.IsEnabled() {
 			return nil // Silent exit - not in workspace and not enabled
 		}
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	// Handle hook mode: read session ID from stdin and persist it
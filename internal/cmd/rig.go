@@ -143,9 +143,12 @@ var rigRebootCmd = &cobra.Command{
 This is equivalent to 'gt rig shutdown' followed by 'gt rig boot'.
 Useful after polecats complete work and land their changes.
 
+Polecats with hooked work are drained first, as in 'gt rig shutdown'.
+
 Examples:
   gt rig reboot greenplace
-  gt rig reboot beads --force`,
+  gt rig reboot beads --force
+  gt rig reboot beads --dry-run`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRigReboot,
 }
@@ -165,7 +168,11 @@ Before shutdown, checks all polecats for uncommitted work:
 - Stashes
 - Unpushed commits
 
-Use --force to skip graceful shutdown and kill immediately.
+Polecats with work on their hook are drained first: nudged to commit and
+hand off, then given --drain-timeout (default: the town's drain_timeout
+setting, 2m) before their sessions are stopped.
+
+Use --force to skip the drain and graceful shutdown and kill immediately.
 Use --nuclear to bypass ALL safety checks (will lose work!).
 
 Examples:
@@ -214,7 +221,11 @@ Before shutdown, checks all polecats for uncommitted work:
 - Stashes
 - Unpushed commits
 
-Use --force to skip graceful shutdown and kill immediately.
+Polecats with work on their hook are drained first: nudged to commit and
+hand off, then given --drain-timeout (default: the town's drain_timeout
+setting, 2m) before their sessions are stopped.
+
+Use --force to skip the drain and graceful shutdown and kill immediately.
 Use --nuclear to bypass ALL safety checks (will lose work!).
 
 Examples:
@@ -239,7 +250,11 @@ Before shutdown, checks all polecats for uncommitted work:
 - Stashes
 - Unpushed commits
 
-Use --force to skip graceful shutdown and kill immediately.
+Polecats with work on their hook are drained first: nudged to commit and
+hand off, then given --drain-timeout (default: the town's drain_timeout
+setting, 2m) before their sessions are stopped.
+
+Use --force to skip the drain and graceful shutdown and kill immediately.
 Use --nuclear to bypass ALL safety checks (will lose work!).
 
 Examples:
@@ -263,10 +278,23 @@ var (
 	rigResetRole       string
 	rigShutdownForce   bool
 	rigShutdownNuclear bool
+	rigShutdownDryRun  bool
+	rigShutdownYes     bool
+	rigShutdownDrain   time.Duration
+	rigRebootForce     bool
+	rigRebootDryRun    bool
+	rigRebootYes       bool
+	rigRebootDrain     time.Duration
 	rigStopForce       bool
 	rigStopNuclear     bool
+	rigStopDrain       time.Duration
+	rigStopDryRun      bool
+	rigStopYes         bool
 	rigRestartForce    bool
 	rigRestartNuclear  bool
+	rigRestartDrain    time.Duration
+	rigRestartDryRun   bool
+	rigRestartYes      bool
 )
 
 func init() {
@@ -295,14 +323,26 @@ func init() {
 
 	rigShutdownCmd.Flags().BoolVarP(&rigShutdownForce, "force", "f", false, "Force immediate shutdown")
 	rigShutdownCmd.Flags().BoolVar(&rigShutdownNuclear, "nuclear", false, "DANGER: Bypass ALL safety checks (loses uncommitted work!)")
+	rigShutdownCmd.Flags().BoolVar(&rigShutdownDryRun, "dry-run", false, "Show which sessions would be stopped without stopping them")
+	rigShutdownCmd.Flags().BoolVarP(&rigShutdownYes, "yes", "y", false, "Skip confirmation prompt")
+	rigShutdownCmd.Flags().DurationVar(&rigShutdownDrain, "drain-timeout", 0, "How long polecats with hooked work get to hand off (default: town drain_timeout, 2m)")
 
-	rigRebootCmd.Flags().BoolVarP(&rigShutdownForce, "force", "f", false, "Force immediate shutdown during reboot")
+	rigRebootCmd.Flags().BoolVarP(&rigRebootForce, "force", "f", false, "Force immediate shutdown during reboot")
+	rigRebootCmd.Flags().BoolVar(&rigRebootDryRun, "dry-run", false, "Show which sessions would be stopped without rebooting")
+	rigRebootCmd.Flags().BoolVarP(&rigRebootYes, "yes", "y", false, "Skip confirmation prompt")
+	rigRebootCmd.Flags().DurationVar(&rigRebootDrain, "drain-timeout", 0, "How long polecats with hooked work get to hand off (default: town drain_timeout, 2m)")
 
 	rigStopCmd.Flags().BoolVarP(&rigStopForce, "force", "f", false, "Force immediate shutdown")
 	rigStopCmd.Flags().BoolVar(&rigStopNuclear, "nuclear", false, "DANGER: Bypass ALL safety checks (loses uncommitted work!)")
+	rigStopCmd.Flags().DurationVar(&rigStopDrain, "drain-timeout", 0, "How long polecats with hooked work get to hand off (default: town drain_timeout, 2m)")
+	rigStopCmd.Flags().BoolVar(&rigStopDryRun, "dry-run", false, "Show which sessions would be stopped without stopping them")
+	rigStopCmd.Flags().BoolVarP(&rigStopYes, "yes", "y", false, "Skip confirmation prompt")
 
 	rigRestartCmd.Flags().BoolVarP(&rigRestartForce, "force", "f", false, "Force immediate shutdown during restart")
 	rigRestartCmd.Flags().BoolVar(&rigRestartNuclear, "nuclear", false, "DANGER: Bypass ALL safety checks (loses uncommitted work!)")
+	rigRestartCmd.Flags().DurationVar(&rigRestartDrain, "drain-timeout", 0, "How long polecats with hooked work get to hand off (default: town drain_timeout, 2m)")
+	rigRestartCmd.Flags().BoolVar(&rigRestartDryRun, "dry-run", false, "Show which sessions would be restarted without restarting them")
+	rigRestartCmd.Flags().BoolVarP(&rigRestartYes, "yes", "y", false, "Skip confirmation prompt")
 }
 
 func runRigAdd(cmd *cobra.Command, args []string) error {
@@ -904,9 +944,29 @@ func runRigStart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// rigShutdownOptions are how a rig is shut down. 'gt rig shutdown' and
+// 'gt rig reboot' each fill them from their own flags.
+type rigShutdownOptions struct {
+	force   bool
+	nuclear bool
+	dryRun  bool
+	yes     bool
+	drain   time.Duration
+}
+
 func runRigShutdown(cmd *cobra.Command, args []string) error {
-	rigName := args[0]
+	return shutdownRig(args[0], rigShutdownOptions{
+		force:   rigShutdownForce,
+		nuclear: rigShutdownNuclear,
+		dryRun:  rigShutdownDryRun,
+		yes:     rigShutdownYes,
+		drain:   rigShutdownDrain,
+	})
+}
 
+// shutdownRig drains and stops a rig's polecats, then its refinery and
+// witness, once confirmed.
+func shutdownRig(rigName string, opts rigShutdownOptions) error {
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -928,7 +988,7 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check all polecats for uncommitted work (unless nuclear)
-	if !rigShutdownNuclear {
+	if !opts.nuclear {
 		polecatGit := git.NewGit(r.Path)
 		polecatMgr := polecat.NewManager(r, polecatGit, nil) // nil tmux: just listing
 		polecats, err := polecatMgr.List()
@@ -960,17 +1020,28 @@ func runRigShutdown(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	t := tmux.NewTmux()
+	plan := &destructivePlan{action: "Shut down rig " + rigName, sessions: rigSessionsToStop(t, r)}
+	if !confirmDestructive(plan, opts.dryRun, opts.yes) {
+		if opts.dryRun {
+			return nil
+		}
+		return fmt.Errorf("rig shutdown canceled")
+	}
+
 	fmt.Printf("Shutting down rig %s...\n", style.Bold.Render(rigName))
 
 	var errors []string
 
-	// 1. Stop all polecat sessions
-	t := tmux.NewTmux()
+	// 1. Drain polecats with hooked work, then stop all polecat sessions
+	if !opts.force && !opts.nuclear {
+		drainRig(t, townRoot, rigName, opts.drain)
+	}
 	polecatMgr := polecat.NewSessionManager(t, r)
 	infos, err := polecatMgr.List()
 	if err == nil && len(infos) > 0 {
 		fmt.Printf("  Stopping %d polecat session(s)...\n", len(infos))
-		if err := polecatMgr.StopAll(rigShutdownForce); err != nil {
+		if err := polecatMgr.StopAll(opts.force); err != nil {
 			errors = append(errors, fmt.Sprintf("polecat sessions: %v", err))
 		}
 	}
@@ -1013,10 +1084,18 @@ func runRigReboot(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Rebooting rig %s...\n\n", style.Bold.Render(rigName))
 
 	// Shutdown first
-	if err := runRigShutdown(cmd, args); err != nil {
+	if err := shutdownRig(rigName, rigShutdownOptions{
+		force:  rigRebootForce,
+		dryRun: rigRebootDryRun,
+		yes:    rigRebootYes,
+		drain:  rigRebootDrain,
+	}); err != nil {
 		// If shutdown fails due to uncommitted work, propagate the error
 		return err
 	}
+	if rigRebootDryRun {
+		return nil
+	}
 
 	fmt.Println() // Blank line between shutdown and boot
 
@@ -1191,6 +1270,10 @@ func runRigStop(cmd *cobra.Command, args []string) error {
 	g := git.NewGit(townRoot)
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 
+	if proceed, err := confirmRigs("Stop rig", rigMgr, tmux.NewTmux(), args, rigStopDryRun, rigStopYes); !proceed {
+		return err
+	}
+
 	// Track results
 	var succeeded []string
 	var failed []string
@@ -1241,8 +1324,11 @@ func runRigStop(cmd *cobra.Command, args []string) error {
 
 		var errors []string
 
-		// 1. Stop all polecat sessions
+		// 1. Drain polecats with hooked work, then stop all polecat sessions
 		t := tmux.NewTmux()
+		if !rigStopForce && !rigStopNuclear {
+			drainRig(t, townRoot, rigName, rigStopDrain)
+		}
 		polecatMgr := polecat.NewSessionManager(t, r)
 		infos, err := polecatMgr.List()
 		if err == nil && len(infos) > 0 {
@@ -1321,6 +1407,10 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 	t := tmux.NewTmux()
 
+	if proceed, err := confirmRigs("Restart rig", rigMgr, t, args, rigRestartDryRun, rigRestartYes); !proceed {
+		return err
+	}
+
 	// Track results
 	var succeeded []string
 	var failed []string
@@ -1375,7 +1465,10 @@ func runRigRestart(cmd *cobra.Command, args []string) error {
 		// === STOP PHASE ===
 		fmt.Printf("  Stopping...\n")
 
-		// 1. Stop all polecat sessions
+		// 1. Drain polecats with hooked work, then stop all polecat sessions
+		if !rigRestartForce && !rigRestartNuclear {
+			drainRig(t, townRoot, rigName, rigRestartDrain)
+		}
 		polecatMgr := polecat.NewSessionManager(t, r)
 		infos, err := polecatMgr.List()
 		if err == nil && len(infos) > 0 {
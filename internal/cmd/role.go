@@ -132,7 +132,7 @@ func GetRole() (RoleInfo, error) {
 		return RoleInfo{}, fmt.Errorf("finding workspace: %w", err)
 	}
 	if townRoot == "" {
-		return RoleInfo{}, fmt.Errorf("not in a Gas Town workspace")
+		return RoleInfo{}, workspace.ErrNotFound
 	}
 
 	return GetRoleWithContext(cwd, townRoot)
@@ -366,7 +366,7 @@ func runRoleHome(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding workspace: %w", err)
 	}
 	if townRoot == "" {
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	// Validate flag combinations: --polecat requires --rig to prevent strange merges
@@ -419,7 +419,7 @@ func runRoleDetect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding workspace: %w", err)
 	}
 	if townRoot == "" {
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	ctx := detectRole(cwd, townRoot)
@@ -481,7 +481,7 @@ func runRoleEnv(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding workspace: %w", err)
 	}
 	if townRoot == "" {
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	// Get current role (read-only - from env vars or cwd)
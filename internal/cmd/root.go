@@ -8,6 +8,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/authz"
+	"github.com/steveyegge/gastown/internal/constants"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/version"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -30,6 +33,8 @@ var beadsExemptCommands = map[string]bool{
 	"version":    true,
 	"help":       true,
 	"completion": true,
+	"which":      true, // Workspace discovery diagnostics
+	"simulate":   true, // Runs on machines without agents or beads
 }
 
 // Commands exempt from the town root branch warning.
@@ -41,6 +46,7 @@ var branchCheckExemptCommands = map[string]bool{
 	"doctor":     true, // Used to fix the problem
 	"install":    true, // Initial setup
 	"git-init":   true, // Git setup
+	"which":      true, // Diagnoses workspace discovery
 }
 
 // persistentPreRun runs before every command.
@@ -48,6 +54,15 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	if err := configureLogging(cmd); err != nil {
+		return err
+	}
+
+	// Refuse destructive commands the calling agent's role may not run
+	if err := authz.Authorize(commandPath(cmd), args); err != nil {
+		return err
+	}
+
 	// Check town root branch (warning only, non-blocking)
 	if !branchCheckExemptCommands[cmdName] {
 		warnIfTownRootOffMain()
@@ -62,6 +77,15 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	return CheckBeadsVersion()
 }
 
+// commandPath returns the command's path below the root, e.g. "polecat nuke".
+func commandPath(cmd *cobra.Command) string {
+	path := cmd.CommandPath()
+	if i := strings.IndexByte(path, ' '); i >= 0 {
+		return path[i+1:]
+	}
+	return ""
+}
+
 // warnIfTownRootOffMain prints a warning if the town root is not on main branch.
 // This is a non-blocking warning to help catch accidental branch switches.
 func warnIfTownRootOffMain() {
@@ -157,16 +181,19 @@ func checkStaleBinaryWarning() {
 
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
+// Errors are mapped to the documented exit codes (see constants.Exit*) so
+// wrappers and the daemon can branch on the failure class.
 func Execute() int {
+	markUsageErrors(rootCmd)
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
 	if err := rootCmd.Execute(); err != nil {
-		// Check for silent exit (scripting commands that signal status via exit code)
-		if code, ok := IsSilentExit(err); ok {
-			return code
-		}
-		// Other errors already printed by cobra
-		return 1
+		// Silent exits keep their explicit code; other errors were already
+		// printed by cobra and are classified by sentinel.
+		return ExitCodeFor(err)
 	}
-	return 0
+	return constants.ExitSuccess
 }
 
 // Command group IDs - used by subcommands to organize help output
@@ -201,6 +228,30 @@ func init() {
 
 	// Global flags can be added here
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
+	rootCmd.PersistentFlags().StringVar(&logSpec, "log", "", "Log levels, e.g. info or warn,mail=debug (default: $"+gtlog.EnvLevel+" or warn)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "Log format: text or json (default: $"+gtlog.EnvFormat+" or text)")
+}
+
+// Global logging flags, overriding GT_LOG and GT_LOG_FORMAT.
+var (
+	logSpec   string
+	logFormat string
+)
+
+// configureLogging applies the logging flags over the environment.
+// Logs go to stderr (or GT_LOG_FILE) so they don't mix with command output.
+func configureLogging(cmd *cobra.Command) error {
+	spec, format := os.Getenv(gtlog.EnvLevel), os.Getenv(gtlog.EnvFormat)
+	if cmd.Flags().Changed("log") {
+		spec = logSpec
+	}
+	if cmd.Flags().Changed("log-format") {
+		format = logFormat
+	}
+	if err := gtlog.Configure(spec, format, gtlog.Writer()); err != nil {
+		return &usageError{err: err}
+	}
+	return nil
 }
 
 // buildCommandPath walks the command hierarchy to build the full command path.
@@ -1,12 +1,10 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -91,7 +89,7 @@ func runSeance(cmd *cobra.Command, args []string) error {
 func runSeanceList() error {
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil || townRoot == "" {
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	// Read session events from our event stream
@@ -232,34 +230,23 @@ func runSeanceTalk(sessionID, prompt string) error {
 	return nil
 }
 
-// discoverSessions reads session_start events from our event stream.
+// discoverSessions reads session_start events from our event stream,
+// including any per-rig shards.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
+	records, err := events.ReadAll(townRoot)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
 		return nil, err
 	}
-	defer file.Close()
 
 	var sessions []sessionEvent
-	scanner := bufio.NewScanner(file)
-
-	// Increase buffer for large lines
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		var event sessionEvent
-		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
-			continue
-		}
-
-		if event.Type == events.TypeSessionStart {
-			sessions = append(sessions, event)
+	for _, r := range records {
+		if r.Type == events.TypeSessionStart {
+			sessions = append(sessions, sessionEvent{
+				Timestamp: r.Timestamp,
+				Type:      r.Type,
+				Actor:     r.Actor,
+				Payload:   r.Payload,
+			})
 		}
 	}
 
@@ -268,7 +255,7 @@ func discoverSessions(townRoot string) ([]sessionEvent, error) {
 		return sessions[i].Timestamp > sessions[j].Timestamp
 	})
 
-	return sessions, scanner.Err()
+	return sessions, nil
 }
 
 func getPayloadString(payload map[string]interface{}, key string) string {
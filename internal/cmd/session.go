@@ -25,6 +25,7 @@ import (
 var (
 	sessionIssue     string
 	sessionForce     bool
+	sessionDrain     time.Duration
 	sessionLines     int
 	sessionMessage   string
 	sessionFile      string
@@ -67,8 +68,12 @@ var sessionStopCmd = &cobra.Command{
 	Short: "Stop a polecat session",
 	Long: `Stop a running polecat session.
 
+If the polecat has work on its hook, it is drained first: nudged to commit
+and hand off, then given --drain-timeout (default: the town's drain_timeout
+setting, 2m).
+
 Attempts graceful shutdown first (Ctrl-C), then kills the tmux session.
-Use --force to skip graceful shutdown.`,
+Use --force to skip the drain and graceful shutdown.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSessionStop,
 }
@@ -172,6 +177,7 @@ func init() {
 
 	// Stop flags
 	sessionStopCmd.Flags().BoolVarP(&sessionForce, "force", "f", false, "Force immediate shutdown")
+	sessionStopCmd.Flags().DurationVar(&sessionDrain, "drain-timeout", 0, "How long a polecat with hooked work gets to hand off (default: town drain_timeout, 2m)")
 
 	// List flags
 	sessionListCmd.Flags().StringVar(&sessionRigFilter, "rig", "", "Filter by rig name")
@@ -290,11 +296,19 @@ func runSessionStop(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	polecatMgr, _, err := getSessionManager(rigName)
+	polecatMgr, r, err := getSessionManager(rigName)
 	if err != nil {
 		return err
 	}
 
+	// Give a polecat with hooked work a chance to hand off first
+	if !sessionForce {
+		target := polecatTarget{rigName: rigName, polecatName: polecatName, r: r}
+		if holding := drainPolecats(tmux.NewTmux(), []polecatTarget{target}, drainWindow(filepath.Dir(r.Path), sessionDrain)); len(holding) > 0 {
+			fmt.Printf("  %s Stopping anyway; hooked work stays on the hook for re-sling\n", style.Warning.Render("⚠"))
+		}
+	}
+
 	if sessionForce {
 		fmt.Printf("Force stopping session for %s/%s...\n", rigName, polecatName)
 	} else {
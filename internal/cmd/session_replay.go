@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	sessionReplaySpeed   string
+	sessionReplayMaxIdle time.Duration
+	sessionReplayList    bool
+	sessionReplayAt      string
+	sessionRecordOut     string
+)
+
+var sessionReplayCmd = &cobra.Command{
+	Use:   "replay <agent>",
+	Short: "Play back a recorded agent session",
+	Long: `Play back what an agent's terminal showed, at the pace it happened.
+
+Mayor and deacon sessions are recorded with timing under .runtime/recordings/
+in the town root. Replay prints the most recent recording by default; use
+--list to see them all and --at to pick one by start time. Useful for
+post-mortems after a mass death.
+
+Pauses longer than --max-idle are shortened so waits on the model don't
+stall playback.
+
+Examples:
+  gt session replay mayor
+  gt session replay deacon --speed 2x
+  gt session replay mayor --list
+  gt session replay mayor --at 20260301T090000 --speed 4x`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionReplay,
+}
+
+var sessionRecordCmd = &cobra.Command{
+	Use:    "record",
+	Short:  "Write piped pane output to a recording",
+	Hidden: true, // Internal command run by tmux pipe-pane
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return transcript.WriteFrames(os.Stdin, sessionRecordOut)
+	},
+}
+
+func init() {
+	sessionReplayCmd.Flags().StringVar(&sessionReplaySpeed, "speed", "1x", "Playback speed (e.g. 2x, 0.5x)")
+	sessionReplayCmd.Flags().DurationVar(&sessionReplayMaxIdle, "max-idle", 2*time.Second, "Longest pause between frames (0 for none)")
+	sessionReplayCmd.Flags().BoolVar(&sessionReplayList, "list", false, "List the agent's recordings instead of playing one")
+	sessionReplayCmd.Flags().StringVar(&sessionReplayAt, "at", "", "Play the recording that started at this time (as shown by --list)")
+
+	sessionRecordCmd.Flags().StringVar(&sessionRecordOut, "out", "", "Recording file to append to")
+	_ = sessionRecordCmd.MarkFlagRequired("out")
+
+	sessionCmd.AddCommand(sessionReplayCmd)
+	sessionCmd.AddCommand(sessionRecordCmd)
+}
+
+func runSessionReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	speed, err := parseSpeed(sessionReplaySpeed)
+	if err != nil {
+		return err
+	}
+
+	agent := strings.TrimSuffix(args[0], "/")
+	recs, err := transcript.Recordings(townRoot, agent)
+	if err != nil {
+		return err
+	}
+	if len(recs) == 0 {
+		return fmt.Errorf("no recordings for %s", agent)
+	}
+
+	if sessionReplayList {
+		for _, r := range recs {
+			fmt.Printf("%s  %s  %s\n", r.StartedAt.Format("20060102T150405"),
+				style.Dim.Render(r.StartedAt.Local().Format("2006-01-02 15:04")), r.Session)
+		}
+		return nil
+	}
+
+	rec := recs[len(recs)-1]
+	if sessionReplayAt != "" {
+		found := false
+		for _, r := range recs {
+			if r.StartedAt.Format("20060102T150405") == sessionReplayAt {
+				rec, found = r, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no recording of %s started at %s (see --list)", agent, sessionReplayAt)
+		}
+	}
+	return transcript.Replay(os.Stdout, rec.Path, speed, sessionReplayMaxIdle)
+}
+
+// parseSpeed parses a playback speed such as "2x", "0.5x" or "3".
+func parseSpeed(s string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(s), "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid speed %q (want e.g. 2x or 0.5x)", s)
+	}
+	return speed, nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/simulate"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	simulateEvents    string
+	simulateSynthetic int
+	simulateRigs      []string
+	simulateSeed      int64
+	simulateSpeed     string
+	simulateTown      string
+	simulateJSON      bool
+	simulateQuiet     bool
+)
+
+var simulateCmd = &cobra.Command{
+	Use:     "simulate",
+	GroupID: GroupDiag,
+	Short:   "Replay an event stream through a sandbox town",
+	Long: `Replay a recorded or synthetic event stream without tmux or live agents.
+
+Events are written to a sandbox town's events log, where the feed curator
+curates them, significance is classified, mail events are delivered to
+in-memory inboxes, and rig health is scored. Use it to try configuration
+changes, give demos, or develop on machines that can't run real agents.
+
+When run inside a town, its settings/*.json are copied into the sandbox so
+the replay uses the town's configuration. The sandbox is a temporary
+directory removed afterwards unless --town names one to keep.
+
+--speed divides the gaps between event timestamps: 10x replays ten times
+faster, max replays without waiting.
+
+Examples:
+  gt simulate --events fixture.jsonl --speed 10x
+  gt simulate --synthetic 50 --rigs gastown,beads --speed max
+  gt simulate --events ~/gt/.events.jsonl --speed 60x --town /tmp/sim`,
+	Args: cobra.NoArgs,
+	RunE: runSimulate,
+}
+
+func init() {
+	simulateCmd.Flags().StringVar(&simulateEvents, "events", "", "JSONL fixture of events to replay")
+	simulateCmd.Flags().IntVar(&simulateSynthetic, "synthetic", 0, "Generate this many synthetic work items instead of reading a fixture")
+	simulateCmd.Flags().StringSliceVar(&simulateRigs, "rigs", []string{"gastown"}, "Rigs for synthetic events")
+	simulateCmd.Flags().Int64Var(&simulateSeed, "seed", 1, "Random seed for synthetic events")
+	simulateCmd.Flags().StringVar(&simulateSpeed, "speed", "10x", "Replay speed (e.g. 1x, 10x, max)")
+	simulateCmd.Flags().StringVar(&simulateTown, "town", "", "Sandbox directory to use and keep (default: temporary)")
+	simulateCmd.Flags().BoolVar(&simulateJSON, "json", false, "Output the report as JSON")
+	simulateCmd.Flags().BoolVarP(&simulateQuiet, "quiet", "q", false, "Don't print each replayed event")
+	simulateCmd.MarkFlagsMutuallyExclusive("events", "synthetic")
+	rootCmd.AddCommand(simulateCmd)
+}
+
+func runSimulate(cmd *cobra.Command, args []string) error {
+	if simulateEvents == "" && simulateSynthetic <= 0 {
+		return &usageError{err: fmt.Errorf("--events or --synthetic is required")}
+	}
+	speed, err := parseSimulateSpeed(simulateSpeed)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	var evs []events.Event
+	if simulateEvents != "" {
+		evs, err = simulate.LoadFixture(simulateEvents)
+		if err != nil {
+			return fmt.Errorf("loading fixture: %w", err)
+		}
+	} else {
+		evs = simulate.Synthetic(simulateRigs, simulateSynthetic, time.Now().Add(-24*time.Hour), simulateSeed)
+	}
+
+	sandbox := simulateTown
+	if sandbox == "" {
+		sandbox, err = os.MkdirTemp("", "gt-simulate-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(sandbox)
+	} else if err := os.MkdirAll(sandbox, 0755); err != nil {
+		return err
+	}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if err := copyTownSettings(townRoot, sandbox); err != nil {
+			return fmt.Errorf("copying town settings: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner := &simulate.Runner{TownRoot: sandbox, Speed: speed}
+	if !simulateJSON && !simulateQuiet {
+		runner.Out = os.Stdout
+		fmt.Printf("%s Replaying %d events at %s\n\n", style.Bold.Render("▶"), len(evs), simulateSpeed)
+	}
+	report, err := runner.Run(ctx, evs)
+	if err != nil && report == nil {
+		return err
+	}
+
+	if simulateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(report); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+
+	printSimulateReport(report)
+	if simulateTown != "" {
+		fmt.Printf("\n%s\n", style.Dim.Render("Sandbox kept at "+sandbox))
+	}
+	return err
+}
+
+// parseSimulateSpeed parses a replay speed such as "10x", "0.5x" or "max".
+// max returns 0, meaning no delays.
+func parseSimulateSpeed(s string) (float64, error) {
+	if s == "max" {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid --speed %q: want e.g. 10x or max", s)
+	}
+	return speed, nil
+}
+
+// copyTownSettings copies a town's settings/*.json into the sandbox.
+func copyTownSettings(townRoot, sandbox string) error {
+	matches, err := filepath.Glob(filepath.Join(townRoot, "settings", "*.json"))
+	if err != nil || len(matches) == 0 {
+		return err
+	}
+	dst := filepath.Join(sandbox, "settings")
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, src := range matches {
+		data, err := os.ReadFile(src) //nolint:gosec // G304: path is under the town's settings directory
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dst, filepath.Base(src)), data, 0644); err != nil { //nolint:gosec // G306: settings are not sensitive
+			return err
+		}
+	}
+	return nil
+}
+
+func printSimulateReport(r *simulate.Report) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Simulation report"))
+	fmt.Printf("  Events:     %d (%d high, %d medium, %d low)\n", r.Events,
+		r.Significance[events.SignificanceHigh], r.Significance[events.SignificanceMedium], r.Significance[events.SignificanceLow])
+	fmt.Printf("  Feed:       %d curated entries\n", r.FeedLines)
+
+	if len(r.Mail) > 0 {
+		recipients := make([]string, 0, len(r.Mail))
+		for to := range r.Mail {
+			recipients = append(recipients, to)
+		}
+		sort.Strings(recipients)
+		fmt.Printf("  Mail:\n")
+		for _, to := range recipients {
+			fmt.Printf("    %-24s %d message(s)\n", to, len(r.Mail[to]))
+		}
+	}
+
+	if len(r.Health) > 0 {
+		rigs := make([]string, 0, len(r.Health))
+		for rig := range r.Health {
+			rigs = append(rigs, rig)
+		}
+		sort.Strings(rigs)
+		fmt.Printf("  Rig health:\n")
+		for _, rig := range rigs {
+			h := r.Health[rig]
+			line := fmt.Sprintf("    %-24s %3d %s %s", rig, h.Score, h.Arrow(), h.Grade)
+			switch h.Grade {
+			case health.GradeCritical:
+				line = style.Error.Render(line)
+			case health.GradeDegraded:
+				line = style.Warning.Render(line)
+			}
+			fmt.Println(line)
+		}
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -274,8 +276,9 @@ func getSessionFromPane(pane string) string {
 }
 
 // ensureAgentReady waits for an agent to be ready before nudging an existing session.
-// Uses a pragmatic approach: wait for the pane to leave a shell, then (Claude-only)
-// accept the bypass permissions warning and give it a moment to finish initializing.
+// Uses a pragmatic approach: wait for the pane to leave a shell, then accept the
+// runtime's startup dialogs (e.g., bypass permissions warning) and give it a moment
+// to finish initializing.
 func ensureAgentReady(sessionName string) error {
 	t := tmux.NewTmux()
 
@@ -289,10 +292,10 @@ func ensureAgentReady(sessionName string) error {
 		return fmt.Errorf("waiting for agent to start: %w", err)
 	}
 
-	// Claude-only: accept bypass permissions warning if present
-	if t.IsClaudeRunning(sessionName) {
-		_ = t.AcceptBypassPermissionsWarning(sessionName)
+	// Accept startup dialogs using the session's resolved runtime prompt rules
+	_ = runtime.AcceptStartupPrompts(t, sessionName, sessionRuntimeConfig(t, sessionName))
 
+	if t.IsClaudeRunning(sessionName) {
 		// PRAGMATIC APPROACH: fixed delay rather than prompt detection.
 		// Claude startup takes ~5-8 seconds on typical machines.
 		time.Sleep(8 * time.Second)
@@ -303,6 +306,22 @@ func ensureAgentReady(sessionName string) error {
 	return nil
 }
 
+// sessionRuntimeConfig resolves the runtime config of an existing session from the
+// GT_ROLE, GT_ROOT and GT_RIG environment set at startup.
+// Returns nil (Claude defaults) if the session's role is unknown.
+func sessionRuntimeConfig(t *tmux.Tmux, sessionName string) *config.RuntimeConfig {
+	role, _ := t.GetEnvironment(sessionName, "GT_ROLE")
+	townRoot, _ := t.GetEnvironment(sessionName, "GT_ROOT")
+	if role == "" || townRoot == "" {
+		return nil
+	}
+	rigPath := ""
+	if rigName, _ := t.GetEnvironment(sessionName, "GT_RIG"); rigName != "" {
+		rigPath = filepath.Join(townRoot, rigName)
+	}
+	return config.ResolveRoleAgentConfig(role, townRoot, rigPath)
+}
+
 // detectCloneRoot finds the root of the current git clone.
 func detectCloneRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
@@ -42,6 +42,7 @@ var (
 	shutdownYes            bool
 	shutdownPolecatsOnly   bool
 	shutdownNuclear        bool
+	shutdownDryRun         bool
 )
 
 var startCmd = &cobra.Command{
@@ -135,6 +136,8 @@ func init() {
 		"Skip confirmation prompt")
 	shutdownCmd.Flags().BoolVar(&shutdownPolecatsOnly, "polecats-only", false,
 		"Only stop polecats (minimal shutdown)")
+	shutdownCmd.Flags().BoolVar(&shutdownDryRun, "dry-run", false,
+		"Show which sessions would be stopped without stopping them")
 	shutdownCmd.Flags().BoolVar(&shutdownNuclear, "nuclear", false,
 		"Force cleanup even if polecats have uncommitted work (DANGER: may lose work)")
 
@@ -457,6 +460,11 @@ func runShutdown(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	if shutdownDryRun {
+		fmt.Println("Dry run: no sessions stopped.")
+		return nil
+	}
+
 	// Confirmation prompt
 	if !shutdownYes && !shutdownForce {
 		fmt.Printf("Proceed with shutdown? [y/N] ")
@@ -16,11 +16,16 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/health"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/town"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/term"
 )
@@ -38,10 +43,19 @@ var statusCmd = &cobra.Command{
 	Short:   "Show overall town status",
 	Long: `Display the current status of the Gas Town workspace.
 
-Shows town name, registered rigs, active polecats, and witness status.
+Shows town name, registered rigs, active polecats, and witness status,
+plus a health summary: daemon state, agent sessions, merge queue depth and
+open escalations. Problems are listed at the top, so a clean header means
+the town is healthy.
 
 Use --fast to skip mail lookups for faster execution.
-Use --watch to continuously refresh status at regular intervals.`,
+Use --watch to continuously refresh status at regular intervals.
+
+--json prints the same status for scripts and dashboards, plus a "town"
+overview gathered from the town's state files: every declared agent with
+its session state and keepalive age, the daemon, deacon and narrator state,
+unread mail per address, and when the town and each rig last logged an
+event. The overview's "version" is bumped only for incompatible changes.`,
 	RunE: runStatus,
 }
 
@@ -56,12 +70,32 @@ func init() {
 
 // TownStatus represents the overall status of the workspace.
 type TownStatus struct {
-	Name     string         `json:"name"`
-	Location string         `json:"location"`
-	Overseer *OverseerInfo  `json:"overseer,omitempty"` // Human operator
-	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
-	Rigs     []RigStatus    `json:"rigs"`
-	Summary  StatusSum      `json:"summary"`
+	Name        string             `json:"name"`
+	Location    string             `json:"location"`
+	Overseer    *OverseerInfo      `json:"overseer,omitempty"` // Human operator
+	Daemon      DaemonSummary      `json:"daemon"`
+	Escalations *EscalationSummary `json:"escalations,omitempty"`
+	Agents      []AgentRuntime     `json:"agents"` // Global agents (Mayor, Deacon)
+	Rigs        []RigStatus        `json:"rigs"`
+	Summary     StatusSum          `json:"summary"`
+
+	// Town is the machine-readable overview from town.Status, for --json.
+	Town *town.Overview `json:"town,omitempty"`
+}
+
+// DaemonSummary is the daemon's state as recorded in its state file.
+type DaemonSummary struct {
+	Running        bool       `json:"running"`
+	PID            int        `json:"pid,omitempty"`
+	LastHeartbeat  *time.Time `json:"last_heartbeat,omitempty"`
+	HeartbeatCount int64      `json:"heartbeat_count"`
+}
+
+// EscalationSummary counts escalations that are not yet closed.
+type EscalationSummary struct {
+	Open     int `json:"open"`
+	Acked    int `json:"acked"`
+	Breached int `json:"breached"` // open past the SLA threshold
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -86,6 +120,8 @@ type AgentRuntime struct {
 	State        string `json:"state,omitempty"`         // Agent state from agent bead
 	UnreadMail   int    `json:"unread_mail"`             // Number of unread messages
 	FirstSubject string `json:"first_subject,omitempty"` // Subject of first unread message
+
+	LastActivity *time.Time `json:"last_activity,omitempty"` // Last tmux session activity
 }
 
 // RigStatus represents status of a single rig.
@@ -100,6 +136,16 @@ type RigStatus struct {
 	Hooks        []AgentHookInfo `json:"hooks,omitempty"`
 	Agents       []AgentRuntime  `json:"agents,omitempty"` // Runtime state of all agents in rig
 	MQ           *MQSummary      `json:"mq,omitempty"`     // Merge queue summary
+	Patrol       *PatrolSummary  `json:"patrol,omitempty"` // Latest witness patrol
+	Health       *health.Score   `json:"health,omitempty"` // Score from recent events
+}
+
+// PatrolSummary condenses the witness's latest completed patrol report.
+type PatrolSummary struct {
+	CompletedAt time.Time `json:"completed_at"`
+	Headline    string    `json:"headline"`        // e.g. "3 polecats (2 working, 1 stuck); 1 nudge"
+	Stuck       []string  `json:"stuck,omitempty"` // "name: diagnosis" for stuck polecats
+	Summary     string    `json:"summary,omitempty"`
 }
 
 // MQSummary represents the merge queue status for a rig.
@@ -128,6 +174,11 @@ type StatusSum struct {
 	WitnessCount  int `json:"witness_count"`
 	RefineryCount int `json:"refinery_count"`
 	ActiveHooks   int `json:"active_hooks"`
+	MQDepth       int `json:"mq_depth"` // Pending, in-flight and blocked MRs across rigs
+
+	// Healthy is true when Problems is empty.
+	Healthy  bool     `json:"healthy"`
+	Problems []string `json:"problems,omitempty"`
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -223,6 +274,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			allSessions[s] = true
 		}
 	}
+	sessionActivity, _ := t.ListSessionActivity()
 
 	// Discover rigs
 	rigs, err := mgr.DiscoverRigs()
@@ -318,12 +370,20 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	// Score rig health from recent events
+	var rigHealth map[string]*health.Score
+	if records, err := events.ReadAll(townRoot); err == nil {
+		rigHealth = health.Compute(records, time.Now())
+	}
+
 	// Build status - parallel fetch global agents and rigs
 	status := TownStatus{
-		Name:     townConfig.Name,
-		Location: townRoot,
-		Overseer: overseerInfo,
-		Rigs:     make([]RigStatus, len(rigs)),
+		Name:        townConfig.Name,
+		Location:    townRoot,
+		Overseer:    overseerInfo,
+		Daemon:      getDaemonSummary(townRoot),
+		Escalations: getEscalationSummary(townRoot),
+		Rigs:        make([]RigStatus, len(rigs)),
 	}
 
 	var wg sync.WaitGroup
@@ -376,12 +436,24 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			// Get MQ summary if rig has a refinery
 			rs.MQ = getMQSummary(r)
 
+			// Summarize the witness's latest patrol
+			rs.Patrol = getPatrolSummary(r)
+
+			if rigHealth != nil {
+				rs.Health = health.ForRig(rigHealth, r.Name)
+			}
+
 			status.Rigs[idx] = rs
 		}(i, r)
 	}
 
 	wg.Wait()
 
+	applySessionActivity(status.Agents, sessionActivity)
+	for i := range status.Rigs {
+		applySessionActivity(status.Rigs[i].Agents, sessionActivity)
+	}
+
 	// Aggregate summary (after parallel work completes)
 	for i, rs := range status.Rigs {
 		status.Summary.PolecatCount += rs.PolecatCount
@@ -393,11 +465,20 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		if rs.HasRefinery {
 			status.Summary.RefineryCount++
 		}
+		if rs.MQ != nil {
+			status.Summary.MQDepth += rs.MQ.Pending + rs.MQ.InFlight + rs.MQ.Blocked
+		}
 	}
 	status.Summary.RigCount = len(rigs)
+	status.Summary.Problems = statusProblems(&status, func(rigName string) bool {
+		operational, _ := daemon.RigOperational(townRoot, rigName)
+		return operational
+	})
+	status.Summary.Healthy = len(status.Summary.Problems) == 0
 
 	// Output
 	if statusJSON {
+		status.Town = townOverview(townRoot, &status, allSessions)
 		return outputStatusJSON(status)
 	}
 	if err := outputStatusText(status); err != nil {
@@ -413,6 +494,35 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// townOverview gathers the town overview for --json. Unread mail is taken
+// from the agents already looked up rather than querying every mailbox
+// again. Returns nil if the town's topology can't be read.
+func townOverview(townRoot string, status *TownStatus, sessions map[string]bool) *town.Overview {
+	names := make([]string, 0, len(sessions))
+	for name := range sessions {
+		names = append(names, name)
+	}
+	overview, err := town.Status(townRoot, town.StatusOptions{Sessions: names})
+	if err != nil {
+		return nil
+	}
+	if statusFast {
+		return overview
+	}
+	overview.Mail = make(map[string]int)
+	if status.Overseer != nil {
+		overview.Mail["overseer"] = status.Overseer.UnreadMail
+	}
+	agents := append([]AgentRuntime(nil), status.Agents...)
+	for _, rs := range status.Rigs {
+		agents = append(agents, rs.Agents...)
+	}
+	for _, a := range agents {
+		overview.Mail[a.Address] = a.UnreadMail
+	}
+	return overview
+}
+
 func outputStatusJSON(status TownStatus) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -439,6 +549,8 @@ func outputStatusText(status TownStatus) error {
 		fmt.Println()
 	}
 
+	renderStatusHealth(status)
+
 	// Role icons - uses centralized emojis from constants package
 	roleIcons := map[string]string{
 		constants.RoleMayor:    constants.EmojiMayor,
@@ -479,7 +591,7 @@ func outputStatusText(status TownStatus) error {
 	// Rigs
 	for _, r := range status.Rigs {
 		// Rig header with separator
-		fmt.Printf("─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		fmt.Printf("─── %s ───────────────────────────────────────────%s\n\n", style.Bold.Render(r.Name+"/"), formatRigHealth(r.Health))
 
 		// Group agents by role
 		var witnesses, refineries, crews, polecats []AgentRuntime
@@ -503,10 +615,23 @@ func outputStatusText(status TownStatus) error {
 				for _, agent := range witnesses {
 					renderAgentDetails(agent, "   ", r.Hooks, status.Location)
 				}
+				// Patrol summary (shown under witness)
+				if r.Patrol != nil {
+					fmt.Printf("   Patrol: %s %s\n", r.Patrol.Headline,
+						style.Dim.Render("("+formatDurationAgo(time.Since(r.Patrol.CompletedAt))+" ago)"))
+					for _, stuck := range r.Patrol.Stuck {
+						fmt.Printf("     %s %s\n", style.Warning.Render("⚠"), stuck)
+					}
+				}
 				fmt.Println()
 			} else {
 				for _, agent := range witnesses {
-					renderAgentCompact(agent, roleIcons["witness"]+" ", r.Hooks, status.Location)
+					// Compact: include latest patrol on same line if present
+					patrolSuffix := ""
+					if r.Patrol != nil {
+						patrolSuffix = "  " + style.Dim.Render("patrol: "+r.Patrol.Headline)
+					}
+					renderAgentCompactWithSuffix(agent, roleIcons["witness"]+" ", r.Hooks, status.Location, patrolSuffix)
 				}
 			}
 		}
@@ -641,7 +766,12 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 		}
 	}
 
-	fmt.Printf("%s%s %s%s\n", indent, style.Dim.Render(agentBeadID), statusStr, stateInfo)
+	activityInfo := ""
+	if sessionExists && agent.LastActivity != nil {
+		activityInfo = style.Dim.Render(fmt.Sprintf(" (active %s ago)", formatDuration(time.Since(*agent.LastActivity))))
+	}
+
+	fmt.Printf("%s%s %s%s%s\n", indent, style.Dim.Render(agentBeadID), statusStr, stateInfo, activityInfo)
 
 	// Line 2: Hook bead (pinned work)
 	hookStr := style.Dim.Render("(none)")
@@ -1130,6 +1260,28 @@ func discoverRigAgents(allSessions map[string]bool, r *rig.Rig, crews []string,
 	return agents
 }
 
+// getPatrolSummary reads the witness's latest completed patrol report.
+// Returns nil if the witness has not recorded one.
+func getPatrolSummary(r *rig.Rig) *PatrolSummary {
+	report, err := witness.LatestReport(r.Path)
+	if err != nil || report == nil || report.CompletedAt == nil {
+		return nil
+	}
+	summary := &PatrolSummary{
+		CompletedAt: *report.CompletedAt,
+		Headline:    report.Headline(),
+		Summary:     report.Summary,
+	}
+	for _, p := range report.Stuck() {
+		entry := p.Name
+		if p.Diagnosis != "" {
+			entry += ": " + p.Diagnosis
+		}
+		summary.Stuck = append(summary.Stuck, entry)
+	}
+	return summary
+}
+
 // getMQSummary queries beads for merge-request issues and returns a summary.
 // Returns nil if the rig has no refinery or no MQ issues.
 func getMQSummary(r *rig.Rig) *MQSummary {
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// getDaemonSummary reads the daemon's PID and state files.
+func getDaemonSummary(townRoot string) DaemonSummary {
+	var summary DaemonSummary
+	if running, pid, err := daemon.IsRunning(townRoot); err == nil && running {
+		summary.Running = true
+		summary.PID = pid
+	}
+	if state, err := daemon.LoadState(townRoot); err == nil {
+		if !state.LastHeartbeat.IsZero() {
+			last := state.LastHeartbeat
+			summary.LastHeartbeat = &last
+		}
+		summary.HeartbeatCount = state.HeartbeatCount
+	}
+	return summary
+}
+
+// getEscalationSummary counts unclosed escalations from the event log.
+// Returns nil if the log can't be read.
+func getEscalationSummary(townRoot string) *EscalationSummary {
+	views, err := loadEscalations(townRoot)
+	if err != nil {
+		return nil
+	}
+	summary := &EscalationSummary{}
+	for _, e := range views {
+		switch e.Status {
+		case escalationOpen:
+			summary.Open++
+		case escalationAcked:
+			summary.Acked++
+		}
+		if e.SLA == slaBreached {
+			summary.Breached++
+		}
+	}
+	return summary
+}
+
+// applySessionActivity fills in each running agent's last session activity.
+func applySessionActivity(agents []AgentRuntime, activity map[string]time.Time) {
+	for i := range agents {
+		if last, ok := activity[agents[i].Session]; ok && agents[i].Running {
+			agents[i].LastActivity = &last
+		}
+	}
+}
+
+// statusProblems lists what needs attention in the town. Agents of rigs
+// that are parked or docked (operational reports false) are expected to be
+// down and aren't reported.
+func statusProblems(status *TownStatus, operational func(rigName string) bool) []string {
+	var problems []string
+	if !status.Daemon.Running {
+		problems = append(problems, "daemon not running (gt daemon start)")
+	}
+	if status.Escalations != nil && status.Escalations.Breached > 0 {
+		problems = append(problems, fmt.Sprintf("%d escalation(s) past SLA (gt escalation)", status.Escalations.Breached))
+	}
+	for _, agent := range status.Agents {
+		if agent.Name == "deacon" && !agent.Running {
+			problems = append(problems, "deacon not running")
+		}
+	}
+	for _, rs := range status.Rigs {
+		up := operational(rs.Name)
+		for _, agent := range rs.Agents {
+			switch {
+			case up && (agent.Role == "witness" || agent.Role == "refinery") && !agent.Running:
+				problems = append(problems, fmt.Sprintf("%s: %s not running", rs.Name, agent.Role))
+			case agent.State == "stuck":
+				problems = append(problems, fmt.Sprintf("%s is stuck", agent.Address))
+			}
+		}
+		if rs.Health != nil && rs.Health.Grade == health.GradeCritical {
+			problems = append(problems, fmt.Sprintf("%s: health critical (%d)", rs.Name, rs.Health.Score))
+		}
+		if rs.MQ != nil && rs.MQ.Health == "stale" {
+			problems = append(problems, fmt.Sprintf("%s: merge queue stale", rs.Name))
+		}
+	}
+	return problems
+}
+
+// renderStatusHealth prints the town health header: problems, daemon,
+// escalations and merge queue depth.
+func renderStatusHealth(status TownStatus) {
+	if status.Summary.Healthy {
+		fmt.Printf("🩺 %s %s\n", style.Bold.Render("Health:"), style.Success.Render("✓ healthy"))
+	} else {
+		fmt.Printf("🩺 %s %s\n", style.Bold.Render("Health:"),
+			style.Warning.Render(fmt.Sprintf("⚠ %d problem(s)", len(status.Summary.Problems))))
+		for _, p := range status.Summary.Problems {
+			fmt.Printf("   • %s\n", p)
+		}
+	}
+
+	daemonStr := style.Error.Render("stopped")
+	if status.Daemon.Running {
+		daemonStr = style.Success.Render("running") + style.Dim.Render(fmt.Sprintf(" (pid %d)", status.Daemon.PID))
+	}
+	if status.Daemon.LastHeartbeat != nil {
+		daemonStr += style.Dim.Render(fmt.Sprintf(", last heartbeat %s ago", formatDuration(time.Since(*status.Daemon.LastHeartbeat))))
+	}
+	fmt.Printf("⚙️  %s %s\n", style.Bold.Render("Daemon:"), daemonStr)
+
+	if e := status.Escalations; e != nil && e.Open+e.Acked > 0 {
+		escStr := fmt.Sprintf("%d open, %d acked", e.Open, e.Acked)
+		if e.Breached > 0 {
+			escStr += ", " + style.Error.Render(fmt.Sprintf("%d past SLA", e.Breached))
+		}
+		fmt.Printf("🚨 %s %s\n", style.Bold.Render("Escalations:"), escStr)
+	}
+	if status.Summary.MQDepth > 0 {
+		fmt.Printf("📋 %s %d MR(s) queued\n", style.Bold.Render("Merge queue:"), status.Summary.MQDepth)
+	}
+	fmt.Println()
+}
+
+// formatRigHealth renders a rig's health score for its status header.
+func formatRigHealth(h *health.Score) string {
+	if h == nil {
+		return ""
+	}
+	text := fmt.Sprintf(" health %d %s", h.Score, h.Arrow())
+	switch h.Grade {
+	case health.GradeCritical:
+		return style.Error.Render(text)
+	case health.GradeDegraded:
+		return style.Warning.Render(text)
+	default:
+		return style.Dim.Render(text)
+	}
+}
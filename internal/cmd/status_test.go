@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/health"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -158,3 +159,53 @@ func TestRunStatusWatch_RejectsJSONCombo(t *testing.T) {
 		t.Errorf("error %q should mention 'cannot be used together'", err.Error())
 	}
 }
+
+func TestStatusProblems(t *testing.T) {
+	status := &TownStatus{
+		Daemon:      DaemonSummary{Running: false},
+		Escalations: &EscalationSummary{Open: 2, Breached: 1},
+		Agents: []AgentRuntime{
+			{Name: "mayor", Running: false},
+			{Name: "deacon", Running: true},
+		},
+		Rigs: []RigStatus{
+			{
+				Name: "gastown",
+				Agents: []AgentRuntime{
+					{Name: "witness", Role: "witness", Address: "gastown/witness", Running: false},
+					{Name: "refinery", Role: "refinery", Address: "gastown/refinery", Running: true},
+					{Name: "Toast", Role: "polecat", Address: "gastown/Toast", Running: true, State: "stuck"},
+				},
+				MQ:     &MQSummary{Pending: 3, Health: "stale"},
+				Health: &health.Score{Rig: "gastown", Score: 35, Grade: health.GradeCritical},
+			},
+			{
+				Name: "parked",
+				Agents: []AgentRuntime{
+					{Name: "witness", Role: "witness", Address: "parked/witness", Running: false},
+				},
+			},
+		},
+	}
+
+	got := statusProblems(status, func(rigName string) bool { return rigName != "parked" })
+	want := []string{
+		"daemon not running (gt daemon start)",
+		"1 escalation(s) past SLA (gt escalation)",
+		"gastown: witness not running",
+		"gastown/Toast is stuck",
+		"gastown: health critical (35)",
+		"gastown: merge queue stale",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("statusProblems() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	healthy := &TownStatus{
+		Daemon: DaemonSummary{Running: true},
+		Agents: []AgentRuntime{{Name: "deacon", Running: true}},
+	}
+	if problems := statusProblems(healthy, func(string) bool { return true }); len(problems) != 0 {
+		t.Errorf("statusProblems() on healthy town = %v", problems)
+	}
+}
@@ -698,8 +698,9 @@ func getMailPreview(identity string, maxLen int) (int, string) {
 
 // getMailPreviewWithRoot is like getMailPreview but uses an explicit town root.
 func getMailPreviewWithRoot(identity string, maxLen int, townRoot string) (int, string) {
-	// Use NewMailboxFromAddress to normalize identity (e.g., gastown/crew/gus -> gastown/gus)
-	mailbox := mail.NewMailboxFromAddress(identity, townRoot)
+	// GetMailbox normalizes identity (e.g., gastown/crew/gus -> gastown/gus)
+	// and uses the town's configured mail transport
+	mailbox, _ := mail.NewRouterWithTownRoot(townRoot, townRoot).GetMailbox(identity)
 
 	// Get unread messages
 	messages, err := mailbox.ListUnread()
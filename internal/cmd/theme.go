@@ -333,7 +333,7 @@ func saveRigTheme(rigName, themeName string) error {
 		return fmt.Errorf("finding workspace: %w", err)
 	}
 	if townRoot == "" {
-		return fmt.Errorf("not in a Gas Town workspace")
+		return workspace.ErrNotFound
 	}
 
 	settingsPath := filepath.Join(townRoot, rigName, "settings", "config.json")
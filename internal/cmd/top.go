@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/top"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var topCmd = &cobra.Command{
+	Use:     "top",
+	GroupID: GroupDiag,
+	Short:   "Live dashboard of the town",
+	Args:    cobra.NoArgs,
+	Long: `Show a live terminal dashboard of the town.
+
+Panels:
+  Sessions      Running gt-/hq- tmux sessions
+  Keepalives    Each agent's last gt command and how long ago it ran
+  Merges        Per-rig merge queue outcomes from the events log
+  Unread mail   Unread messages per recipient
+  Events        Recent high-significance events
+
+Events are tailed as they are written; sessions, keepalives and mail are
+polled every few seconds. gt top only reads: it doesn't mark mail read,
+touch keepalives or nudge agents.
+
+Keys: r refresh, ? help, q quit.
+
+Examples:
+  gt top`,
+	RunE: runTop,
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return err
+	}
+
+	// Start tailing before reading history so nothing falls between them
+	tailer, err := events.NewTailer(townRoot)
+	if err != nil {
+		return fmt.Errorf("opening events log: %w", err)
+	}
+	defer func() { _ = tailer.Close() }()
+	history, err := events.ReadAll(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	m := top.New(townRoot, history, tailer, rules, tmux.NewTmux(), mail.NewRouterWithTownRoot(townRoot, townRoot))
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("running TUI: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/town"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townSnapshotJSON   bool
+	townSnapshotOutput string
+	townSnapshotEvents bool
+	townSnapshotKey    bool
+	townDriftJSON      bool
+	townRestoreInto    string
+	townRestoreForce   bool
+)
+
+var townSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record the declared town topology",
+	Long: `Record the town's declared topology to mayor/snapshot.json.
+
+The snapshot lists the rigs registered in mayor/rigs.json (with their git
+URL and parked/docked status) and the agents they declare: mayor, deacon,
+each rig's witness and refinery, and every crew and polecat directory.
+
+'gt town drift' compares later states against it.
+
+With --output, also writes the town's state to a gzipped tar archive, for
+'gt town restore' on this or another machine: mayor, deacon and daemon
+state, settings and config, the narrator's state, runtime offsets,
+subscriptions and scheduled mail, usage records, mail indexes and rig
+settings, plus the topology. The archive records where each events log
+ended, so a restore can tell what the archived state has already seen.
+The logs themselves are only included with --events (along with their
+annotations and renumbering history). The events encryption key is left
+out unless --include-key is also given: whoever holds an archive with the
+key can read the encrypted events. Stop the daemon first for a consistent
+archive.
+
+Examples:
+  gt town snapshot
+  gt town snapshot -o town.tar.gz
+  gt town snapshot -o town.tar.gz --events   # For moving to another machine
+  gt town snapshot -o town.tar.gz --events --include-key`,
+	Args: cobra.NoArgs,
+	RunE: runTownSnapshot,
+}
+
+var townRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore town state from a snapshot archive",
+	Long: `Bring a town's state back to a 'gt town snapshot --output' archive.
+
+Every file in the archive replaces the town's file of the same name; files
+the archive doesn't hold are left alone. The daemon must be stopped.
+Restoring over an existing town requires --force.
+
+Restore into the current town, or with --into, into a directory on a new
+machine. Afterwards the events logs are compared with the archive's
+record of where they ended: events written since the snapshot are
+delivered again to the restored readers, and a log shorter than the
+archive's record is reported, since readers will wait for events it lacks
+(restore an archive taken with --events to bring the logs along).
+
+Examples:
+  gt town restore town.tar.gz --force
+  gt town restore town.tar.gz --into ~/gt`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownRestore,
+}
+
+var townDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Compare the declared town against what is running",
+	Long: `Report where the town has drifted from its declared topology.
+
+Checks live reality against the current declaration:
+  undeclared-session  a gt-/hq- tmux session that no declared agent owns
+  rig-no-sessions     an active rig with none of its agents running
+  missing-workdir     a declared agent whose directory is gone
+  orphan-state        rig state left behind for an unregistered rig
+
+If 'gt town snapshot' has been run, also reports rigs and agents added or
+removed since then.`,
+	RunE: runTownDrift,
+}
+
+func init() {
+	townSnapshotCmd.Flags().BoolVar(&townSnapshotJSON, "json", false, "Print the snapshot as JSON instead of a summary")
+	townSnapshotCmd.Flags().StringVarP(&townSnapshotOutput, "output", "o", "", "Also write the town's state to this archive")
+	townSnapshotCmd.Flags().BoolVar(&townSnapshotEvents, "events", false, "Include the events logs in the archive")
+	townSnapshotCmd.Flags().BoolVar(&townSnapshotKey, "include-key", false, "Also include the events encryption key (with --events)")
+	townDriftCmd.Flags().BoolVar(&townDriftJSON, "json", false, "Output as JSON")
+	townRestoreCmd.Flags().StringVar(&townRestoreInto, "into", "", "Town directory to restore into (default: the current town)")
+	townRestoreCmd.Flags().BoolVar(&townRestoreForce, "force", false, "Overwrite an existing town's state")
+
+	townCmd.AddCommand(townSnapshotCmd)
+	townCmd.AddCommand(townDriftCmd)
+	townCmd.AddCommand(townRestoreCmd)
+}
+
+func runTownSnapshot(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	snap, err := town.Declared(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := town.SaveSnapshot(townRoot, snap); err != nil {
+		return fmt.Errorf("saving snapshot: %w", err)
+	}
+	if townSnapshotEvents && townSnapshotOutput == "" {
+		return &usageError{err: fmt.Errorf("--events requires --output")}
+	}
+	if townSnapshotKey && !townSnapshotEvents {
+		return &usageError{err: fmt.Errorf("--include-key requires --events")}
+	}
+	if townSnapshotOutput != "" {
+		return writeTownArchive(townRoot)
+	}
+
+	if townSnapshotJSON {
+		out, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding snapshot: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Printf("%s Saved snapshot: %d rigs, %d agents\n",
+		style.Bold.Render("✓"), len(snap.Rigs), len(snap.Agents))
+	fmt.Printf("  %s\n", style.Dim.Render(town.SnapshotFile(townRoot)))
+	return nil
+}
+
+// writeTownArchive writes the state archive for 'gt town snapshot -o'.
+func writeTownArchive(townRoot string) error {
+	if running, pid, _ := daemon.IsRunning(townRoot); running {
+		fmt.Printf("%s Daemon is running (PID %d); state may change while archiving\n", style.Warning.Render("⚠"), pid)
+	}
+	perm := os.FileMode(0666)
+	if townSnapshotKey {
+		fmt.Printf("%s Including the events key: anyone with %s can read the encrypted events\n", style.Warning.Render("⚠"), townSnapshotOutput)
+		perm = 0600
+	}
+	f, err := os.OpenFile(townSnapshotOutput, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm) //nolint:gosec // G302: archives without the key hold non-sensitive state
+	if err != nil {
+		return err
+	}
+	m, err := town.WriteArchive(townRoot, f, town.ArchiveOptions{Events: townSnapshotEvents, IncludeKey: townSnapshotKey})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(townSnapshotOutput)
+		return fmt.Errorf("writing archive: %w", err)
+	}
+
+	if townSnapshotJSON {
+		out, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding manifest: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Printf("%s Archived %d state files to %s\n", style.Bold.Render("✓"), len(m.Files), townSnapshotOutput)
+	fmt.Printf("  %s\n", style.Dim.Render("events: "+formatEventsPosition(m.Events)))
+	if m.IncludesEvents {
+		fmt.Printf("  %s\n", style.Dim.Render("includes the events logs"))
+	}
+	return nil
+}
+
+// formatEventsPosition describes where each events log ended.
+func formatEventsPosition(pos map[string]events.Offset) string {
+	shards := make([]string, 0, len(pos))
+	for shard := range pos {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+	var parts []string
+	for _, shard := range shards {
+		name := shard
+		if name == "" {
+			name = "town"
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", name, pos[shard].Lines))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runTownRestore(cmd *cobra.Command, args []string) error {
+	target := townRestoreInto
+	if target == "" {
+		var err error
+		if target, err = workspace.FindFromCwdOrError(); err != nil {
+			return fmt.Errorf("not in a Gas Town workspace (use --into): %w", err)
+		}
+	}
+	if running, pid, _ := daemon.IsRunning(target); running {
+		return fmt.Errorf("daemon is running (PID %d); stop it with 'gt daemon stop' first", pid)
+	}
+	if _, err := os.Stat(constants.MayorTownPath(target)); err == nil && !townRestoreForce {
+		return fmt.Errorf("%s already has a town; use --force to overwrite its state", target)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	m, err := town.RestoreArchive(target, f)
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", args[0], err)
+	}
+
+	name := m.Town
+	if name == "" {
+		name = "town"
+	}
+	fmt.Printf("%s Restored %d state files of %s (snapshot of %s)\n",
+		style.Bold.Render("✓"), len(m.Files), name, m.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	now, err := events.Position(target)
+	if err != nil {
+		return fmt.Errorf("reading events position: %w", err)
+	}
+	for shard, was := range m.Events {
+		label := shard
+		if label == "" {
+			label = "town"
+		}
+		cur := now[shard]
+		switch {
+		case cur.Generation != was.Generation:
+			fmt.Printf("  %s %s events log was rewritten since the snapshot; readers remap their offsets\n", style.Warning.Render("⚠"), label)
+		case cur.Lines < was.Lines:
+			fmt.Printf("  %s %s events log has %d events, the snapshot had seen %d\n", style.Warning.Render("⚠"), label, cur.Lines, was.Lines)
+		case cur.Lines > was.Lines:
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%s events log: %d events since the snapshot", label, cur.Lines-was.Lines)))
+		}
+	}
+	return nil
+}
+
+func runTownDrift(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	declared, err := town.Declared(townRoot)
+	if err != nil {
+		return err
+	}
+	sessions, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+
+	drifts := town.CompareLive(townRoot, declared, sessions)
+
+	saved, err := town.LoadSnapshot(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading snapshot: %w", err)
+	}
+	if saved != nil {
+		drifts = append(drifts, town.CompareSnapshots(saved, declared)...)
+	}
+
+	if townDriftJSON {
+		if drifts == nil {
+			drifts = []town.Drift{}
+		}
+		out, err := json.MarshalIndent(drifts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding drift: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(drifts) == 0 {
+		fmt.Printf("%s No drift: %d rigs, %d agents as declared\n",
+			style.Bold.Render("✓"), len(declared.Rigs), len(declared.Agents))
+		if saved == nil {
+			fmt.Printf("  %s\n", style.Dim.Render("No snapshot saved; run 'gt town snapshot' to track topology changes"))
+		}
+		return nil
+	}
+
+	fmt.Printf("%s %d drift finding(s)\n\n", style.Bold.Render("⚠"), len(drifts))
+	for _, d := range drifts {
+		fmt.Printf("  %-18s %s\n", d.Kind, style.Bold.Render(d.Subject))
+		fmt.Printf("  %-18s %s\n", "", style.Dim.Render(d.Detail))
+	}
+	return nil
+}
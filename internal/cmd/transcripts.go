@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	transcriptsAgent string
+	transcriptsSince string
+	transcriptsLimit int
+	transcriptsJSON  bool
+)
+
+var transcriptsCmd = &cobra.Command{
+	Use:     "transcripts",
+	GroupID: GroupDiag,
+	Short:   "Search archived session transcripts",
+	Long: `Search what agents actually said and did in ended sessions.
+
+Agent sessions are recorded as they run. When a session ends, its output is
+cleaned of terminal escapes and archived under logs/transcripts/ in the town
+root. The daemon archives ended sessions on each heartbeat; these commands
+also archive any they find before reading.
+
+Examples:
+  gt transcripts list --agent gastown/Toast
+  gt transcripts search "permission denied" --since 7d
+  gt transcripts search "git push" --agent gastown/Toast --json
+  gt transcripts show gt-gastown-Toast-20260301T090000`,
+	RunE: requireSubcommand,
+}
+
+var transcriptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List archived transcripts",
+	RunE:  runTranscriptsList,
+}
+
+var transcriptsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search archived transcripts for a phrase",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTranscriptsSearch,
+}
+
+var transcriptsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Print an archived transcript",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTranscriptsShow,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{transcriptsListCmd, transcriptsSearchCmd} {
+		c.Flags().StringVar(&transcriptsAgent, "agent", "", "Only transcripts of this agent (e.g. gastown/Toast)")
+		c.Flags().StringVar(&transcriptsSince, "since", "", "Only transcripts that ended within this window (e.g. 24h, 7d)")
+		c.Flags().BoolVar(&transcriptsJSON, "json", false, "Output as JSON")
+	}
+	transcriptsSearchCmd.Flags().IntVarP(&transcriptsLimit, "limit", "n", 100, "Maximum matches to show (0 for all)")
+
+	transcriptsCmd.AddCommand(transcriptsListCmd)
+	transcriptsCmd.AddCommand(transcriptsSearchCmd)
+	transcriptsCmd.AddCommand(transcriptsShowCmd)
+	rootCmd.AddCommand(transcriptsCmd)
+}
+
+// transcriptsTown finds the town root and archives the recordings of any
+// sessions that have ended since the daemon last did.
+func transcriptsTown() (string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	t := tmux.NewTmux()
+	if t.IsAvailable() {
+		_, _ = transcript.ArchiveEnded(townRoot, func(session string) bool {
+			running, err := t.HasSession(session)
+			return running || err != nil
+		})
+	}
+	return townRoot, nil
+}
+
+// transcriptsSinceTime converts --since to a cutoff time.
+func transcriptsSinceTime() (time.Time, error) {
+	if transcriptsSince == "" {
+		return time.Time{}, nil
+	}
+	d, err := parseDuration(transcriptsSince)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since: %w", err)
+	}
+	return time.Now().Add(-d), nil
+}
+
+func runTranscriptsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := transcriptsTown()
+	if err != nil {
+		return err
+	}
+	since, err := transcriptsSinceTime()
+	if err != nil {
+		return err
+	}
+	entries, err := transcript.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading transcript index: %w", err)
+	}
+
+	shown := make([]transcript.Entry, 0, len(entries))
+	for _, e := range entries {
+		if transcriptsAgent != "" && e.Agent != transcriptsAgent {
+			continue
+		}
+		if !since.IsZero() && e.EndedAt.Before(since) {
+			continue
+		}
+		shown = append(shown, e)
+	}
+
+	if transcriptsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shown)
+	}
+	if len(shown) == 0 {
+		fmt.Println("No transcripts found")
+		return nil
+	}
+	for _, e := range shown {
+		fmt.Printf("%s  %-24s %s  %s\n", style.Bold.Render(e.ID), e.Agent,
+			e.EndedAt.Local().Format("2006-01-02 15:04"),
+			style.Dim.Render(fmt.Sprintf("%d lines, %s", e.Lines, formatDuration(e.EndedAt.Sub(e.StartedAt)))))
+	}
+	return nil
+}
+
+func runTranscriptsSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := transcriptsTown()
+	if err != nil {
+		return err
+	}
+	since, err := transcriptsSinceTime()
+	if err != nil {
+		return err
+	}
+	matches, err := transcript.Search(townRoot, transcript.Query{
+		Text:  args[0],
+		Agent: transcriptsAgent,
+		Since: since,
+		Limit: transcriptsLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if transcriptsJSON {
+		if matches == nil {
+			matches = []transcript.Match{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+	if len(matches) == 0 {
+		fmt.Println("No matches")
+		return nil
+	}
+	current := ""
+	for _, m := range matches {
+		if m.Entry.ID != current {
+			current = m.Entry.ID
+			fmt.Printf("\n%s %s %s\n", style.Bold.Render(m.Entry.Agent), m.Entry.ID,
+				style.Dim.Render(m.Entry.EndedAt.Local().Format("2006-01-02 15:04")))
+		}
+		fmt.Printf("  %s %s\n", style.Dim.Render(fmt.Sprintf("%5d:", m.Line)), m.Text)
+	}
+	if transcriptsLimit > 0 && len(matches) >= transcriptsLimit {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("(showing first %d matches; use --limit to see more)", transcriptsLimit)))
+	}
+	return nil
+}
+
+func runTranscriptsShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := transcriptsTown()
+	if err != nil {
+		return err
+	}
+	entries, err := transcript.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading transcript index: %w", err)
+	}
+	for _, e := range entries {
+		if e.ID == args[0] {
+			data, err := os.ReadFile(e.Path(townRoot))
+			if err != nil {
+				return fmt.Errorf("reading transcript: %w", err)
+			}
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+	}
+	return fmt.Errorf("transcript not found: %s", args[0])
+}
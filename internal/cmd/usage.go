@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/usage"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	usageSince  string
+	usageUntil  string
+	usageByRig  bool
+	usageByRole bool
+	usageJSON   bool
+)
+
+var usageCmd = &cobra.Command{
+	Use:     "usage",
+	GroupID: GroupDiag,
+	Short:   "Report CPU, memory and token usage per agent",
+	Long: `Report what each agent costs to run.
+
+The daemon samples every Gas Town tmux session on each heartbeat: the CPU
+time and memory of the session's process tree, and the tokens the claude
+CLI recorded in its transcripts (with cost, where the CLI reports it).
+Samples are added up per agent per day in logs/usage/.
+
+Commands:
+  gt usage report    Sum usage over a period, per agent, rig or role
+  gt usage sample    Take a sample now (the daemon does this each heartbeat)`,
+	RunE: requireSubcommand,
+}
+
+var usageReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Sum agent usage over a period",
+	Long: `Sum agent usage over a period, for cost accounting.
+
+Rows are agents, or rigs with --by-rig (town-level agents such as the mayor
+count as "(town)"), or roles with --by-role. Peak memory is the largest
+process-tree memory seen in any one sample. Cost is only what the claude
+CLI reported; token counts are always shown. Input tokens include cache
+reads and writes.
+
+--since and --until take a date, an RFC3339 time or an age such as 7d, and
+select whole days.
+
+Examples:
+  gt usage report                    # Last 7 days, per agent
+  gt usage report --by-rig --since 30d
+  gt usage report --by-role --since 2026-03-01 --until 2026-03-31
+  gt usage report --json`,
+	Args: cobra.NoArgs,
+	RunE: runUsageReport,
+}
+
+var usageSampleCmd = &cobra.Command{
+	Use:   "sample",
+	Short: "Sample agent sessions now",
+	Args:  cobra.NoArgs,
+	RunE:  runUsageSample,
+}
+
+func init() {
+	usageReportCmd.Flags().StringVar(&usageSince, "since", "7d", "Start of the period: date, RFC3339 time or age like 30d")
+	usageReportCmd.Flags().StringVar(&usageUntil, "until", "", "End of the period (default: today)")
+	usageReportCmd.Flags().BoolVar(&usageByRig, "by-rig", false, "One row per rig")
+	usageReportCmd.Flags().BoolVar(&usageByRole, "by-role", false, "One row per role")
+	usageReportCmd.Flags().BoolVar(&usageJSON, "json", false, "Output as JSON")
+	usageReportCmd.MarkFlagsMutuallyExclusive("by-rig", "by-role")
+
+	usageCmd.AddCommand(usageReportCmd)
+	usageCmd.AddCommand(usageSampleCmd)
+	rootCmd.AddCommand(usageCmd)
+}
+
+func runUsageReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	since, err := parseCutoff("--since", usageSince)
+	if err != nil {
+		return &usageError{err: err}
+	}
+	until := time.Now()
+	if usageUntil != "" {
+		if until, err = parseCutoff("--until", usageUntil); err != nil {
+			return &usageError{err: err}
+		}
+	}
+
+	days, err := usage.Load(townRoot, since, until)
+	if err != nil {
+		return fmt.Errorf("reading usage: %w", err)
+	}
+	key, label := usage.ByAgent, "Agent"
+	switch {
+	case usageByRig:
+		key, label = usage.ByRig, "Rig"
+	case usageByRole:
+		key, label = usage.ByRole, "Role"
+	}
+	rows := usage.Aggregate(days, key)
+
+	if usageJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	period := fmt.Sprintf("%s to %s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+	if len(rows) == 0 {
+		fmt.Println(style.Dim.Render("No usage recorded " + period))
+		return nil
+	}
+
+	fmt.Printf("\n%s Agent usage, %s\n\n", style.Bold.Render("📊"), period)
+	fmt.Printf("%-30s %10s %9s %12s %12s %9s\n", label, "CPU", "Peak mem", "Input tok", "Output tok", "Cost")
+	fmt.Println(strings.Repeat("─", 87))
+	var total usage.Usage
+	for _, u := range rows {
+		name := u.Agent
+		if name == "" {
+			name = "(town)"
+		}
+		printUsageRow(name, u)
+		total.Add(u)
+	}
+	fmt.Println(strings.Repeat("─", 87))
+	printUsageRow("Total", total)
+	return nil
+}
+
+func printUsageRow(name string, u usage.Usage) {
+	cost := style.Dim.Render("—")
+	if u.CostUSD > 0 {
+		cost = fmt.Sprintf("$%.2f", u.CostUSD)
+	}
+	fmt.Printf("%-30s %10s %9s %12d %12d %9s\n",
+		name,
+		(time.Duration(u.CPUSeconds) * time.Second).String(),
+		fmt.Sprintf("%dM", u.PeakRSS>>20),
+		u.InputTokens+u.CacheCreationTokens+u.CacheReadTokens,
+		u.OutputTokens,
+		cost)
+}
+
+func runUsageSample(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	sampler := &usage.Sampler{TownRoot: townRoot, Sessions: tmux.NewTmux()}
+	sampled, err := sampler.Sample()
+	if err != nil {
+		return fmt.Errorf("sampling usage: %w", err)
+	}
+	for _, u := range sampled {
+		fmt.Printf("  %-30s %s CPU, %dM, %d tokens\n", u.Agent,
+			(time.Duration(u.CPUSeconds) * time.Second).String(), u.PeakRSS>>20, u.Tokens())
+	}
+	fmt.Printf("%s Sampled %d session(s)\n", style.Success.Render("✓"), len(sampled))
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessReportJSON bool
+	witnessReportLast int
+)
+
+var witnessReportCmd = &cobra.Command{
+	Use:   "report [rig]",
+	Short: "Show the witness's structured patrol reports",
+	Long: `Show the structured reports the Witness writes for each patrol cycle.
+
+Each report lists every polecat checked (status, hooked issue, and any stuck
+diagnosis) and the actions taken (nudges and escalations). Reports are built
+from the witness's 'gt activity emit' patrol events and kept in
+<rig>/witness/patrol/.
+
+If rig is not specified, infers it from the current directory.
+
+Examples:
+  gt witness report greenplace
+  gt witness report greenplace --last 5
+  gt witness report --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWitnessReport,
+}
+
+func init() {
+	witnessReportCmd.Flags().BoolVar(&witnessReportJSON, "json", false, "Output as JSON")
+	witnessReportCmd.Flags().IntVar(&witnessReportLast, "last", 1, "Number of completed patrols to show")
+
+	witnessCmd.AddCommand(witnessReportCmd)
+}
+
+func runWitnessReport(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	} else {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("could not determine rig: %w\nUsage: gt witness report <rig>", err)
+		}
+	}
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	reports, err := witness.ReadReports(r.Path, witnessReportLast)
+	if err != nil {
+		return fmt.Errorf("reading patrol reports: %w", err)
+	}
+	current, err := witness.CurrentPatrol(r.Path)
+	if err != nil {
+		return fmt.Errorf("reading current patrol: %w", err)
+	}
+
+	if witnessReportJSON {
+		out := struct {
+			Rig     string                 `json:"rig"`
+			Current *witness.PatrolReport  `json:"current,omitempty"`
+			Reports []witness.PatrolReport `json:"reports"`
+		}{Rig: rigName, Current: current, Reports: reports}
+		if out.Reports == nil {
+			out.Reports = []witness.PatrolReport{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s Witness patrols: %s\n", style.Bold.Render(AgentTypeIcons[AgentWitness]), rigName)
+	if current != nil {
+		fmt.Printf("\n  %s started %s\n", style.Bold.Render("In progress:"), current.StartedAt.Local().Format("15:04:05"))
+		printPatrolReport(current)
+	}
+	if len(reports) == 0 {
+		fmt.Printf("\n  %s\n", style.Dim.Render("No completed patrols recorded"))
+		return nil
+	}
+	for i := len(reports) - 1; i >= 0; i-- {
+		report := &reports[i]
+		when := report.StartedAt
+		if report.CompletedAt != nil {
+			when = *report.CompletedAt
+		}
+		fmt.Printf("\n  %s %s\n", style.Bold.Render(when.Local().Format("2006-01-02 15:04:05")), report.Headline())
+		printPatrolReport(report)
+	}
+	return nil
+}
+
+func printPatrolReport(report *witness.PatrolReport) {
+	if report.Summary != "" {
+		fmt.Printf("    %s\n", style.Dim.Render(report.Summary))
+	}
+	for _, p := range report.Polecats {
+		line := fmt.Sprintf("%-12s %s", p.Name, p.Status)
+		if p.Issue != "" {
+			line += " " + style.Dim.Render(p.Issue)
+		}
+		if p.Diagnosis != "" {
+			line += " — " + p.Diagnosis
+		}
+		fmt.Printf("    • %s\n", line)
+	}
+	for _, a := range report.Actions {
+		target := a.Target
+		if a.To != "" {
+			target += " → " + a.To
+		}
+		fmt.Printf("    %s %s %s", style.Warning.Render("↳"), a.Kind, target)
+		if a.Reason != "" {
+			fmt.Printf(": %s", a.Reason)
+		}
+		fmt.Println()
+	}
+}
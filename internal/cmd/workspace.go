@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	workspaceWhichExplain bool
+	workspaceWhichJSON    bool
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:     "workspace",
+	GroupID: GroupWorkspace,
+	Short:   "Inspect Gas Town workspace discovery",
+	RunE:    requireSubcommand,
+}
+
+var workspaceWhichCmd = &cobra.Command{
+	Use:   "which [dir]",
+	Short: "Show which town root commands will use",
+	Long: `Show the town root gt commands resolve from a directory (default: cwd).
+
+Discovery rules, in order:
+  1. GT_TOWN_ROOT wins if it has mayor/town.json and contains the directory
+     (or the working directory no longer exists).
+  2. Outside a polecats/ or crew/ worktree, the innermost mayor/town.json wins,
+     so a town nested inside another town resolves to itself.
+  3. Inside a worktree, the outermost mayor/town.json wins: a rig checkout may
+     carry its own town files, but the agent belongs to the enclosing town.
+  4. With no mayor/town.json, the outermost mayor/ directory wins.
+
+Use --explain to see every marker found and why the root was chosen.
+
+Examples:
+  gt workspace which
+  gt workspace which --explain
+  gt workspace which ~/gt/gastown/crew/max --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorkspaceWhich,
+}
+
+func init() {
+	workspaceWhichCmd.Flags().BoolVar(&workspaceWhichExplain, "explain", false, "Show markers found and why the root was chosen")
+	workspaceWhichCmd.Flags().BoolVar(&workspaceWhichJSON, "json", false, "Output as JSON")
+
+	workspaceCmd.AddCommand(workspaceWhichCmd)
+	rootCmd.AddCommand(workspaceCmd)
+}
+
+func runWorkspaceWhich(cmd *cobra.Command, args []string) error {
+	var res *workspace.Resolution
+	var err error
+	if len(args) == 1 {
+		res, err = workspace.Resolve(args[0])
+	} else {
+		res, err = workspace.ResolveFromCwd()
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case workspaceWhichJSON:
+		out, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding resolution: %w", err)
+		}
+		fmt.Println(string(out))
+	case workspaceWhichExplain:
+		printResolution(res)
+	case res.Root == "":
+		return workspace.ErrNotFound
+	default:
+		fmt.Println(res.Root)
+	}
+	return nil
+}
+
+func printResolution(res *workspace.Resolution) {
+	if res.Root != "" {
+		fmt.Printf("%s %s %s\n", style.Bold.Render("Town root:"), res.Root, style.Dim.Render("(via "+res.Source+")"))
+	} else {
+		fmt.Printf("%s %s\n", style.Bold.Render("Town root:"), "none")
+	}
+
+	startDir := res.StartDir
+	if startDir == "" {
+		startDir = "(working directory unavailable)"
+	}
+	fmt.Printf("%s %s\n", style.Dim.Render("Start:    "), startDir)
+	if res.EnvRoot != "" {
+		fmt.Printf("%s %s\n", style.Dim.Render("Env:      "), workspace.TownRootEnv+"="+res.EnvRoot)
+	}
+	if res.InWorktree {
+		fmt.Printf("%s inside a polecats/ or crew/ worktree\n", style.Dim.Render("Worktree: "))
+	}
+	if res.Nested {
+		fmt.Printf("%s nested towns detected\n", style.Dim.Render("Nested:   "))
+	}
+
+	if len(res.Candidates) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Markers (innermost first):"))
+		for _, c := range res.Candidates {
+			mark := " "
+			if c.Chosen {
+				mark = style.Bold.Render("→")
+			}
+			fmt.Printf("  %s %s %s\n", mark, c.Path, style.Dim.Render(c.Marker))
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Reasoning:"))
+	for _, reason := range res.Reasons {
+		fmt.Printf("  - %s\n", reason)
+	}
+}
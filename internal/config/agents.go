@@ -69,6 +69,10 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// StartupPrompts are dialogs the agent may show at startup that must be
+	// accepted before it takes input (e.g., Claude's bypass permissions warning).
+	StartupPrompts []StartupPromptRule `json:"startup_prompts,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -109,6 +113,15 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       true,
 		SupportsForkSession: true,
 		NonInteractive:      nil, // Claude is native non-interactive
+		StartupPrompts: []StartupPromptRule{
+			{
+				// Shown when starting with --dangerously-skip-permissions:
+				// Down selects "Yes, I accept", Enter confirms.
+				Name:  "bypass-permissions",
+				Match: "Bypass Permissions mode",
+				Keys:  []string{"Down", "Enter"},
+			},
+		},
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
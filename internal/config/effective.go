@@ -0,0 +1,250 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Rig settings (<rig>/settings/config.json) overlay the town's configuration.
+// For each setting a rig can override, the value comes from the first of:
+//
+//  1. the rig's settings
+//  2. the town's config: settings/config.json, mayor/overseer.json
+//     (notifications) and mayor/daemon.json (patrols)
+//  3. built-in defaults
+//
+// Map-valued settings (role_agents, patrols) are merged key by key, so a rig
+// overriding one role's agent still inherits the others.
+
+// Sources of an effective setting.
+const (
+	SourceRig     = "rig"
+	SourceTown    = "town"
+	SourceDefault = "default"
+)
+
+// RigPatrols are the daemon patrols a rig's settings can override.
+var RigPatrols = []string{"witness", "refinery"}
+
+func isRigPatrol(name string) bool {
+	for _, p := range RigPatrols {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveNotifyConfig returns the desktop notification settings for mail
+// from a rig's agents: the rig's overrides on top of the overseer's, with
+// defaults filled in. Either argument may be nil.
+func EffectiveNotifyConfig(overseer *OverseerConfig, rig *RigSettings) OverseerNotifyConfig {
+	var cfg OverseerNotifyConfig
+	if overseer != nil && overseer.Notifications != nil {
+		cfg = *overseer.Notifications
+	}
+	if rig != nil && rig.Notifications != nil {
+		if rig.Notifications.MinPriority != "" {
+			cfg.MinPriority = rig.Notifications.MinPriority
+		}
+		if rig.Notifications.Command != "" {
+			cfg.Command = rig.Notifications.Command
+		}
+	}
+	if cfg.MinPriority == "" {
+		cfg.MinPriority = DefaultNotifyMinPriority
+	}
+	return cfg
+}
+
+// RigPatrolEnabled reports whether a patrol runs for a rig: the rig's
+// override if its settings have one, otherwise townEnabled.
+func RigPatrolEnabled(rig *RigSettings, patrol string, townEnabled bool) bool {
+	if rig != nil {
+		if p, ok := rig.Patrols[patrol]; ok {
+			return p.Enabled
+		}
+	}
+	return townEnabled
+}
+
+// EffectiveSetting is one resolved setting and where its value came from.
+type EffectiveSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // SourceRig, SourceTown or SourceDefault
+}
+
+// EffectiveRigConfig is a rig's configuration after overlaying its settings
+// on the town's.
+type EffectiveRigConfig struct {
+	Rig      string             `json:"rig"`
+	Settings []EffectiveSetting `json:"settings"`
+}
+
+// Get returns the effective setting for key, or nil if there is none.
+func (c *EffectiveRigConfig) Get(key string) *EffectiveSetting {
+	for i := range c.Settings {
+		if c.Settings[i].Key == key {
+			return &c.Settings[i]
+		}
+	}
+	return nil
+}
+
+func (c *EffectiveRigConfig) add(key, value, source string) {
+	c.Settings = append(c.Settings, EffectiveSetting{Key: key, Value: value, Source: source})
+}
+
+// ResolveEffectiveRigConfig loads the town and rig configuration and
+// returns the rig's effective settings. Missing config files are treated
+// as empty; invalid ones are errors.
+func ResolveEffectiveRigConfig(townRoot, rigName string) (*EffectiveRigConfig, error) {
+	var town *TownSettings
+	if _, err := os.Stat(TownSettingsPath(townRoot)); err == nil {
+		town, err = LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading town settings: %w", err)
+		}
+	}
+
+	rig, err := LoadRigSettings(RigSettingsPath(filepath.Join(townRoot, rigName)))
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("loading rig settings: %w", err)
+		}
+		rig = nil
+	}
+
+	overseer, err := LoadOverseerConfig(OverseerConfigPath(townRoot))
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("loading overseer config: %w", err)
+		}
+		overseer = nil
+	}
+
+	patrols, err := LoadDaemonPatrolConfig(DaemonPatrolConfigPath(townRoot))
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("loading daemon patrol config: %w", err)
+		}
+		patrols = nil
+	}
+
+	return effectiveRigConfig(rigName, town, rig, overseer, patrols), nil
+}
+
+// effectiveRigConfig resolves a rig's settings from already-loaded config.
+// Any argument but rigName may be nil.
+func effectiveRigConfig(rigName string, town *TownSettings, rig *RigSettings, overseer *OverseerConfig, patrols *DaemonPatrolConfig) *EffectiveRigConfig {
+	c := &EffectiveRigConfig{Rig: rigName}
+
+	// Agent
+	switch {
+	case rig != nil && rig.Agent != "":
+		c.add("agent", rig.Agent, SourceRig)
+	case town != nil && town.DefaultAgent != "":
+		c.add("agent", town.DefaultAgent, SourceTown)
+	default:
+		c.add("agent", "claude", SourceDefault)
+	}
+
+	roles := make(map[string]bool)
+	if town != nil {
+		for role := range town.RoleAgents {
+			roles[role] = true
+		}
+	}
+	if rig != nil {
+		for role := range rig.RoleAgents {
+			roles[role] = true
+		}
+	}
+	for _, role := range sortedKeys(roles) {
+		if rig != nil && rig.RoleAgents[role] != "" {
+			c.add("role_agents."+role, rig.RoleAgents[role], SourceRig)
+		} else if town != nil && town.RoleAgents[role] != "" {
+			c.add("role_agents."+role, town.RoleAgents[role], SourceTown)
+		}
+	}
+
+	// Namepool: the whole config comes from one level, as in
+	// polecat.NamepoolConfigFor
+	pool, poolSource := DefaultNamepoolConfig(), SourceDefault
+	switch {
+	case rig != nil && rig.Namepool != nil:
+		pool, poolSource = rig.Namepool, SourceRig
+	case town != nil && town.Namepool != nil:
+		pool, poolSource = town.Namepool, SourceTown
+	}
+	style := pool.Style
+	if len(pool.Names) > 0 {
+		style = fmt.Sprintf("custom (%d names)", len(pool.Names))
+	} else if style == "" {
+		style = DefaultNamepoolConfig().Style
+	}
+	c.add("namepool.style", style, poolSource)
+	maxBefore := pool.MaxBeforeNumbering
+	if maxBefore == 0 {
+		maxBefore = DefaultNamepoolConfig().MaxBeforeNumbering
+	}
+	c.add("namepool.max_before_numbering", strconv.Itoa(maxBefore), poolSource)
+
+	// Notifications
+	var townNotify, rigNotify OverseerNotifyConfig
+	if overseer != nil && overseer.Notifications != nil {
+		townNotify = *overseer.Notifications
+	}
+	if rig != nil && rig.Notifications != nil {
+		rigNotify = *rig.Notifications
+	}
+	value, source := layered(rigNotify.MinPriority, townNotify.MinPriority, DefaultNotifyMinPriority)
+	c.add("notifications.min_priority", value, source)
+	value, source = layered(rigNotify.Command, townNotify.Command, "auto")
+	c.add("notifications.command", value, source)
+
+	// Patrols: a patrol missing from mayor/daemon.json runs, as in the daemon
+	for _, patrol := range RigPatrols {
+		townEnabled, source := true, SourceDefault
+		if patrols != nil {
+			if p, ok := patrols.Patrols[patrol]; ok {
+				townEnabled, source = p.Enabled, SourceTown
+			}
+		}
+		if rig != nil {
+			if _, ok := rig.Patrols[patrol]; ok {
+				source = SourceRig
+			}
+		}
+		c.add("patrols."+patrol, strconv.FormatBool(RigPatrolEnabled(rig, patrol, townEnabled)), source)
+	}
+
+	return c
+}
+
+// layered returns the first non-empty of the rig and town values, or def,
+// with its source.
+func layered(rigValue, townValue, def string) (string, string) {
+	switch {
+	case rigValue != "":
+		return rigValue, SourceRig
+	case townValue != "":
+		return townValue, SourceTown
+	default:
+		return def, SourceDefault
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
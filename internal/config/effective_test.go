@@ -0,0 +1,103 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveEffectiveRigConfig(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+
+	town := NewTownSettings()
+	town.DefaultAgent = "gemini"
+	town.RoleAgents = map[string]string{"polecat": "claude-sonnet", "witness": "claude-haiku"}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+
+	overseer := &OverseerConfig{Name: "Steve", Notifications: &OverseerNotifyConfig{MinPriority: "normal", Command: "notify-send"}}
+	if err := SaveOverseerConfig(OverseerConfigPath(townRoot), overseer); err != nil {
+		t.Fatal(err)
+	}
+
+	patrols := NewDaemonPatrolConfig()
+	patrols.Patrols["witness"] = PatrolConfig{Enabled: false}
+	if err := SaveDaemonPatrolConfig(DaemonPatrolConfigPath(townRoot), patrols); err != nil {
+		t.Fatal(err)
+	}
+
+	rig := NewRigSettings()
+	rig.RoleAgents = map[string]string{"polecat": "codex"}
+	rig.Notifications = &OverseerNotifyConfig{MinPriority: "urgent"}
+	rig.Patrols = map[string]PatrolConfig{"refinery": {Enabled: false}}
+	if err := SaveRigSettings(RigSettingsPath(filepath.Join(townRoot, "gastown")), rig); err != nil {
+		t.Fatal(err)
+	}
+
+	eff, err := ResolveEffectiveRigConfig(townRoot, "gastown")
+	if err != nil {
+		t.Fatalf("ResolveEffectiveRigConfig: %v", err)
+	}
+
+	tests := []struct {
+		key, value, source string
+	}{
+		{"agent", "gemini", SourceTown},
+		{"role_agents.polecat", "codex", SourceRig},
+		{"role_agents.witness", "claude-haiku", SourceTown},
+		{"namepool.style", "mad-max", SourceRig},
+		{"notifications.min_priority", "urgent", SourceRig},
+		{"notifications.command", "notify-send", SourceTown},
+		{"patrols.witness", "false", SourceTown},
+		{"patrols.refinery", "false", SourceRig},
+	}
+	for _, tt := range tests {
+		got := eff.Get(tt.key)
+		if got == nil {
+			t.Errorf("%s: missing", tt.key)
+			continue
+		}
+		if got.Value != tt.value || got.Source != tt.source {
+			t.Errorf("%s = %q (%s), want %q (%s)", tt.key, got.Value, got.Source, tt.value, tt.source)
+		}
+	}
+}
+
+func TestResolveEffectiveRigConfigDefaults(t *testing.T) {
+	t.Parallel()
+	eff, err := ResolveEffectiveRigConfig(t.TempDir(), "gastown")
+	if err != nil {
+		t.Fatalf("ResolveEffectiveRigConfig: %v", err)
+	}
+	for _, s := range eff.Settings {
+		if s.Source != SourceDefault {
+			t.Errorf("%s source = %s, want default with no config files", s.Key, s.Source)
+		}
+	}
+	if got := eff.Get("patrols.witness"); got == nil || got.Value != "true" {
+		t.Errorf("patrols.witness = %+v, want enabled by default", got)
+	}
+}
+
+func TestEffectiveNotifyConfig(t *testing.T) {
+	t.Parallel()
+	if got := EffectiveNotifyConfig(nil, nil); got.MinPriority != DefaultNotifyMinPriority {
+		t.Errorf("default MinPriority = %q, want %q", got.MinPriority, DefaultNotifyMinPriority)
+	}
+	overseer := &OverseerConfig{Notifications: &OverseerNotifyConfig{MinPriority: "low", Command: "osascript"}}
+	rig := &RigSettings{Notifications: &OverseerNotifyConfig{MinPriority: "off"}}
+	got := EffectiveNotifyConfig(overseer, rig)
+	if got.MinPriority != "off" || got.Command != "osascript" {
+		t.Errorf("EffectiveNotifyConfig = %+v, want rig min_priority and town command", got)
+	}
+}
+
+func TestRigSettingsRejectsUnknownPatrol(t *testing.T) {
+	t.Parallel()
+	rig := NewRigSettings()
+	rig.Patrols = map[string]PatrolConfig{"deacon": {Enabled: false}}
+	if err := SaveRigSettings(RigSettingsPath(t.TempDir()), rig); err == nil {
+		t.Error("rig settings overriding the deacon patrol should be rejected")
+	}
+}
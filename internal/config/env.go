@@ -19,6 +19,7 @@ type AgentEnvConfig struct {
 
 	// AgentName is the specific agent name (empty for singletons like witness/refinery)
 	// For polecats, this is the polecat name. For crew, this is the crew member name.
+	// For a named narrator instance, this is the instance name.
 	AgentName string
 
 	// TownRoot is the root of the Gas Town workspace.
@@ -79,6 +80,11 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["GT_CREW"] = cfg.AgentName
 		env["BD_ACTOR"] = fmt.Sprintf("%s/crew/%s", cfg.Rig, cfg.AgentName)
 		env["GIT_AUTHOR_NAME"] = cfg.AgentName
+
+	case "narrator":
+		if cfg.AgentName != "" {
+			env["GT_NARRATOR"] = cfg.AgentName
+		}
 	}
 
 	// Only set GT_ROOT if provided
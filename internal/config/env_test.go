@@ -72,6 +72,22 @@ func TestAgentEnv_Crew(t *testing.T) {
 	assertEnv(t, env, "BEADS_NO_DAEMON", "1")
 }
 
+func TestAgentEnv_NarratorInstance(t *testing.T) {
+	t.Parallel()
+	env := AgentEnv(AgentEnvConfig{
+		Role:      "narrator",
+		AgentName: "changelog",
+		TownRoot:  "/town",
+	})
+
+	assertEnv(t, env, "GT_ROLE", "narrator")
+	assertEnv(t, env, "GT_NARRATOR", "changelog")
+	assertNotSet(t, env, "GT_RIG")
+
+	env = AgentEnv(AgentEnvConfig{Role: "narrator", TownRoot: "/town"})
+	assertNotSet(t, env, "GT_NARRATOR")
+}
+
 func TestAgentEnv_Refinery(t *testing.T) {
 	t.Parallel()
 	env := AgentEnv(AgentEnvConfig{
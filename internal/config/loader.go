@@ -212,6 +212,14 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if err := validateNotifyConfig(c.Notifications); err != nil {
+		return err
+	}
+	for name := range c.Patrols {
+		if !isRigPatrol(name) {
+			return fmt.Errorf("invalid patrol %q: rig settings can override %s", name, strings.Join(RigPatrols, " and "))
+		}
+	}
 	return nil
 }
 
@@ -693,6 +701,50 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	switch c.Transport {
+	case "", MailTransportBeads, MailTransportFile:
+	default:
+		return fmt.Errorf("invalid mail transport %q: want %q or %q", c.Transport, MailTransportBeads, MailTransportFile)
+	}
+
+	for role, policy := range c.Notify {
+		for _, tier := range []string{policy.Urgent, policy.High, policy.Normal, policy.Low} {
+			switch tier {
+			case "", MailNotifyRepeat, MailNotifyImmediate, MailNotifyBatch, MailNotifyNone:
+			default:
+				return fmt.Errorf("notify '%s': invalid tier %q (want %s, %s, %s or %s)", role, tier,
+					MailNotifyRepeat, MailNotifyImmediate, MailNotifyBatch, MailNotifyNone)
+			}
+		}
+		if policy.Repeat != "" {
+			if d, err := time.ParseDuration(policy.Repeat); err != nil || d <= 0 {
+				return fmt.Errorf("notify '%s': invalid repeat %q", role, policy.Repeat)
+			}
+		}
+	}
+
+	if r := c.Retention; r != nil {
+		if r.ArchiveAfterDays < 0 || r.PurgeAfterDays < 0 {
+			return fmt.Errorf("retention: days must be non-negative")
+		}
+		if r.ArchiveAfterDays > 0 && r.PurgeAfterDays > 0 && r.PurgeAfterDays < r.ArchiveAfterDays {
+			return fmt.Errorf("retention: purge_after_days (%d) is before archive_after_days (%d)", r.PurgeAfterDays, r.ArchiveAfterDays)
+		}
+	}
+
+	// Validate routes point inside the town
+	for i, route := range c.Routes {
+		if strings.Trim(route.Prefix, "/") == "" {
+			return fmt.Errorf("%w: route %d prefix", ErrMissingField, i)
+		}
+		if route.Path == "" {
+			return fmt.Errorf("%w: route '%s' path", ErrMissingField, route.Prefix)
+		}
+		if filepath.IsAbs(route.Path) || strings.HasPrefix(filepath.Clean(route.Path), "..") {
+			return fmt.Errorf("route '%s' path must be relative to the town root: %s", route.Prefix, route.Path)
+		}
+	}
+
 	return nil
 }
 
@@ -899,6 +951,17 @@ func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*R
 	return lookupAgentConfig(agentName, townSettings, rigSettings), agentName, nil
 }
 
+// ResolveRoleAgentConfigWithOverride resolves the runtime config a role's session
+// runs with, using the same priority as BuildStartupCommandWithAgentOverride:
+// agentOverride first, then role_agents, then the default agent.
+func ResolveRoleAgentConfigWithOverride(role, townRoot, rigPath, agentOverride string) (*RuntimeConfig, error) {
+	if agentOverride != "" {
+		rc, _, err := ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
+		return rc, err
+	}
+	return ResolveRoleAgentConfig(role, townRoot, rigPath), nil
+}
+
 // ValidateAgentConfig checks if an agent configuration is valid and the binary exists.
 // Returns an error describing the issue, or nil if valid.
 func ValidateAgentConfig(agentName string, townSettings *TownSettings, rigSettings *RigSettings) error {
@@ -1084,6 +1147,8 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 		Command:       rc.Command,
 		Args:          rc.Args,
 		InitialPrompt: rc.InitialPrompt,
+		Prompts:       rc.Prompts,
+		Startup:       rc.Startup,
 	}
 	if result.Command == "" {
 		result.Command = "claude"
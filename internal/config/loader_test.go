@@ -829,6 +829,12 @@ func TestRuntimeConfigDefaults(t *testing.T) {
 	if rc.Session == nil || rc.Session.SessionIDEnv != "CLAUDE_SESSION_ID" {
 		t.Errorf("SessionIDEnv = %q, want %q", rc.Session.SessionIDEnv, "CLAUDE_SESSION_ID")
 	}
+	if rc.Prompts == nil || rc.Prompts.AutoAccept != PromptPolicyAlways {
+		t.Errorf("Prompts.AutoAccept = %v, want %q", rc.Prompts, PromptPolicyAlways)
+	}
+	if len(rc.Prompts.Rules) != 1 || rc.Prompts.Rules[0].Name != "bypass-permissions" {
+		t.Errorf("Prompts.Rules = %v, want [bypass-permissions]", rc.Prompts.Rules)
+	}
 }
 
 func TestRuntimeConfigBuildCommand(t *testing.T) {
@@ -2400,6 +2406,31 @@ func TestEscalationConfigGetStaleThreshold(t *testing.T) {
 	}
 }
 
+func TestTownSettingsGetDrainTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings *TownSettings
+		expected time.Duration
+	}{
+		{"nil settings", nil, DefaultDrainTimeout},
+		{"default when empty", &TownSettings{}, DefaultDrainTimeout},
+		{"30 seconds", &TownSettings{DrainTimeout: "30s"}, 30 * time.Second},
+		{"zero disables the wait", &TownSettings{DrainTimeout: "0s"}, 0},
+		{"invalid duration falls back to default", &TownSettings{DrainTimeout: "soon"}, DefaultDrainTimeout},
+		{"negative falls back to default", &TownSettings{DrainTimeout: "-1m"}, DefaultDrainTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.GetDrainTimeout(); got != tt.expected {
+				t.Errorf("GetDrainTimeout() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestEscalationConfigGetRouteForSeverity(t *testing.T) {
 	t.Parallel()
 
@@ -2603,3 +2634,27 @@ func TestBuildStartupCommandWithAgentOverride_IncludesGTRoot(t *testing.T) {
 		t.Errorf("expected GT_ROOT=%s in command, got: %q", townRoot, cmd)
 	}
 }
+
+func TestMessagingConfigRoutesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  []MailRoute
+		wantErr bool
+	}{
+		{"valid", []MailRoute{{Prefix: "gastown/", Path: "gastown/mayor/rig"}}, false},
+		{"empty prefix", []MailRoute{{Prefix: "/", Path: "gastown"}}, true},
+		{"empty path", []MailRoute{{Prefix: "gastown/"}}, true},
+		{"absolute path", []MailRoute{{Prefix: "gastown/", Path: "/srv/beads"}}, true},
+		{"escapes town", []MailRoute{{Prefix: "gastown/", Path: "../other"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewMessagingConfig()
+			cfg.Routes = tt.routes
+			err := validateMessagingConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMessagingConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
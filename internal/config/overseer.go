@@ -18,6 +18,35 @@ type OverseerConfig struct {
 	Email    string `json:"email,omitempty"`    // email address
 	Username string `json:"username,omitempty"` // username/handle
 	Source   string `json:"source"`             // how identity was detected
+
+	// Notifications configures desktop notifications for mail to the
+	// overseer. Nil uses the defaults (notify for high and urgent mail).
+	Notifications *OverseerNotifyConfig `json:"notifications,omitempty"`
+}
+
+// DefaultNotifyMinPriority is the lowest mail priority that triggers a
+// desktop notification when none is configured.
+const DefaultNotifyMinPriority = "high"
+
+// OverseerNotifyConfig configures desktop notifications for the overseer.
+type OverseerNotifyConfig struct {
+	// MinPriority is the lowest mail priority that raises a desktop
+	// notification: "low", "normal", "high" or "urgent", or "off" to
+	// disable desktop notifications.
+	MinPriority string `json:"min_priority,omitempty"`
+
+	// Command forces a notification backend: "terminal-notifier",
+	// "osascript" or "notify-send". Empty picks one for the platform.
+	Command string `json:"command,omitempty"`
+}
+
+// NotifyMinPriority returns the configured notification threshold, or the
+// default if unset.
+func (c *OverseerConfig) NotifyMinPriority() string {
+	if c == nil || c.Notifications == nil || c.Notifications.MinPriority == "" {
+		return DefaultNotifyMinPriority
+	}
+	return c.Notifications.MinPriority
 }
 
 // CurrentOverseerVersion is the current schema version for OverseerConfig.
@@ -88,6 +117,20 @@ func validateOverseerConfig(c *OverseerConfig) error {
 	if c.Name == "" {
 		return fmt.Errorf("%w: name", ErrMissingField)
 	}
+	return validateNotifyConfig(c.Notifications)
+}
+
+// validateNotifyConfig validates notification settings from the overseer
+// config or a rig overlay. Nil is valid.
+func validateNotifyConfig(c *OverseerNotifyConfig) error {
+	if c == nil {
+		return nil
+	}
+	switch c.MinPriority {
+	case "", "off", "low", "normal", "high", "urgent":
+	default:
+		return fmt.Errorf("invalid notifications.min_priority %q: must be low, normal, high, urgent or off", c.MinPriority)
+	}
 	return nil
 }
 
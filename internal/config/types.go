@@ -61,6 +61,480 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Events configures how the town's event log is written.
+	Events *EventsConfig `json:"events,omitempty"`
+
+	// DrainTimeout is how long an agent with hooked work is given to commit
+	// and hand off before it is killed (Go duration, e.g. "2m").
+	// Default: "2m"
+	DrainTimeout string `json:"drain_timeout,omitempty"`
+
+	// LifecycleHooks maps lifecycle events ("pre-spawn", "post-start",
+	// "pre-kill", "post-done") to scripts run with the agent's environment.
+	// Rig settings can add more hooks for their own agents.
+	LifecycleHooks map[string][]LifecycleHook `json:"lifecycle_hooks,omitempty"`
+
+	// Namepool is the town-wide polecat name pool, used by rigs whose own
+	// settings don't configure one. Keeping one theme across rigs keeps the
+	// cast of polecat names stable.
+	Namepool *NamepoolConfig `json:"namepool,omitempty"`
+
+	// Multiplexer selects the terminal multiplexer agent sessions run in:
+	// "tmux" (default) or "zellij". GT_MUX overrides it.
+	Multiplexer string `json:"multiplexer,omitempty"`
+
+	// Narrator configures how town history is narrated.
+	Narrator *NarratorConfig `json:"narrator,omitempty"`
+
+	// Restart maps town-level agents ("mayor", "deacon") to the policy the
+	// daemon's supervisor restarts them by when they crash or turn into
+	// zombies. Unlisted agents keep the default: the deacon is kept running
+	// by the heartbeat and the mayor is left alone.
+	Restart map[string]*RestartPolicy `json:"restart,omitempty"`
+}
+
+// Restart policies for RestartPolicy.Policy.
+const (
+	// RestartNever leaves a dead agent down.
+	RestartNever = "never"
+	// RestartOnFailure restarts an agent whose session crashed or became a
+	// zombie, but not one stopped on purpose (gt mayor stop).
+	RestartOnFailure = "on-failure"
+	// RestartAlways keeps the agent running, starting it whenever it is down.
+	RestartAlways = "always"
+)
+
+// RestartPolicy is how the supervisor treats an agent that goes down.
+type RestartPolicy struct {
+	// Policy is "never", "on-failure" (default) or "always".
+	Policy string `json:"policy,omitempty"`
+
+	// MaxRestarts is how many restarts in a row the supervisor attempts
+	// before giving up; an agent that stays up for ten minutes starts a new
+	// run. 0 means no limit.
+	MaxRestarts int `json:"max_restarts,omitempty"`
+
+	// Backoff is the wait before a repeated restart (Go duration), doubled
+	// for each restart in a row up to 30m. Default: "30s".
+	Backoff string `json:"backoff,omitempty"`
+}
+
+// DefaultRestartBackoff is the restart backoff when none is configured.
+const DefaultRestartBackoff = 30 * time.Second
+
+// GetBackoff returns Backoff as a time.Duration, or DefaultRestartBackoff if
+// unset or invalid.
+func (p *RestartPolicy) GetBackoff() time.Duration {
+	if p == nil || p.Backoff == "" {
+		return DefaultRestartBackoff
+	}
+	d, err := time.ParseDuration(p.Backoff)
+	if err != nil || d < 0 {
+		return DefaultRestartBackoff
+	}
+	return d
+}
+
+// GetPolicy returns Policy, defaulting to "on-failure".
+func (p *RestartPolicy) GetPolicy() string {
+	if p == nil || p.Policy == "" {
+		return RestartOnFailure
+	}
+	return p.Policy
+}
+
+// NarratorConfig configures the narrator.
+type NarratorConfig struct {
+	// OnResume is what happens to events that arrived while the narrator
+	// was paused: "summarize" (default) writes a catch-up chapter, "skip"
+	// drops them from the narrative.
+	OnResume string `json:"on_resume,omitempty"`
+
+	// OutputFormat renders each chapter to "markdown", "html" (a
+	// standalone page in Theme) or "text". Default: the format the
+	// chapter's style writes.
+	OutputFormat string `json:"output_format,omitempty"`
+
+	// OutputDir is where chapters are written: a Go template of a path,
+	// relative to the town root unless absolute, given .Date (2006-01-02),
+	// .Time (150405), .Rig ("town" for town-wide chapters), .N (chapter
+	// number) and .Style. A path with an extension names the chapter file
+	// (the extension follows the chapter's format); one without names its
+	// directory. Directories are created as needed. Example:
+	// "history/{{.Date}}/{{.Rig}}/chapter-{{.N}}.md". Default: narrator/.
+	OutputDir string `json:"output_dir,omitempty"`
+
+	// Styles are further styles each chapter is also written in, from the
+	// same events: one generation pass can write a book chapter and a
+	// short video script alike. The chapter in the generation's own style
+	// is the chapter of record; each of these is an edition of it, written
+	// to its style's output directory.
+	Styles []NarratorStyle `json:"styles,omitempty"`
+
+	// Tone is the register chapters and summaries are written in:
+	// "corporate-neutral", "grimdark", "comedic" or "epic". Default: the
+	// style's own.
+	Tone string `json:"tone,omitempty"`
+
+	// Intensity is how strongly Tone is laid on, 1 (a hint) to 5 (never
+	// breaking character). Default: 3.
+	Intensity int `json:"intensity,omitempty"`
+
+	// Theme is the theme of HTML chapters: a built-in one ("default",
+	// "dark") or a directory in narrator/themes/. Default: "default".
+	Theme string `json:"theme,omitempty"`
+
+	// GitOutput, when set, commits each chapter to a branch of the town
+	// repo so the story's history is versioned and diffable.
+	GitOutput *NarratorGitOutput `json:"git_output,omitempty"`
+
+	// Illustrations, when set, writes an image-generation prompt for each
+	// significant scene of a chapter, and optionally renders them.
+	Illustrations *NarratorIllustrations `json:"illustrations,omitempty"`
+
+	// Publish configures the static site built from the chapters by
+	// 'gt narrator publish', and whether it is rebuilt after each chapter.
+	Publish *NarratorPublish `json:"publish,omitempty"`
+
+	// Quota, when set, bounds how much narration is generated, so the
+	// narrating agent's cost stays bounded on a busy town.
+	Quota *NarratorQuota `json:"quota,omitempty"`
+
+	// Digest, when set, narrates only high-significance events and writes
+	// the rest up as a digest per rig once each interval.
+	Digest *NarratorDigest `json:"digest,omitempty"`
+
+	// Trigger configures 'gt narrator serve', the HTTP endpoint other
+	// systems (a deploy pipeline, say) ask for a chapter on demand.
+	Trigger *NarratorTrigger `json:"trigger,omitempty"`
+
+	// Review, when set, holds each chapter back from the site and feed
+	// until a human approves it with 'gt narrator approve'.
+	Review *NarratorReview `json:"review,omitempty"`
+
+	// Instances are named narrators run alongside the town's own, each in
+	// its session ('gt narrator start --name <name>') with its run state
+	// in narrator/narrator-<name>.json: one per rig, say, or one writing
+	// a changelog. A name without an entry runs with the defaults.
+	Instances map[string]NarratorInstance `json:"instances,omitempty"`
+}
+
+// NarratorInstance configures a named narrator. Its chapters join the
+// town's story like any other; the rest of the narrator settings apply to
+// it as to the town's narrator.
+type NarratorInstance struct {
+	// Rig narrows its chapters to one rig's events. Default: town-wide.
+	Rig string `json:"rig,omitempty"`
+
+	// Style is the style it writes in. Default: the default style.
+	Style string `json:"style,omitempty"`
+
+	// Agent is the agent alias it runs. Default: role_agents.narrator.
+	Agent string `json:"agent,omitempty"`
+
+	// OutputDir is where its chapters are written, a path template as
+	// NarratorConfig.OutputDir. Default: narrator/<name>/.
+	OutputDir string `json:"output_dir,omitempty"`
+}
+
+// NarratorStyle is a further style chapters are written in.
+type NarratorStyle struct {
+	// Name is the style: narrator/styles/<name>.md or .yaml.
+	Name string `json:"name"`
+
+	// OutputDir is where its editions are written, a path template as
+	// NarratorConfig.OutputDir. Default: narrator/<name>/.
+	OutputDir string `json:"output_dir,omitempty"`
+}
+
+// NarratorTrigger configures the narrator's on-demand HTTP endpoint.
+type NarratorTrigger struct {
+	// Addr is the address it listens on. Default: "127.0.0.1:9466".
+	Addr string `json:"addr,omitempty"`
+
+	// Secret authenticates requests: each body must be signed with
+	// HMAC-SHA256, sent as X-Gastown-Signature: sha256=<hex>, as event
+	// webhooks are. SecretEnv names an environment variable to read it
+	// from instead. Without either, requests are not authenticated.
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+
+	// URL is the base URL of the endpoint as its callers reach it, for
+	// the chapter URLs it returns. Default: the host each request names.
+	URL string `json:"url,omitempty"`
+}
+
+// NarratorReview configures the approval queue chapters wait in before
+// they are published.
+type NarratorReview struct {
+	// Notify is the address mailed when a chapter awaits review.
+	// Default: "overseer".
+	Notify string `json:"notify,omitempty"`
+}
+
+// NarratorDigest configures digest mode.
+type NarratorDigest struct {
+	// Interval is how often digests are written (Go duration).
+	// Default: "24h".
+	Interval string `json:"interval,omitempty"`
+}
+
+// NarratorQuota limits chapter generation over rolling windows. Zero
+// limits are unlimited.
+type NarratorQuota struct {
+	// MaxPerHour caps the chapters generated in any hour.
+	MaxPerHour int `json:"max_per_hour,omitempty"`
+
+	// MaxPerDay caps the chapters generated in any 24 hours.
+	MaxPerDay int `json:"max_per_day,omitempty"`
+
+	// MaxTokensPerDay caps the tokens (prompt and reply, estimated)
+	// spent narrating in any 24 hours.
+	MaxTokensPerDay int `json:"max_tokens_per_day,omitempty"`
+
+	// OverQuota is what happens to a chapter over quota: "queue"
+	// (default) keeps its events for the next chapter the quota allows,
+	// "drop-low-significance" narrates only its high-significance events
+	// if that smaller chapter fits every limit, and "summarize" writes a
+	// summary of them without the agent.
+	OverQuota string `json:"over_quota,omitempty"`
+}
+
+// Over-quota handling for NarratorQuota.OverQuota.
+const (
+	NarratorQuotaQueue     = "queue"
+	NarratorQuotaDropLow   = "drop-low-significance"
+	NarratorQuotaSummarize = "summarize"
+)
+
+// NarratorPublish configures the narrator's static site.
+type NarratorPublish struct {
+	// Dir is where the site is written, relative to the town root unless
+	// absolute. Default: "narrator/site".
+	Dir string `json:"dir,omitempty"`
+
+	// Auto rebuilds the site after each chapter and catch-up chapter.
+	Auto bool `json:"auto,omitempty"`
+
+	// Feed describes the site's Atom feed (feed.xml).
+	Feed *NarratorFeed `json:"feed,omitempty"`
+}
+
+// NarratorFeed is the metadata of the narrator's Atom feed.
+type NarratorFeed struct {
+	// Title of the feed. Default: "The history of <town>".
+	Title string `json:"title,omitempty"`
+
+	// Author credited with the feed. Default: the town's name.
+	Author string `json:"author,omitempty"`
+
+	// URL is where the site is served. With it the feed's links and IDs
+	// are absolute; without it links are relative to feed.xml.
+	URL string `json:"url,omitempty"`
+}
+
+// NarratorIllustrations configures the scenes illustrated after a chapter
+// is written. Prompts go to an illustrations directory beside the chapter.
+type NarratorIllustrations struct {
+	// Significance is the minimum significance of an event that gets a
+	// scene. Default: "high".
+	Significance string `json:"significance,omitempty"`
+
+	// MaxScenes caps the scenes per chapter, keeping the latest.
+	// Default: DefaultIllustrationScenes.
+	MaxScenes int `json:"max_scenes,omitempty"`
+
+	// Command, when set, is run with sh -c once per scene to render it.
+	// It reads the prompt on stdin (and from $GT_ILLUSTRATION_PROMPT) and
+	// writes the image to $GT_ILLUSTRATION_IMAGE.
+	Command string `json:"command,omitempty"`
+
+	// Timeout bounds each run of Command (Go duration). Default: "5m".
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// DefaultIllustrationScenes is the scenes per chapter when
+// NarratorIllustrations doesn't set MaxScenes.
+const DefaultIllustrationScenes = 8
+
+// NarratorGitOutput configures committing chapters to a git branch.
+type NarratorGitOutput struct {
+	// Branch receives the chapters. Default: DefaultNarrativeBranch.
+	Branch string `json:"branch,omitempty"`
+
+	// Dir is the directory within the branch holding the chapters.
+	// Default: the branch root.
+	Dir string `json:"dir,omitempty"`
+}
+
+// DefaultNarrativeBranch is the branch chapters are committed to when
+// NarratorGitOutput doesn't name one.
+const DefaultNarrativeBranch = "narrative"
+
+// Narrator backlog handling for NarratorConfig.OnResume.
+const (
+	NarratorResumeSummarize = "summarize"
+	NarratorResumeSkip      = "skip"
+)
+
+// Terminal multiplexers for TownSettings.Multiplexer.
+const (
+	MultiplexerTmux   = "tmux"
+	MultiplexerZellij = "zellij"
+)
+
+// LifecycleHook is a script run at an agent lifecycle event.
+type LifecycleHook struct {
+	// Command is run with sh -c in the agent's working directory.
+	Command string `json:"command"`
+
+	// Roles limits the hook to these agent roles (e.g. "polecat", "crew").
+	// Empty means every role.
+	Roles []string `json:"roles,omitempty"`
+
+	// Timeout bounds the script's run time (Go duration). Default: "60s".
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// DefaultDrainTimeout is the drain window when none is configured.
+const DefaultDrainTimeout = 2 * time.Minute
+
+// GetDrainTimeout returns the drain window as a time.Duration.
+// Returns DefaultDrainTimeout if not configured or invalid.
+func (s *TownSettings) GetDrainTimeout() time.Duration {
+	if s == nil || s.DrainTimeout == "" {
+		return DefaultDrainTimeout
+	}
+	d, err := time.ParseDuration(s.DrainTimeout)
+	if err != nil || d < 0 {
+		return DefaultDrainTimeout
+	}
+	return d
+}
+
+// Event log sharding modes.
+const (
+	// EventShardNone writes every event to the single town-wide log (default).
+	EventShardNone = "none"
+	// EventShardRig writes rig-scoped events to per-rig log files.
+	EventShardRig = "rig"
+)
+
+// Event log fsync policies.
+const (
+	// EventFsyncNever leaves flushing appended events to the OS (default).
+	EventFsyncNever = "never"
+	// EventFsyncAlways fsyncs the log after every appended event.
+	EventFsyncAlways = "always"
+)
+
+// EventsConfig configures the town's event log.
+type EventsConfig struct {
+	// Shard selects the write-side layout: "none" (default) or "rig".
+	// With "rig", events about a rig are appended to .events/<rig>.jsonl and
+	// town-level events stay in .events.jsonl; readers merge all files.
+	Shard string `json:"shard,omitempty"`
+
+	// Fsync selects when appends are flushed to disk: "never" (default) or
+	// "always". "always" trades write latency for surviving power loss.
+	Fsync string `json:"fsync,omitempty"`
+
+	// Encrypt seals each appended event with the town key (AES-256-GCM) so
+	// payloads are unreadable in synced or shared copies of the town root.
+	// Readers decrypt transparently; existing plaintext lines stay readable.
+	Encrypt bool `json:"encrypt,omitempty"`
+
+	// KeyFile is where the town key is kept, created on first use. The
+	// default is outside the town root, in the user's config directory
+	// (gastown/keys/), so a synced or committed town root doesn't carry
+	// it; a .events.key already in the town root is still used.
+	// GT_EVENTS_KEY (hex) overrides the file.
+	KeyFile string `json:"key_file,omitempty"`
+
+	// Rotate archives each log once it grows past a size or age. Unset
+	// means logs are never rotated.
+	Rotate *EventsRotateConfig `json:"rotate,omitempty"`
+
+	// Index keeps a SQLite index of the logs (.events.db) that the daemon
+	// updates on each heartbeat and queries use instead of scanning. Needs
+	// the sqlite3 CLI; ignored when Encrypt is set.
+	Index bool `json:"index,omitempty"`
+
+	// Webhooks are sinks the daemon POSTs matching events to as they are
+	// written.
+	Webhooks []EventWebhook `json:"webhooks,omitempty"`
+
+	// Redact strips sensitive payload data from events before they reach
+	// the feed, the narrator or webhooks; the log itself, as shown by
+	// 'gt events --audit', is never redacted. Unset uses built-in rules
+	// that strip tokens, secrets and passwords; an empty list disables
+	// redaction.
+	Redact []EventRedactRule `json:"redact,omitempty"`
+}
+
+// EventRedactRule removes payload data from events shown to an audience.
+type EventRedactRule struct {
+	// Keys are payload keys to strip, as case-insensitive path.Match
+	// globs matched at any depth, e.g. "token", "*_path", "email".
+	Keys []string `json:"keys,omitempty"`
+
+	// Match is a regular expression; matching text in string payload
+	// values is replaced with "[redacted]", e.g. an email pattern.
+	Match string `json:"match,omitempty"`
+
+	// Types limits the rule to these event types (globs; empty = any).
+	Types []string `json:"types,omitempty"`
+
+	// Audiences limits the rule to "feed", "narrator" or "webhook"
+	// (empty = all three).
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+// EventWebhook is a URL that receives selected events as JSON.
+type EventWebhook struct {
+	// URL receives a POST per matching event.
+	URL string `json:"url"`
+
+	// Types limits the sink to these event types (empty = any type).
+	Types []string `json:"types,omitempty"`
+
+	// Significance is the minimum significance level sent: "low",
+	// "medium" or "high". Defaults to "high" when Types is empty.
+	Significance string `json:"significance,omitempty"`
+
+	// Secret signs each body with HMAC-SHA256, sent as
+	// X-Gastown-Signature: sha256=<hex>. SecretEnv names an environment
+	// variable to read it from instead, keeping it out of the town root.
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+
+	// MaxRetries is how many times a failed delivery is retried with
+	// exponential backoff (default 3).
+	MaxRetries int `json:"max_retries,omitempty"`
+}
+
+// EventsRotateConfig sets when the daemon rotates an events log into a
+// gzipped archive. A log is rotated when either threshold is reached.
+type EventsRotateConfig struct {
+	// MaxSizeMB rotates a log larger than this many megabytes (0 = no limit).
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+
+	// MaxAge rotates a log whose oldest event is older than this, e.g.
+	// "168h" (empty = no limit).
+	MaxAge string `json:"max_age,omitempty"`
+}
+
+// GetMaxAge returns MaxAge as a time.Duration, or 0 if unset or invalid.
+func (c *EventsRotateConfig) GetMaxAge() time.Duration {
+	if c == nil || c.MaxAge == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.MaxAge)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -91,8 +565,23 @@ type DaemonPatrolConfig struct {
 
 // HeartbeatConfig represents heartbeat settings for daemon.
 type HeartbeatConfig struct {
-	Enabled  bool   `json:"enabled"`            // whether heartbeat is enabled
-	Interval string `json:"interval,omitempty"` // e.g., "3m"
+	Enabled  bool           `json:"enabled"`            // whether heartbeat is enabled
+	Interval string         `json:"interval,omitempty"` // e.g., "3m"
+	Backoff  *BackoffConfig `json:"backoff,omitempty"`  // back off while the town is idle; nil keeps the interval fixed
+}
+
+// BackoffConfig represents an exponential backoff policy (see
+// internal/backoff): the interval grows from Base by Multiplier each idle
+// cycle, up to Max.
+type BackoffConfig struct {
+	Base       string  `json:"base,omitempty"`       // e.g., "3m"; default: the loop's own interval
+	Max        string  `json:"max,omitempty"`        // e.g., "30m"; default: no cap
+	Multiplier float64 `json:"multiplier,omitempty"` // default 2
+	Jitter     float64 `json:"jitter,omitempty"`     // randomize by this fraction either way, e.g., 0.1
+
+	// ResetOnActivity drops back to Base when agents show new activity
+	// (default true).
+	ResetOnActivity *bool `json:"reset_on_activity,omitempty"`
 }
 
 // PatrolConfig represents a single patrol configuration.
@@ -229,6 +718,20 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// LifecycleHooks adds lifecycle hook scripts for this rig's agents,
+	// run after the town's hooks for the same event.
+	LifecycleHooks map[string][]LifecycleHook `json:"lifecycle_hooks,omitempty"`
+
+	// Notifications overrides the overseer's desktop notification settings
+	// (mayor/overseer.json) for mail sent by this rig's agents. Fields left
+	// empty inherit the town's.
+	Notifications *OverseerNotifyConfig `json:"notifications,omitempty"`
+
+	// Patrols overrides the daemon's patrols (mayor/daemon.json) for this
+	// rig. Keys are "witness" and "refinery"; patrols not listed inherit
+	// the town's setting.
+	Patrols map[string]PatrolConfig `json:"patrols,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -283,6 +786,58 @@ type RuntimeConfig struct {
 
 	// Instructions controls the per-workspace instruction file name.
 	Instructions *RuntimeInstructionsConfig `json:"instructions,omitempty"`
+
+	// Prompts controls detection and dismissal of interactive startup dialogs.
+	Prompts *RuntimePromptsConfig `json:"prompts,omitempty"`
+
+	// Startup maps roles ("mayor", "deacon", "narrator") to the steps that
+	// bring up their session, replacing the built-in sequence for roles
+	// listed. See StartupStep.
+	Startup map[string][]StartupStep `json:"startup,omitempty"`
+}
+
+// Startup steps for StartupStep.Step.
+const (
+	// StepEnsureSettings creates the agent's directory and its runtime
+	// settings (hooks).
+	StepEnsureSettings = "ensure-settings"
+	// StepCreateSession starts the session running the agent.
+	StepCreateSession = "create-session"
+	// StepEnv sets the role's environment variables on the session.
+	StepEnv = "env"
+	// StepTheme applies the role's status-bar theme (tmux only).
+	StepTheme = "theme"
+	// StepWaitForClaude waits for the agent to replace the shell in the
+	// session (tmux only); the session is killed if it doesn't.
+	StepWaitForClaude = "wait-for-claude"
+	// StepAcceptDialog accepts the runtime's startup dialogs (tmux only).
+	StepAcceptDialog = "accept-dialog"
+	// StepStartupNudge sends the startup beacon that names the session in
+	// the agent's /resume picker.
+	StepStartupNudge = "startup-nudge"
+	// StepPropulsionNudge tells the agent to start on its hooked work.
+	StepPropulsionNudge = "propulsion-nudge"
+)
+
+// StartupStep is one step of a role's session startup. ensure-settings,
+// create-session and wait-for-claude abort the startup when they fail; the
+// other steps are best-effort.
+type StartupStep struct {
+	// Step names the step (see the Step* constants).
+	Step string `json:"step"`
+
+	// Delay is a pause before the step (Go duration), e.g. to let the
+	// agent take in one nudge before the next.
+	Delay string `json:"delay,omitempty"`
+
+	// Timeout bounds the step (positive Go duration). Default: 60s for
+	// wait-for-claude, 30s for the others. A session that create-session
+	// gave up on is killed if it appears later.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Beacon starts the agent with the startup beacon as its first prompt,
+	// instead of a separate startup-nudge. create-session only.
+	Beacon bool `json:"beacon,omitempty"`
 }
 
 // RuntimeSessionConfig configures how Gas Town discovers runtime session IDs.
@@ -320,6 +875,38 @@ type RuntimeTmuxConfig struct {
 	ReadyDelayMs int `json:"ready_delay_ms,omitempty"`
 }
 
+// Startup prompt auto-accept policies.
+const (
+	// PromptPolicyAlways dismisses every detected startup dialog (default).
+	PromptPolicyAlways = "always"
+	// PromptPolicyNever leaves startup dialogs for a human to answer.
+	PromptPolicyNever = "never"
+)
+
+// RuntimePromptsConfig controls how startup dialogs shown by a runtime are handled.
+// Autonomous agents cannot answer dialogs such as Claude's bypass permissions
+// warning, so known dialogs are detected in the pane and accepted with keystrokes.
+type RuntimePromptsConfig struct {
+	// AutoAccept is the accept policy: "always" or "never". Default: "always".
+	AutoAccept string `json:"auto_accept,omitempty"`
+
+	// Rules are the dialogs to detect. Default: the agent preset's startup prompts.
+	// An empty array [] disables detection (not "use defaults").
+	Rules []StartupPromptRule `json:"rules"`
+}
+
+// StartupPromptRule describes one startup dialog and how to accept it.
+type StartupPromptRule struct {
+	// Name identifies the dialog (e.g., "bypass-permissions").
+	Name string `json:"name"`
+
+	// Match is text that appears in the pane while the dialog is shown.
+	Match string `json:"match"`
+
+	// Keys are tmux key names sent in order to accept the dialog (e.g., ["Down", "Enter"]).
+	Keys []string `json:"keys"`
+}
+
 // RuntimeInstructionsConfig controls the name of the role instruction file.
 type RuntimeInstructionsConfig struct {
 	// File is the instruction filename (e.g., "CLAUDE.md", "AGENTS.md").
@@ -457,6 +1044,18 @@ func normalizeRuntimeConfig(rc *RuntimeConfig) *RuntimeConfig {
 		rc.Instructions.File = defaultInstructionsFile(rc.Provider)
 	}
 
+	if rc.Prompts == nil {
+		rc.Prompts = &RuntimePromptsConfig{}
+	}
+
+	if rc.Prompts.AutoAccept == "" {
+		rc.Prompts.AutoAccept = PromptPolicyAlways
+	}
+
+	if rc.Prompts.Rules == nil {
+		rc.Prompts.Rules = DefaultStartupPrompts(rc.Provider, rc.Command)
+	}
+
 	return rc
 }
 
@@ -567,6 +1166,21 @@ func defaultReadyDelayMs(provider string) int {
 	return 0
 }
 
+// DefaultStartupPrompts returns the startup dialogs known for a runtime.
+// The agent preset is looked up by provider first, then by command name,
+// so custom agents wrapping a known CLI inherit its dialogs.
+func DefaultStartupPrompts(provider, command string) []StartupPromptRule {
+	for _, name := range []string{provider, filepath.Base(command)} {
+		if name == "" || name == "." {
+			continue
+		}
+		if info := GetAgentPresetByName(name); info != nil {
+			return append([]StartupPromptRule(nil), info.StartupPrompts...)
+		}
+	}
+	return nil
+}
+
 func defaultInstructionsFile(provider string) string {
 	if provider == "codex" {
 		return "AGENTS.md"
@@ -757,6 +1371,134 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Routes map address prefixes to the beads database holding their mail,
+	// for towns where rigs keep separate databases. The longest matching
+	// prefix wins; unmatched addresses use the town database.
+	// Example: [{"prefix": "gastown/", "path": "gastown/mayor/rig"}]
+	Routes []MailRoute `json:"routes,omitempty"`
+
+	// Transport selects where mail is stored: "beads" (default) runs the bd
+	// CLI; "file" keeps a JSONL file in each beads directory, for towns
+	// without beads installed.
+	Transport string `json:"transport,omitempty"`
+
+	// Notify sets how agents are told about new mail, by role ("mayor",
+	// "deacon", "witness", "refinery", "polecat", "crew"). A "default" entry
+	// applies to every role; a role's entry overrides it field by field.
+	// Example: {"polecat": {"normal": "none"}, "default": {"repeat": "5m"}}
+	Notify map[string]MailNotifyPolicy `json:"notify,omitempty"`
+
+	// Retention archives and purges old mail (see gt mail compact).
+	// Example: {"archive_after_days": 14, "purge_after_days": 365, "daemon": true}
+	Retention *MailRetention `json:"retention,omitempty"`
+}
+
+// MailRetention is how long mail is kept. Read messages older than
+// ArchiveAfterDays are exported to the town's mail archive and removed from
+// the beads database; archived messages older than PurgeAfterDays are
+// dropped from the archive. A zero field turns that step off.
+type MailRetention struct {
+	ArchiveAfterDays int `json:"archive_after_days,omitempty"`
+	PurgeAfterDays   int `json:"purge_after_days,omitempty"`
+
+	// Daemon runs the policy from the daemon's heartbeat, once a day.
+	Daemon bool `json:"daemon,omitempty"`
+}
+
+// Mail notification tiers for MailNotifyPolicy.
+const (
+	MailNotifyRepeat    = "repeat"    // nudge now, and again until the mail is read
+	MailNotifyImmediate = "immediate" // nudge now
+	MailNotifyBatch     = "batch"     // mention in the agent's next nudge
+	MailNotifyNone      = "none"      // never interrupt
+)
+
+// DefaultMailNotifyRepeat is how often repeat-tier notifications are sent
+// again while the mail is unread.
+const DefaultMailNotifyRepeat = 10 * time.Minute
+
+// MailNotifyPolicy is the notification tier for each mail priority. Empty
+// fields use the defaults: urgent mail repeats, high is immediate, normal
+// is batched and low never interrupts.
+type MailNotifyPolicy struct {
+	Urgent string `json:"urgent,omitempty"`
+	High   string `json:"high,omitempty"`
+	Normal string `json:"normal,omitempty"`
+	Low    string `json:"low,omitempty"`
+
+	// Repeat is how often repeat-tier notifications are sent again, as a
+	// duration like "5m". Default 10m; the daemon checks each heartbeat.
+	Repeat string `json:"repeat,omitempty"`
+}
+
+// Tier returns the notification tier for mail of priority (urgent, high,
+// normal or low).
+func (p MailNotifyPolicy) Tier(priority string) string {
+	tier, fallback := p.Normal, MailNotifyBatch
+	switch priority {
+	case "urgent":
+		tier, fallback = p.Urgent, MailNotifyRepeat
+	case "high":
+		tier, fallback = p.High, MailNotifyImmediate
+	case "low":
+		tier, fallback = p.Low, MailNotifyNone
+	}
+	if tier == "" {
+		return fallback
+	}
+	return tier
+}
+
+// RepeatInterval returns how often repeat-tier notifications are sent.
+func (p MailNotifyPolicy) RepeatInterval() time.Duration {
+	if d, err := time.ParseDuration(p.Repeat); err == nil && d > 0 {
+		return d
+	}
+	return DefaultMailNotifyRepeat
+}
+
+// MailNotifyPolicy returns the notification policy for role: its entry in
+// Notify over the "default" entry.
+func (c *MessagingConfig) MailNotifyPolicy(role string) MailNotifyPolicy {
+	p := c.Notify["default"]
+	o, ok := c.Notify[role]
+	if !ok {
+		return p
+	}
+	if o.Urgent != "" {
+		p.Urgent = o.Urgent
+	}
+	if o.High != "" {
+		p.High = o.High
+	}
+	if o.Normal != "" {
+		p.Normal = o.Normal
+	}
+	if o.Low != "" {
+		p.Low = o.Low
+	}
+	if o.Repeat != "" {
+		p.Repeat = o.Repeat
+	}
+	return p
+}
+
+// Mail transports for MessagingConfig.Transport.
+const (
+	MailTransportBeads = "beads"
+	MailTransportFile  = "file"
+)
+
+// MailRoute maps an address prefix to a beads database.
+type MailRoute struct {
+	// Prefix matches an address and everything under it: "gastown/"
+	// matches "gastown/witness" and "gastown/polecats/Toast".
+	Prefix string `json:"prefix"`
+
+	// Path is the work directory holding the .beads database, relative to
+	// the town root. Beads redirects are followed.
+	Path string `json:"path"`
 }
 
 // QueueConfig represents a work queue configuration.
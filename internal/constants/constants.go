@@ -28,6 +28,33 @@ const (
 	PollInterval = 100 * time.Millisecond
 )
 
+// Process exit codes returned by gt commands.
+// Codes 0-2 keep their command-specific meanings (e.g., "gt mail check" uses 1
+// for "no mail"); codes 3 and above identify a failure class so wrappers and the
+// daemon can branch on why a command failed without parsing stderr.
+const (
+	// ExitSuccess indicates the command completed successfully.
+	ExitSuccess = 0
+
+	// ExitError is the generic failure code for errors without a known class.
+	ExitError = 1
+
+	// ExitWorkspaceNotFound indicates the command was not run inside a Gas Town workspace.
+	ExitWorkspaceNotFound = 3
+
+	// ExitAlreadyRunning indicates the target session or agent is already running.
+	ExitAlreadyRunning = 4
+
+	// ExitNotRunning indicates the target session or agent is not running.
+	ExitNotRunning = 5
+
+	// ExitTransport indicates a failure talking to an external tool (bd, tmux).
+	ExitTransport = 6
+
+	// ExitValidation indicates invalid arguments, flags, or configuration.
+	ExitValidation = 7
+)
+
 // Directory names within a Gas Town workspace.
 const (
 	// DirMayor is the directory containing mayor configuration and state.
@@ -14,9 +14,11 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/lifecycle"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -514,12 +516,21 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		claudeCmd = strings.Replace(claudeCmd, " --dangerously-skip-permissions", "", 1)
 	}
 
+	// Run pre-spawn lifecycle hooks; a failure aborts the start
+	hookAgent := m.lifecycleAgent(name, worker.ClonePath)
+	if err := lifecycle.Run(lifecycle.PreSpawn, hookAgent); err != nil {
+		return fmt.Errorf("pre-spawn hook: %w", err)
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Record the session's output for the transcript archive (non-fatal)
+	_ = transcript.Record(t, filepath.Dir(m.rig.Path), sessionID, m.rig.Name+"/crew/"+name)
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	townRoot := filepath.Dir(m.rig.Path)
@@ -547,9 +558,23 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// serves no purpose. If the caller needs to know when Claude is ready,
 	// they can check with IsClaudeRunning().
 
+	lifecycle.Warn(lifecycle.PostStart, hookAgent)
 	return nil
 }
 
+// lifecycleAgent describes a crew member for lifecycle hooks.
+func (m *Manager) lifecycleAgent(name, workDir string) lifecycle.Agent {
+	return lifecycle.Agent{
+		TownRoot: filepath.Dir(m.rig.Path),
+		RigPath:  m.rig.Path,
+		Role:     "crew",
+		Rig:      m.rig.Name,
+		Name:     name,
+		Session:  m.SessionName(name),
+		WorkDir:  workDir,
+	}
+}
+
 // Stop terminates a crew member's tmux session.
 func (m *Manager) Stop(name string) error {
 	if err := validateCrewName(name); err != nil {
@@ -568,6 +593,8 @@ func (m *Manager) Stop(name string) error {
 		return ErrSessionNotFound
 	}
 
+	lifecycle.Warn(lifecycle.PreKill, m.lifecycleAgent(name, m.crewDir(name)))
+
 	// Kill the session
 	if err := t.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+// watchActivity keeps the daemon's view of agent keepalives current by
+// watching the town's activity index, instead of reading every workspace's
+// keepalive file on each check. The watch ends with the daemon's context.
+func (d *Daemon) watchActivity() {
+	changes, err := keepalive.WatchActivity(d.ctx, d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: watching agent activity: %v (falling back to reading keepalives)", err)
+		return
+	}
+
+	d.activityMu.Lock()
+	d.activity = keepalive.ReadActivity(d.config.TownRoot)
+	d.activityMu.Unlock()
+
+	go func() {
+		for batch := range changes {
+			d.activityMu.Lock()
+			for _, c := range batch {
+				d.activity[c.Workspace] = c.State
+			}
+			d.activityMu.Unlock()
+		}
+	}()
+	d.logger.Println("Activity watch started")
+}
+
+// agentKeepalive returns the latest keepalive for an agent's working
+// directory: from the watched activity index when it has the workspace,
+// else read from the workspace (e.g. one last touched before the index
+// existed). Returns nil if there is none.
+func (d *Daemon) agentKeepalive(workDir string) *keepalive.State {
+	if rel, err := filepath.Rel(d.config.TownRoot, workDir); err == nil {
+		d.activityMu.Lock()
+		state, ok := d.activity[filepath.ToSlash(rel)]
+		d.activityMu.Unlock()
+		if ok {
+			return &state
+		}
+	}
+	return keepalive.Read(workDir)
+}
+
+// lastActivity returns the newest keepalive in the town: from the watched
+// activity index, or read from disk when the watch isn't running. Zero if
+// no agent has shown any.
+func (d *Daemon) lastActivity() time.Time {
+	d.activityMu.Lock()
+	defer d.activityMu.Unlock()
+	activity := d.activity
+	if activity == nil {
+		activity = keepalive.ReadActivity(d.config.TownRoot)
+	}
+	var last time.Time
+	for _, state := range activity {
+		if state.Timestamp.After(last) {
+			last = state.Timestamp
+		}
+	}
+	return last
+}
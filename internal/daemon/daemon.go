@@ -16,18 +16,27 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/backoff"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/boot"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/escalation"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/keepalive"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/usage"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -45,7 +54,10 @@ type Daemon struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	curator      *feed.Curator
+	broker       *events.Broker
 	convoyWatcher *ConvoyWatcher
+	publisher     *events.Publisher
+	supervisor    *Supervisor
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -56,6 +68,19 @@ type Daemon struct {
 	// See: https://github.com/steveyegge/gastown/issues/567
 	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
 	deaconLastStarted time.Time
+
+	// Agents suspended for idleness, keyed by identity (see manageIdleAgents).
+	// Loaded lazily; only accessed from the heartbeat loop goroutine.
+	suspended map[string]SuspendedAgent
+
+	// Recent events rig health is scored from, read incrementally by
+	// updateRigHealth. Created lazily; only accessed from the heartbeat
+	// loop goroutine.
+	rigHealth *health.Tracker
+
+	// Latest keepalive per workspace, kept current by watchActivity.
+	activityMu sync.Mutex
+	activity   keepalive.Activity
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -106,6 +131,7 @@ func New(config *Config) (*Daemon, error) {
 // Run starts the daemon main loop.
 func (d *Daemon) Run() error {
 	d.logger.Printf("Daemon starting (PID %d)", os.Getpid())
+	d.configureCommandLogs()
 
 	// Acquire exclusive lock to prevent multiple daemons from running.
 	// This prevents the TOCTOU race condition where multiple concurrent starts
@@ -132,9 +158,10 @@ func (d *Daemon) Run() error {
 
 	// Update state
 	state := &State{
-		Running:   true,
-		PID:       os.Getpid(),
-		StartedAt: time.Now(),
+		Running:           true,
+		PID:               os.Getpid(),
+		StartedAt:         time.Now(),
+		HeartbeatInterval: recoveryHeartbeatInterval.String(),
 	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
@@ -144,16 +171,27 @@ func (d *Daemon) Run() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
 
-	// Fixed recovery-focused heartbeat (no activity-based backoff)
+	// Recovery-focused heartbeat, fixed unless heartbeat.backoff is configured
 	// Normal wake is handled by feed subscription (bd activity --follow)
+	pace := d.heartbeatBackoff()
 	timer := time.NewTimer(recoveryHeartbeatInterval)
 	defer timer.Stop()
 
-	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	d.logger.Printf("Daemon running, recovery heartbeat interval %v", pace.Policy.Base)
+
+	// Start the event broker, which reads the events logs once for the
+	// curator, the webhook publisher and subscribers over its socket
+	broker := events.NewBroker(d.config.TownRoot, d.logger.Printf)
+	if err := broker.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start event broker: %v", err)
+	} else {
+		d.broker = broker
+		d.logger.Println("Event broker started")
+	}
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
-	if err := d.curator.Start(); err != nil {
+	if err := d.startFollower(d.curator.Start, d.curator.StartFrom); err != nil {
 		d.logger.Printf("Warning: failed to start feed curator: %v", err)
 	} else {
 		d.logger.Println("Feed curator started")
@@ -167,8 +205,29 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Convoy watcher started")
 	}
 
+	// Start webhook publisher if the town configures any sinks
+	if publisher := events.NewPublisher(d.config.TownRoot, d.logger.Printf); publisher.Enabled() {
+		if err := d.startFollower(publisher.Start, publisher.StartFrom); err != nil {
+			d.logger.Printf("Warning: failed to start webhook publisher: %v", err)
+		} else {
+			d.publisher = publisher
+			d.logger.Println("Webhook publisher started")
+		}
+	}
+
+	// Start the supervisor if the town gives any agent a restart policy
+	if supervisor := NewSupervisor(d.config.TownRoot, d.tmux, d.logger.Printf); supervisor.Enabled() {
+		supervisor.Start()
+		d.supervisor = supervisor
+		d.logger.Println("Supervisor started")
+	}
+
+	// Follow agent keepalives as they change
+	d.watchActivity()
+
 	// Initial heartbeat
 	d.heartbeat(state)
+	timer.Reset(d.nextHeartbeat(pace, state))
 
 	for {
 		select {
@@ -188,9 +247,7 @@ func (d *Daemon) Run() error {
 
 		case <-timer.C:
 			d.heartbeat(state)
-
-			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
+			timer.Reset(d.nextHeartbeat(pace, state))
 		}
 	}
 }
@@ -201,6 +258,69 @@ func (d *Daemon) Run() error {
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
 const recoveryHeartbeatInterval = 3 * time.Minute
 
+// HeartbeatBackoffFile returns the path to the heartbeat's backoff state,
+// kept so a restarted daemon resumes its pace.
+func HeartbeatBackoffFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "heartbeat-backoff.json")
+}
+
+// CommandLogFile returns where gt commands run by the daemon, and the
+// daemon's own structured logs, are written.
+func CommandLogFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "gt.log")
+}
+
+// configureCommandLogs makes the gt commands the daemon runs, which
+// inherit its environment but have no terminal, log JSON to
+// CommandLogFile. Settings already in the environment win.
+func (d *Daemon) configureCommandLogs() {
+	defaults := map[string]string{
+		gtlog.EnvLevel:  "info",
+		gtlog.EnvFormat: gtlog.FormatJSON,
+		gtlog.EnvFile:   CommandLogFile(d.config.TownRoot),
+	}
+	for k, v := range defaults {
+		if os.Getenv(k) == "" {
+			_ = os.Setenv(k, v)
+		}
+	}
+	if err := gtlog.Configure(os.Getenv(gtlog.EnvLevel), os.Getenv(gtlog.EnvFormat), gtlog.Writer()); err != nil {
+		d.logger.Printf("Warning: structured logging: %v", err)
+	}
+}
+
+// heartbeatBackoff returns the heartbeat's pacing: heartbeat.backoff in
+// mayor/daemon.json, based on the recovery interval, so the daemon checks in
+// less often while no agent shows activity. Without one (or with an invalid
+// one) the interval stays fixed.
+func (d *Daemon) heartbeatBackoff() *backoff.Backoff {
+	var cfg *config.BackoffConfig
+	if patrols, err := config.LoadDaemonPatrolConfig(config.DaemonPatrolConfigPath(d.config.TownRoot)); err == nil && patrols.Heartbeat != nil {
+		cfg = patrols.Heartbeat.Backoff
+	}
+	policy, err := backoff.FromConfig(cfg, recoveryHeartbeatInterval)
+	if err != nil {
+		d.logger.Printf("Warning: heartbeat %v; keeping the interval fixed", err)
+		policy = backoff.Fixed(recoveryHeartbeatInterval)
+	}
+	return backoff.New(policy, backoff.LoadState(HeartbeatBackoffFile(d.config.TownRoot)))
+}
+
+// nextHeartbeat returns the wait before the next heartbeat, recording the
+// pace in the daemon's state.
+func (d *Daemon) nextHeartbeat(pace *backoff.Backoff, state *State) time.Duration {
+	next := pace.NextInterval(d.lastActivity())
+	state.HeartbeatInterval = pace.State.Interval
+	state.BackoffLevel = pace.State.Level
+	if err := pace.State.Save(HeartbeatBackoffFile(d.config.TownRoot)); err != nil {
+		d.logger.Printf("Warning: failed to save heartbeat backoff: %v", err)
+	}
+	if err := SaveState(d.config.TownRoot, state); err != nil {
+		d.logger.Printf("Warning: failed to save state: %v", err)
+	}
+	return next
+}
+
 // heartbeat performs one heartbeat cycle.
 // The daemon is recovery-focused: it ensures agents are running and detects failures.
 // Normal wake is handled by feed subscription (bd activity --follow).
@@ -233,21 +353,19 @@ func (d *Daemon) heartbeat(state *State) {
 		d.checkDeaconHeartbeat()
 	}
 
+	// 3a. Resume suspended agents that have new mail or hooked work, and
+	// suspend idle ones (opt-in via auto_suspend in mayor/daemon.json).
+	// Runs before the ensure steps, which skip suspended agents.
+	d.manageIdleAgents()
+
 	// 4. Ensure Witnesses are running for all rigs (restart if dead)
-	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "witness") {
-		d.ensureWitnessesRunning()
-	} else {
-		d.logger.Printf("Witness patrol disabled in config, skipping")
-	}
+	// Check patrol config - can be disabled in mayor/daemon.json, and
+	// overridden per rig in <rig>/settings/config.json
+	d.ensureWitnessesRunning()
 
 	// 5. Ensure Refineries are running for all rigs (restart if dead)
-	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "refinery") {
-		d.ensureRefineriesRunning()
-	} else {
-		d.logger.Printf("Refinery patrol disabled in config, skipping")
-	}
+	// Same patrol config and rig overrides as witnesses
+	d.ensureRefineriesRunning()
 
 	// 6. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
 	// This ensures polecats get nudged even when Deacon isn't in a patrol cycle.
@@ -274,6 +392,33 @@ func (d *Daemon) heartbeat(state *State) {
 	// This is a safety net - Deacon patrol also does this more frequently.
 	d.cleanupOrphanedProcesses()
 
+	// 13. Archive transcripts of sessions that have ended
+	d.archiveTranscripts()
+
+	// 14. Rescore rig health and report grade changes
+	d.updateRigHealth()
+
+	// 15. Rotate events logs past their configured size or age
+	d.rotateEvents()
+
+	// 16. Catch the events index up with the logs
+	d.indexEvents()
+
+	// 17. Remind the targets of escalations left unacknowledged past their SLA
+	d.checkEscalations()
+
+	// 18. Send scheduled mail and re-surface snoozed mail that is due
+	d.runScheduledMail()
+
+	// 19. Sample each agent session's CPU, memory and token usage
+	d.sampleUsage()
+
+	// 20. Nudge agents about batched mail, and again about unread urgent mail
+	d.flushMailNotifications()
+
+	// 21. Archive and purge old mail, daily, if the retention policy asks
+	d.compactMail()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -284,6 +429,148 @@ func (d *Daemon) heartbeat(state *State) {
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
+// archiveTranscripts moves the recordings of ended sessions into the
+// searchable transcript archive.
+func (d *Daemon) archiveTranscripts() {
+	archived, err := transcript.ArchiveEnded(d.config.TownRoot, func(session string) bool {
+		running, err := d.tmux.HasSession(session)
+		return running || err != nil // keep recording if tmux can't tell
+	})
+	if err != nil {
+		d.logger.Printf("Warning: archiving transcripts: %v", err)
+	}
+	for _, e := range archived {
+		d.logger.Printf("Archived transcript %s (%d lines)", e.ID, e.Lines)
+	}
+}
+
+// updateRigHealth scores each rig from recent events and emits a
+// rig_health_changed event for every rig whose grade moved. Each heartbeat
+// reads only the events logged since the last.
+func (d *Daemon) updateRigHealth() {
+	if d.rigHealth == nil {
+		d.rigHealth = health.NewTracker(d.config.TownRoot)
+	}
+	scores, err := d.rigHealth.Update(time.Now())
+	if err != nil {
+		d.logger.Printf("Warning: reading events for rig health: %v", err)
+		return
+	}
+	changes, err := health.Track(d.config.TownRoot, scores)
+	if err != nil {
+		d.logger.Printf("Warning: recording rig health: %v", err)
+		return
+	}
+	for _, c := range changes {
+		d.logger.Printf("Rig %s health %s -> %s (%d)", c.Score.Rig, c.PreviousGrade, c.Score.Grade, c.Score.Score)
+		_ = events.LogFeed(events.TypeRigHealthChanged, "daemon",
+			events.RigHealthPayload(c.Score.Rig, c.Score.Score, c.Score.Grade, c.PreviousScore, c.PreviousGrade, c.Score.Trend))
+	}
+}
+
+// rotateEvents archives events logs that have reached the town's rotation
+// thresholds. Does nothing unless rotation is configured.
+func (d *Daemon) rotateEvents() {
+	rotator := events.NewRotator(d.config.TownRoot)
+	if !rotator.Enabled() {
+		return
+	}
+	results, err := rotator.Rotate(time.Now(), false)
+	for _, r := range results {
+		d.logger.Printf("Rotated %d events to %s (kept %d annotated)", r.Dropped, r.Archive, r.Kept)
+	}
+	if err != nil {
+		d.logger.Printf("Warning: rotating events: %v", err)
+	}
+}
+
+// indexEvents adds new events to the town's SQLite index. Does nothing
+// unless indexing is enabled.
+func (d *Daemon) indexEvents() {
+	if !events.IndexEnabled(d.config.TownRoot) {
+		return
+	}
+	added, err := events.UpdateIndex(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: indexing events: %v", err)
+		return
+	}
+	if added > 0 {
+		d.logger.Printf("Indexed %d events", added)
+	}
+}
+
+// checkEscalations mails a reminder about each escalation still
+// unacknowledged past its deadline.
+func (d *Daemon) checkEscalations() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	reminded, err := escalation.CheckSLA(d.config.TownRoot, "daemon", time.Now(), router)
+	if err != nil {
+		d.logger.Printf("Warning: checking escalation SLAs: %v", err)
+		return
+	}
+	for _, r := range reminded {
+		d.logger.Printf("Escalation %s unacknowledged past SLA, reminded %s (reminder %d)",
+			r.Record.ID, strings.Join(r.Record.To, ", "), r.Record.Renotified)
+		if len(r.Failed) > 0 {
+			d.logger.Printf("Warning: could not remind %s about escalation %s", strings.Join(r.Failed, ", "), r.Record.ID)
+		}
+	}
+}
+
+// runScheduledMail sends mail scheduled with gt mail send --at and wakes
+// mail snoozed with gt mail snooze, once due.
+func (d *Daemon) runScheduledMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	ran, err := router.RunDue(time.Now())
+	for _, s := range ran {
+		switch s.Kind {
+		case mail.ScheduleSnooze:
+			d.logger.Printf("Woke snoozed mail %s for %s", s.Message.ID, s.Address)
+		default:
+			d.logger.Printf("Sent scheduled mail %s to %s", s.ID, s.Message.To)
+		}
+	}
+	if err != nil {
+		d.logger.Printf("Warning: running scheduled mail: %v", err)
+	}
+}
+
+// flushMailNotifications sends the mail notifications held back by each
+// role's notification policy: batched mail, and repeats for unread mail.
+func (d *Daemon) flushMailNotifications() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	nudged, err := router.FlushNotifications(time.Now())
+	if nudged > 0 {
+		d.logger.Printf("Sent %d mail notification(s)", nudged)
+	}
+	if err != nil {
+		d.logger.Printf("Warning: flushing mail notifications: %v", err)
+	}
+}
+
+// compactMail applies the town's mail retention policy when it is set to
+// run from the daemon and a day has passed since it last ran.
+func (d *Daemon) compactMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	res, err := router.CompactIfDue(time.Now())
+	if res != nil && (res.Archived > 0 || res.Purged > 0) {
+		d.logger.Printf("Compacted mail: archived %d, purged %d", res.Archived, res.Purged)
+	}
+	if err != nil {
+		d.logger.Printf("Warning: compacting mail: %v", err)
+	}
+}
+
+// sampleUsage adds each agent session's resource use since the last
+// heartbeat to the town's daily usage (see gt usage report).
+func (d *Daemon) sampleUsage() {
+	sampler := &usage.Sampler{TownRoot: d.config.TownRoot, Sessions: d.tmux}
+	if _, err := sampler.Sample(); err != nil {
+		d.logger.Printf("Warning: sampling usage: %v", err)
+	}
+}
+
 // DeaconRole is the role name for the Deacon's handoff bead.
 const DeaconRole = "deacon"
 
@@ -361,6 +648,11 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 // ensureDeaconRunning ensures the Deacon is running.
 // Uses deacon.Manager for consistent startup behavior (WaitForShellReady, GUPP, etc.).
 func (d *Daemon) ensureDeaconRunning() {
+	// A Deacon restart policy in town settings leaves restarts to the supervisor
+	if d.supervisor.Supervises("deacon") {
+		return
+	}
+
 	mgr := deacon.NewManager(d.config.TownRoot)
 
 	if err := mgr.Start(""); err != nil {
@@ -450,6 +742,10 @@ func (d *Daemon) checkDeaconHeartbeat() {
 func (d *Daemon) ensureWitnessesRunning() {
 	rigs := d.getKnownRigs()
 	for _, rigName := range rigs {
+		if !d.isRigPatrolEnabled(rigName, "witness") {
+			d.logger.Printf("Witness patrol disabled for %s in config, skipping", rigName)
+			continue
+		}
 		d.ensureWitnessRunning(rigName)
 	}
 }
@@ -457,6 +753,12 @@ func (d *Daemon) ensureWitnessesRunning() {
 // ensureWitnessRunning ensures the witness for a specific rig is running.
 // Discover, don't track: uses Manager.Start() which checks tmux directly (gt-zecmc).
 func (d *Daemon) ensureWitnessRunning(rigName string) {
+	// Suspended for idleness: manageIdleAgents resumes it when needed
+	if d.isSuspended(rigName + "-witness") {
+		d.logger.Printf("Witness for %s is suspended, skipping auto-start", rigName)
+		return
+	}
+
 	// Check rig operational state before auto-starting
 	if operational, reason := d.isRigOperational(rigName); !operational {
 		d.logger.Printf("Skipping witness auto-start for %s: %s", rigName, reason)
@@ -490,13 +792,33 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 func (d *Daemon) ensureRefineriesRunning() {
 	rigs := d.getKnownRigs()
 	for _, rigName := range rigs {
+		if !d.isRigPatrolEnabled(rigName, "refinery") {
+			d.logger.Printf("Refinery patrol disabled for %s in config, skipping", rigName)
+			continue
+		}
 		d.ensureRefineryRunning(rigName)
 	}
 }
 
+// isRigPatrolEnabled reports whether a patrol runs for a rig. The rig's
+// settings override the town's mayor/daemon.json.
+func (d *Daemon) isRigPatrolEnabled(rigName, patrol string) bool {
+	rigSettings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(d.config.TownRoot, rigName)))
+	if err != nil {
+		rigSettings = nil
+	}
+	return config.RigPatrolEnabled(rigSettings, patrol, IsPatrolEnabled(d.patrolConfig, patrol))
+}
+
 // ensureRefineryRunning ensures the refinery for a specific rig is running.
 // Discover, don't track: uses Manager.Start() which checks tmux directly (gt-zecmc).
 func (d *Daemon) ensureRefineryRunning(rigName string) {
+	// Suspended for idleness: manageIdleAgents resumes it when needed
+	if d.isSuspended(rigName + "-refinery") {
+		d.logger.Printf("Refinery for %s is suspended, skipping auto-start", rigName)
+		return
+	}
+
 	// Check rig operational state before auto-starting
 	if operational, reason := d.isRigOperational(rigName); !operational {
 		d.logger.Printf("Skipping refinery auto-start for %s: %s", rigName, reason)
@@ -527,7 +849,12 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 
 // getKnownRigs returns list of registered rig names.
 func (d *Daemon) getKnownRigs() []string {
-	rigsPath := filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
+	return KnownRigs(d.config.TownRoot)
+}
+
+// KnownRigs returns the rig names registered in mayor/rigs.json.
+func KnownRigs(townRoot string) []string {
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	data, err := os.ReadFile(rigsPath)
 	if err != nil {
 		return nil
@@ -558,6 +885,15 @@ func (d *Daemon) isRigOperational(rigName string) (bool, string) {
 		d.logger.Printf("Warning: no wisp config for %s - parked state may have been lost", rigName)
 	}
 
+	return RigOperational(d.config.TownRoot, rigName)
+}
+
+// RigOperational reports whether the daemon may auto-start a rig's agents.
+// Returns false (with reason) if the rig is parked, docked, or has
+// auto_restart blocked/disabled.
+func RigOperational(townRoot, rigName string) (bool, string) {
+	cfg := wisp.NewConfig(townRoot, rigName)
+
 	// Check rig status - parked and docked rigs should not have agents auto-started
 	status := cfg.GetString("status")
 	switch status {
@@ -634,6 +970,20 @@ func (d *Daemon) triggerPendingSpawns() {
 	}
 }
 
+// startFollower starts an events log follower on a tap of the event broker,
+// or on its own tailer (start) if the broker isn't running.
+func (d *Daemon) startFollower(start func() error, startFrom func(events.LineSource) error) error {
+	if d.broker == nil {
+		return start()
+	}
+	tap := d.broker.Tap()
+	if err := startFrom(tap); err != nil {
+		_ = tap.Close()
+		return err
+	}
+	return nil
+}
+
 // processLifecycleRequests checks for and processes lifecycle requests.
 func (d *Daemon) processLifecycleRequests() {
 	d.ProcessLifecycleRequests()
@@ -655,6 +1005,24 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Convoy watcher stopped")
 	}
 
+	// Stop webhook publisher
+	if d.publisher != nil {
+		d.publisher.Stop()
+		d.logger.Println("Webhook publisher stopped")
+	}
+
+	// Stop supervisor
+	if d.supervisor != nil {
+		d.supervisor.Stop()
+		d.logger.Println("Supervisor stopped")
+	}
+
+	// Stop event broker, after the followers reading its taps
+	if d.broker != nil {
+		d.broker.Stop()
+		d.logger.Println("Event broker stopped")
+	}
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -958,12 +1326,12 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		return fmt.Errorf("sending startup command: %w", err)
 	}
 
-	// Wait for Claude to start, then accept bypass permissions warning if it appears.
-	// This ensures automated restarts aren't blocked by the warning dialog.
+	// Wait for Claude to start, then accept runtime startup dialogs if they appear.
+	// This ensures automated restarts aren't blocked by the bypass permissions warning.
 	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
+	_ = runtime.AcceptStartupPrompts(d.tmux, sessionName, config.ResolveRoleAgentConfig("polecat", d.config.TownRoot, rigPath))
 
 	return nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -332,6 +333,15 @@ func (d *Daemon) identityToSession(identity string) string {
 	}
 }
 
+// resolveRuntimeConfig returns the runtime config an agent's session runs with.
+func (d *Daemon) resolveRuntimeConfig(parsed *ParsedIdentity) *config.RuntimeConfig {
+	rigPath := ""
+	if parsed.RigName != "" {
+		rigPath = filepath.Join(d.config.TownRoot, parsed.RigName)
+	}
+	return config.ResolveRoleAgentConfig(parsed.RoleType, d.config.TownRoot, rigPath)
+}
+
 // restartSession starts a new session for the given agent.
 // Uses role bead config if available, falls back to hardcoded defaults.
 func (d *Daemon) restartSession(sessionName, identity string) error {
@@ -383,12 +393,12 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
 
-	// Wait for Claude to start, then accept bypass permissions warning if it appears.
-	// This ensures automated role starts aren't blocked by the warning dialog.
+	// Wait for Claude to start, then accept runtime startup dialogs if they appear.
+	// This ensures automated role starts aren't blocked by the bypass permissions warning.
 	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
+	_ = runtime.AcceptStartupPrompts(d.tmux, sessionName, d.resolveRuntimeConfig(parsed))
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	// GUPP: Gas Town Universal Propulsion Principle
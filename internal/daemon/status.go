@@ -0,0 +1,238 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/keepalive"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Service supervision states reported by CollectStatus.
+const (
+	ServiceRunning   = "running"
+	ServiceStopped   = "stopped"
+	ServiceSuspended = "suspended"
+	ServiceDisabled  = "disabled"
+	ServiceBlocked   = "blocked"
+)
+
+// StatusReport is a machine-readable snapshot of the daemon and the services
+// it supervises, as printed by 'gt daemon status --json'.
+type StatusReport struct {
+	Running           bool             `json:"running"`
+	PID               int              `json:"pid,omitempty"`
+	StartedAt         *time.Time       `json:"started_at,omitempty"`
+	LastHeartbeat     *time.Time       `json:"last_heartbeat,omitempty"`
+	HeartbeatCount    int64            `json:"heartbeat_count"`
+	HeartbeatInterval string           `json:"heartbeat_interval"`
+	BackoffLevel      int              `json:"backoff_level"`
+	Activity          []ActivityStatus `json:"activity"`
+	Services          []ServiceStatus  `json:"services"`
+}
+
+// ActivityStatus is the last recorded activity in one workspace.
+type ActivityStatus struct {
+	Workspace string    `json:"workspace"`
+	Source    string    `json:"source"`
+	Command   string    `json:"command,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Stale     bool      `json:"stale"` // no keepalive within KeepaliveStaleThreshold
+}
+
+// KeepaliveStaleThreshold is how old a keepalive can be before status
+// reports its workspace as stale: several missed heartbeats.
+const KeepaliveStaleThreshold = 5 * keepalive.DefaultHeartbeatInterval
+
+// ServiceStatus is the supervision state of one daemon-managed agent.
+type ServiceStatus struct {
+	Name         string     `json:"name"`
+	Role         string     `json:"role"`
+	Rig          string     `json:"rig,omitempty"`
+	Session      string     `json:"session"`
+	State        string     `json:"state"`
+	Reason       string     `json:"reason,omitempty"`
+	LastActivity *time.Time `json:"last_activity,omitempty"`
+}
+
+// StatusSessions is the tmux access CollectStatus needs. *tmux.Tmux
+// implements it.
+type StatusSessions interface {
+	HasSession(name string) (bool, error)
+	GetSessionInfo(name string) (*tmux.SessionInfo, error)
+}
+
+// CollectStatus builds a status report from the daemon's state file, the
+// patrol config, the suspended-agent record, workspace keepalives and the
+// live tmux sessions. It does not require the daemon to be running.
+func CollectStatus(townRoot string, sessions StatusSessions) (*StatusReport, error) {
+	report := &StatusReport{
+		HeartbeatInterval: recoveryHeartbeatInterval.String(),
+		Activity:          []ActivityStatus{},
+		Services:          []ServiceStatus{},
+	}
+
+	running, pid, err := IsRunning(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	report.Running = running
+	if running {
+		report.PID = pid
+	}
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		if !state.StartedAt.IsZero() {
+			startedAt := state.StartedAt
+			report.StartedAt = &startedAt
+		}
+		if state.HeartbeatInterval != "" {
+			report.HeartbeatInterval = state.HeartbeatInterval
+		}
+		report.BackoffLevel = state.BackoffLevel
+	}
+	if !state.LastHeartbeat.IsZero() {
+		lastHeartbeat := state.LastHeartbeat
+		report.LastHeartbeat = &lastHeartbeat
+	}
+	report.HeartbeatCount = state.HeartbeatCount
+
+	rigs := KnownRigs(townRoot)
+	sort.Strings(rigs)
+
+	report.Activity = collectActivity(townRoot, rigs)
+
+	suspended, err := LoadSuspended(townRoot)
+	if err != nil {
+		suspended = map[string]SuspendedAgent{}
+	}
+	report.Services = collectServices(townRoot, rigs, LoadPatrolConfig(townRoot), suspended, sessions)
+
+	return report, nil
+}
+
+// collectActivity reads the keepalive of the town root and every agent
+// workspace that has one.
+func collectActivity(townRoot string, rigs []string) []ActivityStatus {
+	workspaces := []string{townRoot}
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		workspaces = append(workspaces, rigPath, filepath.Join(rigPath, "refinery", "rig"))
+		workspaces = append(workspaces, subdirs(filepath.Join(rigPath, "crew"))...)
+		for _, polecat := range subdirs(filepath.Join(rigPath, "polecats")) {
+			// New structure: polecats/<name>/<rigname>/
+			if nested := filepath.Join(polecat, rigName); isDir(nested) {
+				polecat = nested
+			}
+			workspaces = append(workspaces, polecat)
+		}
+	}
+
+	activity := []ActivityStatus{}
+	for _, ws := range workspaces {
+		state := keepalive.Read(ws)
+		if state == nil {
+			continue
+		}
+		rel, err := filepath.Rel(townRoot, ws)
+		if err != nil {
+			rel = ws
+		}
+		activity = append(activity, ActivityStatus{
+			Workspace: filepath.ToSlash(rel),
+			Source:    "keepalive",
+			Command:   state.LastCommand,
+			Timestamp: state.Timestamp,
+			Stale:     state.IsStale(KeepaliveStaleThreshold),
+		})
+	}
+	return activity
+}
+
+// collectServices reports the deacon and each rig's witness and refinery.
+func collectServices(townRoot string, rigs []string, patrols *DaemonPatrolConfig, suspended map[string]SuspendedAgent, sessions StatusSessions) []ServiceStatus {
+	services := []ServiceStatus{
+		serviceStatus(ServiceStatus{Name: "deacon", Role: "deacon", Session: session.DeaconSessionName()},
+			IsPatrolEnabled(patrols, "deacon"), "", suspended, sessions),
+	}
+
+	for _, rigName := range rigs {
+		operational, reason := RigOperational(townRoot, rigName)
+		if operational {
+			reason = ""
+		}
+		for _, role := range []string{"witness", "refinery"} {
+			sessionName := session.WitnessSessionName(rigName)
+			if role == "refinery" {
+				sessionName = session.RefinerySessionName(rigName)
+			}
+			svc := ServiceStatus{Name: rigName + "-" + role, Role: role, Rig: rigName, Session: sessionName}
+			services = append(services, serviceStatus(svc, IsPatrolEnabled(patrols, role), reason, suspended, sessions))
+		}
+	}
+	return services
+}
+
+// serviceStatus fills in the supervision state of svc. blockedReason is
+// non-empty when the rig is not operational.
+func serviceStatus(svc ServiceStatus, enabled bool, blockedReason string, suspended map[string]SuspendedAgent, sessions StatusSessions) ServiceStatus {
+	if sessions != nil {
+		if has, err := sessions.HasSession(svc.Session); err == nil && has {
+			svc.State = ServiceRunning
+			if info, err := sessions.GetSessionInfo(svc.Session); err == nil {
+				if last := lastActivity(info.Activity, nil); !last.IsZero() {
+					svc.LastActivity = &last
+				}
+			}
+		}
+	}
+
+	if agent, ok := suspended[svc.Name]; ok {
+		svc.State = ServiceSuspended
+		svc.Reason = "idle since " + agent.SuspendedAt.Format(time.RFC3339) + " (" + agent.Mode + ")"
+		return svc
+	}
+	if svc.State == ServiceRunning {
+		return svc
+	}
+
+	switch {
+	case !enabled:
+		svc.State = ServiceDisabled
+		svc.Reason = "patrol disabled in mayor/daemon.json"
+	case blockedReason != "":
+		svc.State = ServiceBlocked
+		svc.Reason = blockedReason
+	default:
+		svc.State = ServiceStopped
+	}
+	return svc
+}
+
+// subdirs returns the visible subdirectories of dir.
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return dirs
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/keepalive"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+type fakeStatusSessions struct {
+	activity map[string]time.Time
+}
+
+func (f fakeStatusSessions) HasSession(name string) (bool, error) {
+	_, ok := f.activity[name]
+	return ok, nil
+}
+
+func (f fakeStatusSessions) GetSessionInfo(name string) (*tmux.SessionInfo, error) {
+	return &tmux.SessionInfo{Name: name, Activity: strconv.FormatInt(f.activity[name].Unix(), 10)}, nil
+}
+
+func TestCollectStatus(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "daemon", "alpha/crew/max", "beta"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigsJSON := `{"rigs": {"alpha": {}, "beta": {}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	patrolJSON := `{"type": "daemon-patrol-config", "version": 1, "patrols": {"refinery": {"enabled": false}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "daemon.json"), []byte(patrolJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keepalive.TouchInWorkspace(filepath.Join(townRoot, "alpha", "crew", "max"), "gt hook")
+
+	if err := SaveSuspended(townRoot, map[string]SuspendedAgent{
+		"beta-witness": {Identity: "beta-witness", Session: session.WitnessSessionName("beta"), Mode: SuspendModeExit},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	heartbeat := time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)
+	if err := SaveState(townRoot, &State{LastHeartbeat: heartbeat, HeartbeatCount: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	active := time.Date(2026, 1, 10, 2, 30, 0, 0, time.UTC)
+	sessions := fakeStatusSessions{activity: map[string]time.Time{
+		session.WitnessSessionName("alpha"): active,
+	}}
+
+	report, err := CollectStatus(townRoot, sessions)
+	if err != nil {
+		t.Fatalf("CollectStatus: %v", err)
+	}
+
+	if report.Running {
+		t.Error("expected daemon not running")
+	}
+	if report.HeartbeatInterval != recoveryHeartbeatInterval.String() || report.BackoffLevel != 0 {
+		t.Errorf("interval/backoff = %s/%d", report.HeartbeatInterval, report.BackoffLevel)
+	}
+	if report.HeartbeatCount != 7 || report.LastHeartbeat == nil || !report.LastHeartbeat.Equal(heartbeat) {
+		t.Errorf("heartbeat = %v (#%d)", report.LastHeartbeat, report.HeartbeatCount)
+	}
+
+	if len(report.Activity) != 1 {
+		t.Fatalf("activity = %+v, want one workspace", report.Activity)
+	}
+	if got := report.Activity[0]; got.Workspace != "alpha/crew/max" || got.Source != "keepalive" || got.Command != "gt hook" {
+		t.Errorf("activity = %+v", got)
+	}
+
+	want := map[string]string{
+		"deacon":         ServiceStopped,
+		"alpha-witness":  ServiceRunning,
+		"alpha-refinery": ServiceDisabled,
+		"beta-witness":   ServiceSuspended,
+		"beta-refinery":  ServiceDisabled,
+	}
+	if len(report.Services) != len(want) {
+		t.Fatalf("services = %+v, want %d", report.Services, len(want))
+	}
+	for _, svc := range report.Services {
+		if svc.State != want[svc.Name] {
+			t.Errorf("%s state = %q, want %q", svc.Name, svc.State, want[svc.Name])
+		}
+		if svc.Name == "alpha-witness" && (svc.LastActivity == nil || !svc.LastActivity.Equal(active)) {
+			t.Errorf("alpha-witness last activity = %v, want %v", svc.LastActivity, active)
+		}
+	}
+
+	if _, err := json.Marshal(report); err != nil {
+		t.Errorf("report does not encode: %v", err)
+	}
+}
+
+func TestServiceStatus_Blocked(t *testing.T) {
+	svc := serviceStatus(ServiceStatus{Name: "gamma-witness", Session: "gt-gamma-witness"},
+		true, "rig is parked", nil, fakeStatusSessions{})
+	if svc.State != ServiceBlocked || svc.Reason != "rig is parked" {
+		t.Errorf("parked rig service = %+v, want blocked", svc)
+	}
+}
@@ -0,0 +1,333 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Supervisor timing.
+const (
+	// supervisorInterval is how often supervised agents are checked.
+	supervisorInterval = 30 * time.Second
+
+	// restartGrace is how long a restarted agent has to come up before it
+	// is checked again, so a session still starting isn't taken for a zombie.
+	restartGrace = time.Minute
+
+	// restartStable is how long an agent must stay up after a restart for
+	// its restarts-in-a-row count to reset.
+	restartStable = 10 * time.Minute
+
+	// maxRestartBackoff caps the doubling restart backoff.
+	maxRestartBackoff = 30 * time.Minute
+)
+
+// Reasons an agent was found down.
+const (
+	downCrashed    = "crashed"     // session vanished while up
+	downZombie     = "zombie"      // session alive, agent process dead
+	downNotRunning = "not running" // not running when first checked, or stopped
+)
+
+// SupervisedAgent is the supervisor's record of a town-level agent.
+type SupervisedAgent struct {
+	Up          bool      `json:"up"`                // running when last checked
+	Stopped     bool      `json:"stopped,omitempty"` // stopped on purpose since
+	Restarts    int       `json:"restarts"`          // restarts in a row
+	LastRestart time.Time `json:"last_restart"`
+	Halted      bool      `json:"halted,omitempty"` // gave up after max_restarts
+}
+
+// SupervisorFile returns the path to the supervisor's agent records.
+func SupervisorFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "supervisor.json")
+}
+
+// LoadSupervised loads the supervisor's agent records, keyed by agent.
+// Returns an empty map if none are recorded.
+func LoadSupervised(townRoot string) (map[string]SupervisedAgent, error) {
+	data, err := os.ReadFile(SupervisorFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]SupervisedAgent{}, nil
+		}
+		return nil, err
+	}
+
+	agents := map[string]SupervisedAgent{}
+	if err := json.Unmarshal(data, &agents); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// SaveSupervised saves the supervisor's agent records using atomic write.
+func SaveSupervised(townRoot string, agents map[string]SupervisedAgent) error {
+	path := SupervisorFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, agents)
+}
+
+// MarkStopped records that an agent was stopped on purpose, so an
+// on-failure restart policy leaves it down until it is started again.
+func MarkStopped(townRoot, agent string) error {
+	agents, err := LoadSupervised(townRoot)
+	if err != nil {
+		return err
+	}
+	rec := agents[agent]
+	rec.Up = false
+	rec.Stopped = true
+	agents[agent] = rec
+	return SaveSupervised(townRoot, agents)
+}
+
+// sessionProbe reports on tmux sessions. *tmux.Tmux implements it.
+type sessionProbe interface {
+	HasSession(name string) (bool, error)
+	IsClaudeRunning(session string) bool
+}
+
+// managedAgent is a town-level agent the supervisor can check and start.
+type managedAgent struct {
+	session string
+	start   func() error
+}
+
+// Supervisor restarts town-level agents that go down, according to the
+// restart policies in the town settings. It runs its own loop so restarts
+// don't wait for the recovery heartbeat.
+type Supervisor struct {
+	townRoot string
+	policies map[string]*config.RestartPolicy
+	agents   map[string]managedAgent
+	probe    sessionProbe
+	logf     func(format string, args ...interface{})
+	now      func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSupervisor creates a supervisor for the agents the town settings give
+// restart policies. Policies for agents without a managed session are
+// ignored with a warning.
+func NewSupervisor(townRoot string, t *tmux.Tmux, logf func(format string, args ...interface{})) *Supervisor {
+	agents := map[string]managedAgent{
+		"mayor": {
+			session: mayor.SessionName(),
+			start: func() error {
+				if err := mayor.NewManager(townRoot).Start(""); !errors.Is(err, mayor.ErrAlreadyRunning) {
+					return err
+				}
+				return nil
+			},
+		},
+		"deacon": {
+			session: deacon.SessionName(),
+			start: func() error {
+				if err := deacon.NewManager(townRoot).Start(""); !errors.Is(err, deacon.ErrAlreadyRunning) {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+
+	policies := make(map[string]*config.RestartPolicy)
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		logf("Warning: loading restart policies: %v", err)
+	} else {
+		for agent, policy := range settings.Restart {
+			if _, ok := agents[agent]; !ok {
+				logf("Warning: no supervised session for %q, ignoring its restart policy", agent)
+				continue
+			}
+			switch policy.GetPolicy() {
+			case config.RestartNever, config.RestartOnFailure, config.RestartAlways:
+				policies[agent] = policy
+			default:
+				logf("Warning: unknown restart policy %q for %s, ignoring it", policy.Policy, agent)
+			}
+		}
+	}
+
+	return &Supervisor{
+		townRoot: townRoot,
+		policies: policies,
+		agents:   agents,
+		probe:    t,
+		logf:     logf,
+		now:      time.Now,
+	}
+}
+
+// Enabled reports whether any agent has a restart policy.
+func (s *Supervisor) Enabled() bool {
+	return s != nil && len(s.policies) > 0
+}
+
+// Supervises reports whether an agent's restarts are governed by a policy,
+// in which case the heartbeat leaves starting it to the supervisor.
+func (s *Supervisor) Supervises(agent string) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.policies[agent]
+	return ok
+}
+
+// Start begins checking agents in the background.
+func (s *Supervisor) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+// Stop halts the loop and waits for a check in progress to finish.
+func (s *Supervisor) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+}
+
+func (s *Supervisor) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(supervisorInterval)
+	defer ticker.Stop()
+	for {
+		s.check()
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check runs one pass over the supervised agents.
+func (s *Supervisor) check() {
+	records, err := LoadSupervised(s.townRoot)
+	if err != nil {
+		s.logf("Warning: loading supervisor state: %v", err)
+		return
+	}
+	names := make([]string, 0, len(s.policies))
+	for name := range s.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rec := records[name]
+		s.checkAgent(name, s.policies[name], &rec)
+		records[name] = rec
+	}
+	if err := SaveSupervised(s.townRoot, records); err != nil {
+		s.logf("Warning: saving supervisor state: %v", err)
+	}
+}
+
+// checkAgent applies an agent's restart policy to its current health.
+func (s *Supervisor) checkAgent(name string, policy *config.RestartPolicy, rec *SupervisedAgent) {
+	agent := s.agents[name]
+	now := s.now()
+	if !rec.LastRestart.IsZero() && now.Sub(rec.LastRestart) < restartGrace {
+		return // still coming up
+	}
+
+	running, err := s.probe.HasSession(agent.session)
+	if err != nil {
+		s.logf("Supervisor: checking %s: %v", name, err)
+		return
+	}
+	if running && s.probe.IsClaudeRunning(agent.session) {
+		rec.Up = true
+		rec.Stopped = false
+		rec.Halted = false
+		if rec.Restarts > 0 && now.Sub(rec.LastRestart) >= restartStable {
+			rec.Restarts = 0
+		}
+		return
+	}
+
+	reason := downNotRunning
+	switch {
+	case running:
+		reason = downZombie
+	case rec.Up && !rec.Stopped:
+		reason = downCrashed
+	}
+
+	mode := policy.GetPolicy()
+	if mode == config.RestartNever || (mode == config.RestartOnFailure && reason == downNotRunning) {
+		rec.Up = false
+		return
+	}
+	if rec.Halted {
+		return
+	}
+	if policy.MaxRestarts > 0 && rec.Restarts >= policy.MaxRestarts {
+		rec.Halted = true
+		rec.Up = false
+		s.logf("Supervisor: %s is %s after %d restarts in a row, giving up", name, reason, rec.Restarts)
+		s.emit(events.TypeAgentRestartHalt, events.RestartPayload(name, agent.session, reason, mode, rec.Restarts))
+		return
+	}
+	if rec.Restarts > 0 && now.Before(rec.LastRestart.Add(restartBackoff(policy, rec.Restarts))) {
+		return // backing off; still counts as down for reason
+	}
+
+	rec.Restarts++
+	rec.LastRestart = now
+	rec.Up = true // a restart that doesn't stick is a crash
+	rec.Stopped = false
+	if err := agent.start(); err != nil {
+		s.logf("Supervisor: restarting %s (%s, attempt %d): %v", name, reason, rec.Restarts, err)
+		return
+	}
+	s.logf("Supervisor: restarted %s (%s, attempt %d)", name, reason, rec.Restarts)
+	s.emit(events.TypeAgentRestarted, events.RestartPayload(name, agent.session, reason, mode, rec.Restarts))
+}
+
+// restartBackoff is the wait before restart number attempt+1: the policy's
+// backoff doubled for each restart in a row so far, capped.
+func restartBackoff(policy *config.RestartPolicy, attempt int) time.Duration {
+	d := policy.GetBackoff()
+	for i := 1; i < attempt && d < maxRestartBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRestartBackoff {
+		d = maxRestartBackoff
+	}
+	return d
+}
+
+func (s *Supervisor) emit(eventType string, payload map[string]interface{}) {
+	err := events.Append(s.townRoot, events.Event{
+		Timestamp:  s.now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      "daemon",
+		Payload:    payload,
+		Visibility: events.VisibilityFeed,
+	})
+	if err != nil {
+		s.logf("Warning: logging %s event: %v", eventType, err)
+	}
+}
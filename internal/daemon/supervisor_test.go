@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// fakeProbe reports a single session as up, zombie or gone.
+type fakeProbe struct {
+	session bool
+	agent   bool
+}
+
+func (p *fakeProbe) HasSession(string) (bool, error) { return p.session, nil }
+func (p *fakeProbe) IsClaudeRunning(string) bool     { return p.session && p.agent }
+
+// newTestSupervisor supervises one agent, "mayor", whose start brings the
+// fake session up and counts calls.
+func newTestSupervisor(t *testing.T, policy *config.RestartPolicy) (*Supervisor, *fakeProbe, *int, *time.Time) {
+	t.Helper()
+	probe := &fakeProbe{}
+	starts := 0
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &Supervisor{
+		townRoot: t.TempDir(),
+		policies: map[string]*config.RestartPolicy{"mayor": policy},
+		agents: map[string]managedAgent{"mayor": {session: "hq-mayor", start: func() error {
+			starts++
+			probe.session, probe.agent = true, true
+			return nil
+		}}},
+		probe: probe,
+		logf:  func(string, ...interface{}) {},
+		now:   func() time.Time { return now },
+	}
+	return s, probe, &starts, &now
+}
+
+func TestSupervisor_OnFailure(t *testing.T) {
+	s, probe, starts, now := newTestSupervisor(t, &config.RestartPolicy{Policy: config.RestartOnFailure, Backoff: "5m"})
+
+	// Never seen running: on-failure leaves it alone
+	s.check()
+	if *starts != 0 {
+		t.Fatalf("started an agent that was never up (%d starts)", *starts)
+	}
+
+	// Up, then crashed: restarted
+	probe.session, probe.agent = true, true
+	s.check()
+	probe.session, probe.agent = false, false
+	s.check()
+	if *starts != 1 {
+		t.Fatalf("starts after crash = %d, want 1", *starts)
+	}
+
+	// Zombie after the grace period: restarted again after backoff
+	*now = now.Add(restartGrace)
+	probe.agent = false
+	s.check()
+	if *starts != 1 {
+		t.Fatalf("restarted within backoff (%d starts)", *starts)
+	}
+	*now = now.Add(5 * time.Minute)
+	s.check()
+	if *starts != 2 {
+		t.Fatalf("starts after zombie = %d, want 2", *starts)
+	}
+
+	// Stopped on purpose: left down
+	*now = now.Add(restartStable)
+	s.check()
+	if err := MarkStopped(s.townRoot, "mayor"); err != nil {
+		t.Fatal(err)
+	}
+	probe.session, probe.agent = false, false
+	s.check()
+	if *starts != 2 {
+		t.Errorf("restarted a stopped agent (%d starts)", *starts)
+	}
+
+	records, err := events.ReadAll(s.townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Type != events.TypeAgentRestarted || records[0].Payload["reason"] != downCrashed ||
+		records[1].Payload["reason"] != downZombie {
+		t.Errorf("restart events = %+v", records)
+	}
+}
+
+func TestSupervisor_MaxRestarts(t *testing.T) {
+	s, probe, starts, now := newTestSupervisor(t, &config.RestartPolicy{Policy: config.RestartAlways, MaxRestarts: 2, Backoff: "1s"})
+	s.agents["mayor"] = managedAgent{session: "hq-mayor", start: func() error { *starts++; return nil }} // never comes up
+
+	for i := 0; i < 10; i++ {
+		s.check()
+		*now = now.Add(time.Hour)
+	}
+	if *starts != 2 {
+		t.Errorf("starts = %d, want max_restarts 2", *starts)
+	}
+	records, err := LoadSupervised(s.townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !records["mayor"].Halted {
+		t.Errorf("record = %+v, want halted", records["mayor"])
+	}
+
+	// Started by hand: the supervisor takes over again
+	probe.session, probe.agent = true, true
+	s.check()
+	if records, _ := LoadSupervised(s.townRoot); records["mayor"].Halted {
+		t.Error("still halted after the agent came back up")
+	}
+}
+
+func TestSupervisor_Never(t *testing.T) {
+	s, probe, starts, _ := newTestSupervisor(t, &config.RestartPolicy{Policy: config.RestartNever})
+	probe.session, probe.agent = true, true
+	s.check()
+	probe.session = false
+	s.check()
+	if *starts != 0 {
+		t.Errorf("never policy restarted the agent %d times", *starts)
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	p := &config.RestartPolicy{Backoff: "10m"}
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Minute, 2: 20 * time.Minute, 3: maxRestartBackoff, 9: maxRestartBackoff} {
+		if got := restartBackoff(p, attempt); got != want {
+			t.Errorf("restartBackoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/keepalive"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SuspendedAgent records an agent the daemon suspended for being idle.
+type SuspendedAgent struct {
+	Identity    string    `json:"identity"`
+	Session     string    `json:"session"`
+	Mode        string    `json:"mode"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+// SuspendedFile returns the path to the record of suspended agents.
+func SuspendedFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "suspended.json")
+}
+
+// LoadSuspended loads the suspended agents, keyed by identity.
+// Returns an empty map if none are recorded.
+func LoadSuspended(townRoot string) (map[string]SuspendedAgent, error) {
+	data, err := os.ReadFile(SuspendedFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]SuspendedAgent{}, nil
+		}
+		return nil, err
+	}
+
+	agents := map[string]SuspendedAgent{}
+	if err := json.Unmarshal(data, &agents); err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// SaveSuspended saves the suspended agents using atomic write.
+func SaveSuspended(townRoot string, agents map[string]SuspendedAgent) error {
+	path := SuspendedFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, agents)
+}
+
+// suspendedPaneCommand replaces an agent's runtime in "exit" mode, leaving a
+// shell that explains why the agent stopped.
+const suspendedPaneCommand = `echo "Suspended by the gt daemon while idle; resumes on new mail or hooked work."; exec "${SHELL:-/bin/sh}"`
+
+// lastActivity returns the most recent sign of life for an agent: its tmux
+// session activity (unix seconds) or the keepalive in its workspace,
+// whichever is later. Returns the zero time if neither is known.
+func lastActivity(sessionActivity string, agent *keepalive.State) time.Time {
+	var last time.Time
+	if secs, err := strconv.ParseInt(strings.TrimSpace(sessionActivity), 10, 64); err == nil && secs > 0 {
+		last = time.Unix(secs, 0)
+	}
+	if agent != nil && agent.Timestamp.After(last) {
+		last = agent.Timestamp
+	}
+	return last
+}
+
+// isSuspended reports whether the daemon suspended this agent. Patrols that
+// restart dead agents skip suspended ones.
+func (d *Daemon) isSuspended(identity string) bool {
+	_, ok := d.suspended[identity]
+	return ok
+}
+
+// manageIdleAgents resumes suspended agents that have new mail or hooked
+// work, then suspends agents that have been idle past the threshold.
+// Does nothing unless auto_suspend is configured in mayor/daemon.json; if it
+// is switched off, every suspended agent is resumed.
+func (d *Daemon) manageIdleAgents() {
+	if d.suspended == nil {
+		suspended, err := LoadSuspended(d.config.TownRoot)
+		if err != nil {
+			d.logger.Printf("Warning: failed to load suspended agents: %v", err)
+			suspended = map[string]SuspendedAgent{}
+		}
+		d.suspended = suspended
+	}
+
+	var cfg *AutoSuspendConfig
+	if d.patrolConfig != nil {
+		cfg = d.patrolConfig.AutoSuspend
+	}
+	if (cfg == nil || !cfg.Enabled) && len(d.suspended) == 0 {
+		return
+	}
+
+	changed := d.resumeSuspendedAgents(cfg)
+	if cfg != nil && cfg.Enabled {
+		if d.suspendIdleAgents(cfg) {
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := SaveSuspended(d.config.TownRoot, d.suspended); err != nil {
+			d.logger.Printf("Warning: failed to save suspended agents: %v", err)
+		}
+	}
+}
+
+// resumeSuspendedAgents restarts suspended agents that have something to do.
+// Returns true if the suspended set changed.
+func (d *Daemon) resumeSuspendedAgents(cfg *AutoSuspendConfig) bool {
+	changed := false
+	for _, identity := range sortedIdentities(d.suspended) {
+		agent := d.suspended[identity]
+
+		// Someone restarted the agent by hand: nothing to resume
+		if d.tmux.IsClaudeRunning(agent.Session) {
+			d.logger.Printf("Suspended agent %s is running again, forgetting suspension", identity)
+			delete(d.suspended, identity)
+			changed = true
+			continue
+		}
+
+		reason := d.resumeReason(identity)
+		if reason == "" && (cfg == nil || !cfg.Enabled) {
+			reason = "auto-suspend disabled"
+		}
+		if reason == "" {
+			continue
+		}
+
+		d.logger.Printf("Resuming %s (%s)", identity, reason)
+		if err := d.restartSession(agent.Session, identity); err != nil {
+			d.logger.Printf("Error resuming %s: %v", identity, err)
+			continue
+		}
+		delete(d.suspended, identity)
+		changed = true
+		_ = events.LogFeed(events.TypeSessionResumed, "daemon",
+			events.SuspendPayload(agent.Session, identityToBDActor(identity), agent.Mode, reason))
+	}
+	return changed
+}
+
+// resumeReason returns why a suspended agent should wake up, or "" if it
+// has nothing to do.
+func (d *Daemon) resumeReason(identity string) string {
+	address := identityToBDActor(identity)
+	mailbox, _ := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot).GetMailbox(address)
+	if _, unread, err := mailbox.Count(); err == nil && unread > 0 {
+		return "unread mail"
+	}
+
+	if info, err := d.getAgentBeadInfo(d.identityToAgentBeadID(identity)); err == nil && info.HookBead != "" {
+		return "hooked work " + info.HookBead
+	}
+	return ""
+}
+
+// suspendIdleAgents suspends running agents that have been idle longer than
+// the configured threshold. Agents with a human attached, unread mail or
+// hooked work are left alone. Returns true if any agent was suspended.
+func (d *Daemon) suspendIdleAgents(cfg *AutoSuspendConfig) bool {
+	threshold := cfg.IdleThreshold()
+	mode := cfg.SuspendMode()
+
+	changed := false
+	for _, identity := range d.suspendCandidates(cfg) {
+		if d.isSuspended(identity) {
+			continue
+		}
+		sessionName := d.identityToSession(identity)
+		if sessionName == "" || !d.tmux.IsClaudeRunning(sessionName) {
+			continue
+		}
+
+		info, err := d.tmux.GetSessionInfo(sessionName)
+		if err != nil || info.Attached {
+			continue
+		}
+		var agentKeepalive *keepalive.State
+		if workDir := d.agentWorkDir(identity); workDir != "" {
+			agentKeepalive = d.agentKeepalive(workDir)
+		}
+		last := lastActivity(info.Activity, agentKeepalive)
+		if last.IsZero() {
+			continue
+		}
+		idle := time.Since(last)
+		if idle < threshold {
+			continue
+		}
+		if d.resumeReason(identity) != "" {
+			continue
+		}
+
+		if err := d.suspendAgent(sessionName, mode); err != nil {
+			d.logger.Printf("Error suspending %s: %v", identity, err)
+			continue
+		}
+
+		reason := fmt.Sprintf("idle %s", idle.Round(time.Minute))
+		d.logger.Printf("Suspended %s (%s, mode %s)", identity, reason, mode)
+		d.suspended[identity] = SuspendedAgent{
+			Identity:    identity,
+			Session:     sessionName,
+			Mode:        mode,
+			SuspendedAt: time.Now().UTC(),
+		}
+		changed = true
+		_ = events.LogFeed(events.TypeSessionSuspended, "daemon",
+			events.SuspendPayload(sessionName, identityToBDActor(identity), mode, reason))
+	}
+	return changed
+}
+
+// agentWorkDir returns an agent's working directory, or "" if unknown.
+func (d *Daemon) agentWorkDir(identity string) string {
+	roleConfig, parsed, err := d.getRoleConfigForIdentity(identity)
+	if err != nil {
+		return ""
+	}
+	return d.getWorkDir(roleConfig, parsed)
+}
+
+// suspendAgent stops an agent's runtime. In exit mode the session is kept
+// with a shell in place of the runtime; in park mode it is killed.
+func (d *Daemon) suspendAgent(sessionName, mode string) error {
+	if mode == SuspendModePark {
+		return d.tmux.KillSessionWithProcesses(sessionName)
+	}
+
+	paneID, err := d.tmux.GetPaneID(sessionName)
+	if err != nil {
+		return fmt.Errorf("finding pane: %w", err)
+	}
+	return d.tmux.RespawnPane(paneID, suspendedPaneCommand)
+}
+
+// suspendCandidates lists the identities auto-suspend applies to: each
+// rig's witness and refinery and its crew members, filtered by role.
+func (d *Daemon) suspendCandidates(cfg *AutoSuspendConfig) []string {
+	var identities []string
+	rigs := d.getKnownRigs()
+	sort.Strings(rigs)
+	for _, rigName := range rigs {
+		if operational, _ := d.isRigOperational(rigName); !operational {
+			continue
+		}
+		for _, role := range []string{"witness", "refinery"} {
+			if cfg.AppliesTo(role) {
+				identities = append(identities, rigName+"-"+role)
+			}
+		}
+		if !cfg.AppliesTo("crew") {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(d.config.TownRoot, rigName, "crew"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				identities = append(identities, rigName+"-crew-"+entry.Name())
+			}
+		}
+	}
+	return identities
+}
+
+// sortedIdentities returns the keys of a suspended set in order, so
+// resumption is deterministic.
+func sortedIdentities(agents map[string]SuspendedAgent) []string {
+	identities := make([]string, 0, len(agents))
+	for identity := range agents {
+		identities = append(identities, identity)
+	}
+	sort.Strings(identities)
+	return identities
+}
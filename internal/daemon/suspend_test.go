@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+func TestLoadPatrolConfig_AutoSuspend(t *testing.T) {
+	tmpDir := t.TempDir()
+	mayorDir := filepath.Join(tmpDir, "mayor")
+	if err := os.MkdirAll(mayorDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configJSON := `{
+		"type": "daemon-patrol-config",
+		"version": 1,
+		"auto_suspend": {"enabled": true, "idle_after": "90m", "mode": "park", "roles": ["crew"]}
+	}`
+	if err := os.WriteFile(filepath.Join(mayorDir, "daemon.json"), []byte(configJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := LoadPatrolConfig(tmpDir)
+	if config == nil || config.AutoSuspend == nil {
+		t.Fatal("expected auto_suspend config to be loaded")
+	}
+	cfg := config.AutoSuspend
+	if got := cfg.IdleThreshold(); got != 90*time.Minute {
+		t.Errorf("IdleThreshold() = %v, want 90m", got)
+	}
+	if got := cfg.SuspendMode(); got != SuspendModePark {
+		t.Errorf("SuspendMode() = %q, want park", got)
+	}
+	if !cfg.AppliesTo("crew") || cfg.AppliesTo("witness") {
+		t.Error("expected auto-suspend to apply to crew only")
+	}
+}
+
+func TestAutoSuspendConfigDefaults(t *testing.T) {
+	var nilCfg *AutoSuspendConfig
+	if nilCfg.AppliesTo("witness") {
+		t.Error("nil config should not apply to any role")
+	}
+	if got := nilCfg.IdleThreshold(); got != DefaultSuspendIdleAfter {
+		t.Errorf("nil IdleThreshold() = %v, want %v", got, DefaultSuspendIdleAfter)
+	}
+
+	cfg := &AutoSuspendConfig{Enabled: true, IdleAfter: "soon", Mode: "hibernate"}
+	if got := cfg.IdleThreshold(); got != DefaultSuspendIdleAfter {
+		t.Errorf("invalid IdleThreshold() = %v, want default", got)
+	}
+	if got := cfg.SuspendMode(); got != DefaultSuspendMode {
+		t.Errorf("unknown SuspendMode() = %q, want default", got)
+	}
+	for _, role := range DefaultSuspendRoles {
+		if !cfg.AppliesTo(role) {
+			t.Errorf("expected default roles to include %s", role)
+		}
+	}
+	for _, role := range []string{"mayor", "deacon", "polecat"} {
+		if cfg.AppliesTo(role) {
+			t.Errorf("auto-suspend should never apply to %s", role)
+		}
+	}
+
+	disabled := &AutoSuspendConfig{Roles: []string{"crew"}}
+	if disabled.AppliesTo("crew") {
+		t.Error("disabled config should not apply to any role")
+	}
+}
+
+func TestSaveLoadSuspended_Roundtrip(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	agents, err := LoadSuspended(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadSuspended (missing file): %v", err)
+	}
+	if len(agents) != 0 {
+		t.Fatalf("expected no suspended agents, got %v", agents)
+	}
+
+	suspendedAt := time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)
+	agents["gastown-witness"] = SuspendedAgent{
+		Identity:    "gastown-witness",
+		Session:     "gt-gastown-witness",
+		Mode:        SuspendModeExit,
+		SuspendedAt: suspendedAt,
+	}
+	if err := SaveSuspended(tmpDir, agents); err != nil {
+		t.Fatalf("SaveSuspended: %v", err)
+	}
+
+	loaded, err := LoadSuspended(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadSuspended: %v", err)
+	}
+	got, ok := loaded["gastown-witness"]
+	if !ok {
+		t.Fatal("suspended witness not found after roundtrip")
+	}
+	if got.Session != "gt-gastown-witness" || got.Mode != SuspendModeExit || !got.SuspendedAt.Equal(suspendedAt) {
+		t.Errorf("roundtrip = %+v", got)
+	}
+}
+
+func TestLastActivity(t *testing.T) {
+	session := time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)
+	sessionActivity := strconv.FormatInt(session.Unix(), 10)
+
+	if got := lastActivity(sessionActivity, nil); !got.Equal(session) {
+		t.Errorf("session only = %v, want %v", got, session)
+	}
+
+	later := &keepalive.State{Timestamp: session.Add(time.Hour)}
+	if got := lastActivity(sessionActivity, later); !got.Equal(later.Timestamp) {
+		t.Errorf("newer keepalive = %v, want %v", got, later.Timestamp)
+	}
+
+	earlier := &keepalive.State{Timestamp: session.Add(-time.Hour)}
+	if got := lastActivity(sessionActivity, earlier); !got.Equal(session) {
+		t.Errorf("older keepalive = %v, want %v", got, session)
+	}
+
+	if got := lastActivity("", nil); !got.IsZero() {
+		t.Errorf("no signals = %v, want zero", got)
+	}
+}
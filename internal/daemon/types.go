@@ -59,6 +59,14 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// HeartbeatInterval is the current interval between heartbeats.
+	HeartbeatInterval string `json:"heartbeat_interval,omitempty"`
+
+	// BackoffLevel is how many times the heartbeat interval has been backed
+	// off from its base value. It stays 0 unless heartbeat.backoff is set in
+	// mayor/daemon.json.
+	BackoffLevel int `json:"backoff_level"`
 }
 
 // StateFile returns the path to the state file.
@@ -121,6 +129,96 @@ type DaemonPatrolConfig struct {
 	Version   int            `json:"version"`
 	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
 	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+
+	// AutoSuspend suspends idle agents to save API spend (opt-in).
+	AutoSuspend *AutoSuspendConfig `json:"auto_suspend,omitempty"`
+}
+
+// Auto-suspend modes.
+const (
+	// SuspendModeExit stops the agent's runtime but keeps its tmux session,
+	// leaving a shell so the pane's history stays attachable.
+	SuspendModeExit = "exit"
+
+	// SuspendModePark kills the agent's tmux session entirely.
+	SuspendModePark = "park"
+)
+
+// Auto-suspend defaults.
+const (
+	DefaultSuspendIdleAfter = 2 * time.Hour
+	DefaultSuspendMode      = SuspendModeExit
+)
+
+// DefaultSuspendRoles are the roles auto-suspend applies to when none are
+// configured. Mayor and deacon are never suspended: the deacon drives
+// resumption and the mayor is the overseer's entry point. Polecats are
+// managed by their witness.
+var DefaultSuspendRoles = []string{"witness", "refinery", "crew"}
+
+// AutoSuspendConfig controls suspending agents that have been idle — no
+// tmux pane activity and no gt commands anywhere in the town — for longer
+// than IdleAfter. Suspended agents are resumed when they get unread mail
+// or hooked work.
+type AutoSuspendConfig struct {
+	// Enabled turns auto-suspend on.
+	Enabled bool `json:"enabled"`
+
+	// IdleAfter is how long an agent must be idle before it is suspended
+	// (Go duration, e.g. "90m"). Defaults to 2h.
+	IdleAfter string `json:"idle_after,omitempty"`
+
+	// Mode is "exit" (default) or "park".
+	Mode string `json:"mode,omitempty"`
+
+	// Roles limits auto-suspend to these roles (witness, refinery, crew).
+	Roles []string `json:"roles,omitempty"`
+}
+
+// IdleThreshold returns the configured idle threshold, or the default if
+// unset or invalid.
+func (c *AutoSuspendConfig) IdleThreshold() time.Duration {
+	if c != nil && c.IdleAfter != "" {
+		if d, err := time.ParseDuration(c.IdleAfter); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultSuspendIdleAfter
+}
+
+// SuspendMode returns the configured mode, or the default if unset or
+// unknown.
+func (c *AutoSuspendConfig) SuspendMode() string {
+	if c != nil && (c.Mode == SuspendModeExit || c.Mode == SuspendModePark) {
+		return c.Mode
+	}
+	return DefaultSuspendMode
+}
+
+// AppliesTo reports whether auto-suspend covers the given role.
+// Roles outside DefaultSuspendRoles are never covered.
+func (c *AutoSuspendConfig) AppliesTo(role string) bool {
+	if c == nil || !c.Enabled {
+		return false
+	}
+	supported := false
+	for _, r := range DefaultSuspendRoles {
+		if r == role {
+			supported = true
+		}
+	}
+	if !supported {
+		return false
+	}
+	if len(c.Roles) == 0 {
+		return true
+	}
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
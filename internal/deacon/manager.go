@@ -3,13 +3,10 @@ package deacon
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/steveyegge/gastown/internal/claude"
-	"github.com/steveyegge/gastown/internal/config"
-	"github.com/steveyegge/gastown/internal/constants"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -20,6 +17,9 @@ var (
 	ErrAlreadyRunning = errors.New("deacon already running")
 )
 
+// logger records session starts and stops.
+var logger = gtlog.For("deacon")
+
 // Manager handles deacon lifecycle operations.
 type Manager struct {
 	townRoot string
@@ -52,95 +52,54 @@ func (m *Manager) deaconDir() string {
 // agentOverride allows specifying an alternate agent alias (e.g., for testing).
 // Restarts are handled by daemon via ensureDeaconRunning on each heartbeat.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	mux := tmux.NewMultiplexer(m.townRoot)
+	t, isTmux := mux.(*tmux.Tmux)
 	sessionID := m.SessionName()
 
 	// Check if session already exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := mux.HasSession(sessionID)
 	if running {
-		// Session exists - check if Claude is actually running (healthy vs zombie)
-		if t.IsClaudeRunning(sessionID) {
+		// Session exists - check if Claude is actually running (healthy vs zombie).
+		// Only tmux can inspect the pane, so other multiplexers trust the session.
+		if !isTmux || t.IsClaudeRunning(sessionID) {
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
+		logger.Warn("killing zombie session", "session", sessionID)
 		if err := t.KillSession(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
 
-	// Ensure deacon directory exists
-	deaconDir := m.deaconDir()
-	if err := os.MkdirAll(deaconDir, 0755); err != nil {
-		return fmt.Errorf("creating deacon directory: %w", err)
-	}
-
-	// Ensure Claude settings exist
-	if err := claude.EnsureSettingsForRole(deaconDir, "deacon"); err != nil {
-		return fmt.Errorf("ensuring Claude settings: %w", err)
-	}
-
-	// Build startup command first
 	// Restarts are handled by daemon via ensureDeaconRunning on each heartbeat
-	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("deacon", "", m.townRoot, "", "", agentOverride)
-	if err != nil {
-		return fmt.Errorf("building startup command: %w", err)
-	}
-
-	// Create session with command directly to avoid send-keys race condition.
-	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, deaconDir, startupCmd); err != nil {
-		return fmt.Errorf("creating tmux session: %w", err)
-	}
-
-	// Set environment variables (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:     "deacon",
-		TownRoot: m.townRoot,
-	})
-	for k, v := range envVars {
-		_ = t.SetEnvironment(sessionID, k, v)
+	if err := session.RunStartup(session.StartupSpec{
+		Role:          "deacon",
+		TownRoot:      m.townRoot,
+		Dir:           m.deaconDir(),
+		Session:       sessionID,
+		AgentOverride: agentOverride,
+		Theme:         tmux.DeaconTheme(),
+		DisplayName:   "Deacon",
+		Focus:         "health-check",
+		Nudge: session.StartupNudgeConfig{
+			Recipient: "deacon",
+			Sender:    "daemon",
+			Topic:     "patrol",
+		},
+	}); err != nil {
+		return err
 	}
-
-	// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.DeaconTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
-
-	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Kill the zombie session before returning error
-		_ = t.KillSessionWithProcesses(sessionID)
-		return fmt.Errorf("waiting for deacon to start: %w", err)
-	}
-
-	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
-
-	time.Sleep(constants.ShutdownNotifyDelay)
-
-	// Inject startup nudge for predecessor discovery via /resume
-	_ = session.StartupNudge(t, sessionID, session.StartupNudgeConfig{
-		Recipient: "deacon",
-		Sender:    "daemon",
-		Topic:     "patrol",
-	}) // Non-fatal
-
-	// GUPP: Gas Town Universal Propulsion Principle
-	// Send the propulsion nudge to trigger autonomous patrol execution.
-	// Wait for beacon to be fully processed (needs to be separate prompt)
-	time.Sleep(2 * time.Second)
-	_ = t.NudgeSession(sessionID, session.PropulsionNudgeForRole("deacon", deaconDir)) // Non-fatal
-
+	logger.Info("session started", "session", sessionID, "agent", agentOverride)
 	return nil
 }
 
 // Stop stops the deacon session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	mux := tmux.NewMultiplexer(m.townRoot)
 	sessionID := m.SessionName()
 
 	// Check if session exists
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -149,29 +108,32 @@ func (m *Manager) Stop() error {
 	}
 
 	// Try graceful shutdown first (best-effort interrupt)
-	_ = t.SendKeysRaw(sessionID, "C-c")
-	time.Sleep(100 * time.Millisecond)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		_ = t.SendKeysRaw(sessionID, "C-c")
+		time.Sleep(100 * time.Millisecond)
+	}
 
 	// Kill the session
-	if err := t.KillSession(sessionID); err != nil {
+	if err := mux.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	logger.Info("session stopped", "session", sessionID)
 
 	return nil
 }
 
 // IsRunning checks if the deacon session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	return tmux.NewMultiplexer(m.townRoot).HasSession(m.SessionName())
 }
 
 // Status returns information about the deacon session.
+// Multiplexers other than tmux report only the session name.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	mux := tmux.NewMultiplexer(m.townRoot)
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -179,5 +141,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		return t.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
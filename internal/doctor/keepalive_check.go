@@ -0,0 +1,63 @@
+package doctor
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+// StaleKeepaliveAge is how long a workspace can go without any gt command
+// before doctor reports it. Idle agents normally touch their keepalive well
+// within this; older ones are usually abandoned workspaces.
+const StaleKeepaliveAge = 24 * time.Hour
+
+// StaleKeepaliveCheck reports agent workspaces whose keepalive has gone
+// stale.
+type StaleKeepaliveCheck struct {
+	BaseCheck
+}
+
+// NewStaleKeepaliveCheck creates a new stale keepalive check.
+func NewStaleKeepaliveCheck() *StaleKeepaliveCheck {
+	return &StaleKeepaliveCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "stale-keepalives",
+			CheckDescription: "Detect agent workspaces with no gt activity in 24h",
+			CheckCategory:    CategoryCleanup,
+		},
+	}
+}
+
+// Run lists workspaces whose keepalive is older than StaleKeepaliveAge.
+func (c *StaleKeepaliveCheck) Run(ctx *CheckContext) *CheckResult {
+	workspaces := keepalive.Workspaces(ctx.TownRoot)
+	var stale []string
+	for _, ws := range workspaces {
+		state := keepalive.Read(ws)
+		if state == nil || !state.IsStale(StaleKeepaliveAge) {
+			continue
+		}
+		rel, err := filepath.Rel(ctx.TownRoot, ws)
+		if err != nil {
+			rel = ws
+		}
+		stale = append(stale, filepath.ToSlash(rel)+": last '"+state.LastCommand+"' "+
+			state.Age().Round(time.Hour).String()+" ago")
+	}
+
+	if len(stale) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: itoa(len(stale)) + " workspace(s) with stale keepalives",
+			Details: stale,
+			FixHint: "Restart the agent if it should be working, or remove the workspace if it's abandoned",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: itoa(len(workspaces)) + " keepalive(s) fresh",
+	}
+}
@@ -0,0 +1,33 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+func TestStaleKeepaliveCheck(t *testing.T) {
+	town := t.TempDir()
+	keepalive.TouchInWorkspace(filepath.Join(town, "gastown", "witness"), "gt patrol")
+
+	stale := filepath.Join(town, "gastown", "polecats", "nux")
+	if err := os.MkdirAll(filepath.Join(stale, ".runtime"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(keepalive.State{LastCommand: "gt done", Timestamp: time.Now().Add(-48 * time.Hour)})
+	if err := os.WriteFile(filepath.Join(stale, ".runtime", "keepalive.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewStaleKeepaliveCheck().Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("got %v %v, want one stale workspace", result.Status, result.Details)
+	}
+	if want := "gastown/polecats/nux: last 'gt done' 48h0m0s ago"; result.Details[0] != want {
+		t.Errorf("detail = %q, want %q", result.Details[0], want)
+	}
+}
@@ -148,8 +148,8 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 			continue
 		}
 		// Log pre-death event for crash investigation (before killing)
-		_ = events.LogFeed(events.TypeSessionDeath, sess,
-			events.SessionDeathPayload(sess, "unknown", "orphan cleanup", "gt doctor"))
+		_ = events.LogTo(ctx.TownRoot, events.TypeSessionDeath, sess,
+			events.SessionDeathPayload(sess, "unknown", "orphan cleanup", "gt doctor"), events.VisibilityFeed)
 		if err := t.KillSession(sess); err != nil {
 			lastErr = err
 		}
@@ -0,0 +1,31 @@
+package doctor
+
+import "sync"
+
+var (
+	registryMu sync.Mutex
+	registry   []func() Check
+)
+
+// RegisterCheck adds a check that gt doctor runs alongside the built-in
+// ones. Packages call it from init so their diagnostics ship with the
+// package; the factory is called once per doctor run. A package that
+// doctor itself imports can't register this way and is wired up in
+// gt doctor directly.
+func RegisterCheck(factory func() Check) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, factory)
+}
+
+// RegisteredChecks returns fresh instances of the checks added with
+// RegisterCheck, in registration order.
+func RegisteredChecks() []Check {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	checks := make([]Check, 0, len(registry))
+	for _, factory := range registry {
+		checks = append(checks, factory())
+	}
+	return checks
+}
@@ -0,0 +1,26 @@
+package doctor
+
+import "testing"
+
+func TestRegisterCheck(t *testing.T) {
+	registryMu.Lock()
+	saved := registry
+	registry = nil
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	RegisterCheck(func() Check { return NewClaudeCLICheck() })
+	RegisterCheck(func() Check { return NewStaleKeepaliveCheck() })
+
+	checks := RegisteredChecks()
+	if len(checks) != 2 || checks[0].Name() != "claude-available" || checks[1].Name() != "stale-keepalives" {
+		t.Errorf("RegisteredChecks = %v", checks)
+	}
+	if again := RegisteredChecks(); again[0] == checks[0] {
+		t.Error("RegisteredChecks should return fresh instances")
+	}
+}
@@ -0,0 +1,87 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// runtimeDirs are town directories gt and the daemon write state into.
+var runtimeDirs = []string{".runtime", "daemon"}
+
+// RuntimeDirsCheck verifies the town's runtime state directories exist and
+// are writable.
+type RuntimeDirsCheck struct {
+	FixableCheck
+	missing []string
+}
+
+// NewRuntimeDirsCheck creates a new runtime directory check.
+func NewRuntimeDirsCheck() *RuntimeDirsCheck {
+	return &RuntimeDirsCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "runtime-dirs-writable",
+				CheckDescription: "Check .runtime/ and daemon/ are writable",
+				CheckCategory:    CategoryCore,
+			},
+		},
+	}
+}
+
+// Run creates and removes a probe file in each runtime directory.
+func (c *RuntimeDirsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.missing = nil
+	var unwritable []string
+	for _, name := range runtimeDirs {
+		dir := filepath.Join(ctx.TownRoot, name)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			c.missing = append(c.missing, name)
+			continue
+		}
+		f, err := os.CreateTemp(dir, ".doctor-probe-*")
+		if err != nil {
+			unwritable = append(unwritable, name+"/: "+err.Error())
+			continue
+		}
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+
+	if len(unwritable) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Runtime directories not writable",
+			Details: unwritable,
+			FixHint: "Fix ownership or permissions so the user running gt can write them",
+		}
+	}
+	if len(c.missing) > 0 {
+		details := make([]string, len(c.missing))
+		for i, name := range c.missing {
+			details[i] = name + "/ missing"
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Runtime directories missing",
+			Details: details,
+			FixHint: "Run 'gt doctor --fix' to create them",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "Runtime directories writable",
+	}
+}
+
+// Fix creates missing runtime directories.
+func (c *RuntimeDirsCheck) Fix(ctx *CheckContext) error {
+	for _, name := range c.missing {
+		if err := os.MkdirAll(filepath.Join(ctx.TownRoot, name), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package doctor
+
+import "testing"
+
+func TestRuntimeDirsCheck(t *testing.T) {
+	town := t.TempDir()
+	ctx := &CheckContext{TownRoot: town}
+	check := NewRuntimeDirsCheck()
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 2 {
+		t.Fatalf("missing dirs: got %v %v, want a warning for both", result.Status, result.Details)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: got %v %s", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,165 @@
+package doctor
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+// MinTmuxVersion is the oldest tmux Gas Town is tested with.
+const MinTmuxVersion = "3.0"
+
+// tmuxVersionRe matches "tmux 3.3a", "tmux next-3.4" and similar.
+var tmuxVersionRe = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// parseTmuxVersion extracts major and minor from `tmux -V` output.
+func parseTmuxVersion(output string) (major, minor int, ok bool) {
+	m := tmuxVersionRe.FindStringSubmatch(output)
+	if m == nil {
+		return 0, 0, false
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	return major, minor, true
+}
+
+// TmuxCheck verifies tmux is installed and recent enough.
+type TmuxCheck struct {
+	BaseCheck
+}
+
+// NewTmuxCheck creates a new tmux availability check.
+func NewTmuxCheck() *TmuxCheck {
+	return &TmuxCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "tmux-available",
+			CheckDescription: "Check tmux " + MinTmuxVersion + "+ is installed",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run checks for tmux in PATH and its version.
+func (c *TmuxCheck) Run(ctx *CheckContext) *CheckResult {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "tmux not found",
+			Details: []string{"Agents run in tmux sessions; gt up and gt sling need it"},
+			FixHint: "Install tmux: apt install tmux (Debian/Ubuntu) or brew install tmux (macOS)",
+		}
+	}
+
+	out, err := exec.Command("tmux", "-V").Output()
+	major, minor, ok := parseTmuxVersion(string(out))
+	if err != nil || !ok {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "tmux found but its version could not be read",
+		}
+	}
+
+	version := strconv.Itoa(major) + "." + strconv.Itoa(minor)
+	if major < 3 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "tmux " + version + " is older than " + MinTmuxVersion,
+			FixHint: "Upgrade tmux to " + MinTmuxVersion + " or newer",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "tmux " + version,
+	}
+}
+
+// BeadsCLICheck verifies bd is installed and compatible.
+type BeadsCLICheck struct {
+	BaseCheck
+}
+
+// NewBeadsCLICheck creates a new bd availability check.
+func NewBeadsCLICheck() *BeadsCLICheck {
+	return &BeadsCLICheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "beads-available",
+			CheckDescription: "Check bd " + deps.MinBeadsVersion + "+ is installed",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run checks for bd in PATH and its version.
+func (c *BeadsCLICheck) Run(ctx *CheckContext) *CheckResult {
+	status, version := deps.CheckBeads()
+	switch status {
+	case deps.BeadsNotFound:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "bd not found",
+			Details: []string{"Work tracking and mail are stored in beads"},
+			FixHint: "Install beads: go install " + deps.BeadsInstallPath,
+		}
+	case deps.BeadsTooOld:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "bd " + version + " is older than " + deps.MinBeadsVersion,
+			FixHint: "Upgrade beads: go install " + deps.BeadsInstallPath,
+		}
+	case deps.BeadsUnknown:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "bd found but its version could not be read",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "bd " + version,
+	}
+}
+
+// ClaudeCLICheck verifies the claude CLI is installed.
+// Towns that run other agents only get a warning.
+type ClaudeCLICheck struct {
+	BaseCheck
+}
+
+// NewClaudeCLICheck creates a new claude CLI availability check.
+func NewClaudeCLICheck() *ClaudeCLICheck {
+	return &ClaudeCLICheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "claude-available",
+			CheckDescription: "Check claude CLI is installed (default agent runtime)",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run checks for claude in PATH.
+func (c *ClaudeCLICheck) Run(ctx *CheckContext) *CheckResult {
+	path, err := exec.LookPath("claude")
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "claude CLI not found",
+			Details: []string{"Agents using the default claude preset won't start"},
+			FixHint: "Install Claude Code (npm install -g @anthropic-ai/claude-code) or set default_agent in settings/config.json",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "claude found at " + path,
+	}
+}
@@ -0,0 +1,23 @@
+package doctor
+
+import "testing"
+
+func TestParseTmuxVersion(t *testing.T) {
+	tests := []struct {
+		output       string
+		major, minor int
+		ok           bool
+	}{
+		{"tmux 3.3a\n", 3, 3, true},
+		{"tmux 2.9", 2, 9, true},
+		{"tmux next-3.4", 3, 4, true},
+		{"tmux master", 0, 0, false},
+	}
+	for _, tt := range tests {
+		major, minor, ok := parseTmuxVersion(tt.output)
+		if major != tt.major || minor != tt.minor || ok != tt.ok {
+			t.Errorf("parseTmuxVersion(%q) = %d, %d, %v; want %d, %d, %v",
+				tt.output, major, minor, ok, tt.major, tt.minor, tt.ok)
+		}
+	}
+}
@@ -125,8 +125,8 @@ func (c *ZombieSessionCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Log pre-death event for audit trail
-		_ = events.LogFeed(events.TypeSessionDeath, sess,
-			events.SessionDeathPayload(sess, "unknown", "zombie cleanup", "gt doctor"))
+		_ = events.LogTo(ctx.TownRoot, events.TypeSessionDeath, sess,
+			events.SessionDeathPayload(sess, "unknown", "zombie cleanup", "gt doctor"), events.VisibilityFeed)
 
 		if err := t.KillSession(sess); err != nil {
 			lastErr = err
@@ -2,6 +2,8 @@ package doctor
 
 import (
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 func TestNewZombieSessionCheck(t *testing.T) {
@@ -70,5 +72,13 @@ func TestZombieSessionCheck_FixProtectsCrewSessions(t *testing.T) {
 	// (We can't fully test this without mocking tmux, but the safeguard is in place)
 	_ = check.Fix(ctx)
 
-	// The test passes if no panic occurred and crew sessions are protected by the safeguard
+	// The pre-death event goes to the checked town, not the cwd's, and
+	// only for the session that isn't crew
+	records, err := events.ReadAll(ctx.TownRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Actor != "gt-gastown-witness" {
+		t.Errorf("town events = %+v, want one session_death for the witness", records)
+	}
 }
@@ -0,0 +1,351 @@
+// Package escalation manages the lifecycle of escalations: opening them,
+// acknowledging and closing them, and renotifying their targets when they
+// sit unacknowledged past their SLA.
+//
+// Each escalation is a record in the town's escalation store. Every
+// transition is also logged to the event feed, which is where the
+// escalation dashboard reads history from.
+package escalation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Escalation statuses.
+const (
+	StatusOpen   = "open"
+	StatusAcked  = "acked"
+	StatusClosed = "closed"
+)
+
+// lockTimeout bounds the wait for the store's lock.
+const lockTimeout = 2 * time.Second
+
+// ErrNotFound indicates an escalation has no record in the store, such as
+// one raised by a witness event or before the store existed.
+var ErrNotFound = errors.New("escalation not found")
+
+// Record is the stored state of an escalation.
+type Record struct {
+	ID       string   `json:"id"`
+	Bead     bool     `json:"bead,omitempty"` // backed by an escalation bead
+	Severity string   `json:"severity"`
+	Summary  string   `json:"summary"`
+	Reason   string   `json:"reason,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	Related  string   `json:"related,omitempty"` // related bead
+	To       []string `json:"to,omitempty"`      // mail targets, renotified past the deadline
+
+	Status   string    `json:"status"`
+	OpenedBy string    `json:"opened_by"`
+	OpenedAt time.Time `json:"opened_at"`
+
+	// Deadline is when an open escalation is next renotified; zero once
+	// renotifications are used up.
+	Deadline     time.Time `json:"deadline,omitempty"`
+	Renotified   int       `json:"renotified,omitempty"`
+	LastNotified time.Time `json:"last_notified,omitempty"`
+
+	AckedBy     string    `json:"acked_by,omitempty"`
+	AckedAt     time.Time `json:"acked_at,omitempty"`
+	ClosedBy    string    `json:"closed_by,omitempty"`
+	ClosedAt    time.Time `json:"closed_at,omitempty"`
+	CloseReason string    `json:"close_reason,omitempty"`
+}
+
+// StorePath returns the path to a town's escalation store.
+func StorePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "escalations.json")
+}
+
+// List returns a town's escalation records, oldest first.
+func List(townRoot string) ([]*Record, error) {
+	store, err := load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, len(store))
+	for _, r := range store {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].OpenedAt.Equal(records[j].OpenedAt) {
+			return records[i].OpenedAt.Before(records[j].OpenedAt)
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+// Get returns the escalation record with id, or ErrNotFound.
+func Get(townRoot, id string) (*Record, error) {
+	store, err := load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := store[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return r, nil
+}
+
+// Options describes an escalation to open.
+type Options struct {
+	ID       string // "" generates one; escalation beads pass the bead ID
+	Bead     bool
+	Severity string
+	Summary  string
+	Reason   string
+	Source   string
+	Related  string
+	OpenedBy string
+	To       []string
+
+	// Payload adds fields to the escalation_sent event.
+	Payload map[string]interface{}
+}
+
+// Open records a new escalation and logs it to the feed. Its deadline is
+// the stale_threshold in settings/escalation.json. Open does not notify the
+// targets; the caller routes the escalation, and CheckSLA renotifies.
+func Open(townRoot string, opts Options) (*Record, error) {
+	if opts.Summary == "" {
+		return nil, fmt.Errorf("summary is required")
+	}
+	if !config.IsValidSeverity(opts.Severity) {
+		return nil, fmt.Errorf("invalid severity %q: must be critical, high, medium, or low", opts.Severity)
+	}
+	id := opts.ID
+	if id == "" {
+		id = generateID()
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	rec := &Record{
+		ID:       id,
+		Bead:     opts.Bead,
+		Severity: opts.Severity,
+		Summary:  opts.Summary,
+		Reason:   opts.Reason,
+		Source:   opts.Source,
+		Related:  opts.Related,
+		To:       opts.To,
+		Status:   StatusOpen,
+		OpenedBy: opts.OpenedBy,
+		OpenedAt: now,
+		Deadline: now.Add(loadConfig(townRoot).GetStaleThreshold()),
+	}
+	err := update(townRoot, func(store map[string]*Record) error {
+		if _, exists := store[id]; exists {
+			return fmt.Errorf("escalation %s already exists", id)
+		}
+		store[id] = rec
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payload := events.EscalationPayload(id, rec.OpenedBy, strings.Join(rec.To, ","), rec.Summary)
+	payload["escalation_id"] = id
+	payload["severity"] = rec.Severity
+	if rec.Source != "" {
+		payload["source"] = rec.Source
+	}
+	for k, v := range opts.Payload {
+		payload[k] = v
+	}
+	emit(townRoot, now, events.TypeEscalationSent, rec.OpenedBy, payload)
+	return rec, nil
+}
+
+// Ack acknowledges an open escalation, which stops its renotifications.
+func Ack(townRoot, id, ackedBy string) (*Record, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	var rec *Record
+	err := update(townRoot, func(store map[string]*Record) error {
+		rec = store[id]
+		if rec == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		if rec.Status != StatusOpen {
+			return fmt.Errorf("escalation %s is already %s", id, rec.Status)
+		}
+		if rec.Bead {
+			if err := beadsFor(townRoot).AckEscalation(id, ackedBy); err != nil {
+				return fmt.Errorf("acknowledging escalation bead: %w", err)
+			}
+		}
+		rec.Status = StatusAcked
+		rec.AckedBy = ackedBy
+		rec.AckedAt = now
+		rec.Deadline = time.Time{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	emit(townRoot, now, events.TypeEscalationAcked, ackedBy, map[string]interface{}{
+		"escalation_id": id,
+		"acked_by":      ackedBy,
+	})
+	return rec, nil
+}
+
+// Close resolves an escalation, open or acknowledged.
+func Close(townRoot, id, closedBy, reason string) (*Record, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	var rec *Record
+	err := update(townRoot, func(store map[string]*Record) error {
+		rec = store[id]
+		if rec == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		if rec.Status == StatusClosed {
+			return fmt.Errorf("escalation %s is already closed", id)
+		}
+		if rec.Bead {
+			if err := beadsFor(townRoot).CloseEscalation(id, closedBy, reason); err != nil {
+				return fmt.Errorf("closing escalation bead: %w", err)
+			}
+		}
+		rec.Status = StatusClosed
+		rec.ClosedBy = closedBy
+		rec.ClosedAt = now
+		rec.CloseReason = reason
+		rec.Deadline = time.Time{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	emit(townRoot, now, events.TypeEscalationClosed, closedBy, map[string]interface{}{
+		"escalation_id": id,
+		"closed_by":     closedBy,
+		"reason":        reason,
+	})
+	return rec, nil
+}
+
+// Priority maps an escalation severity to a mail priority.
+func Priority(severity string) mail.Priority {
+	switch severity {
+	case config.SeverityCritical:
+		return mail.PriorityUrgent
+	case config.SeverityHigh:
+		return mail.PriorityHigh
+	case config.SeverityMedium:
+		return mail.PriorityNormal
+	default:
+		return mail.PriorityLow
+	}
+}
+
+// beadsFor returns the beads client for a town's escalation beads.
+var beadsFor = func(townRoot string) escalationBeads {
+	return beads.New(beads.ResolveBeadsDir(townRoot))
+}
+
+// escalationBeads updates escalation beads. *beads.Beads implements it.
+type escalationBeads interface {
+	AckEscalation(id, ackedBy string) error
+	CloseEscalation(id, closedBy, reason string) error
+}
+
+// loadConfig returns the town's escalation config, or the defaults if it
+// can't be read.
+func loadConfig(townRoot string) *config.EscalationConfig {
+	cfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return config.NewEscalationConfig()
+	}
+	return cfg
+}
+
+// load reads the store. A town without one has no escalations.
+func load(townRoot string) (map[string]*Record, error) {
+	data, err := os.ReadFile(StorePath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading escalations: %w", err)
+	}
+	store := map[string]*Record{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store); err != nil {
+			return nil, fmt.Errorf("parsing escalations: %w", err)
+		}
+	}
+	return store, nil
+}
+
+// update changes the store under its lock, writing it back unless fn fails.
+func update(townRoot string, fn func(store map[string]*Record) error) error {
+	path := StorePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating daemon dir: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("locking escalations: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("locking escalations: timed out")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	store, err := load(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(store); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, store)
+}
+
+// emit logs an escalation event. Events are best-effort: the store is the
+// record of truth.
+func emit(townRoot string, at time.Time, eventType, actor string, payload map[string]interface{}) {
+	_ = events.Append(townRoot, events.Event{
+		Timestamp:  at.Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: events.VisibilityFeed,
+	})
+}
+
+// generateID returns a new escalation ID.
+func generateID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("esc-%x", time.Now().UnixNano())
+	}
+	return "esc-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,159 @@
+package escalation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+type fakeBeads struct {
+	acked, closed []string
+}
+
+func (f *fakeBeads) AckEscalation(id, ackedBy string) error {
+	f.acked = append(f.acked, id)
+	return nil
+}
+
+func (f *fakeBeads) CloseEscalation(id, closedBy, reason string) error {
+	f.closed = append(f.closed, id)
+	return nil
+}
+
+func useFakeBeads(t *testing.T) *fakeBeads {
+	t.Helper()
+	fake := &fakeBeads{}
+	orig := beadsFor
+	beadsFor = func(string) escalationBeads { return fake }
+	t.Cleanup(func() { beadsFor = orig })
+	return fake
+}
+
+type fakeNotifier struct {
+	sent []*mail.Message
+}
+
+func (f *fakeNotifier) Send(msg *mail.Message) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestLifecycle(t *testing.T) {
+	townRoot := t.TempDir()
+	fake := useFakeBeads(t)
+
+	rec, err := Open(townRoot, Options{
+		ID: "hq-esc1", Bead: true, Severity: "high", Summary: "CI blocked",
+		OpenedBy: "gastown/crew/max", To: []string{"mayor/"},
+	})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if rec.Status != StatusOpen || rec.Deadline.Sub(rec.OpenedAt) != 4*time.Hour {
+		t.Errorf("opened = %+v, want open with a 4h deadline", rec)
+	}
+	if _, err := Open(townRoot, Options{ID: "hq-esc1", Severity: "high", Summary: "again"}); err == nil {
+		t.Error("Open with a duplicate ID succeeded")
+	}
+
+	if _, err := Ack(townRoot, "hq-esc1", "mayor"); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if _, err := Ack(townRoot, "hq-esc1", "mayor"); err == nil {
+		t.Error("second Ack succeeded")
+	}
+	rec, err = Close(townRoot, "hq-esc1", "mayor", "fixed")
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if rec.Status != StatusClosed || rec.AckedBy != "mayor" || rec.CloseReason != "fixed" || !rec.Deadline.IsZero() {
+		t.Errorf("closed = %+v", rec)
+	}
+	if len(fake.acked) != 1 || len(fake.closed) != 1 {
+		t.Errorf("bead acks/closes = %v/%v, want one each", fake.acked, fake.closed)
+	}
+
+	if _, err := Ack(townRoot, "missing", "mayor"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Ack(missing) = %v, want ErrNotFound", err)
+	}
+
+	records, err := events.ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("reading events: %v", err)
+	}
+	var types []string
+	for _, r := range records {
+		types = append(types, r.Type)
+	}
+	want := []string{events.TypeEscalationSent, events.TypeEscalationAcked, events.TypeEscalationClosed}
+	if len(types) != len(want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("events = %v, want %v", types, want)
+			break
+		}
+	}
+}
+
+func TestCheckSLA(t *testing.T) {
+	townRoot := t.TempDir()
+	useFakeBeads(t)
+
+	open, err := Open(townRoot, Options{Severity: "critical", Summary: "Refinery down", OpenedBy: "deacon", To: []string{"mayor/", "overseer"}})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	acked, err := Open(townRoot, Options{Severity: "low", Summary: "Flaky test", OpenedBy: "deacon", To: []string{"mayor/"}})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := Ack(townRoot, acked.ID, "mayor"); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+
+	n := &fakeNotifier{}
+	// Within the SLA nothing is due
+	got, err := CheckSLA(townRoot, "daemon", open.OpenedAt.Add(time.Hour), n)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("CheckSLA within SLA = %v, %v; want nothing", got, err)
+	}
+
+	// Past the deadline the open escalation's targets are reminded
+	first := open.OpenedAt.Add(5 * time.Hour)
+	got, err = CheckSLA(townRoot, "daemon", first, n)
+	if err != nil {
+		t.Fatalf("CheckSLA: %v", err)
+	}
+	if len(got) != 1 || got[0].Record.ID != open.ID || got[0].Record.Renotified != 1 {
+		t.Fatalf("renotified = %+v, want %s once", got, open.ID)
+	}
+	if len(n.sent) != 2 || n.sent[0].Priority != mail.PriorityUrgent || n.sent[1].To != "overseer" {
+		t.Errorf("sent = %+v, want urgent reminders to both targets", n.sent)
+	}
+
+	// The deadline moved out by the threshold
+	if got, _ := CheckSLA(townRoot, "daemon", first.Add(time.Hour), n); len(got) != 0 {
+		t.Errorf("reminded again before the new deadline: %+v", got)
+	}
+
+	// The second reminder is the last (max_reescalations defaults to 2)
+	second := first.Add(5 * time.Hour)
+	if got, _ := CheckSLA(townRoot, "daemon", second, n); len(got) != 1 {
+		t.Fatalf("second reminder = %+v", got)
+	}
+	if got, _ := CheckSLA(townRoot, "daemon", second.Add(24*time.Hour), n); len(got) != 0 {
+		t.Errorf("reminded past max_reescalations: %+v", got)
+	}
+	rec, err := Get(townRoot, open.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if rec.Status != StatusOpen || rec.Renotified != 2 || !rec.Deadline.IsZero() {
+		t.Errorf("record = %+v, want open, renotified twice, no deadline", rec)
+	}
+}
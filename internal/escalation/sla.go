@@ -0,0 +1,100 @@
+package escalation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Notifier delivers renotification mail. *mail.Router implements it.
+type Notifier interface {
+	Send(msg *mail.Message) error
+}
+
+// Renotification is one reminder sent by CheckSLA.
+type Renotification struct {
+	Record *Record
+	Failed []string // targets the reminder could not be sent to
+}
+
+// CheckSLA renotifies the targets of open escalations still unacknowledged
+// past their deadline, sending as from. Each reminder pushes the deadline
+// out by another stale_threshold, up to max_reescalations reminders; after
+// that the escalation stays open but is left alone.
+func CheckSLA(townRoot, from string, now time.Time, n Notifier) ([]Renotification, error) {
+	cfg := loadConfig(townRoot)
+	threshold := cfg.GetStaleThreshold()
+	maxReminders := cfg.GetMaxReescalations()
+
+	// Claim due escalations under the lock, then send outside it so slow
+	// mail delivery doesn't hold up acks
+	var due []*Record
+	err := update(townRoot, func(store map[string]*Record) error {
+		for _, rec := range store {
+			if rec.Status != StatusOpen || rec.Deadline.IsZero() || now.Before(rec.Deadline) {
+				continue
+			}
+			rec.Renotified++
+			rec.LastNotified = now
+			rec.Deadline = time.Time{}
+			if rec.Renotified < maxReminders {
+				rec.Deadline = now.Add(threshold)
+			}
+			copied := *rec
+			due = append(due, &copied)
+		}
+		return nil
+	})
+	if err != nil || len(due) == 0 {
+		return nil, err
+	}
+
+	sent := make([]Renotification, 0, len(due))
+	for _, rec := range due {
+		r := Renotification{Record: rec}
+		for _, to := range rec.To {
+			if err := n.Send(reminder(rec, from, to, maxReminders, now)); err != nil {
+				r.Failed = append(r.Failed, to)
+			}
+		}
+		sent = append(sent, r)
+
+		emit(townRoot, now, events.TypeEscalationSent, from, map[string]interface{}{
+			"escalation_id": rec.ID,
+			"renotified":    rec.Renotified,
+			"to":            strings.Join(rec.To, ","),
+		})
+	}
+	return sent, nil
+}
+
+// reminder is the mail renotifying to of an unacknowledged escalation.
+func reminder(rec *Record, from, to string, maxReminders int, now time.Time) *mail.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Escalation %s has been unacknowledged for %s.\n\n", rec.ID, now.Sub(rec.OpenedAt).Round(time.Minute))
+	fmt.Fprintf(&b, "Escalation ID: %s\n", rec.ID)
+	fmt.Fprintf(&b, "Severity: %s\n", rec.Severity)
+	fmt.Fprintf(&b, "From: %s\n", rec.OpenedBy)
+	if rec.Reason != "" {
+		fmt.Fprintf(&b, "\nReason:\n%s\n", rec.Reason)
+	}
+	if rec.Related != "" {
+		fmt.Fprintf(&b, "\nRelated: %s\n", rec.Related)
+	}
+	fmt.Fprintf(&b, "\n---\nReminder %d of %d.\n", rec.Renotified, maxReminders)
+	fmt.Fprintf(&b, "To acknowledge: gt escalation ack %s\n", rec.ID)
+	fmt.Fprintf(&b, "To close: gt escalation close %s --reason \"resolution\"", rec.ID)
+
+	return &mail.Message{
+		From:     from,
+		To:       to,
+		Subject:  fmt.Sprintf("[%s] Unacknowledged: %s", strings.ToUpper(rec.Severity), rec.Summary),
+		Body:     b.String(),
+		Type:     mail.TypeTask,
+		Priority: Priority(rec.Severity),
+		ThreadID: "escalation-" + rec.ID,
+	}
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// AnnotationsFile is the name of the human annotations log.
+// Annotations live beside the raw events log rather than inside it so the
+// audit stream is never rewritten.
+const AnnotationsFile = ".events.annotations.jsonl"
+
+// ErrEventNotFound indicates no event exists with the requested ID.
+var ErrEventNotFound = errors.New("event not found")
+
+// Annotation is a human note attached to an event, used to add context the
+// automatic classification cannot infer (e.g., "this was the root cause").
+type Annotation struct {
+	Seq       int    `json:"seq"`
+	Shard     string `json:"shard,omitempty"`
+	Timestamp string `json:"ts"`
+	Author    string `json:"author,omitempty"`
+	Note      string `json:"note"`
+}
+
+// Annotate attaches a note to the event with the given sequence number in
+// the given shard ("" for the town-wide log).
+// Returns ErrEventNotFound if the sequence number is not in that log.
+func Annotate(townRoot, shard string, seq int, note, author string) (*Annotation, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, fmt.Errorf("annotation note is empty")
+	}
+
+	records, err := readRecords(townRoot, LogPath(townRoot, shard))
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, r := range records {
+		if r.Seq == seq {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, EventID(shard, seq))
+	}
+
+	ann := &Annotation{
+		Seq:       seq,
+		Shard:     shard,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Author:    author,
+		Note:      note,
+	}
+	data, err := json.Marshal(ann)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling annotation: %w", err)
+	}
+	data = append(data, '\n')
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := appendLine(filepath.Join(townRoot, AnnotationsFile), data, loadConfig(townRoot).Fsync == config.EventFsyncAlways); err != nil {
+		return nil, fmt.Errorf("writing annotation: %w", err)
+	}
+
+	return ann, nil
+}
+
+// LoadAnnotations reads all annotations, keyed by event ID (see EventID).
+// Returns an empty map if no annotations have been written.
+func LoadAnnotations(townRoot string) (map[string][]Annotation, error) {
+	result := make(map[string][]Annotation)
+
+	f, err := os.Open(filepath.Join(townRoot, AnnotationsFile)) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("opening annotations file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ann Annotation
+		if err := json.Unmarshal(scanner.Bytes(), &ann); err != nil {
+			continue
+		}
+		id := EventID(ann.Shard, ann.Seq)
+		result[id] = append(result[id], ann)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading annotations file: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeEventsFile(t *testing.T, townRoot string, lines ...string) {
+	t.Helper()
+	var data []byte
+	for _, line := range lines {
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, EventsFile), data, 0644); err != nil {
+		t.Fatalf("writing events file: %v", err)
+	}
+}
+
+func TestReadAll_SeqMatchesLineNumber(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor"}`,
+		`not json`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"done","actor":"gastown/polecats/Toast"}`,
+	)
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Seq != 1 || records[0].Type != "sling" {
+		t.Errorf("records[0] = seq %d type %q, want seq 1 type sling", records[0].Seq, records[0].Type)
+	}
+	if records[1].Seq != 3 || records[1].Type != "done" {
+		t.Errorf("records[1] = seq %d type %q, want seq 3 type done", records[1].Seq, records[1].Type)
+	}
+}
+
+func TestReadAll_MissingLog(t *testing.T) {
+	records, err := ReadAll(t.TempDir())
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("got %d records, want 0", len(records))
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"merge_failed","actor":"gastown/refinery"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"merged","actor":"gastown/refinery"}`,
+	)
+
+	if _, err := Annotate(townRoot, "", 1, "this was the root cause", "overseer"); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+	if _, err := Annotate(townRoot, "", 1, "  fixed by gt-42  ", ""); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if got := len(records[0].Annotations); got != 2 {
+		t.Fatalf("event 1 has %d annotations, want 2", got)
+	}
+	if records[0].Annotations[0].Note != "this was the root cause" || records[0].Annotations[0].Author != "overseer" {
+		t.Errorf("first annotation = %+v", records[0].Annotations[0])
+	}
+	if records[0].Annotations[1].Note != "fixed by gt-42" {
+		t.Errorf("second annotation note = %q, want trimmed note", records[0].Annotations[1].Note)
+	}
+	if len(records[1].Annotations) != 0 {
+		t.Errorf("event 2 has %d annotations, want 0", len(records[1].Annotations))
+	}
+}
+
+func TestAnnotate_Errors(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot, `{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor"}`)
+
+	if _, err := Annotate(townRoot, "", 5, "note", ""); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Annotate unknown seq: err = %v, want ErrEventNotFound", err)
+	}
+	if _, err := Annotate(townRoot, "", 1, "   ", ""); err == nil {
+		t.Error("Annotate with empty note should fail")
+	}
+}
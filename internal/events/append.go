@@ -0,0 +1,114 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// lockTimeout bounds how long a writer waits for another process to finish
+// with a log before giving up.
+const lockTimeout = 5 * time.Second
+
+// lockLog takes the advisory lock guarding a log file (<path>.lock).
+// Appends and rewrites of the same file hold it, so they never overlap.
+func lockLog(path string) (*flock.Flock, error) {
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", filepath.Base(path), err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("locking %s: timed out", filepath.Base(path))
+	}
+	return lock, nil
+}
+
+// appendLine appends one JSONL record to path so that concurrent gt
+// processes and crashes can't interleave or tear lines:
+//
+//   - an advisory lock (<path>.lock) serializes writers across processes;
+//   - a torn last line left by a crashed writer is terminated first, so the
+//     new record starts on its own line instead of merging into garbage;
+//   - the record is written with a single O_APPEND write, and a failed or
+//     short write is truncated back so no partial line is left behind;
+//   - with sync set, the file is fsynced before the lock is released.
+//
+// data must be a single line ending in '\n'.
+func appendLine(path string, data []byte, sync bool) error {
+	return appendLineOnce(path, data, sync, nil)
+}
+
+// appendLineOnce is appendLine, except that nothing is written if marker
+// is set and already appears in the last dedupeWindow bytes of the file.
+func appendLineOnce(path string, data []byte, sync bool, marker []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating events directory: %w", err)
+	}
+
+	lock, err := lockLog(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", filepath.Base(path), err)
+	}
+	size := info.Size()
+
+	if len(marker) > 0 && size > 0 {
+		start := size - dedupeWindow
+		if start < 0 {
+			start = 0
+		}
+		tail := make([]byte, size-start)
+		if _, err := f.ReadAt(tail, start); err != nil && err != io.EOF {
+			return fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+		}
+		if bytes.Contains(tail, marker) {
+			return nil // already written
+		}
+	}
+
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, size-1); err != nil && err != io.EOF {
+			return fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+		}
+		if last[0] != '\n' {
+			data = append([]byte{'\n'}, data...)
+		}
+	}
+
+	if n, err := f.Write(data); err != nil || n != len(data) {
+		_ = f.Truncate(size)
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+
+	if sync {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("syncing %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	return nil
+}
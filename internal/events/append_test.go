@@ -0,0 +1,77 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAppendLine_ConcurrentWritersDoNotInterleave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+	payload := make([]byte, 8*1024)
+	for i := range payload {
+		payload[i] = 'x'
+	}
+
+	const writers, perWriter = 8, 25
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				data, _ := json.Marshal(Event{Type: "test", Actor: fmt.Sprintf("w%d-%d", w, i), Payload: map[string]interface{}{"pad": string(payload)}})
+				if err := appendLine(path, append(data, '\n'), false); err != nil {
+					t.Errorf("appendLine: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	records, err := readRecords(filepath.Dir(path), path)
+	if err != nil {
+		t.Fatalf("readRecords: %v", err)
+	}
+	if len(records) != writers*perWriter {
+		t.Errorf("got %d valid records, want %d", len(records), writers*perWriter)
+	}
+}
+
+func TestAppendLine_RepairsTornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+	torn := `{"ts":"2026-01-01T00:00:00Z","type":"sling"` // crashed mid-write
+	if err := os.WriteFile(path, []byte(torn), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appendLine(path, []byte(`{"ts":"2026-01-01T00:01:00Z","type":"done"}`+"\n"), true); err != nil {
+		t.Fatalf("appendLine: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 2 || lines[0] != torn {
+		t.Fatalf("lines = %q, want torn line then new record", lines)
+	}
+
+	records, err := readRecords(filepath.Dir(path), path)
+	if err != nil {
+		t.Fatalf("readRecords: %v", err)
+	}
+	if len(records) != 1 || records[0].Seq != 2 || records[0].Type != "done" {
+		t.Errorf("records = %+v, want only the new record at seq 2", records)
+	}
+}
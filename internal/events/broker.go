@@ -0,0 +1,809 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// The broker reads the town's events logs once and pushes new events to
+// subscribers over a unix socket, so readers don't each scan the logs.
+//
+// Protocol: newline-delimited JSON messages (BrokerMessage). A client opens
+// with a subscribe message and gets subscribed or error back, then event
+// messages; it acks each event it has handled. A named subscriber's acked
+// offsets are saved per log, and a subscriber that reconnects resumes after
+// its last ack, so every event is delivered at least once. Events of one
+// log arrive in order; logs are interleaved as they are read.
+
+// Subscription start points.
+const (
+	FromSaved = "saved" // resume after the last ack, or FromNow for a new subscriber
+	FromNow   = "now"   // only events written from now on
+	FromStart = "start" // every event in the logs
+)
+
+// Broker message ops.
+const (
+	OpSubscribe  = "subscribe"
+	OpSubscribed = "subscribed"
+	OpEvent      = "event"
+	OpAck        = "ack"
+	OpError      = "error"
+)
+
+// brokerQueue is how many events may wait for a subscriber. A subscriber
+// that falls further behind is disconnected, and catches up from its
+// offsets when it reconnects.
+const brokerQueue = 1024
+
+// ErrNoBroker means the town's event broker isn't running.
+var ErrNoBroker = errors.New("event broker not running")
+
+// subscriberNameRe matches durable subscriber names.
+var subscriberNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// BrokerSocket returns the socket a town's event broker listens on.
+func BrokerSocket(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "events.sock")
+}
+
+// SubscriptionsDir returns where named subscribers' offsets are kept.
+func SubscriptionsDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "event-subscriptions")
+}
+
+func subscriptionPath(townRoot, name string) string {
+	return filepath.Join(SubscriptionsDir(townRoot), name+".json")
+}
+
+// SubscribeRequest opens a subscription.
+type SubscribeRequest struct {
+	// Name makes the subscription durable: its offsets are saved and a
+	// later subscription by the same name resumes from them. Empty for a
+	// one-off subscription.
+	Name   string `json:"name,omitempty"`
+	Filter Filter `json:"filter"`
+	From   string `json:"from,omitempty"` // FromSaved (default), FromNow or FromStart
+}
+
+// BrokerMessage is one line of the broker protocol.
+type BrokerMessage struct {
+	Op        string            `json:"op"`
+	Subscribe *SubscribeRequest `json:"subscribe,omitempty"` // OpSubscribe
+	Record    *Record           `json:"record,omitempty"`    // OpEvent
+	ID        string            `json:"id,omitempty"`        // OpAck: the event's ID
+	Error     string            `json:"error,omitempty"`     // OpError
+}
+
+// Offset is how far a subscriber has read one log: Lines lines of the
+// log's Generation (see RemapOffset).
+type Offset struct {
+	Generation int `json:"generation"`
+	Lines      int `json:"lines"`
+}
+
+// SubscriberState is a named subscriber's saved progress.
+type SubscriberState struct {
+	Name    string            `json:"name"`
+	Offsets map[string]Offset `json:"offsets"` // by shard, "" for the town-wide log
+	Updated time.Time         `json:"updated"`
+}
+
+// Subscribers returns the saved state of the town's named subscribers.
+func Subscribers(townRoot string) ([]SubscriberState, error) {
+	paths, err := filepath.Glob(filepath.Join(SubscriptionsDir(townRoot), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var states []SubscriberState
+	for _, path := range paths {
+		state, err := loadSubscriber(townRoot, strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if state != nil {
+			states = append(states, *state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states, nil
+}
+
+func loadSubscriber(townRoot, name string) (*SubscriberState, error) {
+	data, err := os.ReadFile(subscriptionPath(townRoot, name)) //nolint:gosec // G304: path is constructed from town root
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state SubscriberState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing subscriber %s: %w", name, err)
+	}
+	return &state, nil
+}
+
+// Broker serves a town's events to subscribers. Only one runs per town.
+type Broker struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	rules    *SignificanceRules
+	dec      *Decoder
+
+	mu       sync.Mutex
+	listener net.Listener
+	logs     map[string]*brokerLog
+	subs     map[*subscriber]bool
+	taps     map[*Tap]bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// brokerLog is one log the broker follows.
+type brokerLog struct {
+	shard   string
+	file    *os.File
+	reader  *bufio.Reader
+	pos     int64 // bytes of complete lines read
+	lines   int   // complete lines read
+	partial []byte
+	gen     int
+}
+
+// subscriber is a connected client.
+type subscriber struct {
+	name      string
+	filter    Filter
+	conn      net.Conn
+	out       chan BrokerMessage
+	acked     map[string]Offset // by shard
+	sent      map[string]int    // lines of each log considered
+	delivered map[string]int    // last line of each log delivered
+	catching  bool              // still being sent missed events
+	lagging   bool              // disconnected for falling behind
+	dirty     bool              // offsets changed since saved
+}
+
+// NewBroker creates a broker for a town.
+func NewBroker(townRoot string, logger func(format string, args ...interface{})) *Broker {
+	rules, err := LoadSignificanceRules(townRoot)
+	if err != nil {
+		logger("Warning: %v; using default significance", err)
+		rules = DefaultSignificanceRules()
+	}
+	return &Broker{
+		townRoot: townRoot,
+		logger:   logger,
+		rules:    rules,
+		dec:      NewDecoder(townRoot),
+		logs:     make(map[string]*brokerLog),
+		subs:     make(map[*subscriber]bool),
+		taps:     make(map[*Tap]bool),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start listens on the town's broker socket and begins following the logs
+// from their current ends.
+func (b *Broker) Start() error {
+	sock := BrokerSocket(b.townRoot)
+	if conn, err := net.DialTimeout("unix", sock, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("event broker already listening on %s", sock)
+	}
+	_ = os.Remove(sock) // left by a broker that died
+	if err := os.MkdirAll(filepath.Dir(sock), 0755); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	if err := b.openLog(""); err != nil {
+		b.mu.Unlock()
+		return fmt.Errorf("opening events file: %w", err)
+	}
+	for _, shard := range Shards(b.townRoot) {
+		if err := b.openLog(shard); err != nil {
+			b.logger("Warning: opening events shard %s: %v", shard, err)
+		}
+	}
+	b.mu.Unlock()
+
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", sock, err)
+	}
+	b.listener = listener
+
+	b.wg.Add(2)
+	go b.accept()
+	go b.run()
+	return nil
+}
+
+// Stop closes the socket and every subscription, saving offsets.
+func (b *Broker) Stop() {
+	close(b.done)
+	if b.listener != nil {
+		_ = b.listener.Close()
+	}
+	b.mu.Lock()
+	for s := range b.subs {
+		_ = s.conn.Close()
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, l := range b.logs {
+		_ = l.file.Close()
+	}
+}
+
+// Tap returns an in-process feed of every new log line, for followers that
+// run in the broker's own process. It has Tailer's Poll and Close.
+func (b *Broker) Tap() *Tap {
+	t := &Tap{broker: b}
+	b.mu.Lock()
+	b.taps[t] = true
+	b.mu.Unlock()
+	return t
+}
+
+// Tap is an in-process feed of the broker's log lines.
+type Tap struct {
+	broker *Broker
+	mu     sync.Mutex
+	lines  []string
+}
+
+// Poll returns the lines read since the last call, as Tailer.Poll does.
+func (t *Tap) Poll() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines := t.lines
+	t.lines = nil
+	return lines
+}
+
+// Close stops the feed.
+func (t *Tap) Close() error {
+	t.broker.mu.Lock()
+	delete(t.broker.taps, t)
+	t.broker.mu.Unlock()
+	return nil
+}
+
+// openLog starts following a log from its end. Callers hold b.mu.
+func (b *Broker) openLog(shard string) error {
+	path := LogPath(b.townRoot, shard)
+	flags := os.O_RDONLY
+	if shard == "" {
+		flags |= os.O_CREATE
+	}
+	f, err := os.OpenFile(path, flags, 0644) //nolint:gosec // G302/G304: events file is non-sensitive operational data, path from town root
+	if err != nil {
+		return err
+	}
+	gen, err := Generation(b.townRoot, shard)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l := &brokerLog{shard: shard, file: f, reader: bufio.NewReader(f), gen: gen}
+	l.read(nil) // skip to the end, counting lines
+	b.logs[shard] = l
+	return nil
+}
+
+// read reads the log's new complete lines, calling fn (if set) with each
+// and its line number.
+func (l *brokerLog) read(fn func(line []byte, seq int)) {
+	for {
+		chunk, err := l.reader.ReadBytes('\n')
+		if err != nil {
+			l.partial = append(l.partial, chunk...)
+			return
+		}
+		line := chunk
+		if len(l.partial) > 0 {
+			line = append(l.partial, chunk...)
+			l.partial = nil
+		}
+		l.lines++
+		l.pos += int64(len(line))
+		if fn != nil {
+			fn(line, l.lines)
+		}
+	}
+}
+
+// rewritten reports whether the log was replaced by prune, compact or
+// rotate since it was opened.
+func (l *brokerLog) rewritten(path string) bool {
+	onDisk, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	open, err := l.file.Stat()
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(onDisk, open) || onDisk.Size() < l.pos
+}
+
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			select {
+			case <-b.done:
+				return
+			default:
+			}
+			b.logger("Warning: event broker accept: %v", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		b.wg.Add(1)
+		go b.serve(conn)
+	}
+}
+
+// run reads new log lines every 100ms and pushes them to subscribers.
+func (b *Broker) run() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			b.mu.Lock()
+			b.saveAll()
+			b.mu.Unlock()
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			b.poll()
+			b.saveAll()
+			b.mu.Unlock()
+		}
+	}
+}
+
+// poll reads and delivers new lines from every log. Callers hold b.mu.
+func (b *Broker) poll() {
+	for _, shard := range Shards(b.townRoot) {
+		if _, ok := b.logs[shard]; !ok {
+			// A new shard: everything in it is new
+			if err := b.openLog(shard); err == nil {
+				b.logs[shard].reset()
+			}
+		}
+	}
+
+	for shard, l := range b.logs {
+		if l.rewritten(LogPath(b.townRoot, shard)) {
+			b.reopen(l)
+			l = b.logs[shard]
+			if l == nil {
+				continue
+			}
+		}
+		l.read(func(line []byte, seq int) {
+			event, err := b.dec.Parse(line)
+			if err != nil {
+				return
+			}
+			b.feedTaps(line, event)
+			r := Record{Seq: seq, Shard: shard, Event: event}
+			for s := range b.subs {
+				if !s.catching {
+					b.deliver(s, r)
+				}
+			}
+		})
+		for s := range b.subs {
+			if !s.catching {
+				s.sent[shard] = l.lines
+			}
+		}
+	}
+	for s := range b.subs {
+		s.skipUnmatched(b.logs)
+	}
+}
+
+// reset rewinds a log to its start, so its existing lines are read as new.
+func (l *brokerLog) reset() {
+	_, _ = l.file.Seek(0, io.SeekStart)
+	l.reader.Reset(l.file)
+	l.pos, l.lines, l.partial = 0, 0, nil
+}
+
+// reopen follows a rewritten log from its new end, translating subscriber
+// offsets into its new generation. Lines appended between the rewrite and
+// now are delivered when subscribers next reconnect. Callers hold b.mu.
+func (b *Broker) reopen(l *brokerLog) {
+	oldGen := l.gen
+	_ = l.file.Close()
+	delete(b.logs, l.shard)
+	if err := b.openLog(l.shard); err != nil {
+		b.logger("Warning: reopening events log %s: %v", LogPath(b.townRoot, l.shard), err)
+		return
+	}
+	fresh := b.logs[l.shard]
+	for s := range b.subs {
+		if off, ok := s.acked[l.shard]; ok && off.Generation == oldGen {
+			if gen, lines, err := RemapOffset(b.townRoot, l.shard, off.Generation, off.Lines); err == nil {
+				s.acked[l.shard] = Offset{Generation: gen, Lines: lines}
+				s.dirty = true
+			}
+		}
+		s.sent[l.shard] = fresh.lines
+		s.delivered[l.shard] = 0
+	}
+}
+
+// feedTaps hands a line to every tap, decrypted. Callers hold b.mu.
+func (b *Broker) feedTaps(line []byte, event Event) {
+	if len(b.taps) == 0 {
+		return
+	}
+	text := string(line)
+	if strings.Contains(text, `"enc"`) {
+		if data, err := json.Marshal(event); err == nil {
+			text = string(data) + "\n"
+		}
+	}
+	for t := range b.taps {
+		t.mu.Lock()
+		t.lines = append(t.lines, text)
+		t.mu.Unlock()
+	}
+}
+
+// deliver queues r for s if it matches s's filter. A subscriber whose queue
+// is full is disconnected; what it missed is sent when it reconnects.
+// Callers hold b.mu.
+func (b *Broker) deliver(s *subscriber, r Record) {
+	if s.lagging || !s.filter.Match(r.Event) {
+		return
+	}
+	select {
+	case s.out <- BrokerMessage{Op: OpEvent, Record: &r}:
+		s.delivered[r.Shard] = r.Seq
+	default:
+		b.logger("Warning: event subscriber %q fell behind; disconnecting", s.name)
+		s.lagging = true
+		_ = s.conn.Close()
+	}
+}
+
+// skipUnmatched moves s's offsets past events it was never sent, once
+// everything it was sent is acked, so a reconnect doesn't rescan them.
+func (s *subscriber) skipUnmatched(logs map[string]*brokerLog) {
+	if s.catching || s.lagging {
+		return
+	}
+	for shard, l := range logs {
+		off := s.acked[shard]
+		if s.delivered[shard] <= off.Lines && s.sent[shard] > off.Lines {
+			s.acked[shard] = Offset{Generation: l.gen, Lines: s.sent[shard]}
+			s.dirty = true
+		}
+	}
+}
+
+// saveAll saves the offsets of named subscribers that changed. Callers
+// hold b.mu.
+func (b *Broker) saveAll() {
+	for s := range b.subs {
+		b.save(s)
+	}
+}
+
+func (b *Broker) save(s *subscriber) {
+	if s.name == "" || !s.dirty {
+		return
+	}
+	state := SubscriberState{Name: s.name, Offsets: s.acked, Updated: time.Now().UTC()}
+	if err := os.MkdirAll(SubscriptionsDir(b.townRoot), 0755); err == nil {
+		err = util.AtomicWriteJSON(subscriptionPath(b.townRoot, s.name), state)
+		if err == nil {
+			s.dirty = false
+			return
+		}
+	}
+	b.logger("Warning: saving event subscriber %s offsets failed", s.name)
+}
+
+// serve runs one client connection: the events it missed are written
+// straight from the logs, then new events from its queue.
+func (b *Broker) serve(conn net.Conn) {
+	defer b.wg.Done()
+	defer conn.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	var hello BrokerMessage
+	if err := dec.Decode(&hello); err != nil {
+		return
+	}
+	if hello.Op != OpSubscribe || hello.Subscribe == nil {
+		_ = enc.Encode(BrokerMessage{Op: OpError, Error: "expected subscribe"})
+		return
+	}
+	s, err := b.subscribe(conn, *hello.Subscribe)
+	if err != nil {
+		_ = enc.Encode(BrokerMessage{Op: OpError, Error: err.Error()})
+		return
+	}
+	write := func(msg BrokerMessage) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return enc.Encode(msg)
+	}
+
+	// Acks are read while events are written, so neither side blocks
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			var msg BrokerMessage
+			if err := dec.Decode(&msg); err != nil {
+				break
+			}
+			if msg.Op == OpAck {
+				b.ack(s, msg.ID)
+			}
+		}
+		b.mu.Lock()
+		delete(b.subs, s)
+		b.save(s)
+		close(s.out)
+		b.mu.Unlock()
+	}()
+
+	if err := write(BrokerMessage{Op: OpSubscribed}); err == nil {
+		if err := b.catchUp(s, write); err != nil {
+			b.logger("Warning: catching up event subscriber %q: %v", s.name, err)
+			_ = conn.Close()
+		}
+	}
+	for msg := range s.out {
+		if err := write(msg); err != nil {
+			_ = conn.Close()
+		}
+	}
+	<-readerDone
+}
+
+// subscribe registers a subscriber, starting where its request says. It
+// receives no queued events until it has caught up.
+func (b *Broker) subscribe(conn net.Conn, req SubscribeRequest) (*subscriber, error) {
+	if req.Name != "" && !subscriberNameRe.MatchString(req.Name) {
+		return nil, fmt.Errorf("invalid subscriber name %q (want letters, digits, '.', '_' or '-')", req.Name)
+	}
+	if err := req.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	req.Filter.Rules = b.rules
+	from := req.From
+	if from == "" {
+		from = FromSaved
+	}
+
+	var saved *SubscriberState
+	switch from {
+	case FromSaved:
+		if req.Name != "" {
+			var err error
+			if saved, err = loadSubscriber(b.townRoot, req.Name); err != nil {
+				return nil, err
+			}
+		}
+	case FromNow, FromStart:
+	default:
+		return nil, fmt.Errorf("invalid start %q (want %s, %s or %s)", from, FromSaved, FromNow, FromStart)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		if req.Name != "" && s.name == req.Name {
+			return nil, fmt.Errorf("subscriber %q is already connected", req.Name)
+		}
+	}
+
+	s := &subscriber{
+		name:      req.Name,
+		filter:    req.Filter,
+		conn:      conn,
+		out:       make(chan BrokerMessage, brokerQueue),
+		acked:     make(map[string]Offset),
+		sent:      make(map[string]int),
+		delivered: make(map[string]int),
+		catching:  true,
+		dirty:     true,
+	}
+	for shard, l := range b.logs {
+		switch {
+		case saved != nil:
+			off, ok := saved.Offsets[shard]
+			if ok && off.Generation != l.gen {
+				gen, lines, err := RemapOffset(b.townRoot, shard, off.Generation, off.Lines)
+				if err != nil {
+					return nil, err
+				}
+				off = Offset{Generation: gen, Lines: lines}
+			}
+			// A shard missing from the saved offsets is new since, and
+			// read from its start
+			off.Generation = l.gen
+			s.acked[shard] = off
+		case from == FromStart:
+			s.acked[shard] = Offset{Generation: l.gen}
+		default:
+			s.acked[shard] = Offset{Generation: l.gen, Lines: l.lines}
+		}
+		s.sent[shard] = s.acked[shard].Lines
+	}
+	b.subs[s] = true
+	return s, nil
+}
+
+// catchUp writes the events s missed, reading the logs up to where the
+// broker has read them, until s is level with the broker and can take
+// events from its queue.
+func (b *Broker) catchUp(s *subscriber, write func(BrokerMessage) error) error {
+	for {
+		b.mu.Lock()
+		behind := make(map[string]*brokerLog)
+		for shard, l := range b.logs {
+			if s.sent[shard] < l.lines {
+				behind[shard] = l
+			}
+		}
+		if len(behind) == 0 {
+			s.catching = false
+			b.mu.Unlock()
+			return nil
+		}
+		b.mu.Unlock()
+
+		for shard, l := range behind {
+			b.mu.Lock()
+			from, to := s.sent[shard], l.lines
+			b.mu.Unlock()
+			records, _, err := readRecordsFrom(b.townRoot, LogPath(b.townRoot, shard), from)
+			if err != nil && !errors.Is(err, errLogShrunk) {
+				return err
+			}
+			for _, r := range records {
+				if r.Seq > to {
+					break
+				}
+				if !s.filter.Match(r.Event) {
+					continue
+				}
+				r.Shard = shard
+				b.mu.Lock()
+				s.delivered[shard] = r.Seq
+				b.mu.Unlock()
+				if err := write(BrokerMessage{Op: OpEvent, Record: &r}); err != nil {
+					return err
+				}
+			}
+			b.mu.Lock()
+			if b.logs[shard] == l { // not rewritten meanwhile
+				s.sent[shard] = to
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// ack records that s has handled the event with the given ID. Acks are
+// cumulative per log.
+func (b *Broker) ack(s *subscriber, id string) {
+	shard, seq, err := ParseEventID(id)
+	if err != nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	l, ok := b.logs[shard]
+	if !ok || seq <= s.acked[shard].Lines {
+		return
+	}
+	if seq >= s.delivered[shard] && seq < s.sent[shard] && !s.lagging {
+		seq = s.sent[shard] // what followed was filtered out
+	}
+	s.acked[shard] = Offset{Generation: l.gen, Lines: seq}
+	s.dirty = true
+}
+
+// Subscription is a client's connection to the event broker.
+type Subscription struct {
+	conn net.Conn
+	dec  *json.Decoder
+	mu   sync.Mutex // serializes writes
+	enc  *json.Encoder
+}
+
+// Subscribe connects to the town's event broker. Returns an error wrapping
+// ErrNoBroker if none is running, so callers can read the logs themselves.
+func Subscribe(townRoot string, req SubscribeRequest) (*Subscription, error) {
+	conn, err := net.DialTimeout("unix", BrokerSocket(townRoot), time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoBroker, err)
+	}
+	s := &Subscription{conn: conn, dec: json.NewDecoder(bufio.NewReader(conn)), enc: json.NewEncoder(conn)}
+	if err := s.enc.Encode(BrokerMessage{Op: OpSubscribe, Subscribe: &req}); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	var reply BrokerMessage
+	if err := s.dec.Decode(&reply); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("subscribing: %w", err)
+	}
+	if reply.Op != OpSubscribed {
+		_ = conn.Close()
+		return nil, fmt.Errorf("subscribing: %s", reply.Error)
+	}
+	return s, nil
+}
+
+// Next waits for the next event. It returns io.EOF once the broker has
+// closed the subscription.
+func (s *Subscription) Next() (Record, error) {
+	for {
+		var msg BrokerMessage
+		if err := s.dec.Decode(&msg); err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return Record{}, io.EOF
+			}
+			return Record{}, err
+		}
+		switch msg.Op {
+		case OpEvent:
+			if msg.Record != nil {
+				return *msg.Record, nil
+			}
+		case OpError:
+			return Record{}, errors.New(msg.Error)
+		}
+	}
+}
+
+// Ack tells the broker r has been handled, so a named subscription won't
+// be sent it again.
+func (s *Subscription) Ack(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(BrokerMessage{Op: OpAck, ID: r.ID()})
+}
+
+// Close ends the subscription. Unacked events are sent again the next time
+// a named subscriber connects.
+func (s *Subscription) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBroker_DeliversAtLeastOnce(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor"}`,
+		`{"ts":"2026-01-01T01:00:00Z","type":"done","actor":"gastown/polecats/Toast"}`)
+
+	if _, err := Subscribe(townRoot, SubscribeRequest{}); !errors.Is(err, ErrNoBroker) {
+		t.Fatalf("Subscribe without a broker = %v, want ErrNoBroker", err)
+	}
+
+	b := NewBroker(townRoot, t.Logf)
+	if err := b.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer b.Stop()
+	if err := NewBroker(townRoot, t.Logf).Start(); err == nil {
+		t.Error("second broker started on the same town")
+	}
+	tap := b.Tap()
+	defer tap.Close()
+
+	done := SubscribeRequest{Name: "narrator", Filter: Filter{Type: TypeDone}, From: FromStart}
+	sub, err := Subscribe(townRoot, done)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	r := next(t, sub)
+	if r.Seq != 2 || r.Type != TypeDone {
+		t.Fatalf("caught up with %+v, want the done event at line 2", r)
+	}
+	if err := sub.Ack(r); err != nil {
+		t.Fatal(err)
+	}
+
+	appendEvent := func(line string) {
+		t.Helper()
+		if err := appendLine(filepath.Join(townRoot, EventsFile), []byte(line+"\n"), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendEvent(`{"ts":"2026-01-01T02:00:00Z","type":"sling","actor":"mayor"}`)
+	appendEvent(`{"ts":"2026-01-01T03:00:00Z","type":"done","actor":"gastown/polecats/Nux"}`)
+	if r := next(t, sub); r.Seq != 4 {
+		t.Fatalf("pushed %+v, want the done event at line 4", r)
+	}
+	_ = sub.Close() // without acking line 4
+
+	// The subscriber comes back and is sent line 4 again
+	var again *Subscription
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		again, err = Subscribe(townRoot, SubscribeRequest{Name: "narrator", Filter: Filter{Type: TypeDone}})
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond) // the broker hasn't seen the close yet
+	}
+	if err != nil {
+		t.Fatalf("resubscribing: %v", err)
+	}
+	defer again.Close()
+	if r := next(t, again); r.Seq != 4 {
+		t.Fatalf("after reconnect got %+v, want line 4 again", r)
+	}
+
+	if lines := tap.Poll(); len(lines) != 2 {
+		t.Errorf("tap got %d lines, want the 2 appended", len(lines))
+	}
+
+	states, err := Subscribers(townRoot)
+	if err != nil || len(states) != 1 || states[0].Offsets[""].Lines != 2 {
+		t.Errorf("Subscribers = %+v, %v; want narrator acked through line 2", states, err)
+	}
+}
+
+// next reads a subscription's next event, failing the test after a timeout.
+func next(t *testing.T, s *Subscription) Record {
+	t.Helper()
+	type result struct {
+		r   Record
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		r, err := s.Next()
+		ch <- result{r, err}
+	}()
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			t.Fatalf("Next: %v", res.err)
+		}
+		return res.r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return Record{}
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+// WorkKey is the payload key that ties an event to a work item when its
+// payload doesn't otherwise name one, such as the spawn of the polecat
+// that will do the work.
+const WorkKey = "work"
+
+// WithWork returns payload with its work ID set. An empty workID leaves
+// payload as it is.
+func WithWork(payload map[string]interface{}, workID string) map[string]interface{} {
+	if workID == "" {
+		return payload
+	}
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	payload[WorkKey] = workID
+	return payload
+}
+
+// WorkID returns the ID of the work item an event belongs to, the bead that
+// is slung, hooked, done and merged, so the events of one piece of work
+// share it. It is the payload's WorkKey if set, else the merge payload's
+// source_issue, else its bead; "" if the event isn't about a work item.
+func WorkID(e Event) string {
+	for _, key := range []string{WorkKey, "source_issue", "bead"} {
+		if id, ok := e.Payload[key].(string); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// Chain returns the lifecycle of a work item from the town's logs, oldest
+// first (see ChainOf).
+func Chain(townRoot, workID string) ([]Record, error) {
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return ChainOf(records, workID), nil
+}
+
+// ChainOf picks the lifecycle of a work item out of records, which are in
+// timestamp order: the records Works assigns to workID.
+func ChainOf(records []Record, workID string) []Record {
+	if workID == "" {
+		return nil
+	}
+	var chain []Record
+	for i, work := range Works(records) {
+		if work == workID {
+			chain = append(chain, records[i])
+		}
+	}
+	return chain
+}
+
+// Works returns the work ID of each of records, by index. Records with no
+// WorkID of their own take the work of a branch or merge request they name,
+// as learned from the rest of records. That links merge events written
+// without a source issue to the done event that submitted the branch.
+func Works(records []Record) []string {
+	works := make([]string, len(records))
+	branches := make(map[string]string)
+	mrs := make(map[string]string)
+	link := func(e Event, work string) {
+		if b, ok := e.Payload["branch"].(string); ok && b != "" && branches[b] == "" {
+			branches[b] = work
+		}
+		if mr, ok := e.Payload["mr"].(string); ok && mr != "" && mrs[mr] == "" {
+			mrs[mr] = work
+		}
+	}
+	for i, r := range records {
+		if works[i] = WorkID(r.Event); works[i] != "" {
+			link(r.Event, works[i])
+		}
+	}
+	// Each linked record can name a further branch or merge request, so
+	// repeat until no more records are assigned
+	for grew := true; grew; {
+		grew = false
+		for i, r := range records {
+			if works[i] != "" {
+				continue
+			}
+			b, _ := r.Payload["branch"].(string)
+			mr, _ := r.Payload["mr"].(string)
+			work := branches[b]
+			if work == "" {
+				work = mrs[mr]
+			}
+			if work != "" {
+				works[i] = work
+				link(r.Event, work)
+				grew = true
+			}
+		}
+	}
+	return works
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	townRoot := t.TempDir()
+	branch := "polecat/toast/gt-1"
+	log := []Event{
+		{Type: TypeSling, Actor: "mayor", Payload: SlingPayload("gt-1", "gastown/toast")},
+		{Type: TypeSpawn, Actor: "gt", Payload: WithWork(SpawnPayload("gastown", "toast"), "gt-1")},
+		{Type: TypeSling, Actor: "mayor", Payload: SlingPayload("gt-2", "gastown/nux")},
+		{Type: TypeHook, Actor: "gastown/toast", Payload: HookPayload("gt-1")},
+		{Type: TypeDone, Actor: "gastown/toast", Payload: DonePayload("gt-1", branch)},
+		// Older merge events carry no source issue: linked by branch, then MR
+		{Type: TypeMergeStarted, Actor: "gastown/refinery", Payload: MergeInfo{MR: "mr-1", Branch: branch}.Map()},
+		{Type: TypeMerged, Actor: "gastown/refinery", Payload: MergeInfo{MR: "mr-1"}.Map()},
+		{Type: TypeMerged, Actor: "gastown/refinery", Payload: MergeInfo{MR: "mr-2", Branch: "polecat/nux/gt-2", SourceIssue: "gt-2"}.Map()},
+	}
+	for i, e := range log {
+		e.Timestamp = fmt.Sprintf("2026-03-01T12:00:%02dZ", i)
+		e.Source = "gt"
+		e.Visibility = VisibilityFeed
+		if err := Append(townRoot, e); err != nil {
+			t.Fatalf("Append %s: %v", e.Type, err)
+		}
+	}
+
+	chain, err := Chain(townRoot, "gt-1")
+	if err != nil {
+		t.Fatalf("Chain: %v", err)
+	}
+	var got []string
+	for _, r := range chain {
+		got = append(got, r.Type)
+	}
+	want := []string{TypeSling, TypeSpawn, TypeHook, TypeDone, TypeMergeStarted, TypeMerged}
+	if len(got) != len(want) {
+		t.Fatalf("chain = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("chain = %v, want %v", got, want)
+		}
+	}
+
+	if other, _ := Chain(townRoot, "gt-2"); len(other) != 2 {
+		t.Errorf("gt-2 chain has %d events, want its sling and merge", len(other))
+	}
+	if none, _ := Chain(townRoot, ""); none != nil {
+		t.Errorf("empty work ID chain = %v", none)
+	}
+}
+
+func TestWorkID(t *testing.T) {
+	tests := []struct {
+		e    Event
+		want string
+	}{
+		{Event{Type: TypeSling, Payload: SlingPayload("gt-1", "gastown/toast")}, "gt-1"},
+		{Event{Type: TypeMerged, Payload: MergeInfo{MR: "mr-1", SourceIssue: "gt-1"}.Map()}, "gt-1"},
+		{Event{Type: TypeSpawn, Payload: WithWork(SpawnPayload("gastown", "toast"), "gt-1")}, "gt-1"},
+		{Event{Type: TypeSpawn, Payload: SpawnPayload("gastown", "toast")}, ""},
+		{Event{Type: TypeBoot}, ""},
+	}
+	for _, tt := range tests {
+		if got := WorkID(tt.e); got != tt.want {
+			t.Errorf("WorkID(%s %v) = %q, want %q", tt.e.Type, tt.e.Payload, got, tt.want)
+		}
+	}
+}
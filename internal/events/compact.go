@@ -0,0 +1,423 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RemapFile is the name of the log recording how prune and compact
+// renumbered each events log. Readers that checkpoint a line offset use it to
+// translate old offsets after a rewrite (see RemapOffset).
+const RemapFile = ".events.remap.jsonl"
+
+// Remap records one rewrite of a log file.
+type Remap struct {
+	Timestamp string `json:"ts"`
+	Shard     string `json:"shard,omitempty"`
+	// Generation is the log's generation after this rewrite; a log that has
+	// never been rewritten is generation 0.
+	Generation int `json:"generation"`
+	// OldLines is the number of lines the log had before the rewrite.
+	OldLines int `json:"old_lines"`
+	// Kept lists the old sequence numbers that survived, as inclusive
+	// [first, last] ranges in order. Survivors are renumbered from 1.
+	Kept [][2]int `json:"kept"`
+	// Archive is set by rotation: the gzipped file, relative to the town
+	// root, holding the lines that did not survive, in their old order.
+	Archive string `json:"archive,omitempty"`
+}
+
+// RewriteResult summarizes a prune or compact of one log file.
+type RewriteResult struct {
+	Shard      string `json:"shard,omitempty"`
+	Kept       int    `json:"kept"`
+	Dropped    int    `json:"dropped"`
+	Generation int    `json:"generation"`
+	Archive    string `json:"archive,omitempty"`
+}
+
+// keepFunc decides whether a log line survives a rewrite. event is nil for
+// lines that are not valid events.
+type keepFunc func(event *Event, annotated bool) bool
+
+// Prune removes events older than before from every log file. Events at or
+// above keepSignificance (if set, under the town's significance rules) and
+// annotated events are always kept, as are lines without a readable
+// timestamp.
+func Prune(townRoot string, before time.Time, keepSignificance string) ([]RewriteResult, error) {
+	var rules *SignificanceRules
+	if keepSignificance != "" {
+		if err := ValidSignificance(keepSignificance); err != nil {
+			return nil, err
+		}
+		var err error
+		if rules, err = LoadSignificanceRules(townRoot); err != nil {
+			return nil, err
+		}
+	}
+	return rewriteLogs(townRoot, func(event *Event, annotated bool) bool {
+		if event == nil || annotated {
+			return true
+		}
+		if keepSignificance != "" && rules.AtLeast(*event, keepSignificance) {
+			return true
+		}
+		ts, err := time.Parse(time.RFC3339, event.Timestamp)
+		if err != nil {
+			return true
+		}
+		return !ts.Before(before)
+	})
+}
+
+// Compact removes blank, torn and otherwise malformed lines from every log
+// file. Readers already skip them; compacting reclaims the space and keeps
+// sequence numbers dense.
+func Compact(townRoot string) ([]RewriteResult, error) {
+	return rewriteLogs(townRoot, func(event *Event, _ bool) bool {
+		return event != nil
+	})
+}
+
+// rewriteLogs applies keep to the town-wide log and every shard.
+// Files with nothing to drop are left untouched.
+func rewriteLogs(townRoot string, keep keepFunc) ([]RewriteResult, error) {
+	annotations, err := LoadAnnotations(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []RewriteResult
+	shards := append([]string{""}, Shards(townRoot)...)
+	for _, shard := range shards {
+		result, err := rewriteLog(townRoot, shard, annotations, keep, "")
+		if err != nil {
+			return results, err
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+	return results, nil
+}
+
+// rewriteLog rewrites one log file under its lock, then records the remap
+// and renumbers annotations. If archive is set (relative to the town root),
+// dropped lines are first saved there gzipped. Returns nil if nothing was
+// dropped.
+func rewriteLog(townRoot, shard string, annotations map[string][]Annotation, keep keepFunc, archive string) (*RewriteResult, error) {
+	path := LogPath(townRoot, shard)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	lock, err := lockLog(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+
+	var out, dropped bytes.Buffer
+	var kept []int
+	oldLines := 0
+	dec := NewDecoder(townRoot)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		oldLines++
+		line := scanner.Bytes()
+		var event *Event
+		if e, err := dec.Parse(line); err == nil {
+			event = &e
+		}
+		if !keep(event, len(annotations[EventID(shard, oldLines)]) > 0) {
+			dropped.Write(line)
+			dropped.WriteByte('\n')
+			continue
+		}
+		kept = append(kept, oldLines)
+		out.Write(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+	if len(kept) == oldLines {
+		return nil, nil
+	}
+
+	if archive != "" {
+		if err := writeArchive(filepath.Join(townRoot, archive), dropped.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := replaceFile(path, out.Bytes()); err != nil {
+		return nil, err
+	}
+
+	generation, err := Generation(townRoot, shard)
+	if err != nil {
+		return nil, err
+	}
+	remap := Remap{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Shard:      shard,
+		Generation: generation + 1,
+		OldLines:   oldLines,
+		Kept:       toRanges(kept),
+		Archive:    archive,
+	}
+	line, err := json.Marshal(remap)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling remap: %w", err)
+	}
+	if err := appendLine(filepath.Join(townRoot, RemapFile), append(line, '\n'), true); err != nil {
+		return nil, fmt.Errorf("writing remap: %w", err)
+	}
+
+	if err := renumberAnnotations(townRoot, shard, kept); err != nil {
+		return nil, err
+	}
+
+	return &RewriteResult{
+		Shard:      shard,
+		Kept:       len(kept),
+		Dropped:    oldLines - len(kept),
+		Generation: remap.Generation,
+		Archive:    archive,
+	}, nil
+}
+
+// renumberAnnotations rewrites the annotations of one shard to point at the
+// renumbered events. kept lists the surviving old sequence numbers in order.
+func renumberAnnotations(townRoot, shard string, kept []int) error {
+	path := filepath.Join(townRoot, AnnotationsFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	lock, err := lockLog(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	newSeq := make(map[int]int, len(kept))
+	for i, old := range kept {
+		newSeq[old] = i + 1
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		return fmt.Errorf("reading annotations file: %w", err)
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var ann Annotation
+		if err := json.Unmarshal(line, &ann); err != nil || ann.Shard != shard {
+			out.Write(line)
+			out.WriteByte('\n')
+			continue
+		}
+		seq, ok := newSeq[ann.Seq]
+		if !ok {
+			continue
+		}
+		ann.Seq = seq
+		updated, err := json.Marshal(ann)
+		if err != nil {
+			return fmt.Errorf("marshaling annotation: %w", err)
+		}
+		out.Write(updated)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading annotations file: %w", err)
+	}
+
+	return replaceFile(path, out.Bytes())
+}
+
+// replaceFile atomically replaces path with data via a synced temp file.
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("syncing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil { //nolint:gosec // G302: events file is non-sensitive operational data
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// toRanges collapses sorted sequence numbers into inclusive ranges.
+func toRanges(seqs []int) [][2]int {
+	ranges := [][2]int{}
+	for _, s := range seqs {
+		if n := len(ranges); n > 0 && ranges[n-1][1] == s-1 {
+			ranges[n-1][1] = s
+			continue
+		}
+		ranges = append(ranges, [2]int{s, s})
+	}
+	return ranges
+}
+
+// loadRemaps reads the remap log for one shard, ordered by generation.
+func loadRemaps(townRoot, shard string) ([]Remap, error) {
+	f, err := os.Open(filepath.Join(townRoot, RemapFile)) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening remap file: %w", err)
+	}
+	defer f.Close()
+
+	var remaps []Remap
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var r Remap
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.Shard != shard {
+			continue
+		}
+		remaps = append(remaps, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading remap file: %w", err)
+	}
+
+	sort.SliceStable(remaps, func(i, j int) bool {
+		return remaps[i].Generation < remaps[j].Generation
+	})
+	return remaps, nil
+}
+
+// Generation returns how many times a shard's log ("" for the town-wide
+// log) has been rewritten by prune or compact.
+func Generation(townRoot, shard string) (int, error) {
+	remaps, err := loadRemaps(townRoot, shard)
+	if err != nil {
+		return 0, err
+	}
+	if len(remaps) == 0 {
+		return 0, nil
+	}
+	return remaps[len(remaps)-1].Generation, nil
+}
+
+// Position returns where every log ends now, by shard ("" for the town-wide
+// log): its generation and complete lines. A position taken alongside
+// reader state records which events that state had a chance to see.
+func Position(townRoot string) (map[string]Offset, error) {
+	pos := make(map[string]Offset)
+	for _, shard := range append([]string{""}, Shards(townRoot)...) {
+		gen, err := Generation(townRoot, shard)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := countLines(LogPath(townRoot, shard))
+		if err != nil {
+			return nil, err
+		}
+		pos[shard] = Offset{Generation: gen, Lines: lines}
+	}
+	return pos, nil
+}
+
+// countLines counts the complete lines of a file; a missing file has none.
+func countLines(path string) (int, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from town root
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	lines := 0
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := f.Read(buf)
+		lines += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+// RemapOffset translates a reader checkpoint — offset lines of a shard's
+// log consumed at generation gen — into the current generation. A reader
+// that resumes from the returned offset sees exactly the surviving events it
+// had not yet read.
+func RemapOffset(townRoot, shard string, gen, offset int) (newGen, newOffset int, err error) {
+	remaps, err := loadRemaps(townRoot, shard)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newGen, newOffset = gen, offset
+	for _, r := range remaps {
+		if r.Generation <= newGen {
+			continue
+		}
+		newGen, newOffset = r.Generation, r.translate(newOffset)
+	}
+	return newGen, newOffset, nil
+}
+
+// translate maps an offset in the log before this rewrite to the number of
+// surviving lines it covers, i.e. the offset in the rewritten log.
+func (r Remap) translate(offset int) int {
+	consumed := 0
+	for _, rng := range r.Kept {
+		if rng[0] > offset {
+			break
+		}
+		last := rng[1]
+		if last > offset {
+			last = offset
+		}
+		consumed += last - rng[0] + 1
+	}
+	return consumed
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrune_KeepsSignificantAndAnnotated(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"patrol_started","actor":"gastown/witness"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"merge_failed","actor":"gastown/refinery"}`,
+		`{"ts":"2026-01-01T00:02:00Z","type":"nudge","actor":"deacon"}`,
+		`{"ts":"2026-02-01T00:00:00Z","type":"sling","actor":"mayor"}`,
+	)
+	if _, err := Annotate(townRoot, "", 3, "why was this nudged?", ""); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+
+	cutoff := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	results, err := Prune(townRoot, cutoff, SignificanceHigh)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(results) != 1 || results[0].Dropped != 1 || results[0].Kept != 3 || results[0].Generation != 1 {
+		t.Fatalf("results = %+v, want 1 dropped, 3 kept, generation 1", results)
+	}
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	var types []string
+	for _, r := range records {
+		types = append(types, r.Type)
+	}
+	want := []string{"merge_failed", "nudge", "sling"}
+	if len(types) != len(want) {
+		t.Fatalf("types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] || records[i].Seq != i+1 {
+			t.Errorf("records[%d] = seq %d %s, want seq %d %s", i, records[i].Seq, types[i], i+1, want[i])
+		}
+	}
+	if len(records[1].Annotations) != 1 {
+		t.Errorf("annotation did not follow renumbered event: %+v", records)
+	}
+}
+
+func TestPrune_InvalidSignificance(t *testing.T) {
+	if _, err := Prune(t.TempDir(), time.Now(), "urgent"); err == nil {
+		t.Error("Prune with unknown significance should fail")
+	}
+}
+
+func TestCompact_DropsMalformedAndRemapsOffsets(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"sli`,
+		``,
+		`{"ts":"2026-01-01T00:02:00Z","type":"done","actor":"gastown/polecats/Toast"}`,
+		`{"ts":"2026-01-01T00:03:00Z","type":"halt","actor":"mayor"}`,
+	)
+
+	results, err := Compact(townRoot)
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if len(results) != 1 || results[0].Dropped != 2 {
+		t.Fatalf("results = %+v, want 2 dropped", results)
+	}
+
+	// Nothing left to drop: a second compact is a no-op.
+	if results, err := Compact(townRoot); err != nil || len(results) != 0 {
+		t.Fatalf("second Compact = %+v, %v; want no-op", results, err)
+	}
+
+	// A reader that had consumed 4 lines (through "done") resumes after it.
+	gen, offset, err := RemapOffset(townRoot, "", 0, 4)
+	if err != nil {
+		t.Fatalf("RemapOffset: %v", err)
+	}
+	if gen != 1 || offset != 2 {
+		t.Errorf("RemapOffset(0, 4) = gen %d offset %d, want gen 1 offset 2", gen, offset)
+	}
+	if _, offset, _ := RemapOffset(townRoot, "", 0, 2); offset != 1 {
+		t.Errorf("RemapOffset(0, 2) offset = %d, want 1", offset)
+	}
+	if gen, offset, _ := RemapOffset(townRoot, "", 1, 2); gen != 1 || offset != 2 {
+		t.Errorf("current-generation offset changed: gen %d offset %d", gen, offset)
+	}
+}
+
+func TestToRanges(t *testing.T) {
+	got := toRanges([]int{1, 2, 3, 5, 7, 8})
+	want := [][2]int{{1, 3}, {5, 5}, {7, 8}}
+	if len(got) != len(want) {
+		t.Fatalf("toRanges = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("toRanges[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package events
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// KeyFile is the name of a town key kept in the town root, where keys were
+// kept before DefaultKeyPath.
+const KeyFile = ".events.key"
+
+// KeyEnv overrides the town key with a hex-encoded 32-byte key.
+const KeyEnv = "GT_EVENTS_KEY"
+
+// ErrNoKey indicates an encrypted event was read without the town key.
+var ErrNoKey = errors.New("events are encrypted and no town key is available")
+
+// sealedLine is the on-disk form of an encrypted event: base64 of the GCM
+// nonce followed by the ciphertext. It is still one JSON object per line, so
+// appends, locking and line numbering work unchanged.
+type sealedLine struct {
+	Enc string `json:"enc"`
+}
+
+// keyCache holds loaded town keys by key file path.
+var keyCache sync.Map
+
+// keyWarned records key files already warned about by warnTrackedKey.
+var keyWarned sync.Map
+
+// DefaultKeyPath returns where a town key is kept unless events.key_file
+// says otherwise: in the user's config directory, named for the town root,
+// so syncing or committing the town root never carries the key along with
+// the events it decrypts.
+func DefaultKeyPath(townRoot string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(townRoot, KeyFile)
+	}
+	if abs, err := filepath.Abs(townRoot); err == nil {
+		townRoot = abs
+	}
+	sum := sha256.Sum256([]byte(townRoot))
+	return filepath.Join(dir, "gastown", "keys", hex.EncodeToString(sum[:8])+".key")
+}
+
+// KeyPath returns where the town's key is kept, under its settings.
+func KeyPath(townRoot string) string {
+	return keyPath(townRoot, loadConfig(townRoot))
+}
+
+// keyPath returns the configured town key file. A key already in the town
+// root, from before keys were kept outside it, is still used.
+func keyPath(townRoot string, cfg *config.EventsConfig) string {
+	if cfg.KeyFile == "" {
+		legacy := filepath.Join(townRoot, KeyFile)
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+		return DefaultKeyPath(townRoot)
+	}
+	if strings.HasPrefix(cfg.KeyFile, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, cfg.KeyFile[2:])
+		}
+	}
+	if !filepath.IsAbs(cfg.KeyFile) {
+		return filepath.Join(townRoot, cfg.KeyFile)
+	}
+	return cfg.KeyFile
+}
+
+// loadKey returns the town key. With create set, a missing key file is
+// generated; otherwise a missing key returns ErrNoKey.
+func loadKey(townRoot string, cfg *config.EventsConfig, create bool) ([]byte, error) {
+	if env := os.Getenv(KeyEnv); env != "" {
+		return decodeKey(env)
+	}
+
+	path := keyPath(townRoot, cfg)
+	if key, ok := keyCache.Load(path); ok {
+		return key.([]byte), nil
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from town settings
+	if os.IsNotExist(err) && create {
+		data, err = generateKey(path)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoKey
+		}
+		return nil, fmt.Errorf("reading town key: %w", err)
+	}
+
+	key, err := decodeKey(string(data))
+	if err != nil {
+		return nil, err
+	}
+	keyCache.Store(path, key)
+	warnTrackedKey(townRoot, path)
+	return key, nil
+}
+
+// warnTrackedKey warns, once per key file, when the town key is inside a
+// git-tracked town root without being ignored: a commit would publish it
+// next to the events it decrypts.
+func warnTrackedKey(townRoot, path string) {
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return
+	}
+	if _, warned := keyWarned.LoadOrStore(path, true); warned {
+		return
+	}
+	g := git.NewGit(townRoot)
+	if g.IsRepo() && !g.IsIgnored(path) {
+		fmt.Fprintf(os.Stderr, "Warning: town key %s is in the town's git repo and not ignored; "+
+			"add %s to .gitignore or move it out of the town (events.key_file)\n", path, rel)
+	}
+}
+
+// generateKey writes a new random key to path, readable only by its owner.
+// O_EXCL makes concurrent first writers agree on a single key.
+func generateKey(path string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating town key: %w", err)
+	}
+	encoded := []byte(hex.EncodeToString(key) + "\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if os.IsExist(err) {
+		return os.ReadFile(path) //nolint:gosec // G304: path is from town settings
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating town key: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(encoded); err != nil {
+		return nil, fmt.Errorf("writing town key: %w", err)
+	}
+	return encoded, nil
+}
+
+// decodeKey parses a hex-encoded 32-byte key.
+func decodeKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid town key: want 64 hex characters")
+	}
+	return key, nil
+}
+
+// seal encrypts a marshaled event into a sealed log line (without newline).
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return json.Marshal(sealedLine{Enc: base64.StdEncoding.EncodeToString(sealed)})
+}
+
+// unseal decrypts the payload of a sealed log line.
+func unseal(key []byte, enc string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding sealed event: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed event too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting event: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// ParseLine decodes one events log line into an event, decrypting it with
+// the town key if it was written encrypted. Plaintext lines parse as-is, so
+// logs that switched to encryption part-way stay readable. Callers parsing
+// many lines should hold a Decoder instead, which loads the key once.
+func ParseLine(townRoot string, line []byte) (Event, error) {
+	return NewDecoder(townRoot).Parse(line)
+}
+
+// Decoder parses log lines for one town, loading the key at most once.
+type Decoder struct {
+	townRoot string
+	key      []byte
+	keyErr   error
+	loaded   bool
+}
+
+// NewDecoder creates a decoder for a town's log lines. The town settings
+// and key are read on the first encrypted line.
+func NewDecoder(townRoot string) *Decoder {
+	return &Decoder{townRoot: townRoot}
+}
+
+// Parse decodes one log line as ParseLine does.
+func (d *Decoder) Parse(line []byte) (Event, error) {
+	var sealed sealedLine
+	if err := json.Unmarshal(line, &sealed); err != nil {
+		return Event{}, err
+	}
+	if sealed.Enc != "" {
+		if !d.loaded {
+			d.key, d.keyErr = loadKey(d.townRoot, loadConfig(d.townRoot), false)
+			d.loaded = true
+		}
+		if d.keyErr != nil {
+			return Event{}, d.keyErr
+		}
+		plaintext, err := unseal(d.key, sealed.Enc)
+		if err != nil {
+			return Event{}, err
+		}
+		line = plaintext
+	}
+
+	var event Event
+	if err := json.Unmarshal(line, &event); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func sealedEventLine(t *testing.T, townRoot string, event Event) string {
+	t.Helper()
+	// Keep generated keys out of the real user config dir
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(townRoot, "user-config"))
+	t.Setenv("HOME", filepath.Join(townRoot, "home"))
+	key, err := loadKey(townRoot, &config.EventsConfig{}, true)
+	if err != nil {
+		t.Fatalf("loadKey: %v", err)
+	}
+	data, _ := json.Marshal(event)
+	line, err := seal(key, data)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	return string(line)
+}
+
+func TestEncryptedEvents_ReadTransparently(t *testing.T) {
+	townRoot := t.TempDir()
+	sealed := sealedEventLine(t, townRoot, Event{Timestamp: "2026-01-01T00:01:00Z", Type: "done", Actor: "gastown/polecats/Toast"})
+	if strings.Contains(sealed, "Toast") {
+		t.Fatalf("sealed line leaks plaintext: %s", sealed)
+	}
+
+	if _, err := os.Stat(filepath.Join(townRoot, KeyFile)); !os.IsNotExist(err) {
+		t.Errorf("key file created in the town root")
+	}
+	info, err := os.Stat(DefaultKeyPath(townRoot))
+	if err != nil {
+		t.Fatalf("key file not created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("key file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		sealed,
+	)
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 2 || records[1].Seq != 2 || records[1].Actor != "gastown/polecats/Toast" {
+		t.Fatalf("records = %+v, want plaintext and decrypted event", records)
+	}
+}
+
+func TestParseLine_MissingKey(t *testing.T) {
+	townRoot := t.TempDir()
+	sealed := sealedEventLine(t, townRoot, Event{Type: "sling"})
+	keyCache.Delete(DefaultKeyPath(townRoot))
+	if err := os.Remove(DefaultKeyPath(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseLine(townRoot, []byte(sealed)); !errors.Is(err, ErrNoKey) {
+		t.Errorf("ParseLine without key: err = %v, want ErrNoKey", err)
+	}
+}
+
+func TestParseLine_KeyFromEnv(t *testing.T) {
+	t.Setenv(KeyEnv, strings.Repeat("ab", 32))
+	townRoot := t.TempDir()
+	sealed := sealedEventLine(t, townRoot, Event{Type: "sling", Actor: "mayor"})
+	if _, err := os.Stat(DefaultKeyPath(townRoot)); !os.IsNotExist(err) {
+		t.Error("key file should not be created when GT_EVENTS_KEY is set")
+	}
+
+	event, err := ParseLine(townRoot, []byte(sealed))
+	if err != nil || event.Actor != "mayor" {
+		t.Errorf("ParseLine = %+v, %v", event, err)
+	}
+
+	t.Setenv(KeyEnv, strings.Repeat("cd", 32))
+	if _, err := ParseLine(townRoot, []byte(sealed)); err == nil {
+		t.Error("ParseLine with the wrong key should fail")
+	}
+}
+
+func TestKeyPath_LegacyKeyInTownRoot(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(townRoot, "user-config"))
+	if got := keyPath(townRoot, &config.EventsConfig{}); got != DefaultKeyPath(townRoot) {
+		t.Errorf("keyPath = %s, want the default outside the town", got)
+	}
+	legacy := filepath.Join(townRoot, KeyFile)
+	if err := os.WriteFile(legacy, []byte(strings.Repeat("ab", 32)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := keyPath(townRoot, &config.EventsConfig{}); got != legacy {
+		t.Errorf("keyPath = %s, want the existing key in the town root", got)
+	}
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// dedupeWindow is how far back from the end of a log, in bytes, Append
+// looks for an event with the same hash before writing.
+const dedupeWindow = 64 * 1024
+
+// recentHashes bounds the hashes a Deduper remembers.
+const recentHashes = 4096
+
+// EventHash returns an event's content hash: the first 16 bytes, in hex,
+// of a SHA-256 over its type, actor, timestamp and payload, and its ID if
+// it has one. A command retried within the same second logs the same event
+// twice with the same hash, which is how readers tell the copies apart
+// from distinct events; events with different IDs never share a hash.
+func EventHash(e Event) string {
+	payload, err := json.Marshal(e.Payload) // map keys are sorted
+	if err != nil {
+		payload = nil
+	}
+	h := sha256.New()
+	parts := [][]byte{[]byte(e.Type), []byte(e.Actor), []byte(e.Timestamp), payload}
+	if e.ID != "" {
+		// Hashes of events without an ID are as they always were
+		parts = append(parts, []byte(e.ID))
+	}
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Dedupe returns records without the repeats of any event hash seen
+// earlier in the slice. Events logged without a hash are always kept.
+func Dedupe(records []Record) []Record {
+	seen := make(map[string]bool)
+	kept := records[:0]
+	for _, r := range records {
+		if r.Hash != "" {
+			if seen[r.Hash] {
+				continue
+			}
+			seen[r.Hash] = true
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// Deduper drops repeats from a stream of events, such as a tailed log,
+// remembering the most recent hashes it has seen.
+type Deduper struct {
+	seen  map[string]bool
+	order []string
+}
+
+// NewDeduper returns a Deduper that has seen nothing.
+func NewDeduper() *Deduper {
+	return &Deduper{seen: make(map[string]bool)}
+}
+
+// Seen reports whether e repeats a recent event, and remembers it if not.
+// Events without a hash are never repeats.
+func (d *Deduper) Seen(e Event) bool {
+	if e.Hash == "" {
+		return false
+	}
+	if d.seen[e.Hash] {
+		return true
+	}
+	d.seen[e.Hash] = true
+	d.order = append(d.order, e.Hash)
+	if len(d.order) > recentHashes {
+		delete(d.seen, d.order[0])
+		d.order = d.order[1:]
+	}
+	return false
+}
+
+// Deduplicate rewrites every log file without repeated events, keeping
+// the first copy of each. Events logged before hashes were written are
+// compared by the hash they would have had. Annotated events and lines that
+// are not valid events are kept.
+func Deduplicate(townRoot string) ([]RewriteResult, error) {
+	seen := make(map[string]bool)
+	return rewriteLogs(townRoot, func(event *Event, annotated bool) bool {
+		if event == nil {
+			return true
+		}
+		hash := event.Hash
+		if hash == "" {
+			hash = EventHash(*event)
+		}
+		if seen[hash] && !annotated {
+			return false
+		}
+		seen[hash] = true
+		return true
+	})
+}
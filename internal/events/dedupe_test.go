@@ -0,0 +1,111 @@
+package events
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAppend_SkipsRepeatedEvent(t *testing.T) {
+	townRoot := t.TempDir()
+	event := Event{Timestamp: "2026-01-01T00:00:00Z", Source: "gt", Type: "sling", Actor: "mayor",
+		Payload: SlingPayload("gt-1", "gastown"), Visibility: VisibilityFeed, ID: "sling-1"}
+	for i := 0; i < 2; i++ {
+		if err := Append(townRoot, event); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	event.ID = "sling-2"
+	if err := Append(townRoot, event); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	data, err := os.ReadFile(LogPath(townRoot, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("log has %d lines, want the retried event written once:\n%s", lines, data)
+	}
+	if !strings.Contains(string(data), `"hash":"`+EventHash(event)+`"`) {
+		t.Errorf("event written without its hash:\n%s", data)
+	}
+}
+
+func TestAppend_WritesAlikeEventsWithoutID(t *testing.T) {
+	townRoot := t.TempDir()
+	// Two nudges in the same second are two events, not a retry
+	event := Event{Timestamp: "2026-01-01T00:00:00Z", Source: "gt", Type: "nudge", Actor: "deacon",
+		Payload: map[string]interface{}{"target": "gastown/witness"}, Visibility: VisibilityFeed}
+	for i := 0; i < 2; i++ {
+		if err := Append(townRoot, event); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	data, err := os.ReadFile(LogPath(townRoot, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("log has %d lines, want both events written:\n%s", lines, data)
+	}
+	withID := event
+	withID.ID = "nudge-1"
+	if EventHash(withID) == EventHash(event) {
+		t.Error("an event's ID should be part of its hash")
+	}
+}
+
+func TestReadAll_DropsRepeatedHashes(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor","hash":"aa"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"done","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor","hash":"aa"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"done","actor":"mayor"}`,
+	)
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	// Unhashed events are left for gt events dedupe
+	if len(records) != 3 || records[0].Seq != 1 {
+		t.Errorf("records = %+v, want the repeated hash dropped", records)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"done","actor":"gastown/polecats/Toast"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"done","actor":"gastown/polecats/Toast"}`,
+		`{"ts":"2026-01-01T00:02:00Z","type":"boot","actor":"mayor"}`,
+	)
+	if _, err := Annotate(townRoot, "", 4, "second done?", ""); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+
+	results, err := Deduplicate(townRoot)
+	if err != nil {
+		t.Fatalf("Deduplicate: %v", err)
+	}
+	if len(results) != 1 || results[0].Dropped != 1 || results[0].Kept != 4 {
+		t.Fatalf("results = %+v, want 1 dropped, 4 kept", results)
+	}
+	if results, err := Deduplicate(townRoot); err != nil || len(results) != 0 {
+		t.Fatalf("second Deduplicate = %+v, %v; want no-op", results, err)
+	}
+}
+
+func TestDeduper(t *testing.T) {
+	d := NewDeduper()
+	e := Event{Type: "sling", Hash: "aa"}
+	if d.Seen(e) || !d.Seen(e) {
+		t.Error("want the first copy passed and the second dropped")
+	}
+	if d.Seen(Event{Type: "sling"}) || d.Seen(Event{Type: "sling"}) {
+		t.Error("unhashed events should never be dropped")
+	}
+}
@@ -1,17 +1,19 @@
 // Package events provides event logging for the gt activity feed.
 //
 // Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). When the town
+// enables rig sharding, rig-scoped events go to ~/gt/.events/<rig>.jsonl
+// instead; ReadAll and Tailer merge all files for town-wide consumers.
 package events
 
 import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +25,16 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// ID optionally identifies the event as its producer knows it, such
+	// as one delivery a retry may repeat. Append writes an event with an
+	// ID only once.
+	ID string `json:"id,omitempty"`
+
+	// Hash identifies the event by content (see EventHash), so readers can
+	// drop the copies a retried command logs. Empty in events logged
+	// before hashes were written.
+	Hash string `json:"hash,omitempty"`
 }
 
 // Visibility levels for events.
@@ -46,6 +58,10 @@ const (
 	TypeBoot    = "boot"
 	TypeHalt    = "halt"
 
+	// Drain events: an agent with hooked work is asked to hand off before
+	// it is stopped
+	TypeDrain = "drain"
+
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
@@ -54,6 +70,13 @@ const (
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
+	// Idle suspension events (daemon auto-suspend)
+	TypeSessionSuspended = "session_suspended"
+	TypeSessionResumed   = "session_resumed"
+
+	// Authorization events (audit-only)
+	TypeAuthzDenied = "authz_denied"
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
@@ -68,6 +91,18 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Rig health events (emitted by the daemon when a rig's grade changes)
+	TypeRigHealthChanged = "rig_health_changed"
+
+	// Supervisor events (emitted by the daemon when it restarts an agent
+	// under its restart policy, or stops trying)
+	TypeAgentRestarted   = "agent_restarted"
+	TypeAgentRestartHalt = "agent_restart_halted"
+
+	// Incident events are synthesized by a Scorer from a burst of events
+	// (e.g., five merge failures in ten minutes). They are not logged.
+	TypeIncident = "incident"
 )
 
 // EventsFile is the name of the raw events log.
@@ -101,6 +136,19 @@ func LogAudit(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityAudit)
 }
 
+// LogTo writes an event to a given town's events log, for callers that
+// already know the town rather than finding it from the working directory.
+func LogTo(townRoot, eventType, actor string, payload map[string]interface{}, visibility string) error {
+	return Append(townRoot, Event{
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: visibility,
+	})
+}
+
 // write appends an event to the events file.
 func write(event Event) error {
 	// Find town root
@@ -109,27 +157,56 @@ func write(event Event) error {
 		// Silently ignore - we're not in a Gas Town workspace
 		return nil
 	}
+	return Append(townRoot, event)
+}
 
-	eventsPath := filepath.Join(townRoot, EventsFile)
+// Append writes an event as-is to a town's events log (or its rig shard),
+// honoring the town's sharding and encryption settings. Unlike Log, the
+// caller supplies the timestamp and the town. Events that fail Validate are
+// rejected rather than written for readers to skip.
+//
+// Events are hashed if they aren't already (see EventHash). An event with
+// an ID whose hash is already near the end of its log is not written
+// again, so a producer retrying a write doesn't log the event twice.
+// Events without an ID are always written: two alike in the same second
+// may be distinct, and telling copies apart is left to readers (see
+// Dedupe). Encrypted logs can't be checked; readers drop those repeats.
+func Append(townRoot string, event Event) error {
+	if err := Validate(event); err != nil {
+		return err
+	}
+	if event.Hash == "" {
+		event.Hash = EventHash(event)
+	}
+	cfg := loadConfig(townRoot)
+	eventsPath := LogPath(townRoot, shardFor(cfg, event))
 
 	// Marshal event to JSON
 	data, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshaling event: %w", err)
 	}
+	if cfg.Encrypt {
+		key, err := loadKey(townRoot, cfg, true)
+		if err != nil {
+			return err
+		}
+		if data, err = seal(key, data); err != nil {
+			return fmt.Errorf("encrypting event: %w", err)
+		}
+	}
 	data = append(data, '\n')
+	var marker []byte
+	if event.ID != "" && !cfg.Encrypt {
+		marker = []byte(`"hash":"` + event.Hash + `"`)
+	}
 
-	// Append to file with proper locking
+	// mutex serializes goroutines in this process; appendLine locks
+	// against other processes.
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
-	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := f.Write(data); err != nil {
+	if err := appendLineOnce(eventsPath, data, cfg.Fsync == config.EventFsyncAlways, marker); err != nil {
 		return fmt.Errorf("writing event: %w", err)
 	}
 
@@ -311,6 +388,102 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// SuspendPayload creates a payload for session suspend/resume events.
+// session: tmux session name
+// agent: Gas Town agent identity (e.g., "gastown/witness")
+// mode: how the agent was suspended ("exit" or "park")
+// reason: why (e.g., "idle 2h0m0s", "unread mail")
+func SuspendPayload(session, agent, mode, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"agent":   agent,
+		"mode":    mode,
+		"reason":  reason,
+	}
+}
+
+// AuthzDeniedPayload creates a payload for denied command events.
+// command: the gt command refused (e.g., "gt down")
+// role: the caller's role (e.g., "polecat")
+// reason: the denial message shown to the caller
+func AuthzDeniedPayload(command, role, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"command": command,
+		"role":    role,
+		"reason":  reason,
+	}
+}
+
+// Drain outcomes.
+const (
+	DrainRequested = "requested"  // agent nudged to hand off
+	DrainHandedOff = "handed_off" // hook released
+	DrainExited    = "exited"     // session ended with work still hooked
+	DrainTimedOut  = "timed_out"  // window elapsed with work still hooked
+)
+
+// DrainPayload creates a payload for drain events.
+// target: the agent being drained (e.g., "gastown/Toast")
+// bead: the hooked bead
+// outcome: DrainRequested, DrainHandedOff, DrainExited or DrainTimedOut
+func DrainPayload(target, bead, outcome string) map[string]interface{} {
+	return map[string]interface{}{
+		"target":  target,
+		"bead":    bead,
+		"outcome": outcome,
+	}
+}
+
+// RigHealthPayload creates a payload for rig health change events.
+// score/grade: the rig's current health (0-100, healthy/degraded/critical)
+// previousScore/previousGrade: what it was when last recorded
+// trend: "up", "down" or "steady" against the previous window
+func RigHealthPayload(rig string, score int, grade string, previousScore int, previousGrade, trend string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":            rig,
+		"score":          score,
+		"grade":          grade,
+		"previous_score": previousScore,
+		"previous_grade": previousGrade,
+		"trend":          trend,
+	}
+}
+
+// RestartPayload creates a payload for supervisor restart events.
+// agent: town-level agent (e.g., "mayor")
+// session: tmux session name
+// reason: why it was down ("crashed", "zombie", "not running")
+// policy: the agent's restart policy
+// attempt: restarts in a row, including this one
+func RestartPayload(agent, session, reason, policy string, attempt int) map[string]interface{} {
+	return map[string]interface{}{
+		"agent":   agent,
+		"session": session,
+		"reason":  reason,
+		"policy":  policy,
+		"attempt": attempt,
+	}
+}
+
+// IncidentPayload creates a payload for incident events.
+// eventType: type of the events in the burst (e.g., "merge_failed")
+// scope: what the burst was counted over (a rig or actor), "" for the town
+// count: events in the burst
+// window: the burst rule's window (e.g., "10m")
+// since: timestamp of the burst's first event
+func IncidentPayload(eventType, scope string, count int, window, since string) map[string]interface{} {
+	p := map[string]interface{}{
+		"type":   eventType,
+		"count":  count,
+		"window": window,
+		"since":  since,
+	}
+	if scope != "" {
+		p["scope"] = scope
+	}
+	return p
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export formats.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+	FormatNDJSON  = "ndjson" // whole events, one per line; can be imported
+)
+
+// exportColumns are the columns every export has, before payload fields.
+var exportColumns = []string{"id", "timestamp", "type", "actor", "rig", "role", "significance", "visibility"}
+
+// DefaultExportFields are the payload fields exported when none are chosen.
+// They cover the work item, who it concerns and how it went.
+var DefaultExportFields = []string{"bead", "target", "agent", "branch", "status", "reason"}
+
+// ExportTable flattens records into a header and one row of strings per
+// record: the fixed columns followed by the chosen payload fields. Payload
+// fields that duplicate a fixed column are dropped; missing fields are
+// empty. Significance is classified by rules; nil uses the built-in rules.
+func ExportTable(records []Record, fields []string, rules *SignificanceRules) ([]string, [][]string) {
+	header := append([]string{}, exportColumns...)
+	fixed := make(map[string]bool, len(exportColumns))
+	for _, c := range exportColumns {
+		fixed[c] = true
+	}
+	var payloadFields []string
+	for _, f := range fields {
+		if !fixed[f] {
+			fixed[f] = true
+			payloadFields = append(payloadFields, f)
+		}
+	}
+	header = append(header, payloadFields...)
+
+	rows := make([][]string, 0, len(records))
+	for _, r := range records {
+		row := []string{
+			r.ID(),
+			r.Timestamp,
+			r.Type,
+			r.Actor,
+			EventRig(r.Event),
+			ActorRole(r.Actor),
+			rules.Classify(r.Event),
+			r.Visibility,
+		}
+		for _, f := range payloadFields {
+			row = append(row, payloadString(r.Payload[f]))
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// Export writes records to w in the given format, classifying significance
+// with rules (nil for the built-in rules). NDJSON writes each event whole,
+// as it is logged, so fields and rules don't apply.
+func Export(w io.Writer, format string, records []Record, fields []string, rules *SignificanceRules) error {
+	if format == FormatNDJSON {
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r.Event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	header, rows := ExportTable(records, fields, rules)
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case FormatParquet:
+		return writeParquet(w, header, rows)
+	default:
+		return fmt.Errorf("unknown export format %q (want %s, %s or %s)", format, FormatCSV, FormatParquet, FormatNDJSON)
+	}
+}
+
+// ActorRole returns the role of an event's actor: "mayor", "deacon",
+// "witness", "refinery", "crew" or "polecat" for agent addresses, or the
+// actor itself for other town-level actors such as "daemon".
+func ActorRole(actor string) string {
+	parts := strings.Split(actor, "/")
+	if len(parts) < 2 {
+		return actor
+	}
+	switch parts[1] {
+	case "witness", "refinery":
+		return parts[1]
+	case "crew":
+		return "crew"
+	default:
+		return "polecat"
+	}
+}
+
+// payloadString renders a payload value as a single cell.
+func payloadString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprintf("%v", v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func exportRecords() []Record {
+	return []Record{
+		{Seq: 1, Event: Event{
+			Timestamp: "2026-01-01T00:00:00Z",
+			Type:      TypeMergeFailed,
+			Actor:     "gastown/refinery",
+			Payload:   map[string]interface{}{"branch": "polecat/nux", "reason": "conflict", "attempts": float64(2)},
+		}},
+		{Seq: 2, Shard: "beads", Event: Event{
+			Timestamp:  "2026-01-01T00:01:00Z",
+			Type:       TypeSling,
+			Actor:      "mayor",
+			Payload:    map[string]interface{}{"bead": "gt-1", "target": "beads/polecats/nux", "rig": "beads"},
+			Visibility: VisibilityFeed,
+		}},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatCSV, exportRecords(), []string{"bead", "branch", "attempts", "rig"}, nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading CSV: %v", err)
+	}
+	want := [][]string{
+		{"id", "timestamp", "type", "actor", "rig", "role", "significance", "visibility", "bead", "branch", "attempts"},
+		{"1", "2026-01-01T00:00:00Z", "merge_failed", "gastown/refinery", "gastown", "refinery", "high", "", "", "polecat/nux", "2"},
+		{"beads:2", "2026-01-01T00:01:00Z", "sling", "mayor", "beads", "mayor", "medium", "feed", "gt-1", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if strings.Join(rows[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	for _, records := range [][]Record{exportRecords(), nil} {
+		var buf bytes.Buffer
+		if err := Export(&buf, FormatParquet, records, DefaultExportFields, nil); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+		data := buf.Bytes()
+		if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+			t.Fatal("missing PAR1 magic")
+		}
+		footer := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+		if footer <= 0 || footer > len(data)-12 {
+			t.Fatalf("footer length %d out of range for %d-byte file", footer, len(data))
+		}
+		meta := data[len(data)-8-footer : len(data)-8]
+		for _, col := range []string{"significance", "bead"} {
+			if !bytes.Contains(meta, []byte(col)) {
+				t.Errorf("footer missing column %q", col)
+			}
+		}
+		if len(records) > 0 && !bytes.Contains(data, []byte("polecat/nux")) {
+			t.Error("column data missing")
+		}
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if err := Export(&bytes.Buffer{}, "xlsx", nil, nil, nil); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
+
+func TestActorRole(t *testing.T) {
+	tests := map[string]string{
+		"mayor":               "mayor",
+		"daemon":              "daemon",
+		"gastown/witness":     "witness",
+		"gastown/refinery":    "refinery",
+		"gastown/crew/max":    "crew",
+		"gastown/polecats/nx": "polecat",
+		"gastown/Toast":       "polecat",
+	}
+	for actor, want := range tests {
+		if got := ActorRole(actor); got != want {
+			t.Errorf("ActorRole(%q) = %q, want %q", actor, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Filter selects events by rig, type, actor, payload, significance and time
+// range. Zero fields match everything.
+type Filter struct {
+	Rig          string    `json:"rig,omitempty"`          // Rig the event is about (see EventRig)
+	Type         string    `json:"type,omitempty"`         // Exact event type
+	Actor        string    `json:"actor,omitempty"`        // Exact actor
+	Significance string    `json:"significance,omitempty"` // Minimum significance level
+	Since        time.Time `json:"since,omitempty"`        // At or after
+	Until        time.Time `json:"until,omitempty"`        // Before
+
+	// Payload matches payload values by JSON path ("bead", "$.merge.branch")
+	// against their string form.
+	Payload map[string]string `json:"payload,omitempty"`
+
+	// Rules classify events for Significance; nil uses the built-in rules.
+	// Not sent to the event broker, which uses the town's rules.
+	Rules *SignificanceRules `json:"-"`
+}
+
+// Validate checks the filter's significance level and time range.
+func (f Filter) Validate() error {
+	if f.Significance != "" {
+		if err := ValidSignificance(f.Significance); err != nil {
+			return err
+		}
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Until.After(f.Since) {
+		return fmt.Errorf("time range is empty: until %s is not after since %s",
+			f.Until.Format(time.RFC3339), f.Since.Format(time.RFC3339))
+	}
+	for path := range f.Payload {
+		if !payloadPathRe.MatchString(path) {
+			return fmt.Errorf("invalid payload path %q (want e.g. bead or $.merge.branch)", path)
+		}
+	}
+	return nil
+}
+
+// payloadPathRe matches the JSON paths Filter.Payload accepts: dotted field
+// names, optionally prefixed with "$.".
+var payloadPathRe = regexp.MustCompile(`^(\$\.)?[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// payloadValue looks up a JSON path in an event payload.
+func payloadValue(payload map[string]interface{}, path string) (interface{}, bool) {
+	var v interface{} = payload
+	for _, key := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Match reports whether an event passes the filter. Events without a
+// readable timestamp never match a time range.
+func (f Filter) Match(e Event) bool {
+	if f.Type != "" && e.Type != f.Type {
+		return false
+	}
+	if f.Rig != "" && EventRig(e) != f.Rig {
+		return false
+	}
+	if f.Actor != "" && e.Actor != f.Actor {
+		return false
+	}
+	for path, want := range f.Payload {
+		v, ok := payloadValue(e.Payload, path)
+		if !ok || payloadString(v) != want {
+			return false
+		}
+	}
+	if f.Significance != "" && !f.Rules.AtLeast(e, f.Significance) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			return false
+		}
+		if !f.Since.IsZero() && ts.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && !ts.Before(f.Until) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterMatch(t *testing.T) {
+	e := Event{
+		Timestamp: "2026-01-01T12:00:00Z",
+		Type:      TypeMergeFailed,
+		Actor:     "gastown/refinery",
+	}
+	noon := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"type", Filter{Type: TypeMergeFailed}, true},
+		{"other type", Filter{Type: TypeMerged}, false},
+		{"rig", Filter{Rig: "gastown"}, true},
+		{"other rig", Filter{Rig: "beads"}, false},
+		{"significance", Filter{Significance: SignificanceHigh}, true},
+		{"since inclusive", Filter{Since: noon}, true},
+		{"since after", Filter{Since: noon.Add(time.Second)}, false},
+		{"until exclusive", Filter{Until: noon}, false},
+		{"until after", Filter{Until: noon.Add(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(e); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if (Filter{Since: noon}).Match(Event{Timestamp: "yesterday"}) {
+		t.Error("unparseable timestamp should not match a time range")
+	}
+	if (Filter{Significance: SignificanceMedium}).Match(Event{Type: TypeNudge}) {
+		t.Error("low-significance event should not match a medium filter")
+	}
+
+	demoted := &SignificanceRules{Rules: []SignificanceRule{{Actor: "*/refinery", Level: SignificanceLow}}}
+	if (Filter{Significance: SignificanceHigh, Rules: demoted}).Match(e) {
+		t.Error("event demoted by the filter's rules should not match a high filter")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	noon := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := (Filter{Significance: "urgent"}).Validate(); err == nil {
+		t.Error("unknown significance should be invalid")
+	}
+	if err := (Filter{Since: noon, Until: noon}).Validate(); err == nil {
+		t.Error("empty time range should be invalid")
+	}
+	if err := (Filter{Since: noon, Until: noon.Add(time.Hour)}).Validate(); err != nil {
+		t.Errorf("valid filter: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ImportResult counts what Import did with the events it was given.
+type ImportResult struct {
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"` // already in the town's logs
+	Invalid    int `json:"invalid"`    // lines that aren't valid events
+	Renamed    int `json:"renamed"`    // events whose rig was renamed
+}
+
+// ReadImport parses events exported from another town: NDJSON as written
+// by 'gt events export --format ndjson', or an events log itself. Sealed
+// lines are decrypted with townRoot's key. It returns the events in
+// timestamp order and how many lines were skipped as invalid. CSV and
+// Parquet exports drop payloads, so they can't be imported.
+func ReadImport(townRoot string, r io.Reader) ([]Event, int, error) {
+	records, lines, err := scanRecords(townRoot, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(records) == 0 && lines > 0 {
+		return nil, 0, fmt.Errorf("no events found: only NDJSON exports (gt events export --format ndjson) or events logs can be imported")
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp < records[j].Timestamp
+	})
+	evts := make([]Event, 0, len(records))
+	invalid := lines - len(records)
+	for _, rec := range records {
+		if Validate(rec.Event) != nil {
+			invalid++
+			continue
+		}
+		evts = append(evts, rec.Event)
+	}
+	return evts, invalid, nil
+}
+
+// Payload keys RenameRigs rewrites. Other values are left alone even if
+// they look like addresses: a branch such as "polecat/Toast" or a path
+// isn't a rig name.
+var (
+	// rigFields hold a rig name. An incident's scope is a rig or an actor.
+	rigFields = map[string]bool{"rig": true, "scope": true}
+
+	// addressFields hold an agent address, e.g. "gastown/polecats/Toast".
+	addressFields = map[string]bool{
+		"target": true, "agent": true, "to": true, "from": true,
+		"caller": true, "role": true, "scope": true,
+	}
+)
+
+// RenameRigs rewrites the rig names in an event per renames (old name to
+// new): the rig part of the actor, the payload's rig fields, and the rig
+// part of its address fields. It reports whether anything changed; a
+// changed event is rehashed, since its content is no longer the original's.
+func RenameRigs(e *Event, renames map[string]string) bool {
+	if len(renames) == 0 {
+		return false
+	}
+	changed := false
+	rename := func(s string) string {
+		rig, rest, ok := strings.Cut(s, "/")
+		if to, found := renames[rig]; found && ok {
+			changed = true
+			return to + "/" + rest
+		}
+		return s
+	}
+
+	e.Actor = rename(e.Actor)
+	if len(e.Payload) > 0 {
+		payload := make(map[string]interface{}, len(e.Payload))
+		for k, v := range e.Payload {
+			payload[k] = v
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if to, found := renames[s]; found && rigFields[k] {
+				changed = true
+				payload[k] = to
+			} else if addressFields[k] {
+				payload[k] = rename(s)
+			}
+		}
+		e.Payload = payload
+	}
+	if changed {
+		e.Hash = EventHash(*e)
+	}
+	return changed
+}
+
+// Import appends events from another town to townRoot's logs, renaming
+// rigs per renames. Events already in the town's logs, by hash, are
+// skipped, so importing the same export twice is harmless. Imported events
+// are appended after the town's own; readers merge logs in timestamp order,
+// so they read back in place. With dryRun nothing is written.
+func Import(townRoot string, evts []Event, renames map[string]string, dryRun bool) (*ImportResult, error) {
+	existing, err := ReadAll(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, r := range existing {
+		seen[eventHash(r.Event)] = true
+	}
+
+	result := &ImportResult{}
+	for _, e := range evts {
+		if RenameRigs(&e, renames) {
+			result.Renamed++
+		}
+		hash := eventHash(e)
+		if seen[hash] {
+			result.Duplicates++
+			continue
+		}
+		seen[hash] = true
+		if !dryRun {
+			e.Hash = hash
+			if err := Append(townRoot, e); err != nil {
+				return result, fmt.Errorf("importing %s event at %s: %w", e.Type, e.Timestamp, err)
+			}
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// eventHash returns an event's hash, computing it for events logged before
+// hashes were written.
+func eventHash(e Event) string {
+	if e.Hash != "" {
+		return e.Hash
+	}
+	return EventHash(e)
+}
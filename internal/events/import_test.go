@@ -0,0 +1,137 @@
+package events
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportNDJSON_ImportRoundTrip(t *testing.T) {
+	source := t.TempDir()
+	for _, e := range []Event{
+		{Timestamp: "2026-01-01T00:00:00Z", Source: "gt", Type: TypeSling, Actor: "mayor",
+			Payload: map[string]interface{}{"bead": "gt-1", "target": "gastown/Toast", "rig": "gastown"}, Visibility: VisibilityFeed},
+		{Timestamp: "2026-01-01T00:05:00Z", Source: "gt", Type: TypeDone, Actor: "gastown/Toast",
+			Payload: DonePayload("gt-1", "polecat/Toast"), Visibility: VisibilityFeed},
+	} {
+		if err := Append(source, e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	records, err := ReadAll(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatNDJSON, records, nil, nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	export := buf.String() + "not an event\n"
+
+	target := t.TempDir()
+	if err := Append(target, Event{Timestamp: "2026-01-01T00:02:00Z", Source: "gt", Type: TypeSling, Actor: "mayor",
+		Payload: SlingPayload("gt-9", "beads/Nux"), Visibility: VisibilityFeed}); err != nil {
+		t.Fatal(err)
+	}
+
+	evts, invalid, err := ReadImport(target, strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadImport: %v", err)
+	}
+	if len(evts) != 2 || invalid != 1 {
+		t.Fatalf("ReadImport = %d events, %d invalid; want 2, 1", len(evts), invalid)
+	}
+	result, err := Import(target, evts, map[string]string{"gastown": "oldtown"}, false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Imported != 2 || result.Renamed != 2 || result.Duplicates != 0 {
+		t.Errorf("Import = %+v, want 2 imported and renamed", result)
+	}
+
+	merged, err := ReadAll(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 3 {
+		t.Fatalf("town has %d events after import, want 3", len(merged))
+	}
+	if merged[0].Payload["rig"] != "oldtown" || merged[0].Payload["target"] != "oldtown/Toast" {
+		t.Errorf("first event payload not renamed: %v", merged[0].Payload)
+	}
+	if merged[1].Payload["bead"] != "gt-9" {
+		t.Errorf("events not merged in timestamp order: %v", merged[1].Payload)
+	}
+	if merged[2].Actor != "oldtown/Toast" || merged[2].Payload["branch"] != "polecat/Toast" {
+		t.Errorf("done event = %s %v, want actor renamed and branch untouched", merged[2].Actor, merged[2].Payload)
+	}
+
+	// Importing again finds everything already there
+	evts, _, _ = ReadImport(target, strings.NewReader(export))
+	result, err = Import(target, evts, map[string]string{"gastown": "oldtown"}, false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Imported != 0 || result.Duplicates != 2 {
+		t.Errorf("second Import = %+v, want 2 duplicates", result)
+	}
+}
+
+func TestReadImport_RejectsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatCSV, exportRecords(), DefaultExportFields, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadImport(t.TempDir(), &buf); err == nil || !strings.Contains(err.Error(), "ndjson") {
+		t.Errorf("ReadImport(csv) error = %v, want a hint to export NDJSON", err)
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	townRoot := t.TempDir()
+	evts := []Event{{Timestamp: "2026-01-01T00:00:00Z", Source: "gt", Type: TypeSling, Actor: "mayor",
+		Payload: SlingPayload("gt-1", "gastown/Toast"), Visibility: VisibilityFeed}}
+	result, err := Import(townRoot, evts, nil, true)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Imported != 1 {
+		t.Errorf("Imported = %d, want 1", result.Imported)
+	}
+	records, _ := ReadAll(townRoot)
+	if len(records) != 0 {
+		t.Errorf("dry run wrote %d events", len(records))
+	}
+}
+
+func TestRenameRigs_KnownFieldsOnly(t *testing.T) {
+	e := Event{Type: TypeSessionDeath, Actor: "polecat/witness", Payload: map[string]interface{}{
+		"rig":     "polecat",
+		"agent":   "polecat/polecats/Toast",
+		"caller":  "polecat/witness",
+		"branch":  "polecat/Toast",
+		"cwd":     "polecat/src",
+		"subject": "polecat/Toast is stuck",
+		"count":   float64(2),
+	}}
+	if !RenameRigs(&e, map[string]string{"polecat": "cats"}) {
+		t.Fatal("RenameRigs reported no change")
+	}
+	want := map[string]interface{}{
+		"rig":     "cats",
+		"agent":   "cats/polecats/Toast",
+		"caller":  "cats/witness",
+		"branch":  "polecat/Toast",
+		"cwd":     "polecat/src",
+		"subject": "polecat/Toast is stuck",
+		"count":   float64(2),
+	}
+	if e.Actor != "cats/witness" {
+		t.Errorf("actor = %q, want cats/witness", e.Actor)
+	}
+	for k, v := range want {
+		if e.Payload[k] != v {
+			t.Errorf("payload[%s] = %v, want %v", k, e.Payload[k], v)
+		}
+	}
+}
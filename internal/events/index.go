@@ -0,0 +1,461 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IndexFile is the optional SQLite index of the events log, in the town
+// root. It is maintained through the sqlite3 CLI, like the beads database.
+const IndexFile = ".events.db"
+
+// IndexPath returns the path to a town's events index.
+func IndexPath(townRoot string) string {
+	return filepath.Join(townRoot, IndexFile)
+}
+
+// indexVersion is the index's schema version (PRAGMA user_version). An
+// index written by an older version is rebuilt.
+const indexVersion = 1
+
+// indexTimeLayout is how the index stores timestamps: UTC with fixed-width
+// nanoseconds, so they compare correctly as strings whatever offset or
+// precision an event (an imported one, say) was written with.
+const indexTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// indexTime returns an event timestamp as the index stores it. A timestamp
+// that doesn't parse is kept as written.
+func indexTime(ts string) string {
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return t.UTC().Format(indexTimeLayout)
+}
+
+// sortByTime sorts records as the index orders them: by instant, then
+// shard, then line.
+func sortByTime(records []Record) {
+	sorted := byTime{records: records, keys: make([]string, len(records))}
+	for i, r := range records {
+		sorted.keys[i] = indexTime(r.Timestamp)
+	}
+	sort.Sort(sorted)
+}
+
+type byTime struct {
+	records []Record
+	keys    []string
+}
+
+func (b byTime) Len() int { return len(b.records) }
+
+func (b byTime) Less(i, j int) bool {
+	if b.keys[i] != b.keys[j] {
+		return b.keys[i] < b.keys[j]
+	}
+	if b.records[i].Shard != b.records[j].Shard {
+		return b.records[i].Shard < b.records[j].Shard
+	}
+	return b.records[i].Seq < b.records[j].Seq
+}
+
+func (b byTime) Swap(i, j int) {
+	b.records[i], b.records[j] = b.records[j], b.records[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+const indexSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	shard TEXT NOT NULL,
+	seq INTEGER NOT NULL,
+	ts TEXT NOT NULL,
+	type TEXT NOT NULL,
+	actor TEXT NOT NULL,
+	rig TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	raw TEXT NOT NULL,
+	PRIMARY KEY (shard, seq)
+);
+CREATE INDEX IF NOT EXISTS events_ts ON events (ts);
+CREATE INDEX IF NOT EXISTS events_type ON events (type, ts);
+CREATE INDEX IF NOT EXISTS events_rig ON events (rig, ts);
+CREATE INDEX IF NOT EXISTS events_actor ON events (actor, ts);
+CREATE TABLE IF NOT EXISTS shards (
+	shard TEXT PRIMARY KEY,
+	generation INTEGER NOT NULL,
+	lines INTEGER NOT NULL
+);
+`
+
+// IndexEnabled reports whether the town's settings ask for an events index.
+func IndexEnabled(townRoot string) bool {
+	return loadConfig(townRoot).Index
+}
+
+// IndexAvailable reports whether a town's events can be queried through the
+// index: it exists, sqlite3 is installed, and the log isn't encrypted (the
+// index would hold the events in plaintext).
+func IndexAvailable(townRoot string) bool {
+	if _, err := os.Stat(IndexPath(townRoot)); err != nil {
+		return false
+	}
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return false
+	}
+	return !loadConfig(townRoot).Encrypt
+}
+
+// UpdateIndex brings the town's events index up to date with its logs,
+// creating the index if needed. Only lines appended since the last update
+// are read; a log rewritten by prune, compact or rotate is reindexed from
+// scratch. Returns the number of events added.
+func UpdateIndex(townRoot string) (int, error) {
+	if loadConfig(townRoot).Encrypt {
+		return 0, fmt.Errorf("events are encrypted; not indexing them in plaintext")
+	}
+	if err := ensureIndexSchema(townRoot); err != nil {
+		return 0, err
+	}
+
+	indexed, err := indexedShards(townRoot)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for shard, path := range logFiles(townRoot) {
+		gen, err := Generation(townRoot, shard)
+		if err != nil {
+			return added, err
+		}
+		var script strings.Builder
+		script.WriteString("BEGIN;\n")
+
+		start := 0
+		if state, ok := indexed[shard]; ok && state.Generation == gen {
+			start = state.Lines
+		}
+		records, lines, err := readRecordsFrom(townRoot, path, start)
+		if errors.Is(err, errLogShrunk) {
+			start = 0
+			records, lines, err = readRecordsFrom(townRoot, path, 0)
+		}
+		if err != nil {
+			return added, err
+		}
+		if start == 0 {
+			fmt.Fprintf(&script, "DELETE FROM events WHERE shard = %s;\n", sqlQuote(shard))
+		} else if lines == start {
+			continue // nothing new
+		}
+
+		for _, r := range records {
+			payload, err := json.Marshal(r.Payload)
+			if err != nil {
+				continue
+			}
+			raw, err := json.Marshal(r.Event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&script, "INSERT OR REPLACE INTO events VALUES (%s, %d, %s, %s, %s, %s, %s, %s);\n",
+				sqlQuote(shard), r.Seq, sqlQuote(indexTime(r.Timestamp)), sqlQuote(r.Type), sqlQuote(r.Actor),
+				sqlQuote(EventRig(r.Event)), sqlQuote(string(payload)), sqlQuote(string(raw)))
+		}
+		fmt.Fprintf(&script, "INSERT OR REPLACE INTO shards VALUES (%s, %d, %d);\nCOMMIT;\n", sqlQuote(shard), gen, lines)
+
+		if _, err := runSQLite(townRoot, script.String()); err != nil {
+			return added, fmt.Errorf("indexing %s: %w", LogPath(townRoot, shard), err)
+		}
+		added += len(records)
+	}
+	return added, nil
+}
+
+// ensureIndexSchema creates the index tables, first dropping those of an
+// index written by an older version so its events are reindexed.
+func ensureIndexSchema(townRoot string) error {
+	out, err := runSQLite(townRoot, "PRAGMA user_version;")
+	if err != nil {
+		return fmt.Errorf("reading events index version: %w", err)
+	}
+	script := indexSchema
+	if strings.TrimSpace(string(out)) != fmt.Sprint(indexVersion) {
+		script = "DROP TABLE IF EXISTS events;\nDROP TABLE IF EXISTS shards;\n" + script +
+			fmt.Sprintf("PRAGMA user_version = %d;\n", indexVersion)
+	}
+	if _, err := runSQLite(townRoot, script); err != nil {
+		return fmt.Errorf("creating events index: %w", err)
+	}
+	return nil
+}
+
+// shardState is how much of a shard's log the index covers.
+type shardState struct {
+	Shard      string `json:"shard"`
+	Generation int    `json:"generation"`
+	Lines      int    `json:"lines"`
+}
+
+func indexedShards(townRoot string) (map[string]shardState, error) {
+	out, err := runSQLite(townRoot, "SELECT shard, generation, lines FROM shards;", "-json")
+	if err != nil {
+		return nil, fmt.Errorf("reading events index: %w", err)
+	}
+	states := make(map[string]shardState)
+	if len(bytes.TrimSpace(out)) == 0 {
+		return states, nil
+	}
+	var rows []shardState
+	if err := json.Unmarshal(out, &rows); err != nil {
+		return nil, fmt.Errorf("parsing events index: %w", err)
+	}
+	for _, row := range rows {
+		states[row.Shard] = row
+	}
+	return states, nil
+}
+
+// errLogShrunk means a log has fewer lines than were already indexed.
+var errLogShrunk = errors.New("log has fewer lines than indexed")
+
+// readRecordsFrom parses the complete lines of a log after the first skip,
+// numbering records by line. A trailing line still being written is left
+// for the next update. Returns the number of complete lines in the log.
+func readRecordsFrom(townRoot, path string, skip int) ([]Record, int, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from town root
+	if errors.Is(err, os.ErrNotExist) {
+		if skip > 0 {
+			return nil, 0, errLogShrunk
+		}
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	reader := bufio.NewReader(f)
+	dec := NewDecoder(townRoot)
+	seq := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading events file: %w", err)
+		}
+		seq++
+		if seq <= skip {
+			continue
+		}
+		if event, perr := dec.Parse(bytes.TrimSpace(line)); perr == nil {
+			records = append(records, Record{Seq: seq, Event: event})
+		}
+	}
+	if seq < skip {
+		return nil, 0, errLogShrunk
+	}
+	return records, seq, nil
+}
+
+// Query returns the events matching f, in the order ReadAll returns them,
+// with annotations attached. When the town has an events index it is
+// queried, with events appended since it was last updated read from the
+// logs; Query never writes to the index, which the daemon (or 'gt events
+// index') keeps up to date. A log rewritten since then is scanned instead.
+func Query(townRoot string, f Filter) ([]Record, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if !IndexAvailable(townRoot) {
+		return scanQuery(townRoot, f)
+	}
+	records, err := indexQuery(townRoot, f)
+	if err != nil {
+		return scanQuery(townRoot, f)
+	}
+	return records, nil
+}
+
+// errIndexStale means a log was rewritten since it was last indexed.
+var errIndexStale = errors.New("events index is out of date")
+
+// unindexed returns the events matching f that were appended to the logs
+// since the index was last updated, or errIndexStale if a log was
+// rewritten since then.
+func unindexed(townRoot string, f Filter) ([]Record, error) {
+	indexed, err := indexedShards(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	files := logFiles(townRoot)
+	for shard := range indexed {
+		if _, ok := files[shard]; !ok {
+			return nil, errIndexStale
+		}
+	}
+	var matched []Record
+	for shard, path := range files {
+		gen, err := Generation(townRoot, shard)
+		if err != nil {
+			return nil, err
+		}
+		state, ok := indexed[shard]
+		if !ok || state.Generation != gen {
+			return nil, errIndexStale
+		}
+		records, _, err := readRecordsFrom(townRoot, path, state.Lines)
+		if errors.Is(err, errLogShrunk) {
+			return nil, errIndexStale
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			if f.Match(r.Event) {
+				r.Shard = shard
+				matched = append(matched, r)
+			}
+		}
+	}
+	return matched, nil
+}
+
+// scanQuery answers a query by reading every log.
+func scanQuery(townRoot string, f Filter) ([]Record, error) {
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	matched := []Record{}
+	for _, r := range records {
+		if f.Match(r.Event) {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+// indexQuery answers a query from the index and the events appended since
+// it was updated. Significance depends on the town's rules, so it is
+// applied to the rows returned.
+func indexQuery(townRoot string, f Filter) ([]Record, error) {
+	tail, err := unindexed(townRoot, f)
+	if err != nil {
+		return nil, err
+	}
+
+	var where []string
+	if f.Type != "" {
+		where = append(where, "type = "+sqlQuote(f.Type))
+	}
+	if f.Rig != "" {
+		where = append(where, "rig = "+sqlQuote(f.Rig))
+	}
+	if f.Actor != "" {
+		where = append(where, "actor = "+sqlQuote(f.Actor))
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "ts >= "+sqlQuote(f.Since.UTC().Format(indexTimeLayout)))
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "ts < "+sqlQuote(f.Until.UTC().Format(indexTimeLayout)))
+	}
+	paths := make([]string, 0, len(f.Payload))
+	for path := range f.Payload {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		jsonPath := sqlQuote("$." + strings.TrimPrefix(path, "$."))
+		// Render values as payloadString does: booleans as true/false
+		where = append(where, fmt.Sprintf(
+			"(CASE json_type(payload, %[1]s) WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(json_extract(payload, %[1]s) AS TEXT) END) = %[2]s",
+			jsonPath, sqlQuote(f.Payload[path])))
+	}
+
+	query := "SELECT shard, seq, raw FROM events"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY ts, shard, seq;"
+
+	out, err := runSQLite(townRoot, query, "-json")
+	if err != nil {
+		return nil, fmt.Errorf("querying events index: %w", err)
+	}
+	var rows []struct {
+		Shard string `json:"shard"`
+		Seq   int    `json:"seq"`
+		Raw   string `json:"raw"`
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &rows); err != nil {
+			return nil, fmt.Errorf("parsing events index results: %w", err)
+		}
+	}
+
+	records := []Record{}
+	for _, row := range rows {
+		var e Event
+		if err := json.Unmarshal([]byte(row.Raw), &e); err != nil {
+			continue
+		}
+		if f.Significance != "" && !f.Rules.AtLeast(e, f.Significance) {
+			continue
+		}
+		records = append(records, Record{Seq: row.Seq, Shard: row.Shard, Event: e})
+	}
+	if len(tail) > 0 {
+		records = append(records, tail...)
+		sortByTime(records)
+	}
+	records = Dedupe(records)
+
+	annotations, err := LoadAnnotations(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		records[i].Annotations = annotations[records[i].ID()]
+	}
+	return records, nil
+}
+
+// runSQLite runs a SQL script against the town's events index.
+func runSQLite(townRoot, script string, flags ...string) ([]byte, error) {
+	args := append(append([]string{}, flags...), IndexPath(townRoot))
+	cmd := exec.Command("sqlite3", args...) //nolint:gosec // G204: fixed binary, script built with quoted values
+	// Wait out a concurrent writer (the daemon or another query) rather than
+	// failing with "database is locked"
+	cmd.Stdin = strings.NewReader(".timeout 5000\n" + script)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// sqlQuote quotes s as a SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
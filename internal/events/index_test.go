@@ -0,0 +1,210 @@
+package events
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var indexTestEvents = []string{
+	`{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/Toast"}}`,
+	`{"ts":"2026-01-01T01:00:00Z","type":"done","actor":"gastown/polecats/Toast","payload":{"bead":"gt-1","merge":{"branch":"polecat/Toast"}}}`,
+	`not json`,
+	`{"ts":"2026-01-01T02:00:00Z","type":"nudge","actor":"gastown/witness","payload":{"rig":"gastown","urgent":true}}`,
+	`{"ts":"2026-01-01T03:00:00Z","type":"session_death","actor":"deacon","payload":{"session":"gt-gastown-Toast","agent":"gastown/Toast"}}`,
+}
+
+var indexTestFilters = map[string]Filter{
+	"all":          {},
+	"type":         {Type: TypeDone},
+	"rig":          {Rig: "gastown"},
+	"actor":        {Actor: "mayor"},
+	"payload":      {Payload: map[string]string{"bead": "gt-1"}},
+	"nested path":  {Payload: map[string]string{"$.merge.branch": "polecat/Toast"}},
+	"bool payload": {Payload: map[string]string{"urgent": "true"}},
+	"time range":   {Since: time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC), Until: time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)},
+	"significance": {Significance: SignificanceHigh},
+	"no match":     {Type: TypeDone, Actor: "mayor"},
+}
+
+func requireSQLite(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+}
+
+func TestQuery_IndexMatchesScan(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot, indexTestEvents...)
+	writeShardFile(t, townRoot, "gastown",
+		`{"ts":"2026-01-01T00:30:00Z","type":"spawn","actor":"gastown/witness","payload":{"rig":"gastown","polecat":"Toast"}}`)
+
+	if IndexAvailable(townRoot) {
+		t.Fatal("IndexAvailable before indexing = true")
+	}
+	added, err := UpdateIndex(townRoot)
+	if err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+	if added != 5 {
+		t.Errorf("UpdateIndex added %d events, want 5", added)
+	}
+	if !IndexAvailable(townRoot) {
+		t.Fatal("IndexAvailable after indexing = false")
+	}
+
+	for name, f := range indexTestFilters {
+		t.Run(name, func(t *testing.T) {
+			want, err := scanQuery(townRoot, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Query(townRoot, f)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("index returned %+v\nscan returned %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUpdateIndex_Incremental(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot, indexTestEvents[:2]...)
+	if _, err := UpdateIndex(townRoot); err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+
+	// Appended events are picked up; a partial line waits for its newline
+	f, err := os.OpenFile(LogPath(townRoot, ""), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(indexTestEvents[3] + "\n" + `{"ts":"2026-01-01T04:00:00Z",`)
+	_ = f.Close()
+	if added, err := UpdateIndex(townRoot); err != nil || added != 1 {
+		t.Fatalf("UpdateIndex after append = %d, %v; want 1", added, err)
+	}
+	if added, err := UpdateIndex(townRoot); err != nil || added != 0 {
+		t.Fatalf("UpdateIndex with nothing new = %d, %v; want 0", added, err)
+	}
+
+	// A log rewritten shorter is reindexed from scratch
+	writeEventsFile(t, townRoot, indexTestEvents[4])
+	if added, err := UpdateIndex(townRoot); err != nil || added != 1 {
+		t.Fatalf("UpdateIndex after rewrite = %d, %v; want 1", added, err)
+	}
+	records, err := Query(townRoot, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Type != TypeSessionDeath || records[0].Seq != 1 {
+		t.Errorf("records after rewrite = %+v", records)
+	}
+}
+
+func TestFilter_ValidatePayloadPath(t *testing.T) {
+	for path, ok := range map[string]bool{"bead": true, "$.merge.branch": true, "a'b": false, "": false, "a..b": false} {
+		err := Filter{Payload: map[string]string{path: "x"}}.Validate()
+		if (err == nil) != ok {
+			t.Errorf("Validate(%q) error = %v, want ok=%v", path, err, ok)
+		}
+	}
+}
+
+func TestQuery_IndexNormalizesOffsets(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	// An imported event at 23:30 UTC, written with a +02:00 offset, and
+	// local events either side of it
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T23:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-1"}}`,
+		`{"ts":"2026-01-02T01:30:00+02:00","type":"done","actor":"other/polecats/Nux","payload":{"bead":"gt-2"}}`,
+		`{"ts":"2026-01-01T23:45:00.5Z","type":"nudge","actor":"gastown/witness","payload":{"rig":"gastown"}}`)
+	if _, err := UpdateIndex(townRoot); err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+
+	for name, f := range map[string]Filter{
+		"all":   {},
+		"since": {Since: time.Date(2026, 1, 1, 23, 15, 0, 0, time.UTC)},
+		"until": {Until: time.Date(2026, 1, 1, 23, 45, 0, 0, time.UTC)},
+	} {
+		want, err := scanQuery(townRoot, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Query(townRoot, f)
+		if err != nil {
+			t.Fatalf("%s: Query: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: index returned %+v\nscan returned %+v", name, got, want)
+		}
+	}
+
+	records, _ := Query(townRoot, Filter{})
+	var types []string
+	for _, r := range records {
+		types = append(types, r.Type)
+	}
+	if want := []string{TypeSling, TypeDone, TypeNudge}; !reflect.DeepEqual(types, want) {
+		t.Errorf("order = %v, want %v (by instant, not timestamp text)", types, want)
+	}
+}
+
+func TestQuery_DoesNotWriteIndex(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot, indexTestEvents[:2]...)
+	if _, err := UpdateIndex(townRoot); err != nil {
+		t.Fatalf("UpdateIndex: %v", err)
+	}
+	before, err := indexedShards(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Events appended since the last update are still returned
+	f, err := os.OpenFile(LogPath(townRoot, ""), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(indexTestEvents[3] + "\n")
+	_ = f.Close()
+	for name, filter := range map[string]Filter{"all": {}, "rig": {Rig: "gastown"}} {
+		want, _ := scanQuery(townRoot, filter)
+		got, err := Query(townRoot, filter)
+		if err != nil {
+			t.Fatalf("%s: Query: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: index returned %+v\nscan returned %+v", name, got, want)
+		}
+	}
+
+	// A rewritten log is scanned rather than answered from the stale index
+	writeEventsFile(t, townRoot, indexTestEvents[4])
+	records, err := Query(townRoot, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Type != TypeSessionDeath {
+		t.Errorf("records after rewrite = %+v", records)
+	}
+
+	after, err := indexedShards(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(after, before) {
+		t.Errorf("Query updated the index: %+v, was %+v", after, before)
+	}
+}
@@ -0,0 +1,241 @@
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// A minimal Parquet writer for event exports: one row group, every column a
+// required UTF-8 string, PLAIN encoded and uncompressed. That is all an
+// export needs, and it keeps the format readable by pandas, DuckDB and
+// Spark without pulling in a Parquet library.
+//
+// Parquet metadata is Thrift compact protocol; the structs and field IDs
+// below follow parquet.thrift.
+
+const parquetMagic = "PAR1"
+
+// Parquet enum values used by the writer.
+const (
+	parquetTypeByteArray     = 6 // Type.BYTE_ARRAY
+	parquetRequired          = 0 // FieldRepetitionType.REQUIRED
+	parquetConvertedUTF8     = 0 // ConvertedType.UTF8
+	parquetEncodingPlain     = 0 // Encoding.PLAIN
+	parquetEncodingRLE       = 3 // Encoding.RLE
+	parquetCodecUncompressed = 0 // CompressionCodec.UNCOMPRESSED
+	parquetDataPage          = 0 // PageType.DATA_PAGE
+)
+
+// Thrift compact protocol type IDs.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// columnChunk records where a written column lives in the file.
+type columnChunk struct {
+	name   string
+	offset int64 // of its page header
+	size   int64 // page header and data
+	values int64
+}
+
+// writeParquet writes a table of strings as a Parquet file.
+func writeParquet(w io.Writer, header []string, rows [][]string) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	var chunks []columnChunk
+	if len(rows) > 0 {
+		for col, name := range header {
+			var data bytes.Buffer
+			for _, row := range rows {
+				var n [4]byte
+				binary.LittleEndian.PutUint32(n[:], uint32(len(row[col])))
+				data.Write(n[:])
+				data.WriteString(row[col])
+			}
+
+			var ph thriftWriter
+			ph.i32(1, parquetDataPage)
+			ph.i32(2, int32(data.Len()))
+			ph.i32(3, int32(data.Len()))
+			ph.beginStruct(5) // data_page_header
+			ph.i32(1, int32(len(rows)))
+			ph.i32(2, parquetEncodingPlain)
+			ph.i32(3, parquetEncodingRLE)
+			ph.i32(4, parquetEncodingRLE)
+			ph.endStruct()
+			ph.stop()
+
+			offset := int64(file.Len())
+			file.Write(ph.buf.Bytes())
+			file.Write(data.Bytes())
+			chunks = append(chunks, columnChunk{
+				name:   name,
+				offset: offset,
+				size:   int64(file.Len()) - offset,
+				values: int64(len(rows)),
+			})
+		}
+	}
+
+	var meta thriftWriter
+	meta.i32(1, 1) // version
+	meta.listHeader(2, thriftStruct, len(header)+1)
+	meta.beginElement() // root
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(header)))
+	meta.endElement()
+	for _, name := range header {
+		meta.beginElement()
+		meta.i32(1, parquetTypeByteArray)
+		meta.i32(3, parquetRequired)
+		meta.binary(4, name)
+		meta.i32(6, parquetConvertedUTF8)
+		meta.endElement()
+	}
+	meta.i64(3, int64(len(rows)))
+	if len(chunks) == 0 {
+		meta.listHeader(4, thriftStruct, 0)
+	} else {
+		var total int64
+		for _, c := range chunks {
+			total += c.size
+		}
+		meta.listHeader(4, thriftStruct, 1)
+		meta.beginElement() // row group
+		meta.listHeader(1, thriftStruct, len(chunks))
+		for _, c := range chunks {
+			meta.beginElement()
+			meta.i64(2, c.offset)
+			meta.beginStruct(3) // meta_data
+			meta.i32(1, parquetTypeByteArray)
+			meta.listHeader(2, thriftI32, 1)
+			meta.varint(zigzag(parquetEncodingPlain))
+			meta.listHeader(3, thriftBinary, 1)
+			meta.rawBinary(c.name)
+			meta.i32(4, parquetCodecUncompressed)
+			meta.i64(5, c.values)
+			meta.i64(6, c.size)
+			meta.i64(7, c.size)
+			meta.i64(9, c.offset)
+			meta.endStruct()
+			meta.endElement()
+		}
+		meta.i64(2, total)
+		meta.i64(3, int64(len(rows)))
+		meta.endElement()
+	}
+	meta.binary(6, "gastown")
+	meta.stop()
+
+	file.Write(meta.buf.Bytes())
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(meta.buf.Len()))
+	file.Write(n[:])
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// thriftWriter encodes structs in the Thrift compact protocol. Field IDs
+// are delta-encoded against the previous field of the enclosing struct, so
+// it keeps a stack of the last field ID per open struct.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16
+}
+
+func (t *thriftWriter) lastID() int16 {
+	if len(t.last) == 0 {
+		return 0
+	}
+	return t.last[len(t.last)-1]
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	if delta := id - t.lastID(); delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(zigzag(int64(id)))
+	}
+	if len(t.last) == 0 {
+		t.last = append(t.last, id)
+	} else {
+		t.last[len(t.last)-1] = id
+	}
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.rawBinary(s)
+}
+
+func (t *thriftWriter) rawBinary(s string) {
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+// listHeader writes a list field; its elements follow.
+func (t *thriftWriter) listHeader(id int16, elemType byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xF0 | elemType)
+		t.varint(uint64(size))
+	}
+}
+
+// beginStruct opens a struct-valued field.
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftStruct)
+	t.last = append(t.last, 0)
+}
+
+// endStruct closes a struct opened by beginStruct.
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+// beginElement opens a struct that is a list element.
+func (t *thriftWriter) beginElement() {
+	t.last = append(t.last, 0)
+}
+
+// endElement closes a struct opened by beginElement.
+func (t *thriftWriter) endElement() {
+	t.endStruct()
+}
+
+// stop ends the top-level struct.
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
@@ -0,0 +1,243 @@
+package events
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+// thriftReader decodes the Thrift compact protocol independently of
+// thriftWriter, so exported files are checked against parquet.thrift
+// rather than against the writer's own idea of it.
+type thriftReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// decodedStruct is a decoded Thrift struct: field ID to value. Integers decode to
+// int64, binaries to string, lists to []interface{}.
+type decodedStruct map[int16]interface{}
+
+func (r *thriftReader) next() byte {
+	if r.pos >= len(r.data) {
+		if r.err == nil {
+			r.err = fmt.Errorf("unexpected end of data at %d", r.pos)
+		}
+		return 0
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		b := r.next()
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return v
+}
+
+func (r *thriftReader) int() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) readStruct() decodedStruct {
+	s := make(decodedStruct)
+	var last int16
+	for r.err == nil {
+		b := r.next()
+		if b == 0 {
+			break
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			id = int16(r.int())
+		}
+		last = id
+		s[id] = r.value(b & 0x0f)
+	}
+	return s
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case 1, 2: // boolean true, false
+		return typ == 1
+	case 3: // byte
+		return int64(r.next())
+	case 4, 5, 6: // i16, i32, i64
+		return r.int()
+	case 7: // double
+		if r.pos+8 > len(r.data) {
+			r.err = fmt.Errorf("double past end of data at %d", r.pos)
+			return nil
+		}
+		r.pos += 8
+		return nil
+	case 8: // binary
+		n := int(r.uvarint())
+		if r.pos+n > len(r.data) {
+			r.err = fmt.Errorf("binary of %d bytes past end of data at %d", n, r.pos)
+			return nil
+		}
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case 9, 10: // list, set
+		h := r.next()
+		size := int(h >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, 0, size)
+		for i := 0; i < size && r.err == nil; i++ {
+			list = append(list, r.value(h&0x0f))
+		}
+		return list
+	case 12: // struct
+		return r.readStruct()
+	}
+	r.err = fmt.Errorf("unknown compact type %d at %d", typ, r.pos)
+	return nil
+}
+
+// decodeParquet reads a file's FileMetaData from its footer.
+func decodeParquet(t *testing.T, data []byte) decodedStruct {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footer := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footer <= 0 || footer > len(data)-12 {
+		t.Fatalf("footer length %d out of range for %d-byte file", footer, len(data))
+	}
+	r := &thriftReader{data: data[len(data)-8-footer : len(data)-8]}
+	meta := r.readStruct()
+	if r.err != nil {
+		t.Fatalf("decoding FileMetaData: %v", r.err)
+	}
+	if r.pos != footer {
+		t.Fatalf("FileMetaData is %d bytes, footer says %d", r.pos, footer)
+	}
+	return meta
+}
+
+func TestExportParquet_Decoded(t *testing.T) {
+	// The table TestExportCSV expects, as Parquet
+	header := []string{"id", "timestamp", "type", "actor", "rig", "role", "significance", "visibility", "bead", "branch", "attempts"}
+	rows := [][]string{
+		{"1", "2026-01-01T00:00:00Z", "merge_failed", "gastown/refinery", "gastown", "refinery", "high", "", "", "polecat/nux", "2"},
+		{"beads:2", "2026-01-01T00:01:00Z", "sling", "mayor", "beads", "mayor", "medium", "feed", "gt-1", "", ""},
+	}
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatParquet, exportRecords(), []string{"bead", "branch", "attempts", "rig"}, nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	data := buf.Bytes()
+	meta := decodeParquet(t, data)
+
+	if meta[1] != int64(1) || meta[3] != int64(len(rows)) || meta[6] != "gastown" {
+		t.Errorf("version, num_rows, created_by = %v, %v, %v; want 1, %d, gastown", meta[1], meta[3], meta[6], len(rows))
+	}
+
+	// Schema: a root with one required UTF-8 byte array per column
+	schema, _ := meta[2].([]interface{})
+	if len(schema) != len(header)+1 {
+		t.Fatalf("schema has %d elements, want %d", len(schema), len(header)+1)
+	}
+	if root := schema[0].(decodedStruct); root[4] != "schema" || root[5] != int64(len(header)) {
+		t.Errorf("schema root = %v, want %d children", root, len(header))
+	}
+	for i, name := range header {
+		el := schema[i+1].(decodedStruct)
+		if el[1] != int64(parquetTypeByteArray) || el[3] != int64(parquetRequired) || el[4] != name || el[6] != int64(parquetConvertedUTF8) {
+			t.Errorf("schema element %d = %v, want required UTF8 byte array %q", i+1, el, name)
+		}
+	}
+
+	groups, _ := meta[4].([]interface{})
+	if len(groups) != 1 {
+		t.Fatalf("%d row groups, want 1", len(groups))
+	}
+	group := groups[0].(decodedStruct)
+	columns, _ := group[1].([]interface{})
+	if len(columns) != len(header) || group[3] != int64(len(rows)) {
+		t.Fatalf("row group has %d columns, %v rows; want %d, %d", len(columns), group[3], len(header), len(rows))
+	}
+
+	var total int64
+	for i, name := range header {
+		chunk := columns[i].(decodedStruct)
+		md, _ := chunk[3].(decodedStruct)
+		if md == nil {
+			t.Fatalf("column %q has no meta_data", name)
+		}
+		path, _ := md[3].([]interface{})
+		encodings, _ := md[2].([]interface{})
+		if md[1] != int64(parquetTypeByteArray) || len(path) != 1 || path[0] != name ||
+			len(encodings) != 1 || encodings[0] != int64(parquetEncodingPlain) ||
+			md[4] != int64(parquetCodecUncompressed) || md[5] != int64(len(rows)) {
+			t.Errorf("column %q meta_data = %v", name, md)
+		}
+		offset, _ := md[9].(int64)
+		size, _ := md[7].(int64)
+		if chunk[2] != offset || md[6] != size {
+			t.Errorf("column %q: file_offset %v, data_page_offset %d, sizes %v/%d disagree", name, chunk[2], offset, md[6], size)
+		}
+		total += size
+
+		// The data page: a header, then the PLAIN values
+		r := &thriftReader{data: data[offset : offset+size]}
+		page := r.readStruct()
+		if r.err != nil {
+			t.Fatalf("column %q: decoding page header: %v", name, r.err)
+		}
+		dph, _ := page[5].(decodedStruct)
+		if page[1] != int64(parquetDataPage) || page[2] != page[3] || dph == nil ||
+			dph[1] != int64(len(rows)) || dph[2] != int64(parquetEncodingPlain) {
+			t.Fatalf("column %q page header = %v", name, page)
+		}
+		values := data[offset+int64(r.pos) : offset+size]
+		if int64(len(values)) != page[3].(int64) {
+			t.Errorf("column %q: page holds %d bytes, header says %v", name, len(values), page[3])
+		}
+		for row := range rows {
+			if len(values) < 4 {
+				t.Fatalf("column %q: value %d truncated", name, row)
+			}
+			n := int(binary.LittleEndian.Uint32(values))
+			if len(values) < 4+n {
+				t.Fatalf("column %q: value %d truncated", name, row)
+			}
+			if got := string(values[4 : 4+n]); got != rows[row][i] {
+				t.Errorf("column %q row %d = %q, want %q", name, row, got, rows[row][i])
+			}
+			values = values[4+n:]
+		}
+		if len(values) != 0 {
+			t.Errorf("column %q: %d bytes after its values", name, len(values))
+		}
+	}
+	if group[2] != total {
+		t.Errorf("row group total_byte_size = %v, want %d", group[2], total)
+	}
+}
+
+func TestExportParquet_DecodedEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatParquet, nil, DefaultExportFields, nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	meta := decodeParquet(t, buf.Bytes())
+	if groups, ok := meta[4].([]interface{}); !ok || len(groups) != 0 || meta[3] != int64(0) {
+		t.Errorf("empty export: row_groups = %v, num_rows = %v; want none", meta[4], meta[3])
+	}
+}
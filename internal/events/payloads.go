@@ -0,0 +1,243 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxPayloadFiles caps the file list carried by a merge event; FilesChanged
+// still counts every file.
+const MaxPayloadFiles = 50
+
+// MergeInfo describes a refinery merge attempt for merge_started, merged,
+// merge_failed and merge_skipped events. Consumers such as changelogs and
+// the audit trail read what changed from it, not just that a merge happened.
+type MergeInfo struct {
+	Rig         string `json:"rig,omitempty"`
+	MR          string `json:"mr,omitempty"`           // merge-request bead ID
+	Branch      string `json:"branch,omitempty"`       // source branch
+	Target      string `json:"target,omitempty"`       // target branch
+	SourceIssue string `json:"source_issue,omitempty"` // work item being merged
+	Worker      string `json:"worker,omitempty"`       // who did the work
+
+	MergeCommit  string   `json:"merge_commit,omitempty"`
+	Commits      []string `json:"commits,omitempty"`       // branch commit SHAs, oldest first
+	Files        []string `json:"files,omitempty"`         // files touched (capped at MaxPayloadFiles)
+	FilesChanged int      `json:"files_changed,omitempty"` // total files touched
+	Insertions   int      `json:"insertions,omitempty"`
+	Deletions    int      `json:"deletions,omitempty"`
+
+	FailureType string `json:"failure_type,omitempty"` // conflict, tests, build
+	Reason      string `json:"reason,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// Map converts the payload to the generic form stored on an Event.
+func (p MergeInfo) Map() map[string]interface{} {
+	if len(p.Files) > MaxPayloadFiles {
+		p.Files = p.Files[:MaxPayloadFiles]
+	}
+	return toPayloadMap(p)
+}
+
+// Diffstat returns a short change summary like "3 files, +40/-2", or "" if
+// the payload carries no diffstat.
+func (p MergeInfo) Diffstat() string {
+	if p.FilesChanged == 0 {
+		return ""
+	}
+	files := "files"
+	if p.FilesChanged == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s, +%d/-%d", p.FilesChanged, files, p.Insertions, p.Deletions)
+}
+
+// DecodeMergeInfo reads a merge event's payload back into its typed form.
+// Fields missing from older events are left zero.
+func DecodeMergeInfo(payload map[string]interface{}) MergeInfo {
+	var p MergeInfo
+	if data, err := json.Marshal(payload); err == nil {
+		_ = json.Unmarshal(data, &p)
+	}
+	return p
+}
+
+// toPayloadMap converts a payload struct to a map via its JSON form, so maps
+// built here match what readers decode from the log.
+func toPayloadMap(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// SlingInfo is the payload of sling events.
+type SlingInfo struct {
+	Bead    string `json:"bead"`
+	Target  string `json:"target"`
+	Formula string `json:"formula,omitempty"` // set when a formula was slung
+}
+
+func (p SlingInfo) validate() error {
+	return requireFields("bead", p.Bead, "target", p.Target)
+}
+
+// HookInfo is the payload of hook and unhook events.
+type HookInfo struct {
+	Bead string `json:"bead"`
+}
+
+func (p HookInfo) validate() error {
+	return requireFields("bead", p.Bead)
+}
+
+// DoneInfo is the payload of done events.
+type DoneInfo struct {
+	Bead   string `json:"bead"`
+	Branch string `json:"branch"`
+}
+
+func (p DoneInfo) validate() error { return nil }
+
+// MailInfo is the payload of mail events.
+type MailInfo struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+}
+
+func (p MailInfo) validate() error {
+	return requireFields("to", p.To)
+}
+
+// SpawnInfo is the payload of spawn events.
+type SpawnInfo struct {
+	Rig     string `json:"rig"`
+	Polecat string `json:"polecat"`
+}
+
+func (p SpawnInfo) validate() error {
+	return requireFields("rig", p.Rig, "polecat", p.Polecat)
+}
+
+// TargetInfo is the payload of nudge and kill events.
+type TargetInfo struct {
+	Rig    string `json:"rig"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+func (p TargetInfo) validate() error {
+	return requireFields("target", p.Target)
+}
+
+// SessionDeathInfo is the payload of session_death events.
+type SessionDeathInfo struct {
+	Session string `json:"session"`
+	Agent   string `json:"agent"`
+	Reason  string `json:"reason"`
+	Caller  string `json:"caller"`
+}
+
+func (p SessionDeathInfo) validate() error {
+	return requireFields("session", p.Session)
+}
+
+// RigHealthInfo is the payload of rig_health_changed events.
+type RigHealthInfo struct {
+	Rig           string `json:"rig"`
+	Score         int    `json:"score"`
+	Grade         string `json:"grade"`
+	PreviousScore int    `json:"previous_score"`
+	PreviousGrade string `json:"previous_grade"`
+	Trend         string `json:"trend"`
+}
+
+func (p RigHealthInfo) validate() error {
+	return requireFields("rig", p.Rig, "grade", p.Grade)
+}
+
+func (p MergeInfo) validate() error { return nil }
+
+// payloadValidator is implemented by the typed payloads checked at write
+// time.
+type payloadValidator interface {
+	validate() error
+}
+
+// typedPayloads maps event types to a constructor for their typed payload.
+// Event types not listed here carry free-form payloads.
+var typedPayloads = map[string]func() payloadValidator{
+	TypeSling:            func() payloadValidator { return &SlingInfo{} },
+	TypeHook:             func() payloadValidator { return &HookInfo{} },
+	TypeUnhook:           func() payloadValidator { return &HookInfo{} },
+	TypeDone:             func() payloadValidator { return &DoneInfo{} },
+	TypeMail:             func() payloadValidator { return &MailInfo{} },
+	TypeSpawn:            func() payloadValidator { return &SpawnInfo{} },
+	TypeNudge:            func() payloadValidator { return &TargetInfo{} },
+	TypeKill:             func() payloadValidator { return &TargetInfo{} },
+	TypeSessionDeath:     func() payloadValidator { return &SessionDeathInfo{} },
+	TypeRigHealthChanged: func() payloadValidator { return &RigHealthInfo{} },
+	TypeMergeStarted:     func() payloadValidator { return &MergeInfo{} },
+	TypeMerged:           func() payloadValidator { return &MergeInfo{} },
+	TypeMergeFailed:      func() payloadValidator { return &MergeInfo{} },
+	TypeMergeSkipped:     func() payloadValidator { return &MergeInfo{} },
+}
+
+// ErrInvalidEvent is returned for events rejected by Validate.
+var ErrInvalidEvent = errors.New("invalid event")
+
+// Decode reads the event's payload into a typed payload such as SlingInfo.
+// It fails when a field has the wrong JSON type; missing fields are left
+// zero.
+func (e Event) Decode(into interface{}) error {
+	data, err := json.Marshal(e.Payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s payload: %w", e.Type, err)
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("decoding %s payload: %w", e.Type, err)
+	}
+	return nil
+}
+
+// Validate checks an event before it is written: it needs a type and an
+// RFC 3339 timestamp, and event types with a typed payload must decode into
+// it with their required fields set. Errors wrap ErrInvalidEvent.
+func Validate(e Event) error {
+	if e.Type == "" {
+		return fmt.Errorf("%w: missing type", ErrInvalidEvent)
+	}
+	if _, err := time.Parse(time.RFC3339, e.Timestamp); err != nil {
+		return fmt.Errorf("%w: %s timestamp %q is not RFC 3339", ErrInvalidEvent, e.Type, e.Timestamp)
+	}
+	newPayload, ok := typedPayloads[e.Type]
+	if !ok {
+		return nil
+	}
+	p := newPayload()
+	if err := e.Decode(p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if err := p.validate(); err != nil {
+		return fmt.Errorf("%w: %s payload %v", ErrInvalidEvent, e.Type, err)
+	}
+	return nil
+}
+
+// requireFields takes name/value pairs and reports the first empty value.
+func requireFields(pairs ...string) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			return fmt.Errorf("missing %s", pairs[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMergeInfoRoundTrip(t *testing.T) {
+	var files []string
+	for i := 0; i < MaxPayloadFiles+5; i++ {
+		files = append(files, fmt.Sprintf("file%d.go", i))
+	}
+	info := MergeInfo{
+		MR:           "gt-mr1",
+		Branch:       "polecat/Toast",
+		Worker:       "Toast",
+		MergeCommit:  "abc123",
+		Commits:      []string{"c1", "c2"},
+		Files:        files,
+		FilesChanged: len(files),
+		Insertions:   40,
+		Deletions:    2,
+	}
+
+	payload := info.Map()
+	if payload["mr"] != "gt-mr1" || payload["branch"] != "polecat/Toast" {
+		t.Errorf("payload keys = %v, want mr and branch like MergePayload", payload)
+	}
+
+	decoded := DecodeMergeInfo(payload)
+	if len(decoded.Files) != MaxPayloadFiles {
+		t.Errorf("Files = %d, want capped at %d", len(decoded.Files), MaxPayloadFiles)
+	}
+	if decoded.FilesChanged != len(files) || len(decoded.Commits) != 2 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if got, want := decoded.Diffstat(), "55 files, +40/-2"; got != want {
+		t.Errorf("Diffstat = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeMergeInfo_LegacyPayload(t *testing.T) {
+	decoded := DecodeMergeInfo(MergePayload("gt-mr1", "Toast", "polecat/Toast", "superseded"))
+	if decoded.MR != "gt-mr1" || decoded.Reason != "superseded" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if decoded.Diffstat() != "" {
+		t.Errorf("Diffstat = %q, want empty for legacy payload", decoded.Diffstat())
+	}
+}
+
+func TestEventDecode(t *testing.T) {
+	e := Event{Type: TypeSling, Payload: SlingPayload("gt-abc", "gastown/Toast")}
+	var p SlingInfo
+	if err := e.Decode(&p); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if p.Bead != "gt-abc" || p.Target != "gastown/Toast" {
+		t.Errorf("decoded = %+v", p)
+	}
+
+	bad := Event{Type: TypeRigHealthChanged, Payload: map[string]interface{}{"score": "high"}}
+	var h RigHealthInfo
+	if err := bad.Decode(&h); err == nil {
+		t.Error("Decode accepted a string score")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ts := "2026-01-10T12:00:00Z"
+	tests := []struct {
+		name  string
+		event Event
+		ok    bool
+	}{
+		{"sling", Event{Timestamp: ts, Type: TypeSling, Payload: SlingPayload("gt-abc", "gastown/Toast")}, true},
+		{"formula sling", Event{Timestamp: ts, Type: TypeSling, Payload: map[string]interface{}{"bead": "gt-w", "target": "mayor", "formula": "patrol"}}, true},
+		{"untyped event", Event{Timestamp: ts, Type: "custom", Payload: map[string]interface{}{"anything": 1}}, true},
+		{"merge without mr", Event{Timestamp: ts, Type: TypeMerged, Payload: MergeInfo{Branch: "b"}.Map()}, true},
+		{"missing type", Event{Timestamp: ts}, false},
+		{"bad timestamp", Event{Timestamp: "yesterday", Type: TypeHalt}, false},
+		{"sling missing target", Event{Timestamp: ts, Type: TypeSling, Payload: SlingPayload("gt-abc", "")}, false},
+		{"spawn without payload", Event{Timestamp: ts, Type: TypeSpawn}, false},
+		{"wrong field type", Event{Timestamp: ts, Type: TypeRigHealthChanged, Payload: map[string]interface{}{"rig": "gastown", "grade": "healthy", "score": "90"}}, false},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.event)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Validate = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%s: error %v does not wrap ErrInvalidEvent", tt.name, err)
+		}
+	}
+}
+
+func TestAppendRejectsInvalidEvent(t *testing.T) {
+	town := t.TempDir()
+	err := Append(town, Event{Timestamp: "2026-01-10T12:00:00Z", Type: TypeMail})
+	if !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("Append = %v, want ErrInvalidEvent", err)
+	}
+	records, err := ReadAll(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("invalid event was written: %+v", records)
+	}
+}
@@ -0,0 +1,270 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Webhook request headers.
+const (
+	HeaderEvent     = "X-Gastown-Event"     // event type
+	HeaderSignature = "X-Gastown-Signature" // "sha256=<hex HMAC of body>"
+)
+
+// DefaultWebhookRetries is how often a failed delivery is retried when the
+// sink does not set max_retries.
+const DefaultWebhookRetries = 3
+
+// Publisher follows the events log and POSTs matching events to the
+// webhooks configured in town settings, so bots and dashboards can react
+// without polling the log themselves.
+type Publisher struct {
+	townRoot string
+	sinks    []config.EventWebhook
+	rules    *SignificanceRules
+	policy   *Policy
+	client   *http.Client
+	logger   func(format string, args ...interface{})
+	backoff  time.Duration // delay before the first retry, doubled after each
+	seen     *Deduper      // drops repeated events before they go out again
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewPublisher creates a publisher for a town's configured webhooks.
+func NewPublisher(townRoot string, logger func(format string, args ...interface{})) *Publisher {
+	ctx, cancel := context.WithCancel(context.Background())
+	rules, err := LoadSignificanceRules(townRoot)
+	if err != nil {
+		logger("Warning: %v; using default significance", err)
+		rules = DefaultSignificanceRules()
+	}
+	policy, err := LoadPolicy(townRoot)
+	if err != nil {
+		logger("Warning: %v; using default redaction", err)
+		policy, _ = NewPolicy(nil)
+	}
+	return &Publisher{
+		townRoot: townRoot,
+		sinks:    loadConfig(townRoot).Webhooks,
+		rules:    rules,
+		policy:   policy,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		logger:   logger,
+		backoff:  time.Second,
+		seen:     NewDeduper(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Enabled reports whether any webhooks are configured.
+func (p *Publisher) Enabled() bool {
+	return len(p.sinks) > 0
+}
+
+// Start begins following the events log. Only events written after Start
+// are published.
+func (p *Publisher) Start() error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	tailer, err := NewTailer(p.townRoot)
+	if err != nil {
+		return fmt.Errorf("opening events file: %w", err)
+	}
+
+	p.wg.Add(1)
+	go p.run(tailer)
+	return nil
+}
+
+// StartFrom is Start with new events read from src, such as an event
+// broker's tap. src is closed on Stop.
+func (p *Publisher) StartFrom(src LineSource) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	p.wg.Add(1)
+	go p.run(src)
+	return nil
+}
+
+func (p *Publisher) validate() error {
+	for i, sink := range p.sinks {
+		if sink.URL == "" {
+			return fmt.Errorf("webhook %d: missing url", i+1)
+		}
+		if sink.Significance != "" {
+			if err := ValidSignificance(sink.Significance); err != nil {
+				return fmt.Errorf("webhook %s: %w", sink.URL, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Stop stops following the log and waits for in-flight deliveries, which
+// give up retrying once stopped.
+func (p *Publisher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Publisher) run(tailer LineSource) {
+	defer p.wg.Done()
+	defer tailer.Close()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, line := range tailer.Poll() {
+				p.publish(line)
+			}
+		}
+	}
+}
+
+// publish hands one log line, redacted for webhooks, to every sink that
+// wants it. Audit-only events are never published. Deliveries run
+// concurrently so a slow sink does not hold up the others.
+func (p *Publisher) publish(line string) {
+	var e Event
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return
+	}
+	if p.seen.Seen(e) {
+		return
+	}
+	exposed, ok := p.policy.Expose(e, AudienceWebhook)
+	if !ok {
+		return
+	}
+	body, err := json.Marshal(exposed)
+	if err != nil {
+		return
+	}
+	for _, sink := range p.sinks {
+		if !p.wants(sink, e) {
+			continue
+		}
+		p.wg.Add(1)
+		go func(sink config.EventWebhook) {
+			defer p.wg.Done()
+			if err := p.deliver(sink, e.Type, body); err != nil {
+				p.logger("Webhook %s: dropping %s event: %v", sink.URL, e.Type, err)
+			}
+		}(sink)
+	}
+}
+
+// wants reports whether a sink subscribes to an event. A sink with neither
+// types nor significance set receives high-significance events.
+func (p *Publisher) wants(sink config.EventWebhook, e Event) bool {
+	if len(sink.Types) > 0 {
+		found := false
+		for _, t := range sink.Types {
+			if t == e.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	level := sink.Significance
+	if level == "" {
+		if len(sink.Types) > 0 {
+			return true
+		}
+		level = SignificanceHigh
+	}
+	return p.rules.AtLeast(e, level)
+}
+
+// deliver POSTs body to a sink, retrying network errors, 429s and 5xx
+// responses with exponential backoff.
+func (p *Publisher) deliver(sink config.EventWebhook, eventType string, body []byte) error {
+	retries := sink.MaxRetries
+	if retries <= 0 {
+		retries = DefaultWebhookRetries
+	}
+	secret := sink.Secret
+	if sink.SecretEnv != "" {
+		secret = os.Getenv(sink.SecretEnv)
+	}
+
+	delay := p.backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = p.post(sink.URL, eventType, secret, body)
+		if err == nil || !retry || attempt >= retries {
+			return err
+		}
+		select {
+		case <-p.ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post sends one delivery attempt and reports whether a failure is worth
+// retrying.
+func (p *Publisher) post(url, eventType, secret string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, eventType)
+	if secret != "" {
+		req.Header.Set(HeaderSignature, "sha256="+Sign(secret, body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("status %s", resp.Status)
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of body under secret, as sent in the
+// X-Gastown-Signature header. Receivers recompute it to authenticate
+// deliveries.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// webhookRecorder is a test sink that records deliveries and fails the
+// first `fail` requests with a 500.
+type webhookRecorder struct {
+	mu       sync.Mutex
+	fail     int
+	attempts int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (w *webhookRecorder) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.attempts++
+	if w.attempts <= w.fail {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.bodies = append(w.bodies, body)
+	w.headers = append(w.headers, r.Header.Clone())
+}
+
+func newTestPublisher(t *testing.T, sinks ...config.EventWebhook) *Publisher {
+	t.Helper()
+	p := NewPublisher(t.TempDir(), t.Logf)
+	p.sinks = sinks
+	p.backoff = time.Millisecond
+	return p
+}
+
+func TestPublisher_SignsAndRetries(t *testing.T) {
+	rec := &webhookRecorder{fail: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	p := newTestPublisher(t, config.EventWebhook{URL: srv.URL, Secret: "s3cret"})
+	p.publish(`{"ts":"2026-01-01T00:00:00Z","type":"session_death","actor":"gastown/witness"}`)
+	p.wg.Wait()
+
+	if rec.attempts != 3 || len(rec.bodies) != 1 {
+		t.Fatalf("attempts = %d, deliveries = %d; want 3 attempts, 1 delivery", rec.attempts, len(rec.bodies))
+	}
+	body, h := rec.bodies[0], rec.headers[0]
+	if got, want := h.Get(HeaderSignature), "sha256="+Sign("s3cret", body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if got := h.Get(HeaderEvent); got != TypeSessionDeath {
+		t.Errorf("event header = %q, want %q", got, TypeSessionDeath)
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil || e.Actor != "gastown/witness" {
+		t.Errorf("body = %s (err %v), want the event", body, err)
+	}
+}
+
+func TestPublisher_GivesUpOnClientErrors(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	p := newTestPublisher(t, config.EventWebhook{URL: srv.URL})
+	p.publish(`{"ts":"2026-01-01T00:00:00Z","type":"halt","actor":"mayor"}`)
+	p.wg.Wait()
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (4xx is not retried)", attempts)
+	}
+}
+
+func TestPublisher_Wants(t *testing.T) {
+	p := newTestPublisher(t)
+	death := Event{Type: TypeSessionDeath}
+	sling := Event{Type: TypeSling}
+	nudge := Event{Type: TypeNudge}
+
+	tests := []struct {
+		name  string
+		sink  config.EventWebhook
+		event Event
+		want  bool
+	}{
+		{"default high", config.EventWebhook{}, death, true},
+		{"default skips medium", config.EventWebhook{}, sling, false},
+		{"medium threshold", config.EventWebhook{Significance: SignificanceMedium}, sling, true},
+		{"type listed", config.EventWebhook{Types: []string{TypeNudge}}, nudge, true},
+		{"type not listed", config.EventWebhook{Types: []string{TypeNudge}}, death, false},
+		{"type and threshold", config.EventWebhook{Types: []string{TypeNudge}, Significance: SignificanceHigh}, nudge, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.wants(tt.sink, tt.event); got != tt.want {
+				t.Errorf("wants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Record is an event read back from the events log.
+// Seq is the event's 1-based line number in the file it was read from, and
+// Shard names that file ("" for .events.jsonl, otherwise the rig shard).
+// Logs are append-only, so ID() is a stable way for operators to refer to an
+// event.
+type Record struct {
+	Seq   int    `json:"seq"`
+	Shard string `json:"shard,omitempty"`
+	Event
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// ID returns the record's event ID: "<seq>" for the town-wide log and
+// "<shard>:<seq>" for a rig shard.
+func (r Record) ID() string {
+	return EventID(r.Shard, r.Seq)
+}
+
+// EventID formats an event ID from a shard and sequence number.
+func EventID(shard string, seq int) string {
+	if shard == "" {
+		return strconv.Itoa(seq)
+	}
+	return fmt.Sprintf("%s:%d", shard, seq)
+}
+
+// ParseEventID parses an event ID of the form "<seq>" or "<shard>:<seq>".
+func ParseEventID(id string) (shard string, seq int, err error) {
+	seqStr := id
+	if i := strings.LastIndex(id, ":"); i >= 0 {
+		shard, seqStr = id[:i], id[i+1:]
+		if !shardNameRe.MatchString(shard) {
+			return "", 0, fmt.Errorf("invalid event ID %q", id)
+		}
+	}
+	seq, err = strconv.Atoi(seqStr)
+	if err != nil || seq <= 0 {
+		return "", 0, fmt.Errorf("invalid event ID %q", id)
+	}
+	return shard, seq, nil
+}
+
+// ReadAll reads every event from the town's events log and any per-rig
+// shards, merged in timestamp order, with human annotations attached.
+// Repeats of an event already logged are dropped (see Dedupe).
+// Malformed lines are skipped but still consume a sequence number so Seq
+// always matches the line number.
+// Returns an empty slice if no log exists yet.
+func ReadAll(townRoot string) ([]Record, error) {
+	files := logFiles(townRoot)
+	shards := make([]string, 0, len(files))
+	for shard := range files {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	records := []Record{}
+	for _, shard := range shards {
+		shardRecords, err := readRecords(townRoot, files[shard])
+		if err != nil {
+			return nil, err
+		}
+		for i := range shardRecords {
+			shardRecords[i].Shard = shard
+		}
+		records = append(records, shardRecords...)
+	}
+
+	// Sort on the instant, not the timestamp text: imported events may
+	// carry other offsets. Events at the same instant keep their shard and
+	// line order.
+	sortByTime(records)
+	records = Dedupe(records)
+
+	annotations, err := LoadAnnotations(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		records[i].Annotations = annotations[records[i].ID()]
+	}
+
+	return records, nil
+}
+
+// QueryFile returns the events in a JSONL file outside the town's logs,
+// such as an archive or another town's log, that match f, in timestamp
+// order. Sealed lines are decrypted with townRoot's key. Annotations, which
+// refer to the town's own logs, are not attached.
+func QueryFile(townRoot, path string, f Filter) ([]Record, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path) //nolint:gosec // G304: path is given by the operator
+	if err != nil {
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer file.Close()
+	records, _, err := scanRecords(townRoot, file)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp < records[j].Timestamp
+	})
+	matched := []Record{}
+	for _, r := range Dedupe(records) {
+		if f.Match(r.Event) {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+// readRecords parses a JSONL events file into records numbered by line,
+// decrypting sealed lines with the town key. Lines that can't be decrypted
+// are skipped like malformed ones.
+func readRecords(townRoot, path string) ([]Record, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Record{}, nil
+		}
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	records, _, err := scanRecords(townRoot, f)
+	return records, err
+}
+
+// scanRecords parses JSONL events from r into records numbered by line,
+// and returns the number of lines read.
+func scanRecords(townRoot string, r io.Reader) ([]Record, int, error) {
+	records := []Record{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	dec := NewDecoder(townRoot)
+	seq := 0
+	for scanner.Scan() {
+		seq++
+		event, err := dec.Parse(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		records = append(records, Record{Seq: seq, Event: event})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("reading events file: %w", err)
+	}
+
+	return records, seq, nil
+}
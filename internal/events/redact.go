@@ -0,0 +1,232 @@
+package events
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Audiences events are exposed to. The audit audience reads the log as
+// written; the others see events through the town's redaction policy.
+const (
+	AudienceAudit    = "audit"    // gt events --audit
+	AudienceFeed     = "feed"     // gt events query and the curated feed
+	AudienceNarrator = "narrator" // narration prompts and catch-up chapters
+	AudienceWebhook  = "webhook"  // webhook deliveries
+)
+
+// Redacted replaces text matched by a redaction rule's pattern.
+const Redacted = "[redacted]"
+
+// DefaultRedactRules returns the built-in redaction rules, used when the
+// town sets none: credentials are stripped for every audience.
+func DefaultRedactRules() []config.EventRedactRule {
+	return []config.EventRedactRule{
+		{Keys: []string{"*token*", "*secret*", "*password*", "*api_key*", "authorization"}},
+	}
+}
+
+// Policy decides what each audience sees of an event: audit-only events are
+// hidden from all but the audit audience, and payloads are redacted by the
+// town's rules. The log itself is never changed.
+type Policy struct {
+	rules []redactRule
+}
+
+type redactRule struct {
+	keys      []string
+	match     *regexp.Regexp
+	types     []string
+	audiences []string
+}
+
+// NewPolicy compiles redaction rules into a policy. Nil rules use
+// DefaultRedactRules; an empty list redacts nothing.
+func NewPolicy(rules []config.EventRedactRule) (*Policy, error) {
+	if rules == nil {
+		rules = DefaultRedactRules()
+	}
+	p := &Policy{}
+	for i, r := range rules {
+		if len(r.Keys) == 0 && r.Match == "" {
+			return nil, fmt.Errorf("redact rule %d: needs keys or match", i+1)
+		}
+		rule := redactRule{types: r.Types, audiences: r.Audiences}
+		for _, k := range r.Keys {
+			k = strings.ToLower(k)
+			if k == "" || !validGlob(k) {
+				return nil, fmt.Errorf("redact rule %d: invalid key pattern %q", i+1, k)
+			}
+			rule.keys = append(rule.keys, k)
+		}
+		for _, t := range r.Types {
+			if !validGlob(t) {
+				return nil, fmt.Errorf("redact rule %d: invalid type pattern %q", i+1, t)
+			}
+		}
+		for _, a := range r.Audiences {
+			switch a {
+			case AudienceFeed, AudienceNarrator, AudienceWebhook:
+			default:
+				return nil, fmt.Errorf("redact rule %d: invalid audience %q (want feed, narrator or webhook)", i+1, a)
+			}
+		}
+		if r.Match != "" {
+			re, err := regexp.Compile(r.Match)
+			if err != nil {
+				return nil, fmt.Errorf("redact rule %d: invalid match: %w", i+1, err)
+			}
+			rule.match = re
+		}
+		p.rules = append(p.rules, rule)
+	}
+	return p, nil
+}
+
+// LoadPolicy returns the redaction policy in a town's settings.
+func LoadPolicy(townRoot string) (*Policy, error) {
+	p, err := NewPolicy(loadConfig(townRoot).Redact)
+	if err != nil {
+		return nil, fmt.Errorf("events.redact in settings/config.json: %w", err)
+	}
+	return p, nil
+}
+
+// Visible reports whether an audience may see an event at all. Audit-only
+// events are seen only by the audit audience.
+func (p *Policy) Visible(e Event, audience string) bool {
+	return audience == AudienceAudit || e.Visibility != VisibilityAudit
+}
+
+// Redact returns the event as an audience sees it. The payload is copied
+// before redaction, so e is left intact; events the rules don't touch are
+// returned as-is.
+func (p *Policy) Redact(e Event, audience string) Event {
+	if audience == AudienceAudit || p == nil {
+		return e
+	}
+	var applicable []redactRule
+	for _, r := range p.rules {
+		if r.appliesTo(e.Type, audience) {
+			applicable = append(applicable, r)
+		}
+	}
+	if len(applicable) == 0 || len(e.Payload) == 0 {
+		return e
+	}
+	payload, changed := redactValue(e.Payload, applicable)
+	if changed {
+		e.Payload = payload.(map[string]interface{})
+	}
+	return e
+}
+
+// Expose returns an event as an audience sees it, and false if the audience
+// may not see it.
+func (p *Policy) Expose(e Event, audience string) (Event, bool) {
+	if !p.Visible(e, audience) {
+		return Event{}, false
+	}
+	return p.Redact(e, audience), true
+}
+
+func (r redactRule) appliesTo(eventType, audience string) bool {
+	if len(r.audiences) > 0 && !contains(r.audiences, audience) {
+		return false
+	}
+	if len(r.types) == 0 {
+		return true
+	}
+	for _, t := range r.types {
+		if globMatch(t, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r redactRule) stripsKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if globMatch(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue applies rules to a payload value, copying maps and slices on
+// the way down only where something changes.
+func redactValue(v interface{}, rules []redactRule) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var out map[string]interface{}
+		for key, val := range v {
+			strip := false
+			for _, r := range rules {
+				if r.stripsKey(key) {
+					strip = true
+					break
+				}
+			}
+			newVal, changed := val, false
+			if !strip {
+				newVal, changed = redactValue(val, rules)
+			}
+			if !strip && !changed {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]interface{}, len(v))
+				for k, orig := range v {
+					out[k] = orig
+				}
+			}
+			if strip {
+				delete(out, key)
+			} else {
+				out[key] = newVal
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []interface{}:
+		var out []interface{}
+		for i, val := range v {
+			newVal, changed := redactValue(val, rules)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = append([]interface{}(nil), v...)
+			}
+			out[i] = newVal
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case string:
+		s := v
+		for _, r := range rules {
+			if r.match != nil {
+				s = r.match.ReplaceAllString(s, Redacted)
+			}
+		}
+		return s, s != v
+	}
+	return v, false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
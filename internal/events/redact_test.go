@@ -0,0 +1,113 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPolicy_Redact(t *testing.T) {
+	p, err := NewPolicy([]config.EventRedactRule{
+		{Keys: []string{"*token*", "Authorization"}},
+		{Keys: []string{"path"}, Audiences: []string{AudienceWebhook}},
+		{Match: `[\w.]+@[\w.]+`, Types: []string{"mail*"}},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	e := Event{Type: TypeMail, Payload: map[string]interface{}{
+		"to":   "max@example.com",
+		"path": "/home/max/secret.txt",
+		"auth": map[string]interface{}{"GitHubToken": "ghp_x", "authorization": "Bearer y", "scope": "repo"},
+		"cc":   []interface{}{"ann@example.com", "mayor/"},
+	}}
+	orig, _ := json.Marshal(e.Payload)
+
+	got := p.Redact(e, AudienceWebhook).Payload
+	want := map[string]interface{}{
+		"to":   Redacted,
+		"auth": map[string]interface{}{"scope": "repo"},
+		"cc":   []interface{}{Redacted, "mayor/"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("webhook payload = %v, want %v", got, want)
+	}
+	if got := p.Redact(e, AudienceNarrator).Payload; got["path"] == nil {
+		t.Errorf("narrator payload = %v, want path kept (rule is webhook-only)", got)
+	}
+	if got := p.Redact(e, AudienceAudit).Payload; !reflect.DeepEqual(got, e.Payload) {
+		t.Errorf("audit payload = %v, want it untouched", got)
+	}
+	if after, _ := json.Marshal(e.Payload); string(after) != string(orig) {
+		t.Errorf("Redact changed the original payload: %s", after)
+	}
+
+	sling := Event{Type: TypeSling, Payload: map[string]interface{}{"to": "max@example.com"}}
+	if got := p.Redact(sling, AudienceFeed).Payload["to"]; got != "max@example.com" {
+		t.Errorf("mail-only pattern applied to a sling: %v", got)
+	}
+}
+
+func TestPolicy_Visible(t *testing.T) {
+	p, _ := NewPolicy(nil)
+	denied := Event{Type: TypeAuthzDenied, Visibility: VisibilityAudit}
+	for _, audience := range []string{AudienceFeed, AudienceNarrator, AudienceWebhook} {
+		if _, ok := p.Expose(denied, audience); ok {
+			t.Errorf("audit-only event exposed to %s", audience)
+		}
+	}
+	if _, ok := p.Expose(denied, AudienceAudit); !ok {
+		t.Error("audit-only event hidden from the audit stream")
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	p, err := NewPolicy(nil)
+	if err != nil {
+		t.Fatalf("NewPolicy(nil): %v", err)
+	}
+	e := Event{Payload: map[string]interface{}{"api_key": "k", "bead": "gt-1"}}
+	if got := p.Redact(e, AudienceFeed).Payload; len(got) != 1 || got["bead"] != "gt-1" {
+		t.Errorf("default rules left %v, want only bead", got)
+	}
+	if p, _ := NewPolicy([]config.EventRedactRule{}); len(p.Redact(e, AudienceFeed).Payload) != 2 {
+		t.Error("an empty rule list redacted something")
+	}
+
+	for name, rules := range map[string][]config.EventRedactRule{
+		"empty rule":   {{}},
+		"bad key glob": {{Keys: []string{"[token"}}},
+		"bad match":    {{Match: "("}},
+		"bad audience": {{Keys: []string{"x"}, Audiences: []string{AudienceAudit}}},
+	} {
+		if _, err := NewPolicy(rules); err == nil {
+			t.Errorf("%s: NewPolicy succeeded, want an error", name)
+		}
+	}
+}
+
+func TestPublisher_Redacts(t *testing.T) {
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	p := newTestPublisher(t, config.EventWebhook{URL: srv.URL, Types: []string{TypeAuthzDenied, TypeHalt}})
+	p.publish(`{"ts":"2026-01-01T00:00:00Z","type":"authz_denied","actor":"x","visibility":"audit"}`)
+	p.publish(`{"ts":"2026-01-01T00:00:00Z","type":"halt","actor":"mayor","payload":{"token":"t","reason":"r"}}`)
+	p.wg.Wait()
+
+	if len(rec.bodies) != 1 {
+		t.Fatalf("deliveries = %d, want only the halt", len(rec.bodies))
+	}
+	var e Event
+	if err := json.Unmarshal(rec.bodies[0], &e); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := e.Payload["token"]; ok || e.Payload["reason"] != "r" {
+		t.Errorf("payload = %v, want token stripped", e.Payload)
+	}
+}
@@ -0,0 +1,261 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Rotator archives events logs that have grown past the town's configured
+// size or age (settings/config.json "events": {"rotate": {...}}). Rotation
+// moves a log's lines into a gzipped archive beside it —
+// .events.YYYYMMDD.jsonl.gz for the town-wide log and
+// .events/<rig>.YYYYMMDD.jsonl.gz for a shard — and starts the log afresh.
+// Annotated events stay in the live log so their annotations stay attached.
+//
+// Each rotation is recorded in the remap log like a prune, with the archive
+// it wrote, so ReadSince can replay a reader's old checkpoint through the
+// rotated segments.
+type Rotator struct {
+	townRoot string
+	maxSize  int64
+	maxAge   time.Duration
+}
+
+// NewRotator returns a rotator for the town's configured thresholds.
+func NewRotator(townRoot string) *Rotator {
+	r := &Rotator{townRoot: townRoot}
+	if rotate := loadConfig(townRoot).Rotate; rotate != nil {
+		r.maxSize = int64(rotate.MaxSizeMB) * 1024 * 1024
+		r.maxAge = rotate.GetMaxAge()
+	}
+	return r
+}
+
+// Enabled reports whether any rotation threshold is configured.
+func (r *Rotator) Enabled() bool {
+	return r.maxSize > 0 || r.maxAge > 0
+}
+
+// Due reports whether a shard's log ("" for the town-wide log) has reached
+// a rotation threshold.
+func (r *Rotator) Due(shard string, now time.Time) bool {
+	path := LogPath(r.townRoot, shard)
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	if r.maxSize > 0 && info.Size() >= r.maxSize {
+		return true
+	}
+	if r.maxAge > 0 {
+		if oldest, ok := r.oldestEvent(path); ok && now.Sub(oldest) >= r.maxAge {
+			return true
+		}
+	}
+	return false
+}
+
+// oldestEvent returns the timestamp of the first readable event in a log.
+func (r *Rotator) oldestEvent(path string) (time.Time, bool) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+
+	dec := NewDecoder(r.townRoot)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		event, err := dec.Parse(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		if ts, err := time.Parse(time.RFC3339, event.Timestamp); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Rotate archives every log that is due, or every non-empty log if force
+// is set, and returns what it rotated.
+func (r *Rotator) Rotate(now time.Time, force bool) ([]RewriteResult, error) {
+	annotations, err := LoadAnnotations(r.townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []RewriteResult
+	for _, shard := range append([]string{""}, Shards(r.townRoot)...) {
+		if !force && !r.Due(shard, now) {
+			continue
+		}
+		archive, err := archiveName(r.townRoot, shard, now)
+		if err != nil {
+			return results, err
+		}
+		result, err := rewriteLog(r.townRoot, shard, annotations, func(_ *Event, annotated bool) bool {
+			return annotated
+		}, archive)
+		if err != nil {
+			return results, err
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+	return results, nil
+}
+
+// archiveName returns an unused archive path, relative to the town root,
+// for a shard rotated on now's date.
+func archiveName(townRoot, shard string, now time.Time) (string, error) {
+	prefix := EventsFile[:len(EventsFile)-len(".jsonl")]
+	if shard != "" {
+		prefix = filepath.Join(ShardDir, shard)
+	}
+	date := now.UTC().Format("20060102")
+	for n := 1; n < 1000; n++ {
+		name := fmt.Sprintf("%s.%s.jsonl.gz", prefix, date)
+		if n > 1 {
+			name = fmt.Sprintf("%s.%s-%d.jsonl.gz", prefix, date, n)
+		}
+		if _, err := os.Stat(filepath.Join(townRoot, name)); os.IsNotExist(err) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("too many archives for %s on %s", prefix, date)
+}
+
+// writeArchive atomically writes data gzipped to path.
+func writeArchive(path string, data []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("compressing archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compressing archive: %w", err)
+	}
+	return replaceFile(path, buf.Bytes())
+}
+
+// readArchive returns the decompressed contents of an archive.
+func readArchive(path string) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path comes from the remap log under the town root
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive %s: %w", filepath.Base(path), err)
+	}
+	defer zr.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(zr); err != nil {
+		return nil, fmt.Errorf("reading archive %s: %w", filepath.Base(path), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadSince returns the events of a shard's log ("" for the town-wide log)
+// that a reader checkpointed at offset lines of generation gen has not yet
+// read, including events since rotated into archives, in timestamp order.
+// It also returns the checkpoint to resume from next time.
+//
+// Archived events keep the sequence number they had in the log before
+// rotation, so their IDs no longer resolve against the live log.
+func ReadSince(townRoot, shard string, gen, offset int) (records []Record, newGen, newOffset int, err error) {
+	remaps, err := loadRemaps(townRoot, shard)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	records = []Record{}
+	for _, r := range remaps {
+		if r.Generation <= gen {
+			continue
+		}
+		if r.Archive != "" {
+			archived, err := readArchivedSince(townRoot, shard, r, offset)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			records = append(records, archived...)
+		}
+		gen, offset = r.Generation, r.translate(offset)
+	}
+
+	f, err := os.Open(LogPath(townRoot, shard)) //nolint:gosec // G304: path is constructed from town root
+	lines := 0
+	if err == nil {
+		defer f.Close()
+		var live []Record
+		live, lines, err = scanRecords(townRoot, f)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for _, rec := range live {
+			if rec.Seq > offset {
+				records = append(records, rec)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, 0, 0, fmt.Errorf("opening events file: %w", err)
+	}
+
+	for i := range records {
+		records[i].Shard = shard
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp < records[j].Timestamp
+	})
+	return records, gen, lines, nil
+}
+
+// readArchivedSince returns the events a rotation archived whose sequence
+// number before the rotation is past offset.
+func readArchivedSince(townRoot, shard string, r Remap, offset int) ([]Record, error) {
+	data, err := readArchive(filepath.Join(townRoot, r.Archive))
+	if err != nil {
+		return nil, err
+	}
+	archived, _, err := scanRecords(townRoot, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Archive line k holds the k-th old sequence number not in Kept
+	kept := make(map[int]bool)
+	for _, rng := range r.Kept {
+		for s := rng[0]; s <= rng[1]; s++ {
+			kept[s] = true
+		}
+	}
+	var dropped []int
+	for s := 1; s <= r.OldLines; s++ {
+		if !kept[s] {
+			dropped = append(dropped, s)
+		}
+	}
+
+	var out []Record
+	for _, rec := range archived {
+		if rec.Seq > len(dropped) {
+			break
+		}
+		if oldSeq := dropped[rec.Seq-1]; oldSeq > offset {
+			rec.Seq = oldSeq
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
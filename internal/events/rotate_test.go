@@ -0,0 +1,126 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRotateSettings(t *testing.T, townRoot, rotate string) {
+	t.Helper()
+	settingsDir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type":"town-settings","version":1,"events":{"rotate":` + rotate + `}}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotator_Due(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`{"ts":"2026-01-03T00:00:00Z","type":"sling","actor":"mayor"}`,
+	)
+
+	if r := NewRotator(townRoot); r.Enabled() {
+		t.Error("rotator without settings should be disabled")
+	}
+
+	writeRotateSettings(t, townRoot, `{"max_age":"48h"}`)
+	r := NewRotator(townRoot)
+	if !r.Enabled() {
+		t.Fatal("rotator with max_age should be enabled")
+	}
+	if r.Due("", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Error("log younger than max_age should not be due")
+	}
+	if !r.Due("", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Error("log as old as max_age should be due")
+	}
+
+	writeRotateSettings(t, townRoot, `{"max_size_mb":1}`)
+	if NewRotator(townRoot).Due("", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Error("small log should not be due by size")
+	}
+}
+
+func TestRotator_RotateAndReadSince(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"sling","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:02:00Z","type":"done","actor":"gastown/nux"}`,
+		`{"ts":"2026-01-01T00:03:00Z","type":"merged","actor":"gastown/refinery"}`,
+	)
+	if _, err := Annotate(townRoot, "", 3, "keep me", ""); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	results, err := NewRotator(townRoot).Rotate(now, true)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if len(results) != 1 || results[0].Dropped != 3 || results[0].Kept != 1 {
+		t.Fatalf("results = %+v, want 3 archived, 1 kept", results)
+	}
+	if want := ".events.20260110.jsonl.gz"; results[0].Archive != want {
+		t.Errorf("archive = %q, want %q", results[0].Archive, want)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, results[0].Archive)); err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+
+	// The live log keeps the annotated event, still annotated
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != 1 || records[0].Type != "done" || len(records[0].Annotations) != 1 {
+		t.Fatalf("live log = %+v, want the annotated done event", records)
+	}
+
+	// A second rotation the same day gets its own archive
+	if err := appendLine(LogPath(townRoot, ""), []byte(`{"ts":"2026-01-10T00:00:00Z","type":"halt","actor":"mayor"}`+"\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	results, err = NewRotator(townRoot).Rotate(now, true)
+	if err != nil {
+		t.Fatalf("second Rotate: %v", err)
+	}
+	if len(results) != 1 || results[0].Archive != ".events.20260110-2.jsonl.gz" {
+		t.Fatalf("second rotation = %+v, want a -2 archive", results)
+	}
+
+	// A reader that had consumed the first event (generation 0) replays the
+	// rest across both archives and the live log
+	got, gen, offset, err := ReadSince(townRoot, "", 0, 1)
+	if err != nil {
+		t.Fatalf("ReadSince: %v", err)
+	}
+	var types []string
+	for _, r := range got {
+		types = append(types, r.Type)
+	}
+	want := []string{"sling", "done", "merged", "halt"}
+	if len(types) != len(want) {
+		t.Fatalf("ReadSince types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("ReadSince types = %v, want %v", types, want)
+		}
+	}
+	if gen != 2 || offset != 1 {
+		t.Errorf("checkpoint = generation %d offset %d, want 2, 1", gen, offset)
+	}
+
+	// Resuming from that checkpoint finds nothing new
+	if got, _, _, err := ReadSince(townRoot, "", gen, offset); err != nil || len(got) != 0 {
+		t.Errorf("ReadSince from checkpoint = %+v, %v; want nothing", got, err)
+	}
+}
@@ -0,0 +1,229 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// BurstRule turns a run of matching events into an incident: Count events
+// within Window, counted separately per Scope. Type and Actor are globs in
+// path.Match syntax.
+//
+// Example: {"type": "merge_failed", "scope": "rig", "count": 5, "window": "10m"}
+type BurstRule struct {
+	Type   string `json:"type"`
+	Actor  string `json:"actor,omitempty"`
+	Scope  string `json:"scope,omitempty"` // "rig", "actor" or "" for the whole town
+	Count  int    `json:"count"`
+	Window string `json:"window"`
+}
+
+// Burst scopes.
+const (
+	BurstScopeTown  = ""
+	BurstScopeRig   = "rig"
+	BurstScopeActor = "actor"
+)
+
+func (b BurstRule) validate() error {
+	if b.Type == "" {
+		return fmt.Errorf("type is required")
+	}
+	if b.Count < 2 {
+		return fmt.Errorf("count must be at least 2, got %d", b.Count)
+	}
+	if d, err := time.ParseDuration(b.Window); err != nil || d <= 0 {
+		return fmt.Errorf("invalid window %q", b.Window)
+	}
+	switch b.Scope {
+	case BurstScopeTown, BurstScopeRig, BurstScopeActor:
+	default:
+		return fmt.Errorf("invalid scope %q (want rig, actor or empty)", b.Scope)
+	}
+	for _, p := range []string{b.Type, b.Actor} {
+		if !validGlob(p) {
+			return fmt.Errorf("invalid pattern %q", p)
+		}
+	}
+	return nil
+}
+
+// scopeKey returns what a burst is counted over for an event.
+func (b BurstRule) scopeKey(e Event) string {
+	switch b.Scope {
+	case BurstScopeRig:
+		return EventRig(e)
+	case BurstScopeActor:
+		return e.Actor
+	}
+	return ""
+}
+
+// DecayRule demotes routine events that repeat: once an actor has logged
+// more than After events of one type within Window, further ones drop a
+// level until the repetition stops. High-significance events never decay.
+type DecayRule struct {
+	After  int    `json:"after"` // 0 disables decay
+	Window string `json:"window"`
+}
+
+func (d *DecayRule) validate() error {
+	if d.After < 0 {
+		return fmt.Errorf("after must not be negative, got %d", d.After)
+	}
+	if d.After == 0 {
+		return nil
+	}
+	if w, err := time.ParseDuration(d.Window); err != nil || w <= 0 {
+		return fmt.Errorf("invalid window %q", d.Window)
+	}
+	return nil
+}
+
+// DefaultBursts returns the built-in burst rules.
+func DefaultBursts() []BurstRule {
+	return []BurstRule{
+		{Type: TypeMergeFailed, Scope: BurstScopeRig, Count: 5, Window: "10m"},
+		{Type: TypeAgentRestarted, Scope: BurstScopeActor, Count: 3, Window: "30m"},
+		{Type: TypeAuthzDenied, Scope: BurstScopeActor, Count: 5, Window: "10m"},
+	}
+}
+
+// DefaultDecay returns the built-in decay rule.
+func DefaultDecay() *DecayRule {
+	return &DecayRule{After: 3, Window: "1h"}
+}
+
+// Scorer scores a stream of events: the static classification of its
+// rules, raised to incidents by bursts and lowered by repetition. Events
+// must be scored in log order; windows are measured by event timestamps,
+// so replaying history scores it as it was live.
+//
+// A Scorer is not safe for concurrent use.
+type Scorer struct {
+	rules   *SignificanceRules
+	bursts  []BurstRule
+	windows []time.Duration
+	decay   time.Duration
+	after   int
+
+	burstTimes  map[burstKey][]time.Time
+	repeatTimes map[repeatKey][]time.Time
+}
+
+type burstKey struct {
+	rule  int
+	scope string
+}
+
+type repeatKey struct {
+	eventType string
+	actor     string
+}
+
+// NewScorer creates a scorer for rules. Nil rules score with the built-in
+// defaults.
+func NewScorer(rules *SignificanceRules) *Scorer {
+	if rules == nil {
+		rules = DefaultSignificanceRules()
+	}
+	s := &Scorer{
+		rules:       rules,
+		burstTimes:  make(map[burstKey][]time.Time),
+		repeatTimes: make(map[repeatKey][]time.Time),
+	}
+
+	bursts := rules.Bursts
+	if bursts == nil {
+		bursts = DefaultBursts()
+	}
+	for _, b := range bursts {
+		window, err := time.ParseDuration(b.Window)
+		if err != nil || b.validate() != nil {
+			continue // LoadSignificanceRules rejects these
+		}
+		s.bursts = append(s.bursts, b)
+		s.windows = append(s.windows, window)
+	}
+
+	decay := rules.Decay
+	if decay == nil {
+		decay = DefaultDecay()
+	}
+	if decay.validate() == nil && decay.After > 0 {
+		s.after = decay.After
+		s.decay, _ = time.ParseDuration(decay.Window)
+	}
+	return s
+}
+
+// Score returns an event's significance in context, and the incident it
+// completes, if any. Events without a parseable timestamp get their static
+// significance and never form bursts.
+func (s *Scorer) Score(e Event) (level string, incident *Event) {
+	level = s.rules.Classify(e)
+	at, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return level, nil
+	}
+
+	for i, b := range s.bursts {
+		if e.Type == TypeIncident || !globMatch(b.Type, e.Type) || !globMatch(b.Actor, e.Actor) {
+			continue
+		}
+		key := burstKey{rule: i, scope: b.scopeKey(e)}
+		times := append(within(s.burstTimes[key], at, s.windows[i]), at)
+		if len(times) < b.Count {
+			s.burstTimes[key] = times
+			continue
+		}
+		// Start counting afresh, so a burst that keeps going raises one
+		// incident per Count events rather than one per event
+		delete(s.burstTimes, key)
+		if incident == nil {
+			incident = &Event{
+				Timestamp:  e.Timestamp,
+				Source:     "gt",
+				Type:       TypeIncident,
+				Actor:      e.Actor,
+				Payload:    IncidentPayload(e.Type, key.scope, len(times), b.Window, times[0].UTC().Format(time.RFC3339)),
+				Visibility: VisibilityFeed,
+			}
+		}
+	}
+
+	if s.after > 0 && level != SignificanceHigh {
+		key := repeatKey{eventType: e.Type, actor: e.Actor}
+		times := append(within(s.repeatTimes[key], at, s.decay), at)
+		s.repeatTimes[key] = times
+		if len(times) > s.after {
+			level = demote(level)
+		}
+	}
+	return level, incident
+}
+
+// AtLeast scores an event and reports whether it is at least level, along
+// with any incident it completes.
+func (s *Scorer) AtLeast(e Event, level string) (bool, *Event) {
+	got, incident := s.Score(e)
+	return significanceRank[got] >= significanceRank[level], incident
+}
+
+// within drops the times more than window before at.
+func within(times []time.Time, at time.Time, window time.Duration) []time.Time {
+	cutoff := at.Add(-window)
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+// demote returns the level below level.
+func demote(level string) string {
+	if level == SignificanceHigh {
+		return SignificanceMedium
+	}
+	return SignificanceLow
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func scoredEvent(typ, actor, rig string, at time.Time) Event {
+	e := Event{Type: typ, Actor: actor, Timestamp: at.UTC().Format(time.RFC3339)}
+	if rig != "" {
+		e.Payload = map[string]interface{}{"rig": rig}
+	}
+	return e
+}
+
+func TestScorer_Burst(t *testing.T) {
+	s := NewScorer(DefaultSignificanceRules())
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Four failures in gastown and one in beads: no incident yet
+	for i := 0; i < 4; i++ {
+		if _, incident := s.Score(scoredEvent(TypeMergeFailed, "gastown/refinery", "gastown", start.Add(time.Duration(i)*time.Minute))); incident != nil {
+			t.Fatalf("incident after %d failures: %+v", i+1, incident)
+		}
+	}
+	if _, incident := s.Score(scoredEvent(TypeMergeFailed, "beads/refinery", "beads", start.Add(4*time.Minute))); incident != nil {
+		t.Fatalf("failure in another rig completed gastown's burst: %+v", incident)
+	}
+
+	level, incident := s.Score(scoredEvent(TypeMergeFailed, "gastown/refinery", "gastown", start.Add(5*time.Minute)))
+	if level != SignificanceHigh || incident == nil {
+		t.Fatalf("fifth failure = %q, %+v; want high and an incident", level, incident)
+	}
+	if incident.Type != TypeIncident || incident.Payload["scope"] != "gastown" || incident.Payload["count"] != 5 ||
+		incident.Payload["since"] != start.Format(time.RFC3339) {
+		t.Errorf("incident = %+v", incident)
+	}
+	if got := Significance(*incident); got != SignificanceHigh {
+		t.Errorf("incident significance = %q, want high", got)
+	}
+
+	// Counting starts afresh, and failures outside the window don't count
+	for i := 0; i < 4; i++ {
+		if _, incident := s.Score(scoredEvent(TypeMergeFailed, "gastown/refinery", "gastown", start.Add(time.Duration(6+i*5)*time.Minute))); incident != nil {
+			t.Fatalf("incident %d failures after the last one: %+v", i+1, incident)
+		}
+	}
+}
+
+func TestScorer_Decay(t *testing.T) {
+	s := NewScorer(DefaultSignificanceRules())
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var levels []string
+	for i := 0; i < 5; i++ {
+		level, _ := s.Score(scoredEvent(TypeSling, "mayor", "", start.Add(time.Duration(i)*time.Minute)))
+		levels = append(levels, level)
+	}
+	want := []string{SignificanceMedium, SignificanceMedium, SignificanceMedium, SignificanceLow, SignificanceLow}
+	for i := range want {
+		if levels[i] != want[i] {
+			t.Fatalf("repeated sling levels = %v, want %v", levels, want)
+		}
+	}
+
+	if level, _ := s.Score(scoredEvent(TypeSling, "gastown/crew/max", "", start.Add(5*time.Minute))); level != SignificanceMedium {
+		t.Errorf("sling by another actor = %q, want medium", level)
+	}
+	if level, _ := s.Score(scoredEvent(TypeSling, "mayor", "", start.Add(3*time.Hour))); level != SignificanceMedium {
+		t.Errorf("sling after the repetition stopped = %q, want medium", level)
+	}
+	for i := 0; i < 5; i++ {
+		if level, _ := s.Score(scoredEvent(TypeSessionDeath, "deacon", "", start.Add(time.Duration(i)*time.Second))); level != SignificanceHigh {
+			t.Fatalf("repeated session death %d = %q, high events must not decay", i+1, level)
+		}
+	}
+}
+
+func TestScorer_Disabled(t *testing.T) {
+	rules := DefaultSignificanceRules()
+	rules.Bursts = []BurstRule{}
+	rules.Decay = &DecayRule{}
+	s := NewScorer(rules)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		level, incident := s.Score(scoredEvent(TypeMergeFailed, "gastown/refinery", "gastown", start.Add(time.Duration(i)*time.Second)))
+		if incident != nil {
+			t.Fatalf("incident with bursts off: %+v", incident)
+		}
+		if sling, _ := s.Score(scoredEvent(TypeSling, "mayor", "", start)); sling != SignificanceMedium || level != SignificanceHigh {
+			t.Fatalf("levels with decay off = %q, %q", level, sling)
+		}
+	}
+}
+
+func TestBurstRule_Validate(t *testing.T) {
+	tests := map[string]*SignificanceRules{
+		"missing type":  {Bursts: []BurstRule{{Count: 5, Window: "10m"}}},
+		"count too low": {Bursts: []BurstRule{{Type: TypeMergeFailed, Count: 1, Window: "10m"}}},
+		"bad window":    {Bursts: []BurstRule{{Type: TypeMergeFailed, Count: 5, Window: "soon"}}},
+		"bad scope":     {Bursts: []BurstRule{{Type: TypeMergeFailed, Count: 5, Window: "10m", Scope: "bead"}}},
+		"bad decay":     {Decay: &DecayRule{After: 3, Window: "0s"}},
+	}
+	for name, rules := range tests {
+		if err := rules.Validate(); err == nil {
+			t.Errorf("%s: Validate succeeded, want an error", name)
+		}
+	}
+	if err := DefaultSignificanceRules().Validate(); err != nil {
+		t.Errorf("default rules: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ShardDir is the directory holding per-rig event logs when rig sharding is
+// enabled. It is hidden so it can never collide with a rig directory.
+const ShardDir = ".events"
+
+// shardNameRe restricts shard names to safe file names.
+var shardNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// townLevelActors are actor prefixes that never belong to a rig shard.
+var townLevelActors = map[string]bool{
+	"mayor":  true,
+	"deacon": true,
+}
+
+// LogPath returns the log file for a shard. The empty shard is the
+// town-wide log.
+func LogPath(townRoot, shard string) string {
+	if shard == "" {
+		return filepath.Join(townRoot, EventsFile)
+	}
+	return filepath.Join(townRoot, ShardDir, shard+".jsonl")
+}
+
+// Shards returns the names of the per-rig shards present on disk, sorted.
+func Shards(townRoot string) []string {
+	matches, _ := filepath.Glob(filepath.Join(townRoot, ShardDir, "*.jsonl"))
+	var shards []string
+	for _, m := range matches {
+		name := strings.TrimSuffix(filepath.Base(m), ".jsonl")
+		if shardNameRe.MatchString(name) {
+			shards = append(shards, name)
+		}
+	}
+	sort.Strings(shards)
+	return shards
+}
+
+// cachedConfig is a town's parsed event log settings and the settings
+// file's state when they were read.
+type cachedConfig struct {
+	modTime time.Time
+	size    int64
+	cfg     *config.EventsConfig
+}
+
+var (
+	configMu    sync.Mutex
+	configCache = make(map[string]cachedConfig)
+)
+
+// loadConfig returns the town's event log settings, or defaults if the
+// settings are missing or unreadable. Every append reads them, so they are
+// parsed once per town and re-read only when the settings file changes.
+// Callers must not modify the result.
+func loadConfig(townRoot string) *config.EventsConfig {
+	path := config.TownSettingsPath(townRoot)
+	info, err := os.Stat(path)
+	if err != nil {
+		return &config.EventsConfig{}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	if c, ok := configCache[townRoot]; ok && c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+		return c.cfg
+	}
+	cfg := &config.EventsConfig{}
+	if settings, err := config.LoadOrCreateTownSettings(path); err == nil && settings.Events != nil {
+		cfg = settings.Events
+	}
+	configCache[townRoot] = cachedConfig{modTime: info.ModTime(), size: info.Size(), cfg: cfg}
+	return cfg
+}
+
+// shardFor returns the shard an event should be written to, or "" for the
+// town-wide log.
+func shardFor(cfg *config.EventsConfig, event Event) string {
+	if cfg.Shard != config.EventShardRig {
+		return ""
+	}
+	return EventRig(event)
+}
+
+// EventRig returns the rig an event is about: the payload's "rig" field, or
+// the first segment of a rig-scoped actor (e.g., "gastown/polecats/Toast").
+// Returns "" for town-level events or names unsafe to use as a file name.
+func EventRig(event Event) string {
+	rig, _ := event.Payload["rig"].(string)
+	if rig == "" {
+		if i := strings.Index(event.Actor, "/"); i > 0 {
+			rig = event.Actor[:i]
+		}
+	}
+	if townLevelActors[rig] || !shardNameRe.MatchString(rig) {
+		return ""
+	}
+	return rig
+}
+
+// logFiles returns the town-wide log followed by every shard that exists,
+// keyed by shard name ("" for the town-wide log).
+func logFiles(townRoot string) map[string]string {
+	files := map[string]string{"": LogPath(townRoot, "")}
+	for _, shard := range Shards(townRoot) {
+		files[shard] = LogPath(townRoot, shard)
+	}
+	return files
+}
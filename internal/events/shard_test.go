@@ -0,0 +1,194 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeShardFile(t *testing.T, townRoot, shard string, lines ...string) {
+	t.Helper()
+	var data []byte
+	for _, line := range lines {
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	path := LogPath(townRoot, shard)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("creating shard dir: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("writing shard file: %v", err)
+	}
+}
+
+func TestEventRig(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{"payload rig", Event{Actor: "deacon", Payload: map[string]interface{}{"rig": "gastown"}}, "gastown"},
+		{"rig actor", Event{Actor: "gastown/polecats/Toast"}, "gastown"},
+		{"mayor", Event{Actor: "mayor"}, ""},
+		{"deacon path", Event{Actor: "deacon/boot"}, ""},
+		{"unsafe name", Event{Payload: map[string]interface{}{"rig": "../etc"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EventRig(tt.event); got != tt.want {
+				t.Errorf("EventRig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShardFor_RespectsTownSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	event := Event{Actor: "gastown/witness"}
+
+	if got := shardFor(loadConfig(townRoot), event); got != "" {
+		t.Errorf("shardFor without settings = %q, want town-wide log", got)
+	}
+
+	settingsDir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(settingsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type":"town-settings","version":1,"events":{"shard":"rig"}}`
+	if err := os.WriteFile(filepath.Join(settingsDir, "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := shardFor(loadConfig(townRoot), event); got != "gastown" {
+		t.Errorf("shardFor with rig sharding = %q, want gastown", got)
+	}
+	if got := shardFor(loadConfig(townRoot), Event{Actor: "mayor"}); got != "" {
+		t.Errorf("shardFor town-level event = %q, want town-wide log", got)
+	}
+}
+
+func TestParseEventID(t *testing.T) {
+	shard, seq, err := ParseEventID("42")
+	if err != nil || shard != "" || seq != 42 {
+		t.Errorf("ParseEventID(42) = %q, %d, %v", shard, seq, err)
+	}
+	shard, seq, err = ParseEventID("gastown:7")
+	if err != nil || shard != "gastown" || seq != 7 {
+		t.Errorf("ParseEventID(gastown:7) = %q, %d, %v", shard, seq, err)
+	}
+	for _, bad := range []string{"", "0", "abc", "gastown:", "../x:3"} {
+		if _, _, err := ParseEventID(bad); err == nil {
+			t.Errorf("ParseEventID(%q) should fail", bad)
+		}
+	}
+}
+
+func TestReadAll_MergesShards(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`{"ts":"2026-01-01T00:03:00Z","type":"halt","actor":"mayor"}`,
+	)
+	writeShardFile(t, townRoot, "gastown",
+		`{"ts":"2026-01-01T00:01:00Z","type":"spawn","actor":"gastown/witness"}`,
+		`{"ts":"2026-01-01T00:02:00Z","type":"done","actor":"gastown/polecats/Toast"}`,
+	)
+
+	if _, err := Annotate(townRoot, "gastown", 2, "shipped", ""); err != nil {
+		t.Fatalf("Annotate shard event: %v", err)
+	}
+
+	records, err := ReadAll(townRoot)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	wantIDs := []string{"1", "gastown:1", "gastown:2", "2"}
+	if len(records) != len(wantIDs) {
+		t.Fatalf("got %d records, want %d", len(records), len(wantIDs))
+	}
+	for i, want := range wantIDs {
+		if got := records[i].ID(); got != want {
+			t.Errorf("records[%d].ID() = %q, want %q", i, got, want)
+		}
+	}
+	if len(records[2].Annotations) != 1 || records[2].Annotations[0].Note != "shipped" {
+		t.Errorf("shard event annotations = %+v", records[2].Annotations)
+	}
+	if len(records[1].Annotations) != 0 {
+		t.Errorf("gastown:1 has %d annotations, want 0", len(records[1].Annotations))
+	}
+}
+
+func TestTailer_FollowsNewShards(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot, `{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`)
+
+	tailer, err := NewTailer(townRoot)
+	if err != nil {
+		t.Fatalf("NewTailer: %v", err)
+	}
+	defer tailer.Close()
+
+	if lines := tailer.Poll(); len(lines) != 0 {
+		t.Fatalf("Poll before writes returned %d lines, want 0", len(lines))
+	}
+
+	writeShardFile(t, townRoot, "gastown", `{"ts":"2026-01-01T00:01:00Z","type":"spawn","actor":"gastown/witness"}`)
+	f, err := os.OpenFile(LogPath(townRoot, ""), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"ts":"2026-01-01T00:02:00Z",`)
+	_ = f.Sync()
+
+	if lines := tailer.Poll(); len(lines) != 1 {
+		t.Fatalf("Poll returned %d lines, want 1 (new shard only)", len(lines))
+	}
+
+	_, _ = f.WriteString(`"type":"halt","actor":"mayor"}` + "\n")
+	_ = f.Close()
+
+	lines := tailer.Poll()
+	if len(lines) != 1 {
+		t.Fatalf("Poll returned %d lines, want 1", len(lines))
+	}
+	want := `{"ts":"2026-01-01T00:02:00Z","type":"halt","actor":"mayor"}` + "\n"
+	if lines[0] != want {
+		t.Errorf("Poll line = %q, want %q", lines[0], want)
+	}
+}
+
+func TestLoadConfig_RereadsChangedSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(shard string, mtime time.Time) {
+		t.Helper()
+		settings := `{"type":"town-settings","version":1,"events":{"shard":"` + shard + `"}}`
+		if err := os.WriteFile(path, []byte(settings), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write("rig", start)
+	first := loadConfig(townRoot)
+	if first.Shard != "rig" {
+		t.Fatalf("Shard = %q, want rig", first.Shard)
+	}
+	if again := loadConfig(townRoot); again != first {
+		t.Error("loadConfig re-parsed unchanged settings")
+	}
+
+	write("", start.Add(time.Second))
+	if got := loadConfig(townRoot).Shard; got != "" {
+		t.Errorf("Shard after edit = %q, want the town-wide log", got)
+	}
+}
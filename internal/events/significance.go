@@ -0,0 +1,254 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Significance levels for events, from routine chatter to events an
+// operator should always be able to find later.
+const (
+	SignificanceLow    = "low"
+	SignificanceMedium = "medium"
+	SignificanceHigh   = "high"
+)
+
+// significanceRank orders significance levels for comparison.
+var significanceRank = map[string]int{
+	SignificanceLow:    0,
+	SignificanceMedium: 1,
+	SignificanceHigh:   2,
+}
+
+// typeSignificance classifies known event types. Unlisted types are low.
+var typeSignificance = map[string]string{
+	// Failures, deaths and escalations: the events incidents are built from
+	TypeMergeFailed:      SignificanceHigh,
+	TypeSessionDeath:     SignificanceHigh,
+	TypeMassDeath:        SignificanceHigh,
+	TypeEscalationSent:   SignificanceHigh,
+	TypeKill:             SignificanceHigh,
+	TypeHalt:             SignificanceHigh,
+	TypeAuthzDenied:      SignificanceHigh,
+	TypeAgentRestartHalt: SignificanceHigh,
+	TypeIncident:         SignificanceHigh,
+
+	// Work changing hands or landing
+	TypeSling:            SignificanceMedium,
+	TypeHandoff:          SignificanceMedium,
+	TypeDrain:            SignificanceMedium,
+	TypeDone:             SignificanceMedium,
+	TypeSpawn:            SignificanceMedium,
+	TypeBoot:             SignificanceMedium,
+	TypeMerged:           SignificanceMedium,
+	TypeMergeSkipped:     SignificanceMedium,
+	TypeEscalationAcked:  SignificanceMedium,
+	TypeEscalationClosed: SignificanceMedium,
+	TypeRigHealthChanged: SignificanceMedium,
+	TypeAgentRestarted:   SignificanceMedium,
+}
+
+// SignificanceRules maps event types to significance levels. Towns get a
+// copy of the defaults at settings/significance.json, which operators edit
+// to promote or demote events without recompiling.
+type SignificanceRules struct {
+	Type    string            `json:"type"`    // "significance-rules"
+	Version int               `json:"version"` // schema version
+	Default string            `json:"default"` // level for unlisted types
+	Types   map[string]string `json:"types"`   // event type -> level
+
+	// Rules override Types for the events they match. The first matching
+	// rule wins.
+	Rules []SignificanceRule `json:"rules,omitempty"`
+
+	// Bursts and Decay are applied by a Scorer on top of the static
+	// classification. Omitted, the built-in defaults apply; "bursts": []
+	// or "decay": {"after": 0} turns them off.
+	Bursts []BurstRule `json:"bursts,omitempty"`
+	Decay  *DecayRule  `json:"decay,omitempty"`
+}
+
+// SignificanceRule sets the significance of the events it matches. Empty
+// fields match everything. Type, Actor and payload values are globs in
+// path.Match syntax, so "*/refinery" matches every rig's refinery.
+//
+// Example: {"type": "done", "actor": "gastown/*", "significance": "high"}
+type SignificanceRule struct {
+	Type       string            `json:"type,omitempty"`
+	Actor      string            `json:"actor,omitempty"`
+	Visibility string            `json:"visibility,omitempty"`
+	Payload    map[string]string `json:"payload,omitempty"` // field -> glob
+	Level      string            `json:"significance"`
+}
+
+// Match reports whether the rule applies to an event.
+func (r SignificanceRule) Match(e Event) bool {
+	if !globMatch(r.Type, e.Type) || !globMatch(r.Actor, e.Actor) {
+		return false
+	}
+	if r.Visibility != "" && e.Visibility != r.Visibility {
+		return false
+	}
+	for field, pattern := range r.Payload {
+		v, ok := e.Payload[field]
+		if !ok || !globMatch(pattern, payloadString(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// globMatch matches s against a path.Match pattern; an empty pattern
+// matches everything.
+func globMatch(pattern, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, s)
+	return ok
+}
+
+// validGlob reports whether pattern is valid path.Match syntax.
+func validGlob(pattern string) bool {
+	_, err := path.Match(pattern, "")
+	return err == nil
+}
+
+// Validate checks every level and glob in the rules.
+func (r *SignificanceRules) Validate() error {
+	if r.Default != "" {
+		if err := ValidSignificance(r.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	for t, level := range r.Types {
+		if err := ValidSignificance(level); err != nil {
+			return fmt.Errorf("type %s: %w", t, err)
+		}
+	}
+	for i, burst := range r.Bursts {
+		if err := burst.validate(); err != nil {
+			return fmt.Errorf("burst %d: %w", i+1, err)
+		}
+	}
+	if r.Decay != nil {
+		if err := r.Decay.validate(); err != nil {
+			return fmt.Errorf("decay: %w", err)
+		}
+	}
+	for i, rule := range r.Rules {
+		if err := ValidSignificance(rule.Level); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		patterns := []string{rule.Type, rule.Actor}
+		for _, p := range rule.Payload {
+			patterns = append(patterns, p)
+		}
+		for _, p := range patterns {
+			if !validGlob(p) {
+				return fmt.Errorf("rule %d: invalid pattern %q", i+1, p)
+			}
+		}
+	}
+	return nil
+}
+
+// Classify returns the significance level of an event under these rules:
+// the first matching rule, else the level for its type, else the default.
+// Nil rules classify with the built-in defaults.
+func (r *SignificanceRules) Classify(e Event) string {
+	if r == nil {
+		return Significance(e)
+	}
+	for _, rule := range r.Rules {
+		if rule.Match(e) {
+			return rule.Level
+		}
+	}
+	if level, ok := r.Types[e.Type]; ok {
+		return level
+	}
+	if r.Default != "" {
+		return r.Default
+	}
+	return SignificanceLow
+}
+
+// AtLeast reports whether the event's significance under these rules is at
+// least level.
+func (r *SignificanceRules) AtLeast(e Event, level string) bool {
+	return significanceRank[r.Classify(e)] >= significanceRank[level]
+}
+
+// LoadSignificanceRules loads a town's significance rules. A town without
+// settings/significance.json uses the built-in defaults.
+func LoadSignificanceRules(townRoot string) (*SignificanceRules, error) {
+	data, err := os.ReadFile(SignificanceRulesPath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultSignificanceRules(), nil
+		}
+		return nil, fmt.Errorf("reading significance rules: %w", err)
+	}
+	var rules SignificanceRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing significance rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid significance rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// SignificanceRulesPath returns the path to a town's significance rules.
+func SignificanceRulesPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "significance.json")
+}
+
+// DefaultSignificanceRules returns the built-in classification.
+func DefaultSignificanceRules() *SignificanceRules {
+	types := make(map[string]string, len(typeSignificance))
+	for t, level := range typeSignificance {
+		types[t] = level
+	}
+	return &SignificanceRules{
+		Type:    "significance-rules",
+		Version: 1,
+		Default: SignificanceLow,
+		Types:   types,
+		Bursts:  DefaultBursts(),
+		Decay:   DefaultDecay(),
+	}
+}
+
+// Significance returns the built-in significance level of an event. Use
+// LoadSignificanceRules to classify with a town's own rules.
+func Significance(e Event) string {
+	if level, ok := typeSignificance[e.Type]; ok {
+		return level
+	}
+	return SignificanceLow
+}
+
+// ValidSignificance checks that level is a known significance level.
+func ValidSignificance(level string) error {
+	if _, ok := significanceRank[level]; !ok {
+		return fmt.Errorf("invalid significance %q (want low, medium or high)", level)
+	}
+	return nil
+}
+
+// SignificanceAtLeast reports whether significance got is at least level.
+// Unknown levels compare as low.
+func SignificanceAtLeast(got, level string) bool {
+	return significanceRank[got] >= significanceRank[level]
+}
+
+// AtLeast reports whether the event's significance is at least level.
+// Unknown levels compare as low.
+func AtLeast(e Event, level string) bool {
+	return significanceRank[Significance(e)] >= significanceRank[level]
+}
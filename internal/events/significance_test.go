@@ -0,0 +1,103 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignificanceRules_Classify(t *testing.T) {
+	rules := DefaultSignificanceRules()
+	rules.Rules = []SignificanceRule{
+		{Type: "done", Actor: "gastown/*", Level: SignificanceHigh},
+		{Type: "merge_*", Payload: map[string]string{"reason": "flaky*"}, Level: SignificanceLow},
+		{Visibility: VisibilityAudit, Actor: "*/witness", Level: SignificanceMedium},
+	}
+	if err := rules.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{"rule promotes by actor glob", Event{Type: TypeDone, Actor: "gastown/nux"}, SignificanceHigh},
+		{"other actors keep the type level", Event{Type: TypeDone, Actor: "beads/nux"}, SignificanceMedium},
+		{"rule demotes by payload glob", Event{Type: TypeMergeFailed, Payload: map[string]interface{}{"reason": "flaky test"}}, SignificanceLow},
+		{"payload mismatch falls through", Event{Type: TypeMergeFailed, Payload: map[string]interface{}{"reason": "conflict"}}, SignificanceHigh},
+		{"missing payload field falls through", Event{Type: TypeMergeFailed}, SignificanceHigh},
+		{"visibility rule", Event{Type: "patrol", Actor: "gastown/witness", Visibility: VisibilityAudit}, SignificanceMedium},
+		{"unlisted type uses the default", Event{Type: "patrol", Actor: "gastown/witness"}, SignificanceLow},
+	}
+	for _, tt := range tests {
+		if got := rules.Classify(tt.event); got != tt.want {
+			t.Errorf("%s: Classify = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	var builtin *SignificanceRules
+	if got := builtin.Classify(Event{Type: TypeMergeFailed}); got != SignificanceHigh {
+		t.Errorf("nil rules Classify = %q, want the built-in high", got)
+	}
+}
+
+func TestSignificanceRules_Validate(t *testing.T) {
+	tests := map[string]*SignificanceRules{
+		"bad default":   {Default: "urgent"},
+		"bad type":      {Types: map[string]string{"done": "urgent"}},
+		"bad rule":      {Rules: []SignificanceRule{{Type: "done", Level: "urgent"}}},
+		"missing level": {Rules: []SignificanceRule{{Type: "done"}}},
+		"bad glob":      {Rules: []SignificanceRule{{Actor: "[", Level: SignificanceHigh}}},
+	}
+	for name, rules := range tests {
+		if err := rules.Validate(); err == nil {
+			t.Errorf("%s: Validate succeeded, want an error", name)
+		}
+	}
+}
+
+func TestLoadSignificanceRules(t *testing.T) {
+	townRoot := t.TempDir()
+	rules, err := LoadSignificanceRules(townRoot)
+	if err != nil {
+		t.Fatalf("LoadSignificanceRules without a file: %v", err)
+	}
+	if got := rules.Classify(Event{Type: TypeSling}); got != SignificanceMedium {
+		t.Errorf("default rules classify sling as %q, want medium", got)
+	}
+
+	path := SignificanceRulesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type":"significance-rules","version":1,"default":"medium","types":{"sling":"low"},
+		"rules":[{"type":"spawn","actor":"*/crew/*","significance":"high"}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadSignificanceRules(townRoot)
+	if err != nil {
+		t.Fatalf("LoadSignificanceRules: %v", err)
+	}
+	for _, tt := range []struct {
+		event Event
+		want  string
+	}{
+		{Event{Type: TypeSling}, SignificanceLow},
+		{Event{Type: "patrol"}, SignificanceMedium},
+		{Event{Type: TypeSpawn, Actor: "gastown/crew/max"}, SignificanceHigh},
+	} {
+		if got := rules.Classify(tt.event); got != tt.want {
+			t.Errorf("Classify(%s by %q) = %q, want %q", tt.event.Type, tt.event.Actor, got, tt.want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(`{"rules":[{"type":"done","significance":"urgent"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSignificanceRules(townRoot); err == nil || !strings.Contains(err.Error(), "rule 1") {
+		t.Errorf("invalid rules error = %v, want one naming rule 1", err)
+	}
+}
@@ -0,0 +1,285 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// LineSource yields new events log lines on each Poll. Tailer reads the logs
+// itself; a Broker's Tap shares the broker's reading.
+type LineSource interface {
+	Poll() []string
+	Close() error
+}
+
+// Tailer follows the town-wide events log and every per-rig shard, returning
+// lines appended after it was created. Shards that appear later are read from
+// their start, so no event written to a new shard is missed. A log replaced
+// by rotate, prune or compact, or truncated, is followed into its new file
+// without repeating the lines already returned (see RemapOffset). Encrypted
+// lines are returned decrypted.
+type Tailer struct {
+	townRoot string
+	files    map[string]*tailFile
+	dec      *Decoder
+}
+
+// tailFile is one followed log file.
+type tailFile struct {
+	file    *os.File
+	reader  *bufio.Reader
+	partial string
+	gen     int   // the log's generation when opened
+	pos     int64 // bytes read, through the last complete line
+	lines   int   // complete lines before pos; -1 until counted
+}
+
+// NewTailer creates a tailer positioned at the end of every existing log file.
+// The town-wide log is created if it does not exist.
+func NewTailer(townRoot string) (*Tailer, error) {
+	t, err := newTailer(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, tf := range t.files {
+		if err := tf.seekEnd(); err != nil {
+			_ = t.Close()
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// NewTailerAt creates a tailer that resumes from a checkpoint taken with
+// Offsets: each log in from is read after its offset, translated into the
+// log's current generation if it was rewritten since and clamped to the
+// lines it has now. Logs not in from were created after the checkpoint and
+// are read from their start.
+func NewTailerAt(townRoot string, from map[string]Offset) (*Tailer, error) {
+	t, err := newTailer(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for shard, tf := range t.files {
+		off, ok := from[shard]
+		if !ok {
+			continue
+		}
+		gen, lines, err := RemapOffset(townRoot, shard, off.Generation, off.Lines)
+		if err != nil {
+			_ = t.Close()
+			return nil, err
+		}
+		if gen != tf.gen || lines < 0 {
+			// A generation the remap log doesn't know can't be translated
+			lines = 0
+		}
+		tf.read(lines, nil)
+	}
+	return t, nil
+}
+
+// newTailer opens every log at its start. The town-wide log is created if
+// it does not exist.
+func newTailer(townRoot string) (*Tailer, error) {
+	mainLog, err := os.OpenFile(LogPath(townRoot, ""), os.O_RDONLY|os.O_CREATE, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return nil, err
+	}
+	t := &Tailer{townRoot: townRoot, files: make(map[string]*tailFile), dec: NewDecoder(townRoot)}
+	gen, _ := Generation(townRoot, "")
+	t.files[""] = &tailFile{file: mainLog, reader: bufio.NewReader(mainLog), gen: gen}
+	for _, shard := range Shards(townRoot) {
+		t.open(shard)
+	}
+	return t, nil
+}
+
+// Offsets returns how far the tailer has read each log, by shard ("" for
+// the town-wide log), for NewTailerAt to resume from.
+func (t *Tailer) Offsets() map[string]Offset {
+	offsets := make(map[string]Offset, len(t.files))
+	for shard, tf := range t.files {
+		if tf.lines < 0 {
+			n, err := countLinesBefore(tf.file, tf.pos)
+			if err != nil {
+				continue
+			}
+			tf.lines = n
+		}
+		offsets[shard] = Offset{Generation: tf.gen, Lines: tf.lines}
+	}
+	return offsets
+}
+
+// open starts following a shard from its start.
+func (t *Tailer) open(shard string) {
+	f, err := os.Open(LogPath(t.townRoot, shard)) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		return
+	}
+	gen, _ := Generation(t.townRoot, shard)
+	t.files[shard] = &tailFile{file: f, reader: bufio.NewReader(f), gen: gen}
+}
+
+// seekEnd skips to the end of the file. Its lines are counted only if a
+// rewrite needs them.
+func (tf *tailFile) seekEnd() error {
+	end, err := tf.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	tf.reader.Reset(tf.file)
+	tf.pos, tf.lines, tf.partial = end, -1, ""
+	return nil
+}
+
+// read reads up to max of the file's new complete lines (all of them if max
+// is negative), calling fn (if set) with each.
+func (tf *tailFile) read(max int, fn func(line string)) {
+	for n := 0; max < 0 || n < max; n++ {
+		chunk, err := tf.reader.ReadString('\n')
+		if err != nil {
+			tf.partial += chunk
+			return
+		}
+		line := tf.partial + chunk
+		tf.partial = ""
+		tf.pos += int64(len(line))
+		if tf.lines >= 0 {
+			tf.lines++
+		}
+		if fn != nil {
+			fn(line)
+		}
+	}
+}
+
+// rewritten reports whether the log at path was replaced or truncated
+// since the file was opened.
+func (tf *tailFile) rewritten(path string) bool {
+	onDisk, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	open, err := tf.file.Stat()
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(onDisk, open) || onDisk.Size() < tf.pos
+}
+
+// reopen follows a shard's log into the file that replaced it. After a
+// rotate, prune or compact the lines already read are translated into the
+// new generation and skipped, so only what was appended after them is
+// returned. A log truncated or replaced by anything else is read from its
+// start. Returns nil if the new file can't be opened.
+func (t *Tailer) reopen(shard string, old *tailFile) *tailFile {
+	lines := old.lines
+	if lines < 0 {
+		n, err := countLinesBefore(old.file, old.pos)
+		if err != nil {
+			n = -1
+		}
+		lines = n
+	}
+	_ = old.file.Close()
+
+	f, err := os.Open(LogPath(t.townRoot, shard)) //nolint:gosec // G304: path is constructed from town root
+	if err != nil {
+		return nil
+	}
+	tf := &tailFile{file: f, reader: bufio.NewReader(f)}
+	gen, skip, err := RemapOffset(t.townRoot, shard, old.gen, lines)
+	if err != nil || lines < 0 {
+		tf.gen, _ = Generation(t.townRoot, shard)
+		_ = tf.seekEnd()
+		return tf
+	}
+	if gen == old.gen {
+		skip = 0 // not a recorded rewrite: everything in the file is new
+	}
+	tf.gen = gen
+	tf.read(skip, nil)
+	return tf
+}
+
+// countLinesBefore counts the complete lines in the first n bytes of f.
+func countLinesBefore(f *os.File, n int64) (int, error) {
+	lines := 0
+	buf := make([]byte, 64*1024)
+	for off := int64(0); off < n; {
+		chunk := buf
+		if rest := n - off; rest < int64(len(chunk)) {
+			chunk = chunk[:rest]
+		}
+		read, err := f.ReadAt(chunk, off)
+		lines += bytes.Count(chunk[:read], []byte{'\n'})
+		off += int64(read)
+		if err != nil {
+			if err == io.EOF {
+				return lines, nil
+			}
+			return lines, err
+		}
+	}
+	return lines, nil
+}
+
+// Poll returns the complete lines appended since the last call, picking up
+// any shards created in the meantime. Incomplete trailing lines are held
+// until the rest of the line is written.
+func (t *Tailer) Poll() []string {
+	for _, shard := range Shards(t.townRoot) {
+		if _, ok := t.files[shard]; !ok {
+			t.open(shard)
+		}
+	}
+
+	var lines []string
+	for shard, tf := range t.files {
+		if tf.rewritten(LogPath(t.townRoot, shard)) {
+			if tf = t.reopen(shard, tf); tf == nil {
+				delete(t.files, shard)
+				continue
+			}
+			t.files[shard] = tf
+		}
+		tf.read(-1, func(line string) {
+			lines = append(lines, t.plaintext(line))
+		})
+	}
+	return lines
+}
+
+// Close closes all followed files.
+func (t *Tailer) Close() error {
+	var firstErr error
+	for _, tf := range t.files {
+		if err := tf.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// plaintext returns line with any encrypted event decrypted and re-encoded
+// as plain JSON. Lines that can't be decrypted are returned unchanged.
+func (t *Tailer) plaintext(line string) string {
+	if !strings.Contains(line, `"enc"`) {
+		return line
+	}
+	event, err := t.dec.Parse([]byte(line))
+	if err != nil {
+		return line
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return line
+	}
+	return string(data) + "\n"
+}
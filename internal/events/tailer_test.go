@@ -0,0 +1,94 @@
+package events
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTailer_FollowsRewrittenLog(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`not an event`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"sling","actor":"mayor"}`,
+	)
+	tailer, err := NewTailer(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tailer.Close()
+
+	// Compact replaces the log; the surviving lines were already read
+	if _, err := Compact(townRoot); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if err := Append(townRoot, Event{Timestamp: "2026-01-01T00:02:00Z", Type: TypeDone, Actor: "gastown/nux"}); err != nil {
+		t.Fatal(err)
+	}
+	lines := tailer.Poll()
+	if len(lines) != 1 || !strings.Contains(lines[0], `"done"`) {
+		t.Fatalf("Poll after compact = %q, want only the new done event", lines)
+	}
+
+	// A truncated log is read from its start
+	if err := os.Truncate(LogPath(townRoot, ""), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(townRoot, Event{Timestamp: "2026-01-01T00:03:00Z", Type: TypeMerged, Actor: "gastown/refinery"}); err != nil {
+		t.Fatal(err)
+	}
+	lines = tailer.Poll()
+	if len(lines) != 1 || !strings.Contains(lines[0], `"merged"`) {
+		t.Fatalf("Poll after truncate = %q, want the merged event", lines)
+	}
+}
+
+func TestNewTailerAt_ResumesFromCheckpoint(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEventsFile(t, townRoot,
+		`{"ts":"2026-01-01T00:00:00Z","type":"boot","actor":"mayor"}`,
+		`not an event`,
+		`{"ts":"2026-01-01T00:01:00Z","type":"sling","actor":"mayor"}`,
+	)
+	tailer, err := NewTailer(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := tailer.Offsets()
+	_ = tailer.Close()
+	if checkpoint[""] != (Offset{Lines: 3}) {
+		t.Fatalf("Offsets = %+v, want 3 lines of generation 0", checkpoint)
+	}
+
+	// Written after the checkpoint, then compacted under it
+	if err := Append(townRoot, Event{Timestamp: "2026-01-01T00:02:00Z", Type: TypeDone, Actor: "gastown/nux"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Compact(townRoot); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	tailer, err = NewTailerAt(townRoot, checkpoint)
+	if err != nil {
+		t.Fatalf("NewTailerAt: %v", err)
+	}
+	lines := tailer.Poll()
+	_ = tailer.Close()
+	if len(lines) != 1 || !strings.Contains(lines[0], `"done"`) {
+		t.Fatalf("Poll = %q, want only the event written after the checkpoint", lines)
+	}
+
+	// An offset past the end of the log is clamped to it
+	tailer, err = NewTailerAt(townRoot, map[string]Offset{"": {Generation: 1, Lines: 50}})
+	if err != nil {
+		t.Fatalf("NewTailerAt: %v", err)
+	}
+	defer tailer.Close()
+	if lines := tailer.Poll(); len(lines) != 0 {
+		t.Errorf("Poll = %q, want nothing", lines)
+	}
+	if got := tailer.Offsets()[""]; got != (Offset{Generation: 1, Lines: 3}) {
+		t.Errorf("Offsets = %+v, want the log's 3 lines", got)
+	}
+}
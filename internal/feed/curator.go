@@ -1,7 +1,7 @@
 // Package feed provides the feed daemon that curates raw events into a user-facing feed.
 //
 // The curator:
-// 1. Tails ~/gt/.events.jsonl (raw events)
+// 1. Tails ~/gt/.events.jsonl and any per-rig shards (raw events)
 // 2. Filters by visibility tag (drops audit-only events)
 // 3. Deduplicates repeated updates (5 molecule updates → "agent active")
 // 4. Aggregates related events (3 issues closed → "batch complete")
@@ -9,11 +9,9 @@
 package feed
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -41,6 +39,9 @@ type FeedEvent struct {
 // ZFC: State is derived from the events file, not cached in memory.
 type Curator struct {
 	townRoot string
+	policy   *events.Policy  // redacts events before they reach the feed
+	seen     *events.Deduper // drops events logged twice by a retried command
+	dec      *events.Decoder // decrypts log lines, loading the key once
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
@@ -66,6 +67,8 @@ func NewCurator(townRoot string) *Curator {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Curator{
 		townRoot: townRoot,
+		seen:     events.NewDeduper(),
+		dec:      events.NewDecoder(townRoot),
 		ctx:      ctx,
 		cancel:   cancel,
 	}
@@ -73,22 +76,36 @@ func NewCurator(townRoot string) *Curator {
 
 // Start begins the curator goroutine.
 func (c *Curator) Start() error {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
+	policy, err := events.LoadPolicy(c.townRoot)
+	if err != nil {
+		return err
+	}
+	c.policy = policy
 
-	// Open events file, creating if needed
-	file, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	// Follow the events log and any rig shards, starting at the end to only
+	// process new events
+	tailer, err := events.NewTailer(c.townRoot)
 	if err != nil {
 		return fmt.Errorf("opening events file: %w", err)
 	}
 
-	// Seek to end to only process new events
-	if _, err := file.Seek(0, io.SeekEnd); err != nil {
-		_ = file.Close() //nolint:gosec // G104: best effort cleanup on error
-		return fmt.Errorf("seeking to end: %w", err)
+	c.wg.Add(1)
+	go c.run(tailer)
+
+	return nil
+}
+
+// StartFrom is Start with new events read from src, such as an event
+// broker's tap, instead of the curator's own tailer. src is closed on Stop.
+func (c *Curator) StartFrom(src events.LineSource) error {
+	policy, err := events.LoadPolicy(c.townRoot)
+	if err != nil {
+		return err
 	}
+	c.policy = policy
 
 	c.wg.Add(1)
-	go c.run(file)
+	go c.run(src)
 
 	return nil
 }
@@ -101,11 +118,10 @@ func (c *Curator) Stop() {
 
 // run is the main curator loop.
 // ZFC: No in-memory state to clean up - state is derived from the events file.
-func (c *Curator) run(file *os.File) {
+func (c *Curator) run(tailer events.LineSource) {
 	defer c.wg.Done()
-	defer file.Close()
+	defer tailer.Close()
 
-	reader := bufio.NewReader(file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -116,11 +132,7 @@ func (c *Curator) run(file *os.File) {
 
 		case <-ticker.C:
 			// Read available lines
-			for {
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					break // No more data available
-				}
+			for _, line := range tailer.Poll() {
 				c.processLine(line)
 			}
 		}
@@ -137,6 +149,9 @@ func (c *Curator) processLine(line string) {
 	if err := json.Unmarshal([]byte(line), &rawEvent); err != nil {
 		return // Skip malformed lines
 	}
+	if c.seen.Seen(rawEvent) {
+		return
+	}
 
 	// Filter by visibility - only process feed-visible events
 	if rawEvent.Visibility != events.VisibilityFeed && rawEvent.Visibility != events.VisibilityBoth {
@@ -148,8 +163,9 @@ func (c *Curator) processLine(line string) {
 		return
 	}
 
-	// Write to feed
-	c.writeFeedEvent(&rawEvent)
+	// Write to feed, redacted
+	event := c.policy.Redact(rawEvent, events.AudienceFeed)
+	c.writeFeedEvent(&event)
 }
 
 // shouldDedupe checks if an event should be deduplicated.
@@ -217,20 +233,33 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
 	return result
 }
 
-// readRecentEvents reads events from the events file within the given time window.
+// readRecentEvents reads events from the events log and any rig shards
+// within the given time window.
 // ZFC: This is the observable state that replaces in-memory caching.
-// Uses tail-like reading for performance (reads last N lines).
 func (c *Curator) readRecentEvents(window time.Duration) []events.Event {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
+	cutoff := time.Now().Add(-window)
+	var result []events.Event
+
+	paths := []string{events.LogPath(c.townRoot, "")}
+	for _, shard := range events.Shards(c.townRoot) {
+		paths = append(paths, events.LogPath(c.townRoot, shard))
+	}
+	for _, path := range paths {
+		result = append(result, c.readRecentEventsFile(path, cutoff)...)
+	}
 
+	return result
+}
+
+// readRecentEventsFile reads events newer than cutoff from one log file.
+// Uses tail-like reading for performance (stops at the first older line).
+func (c *Curator) readRecentEventsFile(path string, cutoff time.Time) []events.Event {
 	// Read the file (for small files, this is fine; for large files, consider tail-like reading)
-	data, err := os.ReadFile(eventsPath)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from town root
 	if err != nil {
 		return nil
 	}
 
-	now := time.Now()
-	cutoff := now.Add(-window)
 	var result []events.Event
 
 	// Parse lines from the end (most recent first) for efficiency
@@ -241,8 +270,8 @@ func (c *Curator) readRecentEvents(window time.Duration) []events.Event {
 			continue
 		}
 
-		var event events.Event
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
+		event, err := c.dec.Parse([]byte(line))
+		if err != nil {
 			continue
 		}
 
@@ -313,6 +342,11 @@ func (c *Curator) writeFeedEvent(event *events.Event) {
 	_, _ = f.Write(data)
 }
 
+// Summary returns the human-readable summary the feed shows for an event.
+func Summary(event *events.Event) string {
+	return (&Curator{}).generateSummary(event)
+}
+
 // generateSummary creates a human-readable summary of an event.
 func (c *Curator) generateSummary(event *events.Event) string {
 	switch event.Type {
@@ -354,9 +388,16 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		return fmt.Sprintf("%s completed patrol", event.Actor)
 
 	case events.TypeMerged:
+		stat := events.DecodeMergeInfo(event.Payload).Diffstat()
 		if worker, ok := event.Payload["worker"].(string); ok {
+			if stat != "" {
+				return fmt.Sprintf("Merged work from %s (%s)", worker, stat)
+			}
 			return fmt.Sprintf("Merged work from %s", worker)
 		}
+		if stat != "" {
+			return fmt.Sprintf("Work merged (%s)", stat)
+		}
 		return "Work merged"
 
 	case events.TypeMergeFailed:
@@ -376,6 +417,16 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Session terminated"
 
+	case events.TypeSessionSuspended:
+		agent, _ := event.Payload["agent"].(string)
+		reason, _ := event.Payload["reason"].(string)
+		return fmt.Sprintf("%s suspended (%s)", agent, reason)
+
+	case events.TypeSessionResumed:
+		agent, _ := event.Payload["agent"].(string)
+		reason, _ := event.Payload["reason"].(string)
+		return fmt.Sprintf("%s resumed (%s)", agent, reason)
+
 	case events.TypeMassDeath:
 		count, _ := event.Payload["count"].(float64) // JSON numbers are float64
 		possibleCause, _ := event.Payload["possible_cause"].(string)
@@ -387,6 +438,16 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Multiple sessions died simultaneously"
 
+	case events.TypeRigHealthChanged:
+		rig, _ := event.Payload["rig"].(string)
+		grade, _ := event.Payload["grade"].(string)
+		previous, _ := event.Payload["previous_grade"].(string)
+		score, _ := event.Payload["score"].(float64) // JSON numbers are float64
+		if previous != "" {
+			return fmt.Sprintf("%s health %s → %s (%d)", rig, previous, grade, int(score))
+		}
+		return fmt.Sprintf("%s health %s (%d)", rig, grade, int(score))
+
 	default:
 		return fmt.Sprintf("%s: %s", event.Actor, event.Type)
 	}
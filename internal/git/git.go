@@ -7,7 +7,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GitError contains raw output from a git command for agent observation.
@@ -62,8 +64,21 @@ func (g *Git) IsRepo() bool {
 	return err == nil
 }
 
+// IsIgnored reports whether path would be ignored by the repo's ignore
+// rules, so it can't be committed by accident.
+func (g *Git) IsIgnored(path string) bool {
+	_, err := g.run("check-ignore", "-q", path)
+	return err == nil
+}
+
 // run executes a git command and returns stdout.
 func (g *Git) run(args ...string) (string, error) {
+	return g.runEnv(nil, args...)
+}
+
+// runEnv executes a git command with extra environment variables and
+// returns stdout.
+func (g *Git) runEnv(env []string, args ...string) (string, error) {
 	// If gitDir is set (bare repo), prepend --git-dir flag
 	if g.gitDir != "" {
 		args = append([]string{"--git-dir=" + g.gitDir}, args...)
@@ -73,6 +88,9 @@ func (g *Git) run(args ...string) (string, error) {
 	if g.workDir != "" {
 		cmd.Dir = g.workDir
 	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -583,6 +601,56 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// CommitFileToBranch commits the file src as path on branch, on top of the
+// branch's existing tree, and returns the new commit. The branch is created
+// if it doesn't exist. The working tree, index and current branch are left
+// alone, so this is safe in a repo someone is working in.
+func (g *Git) CommitFileToBranch(branch, path, src, message string) (string, error) {
+	ref := "refs/heads/" + branch
+	parent, err := g.run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		parent = "" // new branch
+	}
+	blob, err := g.run("hash-object", "-w", "--", src)
+	if err != nil {
+		return "", err
+	}
+
+	// Build the tree in a scratch index so the real one is untouched
+	tmpDir, err := os.MkdirTemp("", "gt-index-")
+	if err != nil {
+		return "", fmt.Errorf("creating scratch index: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+	if parent != "" {
+		if _, err := g.runEnv(env, "read-tree", parent); err != nil {
+			return "", err
+		}
+	}
+	if _, err := g.runEnv(env, "update-index", "--add", "--cacheinfo", "100644,"+blob+","+filepath.ToSlash(path)); err != nil {
+		return "", err
+	}
+	tree, err := g.runEnv(env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	commit, err := g.run(args...)
+	if err != nil {
+		return "", err
+	}
+	// Only move the branch if nobody else has since
+	if _, err := g.run("update-ref", ref, commit, parent); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -890,6 +958,113 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// FileStat is one file's line counts in a diff.
+type FileStat struct {
+	Path       string `json:"path"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Binary     bool   `json:"binary,omitempty"`
+}
+
+// DiffStat summarizes the changes between two commits.
+type DiffStat struct {
+	Files      []FileStat `json:"files"`
+	Insertions int        `json:"insertions"`
+	Deletions  int        `json:"deletions"`
+}
+
+// DiffStat returns per-file line counts for the changes from one commit to
+// another (git diff --numstat from to).
+func (g *Git) DiffStat(from, to string) (*DiffStat, error) {
+	out, err := g.run("diff", "--numstat", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(out), nil
+}
+
+// parseNumstat parses `git diff --numstat` output. Binary files report "-"
+// for both counts.
+func parseNumstat(out string) *DiffStat {
+	stat := &DiffStat{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		fs := FileStat{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			fs.Binary = true
+		} else {
+			fs.Insertions, _ = strconv.Atoi(fields[0])
+			fs.Deletions, _ = strconv.Atoi(fields[1])
+		}
+		stat.Files = append(stat.Files, fs)
+		stat.Insertions += fs.Insertions
+		stat.Deletions += fs.Deletions
+	}
+	return stat
+}
+
+// CommitsBetween returns the SHAs of commits reachable from head but not
+// base, oldest first.
+func (g *Git) CommitsBetween(base, head string) ([]string, error) {
+	out, err := g.run("rev-list", "--reverse", base+".."+head)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// LogEntry is a commit as Log reports it.
+type LogEntry struct {
+	Hash    string
+	Author  string
+	Time    time.Time // author date
+	Parents int       // more than one for a merge
+	Tags    []string  // tags pointing at it
+	Subject string
+}
+
+// Log returns the commits reachable from ref (HEAD if empty), newest
+// first and never before their parents: those after since unless it is
+// zero, and at most limit of them unless it is 0.
+func (g *Git) Log(ref string, since time.Time, limit int) ([]LogEntry, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	args := []string{"log", "--topo-order", "--decorate=short", "--format=%H%x1f%an%x1f%aI%x1f%P%x1f%D%x1f%s"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	out, err := g.run(append(args, ref, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	var entries []LogEntry
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 6 {
+			continue
+		}
+		e := LogEntry{Hash: fields[0], Author: fields[1], Parents: len(strings.Fields(fields[3])), Subject: fields[5]}
+		e.Time, _ = time.Parse(time.RFC3339, fields[2])
+		for _, d := range strings.Split(fields[4], ", ") {
+			if tag, ok := strings.CutPrefix(d, "tag: "); ok {
+				e.Tags = append(e.Tags, tag)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func initTestRepo(t *testing.T) string {
@@ -242,6 +243,45 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestLog(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("checkout", "-q", "-b", "feature")
+	run("commit", "-q", "--allow-empty", "-m", "feat(parser): handle tabs")
+	run("checkout", "-q", "-")
+	run("merge", "-q", "--no-ff", "-m", "Merge branch 'feature'", "feature")
+	run("tag", "v1.0")
+
+	entries, err := g.Log("", time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Log = %+v, want 3 commits", entries)
+	}
+	merge := entries[0]
+	if merge.Subject != "Merge branch 'feature'" || merge.Parents != 2 || len(merge.Tags) != 1 || merge.Tags[0] != "v1.0" {
+		t.Errorf("newest = %+v, want the tagged merge", merge)
+	}
+	if merge.Author != "Test User" || merge.Time.IsZero() || len(merge.Hash) != 40 {
+		t.Errorf("newest = %+v", merge)
+	}
+	if entries[2].Subject != "initial" || entries[2].Parents != 0 {
+		t.Errorf("oldest = %+v, want the root commit", entries[2])
+	}
+	if entries, err := g.Log("", time.Time{}, 1); err != nil || len(entries) != 1 {
+		t.Errorf("Log limit 1 = %d entries, %v", len(entries), err)
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
@@ -488,3 +528,92 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestParseNumstat(t *testing.T) {
+	stat := parseNumstat("10\t2\tinternal/cmd/status.go\n-\t-\tdocs/logo.png\n3\t0\tREADME.md")
+	if len(stat.Files) != 3 {
+		t.Fatalf("Files = %+v, want 3", stat.Files)
+	}
+	if stat.Insertions != 13 || stat.Deletions != 2 {
+		t.Errorf("totals = +%d/-%d, want +13/-2", stat.Insertions, stat.Deletions)
+	}
+	if !stat.Files[1].Binary || stat.Files[1].Path != "docs/logo.png" {
+		t.Errorf("binary file = %+v", stat.Files[1])
+	}
+}
+
+func TestDiffStatAndCommitsBetween(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, content := range []string{"one\n", "one\ntwo\n"} {
+		if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add("notes.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Commit("change " + string(rune('a'+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commits, err := g.CommitsBetween(base, "HEAD")
+	if err != nil {
+		t.Fatalf("CommitsBetween: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Errorf("commits = %v, want 2", commits)
+	}
+
+	stat, err := g.DiffStat(base, "HEAD")
+	if err != nil {
+		t.Fatalf("DiffStat: %v", err)
+	}
+	if len(stat.Files) != 1 || stat.Files[0].Path != "notes.txt" || stat.Insertions != 2 {
+		t.Errorf("stat = %+v", stat)
+	}
+}
+
+func TestCommitFileToBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	before, _ := g.CurrentBranch()
+
+	src := filepath.Join(t.TempDir(), "one.md")
+	if err := os.WriteFile(src, []byte("chapter one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := g.CommitFileToBranch("narrative", "chapters/one.md", src, "Chapter 1")
+	if err != nil {
+		t.Fatalf("CommitFileToBranch (new branch): %v", err)
+	}
+	if err := os.WriteFile(src, []byte("chapter two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second, err := g.CommitFileToBranch("narrative", "chapters/two.md", src, "Chapter 2")
+	if err != nil {
+		t.Fatalf("CommitFileToBranch (existing branch): %v", err)
+	}
+
+	if ok, _ := g.IsAncestor(first, second); !ok {
+		t.Error("second chapter commit doesn't follow the first")
+	}
+	files, err := g.run("ls-tree", "-r", "--name-only", "narrative")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != "chapters/one.md\nchapters/two.md" {
+		t.Errorf("narrative tree = %q, want both chapters only", files)
+	}
+	if current, _ := g.CurrentBranch(); current != before {
+		t.Errorf("current branch = %q, want %q unchanged", current, before)
+	}
+	if status, err := g.Status(); err != nil || !status.Clean {
+		t.Errorf("working tree not clean after commit: %+v, %v", status, err)
+	}
+}
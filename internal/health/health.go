@@ -0,0 +1,328 @@
+// Package health scores each rig's health from recent events.
+//
+// A rig's score starts at 100 and loses points for merge failures (by
+// failure rate), session deaths, polecats found stuck by the witness, and
+// escalations raised in the last Window. The trend compares the score with
+// the window before it. The daemon records each rig's grade and emits a
+// rig_health_changed event when it moves.
+//
+// Agents also report their own health: each writes a Probe (phase, last
+// activity, queue depth) as it cycles, and a Manager judges them.
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Window is how far back events count toward a rig's score.
+const Window = 24 * time.Hour
+
+// Grades, from best to worst.
+const (
+	GradeHealthy  = "healthy"  // score >= 80
+	GradeDegraded = "degraded" // score >= 50
+	GradeCritical = "critical" // score < 50
+)
+
+// Trends against the previous window.
+const (
+	TrendUp     = "up"
+	TrendDown   = "down"
+	TrendSteady = "steady"
+)
+
+// trendThreshold is the score change that counts as a trend rather than
+// noise.
+const trendThreshold = 5
+
+// Penalties. Each signal's total penalty is capped so no single noisy
+// signal zeroes a rig on its own.
+const (
+	mergeFailurePenalty = 40 // at a 100% failure rate
+	sessionDeathPenalty = 5
+	sessionDeathCap     = 25
+	stuckPenalty        = 5
+	stuckCap            = 20
+	escalationPenalty   = 10
+	escalationCap       = 30
+)
+
+// Signals are the events counted toward a rig's score.
+type Signals struct {
+	Merges        int `json:"merges"`
+	MergeFailures int `json:"merge_failures"`
+	SessionDeaths int `json:"session_deaths"`
+	Stuck         int `json:"stuck"`
+	Escalations   int `json:"escalations"`
+}
+
+// Score is a rig's health.
+type Score struct {
+	Rig      string  `json:"rig"`
+	Score    int     `json:"score"` // 0-100
+	Grade    string  `json:"grade"`
+	Trend    string  `json:"trend"`
+	Previous int     `json:"previous"` // score over the window before
+	Signals  Signals `json:"signals"`
+}
+
+// Arrow returns a one-character trend indicator.
+func (s *Score) Arrow() string {
+	switch s.Trend {
+	case TrendUp:
+		return "↑"
+	case TrendDown:
+		return "↓"
+	default:
+		return "→"
+	}
+}
+
+// Compute scores every rig that appears in records. Rigs with no events in
+// either window score 100.
+func Compute(records []events.Record, now time.Time) map[string]*Score {
+	current := make(map[string]*Signals)
+	previous := make(map[string]*Signals)
+
+	for _, rec := range records {
+		ts, err := time.Parse(time.RFC3339, rec.Event.Timestamp)
+		if err != nil || ts.After(now) {
+			continue
+		}
+		var bucket map[string]*Signals
+		switch age := now.Sub(ts); {
+		case age <= Window:
+			bucket = current
+		case age <= 2*Window:
+			bucket = previous
+		default:
+			continue
+		}
+		rig := eventRig(rec.Event)
+		if rig == "" {
+			continue
+		}
+		if bucket[rig] == nil {
+			bucket[rig] = &Signals{}
+		}
+		count(bucket[rig], rec.Event)
+	}
+
+	scores := make(map[string]*Score)
+	for _, m := range []map[string]*Signals{current, previous} {
+		for rig := range m {
+			if scores[rig] != nil {
+				continue
+			}
+			var cur, prev Signals
+			if s := current[rig]; s != nil {
+				cur = *s
+			}
+			if s := previous[rig]; s != nil {
+				prev = *s
+			}
+			score := ScoreSignals(cur)
+			prevScore := ScoreSignals(prev)
+			scores[rig] = &Score{
+				Rig:      rig,
+				Score:    score,
+				Grade:    GradeFor(score),
+				Trend:    trend(score, prevScore),
+				Previous: prevScore,
+				Signals:  cur,
+			}
+		}
+	}
+	return scores
+}
+
+// Tracker keeps the events rig scores count between calls, so that
+// scoring every heartbeat reads only the events logged since the last.
+type Tracker struct {
+	townRoot string
+	records  []events.Record // the last two windows' events
+	through  time.Time       // start of the second last read up to
+}
+
+// NewTracker returns a Tracker that has read nothing yet.
+func NewTracker(townRoot string) *Tracker {
+	return &Tracker{townRoot: townRoot}
+}
+
+// Update reads the events logged since the last update (the last two
+// windows' on the first), forgets those too old to count, and scores the
+// rigs as Compute does.
+func (t *Tracker) Update(now time.Time) (map[string]*Score, error) {
+	cutoff := now.Add(-2 * Window)
+	since := cutoff
+	if t.through.After(since) {
+		// Timestamps have second resolution: reread the last second read,
+		// in case more was logged in it
+		since = t.through
+	}
+	fresh, err := events.Query(t.townRoot, events.Filter{Since: since})
+	if err != nil {
+		return nil, err
+	}
+	kept := t.records[:0]
+	for _, r := range t.records {
+		ts, err := time.Parse(time.RFC3339, r.Timestamp)
+		if err != nil || ts.Before(cutoff) || !ts.Before(since) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	t.records = append(kept, fresh...)
+	t.through = now.UTC().Truncate(time.Second)
+	return Compute(t.records, now), nil
+}
+
+// ForRig returns the rig's score, or a perfect score if it had no events.
+func ForRig(scores map[string]*Score, rig string) *Score {
+	if s, ok := scores[rig]; ok {
+		return s
+	}
+	return &Score{Rig: rig, Score: 100, Grade: GradeHealthy, Trend: TrendSteady, Previous: 100}
+}
+
+// eventRig returns the rig an event counts against. Session deaths are
+// logged by session name, so their payload's agent address is used.
+func eventRig(e events.Event) string {
+	if rig := events.EventRig(e); rig != "" {
+		return rig
+	}
+	if agent, ok := e.Payload["agent"].(string); ok {
+		if rig, _, found := strings.Cut(agent, "/"); found {
+			// Validated as EventRig validates actors
+			return events.EventRig(events.Event{Actor: rig + "/"})
+		}
+	}
+	return ""
+}
+
+func count(s *Signals, e events.Event) {
+	switch e.Type {
+	case events.TypeMerged:
+		s.Merges++
+	case events.TypeMergeFailed:
+		s.Merges++
+		s.MergeFailures++
+	case events.TypeSessionDeath:
+		s.SessionDeaths++
+	case events.TypePolecatChecked:
+		if status, _ := e.Payload["status"].(string); status == "stuck" {
+			s.Stuck++
+		}
+	case events.TypeEscalationSent:
+		s.Escalations++
+	}
+}
+
+// ScoreSignals turns signal counts into a 0-100 score.
+func ScoreSignals(s Signals) int {
+	score := 100
+	if s.Merges > 0 {
+		score -= mergeFailurePenalty * s.MergeFailures / s.Merges
+	}
+	score -= min(s.SessionDeaths*sessionDeathPenalty, sessionDeathCap)
+	score -= min(s.Stuck*stuckPenalty, stuckCap)
+	score -= min(s.Escalations*escalationPenalty, escalationCap)
+	return max(score, 0)
+}
+
+// GradeFor returns the grade for a score.
+func GradeFor(score int) string {
+	switch {
+	case score >= 80:
+		return GradeHealthy
+	case score >= 50:
+		return GradeDegraded
+	default:
+		return GradeCritical
+	}
+}
+
+func trend(score, previous int) string {
+	switch {
+	case score-previous >= trendThreshold:
+		return TrendUp
+	case previous-score >= trendThreshold:
+		return TrendDown
+	default:
+		return TrendSteady
+	}
+}
+
+// recorded is the last grade the daemon recorded for a rig.
+type recorded struct {
+	Score int    `json:"score"`
+	Grade string `json:"grade"`
+}
+
+// StatePath returns where the last recorded grades are kept.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "rig_health.json")
+}
+
+// Change is a rig whose grade differs from the last recorded one.
+type Change struct {
+	Score         *Score
+	PreviousScore int
+	PreviousGrade string // "" if the rig had no recorded grade
+}
+
+// Track records the current scores and returns the rigs whose grade
+// changed since the last call, sorted by rig. A rig seen for the first time
+// is reported only if it isn't healthy.
+func Track(townRoot string, scores map[string]*Score) ([]Change, error) {
+	path := StatePath(townRoot)
+	last := make(map[string]recorded)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &last); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+
+	var changes []Change
+	for rig, s := range scores {
+		prev, seen := last[rig]
+		switch {
+		case seen && prev.Grade != s.Grade:
+			changes = append(changes, Change{Score: s, PreviousScore: prev.Score, PreviousGrade: prev.Grade})
+		case !seen && s.Grade != GradeHealthy:
+			changes = append(changes, Change{Score: s, PreviousScore: 100})
+		}
+		last[rig] = recorded{Score: s.Score, Grade: s.Grade}
+	}
+	// Rigs whose events aged out are back to a clean slate
+	for rig, prev := range last {
+		if _, ok := scores[rig]; !ok && prev.Grade != GradeHealthy {
+			s := ForRig(scores, rig)
+			changes = append(changes, Change{Score: s, PreviousScore: prev.Score, PreviousGrade: prev.Grade})
+			last[rig] = recorded{Score: s.Score, Grade: s.Grade}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Score.Rig < changes[j].Score.Rig })
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteJSON(path, last); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func record(ts time.Time, typ, actor string, payload map[string]interface{}) events.Record {
+	return events.Record{Event: events.Event{
+		Timestamp: ts.Format(time.RFC3339),
+		Type:      typ,
+		Actor:     actor,
+		Payload:   payload,
+	}}
+}
+
+func TestScoreSignals(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals Signals
+		want    int
+	}{
+		{"clean", Signals{}, 100},
+		{"all merges fail", Signals{Merges: 4, MergeFailures: 4}, 60},
+		{"half merges fail", Signals{Merges: 4, MergeFailures: 2}, 80},
+		{"deaths capped", Signals{SessionDeaths: 20}, 75},
+		{"everything", Signals{Merges: 1, MergeFailures: 1, SessionDeaths: 10, Stuck: 10, Escalations: 10}, 0},
+	}
+	for _, tt := range tests {
+		if got := ScoreSignals(tt.signals); got != tt.want {
+			t.Errorf("%s: ScoreSignals = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	older := now.Add(-30 * time.Hour)
+	records := []events.Record{
+		record(recent, events.TypeMergeFailed, "gastown/refinery", map[string]interface{}{"rig": "gastown"}),
+		record(recent, events.TypeMergeFailed, "gastown/refinery", map[string]interface{}{"rig": "gastown"}),
+		record(recent, events.TypeEscalationSent, "gastown/witness", nil),
+		record(recent, events.TypeSessionDeath, "daemon", map[string]interface{}{"agent": "gastown/polecats/nux"}),
+		record(recent, events.TypePolecatChecked, "gastown/witness", map[string]interface{}{"status": "stuck"}),
+		record(older, events.TypeMerged, "beads/refinery", map[string]interface{}{"rig": "beads"}),
+		record(older, events.TypeEscalationSent, "beads/witness", nil),
+		record(now.Add(-72*time.Hour), events.TypeEscalationSent, "longago/witness", nil),
+	}
+
+	scores := Compute(records, now)
+
+	g := scores["gastown"]
+	if g == nil {
+		t.Fatal("gastown not scored")
+	}
+	want := Signals{Merges: 2, MergeFailures: 2, SessionDeaths: 1, Stuck: 1, Escalations: 1}
+	if g.Signals != want {
+		t.Errorf("gastown signals = %+v, want %+v", g.Signals, want)
+	}
+	if g.Score != 40 || g.Grade != GradeCritical || g.Trend != TrendDown {
+		t.Errorf("gastown = %d %s %s, want 40 critical down", g.Score, g.Grade, g.Trend)
+	}
+
+	b := scores["beads"]
+	if b == nil {
+		t.Fatal("beads not scored")
+	}
+	if b.Score != 100 || b.Previous != 90 || b.Trend != TrendUp {
+		t.Errorf("beads = %d (previous %d) %s, want 100 (previous 90) up", b.Score, b.Previous, b.Trend)
+	}
+
+	if _, ok := scores["longago"]; ok {
+		t.Error("events older than two windows should not be scored")
+	}
+	if s := ForRig(scores, "quiet"); s.Score != 100 || s.Grade != GradeHealthy {
+		t.Errorf("ForRig(quiet) = %+v, want a perfect score", s)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	townRoot := t.TempDir()
+
+	critical := map[string]*Score{
+		"gastown": {Rig: "gastown", Score: 40, Grade: GradeCritical},
+		"beads":   {Rig: "beads", Score: 100, Grade: GradeHealthy},
+	}
+	changes, err := Track(townRoot, critical)
+	if err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Score.Rig != "gastown" || changes[0].PreviousGrade != "" {
+		t.Fatalf("first Track changes = %+v, want only gastown as new", changes)
+	}
+
+	changes, err = Track(townRoot, critical)
+	if err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("unchanged grades reported %d changes", len(changes))
+	}
+
+	// gastown's events aged out entirely
+	changes, err = Track(townRoot, map[string]*Score{"beads": critical["beads"]})
+	if err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Score.Grade != GradeHealthy || changes[0].PreviousGrade != GradeCritical {
+		t.Errorf("aged-out changes = %+v, want gastown back to healthy", changes)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+	escalate := func(ts time.Time) {
+		t.Helper()
+		if err := events.Append(townRoot, events.Event{Timestamp: ts.Format(time.RFC3339), Source: "gt",
+			Type: events.TypeEscalationSent, Actor: "gastown/witness", Visibility: events.VisibilityFeed}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	escalate(now.Add(-72 * time.Hour))
+	escalate(now.Add(-time.Hour))
+
+	tracker := NewTracker(townRoot)
+	scores, err := tracker.Update(now)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := ForRig(scores, "gastown").Signals.Escalations; got != 1 || len(tracker.records) != 1 {
+		t.Errorf("escalations = %d with %d records kept, want only the last window's", got, len(tracker.records))
+	}
+
+	// Logged in the second last read: reread, but counted once
+	escalate(now)
+	for i := 0; i < 2; i++ {
+		if scores, err = tracker.Update(now.Add(time.Second)); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if got := ForRig(scores, "gastown").Signals.Escalations; got != 2 {
+			t.Errorf("update %d: escalations = %d, want 2", i+1, got)
+		}
+	}
+
+	if _, err := tracker.Update(now.Add(49 * time.Hour)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(tracker.records) != 0 {
+		t.Errorf("%d records kept after both windows passed, want none", len(tracker.records))
+	}
+}
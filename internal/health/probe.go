@@ -0,0 +1,215 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Agent phases a probe can report. Phases are free-form; these are the
+// ones the checks know about.
+const (
+	PhaseStarting = "starting"
+	PhaseIdle     = "idle"
+	PhaseWorking  = "working"
+	PhasePatrol   = "patrol"
+	PhaseStuck    = "stuck" // the agent knows it can't make progress
+	PhaseError    = "error"
+)
+
+// DefaultProbeInterval is how often an agent is expected to write its probe
+// when the probe doesn't say.
+const DefaultProbeInterval = 5 * time.Minute
+
+// Thresholds for judging a probe.
+const (
+	// missedProbes is how many intervals may pass without a probe before
+	// the agent counts as unresponsive.
+	missedProbes = 3
+
+	// idleWithQueue is how long an agent may go without activity while it
+	// has queued work.
+	idleWithQueue = 30 * time.Minute
+)
+
+// Probe is an agent's self-reported health, written to
+// .runtime/health/<role>.json each time it cycles. It says more than
+// whether the session's process is alive: what the agent is doing, when it
+// last did something, and how much work is waiting on it.
+type Probe struct {
+	Role         string    `json:"role"` // agent address, e.g. "mayor" or "gastown/witness"
+	Session      string    `json:"session,omitempty"`
+	Phase        string    `json:"phase"`
+	Detail       string    `json:"detail,omitempty"` // what the phase is about
+	LastActivity time.Time `json:"last_activity"`
+	QueueDepth   int       `json:"queue_depth"`
+	Interval     string    `json:"interval,omitempty"` // how often the agent probes (Go duration)
+	WrittenAt    time.Time `json:"written_at"`
+}
+
+// interval returns how often the agent promises to probe.
+func (p *Probe) interval() time.Duration {
+	if d, err := time.ParseDuration(p.Interval); err == nil && d > 0 {
+		return d
+	}
+	return DefaultProbeInterval
+}
+
+// ProbeDir returns the directory agents write their probes to.
+func ProbeDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "health")
+}
+
+// ProbePath returns the probe file for an agent address. Slashes in the
+// address become dots: "gastown/witness" is gastown.witness.json.
+func ProbePath(townRoot, role string) string {
+	name := strings.ReplaceAll(normalizeRole(role), "/", ".")
+	return filepath.Join(ProbeDir(townRoot), name+".json")
+}
+
+// normalizeRole drops the trailing slash of town-level addresses
+// ("mayor/" is "mayor").
+func normalizeRole(role string) string {
+	return strings.TrimSuffix(strings.TrimSpace(role), "/")
+}
+
+// AgentHealth is an agent's health judged from its probe.
+type AgentHealth struct {
+	Role    string `json:"role"`
+	Healthy bool   `json:"healthy"`
+	Reason  string `json:"reason,omitempty"` // why it is unhealthy
+	Probe   *Probe `json:"probe,omitempty"`  // nil if it never probed
+}
+
+// Manager reads and writes agent probes for a town.
+type Manager struct {
+	townRoot string
+
+	// SessionAlive, if set, reports whether the agent in a probe's
+	// session is running, so a probe left behind by a dead session isn't
+	// taken at its word.
+	SessionAlive func(session string) bool
+
+	now func() time.Time
+}
+
+// NewManager returns a probe manager for a town.
+func NewManager(townRoot string) *Manager {
+	return &Manager{townRoot: townRoot, now: time.Now}
+}
+
+// Report writes an agent's probe, stamping when it was written and, if
+// unset, its last activity.
+func (m *Manager) Report(p *Probe) error {
+	p.Role = normalizeRole(p.Role)
+	if p.Role == "" {
+		return errors.New("probe has no role")
+	}
+	if p.Interval != "" {
+		if d, err := time.ParseDuration(p.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid probe interval %q", p.Interval)
+		}
+	}
+	p.WrittenAt = m.now().UTC()
+	if p.LastActivity.IsZero() {
+		p.LastActivity = p.WrittenAt
+	}
+	path := ProbePath(m.townRoot, p.Role)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, p)
+}
+
+// Probe returns an agent's last probe, or nil if it has never written one.
+func (m *Manager) Probe(role string) (*Probe, error) {
+	return readProbe(ProbePath(m.townRoot, role))
+}
+
+func readProbe(path string) (*Probe, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Probe
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// HealthOf judges an agent's health from its probe. An agent is unhealthy
+// if it never probed, has missed several probes, reports itself stuck or
+// in error, has had work queued with no activity for a while, or its
+// session is gone.
+func (m *Manager) HealthOf(role string) (*AgentHealth, error) {
+	p, err := m.Probe(role)
+	if err != nil {
+		return nil, err
+	}
+	return m.judge(normalizeRole(role), p), nil
+}
+
+// All judges every agent that has written a probe, sorted by role.
+func (m *Manager) All() ([]*AgentHealth, error) {
+	paths, err := filepath.Glob(filepath.Join(ProbeDir(m.townRoot), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var all []*AgentHealth
+	for _, path := range paths {
+		p, err := readProbe(path)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil || p.Role == "" {
+			continue
+		}
+		all = append(all, m.judge(p.Role, p))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Role < all[j].Role })
+	return all, nil
+}
+
+func (m *Manager) judge(role string, p *Probe) *AgentHealth {
+	h := &AgentHealth{Role: role, Probe: p}
+	if p == nil {
+		h.Reason = "no health probe"
+		return h
+	}
+	now := m.now()
+	switch age := now.Sub(p.WrittenAt); {
+	case age > missedProbes*p.interval():
+		h.Reason = fmt.Sprintf("no probe for %s (expected every %s)", roundAge(age), p.interval())
+	case p.Phase == PhaseStuck || p.Phase == PhaseError:
+		h.Reason = p.Phase
+		if p.Detail != "" {
+			h.Reason += ": " + p.Detail
+		}
+	case p.QueueDepth > 0 && now.Sub(p.LastActivity) > idleWithQueue:
+		h.Reason = fmt.Sprintf("%d queued, no activity for %s", p.QueueDepth, roundAge(now.Sub(p.LastActivity)))
+	case p.Session != "" && m.SessionAlive != nil && !m.SessionAlive(p.Session):
+		h.Reason = "session " + p.Session + " not running"
+	default:
+		h.Healthy = true
+	}
+	return h
+}
+
+func roundAge(d time.Duration) time.Duration {
+	if d >= time.Hour {
+		return d.Round(time.Minute)
+	}
+	return d.Round(time.Second)
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHealthOf(t *testing.T) {
+	m := NewManager(t.TempDir())
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	alive := map[string]bool{"gt-gastown-witness": true, "gt-gastown-refinery": true}
+	m.SessionAlive = func(session string) bool { return alive[session] }
+
+	if h, err := m.HealthOf("mayor/"); err != nil || h.Healthy || h.Reason != "no health probe" {
+		t.Errorf("HealthOf(mayor) = %+v, %v; want no probe", h, err)
+	}
+
+	report := func(p *Probe, at time.Time) {
+		t.Helper()
+		m.now = func() time.Time { return at }
+		if err := m.Report(p); err != nil {
+			t.Fatalf("Report: %v", err)
+		}
+		m.now = func() time.Time { return now }
+	}
+	report(&Probe{Role: "gastown/witness", Session: "gt-gastown-witness", Phase: PhasePatrol}, now.Add(-time.Minute))
+	report(&Probe{Role: "gastown/refinery", Session: "gt-gastown-refinery", Phase: PhaseIdle, QueueDepth: 4,
+		LastActivity: now.Add(-time.Hour)}, now.Add(-time.Minute))
+	report(&Probe{Role: "gastown/Toast", Session: "gt-gastown-Toast", Phase: PhaseWorking}, now.Add(-time.Minute))
+	report(&Probe{Role: "deacon/", Phase: PhasePatrol, Interval: "1m"}, now.Add(-5*time.Minute))
+	report(&Probe{Role: "gastown/Nux", Phase: PhaseStuck, Detail: "merge conflict"}, now)
+
+	want := map[string]string{
+		"deacon":           "no probe for 5m0s (expected every 1m0s)",
+		"gastown/Nux":      "stuck: merge conflict",
+		"gastown/Toast":    "session gt-gastown-Toast not running",
+		"gastown/refinery": "4 queued, no activity for 1h0m0s",
+		"gastown/witness":  "",
+	}
+	all, err := m.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != len(want) {
+		t.Fatalf("All = %d agents, want %d", len(all), len(want))
+	}
+	for _, h := range all {
+		if h.Reason != want[h.Role] || h.Healthy != (want[h.Role] == "") {
+			t.Errorf("%s: healthy=%v reason %q, want %q", h.Role, h.Healthy, h.Reason, want[h.Role])
+		}
+	}
+	if h, _ := m.HealthOf("gastown/witness"); !h.Healthy || h.Probe.LastActivity.IsZero() {
+		t.Errorf("HealthOf(witness) = %+v, want healthy with activity defaulted", h)
+	}
+}
+
+func TestReport_Invalid(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if err := m.Report(&Probe{Phase: PhaseIdle}); err == nil {
+		t.Error("probe without a role should be rejected")
+	}
+	if err := m.Report(&Probe{Role: "mayor", Interval: "soon"}); err == nil {
+		t.Error("probe with a bad interval should be rejected")
+	}
+}
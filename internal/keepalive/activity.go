@@ -0,0 +1,133 @@
+package keepalive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// activityFile is the town's activity index in the daemon directory.
+const activityFile = "activity.json"
+
+// ActivityPath returns the path to a town's activity index: the latest
+// keepalive of every workspace, in one file the daemon can watch instead of
+// polling each workspace.
+func ActivityPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", activityFile)
+}
+
+// Activity maps workspaces (relative to the town root, slash-separated) to
+// their latest keepalive.
+type Activity map[string]State
+
+// ReadActivity returns the town's activity index. Like Read it degrades
+// gracefully: a missing or unparseable index is empty.
+func ReadActivity(townRoot string) Activity {
+	data, err := os.ReadFile(ActivityPath(townRoot))
+	if err != nil {
+		return Activity{}
+	}
+	activity := Activity{}
+	if err := json.Unmarshal(data, &activity); err != nil {
+		return Activity{}
+	}
+	return activity
+}
+
+// recordActivity adds a workspace's keepalive to its town's activity index.
+// It silently ignores errors (best-effort signaling).
+func recordActivity(workspaceRoot string, state State) {
+	townRoot, err := workspace.Find(workspaceRoot)
+	if err != nil || townRoot == "" {
+		return
+	}
+	rel, err := filepath.Rel(townRoot, workspaceRoot)
+	if err != nil {
+		return
+	}
+	path := ActivityPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = updateFile(path, func(current []byte) (interface{}, error) {
+		activity := Activity{}
+		if len(current) > 0 {
+			_ = json.Unmarshal(current, &activity) // a corrupt index is rebuilt
+		}
+		activity[filepath.ToSlash(rel)] = state
+		return activity, nil
+	})
+}
+
+// Change is a workspace whose keepalive moved.
+type Change struct {
+	Workspace string // relative to the town root, slash-separated
+	State     State
+}
+
+// WatchActivity notifies of keepalive changes in a town until ctx is done,
+// when the channel is closed. Each send lists the workspaces whose
+// keepalive changed since the last, sorted by workspace. On Linux the
+// activity index is watched with inotify; elsewhere its modification time
+// is checked every second.
+func WatchActivity(ctx context.Context, townRoot string) (<-chan []Change, error) {
+	path := ActivityPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	notify, err := watchFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan []Change)
+	go func() {
+		defer close(changes)
+		last := ReadActivity(townRoot)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+			current := ReadActivity(townRoot)
+			diff := diffActivity(last, current)
+			last = current
+			if len(diff) == 0 {
+				continue
+			}
+			select {
+			case changes <- diff:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// diffActivity returns the workspaces whose keepalive differs in current.
+func diffActivity(last, current Activity) []Change {
+	var diff []Change
+	for ws, state := range current {
+		if prev, ok := last[ws]; ok && prev.LastCommand == state.LastCommand && prev.Timestamp.Equal(state.Timestamp) {
+			continue
+		}
+		diff = append(diff, Change{Workspace: ws, State: state})
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Workspace < diff[j].Workspace })
+	return diff
+}
+
+// signal sends on a wake-up channel without blocking; a pending wake-up
+// already covers this one.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
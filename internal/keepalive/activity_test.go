@@ -0,0 +1,103 @@
+package keepalive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTown creates a town root with a rig workspace inside it.
+func newTown(t *testing.T) (townRoot, workDir string) {
+	t.Helper()
+	townRoot = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	workDir = filepath.Join(townRoot, "gastown", "witness")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot, workDir
+}
+
+func TestTouchRecordsActivity(t *testing.T) {
+	townRoot, workDir := newTown(t)
+	TouchInWorkspace(workDir, "gt patrol")
+	TouchInWorkspace(filepath.Join(townRoot, "mayor"), "gt mail check")
+
+	activity := ReadActivity(townRoot)
+	if len(activity) != 2 || activity["gastown/witness"].LastCommand != "gt patrol" || activity["mayor"].LastCommand != "gt mail check" {
+		t.Errorf("activity = %+v", activity)
+	}
+
+	// Outside a town only the workspace keepalive is written
+	other := t.TempDir()
+	TouchInWorkspace(other, "gt status")
+	if _, err := os.Stat(ActivityPath(other)); !os.IsNotExist(err) {
+		t.Errorf("activity index written outside a town: %v", err)
+	}
+}
+
+func TestTouchConcurrent(t *testing.T) {
+	townRoot, _ := newTown(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		workDir := filepath.Join(townRoot, "gastown", "polecats", string(rune('a'+i)))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				TouchInWorkspace(workDir, "gt hook")
+				if Read(workDir) == nil {
+					t.Error("read a torn or missing keepalive during concurrent writes")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(ReadActivity(townRoot)); got != 8 {
+		t.Errorf("activity index has %d workspaces, want 8 (a concurrent update was lost)", got)
+	}
+}
+
+func TestWatchActivity(t *testing.T) {
+	townRoot, workDir := newTown(t)
+	TouchInWorkspace(workDir, "gt patrol")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := WatchActivity(ctx, townRoot)
+	if err != nil {
+		t.Fatalf("WatchActivity: %v", err)
+	}
+
+	// Touch until the watch reports it; the first touch may race the
+	// watcher starting on platforms that poll
+	deadline := time.After(5 * time.Second)
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	TouchInWorkspace(workDir, "gt done")
+	for got := false; !got; {
+		select {
+		case batch := <-changes:
+			if len(batch) != 1 || batch[0].Workspace != "gastown/witness" || batch[0].State.LastCommand != "gt done" {
+				t.Fatalf("changes = %+v", batch)
+			}
+			got = true
+		case <-tick.C:
+			TouchInWorkspace(workDir, "gt done")
+		case <-deadline:
+			t.Fatal("no change reported for a touched workspace")
+		}
+	}
+
+	cancel()
+	for range changes {
+	}
+}
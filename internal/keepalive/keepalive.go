@@ -11,6 +11,11 @@
 // These files are used by the daemon to detect agent activity and implement
 // features like exponential backoff during idle periods.
 //
+// Writers hold an advisory lock (<file>.lock) and replace the file by
+// renaming a temp file over it, so a concurrent reader sees the old or the
+// new contents, never a torn write. Locks use gofrs/flock and work on Unix
+// and Windows.
+//
 // # Sentinel Pattern
 //
 // This package uses the nil sentinel pattern for graceful degradation:
@@ -33,15 +38,26 @@
 package keepalive
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+// keepaliveFile is the keepalive file in a workspace's .runtime directory.
+const keepaliveFile = "keepalive.json"
+
+// lockTimeout bounds how long a writer waits for another to finish. Signals
+// are best-effort, so a write that can't get the lock is dropped.
+const lockTimeout = 2 * time.Second
+
 // State represents the keepalive file contents.
 type State struct {
 	LastCommand string    `json:"last_command"`
@@ -86,13 +102,74 @@ func TouchInWorkspace(workspaceRoot, command string) {
 		Timestamp:   time.Now().UTC(),
 	}
 
-	data, err := json.Marshal(state)
+	keepalivePath := filepath.Join(runtimeDir, keepaliveFile)
+	_ = updateFile(keepalivePath, func([]byte) (interface{}, error) { return state, nil }) // non-fatal: status file for debugging
+
+	recordActivity(workspaceRoot, state)
+}
+
+// updateFile replaces a JSON file under its advisory lock. update receives
+// the current contents (nil if there are none) and returns the new value.
+func updateFile(path string, update func(current []byte) (interface{}, error)) error {
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 10*time.Millisecond)
 	if err != nil {
-		return
+		return fmt.Errorf("locking %s: %w", filepath.Base(path), err)
 	}
+	if !locked {
+		return fmt.Errorf("locking %s: timed out", filepath.Base(path))
+	}
+	defer func() { _ = lock.Unlock() }()
 
-	keepalivePath := filepath.Join(runtimeDir, "keepalive.json")
-	_ = os.WriteFile(keepalivePath, data, 0644) // non-fatal: status file for debugging
+	current, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed by this package
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	v, err := update(current)
+	if err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, v)
+}
+
+// DefaultHeartbeatInterval is how often StartHeartbeat refreshes the
+// keepalive when callers have no better interval.
+const DefaultHeartbeatInterval = time.Minute
+
+// heartbeatCommand is recorded when a heartbeat finds no earlier keepalive.
+const heartbeatCommand = "heartbeat"
+
+// StartHeartbeat refreshes the workspace's keepalive immediately and then
+// every interval until ctx is done, so a long-running command doesn't look
+// idle after its first touch. The last recorded command is kept; the
+// heartbeat only moves the timestamp forward.
+func StartHeartbeat(ctx context.Context, workspaceRoot string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	beat := func() {
+		command := heartbeatCommand
+		if state := Read(workspaceRoot); state != nil && state.LastCommand != "" {
+			command = state.LastCommand
+		}
+		TouchInWorkspace(workspaceRoot, command)
+	}
+
+	beat()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				beat()
+			}
+		}
+	}()
 }
 
 // Read returns the current keepalive state for the workspace.
@@ -101,7 +178,7 @@ func TouchInWorkspace(workspaceRoot, command string) {
 // when the keepalive file doesn't exist, can't be read, or contains invalid JSON.
 // Callers can safely pass the result to [State.Age] without nil checks.
 func Read(workspaceRoot string) *State {
-	keepalivePath := filepath.Join(workspaceRoot, ".runtime", "keepalive.json")
+	keepalivePath := filepath.Join(workspaceRoot, ".runtime", keepaliveFile)
 
 	data, err := os.ReadFile(keepalivePath)
 	if err != nil {
@@ -135,3 +212,31 @@ func (s *State) Age() time.Duration {
 	}
 	return time.Since(s.Timestamp)
 }
+
+// workspacePatterns locate keepalive files relative to the town root:
+// town-level agents (mayor/, deacon/), rig agents (gastown/witness) and
+// per-worker clones (gastown/polecats/nux, gastown/refinery/rig).
+var workspacePatterns = []string{
+	filepath.Join("*", ".runtime", keepaliveFile),
+	filepath.Join("*", "*", ".runtime", keepaliveFile),
+	filepath.Join("*", "*", "*", ".runtime", keepaliveFile),
+}
+
+// Workspaces returns every workspace under the town root that has a
+// keepalive file.
+func Workspaces(townRoot string) []string {
+	var workspaces []string
+	for _, pattern := range workspacePatterns {
+		matches, _ := filepath.Glob(filepath.Join(townRoot, pattern))
+		for _, path := range matches {
+			workspaces = append(workspaces, filepath.Dir(filepath.Dir(path)))
+		}
+	}
+	return workspaces
+}
+
+// IsStale reports whether the keepalive is older than threshold. Like Age it
+// accepts a nil receiver, so a missing keepalive is always stale.
+func (s *State) IsStale(threshold time.Duration) bool {
+	return s.Age() > threshold
+}
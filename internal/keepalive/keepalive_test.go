@@ -1,6 +1,7 @@
 package keepalive
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -59,8 +60,61 @@ func TestStateAge(t *testing.T) {
 		t.Errorf("expected ~5m age, got %v", age)
 	}
 
-	// NOTE: IsFresh(), IsStale(), IsVeryStale() were removed as part of ZFC cleanup.
-	// Staleness classification belongs in Deacon molecule, not Go code.
+	// NOTE: IsFresh(), IsVeryStale() were removed as part of ZFC cleanup.
+	// Staleness classification belongs in Deacon molecule, not Go code;
+	// IsStale only compares against a threshold the caller supplies.
+}
+
+func TestStateIsStale(t *testing.T) {
+	var nilState *State
+	if !nilState.IsStale(time.Hour) {
+		t.Error("nil state should be stale")
+	}
+
+	state := &State{Timestamp: time.Now().Add(-2 * time.Minute)}
+	if state.IsStale(5 * time.Minute) {
+		t.Error("2m-old state should not be stale at a 5m threshold")
+	}
+	if !state.IsStale(time.Minute) {
+		t.Error("2m-old state should be stale at a 1m threshold")
+	}
+}
+
+func TestStartHeartbeat(t *testing.T) {
+	tmpDir := t.TempDir()
+	TouchInWorkspace(tmpDir, "gt mol await-signal")
+	first := Read(tmpDir).Timestamp
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartHeartbeat(ctx, tmpDir, 10*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		state := Read(tmpDir)
+		if state != nil && state.Timestamp.After(first) {
+			if state.LastCommand != "gt mol await-signal" {
+				t.Errorf("heartbeat changed last_command to %q", state.LastCommand)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("heartbeat did not refresh the keepalive")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStartHeartbeatWithoutKeepalive(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	StartHeartbeat(ctx, tmpDir, time.Hour)
+
+	state := Read(tmpDir)
+	if state == nil || state.LastCommand != "heartbeat" {
+		t.Errorf("expected an initial heartbeat keepalive, got %+v", state)
+	}
 }
 
 func TestDirectoryCreation(t *testing.T) {
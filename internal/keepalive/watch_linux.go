@@ -0,0 +1,64 @@
+//go:build linux
+
+package keepalive
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchPollMillis is how long the inotify loop blocks before checking
+// whether it has been canceled.
+const watchPollMillis = 500
+
+// watchFile signals on the returned channel whenever path is replaced or
+// rewritten, until ctx is done. The file's directory is watched, since
+// writers replace the file by renaming over it.
+func watchFile(ctx context.Context, path string) (<-chan struct{}, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(path), unix.IN_MOVED_TO|unix.IN_CLOSE_WRITE); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+
+	name := []byte(filepath.Base(path))
+	notify := make(chan struct{}, 1)
+	go func() {
+		defer func() { _ = unix.Close(fd) }()
+		buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}} //nolint:gosec // G115: fds fit in int32
+		for ctx.Err() == nil {
+			n, err := unix.Poll(fds, watchPollMillis)
+			if err != nil && err != unix.EINTR {
+				return
+			}
+			if n <= 0 {
+				continue
+			}
+			read, err := unix.Read(fd, buf)
+			if err != nil {
+				continue // EAGAIN: nothing to read after all
+			}
+			for off := 0; off+unix.SizeofInotifyEvent <= read; {
+				event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off])) //nolint:gosec // G103: decoding kernel inotify records
+				nameStart := off + unix.SizeofInotifyEvent
+				nameEnd := nameStart + int(event.Len)
+				if nameEnd > read {
+					break
+				}
+				if bytes.Equal(bytes.TrimRight(buf[nameStart:nameEnd], "\x00"), name) {
+					signal(notify)
+				}
+				off = nameEnd
+			}
+		}
+	}()
+	return notify, nil
+}
@@ -0,0 +1,45 @@
+//go:build !linux
+
+package keepalive
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// watchPollInterval is how often a file's modification time is checked on
+// platforms without inotify.
+const watchPollInterval = time.Second
+
+// watchFile signals on the returned channel whenever path's modification
+// time or size changes, until ctx is done.
+func watchFile(ctx context.Context, path string) (<-chan struct{}, error) {
+	stamp := func() (time.Time, int64) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return info.ModTime(), info.Size()
+	}
+
+	notify := make(chan struct{}, 1)
+	go func() {
+		lastMod, lastSize := stamp()
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			mod, size := stamp()
+			if !mod.Equal(lastMod) || size != lastSize {
+				lastMod, lastSize = mod, size
+				signal(notify)
+			}
+		}
+	}()
+	return notify, nil
+}
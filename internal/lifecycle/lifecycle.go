@@ -0,0 +1,169 @@
+// Package lifecycle runs user-configured hook scripts at agent lifecycle
+// events, so towns can add branch setup, cache warming or notifications
+// without patching gt.
+//
+// Hooks are configured under "lifecycle_hooks" in the town settings
+// (settings/config.json) and in each rig's settings. Town hooks run first.
+// Each hook runs with sh -c in the agent's working directory, with the
+// agent's environment (GT_ROLE, GT_RIG, GT_POLECAT, ...) plus GT_HOOK_EVENT
+// and GT_SESSION.
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Event is an agent lifecycle event.
+type Event string
+
+// Lifecycle events.
+const (
+	// PreSpawn runs before an agent's session is created. A failing
+	// pre-spawn hook aborts the start.
+	PreSpawn Event = "pre-spawn"
+
+	// PostStart runs after an agent's session is up.
+	PostStart Event = "post-start"
+
+	// PreKill runs before an agent's session is killed.
+	PreKill Event = "pre-kill"
+
+	// PostDone runs after a polecat finishes its work with gt done.
+	PostDone Event = "post-done"
+)
+
+// Events lists every lifecycle event.
+var Events = []Event{PreSpawn, PostStart, PreKill, PostDone}
+
+// DefaultTimeout bounds a hook without a configured timeout.
+const DefaultTimeout = 60 * time.Second
+
+// Agent identifies the agent a hook runs for.
+type Agent struct {
+	TownRoot string
+	RigPath  string // empty for town-level agents
+	Role     string // polecat, crew, witness, refinery, mayor, deacon
+	Rig      string
+	Name     string // polecat or crew name
+	Session  string // tmux session name
+	WorkDir  string // hook working directory
+
+	// Env adds event-specific variables, e.g. GT_DONE_ISSUE for post-done.
+	Env map[string]string
+}
+
+// env returns the agent's environment for hook scripts.
+func (a Agent) env(event Event) []string {
+	vars := config.AgentEnv(config.AgentEnvConfig{
+		Role:      a.Role,
+		Rig:       a.Rig,
+		AgentName: a.Name,
+		TownRoot:  a.TownRoot,
+	})
+	for k, v := range a.Env {
+		vars[k] = v
+	}
+	vars["GT_HOOK_EVENT"] = string(event)
+	if a.Session != "" {
+		vars["GT_SESSION"] = a.Session
+	}
+	env := os.Environ()
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// Hooks returns the hooks configured for event and role: the town's, then
+// the rig's. Unreadable settings contribute no hooks.
+func Hooks(townRoot, rigPath string, event Event, role string) []config.LifecycleHook {
+	var configured []config.LifecycleHook
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		configured = append(configured, settings.LifecycleHooks[string(event)]...)
+	}
+	if rigPath != "" {
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings != nil {
+			configured = append(configured, settings.LifecycleHooks[string(event)]...)
+		}
+	}
+
+	var hooks []config.LifecycleHook
+	for _, h := range configured {
+		if strings.TrimSpace(h.Command) != "" && appliesTo(h, role) {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+func appliesTo(h config.LifecycleHook, role string) bool {
+	if len(h.Roles) == 0 {
+		return true
+	}
+	for _, r := range h.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs every hook configured for event and the agent's role, in order.
+// All hooks run even if one fails; the returned error joins the failures.
+func Run(event Event, agent Agent) error {
+	var errs []error
+	for _, h := range Hooks(agent.TownRoot, agent.RigPath, event, agent.Role) {
+		if err := runHook(event, agent, h); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Warn runs the hooks for event and prints failures as warnings. For events
+// whose failure should not stop the lifecycle step (everything but PreSpawn).
+func Warn(event Event, agent Agent) {
+	if err := Run(event, agent); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s hook: %v\n", event, err)
+	}
+}
+
+func runHook(event Event, agent Agent, h config.LifecycleHook) error {
+	timeout := DefaultTimeout
+	if h.Timeout != "" {
+		if d, err := time.ParseDuration(h.Timeout); err == nil && d > 0 {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command) //nolint:gosec // G204: command comes from town/rig settings
+	cmd.Dir = agent.WorkDir
+	cmd.Env = agent.env(event)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Don't wait on children that outlive a killed script and hold its output open
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return fmt.Errorf("%q: %w: %s", h.Command, err, out)
+		}
+		return fmt.Errorf("%q: %w", h.Command, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package lifecycle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeTownHooks(t *testing.T, townRoot string, hooks map[string][]config.LifecycleHook) {
+	t.Helper()
+	settings := config.NewTownSettings()
+	settings.LifecycleHooks = hooks
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_AgentEnvironment(t *testing.T) {
+	townRoot := t.TempDir()
+	workDir := t.TempDir()
+	out := filepath.Join(workDir, "env.txt")
+	writeTownHooks(t, townRoot, map[string][]config.LifecycleHook{
+		string(PostStart): {{Command: `echo "$GT_HOOK_EVENT $GT_ROLE $GT_RIG $GT_POLECAT $GT_SESSION $GT_DONE_ISSUE" > env.txt`}},
+	})
+
+	err := Run(PostStart, Agent{
+		TownRoot: townRoot,
+		Role:     "polecat",
+		Rig:      "gastown",
+		Name:     "Toast",
+		Session:  "gt-gastown-Toast",
+		WorkDir:  workDir,
+		Env:      map[string]string{"GT_DONE_ISSUE": "gt-abc"},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "post-start polecat gastown Toast gt-gastown-Toast gt-abc"; got != want {
+		t.Errorf("hook env = %q, want %q", got, want)
+	}
+}
+
+func TestRun_RolesAndRigHooks(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	writeTownHooks(t, townRoot, map[string][]config.LifecycleHook{
+		string(PreSpawn): {
+			{Command: "echo town >> order.txt"},
+			{Command: "echo crew-only >> order.txt", Roles: []string{"crew"}},
+		},
+	})
+	rigSettings := config.NewRigSettings()
+	rigSettings.LifecycleHooks = map[string][]config.LifecycleHook{
+		string(PreSpawn): {{Command: "echo rig >> order.txt"}},
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := t.TempDir()
+	if err := Run(PreSpawn, Agent{TownRoot: townRoot, RigPath: rigPath, Role: "polecat", Rig: "gastown", WorkDir: workDir}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, "order.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "town\nrig\n"; got != want {
+		t.Errorf("hooks ran %q, want %q", got, want)
+	}
+}
+
+func TestRun_FailureAndTimeout(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTownHooks(t, townRoot, map[string][]config.LifecycleHook{
+		string(PreKill): {
+			{Command: "echo boom; exit 3"},
+			{Command: "sleep 5", Timeout: "50ms"},
+		},
+	})
+
+	err := Run(PreKill, Agent{TownRoot: townRoot, Role: "witness", WorkDir: t.TempDir()})
+	if err == nil {
+		t.Fatal("Run succeeded, want errors")
+	}
+	if !strings.Contains(err.Error(), "boom") || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("error = %v, want both the failure output and the timeout", err)
+	}
+}
+
+func TestRun_NoHooks(t *testing.T) {
+	if err := Run(PostDone, Agent{TownRoot: t.TempDir(), Role: "polecat"}); err != nil {
+		t.Errorf("Run with no hooks = %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package log is Gas Town's structured logging: a slog logger per module,
+// with levels set per module by GT_LOG (or gt --log) and human or JSON
+// output chosen by GT_LOG_FORMAT (or gt --log-format).
+//
+// Logs go to stderr, leaving stdout to command output, or to GT_LOG_FILE.
+// Loggers from For follow the latest Configure, so packages can create
+// theirs at init.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Environment variables read at startup.
+const (
+	EnvLevel  = "GT_LOG"        // level spec, e.g. "info" or "warn,mail=debug"
+	EnvFormat = "GT_LOG_FORMAT" // FormatText or FormatJSON
+	EnvFile   = "GT_LOG_FILE"   // file to append logs to instead of stderr
+)
+
+// Output formats.
+const (
+	FormatText = "text" // human-readable key=value lines
+	FormatJSON = "json" // one JSON object per line
+)
+
+// DefaultLevel keeps commands quiet unless something is wrong.
+const DefaultLevel = slog.LevelWarn
+
+// LevelOff silences a module entirely.
+const LevelOff = slog.Level(1 << 20)
+
+// settings is an applied configuration.
+type settings struct {
+	out     slog.Handler
+	level   slog.Level            // for modules not in modules
+	modules map[string]slog.Level // per-module overrides
+}
+
+func (s *settings) levelFor(module string) slog.Level {
+	if l, ok := s.modules[module]; ok {
+		return l
+	}
+	return s.level
+}
+
+var current atomic.Pointer[settings]
+
+func init() {
+	current.Store(&settings{out: slog.NewTextHandler(os.Stderr, nil), level: DefaultLevel})
+	// A bad environment is reported when the CLI configures logging
+	_ = Configure(os.Getenv(EnvLevel), os.Getenv(EnvFormat), Writer())
+}
+
+// Writer returns where logs go: the file named by GT_LOG_FILE, opened for
+// appending, else stderr.
+func Writer() io.Writer {
+	path := os.Getenv(EnvFile)
+	if path == "" {
+		return os.Stderr
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is the user's choice
+	if err != nil {
+		return os.Stderr
+	}
+	return f
+}
+
+// Configure applies a level spec and format, writing to w. The spec is a
+// comma-separated list of a default level and module=level overrides, e.g.
+// "info" or "warn,mail=debug,narrator=info"; "" means DefaultLevel. Levels
+// are debug, info, warn, error and off. Format "" is FormatText. On error
+// the previous configuration is kept.
+func Configure(spec, format string, w io.Writer) error {
+	level, modules, err := ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug} // filtering is per module
+	var out slog.Handler
+	switch format {
+	case "", FormatText:
+		out = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		out = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q (want %s or %s)", format, FormatText, FormatJSON)
+	}
+	current.Store(&settings{out: out, level: level, modules: modules})
+	return nil
+}
+
+// ParseSpec parses a level spec (see Configure) into the default level and
+// per-module levels.
+func ParseSpec(spec string) (slog.Level, map[string]slog.Level, error) {
+	level := DefaultLevel
+	modules := make(map[string]slog.Level)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		module, name, scoped := strings.Cut(part, "=")
+		if !scoped {
+			name = module
+		}
+		l, err := ParseLevel(name)
+		if err != nil {
+			return 0, nil, err
+		}
+		if scoped {
+			modules[strings.TrimSpace(module)] = l
+		} else {
+			level = l
+		}
+	}
+	return level, modules, nil
+}
+
+// ParseLevel parses a level name.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	case "off", "none":
+		return LevelOff, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, warn, error or off)", name)
+}
+
+// Levels describes the current configuration as a spec, for display.
+func Levels() string {
+	s := current.Load()
+	parts := []string{levelName(s.level)}
+	modules := make([]string, 0, len(s.modules))
+	for m := range s.modules {
+		modules = append(modules, m)
+	}
+	sort.Strings(modules)
+	for _, m := range modules {
+		parts = append(parts, m+"="+levelName(s.modules[m]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func levelName(l slog.Level) string {
+	if l >= LevelOff {
+		return "off"
+	}
+	return strings.ToLower(l.String())
+}
+
+// For returns the logger for a module, e.g. "mail". Records carry the
+// module as the "module" attribute.
+func For(module string) *slog.Logger {
+	return slog.New(&handler{module: module})
+}
+
+// handler filters by its module's level and writes through the current
+// output handler. Attributes and groups added to the logger are replayed
+// onto the output handler each time, so they survive reconfiguration.
+type handler struct {
+	module string
+	wrap   func(slog.Handler) slog.Handler
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= current.Load().levelFor(h.module)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	out := current.Load().out.WithAttrs([]slog.Attr{slog.String("module", h.module)})
+	if h.wrap != nil {
+		out = h.wrap(out)
+	}
+	return out.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.then(func(out slog.Handler) slog.Handler { return out.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return h.then(func(out slog.Handler) slog.Handler { return out.WithGroup(name) })
+}
+
+func (h *handler) then(next func(slog.Handler) slog.Handler) slog.Handler {
+	prev := h.wrap
+	return &handler{module: h.module, wrap: func(out slog.Handler) slog.Handler {
+		if prev != nil {
+			out = prev(out)
+		}
+		return next(out)
+	}}
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseSpec(t *testing.T) {
+	level, modules, err := ParseSpec("info, mail=debug,narrator=off")
+	if err != nil {
+		t.Fatalf("ParseSpec: %v", err)
+	}
+	if level != slog.LevelInfo || modules["mail"] != slog.LevelDebug || modules["narrator"] != LevelOff {
+		t.Errorf("got %v %v", level, modules)
+	}
+	if level, _, _ := ParseSpec(""); level != DefaultLevel {
+		t.Errorf("empty spec level = %v, want %v", level, DefaultLevel)
+	}
+	for _, bad := range []string{"loud", "mail=chatty"} {
+		if _, _, err := ParseSpec(bad); err == nil {
+			t.Errorf("ParseSpec(%q) accepted", bad)
+		}
+	}
+}
+
+func TestModuleLevels(t *testing.T) {
+	t.Cleanup(func() { _ = Configure("", "", os.Stderr) })
+	var buf bytes.Buffer
+	if err := Configure("warn,mail=debug", FormatJSON, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := Levels(); got != "warn,mail=debug" {
+		t.Errorf("Levels() = %q", got)
+	}
+
+	mail := For("mail").With("rig", "gastown")
+	For("narrator").Info("hidden")
+	mail.Debug("delivered", "to", "mayor/")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want only mail's:\n%s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, lines[0])
+	}
+	if rec["module"] != "mail" || rec["msg"] != "delivered" || rec["rig"] != "gastown" || rec["to"] != "mayor/" || rec["level"] != "DEBUG" {
+		t.Errorf("record = %v", rec)
+	}
+
+	// Loggers made earlier follow reconfiguration
+	buf.Reset()
+	if err := Configure("off", FormatText, &buf); err != nil {
+		t.Fatal(err)
+	}
+	mail.Error("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("logged with level off: %s", buf.String())
+	}
+
+	if err := Configure("info", "xml", &buf); err == nil {
+		t.Error("accepted unknown format")
+	}
+	if got := Levels(); got != "off" {
+		t.Errorf("failed Configure changed levels to %q", got)
+	}
+}
@@ -2,10 +2,15 @@ package mail
 
 import (
 	"bytes"
+	"errors"
 	"os/exec"
 	"strings"
 )
 
+// ErrTransport indicates the mail transport (the bd CLI) failed to deliver or
+// fetch messages. All bd command failures match it via errors.Is.
+var ErrTransport = errors.New("mail transport failure")
+
 // bdError represents an error from running a bd command.
 // It wraps the underlying error and includes the stderr output for inspection.
 type bdError struct {
@@ -29,6 +34,12 @@ func (e *bdError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is ErrTransport, classifying every bd failure
+// as a transport error.
+func (e *bdError) Is(target error) bool {
+	return target == ErrTransport
+}
+
 // ContainsError checks if the stderr message contains the given substring.
 func (e *bdError) ContainsError(substr string) bool {
 	return strings.Contains(e.Stderr, substr)
@@ -2,6 +2,7 @@ package mail
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -72,6 +73,21 @@ func TestBdError_UnwrapNil(t *testing.T) {
 	}
 }
 
+func TestBdError_IsTransport(t *testing.T) {
+	bdErr := &bdError{
+		Err:    errors.New("exit status 1"),
+		Stderr: "database locked",
+	}
+
+	wrapped := fmt.Errorf("sending message: %w", bdErr)
+	if !errors.Is(wrapped, ErrTransport) {
+		t.Error("bdError should match ErrTransport")
+	}
+	if errors.Is(errors.New("other"), ErrTransport) {
+		t.Error("unrelated error should not match ErrTransport")
+	}
+}
+
 func TestBdError_ContainsError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,139 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DeliveryAttempts is how many times a message is offered to the transport
+// before it is dead-lettered. Waits between attempts double from the
+// router's backoff.
+const DeliveryAttempts = 3
+
+// deliveryBackoff is the wait before the first retry of a failed delivery.
+const deliveryBackoff = 500 * time.Millisecond
+
+// ErrDeadLetterNotFound indicates no dead letter has the given ID.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter is a message whose delivery failed after every retry. It is
+// kept in the town's dead-letter directory until retried successfully.
+type DeadLetter struct {
+	ID       string    `json:"id"`
+	Message  *Message  `json:"message"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadLetterDir returns the directory a town keeps undeliverable mail in.
+func DeadLetterDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "mail-dlq")
+}
+
+func deadLetterPath(townRoot, id string) string {
+	return filepath.Join(DeadLetterDir(townRoot), id+".json")
+}
+
+// withRetry runs op up to DeliveryAttempts times, doubling the wait after
+// each failure. Returns the last error.
+func (r *Router) withRetry(op func() error) error {
+	delay := r.backoff
+	var err error
+	for attempt := 1; attempt <= DeliveryAttempts; attempt++ {
+		if err = op(); err == nil {
+			return nil
+		}
+		if attempt < DeliveryAttempts && delay > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
+
+// deadLetter saves a message that could not be delivered. Returns the dead
+// letter's ID.
+func (r *Router) deadLetter(msg *Message, cause error) (string, error) {
+	if r.townRoot == "" {
+		return "", fmt.Errorf("no town root for dead-letter queue")
+	}
+	if err := os.MkdirAll(DeadLetterDir(r.townRoot), 0755); err != nil {
+		return "", fmt.Errorf("creating dead-letter dir: %w", err)
+	}
+	dl := DeadLetter{
+		ID:       "dl-" + strings.TrimPrefix(generateID(), "msg-"),
+		Message:  msg,
+		Error:    cause.Error(),
+		Attempts: DeliveryAttempts,
+		FailedAt: timeNow().UTC(),
+	}
+	if err := util.AtomicWriteJSON(deadLetterPath(r.townRoot, dl.ID), dl); err != nil {
+		return "", fmt.Errorf("writing dead letter: %w", err)
+	}
+	return dl.ID, nil
+}
+
+// DeadLetters returns the town's undelivered messages, oldest first.
+func (r *Router) DeadLetters() ([]DeadLetter, error) {
+	paths, err := filepath.Glob(filepath.Join(DeadLetterDir(r.townRoot), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var letters []DeadLetter
+	for _, path := range paths {
+		dl, err := readDeadLetter(path)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *dl)
+	}
+	sort.SliceStable(letters, func(i, j int) bool { return letters[i].FailedAt.Before(letters[j].FailedAt) })
+	return letters, nil
+}
+
+func readDeadLetter(path string) (*DeadLetter, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the town root
+	if err != nil {
+		return nil, err
+	}
+	var dl DeadLetter
+	if err := json.Unmarshal(data, &dl); err != nil {
+		return nil, fmt.Errorf("parsing dead letter %s: %w", filepath.Base(path), err)
+	}
+	return &dl, nil
+}
+
+// RetryDeadLetter tries to deliver a dead letter again. On success it is
+// removed from the queue; on failure its error and attempt count are
+// updated and it stays queued.
+func (r *Router) RetryDeadLetter(id string) error {
+	path := deadLetterPath(r.townRoot, id)
+	dl, err := readDeadLetter(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.deliverSingle(dl.Message); err != nil {
+		dl.Error = err.Error()
+		dl.Attempts += DeliveryAttempts
+		dl.FailedAt = timeNow().UTC()
+		if werr := util.AtomicWriteJSON(path, dl); werr != nil {
+			return fmt.Errorf("updating dead letter: %w", werr)
+		}
+		return fmt.Errorf("retrying %s: %w", id, err)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,81 @@
+package mail
+
+import (
+	"errors"
+	"testing"
+)
+
+// flakyTransport fails the first `fail` creates.
+type flakyTransport struct {
+	*MemoryTransport
+	fail    int
+	creates int
+}
+
+func (t *flakyTransport) Create(beadsDir string, bead *BeadsMessage, actor string) (string, error) {
+	t.creates++
+	if t.creates <= t.fail {
+		return "", ErrTransport
+	}
+	return t.MemoryTransport.Create(beadsDir, bead, actor)
+}
+
+func TestSend_RetriesTransientFailures(t *testing.T) {
+	r, memory, _, townBeads := newTestMemoryRouter(t)
+	flaky := &flakyTransport{MemoryTransport: memory, fail: DeliveryAttempts - 1}
+	r.transport = flaky
+
+	if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if flaky.creates != DeliveryAttempts {
+		t.Errorf("creates = %d, want %d", flaky.creates, DeliveryAttempts)
+	}
+	if n := len(memory.Inbox(townBeads, "mayor/")); n != 1 {
+		t.Errorf("mayor inbox has %d messages, want 1", n)
+	}
+	if letters, _ := r.DeadLetters(); len(letters) != 0 {
+		t.Errorf("dead letters = %d, want 0", len(letters))
+	}
+}
+
+func TestSend_DeadLettersAndRetry(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+	transport.Err = ErrTransport
+
+	err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Stuck"})
+	if !errors.Is(err, ErrTransport) {
+		t.Fatalf("Send error = %v, want ErrTransport", err)
+	}
+	letters, err := r.DeadLetters()
+	if err != nil || len(letters) != 1 {
+		t.Fatalf("DeadLetters = %v, %v; want 1", letters, err)
+	}
+	dl := letters[0]
+	if dl.Message.Subject != "Stuck" || dl.Attempts != DeliveryAttempts || dl.Error == "" {
+		t.Errorf("dead letter = %+v", dl)
+	}
+
+	// Still failing: stays queued with more attempts recorded
+	if err := r.RetryDeadLetter(dl.ID); !errors.Is(err, ErrTransport) {
+		t.Fatalf("RetryDeadLetter error = %v, want ErrTransport", err)
+	}
+	letters, _ = r.DeadLetters()
+	if len(letters) != 1 || letters[0].Attempts != 2*DeliveryAttempts {
+		t.Fatalf("after failed retry: %+v", letters)
+	}
+
+	transport.Err = nil
+	if err := r.RetryDeadLetter(dl.ID); err != nil {
+		t.Fatalf("RetryDeadLetter: %v", err)
+	}
+	if n := len(transport.Inbox(townBeads, "mayor/")); n != 1 {
+		t.Errorf("mayor inbox has %d messages, want 1", n)
+	}
+	if letters, _ := r.DeadLetters(); len(letters) != 0 {
+		t.Errorf("dead letters after retry = %d, want 0", len(letters))
+	}
+	if err := r.RetryDeadLetter(dl.ID); !errors.Is(err, ErrDeadLetterNotFound) {
+		t.Errorf("retrying delivered letter: %v, want ErrDeadLetterNotFound", err)
+	}
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EscalationRef identifies what an escalation is about, so queued mail on
+// the same subject can inherit its urgency.
+type EscalationRef struct {
+	// EscalationID is the escalation bead ID, recorded on bumped messages.
+	EscalationID string
+
+	// ThreadID selects messages in this mail thread.
+	ThreadID string
+
+	// BeadID selects messages whose body mentions this bead.
+	BeadID string
+}
+
+// EscalateRelated raises queued (unread) messages related to an escalation
+// to at least the given priority and re-notifies their recipients, so
+// earlier low-priority mail about the same problem isn't left buried.
+// Each bumped message is labelled escalated-by:<escalation ID>.
+// Returns the messages that were bumped, with their new priority.
+func (r *Router) EscalateRelated(ref EscalationRef, priority Priority) ([]*Message, error) {
+	if ref.ThreadID == "" && ref.BeadID == "" {
+		return nil, nil
+	}
+
+	var bumped []*Message
+	var errs []string
+	for _, beadsDir := range r.mailBeadsDirs() {
+		var candidates []*Message
+		if ref.ThreadID != "" {
+			msgs, err := r.queryOpenMessages(beadsDir, Query{Label: "thread:" + ref.ThreadID})
+			if err != nil {
+				return bumped, err
+			}
+			candidates = append(candidates, msgs...)
+		}
+		if ref.BeadID != "" {
+			msgs, err := r.queryOpenMessages(beadsDir, Query{DescContains: ref.BeadID})
+			if err != nil {
+				return bumped, err
+			}
+			candidates = append(candidates, msgs...)
+		}
+
+		for _, msg := range selectPriorityBumps(candidates, ref.EscalationID, priority) {
+			if err := r.transport.SetPriority(beadsDir, msg.ID, PriorityToBeads(priority)); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", msg.ID, err))
+				continue
+			}
+			if ref.EscalationID != "" {
+				_ = r.transport.AddLabels(beadsDir, msg.ID, "escalated-by:"+ref.EscalationID)
+			}
+			msg.Priority = priority
+			bumped = append(bumped, msg)
+
+			// Best-effort: the priority change stands even if nobody is around
+			_ = r.notifyPriorityRaised(msg, ref.EscalationID)
+		}
+	}
+
+	if len(errs) > 0 {
+		return bumped, fmt.Errorf("raising priority failed: %s", strings.Join(errs, "; "))
+	}
+	return bumped, nil
+}
+
+// queryOpenMessages lists unread messages matching q.
+func (r *Router) queryOpenMessages(beadsDir string, q Query) ([]*Message, error) {
+	q.Status = "open"
+	beadsMsgs, err := r.transport.List(beadsDir, q)
+	if err != nil {
+		return nil, fmt.Errorf("querying related mail: %w", err)
+	}
+	return toMessages(beadsMsgs), nil
+}
+
+// selectPriorityBumps returns the unread messages that rank below priority,
+// once each. The escalation's own notifications are skipped: they were sent
+// at the escalation's priority already.
+func selectPriorityBumps(messages []*Message, escalationID string, priority Priority) []*Message {
+	seen := make(map[string]bool)
+	var result []*Message
+	for _, msg := range messages {
+		if seen[msg.ID] || msg.Read {
+			continue
+		}
+		seen[msg.ID] = true
+		if escalationID != "" && strings.Contains(msg.Body, escalationID) {
+			continue
+		}
+		// Beads priorities count down: 0 is urgent, 3 is low.
+		if PriorityToBeads(msg.Priority) <= PriorityToBeads(priority) {
+			continue
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+// notifyPriorityRaised tells a recipient with an active session that a
+// queued message became more urgent.
+func (r *Router) notifyPriorityRaised(msg *Message, escalationID string) error {
+	if msg.To == "overseer" {
+		return r.notifyOverseer(msg, fmt.Sprintf("Mail from %s now %s priority", msg.From, msg.Priority))
+	}
+
+	sessionID := addressToSessionID(msg.To)
+	if sessionID == "" {
+		return nil
+	}
+
+	hasSession, err := r.tmux.HasSession(sessionID)
+	if err != nil || !hasSession {
+		return nil
+	}
+
+	cause := "an escalation"
+	if escalationID != "" {
+		cause = "escalation " + escalationID
+	}
+	notification := fmt.Sprintf("📬 Mail from %s is now %s priority due to %s. Subject: %s. Run 'gt mail inbox' to read.",
+		msg.From, msg.Priority, cause, msg.Subject)
+	return r.tmux.NudgeSession(sessionID, notification)
+}
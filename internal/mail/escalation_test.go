@@ -0,0 +1,34 @@
+package mail
+
+import "testing"
+
+func TestSelectPriorityBumps(t *testing.T) {
+	messages := []*Message{
+		{ID: "m-low", Priority: PriorityLow},
+		{ID: "m-normal", Priority: PriorityNormal},
+		{ID: "m-low", Priority: PriorityLow}, // matched by thread and bead
+		{ID: "m-urgent", Priority: PriorityUrgent},
+		{ID: "m-read", Priority: PriorityLow, Read: true},
+		{ID: "m-self", Priority: PriorityLow, Body: "Escalation: hq-esc1"},
+	}
+
+	got := selectPriorityBumps(messages, "hq-esc1", PriorityHigh)
+
+	want := []string{"m-low", "m-normal"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d bumps, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("bump[%d] = %s, want %s", i, got[i].ID, id)
+		}
+	}
+}
+
+func TestEscalateRelated_NoReference(t *testing.T) {
+	r := NewRouter(t.TempDir())
+	bumped, err := r.EscalateRelated(EscalationRef{EscalationID: "hq-esc1"}, PriorityUrgent)
+	if err != nil || bumped != nil {
+		t.Errorf("EscalateRelated without thread or bead = %v, %v; want nil, nil", bumped, err)
+	}
+}
@@ -0,0 +1,260 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FileTransportFile is the JSONL file FileTransport keeps messages in,
+// inside the beads directory the Router resolves for each address.
+const FileTransportFile = "mail.jsonl"
+
+// fileLockTimeout bounds how long a writer waits for another process to
+// finish updating a mail file.
+const fileLockTimeout = 5 * time.Second
+
+// FileTransport is a Transport that keeps messages in a JSONL file per
+// beads directory, for towns without beads installed. Each line is a
+// BeadsMessage. Writers take an advisory lock (<file>.lock) and replace the
+// file atomically, so concurrent gt processes never lose or tear messages.
+type FileTransport struct{}
+
+// TransportForTown returns the mail transport configured for a town in
+// config/messaging.json: "file" selects FileTransport; anything else,
+// including a missing config, selects beads.
+func TransportForTown(townRoot string) Transport {
+	if townRoot == "" {
+		return bdTransport{}
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil || cfg.Transport != config.MailTransportFile {
+		return bdTransport{}
+	}
+	return FileTransport{}
+}
+
+func fileTransportPath(beadsDir string) string {
+	return filepath.Join(beadsDir, FileTransportFile)
+}
+
+// Create implements Transport.
+func (FileTransport) Create(beadsDir string, bead *BeadsMessage, _ string) (string, error) {
+	stored := *bead
+	stored.ID = generateID()
+	stored.Status = "open"
+	stored.Labels = append([]string(nil), bead.Labels...)
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = timeNow().UTC()
+	}
+
+	err := updateMailFile(beadsDir, func(msgs []BeadsMessage) ([]BeadsMessage, error) {
+		return append(msgs, stored), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return stored.ID, nil
+}
+
+// List implements Transport.
+func (FileTransport) List(beadsDir string, q Query) ([]BeadsMessage, error) {
+	msgs, err := readMailFile(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []BeadsMessage
+	for _, bead := range msgs {
+		if q.Status != "" && bead.Status != q.Status {
+			continue
+		}
+		if q.Assignee != "" && bead.Assignee != q.Assignee {
+			continue
+		}
+		if q.Label != "" && !bead.HasLabel(q.Label) {
+			continue
+		}
+		if q.DescContains != "" && !strings.Contains(bead.Description, q.DescContains) {
+			continue
+		}
+		if !q.CreatedAfter.IsZero() && !bead.CreatedAt.After(q.CreatedAfter) {
+			continue
+		}
+		result = append(result, bead)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Get implements Transport.
+func (FileTransport) Get(beadsDir, id string) (*BeadsMessage, error) {
+	msgs, err := readMailFile(beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range msgs {
+		if msgs[i].ID == id {
+			return &msgs[i], nil
+		}
+	}
+	return nil, ErrMessageNotFound
+}
+
+// SetPriority implements Transport.
+func (FileTransport) SetPriority(beadsDir, id string, priority int) error {
+	return updateMailMessage(beadsDir, id, func(bead *BeadsMessage) { bead.Priority = priority })
+}
+
+// AddLabels implements Transport.
+func (FileTransport) AddLabels(beadsDir, id string, labels ...string) error {
+	return updateMailMessage(beadsDir, id, func(bead *BeadsMessage) { bead.Labels = withLabels(bead.Labels, labels) })
+}
+
+// RemoveLabels implements Transport.
+func (FileTransport) RemoveLabels(beadsDir, id string, labels ...string) error {
+	return updateMailMessage(beadsDir, id, func(bead *BeadsMessage) {
+		for _, label := range labels {
+			bead.Labels = withoutLabel(bead.Labels, label)
+		}
+	})
+}
+
+// Close implements Transport.
+func (FileTransport) Close(beadsDir, id, _ string) error {
+	return updateMailMessage(beadsDir, id, func(bead *BeadsMessage) { bead.Status = "closed" })
+}
+
+// Reopen implements Transport.
+func (FileTransport) Reopen(beadsDir, id string) error {
+	return updateMailMessage(beadsDir, id, func(bead *BeadsMessage) { bead.Status = "open" })
+}
+
+// Delete implements Transport.
+func (FileTransport) Delete(beadsDir, id string) error {
+	return updateMailFile(beadsDir, func(msgs []BeadsMessage) ([]BeadsMessage, error) {
+		for i := range msgs {
+			if msgs[i].ID == id {
+				return append(msgs[:i], msgs[i+1:]...), nil
+			}
+		}
+		return nil, ErrMessageNotFound
+	})
+}
+
+// readMailFile reads every message in a beads directory's mail file. A
+// missing file is an empty mailbox.
+func readMailFile(beadsDir string) ([]BeadsMessage, error) {
+	path := fileTransportPath(beadsDir)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the resolved beads directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", FileTransportFile, err)
+	}
+
+	var msgs []BeadsMessage
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var bead BeadsMessage
+		if err := json.Unmarshal(scanner.Bytes(), &bead); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		msgs = append(msgs, bead)
+	}
+	return msgs, scanner.Err()
+}
+
+// updateMailMessage applies fn to one message, or returns ErrMessageNotFound.
+func updateMailMessage(beadsDir, id string, fn func(*BeadsMessage)) error {
+	return updateMailFile(beadsDir, func(msgs []BeadsMessage) ([]BeadsMessage, error) {
+		for i := range msgs {
+			if msgs[i].ID == id {
+				fn(&msgs[i])
+				return msgs, nil
+			}
+		}
+		return nil, ErrMessageNotFound
+	})
+}
+
+// updateMailFile rewrites a beads directory's mail file with the result of
+// fn, holding the file's lock throughout.
+func updateMailFile(beadsDir string, fn func([]BeadsMessage) ([]BeadsMessage, error)) error {
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		return fmt.Errorf("creating mail directory: %w", err)
+	}
+	path := fileTransportPath(beadsDir)
+
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), fileLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 10*time.Millisecond)
+	if err != nil || !locked {
+		return fmt.Errorf("locking %s: timed out", FileTransportFile)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	msgs, err := readMailFile(beadsDir)
+	if err != nil {
+		return err
+	}
+	msgs, err = fn(msgs)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for i := range msgs {
+		data, err := json.Marshal(&msgs[i])
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return util.AtomicWriteFile(path, buf.Bytes(), 0644)
+}
+
+// withoutLabel returns labels with every copy of label removed.
+func withoutLabel(labels []string, label string) []string {
+	kept := labels[:0]
+	for _, l := range labels {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+// withLabels returns labels with each of add appended that isn't already
+// there.
+func withLabels(labels, add []string) []string {
+	for _, label := range add {
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
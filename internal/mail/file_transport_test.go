@@ -0,0 +1,147 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestFileTransport_RoundTrip(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	tr := FileTransport{}
+
+	id, err := tr.Create(beadsDir, &BeadsMessage{
+		Title:       "Hello",
+		Description: "first body",
+		Assignee:    "gastown/Toast",
+		Priority:    2,
+		Labels:      []string{"from:mayor/", "thread:t1"},
+	}, "mayor")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := tr.Create(beadsDir, &BeadsMessage{Title: "Other", Assignee: "mayor/"}, "gastown/Toast"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	msgs, err := tr.List(beadsDir, Query{Assignee: "gastown/Toast", Status: "open"})
+	if err != nil || len(msgs) != 1 || msgs[0].ID != id {
+		t.Fatalf("List by assignee = %+v, %v; want the one message", msgs, err)
+	}
+	if msgs, _ := tr.List(beadsDir, Query{Label: "thread:t1", DescContains: "first"}); len(msgs) != 1 {
+		t.Errorf("List by label and body = %d messages, want 1", len(msgs))
+	}
+
+	if err := tr.AddLabels(beadsDir, id, "read"); err != nil {
+		t.Fatalf("AddLabels: %v", err)
+	}
+	if err := tr.SetPriority(beadsDir, id, 0); err != nil {
+		t.Fatalf("SetPriority: %v", err)
+	}
+	if err := tr.Close(beadsDir, id, "done"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	got, err := tr.Get(beadsDir, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != "closed" || got.Priority != 0 || !got.HasLabel("read") {
+		t.Errorf("Get = %+v, want closed, urgent and labelled read", got)
+	}
+
+	if err := tr.RemoveLabels(beadsDir, id, "read"); err != nil {
+		t.Fatalf("RemoveLabels: %v", err)
+	}
+	if err := tr.RemoveLabels(beadsDir, id, "read"); err != nil {
+		t.Errorf("removing an absent label: %v", err)
+	}
+	if err := tr.Reopen(beadsDir, id); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if got, _ := tr.Get(beadsDir, id); got.Status != "open" || got.HasLabel("read") {
+		t.Errorf("after reopen = %+v, want open and unlabelled", got)
+	}
+
+	if _, err := tr.Get(beadsDir, "msg-missing"); err != ErrMessageNotFound {
+		t.Errorf("Get missing = %v, want ErrMessageNotFound", err)
+	}
+	if err := tr.Close(beadsDir, "msg-missing", ""); err != ErrMessageNotFound {
+		t.Errorf("Close missing = %v, want ErrMessageNotFound", err)
+	}
+}
+
+func TestTransportForTown(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, ok := TransportForTown(townRoot).(bdTransport); !ok {
+		t.Error("town without messaging config should use beads")
+	}
+
+	cfg := config.NewMessagingConfig()
+	cfg.Transport = config.MailTransportFile
+	if err := os.MkdirAll(filepath.Join(townRoot, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatalf("SaveMessagingConfig: %v", err)
+	}
+	if _, ok := TransportForTown(townRoot).(FileTransport); !ok {
+		t.Error("transport \"file\" should select FileTransport")
+	}
+}
+
+func TestRouter_FileTransportMailbox(t *testing.T) {
+	townRoot := t.TempDir()
+	router := NewMemoryRouter(townRoot, nil, NewMemorySessions())
+	router.transport = FileTransport{}
+
+	if err := router.Send(&Message{From: "mayor/", To: "gastown/Toast", Subject: "Work", Body: "do it", ThreadID: "t1"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	mailbox, err := router.GetMailbox("gastown/Toast")
+	if err != nil {
+		t.Fatalf("GetMailbox: %v", err)
+	}
+	unread, err := mailbox.ListUnread()
+	if err != nil || len(unread) != 1 || unread[0].Subject != "Work" {
+		t.Fatalf("ListUnread = %+v, %v; want the sent message", unread, err)
+	}
+	id := unread[0].ID
+
+	if thread, err := mailbox.ListByThread("t1"); err != nil || len(thread) != 1 {
+		t.Errorf("ListByThread = %+v, %v; want 1 message", thread, err)
+	}
+
+	if err := mailbox.MarkReadOnly(id); err != nil {
+		t.Fatalf("MarkReadOnly: %v", err)
+	}
+	msg, err := mailbox.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !msg.Read || msg.ReadAt == nil {
+		t.Errorf("after MarkReadOnly = %+v, want read with a receipt", msg)
+	}
+
+	if err := mailbox.MarkUnreadOnly(id); err != nil {
+		t.Fatalf("MarkUnreadOnly: %v", err)
+	}
+	if msg, _ := mailbox.Get(id); msg.Read || msg.ReadAt != nil {
+		t.Errorf("after MarkUnreadOnly = %+v, want unread without a receipt", msg)
+	}
+
+	if err := mailbox.MarkRead(id); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if _, unread, _ := mailbox.Count(); unread != 0 {
+		t.Errorf("unread after MarkRead = %d, want 0", unread)
+	}
+	if err := mailbox.MarkUnread(id); err != nil {
+		t.Fatalf("MarkUnread: %v", err)
+	}
+	if _, unread, _ := mailbox.Count(); unread != 1 {
+		t.Errorf("unread after MarkUnread = %d, want 1", unread)
+	}
+}
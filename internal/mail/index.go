@@ -0,0 +1,188 @@
+package mail
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// searchIndexVersion is bumped when tokenization changes, forcing a rebuild.
+const searchIndexVersion = 1
+
+// searchIndex is an inverted index over a mailbox's subjects and bodies,
+// persisted next to the mailbox. It is a cache: it is brought up to date
+// with the mailbox on every search and rebuilt if missing or unreadable.
+type searchIndex struct {
+	Version int                 `json:"version"`
+	Docs    map[string]bool     `json:"docs"`    // indexed message IDs
+	Subject map[string][]string `json:"subject"` // term -> message IDs
+	Body    map[string][]string `json:"body"`    // term -> message IDs
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		Version: searchIndexVersion,
+		Docs:    make(map[string]bool),
+		Subject: make(map[string][]string),
+		Body:    make(map[string][]string),
+	}
+}
+
+// IndexPath returns the path to the mailbox's search index.
+func (m *Mailbox) IndexPath() string {
+	if m.legacy {
+		return m.path + ".index"
+	}
+	// Beads mailboxes share a directory, so index per identity
+	beadsDir := m.beadsDir
+	if beadsDir == "" {
+		beadsDir = filepath.Join(m.workDir, ".beads")
+	}
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(strings.TrimSuffix(m.identity, "/"))
+	return filepath.Join(beadsDir, "mail-index", name+".json")
+}
+
+// loadSearchIndex reads the index at path, returning an empty one if it is
+// missing, unreadable or from an older version.
+func loadSearchIndex(path string) *searchIndex {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is derived from the mailbox
+	if err != nil {
+		return newSearchIndex()
+	}
+	idx := newSearchIndex()
+	if err := json.Unmarshal(data, idx); err != nil || idx.Version != searchIndexVersion {
+		return newSearchIndex()
+	}
+	return idx
+}
+
+func (idx *searchIndex) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, idx)
+}
+
+// sync indexes messages not yet in the index and drops ones no longer in
+// the mailbox. Messages are immutable once sent, so indexed ones are not
+// re-read. It reports whether the index changed.
+func (idx *searchIndex) sync(messages []*Message) bool {
+	current := make(map[string]bool, len(messages))
+	changed := false
+	for _, msg := range messages {
+		current[msg.ID] = true
+		if idx.Docs[msg.ID] {
+			continue
+		}
+		idx.add(msg)
+		changed = true
+	}
+	var gone []string
+	for id := range idx.Docs {
+		if !current[id] {
+			gone = append(gone, id)
+		}
+	}
+	if len(gone) > 0 {
+		idx.remove(gone)
+		changed = true
+	}
+	return changed
+}
+
+func (idx *searchIndex) add(msg *Message) {
+	idx.Docs[msg.ID] = true
+	for _, term := range uniqueTerms(msg.Subject) {
+		idx.Subject[term] = append(idx.Subject[term], msg.ID)
+	}
+	for _, term := range uniqueTerms(msg.Body) {
+		idx.Body[term] = append(idx.Body[term], msg.ID)
+	}
+}
+
+func (idx *searchIndex) remove(ids []string) {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+		delete(idx.Docs, id)
+	}
+	for _, postings := range []map[string][]string{idx.Subject, idx.Body} {
+		for term, list := range postings {
+			kept := list[:0]
+			for _, id := range list {
+				if !drop[id] {
+					kept = append(kept, id)
+				}
+			}
+			if len(kept) == 0 {
+				delete(postings, term)
+			} else {
+				postings[term] = kept
+			}
+		}
+	}
+}
+
+// lookup returns the IDs of messages containing every query term, each
+// matching as a word prefix in the selected fields. An empty query matches
+// every message.
+func (idx *searchIndex) lookup(query string, subject, body bool) map[string]bool {
+	var result map[string]bool
+	for _, term := range uniqueTerms(query) {
+		ids := make(map[string]bool)
+		for _, postings := range idx.fields(subject, body) {
+			for t, list := range postings {
+				if !strings.HasPrefix(t, term) {
+					continue
+				}
+				for _, id := range list {
+					if result == nil || result[id] {
+						ids[id] = true
+					}
+				}
+			}
+		}
+		result = ids
+		if len(result) == 0 {
+			return result
+		}
+	}
+	if result == nil {
+		result = make(map[string]bool, len(idx.Docs))
+		for id := range idx.Docs {
+			result[id] = true
+		}
+	}
+	return result
+}
+
+func (idx *searchIndex) fields(subject, body bool) []map[string][]string {
+	switch {
+	case subject && !body:
+		return []map[string][]string{idx.Subject}
+	case body && !subject:
+		return []map[string][]string{idx.Body}
+	}
+	return []map[string][]string{idx.Subject, idx.Body}
+}
+
+// uniqueTerms splits text into lowercase words of letters and digits, in
+// first-seen order without repeats.
+func uniqueTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(words))
+	terms := words[:0]
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
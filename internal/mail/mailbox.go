@@ -7,8 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
@@ -31,6 +31,10 @@ type Mailbox struct {
 	beadsDir string // explicit .beads directory path (set via BEADS_DIR)
 	path     string // for legacy JSONL mode (crew workers)
 	legacy   bool   // true = use JSONL files, false = use beads
+
+	// transport stores messages when set; nil runs the bd CLI directly.
+	// Routers set it for towns configured with a non-beads transport.
+	transport Transport
 }
 
 // NewMailbox creates a mailbox for the given JSONL path (legacy mode).
@@ -180,6 +184,20 @@ func (m *Mailbox) identityVariants() []string {
 
 // queryMessages runs a bd list query with the given filter flag and value.
 func (m *Mailbox) queryMessages(beadsDir, filterFlag, filterValue, status string) ([]*Message, error) {
+	if m.transport != nil {
+		q := Query{Status: status}
+		if filterFlag == "--label" {
+			q.Label = filterValue
+		} else {
+			q.Assignee = filterValue
+		}
+		beadsMsgs, err := m.transport.List(beadsDir, q)
+		if err != nil {
+			return nil, err
+		}
+		return toMessages(beadsMsgs), nil
+	}
+
 	args := []string{"list",
 		"--type", "message",
 		filterFlag, filterValue,
@@ -278,6 +296,14 @@ func (m *Mailbox) getBeads(id string) (*Message, error) {
 
 // getFromDir retrieves a message from a beads directory.
 func (m *Mailbox) getFromDir(id, beadsDir string) (*Message, error) {
+	if m.transport != nil {
+		bm, err := m.transport.Get(beadsDir, id)
+		if err != nil {
+			return nil, err
+		}
+		return bm.ToMessage(), nil
+	}
+
 	args := []string{"show", id, "--json"}
 
 	stdout, err := runBdCommand(args, m.workDir, beadsDir)
@@ -323,12 +349,100 @@ func (m *Mailbox) MarkRead(id string) error {
 }
 
 func (m *Mailbox) markReadBeads(id string) error {
+	// Record who read it before closing so successors can see it.
+	// Best-effort: closing is what marks the message read.
+	if receipt, err := m.newReadReceipt(id); err == nil {
+		_ = m.updateLabels(id, receipt, nil)
+	}
+
 	// Single DB - wisps and persistent messages in same store
 	return m.closeInDir(id, m.beadsDir)
 }
 
+// readReceipt returns who is marking a message read and when: the runtime
+// session ID, or the mailbox identity outside an agent session.
+func (m *Mailbox) readReceipt() (string, time.Time) {
+	by := runtime.SessionIDFromEnv()
+	if by == "" {
+		by = m.identity
+	}
+	return by, timeNow().UTC().Truncate(time.Second)
+}
+
+// readReceiptLabels returns the beads labels recording a read receipt.
+func readReceiptLabels(by string, at *time.Time) []string {
+	var labels []string
+	if by != "" {
+		labels = append(labels, "read-by:"+by)
+	}
+	if at != nil {
+		labels = append(labels, "read-at:"+at.Format(time.RFC3339))
+	}
+	return labels
+}
+
+// newReadReceipt returns the labels recording who is reading a beads
+// message and when, or none if it already has a receipt: the first receipt
+// wins, so re-reading a message does not overwrite it.
+func (m *Mailbox) newReadReceipt(id string) ([]string, error) {
+	msg, err := m.getFromDir(id, m.beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	if msg.ReadAt != nil {
+		return nil, nil
+	}
+	by, at := m.readReceipt()
+	return readReceiptLabels(by, &at), nil
+}
+
+// oldReadReceipt returns the labels of a beads message's read receipt.
+func (m *Mailbox) oldReadReceipt(id string) ([]string, error) {
+	msg, err := m.getFromDir(id, m.beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	return readReceiptLabels(msg.ReadBy, msg.ReadAt), nil
+}
+
+// updateLabels adds and removes labels on a beads message in a single bd
+// update, so the read label and the receipt change together.
+func (m *Mailbox) updateLabels(id string, add, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	if m.transport != nil {
+		if len(add) > 0 {
+			if err := m.transport.AddLabels(m.beadsDir, id, add...); err != nil {
+				return err
+			}
+		}
+		if len(remove) > 0 {
+			return m.transport.RemoveLabels(m.beadsDir, id, remove...)
+		}
+		return nil
+	}
+
+	_, err := runBdCommand(labelArgs(id, add, remove), m.workDir, m.beadsDir)
+	if err != nil {
+		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
+			return ErrMessageNotFound
+		}
+		// Ignore error if label doesn't exist
+		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("does not have label") {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
 // closeInDir closes a message in a specific beads directory.
 func (m *Mailbox) closeInDir(id, beadsDir string) error {
+	if m.transport != nil {
+		return m.transport.Close(beadsDir, id, "")
+	}
+
 	args := []string{"close", id}
 	// Pass session ID for work attribution if available
 	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
@@ -352,9 +466,14 @@ func (m *Mailbox) markReadLegacy(id string) error {
 		return err
 	}
 
+	by, at := m.readReceipt()
 	found := false
 	for _, msg := range messages {
 		if msg.ID == id {
+			if !msg.Read {
+				msg.ReadBy = by
+				msg.ReadAt = &at
+			}
 			msg.Read = true
 			found = true
 		}
@@ -371,6 +490,7 @@ func (m *Mailbox) markReadLegacy(id string) error {
 // For beads mode, this adds a "read" label to the message.
 // For legacy mode, this sets the Read field to true.
 // The message remains in the inbox but is displayed as read.
+// Both modes record a read receipt (ReadBy/ReadAt) with the message.
 func (m *Mailbox) MarkReadOnly(id string) error {
 	if m.legacy {
 		return m.markReadLegacy(id)
@@ -379,23 +499,18 @@ func (m *Mailbox) MarkReadOnly(id string) error {
 }
 
 func (m *Mailbox) markReadOnlyBeads(id string) error {
-	// Add "read" label to mark as read without closing
-	args := []string{"label", "add", id, "read"}
-
-	_, err := runBdCommand(args, m.workDir, m.beadsDir)
+	receipt, err := m.newReadReceipt(id)
 	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
-			return ErrMessageNotFound
-		}
 		return err
 	}
-
-	return nil
+	// Add "read" label to mark as read without closing
+	return m.updateLabels(id, append([]string{"read"}, receipt...), nil)
 }
 
 // MarkUnreadOnly marks a message as unread (removes "read" label).
 // For beads mode, this removes the "read" label from the message.
 // For legacy mode, this sets the Read field to false.
+// Both modes clear the read receipt.
 func (m *Mailbox) MarkUnreadOnly(id string) error {
 	if m.legacy {
 		return m.markUnreadLegacy(id)
@@ -404,22 +519,12 @@ func (m *Mailbox) MarkUnreadOnly(id string) error {
 }
 
 func (m *Mailbox) markUnreadOnlyBeads(id string) error {
-	// Remove "read" label to mark as unread
-	args := []string{"label", "remove", id, "read"}
-
-	_, err := runBdCommand(args, m.workDir, m.beadsDir)
+	receipt, err := m.oldReadReceipt(id)
 	if err != nil {
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
-			return ErrMessageNotFound
-		}
-		// Ignore error if label doesn't exist
-		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("does not have label") {
-			return nil
-		}
 		return err
 	}
-
-	return nil
+	// Remove "read" label to mark as unread
+	return m.updateLabels(id, nil, append([]string{"read"}, receipt...))
 }
 
 // MarkUnread marks a message as unread (reopens in beads).
@@ -431,9 +536,21 @@ func (m *Mailbox) MarkUnread(id string) error {
 }
 
 func (m *Mailbox) markUnreadBeads(id string) error {
+	receipt, err := m.oldReadReceipt(id)
+	if err != nil {
+		return err
+	}
+
+	if m.transport != nil {
+		if err := m.transport.Reopen(m.beadsDir, id); err != nil {
+			return err
+		}
+		return m.updateLabels(id, nil, receipt)
+	}
+
 	args := []string{"reopen", id}
 
-	_, err := runBdCommand(args, m.workDir, m.beadsDir)
+	_, err = runBdCommand(args, m.workDir, m.beadsDir)
 	if err != nil {
 		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
 			return ErrMessageNotFound
@@ -441,7 +558,7 @@ func (m *Mailbox) markUnreadBeads(id string) error {
 		return err
 	}
 
-	return nil
+	return m.updateLabels(id, nil, receipt)
 }
 
 func (m *Mailbox) markUnreadLegacy(id string) error {
@@ -454,6 +571,8 @@ func (m *Mailbox) markUnreadLegacy(id string) error {
 	for _, msg := range messages {
 		if msg.ID == id {
 			msg.Read = false
+			msg.ReadBy = ""
+			msg.ReadAt = nil
 			found = true
 		}
 	}
@@ -658,31 +777,22 @@ func (m *Mailbox) rewriteArchive(messages []*Message) error {
 
 // SearchOptions specifies search parameters.
 type SearchOptions struct {
-	Query       string // Regex pattern to search for
-	FromFilter  string // Optional: only match messages from this sender
-	SubjectOnly bool   // Only search subject
-	BodyOnly    bool   // Only search body
+	Query       string      // Words to search for; each matches as a word prefix
+	FromFilter  string      // Optional: only match messages from this sender
+	SubjectOnly bool        // Only search subject
+	BodyOnly    bool        // Only search body
+	Since       time.Time   // Optional: only match messages sent at or after
+	Until       time.Time   // Optional: only match messages sent before
+	ThreadID    string      // Optional: only match messages in this thread
+	Type        MessageType // Optional: only match messages of this type
 }
 
 // Search finds messages matching the given criteria.
-// Returns messages from both inbox and archive.
-// Query and FromFilter are treated as literal strings (not regex) to prevent ReDoS.
+// Returns messages from both inbox and archive, newest first.
+// Words are looked up in the mailbox's search index (see IndexPath), which
+// is brought up to date first; all query words must match. FromFilter is a
+// case-insensitive substring.
 func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
-	// Use QuoteMeta to escape special regex chars - prevents ReDoS attacks
-	// and provides intuitive literal string matching for users
-	re, err := regexp.Compile("(?i)" + regexp.QuoteMeta(opts.Query))
-	if err != nil {
-		return nil, fmt.Errorf("invalid search pattern: %w", err)
-	}
-
-	var fromRe *regexp.Regexp
-	if opts.FromFilter != "" {
-		fromRe, err = regexp.Compile("(?i)" + regexp.QuoteMeta(opts.FromFilter))
-		if err != nil {
-			return nil, fmt.Errorf("invalid from pattern: %w", err)
-		}
-	}
-
 	// Get inbox messages
 	inbox, err := m.List()
 	if err != nil {
@@ -694,31 +804,40 @@ func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
 	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
-
-	// Combine and search
 	all := append(inbox, archived...)
-	var matches []*Message
 
+	// The index is a cache, so failing to save it doesn't fail the search
+	path := m.IndexPath()
+	idx := loadSearchIndex(path)
+	if idx.sync(all) {
+		_ = idx.save(path)
+	}
+
+	hits := idx.lookup(opts.Query, opts.SubjectOnly, opts.BodyOnly)
+	from := strings.ToLower(opts.FromFilter)
+	var matches []*Message
+	seen := make(map[string]bool)
 	for _, msg := range all {
-		// Apply from filter
-		if fromRe != nil && !fromRe.MatchString(msg.From) {
+		if !hits[msg.ID] || seen[msg.ID] {
 			continue
 		}
-
-		// Search in specified fields
-		matched := false
-		if opts.SubjectOnly {
-			matched = re.MatchString(msg.Subject)
-		} else if opts.BodyOnly {
-			matched = re.MatchString(msg.Body)
-		} else {
-			// Search in both subject and body
-			matched = re.MatchString(msg.Subject) || re.MatchString(msg.Body)
+		if from != "" && !strings.Contains(strings.ToLower(msg.From), from) {
+			continue
 		}
-
-		if matched {
-			matches = append(matches, msg)
+		if !opts.Since.IsZero() && msg.Timestamp.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && !msg.Timestamp.Before(opts.Until) {
+			continue
+		}
+		if opts.ThreadID != "" && msg.ThreadID != opts.ThreadID {
+			continue
+		}
+		if opts.Type != "" && msg.Type != opts.Type {
+			continue
 		}
+		seen[msg.ID] = true
+		matches = append(matches, msg)
 	}
 
 	// Sort by timestamp (newest first)
@@ -821,19 +940,27 @@ func (m *Mailbox) ListByThread(threadID string) ([]*Message, error) {
 }
 
 func (m *Mailbox) listByThreadBeads(threadID string) ([]*Message, error) {
-	args := []string{"message", "thread", threadID, "--json"}
+	var beadsMsgs []BeadsMessage
+	if m.transport != nil {
+		var err error
+		beadsMsgs, err = m.transport.List(m.beadsDir, Query{Label: "thread:" + threadID})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		args := []string{"message", "thread", threadID, "--json"}
 
-	stdout, err := runBdCommand(args, m.workDir, m.beadsDir, "BD_IDENTITY="+m.identity)
-	if err != nil {
-		return nil, err
-	}
+		stdout, err := runBdCommand(args, m.workDir, m.beadsDir, "BD_IDENTITY="+m.identity)
+		if err != nil {
+			return nil, err
+		}
 
-	var beadsMsgs []BeadsMessage
-	if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
-		if len(stdout) == 0 || string(stdout) == "null" {
-			return nil, nil
+		if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+			if len(stdout) == 0 || string(stdout) == "null" {
+				return nil, nil
+			}
+			return nil, err
 		}
-		return nil, err
 	}
 
 	var messages []*Message
@@ -507,3 +507,115 @@ func TestMailboxLegacyMarkReadTwice(t *testing.T) {
 	}
 }
 
+func TestMailboxLegacyReadReceipt(t *testing.T) {
+	t.Setenv("GT_SESSION_ID_ENV", "")
+	t.Setenv("CLAUDE_SESSION_ID", "session-predecessor")
+
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldNow := timeNow
+	timeNow = func() time.Time { return fixed }
+	defer func() { timeNow = oldNow }()
+
+	tmpDir := t.TempDir()
+	m := NewMailbox(tmpDir)
+	if err := m.Append(&Message{ID: "msg-001"}); err != nil {
+		t.Fatalf("Append error: %v", err)
+	}
+
+	if err := m.MarkReadOnly("msg-001"); err != nil {
+		t.Fatalf("MarkReadOnly error: %v", err)
+	}
+
+	// A successor session opening the same inbox sees who read it and when.
+	t.Setenv("CLAUDE_SESSION_ID", "session-successor")
+	successor := NewMailbox(tmpDir)
+	got, err := successor.Get("msg-001")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if !got.Read || got.ReadBy != "session-predecessor" || got.ReadAt == nil || !got.ReadAt.Equal(fixed) {
+		t.Errorf("receipt = read %v by %q at %v, want read by session-predecessor at %v", got.Read, got.ReadBy, got.ReadAt, fixed)
+	}
+
+	// Re-reading keeps the first receipt.
+	if err := successor.MarkReadOnly("msg-001"); err != nil {
+		t.Fatalf("MarkReadOnly error: %v", err)
+	}
+	if got, _ := successor.Get("msg-001"); got.ReadBy != "session-predecessor" {
+		t.Errorf("ReadBy after re-read = %q, want session-predecessor", got.ReadBy)
+	}
+
+	if err := successor.MarkUnreadOnly("msg-001"); err != nil {
+		t.Fatalf("MarkUnreadOnly error: %v", err)
+	}
+	got, err = successor.Get("msg-001")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if got.Read || got.ReadBy != "" || got.ReadAt != nil {
+		t.Errorf("after MarkUnreadOnly: read %v by %q at %v, want cleared", got.Read, got.ReadBy, got.ReadAt)
+	}
+}
+
+func TestMailboxLegacySearch(t *testing.T) {
+	tmpDir := t.TempDir()
+	m := NewMailbox(tmpDir)
+
+	now := time.Now()
+	msgs := []*Message{
+		{ID: "msg-001", From: "gastown/witness", Subject: "Deployment failed", Body: "The refinery error log is attached.", ThreadID: "thread-A", Type: TypeNotification, Timestamp: now.Add(-48 * time.Hour)},
+		{ID: "msg-002", From: "mayor/", Subject: "Handoff", Body: "Pick up the deploy fix.", ThreadID: "thread-A", Type: TypeTask, Timestamp: now.Add(-time.Hour)},
+		{ID: "msg-003", From: "mayor/", Subject: "Status check", Body: "All quiet.", ThreadID: "thread-B", Type: TypeNotification, Timestamp: now},
+	}
+	for _, msg := range msgs {
+		if err := m.Append(msg); err != nil {
+			t.Fatalf("Append error: %v", err)
+		}
+	}
+
+	ids := func(opts SearchOptions) string {
+		t.Helper()
+		found, err := m.Search(opts)
+		if err != nil {
+			t.Fatalf("Search(%+v) error: %v", opts, err)
+		}
+		var out []string
+		for _, msg := range found {
+			out = append(out, msg.ID)
+		}
+		return fmt.Sprint(out)
+	}
+
+	for _, tt := range []struct {
+		opts SearchOptions
+		want string
+	}{
+		{SearchOptions{Query: "deploy"}, "[msg-002 msg-001]"},
+		{SearchOptions{Query: "DEPLOY fix"}, "[msg-002]"},
+		{SearchOptions{Query: "deploy", SubjectOnly: true}, "[msg-001]"},
+		{SearchOptions{Query: "error", BodyOnly: true}, "[msg-001]"},
+		{SearchOptions{Query: "", FromFilter: "MAYOR"}, "[msg-003 msg-002]"},
+		{SearchOptions{Query: "", ThreadID: "thread-A", Type: TypeTask}, "[msg-002]"},
+		{SearchOptions{Query: "", Since: now.Add(-2 * time.Hour), Until: now}, "[msg-002]"},
+		{SearchOptions{Query: "missing"}, "[]"},
+	} {
+		if got := ids(tt.opts); got != tt.want {
+			t.Errorf("Search(%+v) = %s, want %s", tt.opts, got, tt.want)
+		}
+	}
+
+	if _, err := os.Stat(m.IndexPath()); err != nil {
+		t.Errorf("search index not persisted: %v", err)
+	}
+
+	// Deleted messages drop out of the index; archived ones stay searchable
+	if err := m.Archive("msg-001"); err != nil {
+		t.Fatalf("Archive error: %v", err)
+	}
+	if err := m.Delete("msg-002"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if got := ids(SearchOptions{Query: "deploy"}); got != "[msg-001]" {
+		t.Errorf("after archive and delete, Search(deploy) = %s, want [msg-001]", got)
+	}
+}
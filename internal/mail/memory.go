@@ -0,0 +1,262 @@
+package mail
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/notify"
+)
+
+// MemoryTransport is an in-memory Transport for tests. Each beads directory
+// is a separate store, as with real per-rig databases. It is safe for
+// concurrent use.
+type MemoryTransport struct {
+	mu     sync.Mutex
+	stores map[string][]*BeadsMessage
+	nextID int
+
+	// Now stamps created messages. Defaults to time.Now.
+	Now func() time.Time
+
+	// Err, if set, is returned by every operation, to exercise error paths.
+	Err error
+}
+
+// NewMemoryTransport returns an empty in-memory transport.
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{stores: make(map[string][]*BeadsMessage)}
+}
+
+// Create implements Transport.
+func (t *MemoryTransport) Create(beadsDir string, bead *BeadsMessage, _ string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return "", t.Err
+	}
+
+	t.nextID++
+	stored := *bead
+	stored.ID = fmt.Sprintf("mem-%d", t.nextID)
+	stored.Status = "open"
+	stored.Labels = append([]string(nil), bead.Labels...)
+	if stored.CreatedAt.IsZero() {
+		now := time.Now
+		if t.Now != nil {
+			now = t.Now
+		}
+		stored.CreatedAt = now()
+	}
+	t.stores[beadsDir] = append(t.stores[beadsDir], &stored)
+	return stored.ID, nil
+}
+
+// List implements Transport.
+func (t *MemoryTransport) List(beadsDir string, q Query) ([]BeadsMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return nil, t.Err
+	}
+
+	var result []BeadsMessage
+	for _, bead := range t.stores[beadsDir] {
+		if q.Status != "" && bead.Status != q.Status {
+			continue
+		}
+		if q.Assignee != "" && bead.Assignee != q.Assignee {
+			continue
+		}
+		if q.Label != "" && !bead.HasLabel(q.Label) {
+			continue
+		}
+		if q.DescContains != "" && !strings.Contains(bead.Description, q.DescContains) {
+			continue
+		}
+		if !q.CreatedAfter.IsZero() && !bead.CreatedAt.After(q.CreatedAfter) {
+			continue
+		}
+		copied := *bead
+		copied.Labels = append([]string(nil), bead.Labels...)
+		result = append(result, copied)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Get implements Transport.
+func (t *MemoryTransport) Get(beadsDir, id string) (*BeadsMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return nil, t.Err
+	}
+	for _, bead := range t.stores[beadsDir] {
+		if bead.ID == id {
+			copied := *bead
+			copied.Labels = append([]string(nil), bead.Labels...)
+			return &copied, nil
+		}
+	}
+	return nil, ErrMessageNotFound
+}
+
+// SetPriority implements Transport.
+func (t *MemoryTransport) SetPriority(beadsDir, id string, priority int) error {
+	return t.update(beadsDir, id, func(bead *BeadsMessage) { bead.Priority = priority })
+}
+
+// AddLabels implements Transport.
+func (t *MemoryTransport) AddLabels(beadsDir, id string, labels ...string) error {
+	return t.update(beadsDir, id, func(bead *BeadsMessage) { bead.Labels = withLabels(bead.Labels, labels) })
+}
+
+// RemoveLabels implements Transport.
+func (t *MemoryTransport) RemoveLabels(beadsDir, id string, labels ...string) error {
+	return t.update(beadsDir, id, func(bead *BeadsMessage) {
+		for _, label := range labels {
+			bead.Labels = withoutLabel(bead.Labels, label)
+		}
+	})
+}
+
+// Close implements Transport.
+func (t *MemoryTransport) Close(beadsDir, id, _ string) error {
+	return t.update(beadsDir, id, func(bead *BeadsMessage) { bead.Status = "closed" })
+}
+
+// Reopen implements Transport.
+func (t *MemoryTransport) Reopen(beadsDir, id string) error {
+	return t.update(beadsDir, id, func(bead *BeadsMessage) { bead.Status = "open" })
+}
+
+// Delete implements Transport.
+func (t *MemoryTransport) Delete(beadsDir, id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return t.Err
+	}
+	store := t.stores[beadsDir]
+	for i, bead := range store {
+		if bead.ID == id {
+			t.stores[beadsDir] = append(store[:i], store[i+1:]...)
+			return nil
+		}
+	}
+	return ErrMessageNotFound
+}
+
+func (t *MemoryTransport) update(beadsDir, id string, fn func(*BeadsMessage)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.Err != nil {
+		return t.Err
+	}
+	for _, bead := range t.stores[beadsDir] {
+		if bead.ID == id {
+			fn(bead)
+			return nil
+		}
+	}
+	return ErrMessageNotFound
+}
+
+// Messages returns every message stored in beadsDir, oldest first.
+func (t *MemoryTransport) Messages(beadsDir string) []*Message {
+	beadsMsgs, _ := t.List(beadsDir, Query{})
+	return toMessages(beadsMsgs)
+}
+
+// Inbox returns the open messages addressed to address in beadsDir, oldest
+// first. address is normalized the way the Router normalizes recipients.
+func (t *MemoryTransport) Inbox(beadsDir, address string) []*Message {
+	identity := addressToIdentity(address)
+	var inbox []*Message
+	for _, msg := range t.Messages(beadsDir) {
+		if !msg.Read && addressToIdentity(msg.To) == identity {
+			inbox = append(inbox, msg)
+		}
+	}
+	return inbox
+}
+
+// Nudge is a session nudge recorded by MemorySessions.
+type Nudge struct {
+	Session string
+	Message string
+}
+
+// MemorySessions is an in-memory Sessions for tests. Sessions listed in Live
+// exist; every nudge is recorded. It also stands in for the overseer's
+// desktop notifier, recording notifications instead of showing them.
+type MemorySessions struct {
+	mu            sync.Mutex
+	Live          map[string]bool
+	nudges        []Nudge
+	notifications []notify.Notification
+}
+
+// NewMemorySessions returns sessions where the named sessions are live.
+func NewMemorySessions(live ...string) *MemorySessions {
+	s := &MemorySessions{Live: make(map[string]bool)}
+	for _, name := range live {
+		s.Live[name] = true
+	}
+	return s
+}
+
+// HasSession implements Sessions.
+func (s *MemorySessions) HasSession(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Live[name], nil
+}
+
+// NudgeSession implements Sessions.
+func (s *MemorySessions) NudgeSession(session, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nudges = append(s.nudges, Nudge{Session: session, Message: message})
+	return nil
+}
+
+// Nudges returns the nudges sent so far.
+func (s *MemorySessions) Nudges() []Nudge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Nudge(nil), s.nudges...)
+}
+
+// Notify implements notify.Notifier.
+func (s *MemorySessions) Notify(n notify.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifications = append(s.notifications, n)
+	return nil
+}
+
+// Notifications returns the desktop notifications raised so far.
+func (s *MemorySessions) Notifications() []notify.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]notify.Notification(nil), s.notifications...)
+}
+
+// NewMemoryRouter returns a Router for tests that stores mail in transport
+// and reports to sessions instead of tmux or the desktop. townRoot may be a
+// temp directory; it is only read for messaging and overseer config.
+func NewMemoryRouter(townRoot string, transport *MemoryTransport, sessions *MemorySessions) *Router {
+	return &Router{
+		workDir:   townRoot,
+		townRoot:  townRoot,
+		tmux:      sessions,
+		desktop:   sessions,
+		transport: transport,
+	}
+}
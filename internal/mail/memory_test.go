@@ -0,0 +1,315 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func newTestMemoryRouter(t *testing.T, live ...string) (*Router, *MemoryTransport, *MemorySessions, string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	transport := NewMemoryTransport()
+	sessions := NewMemorySessions(live...)
+	return NewMemoryRouter(townRoot, transport, sessions), transport, sessions, filepath.Join(townRoot, ".beads")
+}
+
+func TestMemoryRouterSend(t *testing.T) {
+	r, transport, sessions, townBeads := newTestMemoryRouter(t, session.MayorSessionName())
+
+	msg := &Message{
+		From:     "gastown/Toast",
+		To:       "mayor",
+		Subject:  "Work complete",
+		Body:     "Finished gt-abc",
+		Priority: PriorityHigh,
+		ThreadID: "thread-1",
+		CC:       []string{"gastown/polecats/Nux"},
+	}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	inbox := transport.Inbox(townBeads, "mayor/")
+	if len(inbox) != 1 {
+		t.Fatalf("mayor inbox has %d messages, want 1", len(inbox))
+	}
+	got := inbox[0]
+	if got.From != msg.From || got.Subject != msg.Subject || got.Body != msg.Body {
+		t.Errorf("delivered %+v, want fields of %+v", got, msg)
+	}
+	if got.Priority != PriorityHigh || got.ThreadID != "thread-1" {
+		t.Errorf("priority/thread = %s/%s, want high/thread-1", got.Priority, got.ThreadID)
+	}
+	if len(got.CC) != 1 || got.CC[0] != "gastown/Nux" {
+		t.Errorf("CC = %v, want [gastown/Nux]", got.CC)
+	}
+
+	nudges := sessions.Nudges()
+	if len(nudges) != 1 || nudges[0].Session != session.MayorSessionName() {
+		t.Fatalf("nudges = %+v, want one to the mayor session", nudges)
+	}
+	if !strings.Contains(nudges[0].Message, "Work complete") {
+		t.Errorf("nudge %q does not mention the subject", nudges[0].Message)
+	}
+}
+
+func TestMemoryRouterSend_NoSession(t *testing.T) {
+	r, transport, sessions, townBeads := newTestMemoryRouter(t)
+
+	if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if n := len(transport.Inbox(townBeads, "mayor/")); n != 1 {
+		t.Errorf("mayor inbox has %d messages, want 1", n)
+	}
+	if n := len(sessions.Nudges()); n != 0 {
+		t.Errorf("got %d nudges with no live session, want 0", n)
+	}
+}
+
+func TestMemoryRouterSend_TransportError(t *testing.T) {
+	r, transport, _, _ := newTestMemoryRouter(t)
+	transport.Err = ErrTransport
+
+	err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Hi"})
+	if !errors.Is(err, ErrTransport) {
+		t.Errorf("Send error = %v, want ErrTransport", err)
+	}
+}
+
+func TestMemoryRouterEscalateRelated(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+
+	for _, m := range []*Message{
+		{From: "gastown/Toast", To: "mayor/", Subject: "Tests flaky", ThreadID: "thread-1", Priority: PriorityLow},
+		{From: "gastown/Toast", To: "mayor/", Subject: "Unrelated", ThreadID: "thread-2", Priority: PriorityLow},
+	} {
+		if err := r.Send(m); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	bumped, err := r.EscalateRelated(EscalationRef{EscalationID: "hq-esc1", ThreadID: "thread-1"}, PriorityUrgent)
+	if err != nil {
+		t.Fatalf("EscalateRelated: %v", err)
+	}
+	if len(bumped) != 1 || bumped[0].Subject != "Tests flaky" {
+		t.Fatalf("bumped = %+v, want the thread-1 message", bumped)
+	}
+
+	for _, msg := range transport.Messages(townBeads) {
+		want := PriorityLow
+		if msg.ThreadID == "thread-1" {
+			want = PriorityUrgent
+		}
+		if msg.Priority != want {
+			t.Errorf("%s priority = %s, want %s", msg.Subject, msg.Priority, want)
+		}
+	}
+}
+
+func TestMemoryRouterDetectThread(t *testing.T) {
+	r, transport, _, _ := newTestMemoryRouter(t)
+	transport.Now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+	if err := r.Send(&Message{From: "mayor/", To: "gastown/Toast", Subject: "Build broken", ThreadID: "thread-a"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	threadID, err := r.DetectThread(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Re: build broken"})
+	if err != nil {
+		t.Fatalf("DetectThread: %v", err)
+	}
+	if threadID != "thread-a" {
+		t.Errorf("DetectThread = %q, want thread-a", threadID)
+	}
+}
+
+func TestMemoryRouterDetectThread_Window(t *testing.T) {
+	r, transport, _, _ := newTestMemoryRouter(t)
+	transport.Now = func() time.Time { return time.Now().Add(-ThreadWindow - time.Hour) }
+	if err := r.Send(&Message{From: "mayor/", To: "gastown/Toast", Subject: "Build broken", ThreadID: "thread-old"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	threadID, err := r.DetectThread(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Re: build broken"})
+	if err != nil {
+		t.Fatalf("DetectThread: %v", err)
+	}
+	if threadID != "" {
+		t.Errorf("DetectThread = %q, want no thread past the window", threadID)
+	}
+}
+
+func TestMemoryRouterReply(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+
+	original := &Message{ID: "hq-1", From: "gastown/Toast", To: "mayor/", Subject: "Build broken", ThreadID: "thread-1"}
+	reply := &Message{From: "mayor/", Body: "Looking"}
+	if err := r.Reply(original, reply); err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+
+	inbox := transport.Inbox(townBeads, "gastown/Toast")
+	if len(inbox) != 1 {
+		t.Fatalf("Toast inbox has %d messages, want 1", len(inbox))
+	}
+	got := inbox[0]
+	if got.Subject != "Re: Build broken" || got.ThreadID != "thread-1" || got.ReplyTo != "hq-1" {
+		t.Errorf("reply = subject %q thread %q reply-to %q", got.Subject, got.ThreadID, got.ReplyTo)
+	}
+	if reply.Type != TypeReply {
+		t.Errorf("reply type = %q, want %q", reply.Type, TypeReply)
+	}
+
+	unthreaded := &Message{ID: "hq-2", From: "gastown/Toast", To: "mayor/", Subject: "Re: Status"}
+	next := &Message{From: "mayor/", Body: "ok"}
+	if err := r.Reply(unthreaded, next); err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+	if next.ThreadID == "" || next.Subject != "Re: Status" {
+		t.Errorf("reply to unthreaded = thread %q subject %q, want a new thread and unchanged subject", next.ThreadID, next.Subject)
+	}
+}
+
+func TestMemoryRouterUnreadCounts(t *testing.T) {
+	r, _, _, _ := newTestMemoryRouter(t)
+	for _, m := range []*Message{
+		{From: "gastown/Toast", To: "mayor/", Subject: "One"},
+		{From: "gastown/Toast", To: "mayor/", Subject: "Two"},
+		{From: "mayor/", To: "gastown/Toast", Subject: "Three"},
+	} {
+		if err := r.Send(m); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	mailbox, err := r.GetMailbox("gastown/Toast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox, err := mailbox.List()
+	if err != nil || len(inbox) != 1 {
+		t.Fatalf("Toast inbox = %v, %v", inbox, err)
+	}
+	if err := mailbox.MarkRead(inbox[0].ID); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	counts, err := r.UnreadCounts()
+	if err != nil {
+		t.Fatalf("UnreadCounts: %v", err)
+	}
+	if counts["mayor/"] != 2 || counts["gastown/Toast"] != 0 {
+		t.Errorf("UnreadCounts = %v, want mayor/=2 and nothing for gastown/Toast", counts)
+	}
+}
+
+func TestMemoryMailboxReadReceipt(t *testing.T) {
+	t.Setenv("GT_SESSION_ID_ENV", "")
+	t.Setenv("CLAUDE_SESSION_ID", "session-reader")
+
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+	if err := r.Send(&Message{From: "mayor/", To: "gastown/Toast", Subject: "Hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	mailbox, err := r.GetMailbox("gastown/Toast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := transport.Messages(townBeads)[0].ID
+
+	if err := mailbox.MarkReadOnly(id); err != nil {
+		t.Fatalf("MarkReadOnly: %v", err)
+	}
+	got, err := mailbox.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Read || got.ReadBy != "session-reader" || got.ReadAt == nil {
+		t.Errorf("after MarkReadOnly: read %v by %q at %v, want a receipt", got.Read, got.ReadBy, got.ReadAt)
+	}
+
+	if err := mailbox.MarkUnreadOnly(id); err != nil {
+		t.Fatalf("MarkUnreadOnly: %v", err)
+	}
+	if got, _ := mailbox.Get(id); got.Read || got.ReadBy != "" || got.ReadAt != nil {
+		t.Errorf("after MarkUnreadOnly: read %v by %q at %v, want cleared", got.Read, got.ReadBy, got.ReadAt)
+	}
+}
+
+func TestMemoryRouterSendToList_Broadcast(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+	configDir := filepath.Join(r.townRoot, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"type": "messaging", "version": 1, "lists": {"oncall": ["mayor/", "gastown/witness"]}}`
+	if err := os.WriteFile(filepath.Join(configDir, "messaging.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Send(&Message{From: "deacon/", To: "list:oncall", Subject: "Alert", Body: "System down"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var threads []string
+	for _, addr := range []string{"mayor/", "gastown/witness"} {
+		inbox := transport.Inbox(townBeads, addr)
+		if len(inbox) != 1 {
+			t.Fatalf("%s inbox has %d messages, want 1", addr, len(inbox))
+		}
+		if inbox[0].Broadcast != "list:oncall" {
+			t.Errorf("%s copy Broadcast = %q, want list:oncall", addr, inbox[0].Broadcast)
+		}
+		threads = append(threads, inbox[0].ThreadID)
+	}
+	if threads[0] == "" || threads[0] != threads[1] {
+		t.Errorf("broadcast threads = %v, want one shared thread", threads)
+	}
+}
+
+func TestMemoryRouterMarkRead_Receipt(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+
+	if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Deploy freeze", ThreadID: "thread-1", RequestReceipt: true}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "FYI"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	inbox := transport.Inbox(townBeads, "mayor/")
+	if len(inbox) != 2 || !inbox[0].RequestReceipt || inbox[1].RequestReceipt {
+		t.Fatalf("mayor inbox = %+v, want the receipt request on the first message only", inbox)
+	}
+
+	for _, msg := range inbox {
+		if err := r.MarkRead("mayor/", msg.ID); err != nil {
+			t.Fatalf("MarkRead(%s): %v", msg.ID, err)
+		}
+	}
+	// Marking an already-read message sends no second receipt
+	if err := r.MarkRead("mayor/", inbox[0].ID); err != nil {
+		t.Fatalf("MarkRead again: %v", err)
+	}
+
+	receipts := transport.Inbox(townBeads, "gastown/Toast")
+	if len(receipts) != 1 {
+		t.Fatalf("sender got %d receipts, want 1: %+v", len(receipts), receipts)
+	}
+	got := receipts[0]
+	if got.From != "mayor/" || got.Subject != "Read: Deploy freeze" || got.ReplyTo != inbox[0].ID || got.ThreadID != "thread-1" {
+		t.Errorf("receipt = %+v", got)
+	}
+	for _, msg := range transport.Inbox(townBeads, "mayor/") {
+		if !msg.Read {
+			t.Errorf("message %s still unread", msg.ID)
+		}
+	}
+}
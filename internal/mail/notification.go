@@ -0,0 +1,269 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Notice is a notification waiting to be sent: batched mail, mentioned in
+// the recipient's next nudge, or unread repeat-tier mail, nudged again
+// until it is read (see config.MailNotifyPolicy). Notices are kept in the
+// town's notice directory until the daemon flushes them (see
+// FlushNotifications).
+type Notice struct {
+	MessageID string   `json:"message_id"`
+	To        string   `json:"to"`
+	From      string   `json:"from"`
+	Subject   string   `json:"subject"`
+	Priority  Priority `json:"priority"`
+	Tier      string   `json:"tier"`
+
+	// Next is when a repeat notice is next due.
+	Next time.Time `json:"next,omitempty"`
+	// Repeats is how many times a repeat notice has been sent again.
+	Repeats int `json:"repeats,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NoticeDir returns the directory a town keeps pending mail notifications
+// in.
+func NoticeDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "mail-notify")
+}
+
+func noticePath(townRoot, messageID string) string {
+	return filepath.Join(NoticeDir(townRoot), messageID+".json")
+}
+
+// addressRole returns the role of the agent at address, or "" for an
+// address that isn't an agent's.
+func addressRole(address string) string {
+	parts := strings.Split(strings.TrimSuffix(address, "/"), "/")
+	switch {
+	case len(parts) == 1 && (parts[0] == "mayor" || parts[0] == "deacon"):
+		return parts[0]
+	case len(parts) == 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return parts[1]
+	case len(parts) == 2:
+		return "polecat" // rig/name
+	case len(parts) == 3 && parts[1] == "crew":
+		return "crew"
+	case len(parts) == 3 && parts[1] == "polecats":
+		return "polecat"
+	}
+	return ""
+}
+
+// notifyPolicy returns the mail notification policy of the agent at
+// address. Without a readable messaging config, the defaults apply.
+func (r *Router) notifyPolicy(address string) config.MailNotifyPolicy {
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		return config.MailNotifyPolicy{}
+	}
+	return cfg.MailNotifyPolicy(addressRole(address))
+}
+
+// notify tells msg's recipient about it as their policy says for its
+// priority. id is the delivered message's ID. Mail to the overseer, and
+// mail delivered outside a town, is notified immediately.
+func (r *Router) notify(msg *Message, id string) error {
+	if msg.To == "overseer" || r.townRoot == "" || id == "" {
+		return r.notifyRecipient(msg)
+	}
+	policy := r.notifyPolicy(msg.To)
+	tier := policy.Tier(string(msg.Priority))
+	notice := &Notice{
+		MessageID: id,
+		To:        msg.To,
+		From:      msg.From,
+		Subject:   msg.Subject,
+		Priority:  msg.Priority,
+		Tier:      tier,
+		CreatedAt: time.Now(),
+	}
+
+	switch tier {
+	case config.MailNotifyNone:
+		return nil
+	case config.MailNotifyBatch:
+		return r.saveNotice(notice)
+	case config.MailNotifyRepeat:
+		notice.Next = notice.CreatedAt.Add(policy.RepeatInterval())
+		if err := r.saveNotice(notice); err != nil {
+			return err
+		}
+	}
+
+	// Mail batched for the recipient rides along with this nudge
+	batched, err := r.takeBatched(msg.To)
+	if err != nil {
+		logger.Warn("reading batched notices", "to", msg.To, "err", err)
+	}
+	text := fmt.Sprintf("📬 You have new mail from %s. Subject: %s.", msg.From, msg.Subject)
+	if len(batched) > 0 {
+		text += fmt.Sprintf(" You also have %d other new message(s).", len(batched))
+	}
+	return r.nudgeRecipient(msg, fmt.Sprintf("Mail from %s", msg.From), text+" Run 'gt mail inbox' to read.")
+}
+
+func (r *Router) saveNotice(n *Notice) error {
+	if err := os.MkdirAll(NoticeDir(r.townRoot), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(noticePath(r.townRoot, n.MessageID), n)
+}
+
+// Notices returns the town's pending mail notifications, oldest first.
+func (r *Router) Notices() ([]Notice, error) {
+	paths, err := filepath.Glob(filepath.Join(NoticeDir(r.townRoot), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var notices []Notice
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the town root
+		if errors.Is(err, os.ErrNotExist) {
+			continue // flushed meanwhile
+		}
+		if err != nil {
+			return nil, err
+		}
+		var n Notice
+		if err := json.Unmarshal(data, &n); err != nil {
+			return nil, fmt.Errorf("parsing mail notice %s: %w", filepath.Base(path), err)
+		}
+		notices = append(notices, n)
+	}
+	sort.Slice(notices, func(i, j int) bool { return notices[i].CreatedAt.Before(notices[j].CreatedAt) })
+	return notices, nil
+}
+
+// takeBatched removes and returns the batched notices for address.
+func (r *Router) takeBatched(address string) ([]Notice, error) {
+	notices, err := r.Notices()
+	if err != nil {
+		return nil, err
+	}
+	var taken []Notice
+	for _, n := range notices {
+		if n.Tier != config.MailNotifyBatch || n.To != address {
+			continue
+		}
+		if err := os.Remove(noticePath(r.townRoot, n.MessageID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return taken, err
+		}
+		taken = append(taken, n)
+	}
+	return taken, nil
+}
+
+// unread reports whether n's message is still unread. A message that no
+// longer exists isn't.
+func (r *Router) unread(n Notice) (bool, error) {
+	mailbox, err := r.GetMailbox(n.To)
+	if err != nil {
+		return false, err
+	}
+	msg, err := mailbox.Get(n.MessageID)
+	if errors.Is(err, ErrMessageNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !msg.Read, nil
+}
+
+// FlushNotifications sends pending mail notifications as of now: one nudge
+// per agent listing its batched mail, and another nudge for each repeat
+// notice that is due. Notices for mail that has been read are dropped.
+// Returns the number of nudges attempted; the daemon calls it each
+// heartbeat.
+func (r *Router) FlushNotifications(now time.Time) (int, error) {
+	notices, err := r.Notices()
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	drop := func(n Notice) {
+		if err := os.Remove(noticePath(r.townRoot, n.MessageID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	batched := make(map[string][]Notice)
+	var recipients []string
+	nudged := 0
+	for _, n := range notices {
+		if n.Tier == config.MailNotifyRepeat && n.Next.After(now) {
+			continue
+		}
+		unread, err := r.unread(n)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("checking %s: %w", n.MessageID, err))
+			continue
+		}
+		if !unread {
+			drop(n)
+			continue
+		}
+		if n.Tier != config.MailNotifyRepeat {
+			if batched[n.To] == nil {
+				recipients = append(recipients, n.To)
+			}
+			batched[n.To] = append(batched[n.To], n)
+			continue
+		}
+
+		msg := &Message{To: n.To, From: n.From, Subject: n.Subject, Priority: n.Priority}
+		text := fmt.Sprintf("🚨 Unread %s mail from %s. Subject: %s. Run 'gt mail read %s' to read it.",
+			n.Priority, n.From, n.Subject, n.MessageID)
+		if err := r.nudgeRecipient(msg, fmt.Sprintf("Unread mail from %s", n.From), text); err != nil {
+			errs = append(errs, fmt.Errorf("nudging %s: %w", n.To, err))
+		}
+		nudged++
+		n.Repeats++
+		n.Next = now.Add(r.notifyPolicy(n.To).RepeatInterval())
+		if err := r.saveNotice(&n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, to := range recipients {
+		list := batched[to]
+		var from []string
+		seen := make(map[string]bool)
+		for _, n := range list {
+			if !seen[n.From] {
+				seen[n.From] = true
+				from = append(from, n.From)
+			}
+		}
+		msg := &Message{To: to, From: list[0].From, Subject: list[0].Subject, Priority: list[0].Priority}
+		text := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", list[0].From, list[0].Subject)
+		if len(list) > 1 {
+			text = fmt.Sprintf("📬 You have %d new messages from %s. Run 'gt mail inbox' to read.", len(list), strings.Join(from, ", "))
+		}
+		if err := r.nudgeRecipient(msg, fmt.Sprintf("Mail from %s", strings.Join(from, ", ")), text); err != nil {
+			errs = append(errs, fmt.Errorf("nudging %s: %w", to, err))
+			continue
+		}
+		nudged++
+		for _, n := range list {
+			drop(n)
+		}
+	}
+	return nudged, errors.Join(errs...)
+}
@@ -0,0 +1,125 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestNotify_TiersByPriority(t *testing.T) {
+	r, transport, sessions, townBeads := newTestMemoryRouter(t, session.MayorSessionName())
+	send := func(subject string, priority Priority) {
+		t.Helper()
+		if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: subject, Priority: priority}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	send("FYI", PriorityLow)
+	send("Status 1", PriorityNormal)
+	send("Status 2", PriorityNormal)
+	if n := len(sessions.Nudges()); n != 0 {
+		t.Fatalf("low and normal mail nudged %d times, want 0", n)
+	}
+	if notices, _ := r.Notices(); len(notices) != 2 {
+		t.Fatalf("notices = %+v, want the 2 normal messages batched", notices)
+	}
+
+	// High mail nudges at once, carrying the batch along
+	send("Merge ready", PriorityHigh)
+	nudges := sessions.Nudges()
+	if len(nudges) != 1 || !strings.Contains(nudges[0].Message, "Merge ready") || !strings.Contains(nudges[0].Message, "2 other new message") {
+		t.Fatalf("nudges = %+v, want one about the high mail and the batch", nudges)
+	}
+	if notices, _ := r.Notices(); len(notices) != 0 {
+		t.Errorf("batch not cleared by the nudge: %+v", notices)
+	}
+
+	// The next batch goes out with the daemon's flush
+	send("Status 3", PriorityNormal)
+	now := time.Now()
+	if n, err := r.FlushNotifications(now); err != nil || n != 1 {
+		t.Fatalf("FlushNotifications = %d, %v; want 1 nudge", n, err)
+	}
+	if nudges := sessions.Nudges(); len(nudges) != 2 || !strings.Contains(nudges[1].Message, "Status 3") {
+		t.Errorf("nudges = %+v, want a digest of Status 3", nudges)
+	}
+
+	// Urgent mail repeats until read
+	send("Outage", PriorityUrgent)
+	if n := len(sessions.Nudges()); n != 3 {
+		t.Fatalf("urgent mail nudged %d times in all, want 3", n)
+	}
+	if n, _ := r.FlushNotifications(now); n != 0 {
+		t.Errorf("repeated before due (%d nudges)", n)
+	}
+	if n, _ := r.FlushNotifications(now.Add(config.DefaultMailNotifyRepeat + time.Minute)); n != 1 {
+		t.Errorf("repeat nudges when due = %d, want 1", n)
+	}
+	var urgent *Message
+	for _, m := range transport.Inbox(townBeads, "mayor/") {
+		if m.Subject == "Outage" {
+			urgent = m
+		}
+	}
+	mailbox, _ := r.GetMailbox("mayor/")
+	if err := mailbox.MarkReadOnly(urgent.ID); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := r.FlushNotifications(now.Add(time.Hour)); n != 0 {
+		t.Errorf("repeated after the mail was read (%d nudges)", n)
+	}
+	if notices, _ := r.Notices(); len(notices) != 0 {
+		t.Errorf("notices left after read: %+v", notices)
+	}
+}
+
+func TestNotify_PolicyPerRole(t *testing.T) {
+	r, _, sessions, _ := newTestMemoryRouter(t, session.MayorSessionName())
+	cfg := config.NewMessagingConfig()
+	cfg.Notify = map[string]config.MailNotifyPolicy{
+		"default": {Normal: config.MailNotifyNone},
+		"mayor":   {Low: config.MailNotifyImmediate},
+	}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(r.townRoot), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []Priority{PriorityLow, PriorityNormal} {
+		if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: string(p), Priority: p}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if nudges := sessions.Nudges(); len(nudges) != 1 || !strings.Contains(nudges[0].Message, "low") {
+		t.Errorf("nudges = %+v, want only the low mail, per the mayor's policy", nudges)
+	}
+	if notices, _ := r.Notices(); len(notices) != 0 {
+		t.Errorf("normal mail batched despite the default policy: %+v", notices)
+	}
+
+	cfg.Notify["mayor"] = config.MailNotifyPolicy{Urgent: "loud"}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(r.townRoot), cfg); err == nil {
+		t.Error("saved an invalid tier")
+	}
+}
+
+func TestAddressRole(t *testing.T) {
+	tests := map[string]string{
+		"mayor/":               "mayor",
+		"deacon":               "deacon",
+		"gastown/witness":      "witness",
+		"gastown/refinery":     "refinery",
+		"gastown/Toast":        "polecat",
+		"gastown/polecats/Nux": "polecat",
+		"gastown/crew/max":     "crew",
+		"overseer":             "",
+	}
+	for addr, want := range tests {
+		if got := addressRole(addr); got != want {
+			t.Errorf("addressRole(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
@@ -65,8 +65,8 @@ func (r *Resolver) Resolve(address string) ([]Recipient, error) {
 		return r.resolveChannel(name)
 	}
 
-	// Legacy prefixes (list:, announce:) - pass through
-	if strings.HasPrefix(address, "list:") || strings.HasPrefix(address, "announce:") {
+	// Legacy prefixes (list:, announce:) and all-<role> broadcasts - pass through
+	if strings.HasPrefix(address, "list:") || strings.HasPrefix(address, "announce:") || IsRoleBroadcast(address) {
 		// These are handled by existing router logic
 		return []Recipient{{Address: address, Type: RecipientAgent}}, nil
 	}
@@ -156,7 +156,7 @@ func (r *Resolver) resolveAtPattern(address string) ([]Recipient, error) {
 // resolveByName looks up a name as group → queue → channel.
 // Returns error if name conflicts exist without explicit prefix.
 func (r *Resolver) resolveByName(name string) ([]Recipient, error) {
-	var foundGroup, foundQueue, foundChannel bool
+	var foundGroup, foundQueue, foundChannel, foundList bool
 	var groupFields *beads.GroupFields
 
 	// Check for beads-native group
@@ -203,6 +203,9 @@ func (r *Resolver) resolveByName(name string) ([]Recipient, error) {
 			if _, ok := cfg.Announces[name]; ok {
 				foundChannel = true
 			}
+			if _, ok := cfg.Lists[name]; ok {
+				foundList = true
+			}
 		}
 	}
 
@@ -217,9 +220,12 @@ func (r *Resolver) resolveByName(name string) ([]Recipient, error) {
 	if foundChannel {
 		conflictCount++
 	}
+	if foundList {
+		conflictCount++
+	}
 
 	if conflictCount == 0 {
-		return nil, fmt.Errorf("unknown address: %s (not a group, queue, or channel)", name)
+		return nil, fmt.Errorf("unknown address: %s (not a group, queue, channel, or list)", name)
 	}
 
 	if conflictCount > 1 {
@@ -233,6 +239,9 @@ func (r *Resolver) resolveByName(name string) ([]Recipient, error) {
 		if foundChannel {
 			types = append(types, "channel:"+name)
 		}
+		if foundList {
+			types = append(types, "list:"+name)
+		}
 		return nil, fmt.Errorf("ambiguous address %q: matches multiple types. Use explicit prefix: %s",
 			name, strings.Join(types, ", "))
 	}
@@ -244,6 +253,10 @@ func (r *Resolver) resolveByName(name string) ([]Recipient, error) {
 	if foundQueue {
 		return r.resolveQueue(name)
 	}
+	if foundList {
+		// Lists are expanded by the router, which marks the copies as a broadcast
+		return []Recipient{{Address: "list:" + name, Type: RecipientAgent}}, nil
+	}
 	return r.resolveChannel(name)
 }
 
@@ -0,0 +1,281 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// compactInterval is how often the daemon applies a town's retention
+// policy.
+const compactInterval = 24 * time.Hour
+
+// ArchivedMessage is a message exported to the town's mail archive.
+type ArchivedMessage struct {
+	*Message
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// CompactResult reports what applying a retention policy did, or with
+// DryRun would do.
+type CompactResult struct {
+	Archived int       `json:"archived"` // messages moved from beads to the archive
+	Purged   int       `json:"purged"`   // archived messages dropped
+	Files    []string  `json:"files,omitempty"`
+	DryRun   bool      `json:"dry_run,omitempty"`
+	RanAt    time.Time `json:"ran_at"`
+}
+
+// MailArchiveDir returns the directory a town's archived mail is exported
+// to: one JSONL file of ArchivedMessage records per month sent, for the
+// narrator and other history readers.
+func MailArchiveDir(townRoot string) string {
+	return filepath.Join(townRoot, "mail", "archive")
+}
+
+// compactStatePath is where the last compaction's result is kept, so the
+// daemon knows when the next one is due.
+func compactStatePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "mail-compact.json")
+}
+
+// Compact applies a retention policy as of now. Read messages sent more
+// than policy.ArchiveAfterDays ago are appended to the mail archive and
+// deleted from their beads database; pinned and snoozed messages stay put.
+// Archived messages sent more than policy.PurgeAfterDays ago are then
+// dropped from the archive. With dryRun nothing is changed.
+func (r *Router) Compact(policy config.MailRetention, now time.Time, dryRun bool) (*CompactResult, error) {
+	if r.townRoot == "" {
+		return nil, errors.New("mail compaction needs a town")
+	}
+	res := &CompactResult{DryRun: dryRun, RanAt: now}
+	var errs []error
+	if policy.ArchiveAfterDays > 0 {
+		errs = append(errs, r.archiveOld(now.AddDate(0, 0, -policy.ArchiveAfterDays), res))
+	}
+	if policy.PurgeAfterDays > 0 {
+		errs = append(errs, r.purgeArchive(now.AddDate(0, 0, -policy.PurgeAfterDays), res))
+	}
+	sort.Strings(res.Files)
+	if !dryRun {
+		if err := os.MkdirAll(filepath.Dir(compactStatePath(r.townRoot)), 0755); err != nil {
+			errs = append(errs, err)
+		} else if err := util.AtomicWriteJSON(compactStatePath(r.townRoot), res); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// CompactIfDue applies the town's retention policy if it asks for the
+// daemon to, and it hasn't run in the last day. Returns nil if it didn't
+// run.
+func (r *Router) CompactIfDue(now time.Time) (*CompactResult, error) {
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil || cfg.Retention == nil || !cfg.Retention.Daemon {
+		return nil, nil
+	}
+	var last CompactResult
+	if data, err := os.ReadFile(compactStatePath(r.townRoot)); err == nil { //nolint:gosec // G304: path is constructed from the town root
+		_ = json.Unmarshal(data, &last)
+	}
+	if now.Sub(last.RanAt) < compactInterval {
+		return nil, nil
+	}
+	return r.Compact(*cfg.Retention, now, false)
+}
+
+// archiveOld moves read mail sent before cutoff into the archive.
+func (r *Router) archiveOld(cutoff time.Time, res *CompactResult) error {
+	snoozed := make(map[string]bool)
+	scheduled, err := r.ScheduledMail()
+	if err != nil {
+		return fmt.Errorf("reading snoozed mail: %w", err)
+	}
+	for _, s := range scheduled {
+		if s.Kind == ScheduleSnooze {
+			snoozed[s.Message.ID] = true
+		}
+	}
+
+	var errs []error
+	for _, beadsDir := range r.mailBeadsDirs() {
+		beadsMsgs, err := r.transport.List(beadsDir, Query{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing mail in %s: %w", beadsDir, err))
+			continue
+		}
+		byMonth := make(map[string][]*Message)
+		for i := range beadsMsgs {
+			msg := beadsMsgs[i].ToMessage()
+			if !msg.Read || beadsMsgs[i].Pinned || snoozed[msg.ID] || !msg.Timestamp.Before(cutoff) {
+				continue
+			}
+			month := msg.Timestamp.UTC().Format("2006-01")
+			byMonth[month] = append(byMonth[month], msg)
+		}
+		for month, msgs := range byMonth {
+			path := filepath.Join(MailArchiveDir(r.townRoot), month+".jsonl")
+			res.addFile(path)
+			res.Archived += len(msgs)
+			if res.DryRun {
+				continue
+			}
+			// Write the archive before deleting, so a failed delete only
+			// leaves a message to be archived again (and deduplicated)
+			if err := appendMailArchive(path, msgs, res.RanAt); err != nil {
+				errs = append(errs, fmt.Errorf("archiving %s: %w", month, err))
+				res.Archived -= len(msgs)
+				continue
+			}
+			for _, msg := range msgs {
+				if err := r.transport.Delete(beadsDir, msg.ID); err != nil && !errors.Is(err, ErrMessageNotFound) {
+					errs = append(errs, fmt.Errorf("deleting archived %s: %w", msg.ID, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// purgeArchive drops archived mail sent before cutoff.
+func (r *Router) purgeArchive(cutoff time.Time, res *CompactResult) error {
+	paths, err := filepath.Glob(filepath.Join(MailArchiveDir(r.townRoot), "*.jsonl"))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		records, err := readMailArchiveFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var keep []ArchivedMessage
+		for _, rec := range records {
+			if rec.Timestamp.Before(cutoff) {
+				res.Purged++
+			} else {
+				keep = append(keep, rec)
+			}
+		}
+		if len(keep) == len(records) {
+			continue
+		}
+		res.addFile(path)
+		if res.DryRun {
+			continue
+		}
+		if len(keep) == 0 {
+			err = os.Remove(path)
+		} else {
+			err = writeMailArchiveFile(path, keep)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("purging %s: %w", filepath.Base(path), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (res *CompactResult) addFile(path string) {
+	for _, f := range res.Files {
+		if f == path {
+			return
+		}
+	}
+	res.Files = append(res.Files, path)
+}
+
+// appendMailArchive adds msgs to the archive file at path, skipping any
+// already there.
+func appendMailArchive(path string, msgs []*Message, at time.Time) error {
+	existing, err := readMailArchiveFile(path)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, rec := range existing {
+		seen[rec.ID] = true
+	}
+	for _, msg := range msgs {
+		if !seen[msg.ID] {
+			seen[msg.ID] = true
+			existing = append(existing, ArchivedMessage{Message: msg, ArchivedAt: at})
+		}
+	}
+	return writeMailArchiveFile(path, existing)
+}
+
+// ReadMailArchive returns a town's archived mail, oldest first.
+func ReadMailArchive(townRoot string) ([]ArchivedMessage, error) {
+	paths, err := filepath.Glob(filepath.Join(MailArchiveDir(townRoot), "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	var all []ArchivedMessage
+	for _, path := range paths {
+		records, err := readMailArchiveFile(path)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, records...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all, nil
+}
+
+// readMailArchiveFile reads one month of the archive. A missing file is
+// empty.
+func readMailArchiveFile(path string) ([]ArchivedMessage, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []ArchivedMessage
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec ArchivedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Message == nil {
+			return nil, fmt.Errorf("%s:%d: not an archived message", path, line)
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+func writeMailArchiveFile(path string, records []ArchivedMessage) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, rec := range records {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return util.AtomicWriteFile(path, []byte(b.String()), 0644)
+}
@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestCompact_ArchivesAndPurges(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+	now := time.Now()
+	sent := now.AddDate(0, 0, -60)
+	transport.Now = func() time.Time { return sent }
+	for _, msg := range []*Message{
+		{From: "gastown/Toast", To: "mayor/", Subject: "Old and read"},
+		{From: "gastown/Toast", To: "mayor/", Subject: "Old and unread"},
+		{From: "gastown/Toast", To: "mayor/", Subject: "Old but snoozed"},
+	} {
+		if err := r.Send(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	transport.Now = nil
+	if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "New and read"}); err != nil {
+		t.Fatal(err)
+	}
+	mailbox, _ := r.GetMailbox("mayor/")
+	for _, m := range transport.Messages(townBeads) {
+		switch m.Subject {
+		case "Old and read", "New and read":
+			if err := mailbox.MarkRead(m.ID); err != nil {
+				t.Fatal(err)
+			}
+		case "Old but snoozed":
+			if _, err := r.Snooze("mayor/", m.ID, now.Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	policy := config.MailRetention{ArchiveAfterDays: 30}
+	res, err := r.Compact(policy, now, true)
+	if err != nil || res.Archived != 1 || len(transport.Messages(townBeads)) != 4 {
+		t.Fatalf("dry run = %+v, %v; want 1 to archive and nothing changed", res, err)
+	}
+
+	if res, err = r.Compact(policy, now, false); err != nil || res.Archived != 1 {
+		t.Fatalf("Compact = %+v, %v; want 1 archived", res, err)
+	}
+	for _, m := range transport.Messages(townBeads) {
+		if m.Subject == "Old and read" {
+			t.Error("archived message still in beads")
+		}
+	}
+	archived, err := ReadMailArchive(r.townRoot)
+	if err != nil || len(archived) != 1 || archived[0].Subject != "Old and read" || archived[0].ArchivedAt.IsZero() {
+		t.Fatalf("archive = %+v, %v", archived, err)
+	}
+	file := filepath.Join(MailArchiveDir(r.townRoot), sent.UTC().Format("2006-01")+".jsonl")
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("monthly archive file: %v", err)
+	}
+
+	// Not due again for a day, and only with the daemon asked to run it
+	cfg := config.NewMessagingConfig()
+	cfg.Retention = &config.MailRetention{PurgeAfterDays: 30, Daemon: true}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(r.townRoot), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := r.CompactIfDue(now.Add(time.Hour)); res != nil {
+		t.Errorf("compacted again within the day: %+v", res)
+	}
+	res, err = r.CompactIfDue(now.Add(25 * time.Hour))
+	if err != nil || res == nil || res.Purged != 1 {
+		t.Fatalf("CompactIfDue = %+v, %v; want the archived message purged", res, err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Error("emptied archive file left behind")
+	}
+}
@@ -6,14 +6,20 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
+// logger records delivery problems that don't fail a send.
+var logger = gtlog.For("mail")
+
 // ErrUnknownList indicates a mailing list name was not found in configuration.
 var ErrUnknownList = errors.New("unknown mailing list")
 
@@ -28,9 +34,12 @@ var ErrUnknownAnnounce = errors.New("unknown announce channel")
 // - Town-level (mayor/, deacon/) -> {townRoot}/.beads
 // - Rig-level (rig/polecat) -> {townRoot}/{rig}/.beads
 type Router struct {
-	workDir  string // fallback directory to run bd commands in
-	townRoot string // town root directory (e.g., ~/gt)
-	tmux     *tmux.Tmux
+	workDir   string // fallback directory to run bd commands in
+	townRoot  string // town root directory (e.g., ~/gt)
+	tmux      Sessions
+	desktop   notify.Notifier // nil uses a platform desktop notifier
+	transport Transport
+	backoff   time.Duration // wait before retrying a failed delivery
 }
 
 // NewRouter creates a new mail router.
@@ -41,18 +50,22 @@ func NewRouter(workDir string) *Router {
 	townRoot := detectTownRoot(workDir)
 
 	return &Router{
-		workDir:  workDir,
-		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		workDir:   workDir,
+		townRoot:  townRoot,
+		tmux:      tmux.NewTmux(),
+		transport: TransportForTown(townRoot),
+		backoff:   deliveryBackoff,
 	}
 }
 
 // NewRouterWithTownRoot creates a router with an explicit town root.
 func NewRouterWithTownRoot(workDir, townRoot string) *Router {
 	return &Router{
-		workDir:  workDir,
-		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		workDir:   workDir,
+		townRoot:  townRoot,
+		tmux:      tmux.NewTmux(),
+		transport: TransportForTown(townRoot),
+		backoff:   deliveryBackoff,
 	}
 }
 
@@ -175,30 +188,152 @@ func detectTownRoot(startDir string) string {
 // resolveBeadsDir returns the correct .beads directory for the given address.
 //
 // Two-level beads architecture:
-// - ALL mail uses town beads ({townRoot}/.beads) regardless of address
+// - Mail uses town beads ({townRoot}/.beads) by default, regardless of address
 // - Rig-level beads ({rig}/.beads) are for project issues only, not mail
 //
-// This ensures messages are visible to all agents in the town.
-func (r *Router) resolveBeadsDir(_ string) string { // address unused: all mail uses town-level beads
+// This ensures messages are visible to all agents in the town. Towns whose
+// rigs keep separate databases can route address prefixes elsewhere with
+// the routes table in config/messaging.json (see routedBeadsDir).
+func (r *Router) resolveBeadsDir(address string) string {
 	// If no town root, fall back to workDir's .beads
 	if r.townRoot == "" {
 		return filepath.Join(r.workDir, ".beads")
 	}
 
-	// All mail uses town-level beads
+	if beadsDir := r.routedBeadsDir(address); beadsDir != "" {
+		return beadsDir
+	}
+
+	// Unrouted mail uses town-level beads
 	return filepath.Join(r.townRoot, ".beads")
 }
 
+// routedBeadsDir returns the beads directory the routing table assigns to
+// address, or "" if no route matches.
+func (r *Router) routedBeadsDir(address string) string {
+	if address == "" {
+		return ""
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		return ""
+	}
+	route := matchMailRoute(cfg.Routes, address)
+	if route == nil {
+		return ""
+	}
+	return beads.ResolveBeadsDir(filepath.Join(r.townRoot, route.Path))
+}
+
+// resolveBeadsDirs returns the distinct beads directories holding the
+// mailboxes of addrs, in the order first seen. Unlike calling
+// resolveBeadsDir for each, it reads the routing table once.
+func (r *Router) resolveBeadsDirs(addrs []string) []string {
+	if r.townRoot == "" {
+		return []string{filepath.Join(r.workDir, ".beads")}
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		cfg = nil
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		if addr == "" {
+			continue
+		}
+		dir := filepath.Join(r.townRoot, ".beads")
+		if cfg != nil {
+			if route := matchMailRoute(cfg.Routes, addr); route != nil {
+				dir = beads.ResolveBeadsDir(filepath.Join(r.townRoot, route.Path))
+			}
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// mailBeadsDirs returns every beads directory that may hold mail: the town
+// database followed by each distinct routed database.
+func (r *Router) mailBeadsDirs() []string {
+	dirs := []string{r.resolveBeadsDir("")}
+	if r.townRoot == "" {
+		return dirs
+	}
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		return dirs
+	}
+	seen := map[string]bool{dirs[0]: true}
+	for _, route := range cfg.Routes {
+		dir := beads.ResolveBeadsDir(filepath.Join(r.townRoot, route.Path))
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// matchMailRoute returns the route with the longest prefix matching address,
+// or nil. A prefix matches the address itself and any address under it.
+func matchMailRoute(routes []config.MailRoute, address string) *config.MailRoute {
+	addr := strings.TrimSuffix(address, "/")
+	var best *config.MailRoute
+	bestLen := -1
+	for i := range routes {
+		prefix := strings.TrimSuffix(routes[i].Prefix, "/")
+		if prefix == "" || (addr != prefix && !strings.HasPrefix(addr, prefix+"/")) {
+			continue
+		}
+		if len(prefix) > bestLen {
+			best, bestLen = &routes[i], len(prefix)
+		}
+	}
+	return best
+}
+
 // isTownLevelAddress returns true if the address is for a town-level agent or the overseer.
 func isTownLevelAddress(address string) bool {
 	addr := strings.TrimSuffix(address, "/")
 	return addr == "mayor" || addr == "deacon" || addr == "overseer"
 }
 
-// isGroupAddress returns true if the address is a @group address.
-// Group addresses start with @ and resolve to multiple recipients.
+// isGroupAddress returns true if the address is a @group address or one of
+// its bare aliases (<rig>/*, all-polecats, ...). Group addresses resolve to
+// multiple recipients.
 func isGroupAddress(address string) bool {
-	return strings.HasPrefix(address, "@")
+	if strings.HasPrefix(address, "@") {
+		return true
+	}
+	_, ok := roleBroadcasts[address]
+	return ok || isRigWildcard(address)
+}
+
+// roleBroadcasts maps the all-<role> aliases to the role they reach in
+// every rig.
+var roleBroadcasts = map[string]string{
+	"all-polecats":   "polecat",
+	"all-crew":       "crew",
+	"all-witnesses":  "witness",
+	"all-refineries": "refinery",
+	"all-dogs":       "dog",
+}
+
+// IsRoleBroadcast reports whether address is an all-<role> alias.
+func IsRoleBroadcast(address string) bool {
+	_, ok := roleBroadcasts[address]
+	return ok
+}
+
+// isRigWildcard reports whether address is <rig>/*, every agent in a rig.
+func isRigWildcard(address string) bool {
+	rig, ok := strings.CutSuffix(address, "/*")
+	return ok && rig != "" && !strings.ContainsAny(rig, "/*@:")
 }
 
 // GroupType represents the type of group address.
@@ -231,10 +366,19 @@ type ParsedGroup struct {
 //   - @polecats/<rigname>: Polecats in a specific rig
 //   - @dogs: All Deacon dogs
 //   - @overseer: Human operator (special case)
+//   - <rig>/*: All agents in a rig (same as @rig/<rigname>)
+//   - all-polecats, all-crew, all-witnesses, all-refineries, all-dogs:
+//     every agent of that role across rigs
 func parseGroupAddress(address string) *ParsedGroup {
 	if !isGroupAddress(address) {
 		return nil
 	}
+	if role, ok := roleBroadcasts[address]; ok {
+		return &ParsedGroup{Type: GroupTypeRole, RoleType: role, Original: address}
+	}
+	if isRigWildcard(address) {
+		return &ParsedGroup{Type: GroupTypeRig, Rig: strings.TrimSuffix(address, "/*"), Original: address}
+	}
 
 	// Remove @ prefix
 	group := strings.TrimPrefix(address, "@")
@@ -548,10 +692,11 @@ func (r *Router) sendToGroup(msg *Message) error {
 	}
 
 	// Fan-out: send a copy to each recipient
+	base := broadcastBase(msg)
 	var errs []string
 	for _, recipient := range recipients {
 		// Create a copy of the message for this recipient
-		msgCopy := *msg
+		msgCopy := base
 		msgCopy.To = recipient
 
 		if err := r.sendToSingle(&msgCopy); err != nil {
@@ -566,8 +711,37 @@ func (r *Router) sendToGroup(msg *Message) error {
 	return nil
 }
 
-// sendToSingle sends a message to a single recipient.
+// broadcastBase returns the message each fan-out copy is made from. Copies
+// share one thread and record the group address they were sent to; a list
+// member that is itself a group keeps the outermost address.
+func broadcastBase(msg *Message) Message {
+	base := *msg
+	if base.ThreadID == "" {
+		base.ThreadID = generateThreadID()
+	}
+	if base.Broadcast == "" {
+		base.Broadcast = msg.To
+	}
+	return base
+}
+
+// sendToSingle sends a message to a single recipient. Failed deliveries are
+// retried with backoff; a message that still can't be delivered is saved to
+// the town's dead-letter queue (see RetryDeadLetter).
 func (r *Router) sendToSingle(msg *Message) error {
+	err := r.deliverSingle(msg)
+	if err == nil {
+		return nil
+	}
+	if id, dlErr := r.deadLetter(msg, err); dlErr == nil {
+		return fmt.Errorf("sending message (saved as dead letter %s): %w", id, err)
+	}
+	return fmt.Errorf("sending message: %w", err)
+}
+
+// deliverSingle stores a message for a single recipient and notifies them,
+// retrying transport failures.
+func (r *Router) deliverSingle(msg *Message) error {
 	// Convert addresses to beads identities
 	toIdentity := addressToIdentity(msg.To)
 
@@ -580,46 +754,45 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.Broadcast != "" {
+		labels = append(labels, "broadcast:"+msg.Broadcast)
+	}
+	if msg.RequestReceipt {
+		labels = append(labels, "request-receipt")
+	}
 	// Add CC labels (one per recipient)
 	for _, cc := range msg.CC {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
-	args := []string{"create", msg.Subject,
-		"--type", "message",
-		"--assignee", toIdentity,
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
-	// Add --ephemeral flag for ephemeral messages (stored in single DB, filtered from JSONL export)
-	if r.shouldBeWisp(msg) {
-		args = append(args, "--ephemeral")
-	}
-
 	beadsDir := r.resolveBeadsDir(msg.To)
-	_, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
-	if err != nil {
-		return fmt.Errorf("sending message: %w", err)
+	bead := &BeadsMessage{
+		Title:       msg.Subject,
+		Description: msg.Body,
+		Assignee:    toIdentity,
+		Priority:    PriorityToBeads(msg.Priority),
+		Labels:      labels,
+		// Ephemeral messages are stored in the same DB, filtered from JSONL export
+		Wisp: r.shouldBeWisp(msg),
+	}
+	var id string
+	if err := r.withRetry(func() error {
+		var err error
+		id, err = r.transport.Create(beadsDir, bead, msg.From)
+		return err
+	}); err != nil {
+		return err
 	}
+	logger.Debug("delivered", "from", msg.From, "to", toIdentity, "subject", msg.Subject)
 
-	// Notify recipient if they have an active session (best-effort notification)
+	// Notify recipient as their notification policy says (best-effort,
+	// retried since the message is already delivered)
 	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
 	if !isSelfMail(msg.From, msg.To) {
-		_ = r.notifyRecipient(msg)
+		if err := r.withRetry(func() error { return r.notify(msg, id) }); err != nil {
+			logger.Warn("notifying recipient", "to", msg.To, "err", err)
+		}
 	}
 
 	return nil
@@ -636,11 +809,12 @@ func (r *Router) sendToList(msg *Message) error {
 	}
 
 	// Send to each recipient
+	base := broadcastBase(msg)
 	var lastErr error
 	successCount := 0
 	for _, recipient := range recipients {
 		// Create a copy of the message for this recipient
-		copy := *msg
+		copy := base
 		copy.To = recipient
 
 		if err := r.Send(&copy); err != nil {
@@ -696,32 +870,18 @@ func (r *Router) sendToQueue(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
-	// Use queue:<name> as assignee so inbox queries can filter by queue
-	args := []string{"create", msg.Subject,
-		"--type", "message",
-		"--assignee", msg.To, // queue:name
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels (includes queue name for filtering)
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
+	// Use queue:<name> as assignee so inbox queries can filter by queue.
 	// Queue messages are never ephemeral - they need to persist until claimed
-	// (deliberately not checking shouldBeWisp)
-
-	// Queue messages go to town-level beads (shared location)
+	// (deliberately not checking shouldBeWisp).
+	// Queue messages go to town-level beads (shared location).
 	beadsDir := r.resolveBeadsDir("")
-	_, err = runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	_, err = r.transport.Create(beadsDir, &BeadsMessage{
+		Title:       msg.Subject,
+		Description: msg.Body,
+		Assignee:    msg.To, // queue:name
+		Priority:    PriorityToBeads(msg.Priority),
+		Labels:      labels, // includes queue name for filtering
+	}, msg.From)
 	if err != nil {
 		return fmt.Errorf("sending to queue %s: %w", queueName, err)
 	}
@@ -748,7 +908,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		if err := r.pruneAnnounce(announceName, announceCfg.RetainCount); err != nil {
 			// Log but don't fail - pruning is best-effort
 			// The new message should still be created
-			_ = err
+			logger.Warn("pruning announce channel", "channel", announceName, "err", err)
 		}
 	}
 
@@ -767,32 +927,18 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --type=message --assignee=announce:<name> -d <body>
-	// Use announce:<name> as assignee so queries can filter by channel
-	args := []string{"create", msg.Subject,
-		"--type", "message",
-		"--assignee", msg.To, // announce:name
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels (includes announce name for filtering)
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
+	// Use announce:<name> as assignee so queries can filter by channel.
 	// Announce messages are never ephemeral - they need to persist for readers
-	// (deliberately not checking shouldBeWisp)
-
-	// Announce messages go to town-level beads (shared location)
+	// (deliberately not checking shouldBeWisp).
+	// Announce messages go to town-level beads (shared location).
 	beadsDir := r.resolveBeadsDir("")
-	_, err = runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	_, err = r.transport.Create(beadsDir, &BeadsMessage{
+		Title:       msg.Subject,
+		Description: msg.Body,
+		Assignee:    msg.To, // announce:name
+		Priority:    PriorityToBeads(msg.Priority),
+		Labels:      labels, // includes announce name for filtering
+	}, msg.From)
 	if err != nil {
 		return fmt.Errorf("sending to announce %s: %w", announceName, err)
 	}
@@ -840,32 +986,18 @@ func (r *Router) sendToChannel(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
-	// Build command: bd create <subject> --type=message --assignee=channel:<name> -d <body>
-	// Use channel:<name> as assignee so queries can filter by channel
-	args := []string{"create", msg.Subject,
-		"--type", "message",
-		"--assignee", msg.To, // channel:name
-		"-d", msg.Body,
-	}
-
-	// Add priority flag
-	beadsPriority := PriorityToBeads(msg.Priority)
-	args = append(args, "--priority", fmt.Sprintf("%d", beadsPriority))
-
-	// Add labels (includes channel name for filtering)
-	if len(labels) > 0 {
-		args = append(args, "--labels", strings.Join(labels, ","))
-	}
-
-	// Add actor for attribution (sender identity)
-	args = append(args, "--actor", msg.From)
-
+	// Use channel:<name> as assignee so queries can filter by channel.
 	// Channel messages are never ephemeral - they persist according to retention policy
-	// (deliberately not checking shouldBeWisp)
-
-	// Channel messages go to town-level beads (shared location)
+	// (deliberately not checking shouldBeWisp).
+	// Channel messages go to town-level beads (shared location).
 	beadsDir := r.resolveBeadsDir("")
-	_, err = runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	_, err = r.transport.Create(beadsDir, &BeadsMessage{
+		Title:       msg.Subject,
+		Description: msg.Body,
+		Assignee:    msg.To, // channel:name
+		Priority:    PriorityToBeads(msg.Priority),
+		Labels:      labels, // includes channel name for filtering
+	}, msg.From)
 	if err != nil {
 		return fmt.Errorf("sending to channel %s: %w", channelName, err)
 	}
@@ -887,7 +1019,9 @@ func (r *Router) sendToChannel(msg *Message) error {
 			msgCopy.Subject = fmt.Sprintf("[channel:%s] %s", channelName, msg.Subject)
 
 			// Best-effort delivery - don't fail the channel send if one subscriber fails
-			_ = r.sendToSingle(&msgCopy)
+			if err := r.sendToSingle(&msgCopy); err != nil {
+				logger.Warn("delivering to channel subscriber", "channel", channelName, "to", subscriber, "err", err)
+			}
 		}
 	}
 
@@ -903,30 +1037,12 @@ func (r *Router) pruneAnnounce(announceName string, retainCount int) error {
 
 	beadsDir := r.resolveBeadsDir("")
 
-	// Query existing messages in this announce channel
-	// Use bd list with labels filter to find messages with announce:<name> label
-	args := []string{"list",
-		"--type=message",
-		"--labels=announce:" + announceName,
-		"--json",
-		"--limit=0", // Get all
-		"--sort=created",
-		"--asc", // Oldest first
-	}
-
-	stdout, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	// Query existing messages in this announce channel, oldest first
+	messages, err := r.transport.List(beadsDir, Query{Label: "announce:" + announceName})
 	if err != nil {
 		return fmt.Errorf("querying announce messages: %w", err)
 	}
 
-	// Parse message list
-	var messages []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(stdout, &messages); err != nil {
-		return fmt.Errorf("parsing announce messages: %w", err)
-	}
-
 	// Calculate how many to delete (we're about to add 1 more)
 	// If we have N messages and retainCount is R, we need to keep at most R-1 after pruning
 	// so the new message makes it exactly R
@@ -937,9 +1053,10 @@ func (r *Router) pruneAnnounce(announceName string, retainCount int) error {
 
 	// Delete oldest messages
 	for i := 0; i < toDelete && i < len(messages); i++ {
-		deleteArgs := []string{"close", messages[i].ID, "--reason=retention pruning"}
 		// Best-effort deletion - don't fail if one delete fails
-		_, _ = runBdCommand(deleteArgs, filepath.Dir(beadsDir), beadsDir)
+		if err := r.transport.Close(beadsDir, messages[i].ID, "retention pruning"); err != nil {
+			logger.Warn("pruning announce message", "channel", announceName, "id", messages[i].ID, "err", err)
+		}
 	}
 
 	return nil
@@ -958,13 +1075,78 @@ func isSelfMail(from, to string) bool {
 func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 	beadsDir := r.resolveBeadsDir(address)
 	workDir := filepath.Dir(beadsDir) // Parent of .beads
-	return NewMailboxFromAddress(address, workDir), nil
+	mailbox := NewMailboxFromAddress(address, workDir)
+	if _, isBd := r.transport.(bdTransport); !isBd {
+		mailbox.transport = r.transport
+	}
+	return mailbox, nil
+}
+
+// MarkRead marks a message in address's mailbox read, leaving it in the
+// inbox. If the message requested a receipt and was unread, the receipt is
+// mailed back to its sender; a failed receipt is returned as an error after
+// the message has been marked.
+func (r *Router) MarkRead(address, id string) error {
+	mailbox, err := r.GetMailbox(address)
+	if err != nil {
+		return err
+	}
+	msg, err := mailbox.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := mailbox.MarkReadOnly(id); err != nil {
+		return err
+	}
+	if msg.Read || !msg.RequestReceipt || msg.From == "" || isSelfMail(msg.From, msg.To) {
+		return nil
+	}
+	// Reload for the read receipt just recorded
+	if read, err := mailbox.Get(id); err == nil {
+		msg = read
+	}
+	if err := r.Send(NewReceiptMessage(msg)); err != nil {
+		return fmt.Errorf("sending receipt to %s: %w", msg.From, err)
+	}
+	return nil
+}
+
+// UnreadCounts returns the number of unread messages per recipient address
+// across every mail database, for dashboards. It only reads.
+func (r *Router) UnreadCounts() (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, dir := range r.mailBeadsDirs() {
+		for _, status := range []string{"open", "hooked"} {
+			beadsMsgs, err := r.transport.List(dir, Query{Status: status})
+			if err != nil {
+				return counts, err
+			}
+			for _, msg := range toMessages(beadsMsgs) {
+				if !msg.Read && msg.To != "" {
+					counts[msg.To]++
+				}
+			}
+		}
+	}
+	return counts, nil
 }
 
 // notifyRecipient sends a notification to a recipient's tmux session.
 // Uses NudgeSession to add the notification to the agent's conversation history.
-// Supports mayor/, rig/polecat, and rig/refinery addresses.
+// Supports mayor/, rig/polecat, and rig/refinery addresses. Mail to the
+// overseer raises a desktop notification instead.
 func (r *Router) notifyRecipient(msg *Message) error {
+	return r.nudgeRecipient(msg, fmt.Sprintf("Mail from %s", msg.From),
+		fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject))
+}
+
+// nudgeRecipient tells msg's recipient about it: notification is nudged into
+// their session, or title raised on the desktop for the overseer.
+func (r *Router) nudgeRecipient(msg *Message, title, notification string) error {
+	if msg.To == "overseer" {
+		return r.notifyOverseer(msg, title)
+	}
+
 	sessionID := addressToSessionID(msg.To)
 	if sessionID == "" {
 		return nil // Unable to determine session ID
@@ -977,10 +1159,48 @@ func (r *Router) notifyRecipient(msg *Message) error {
 	}
 
 	// Send notification to the agent's conversation history
-	notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
 	return r.tmux.NudgeSession(sessionID, notification)
 }
 
+// notifyOverseer raises a desktop notification for mail to the human
+// operator, if the message meets the overseer's configured priority
+// threshold (see config.OverseerNotifyConfig).
+func (r *Router) notifyOverseer(msg *Message, title string) error {
+	if r.townRoot == "" {
+		return nil
+	}
+	overseer, err := config.LoadOverseerConfig(config.OverseerConfigPath(r.townRoot))
+	if err != nil {
+		overseer = nil // No overseer config: use defaults
+	}
+
+	// Mail from a rig's agents uses that rig's notification overrides
+	var rigSettings *config.RigSettings
+	if rigName, _, found := strings.Cut(msg.From, "/"); found && rigName != "mayor" && rigName != "deacon" {
+		rigSettings, err = config.LoadRigSettings(config.RigSettingsPath(filepath.Join(r.townRoot, rigName)))
+		if err != nil {
+			rigSettings = nil
+		}
+	}
+	notifyCfg := config.EffectiveNotifyConfig(overseer, rigSettings)
+
+	minPriority := notifyCfg.MinPriority
+	if minPriority == "off" || PriorityToBeads(msg.Priority) > PriorityToBeads(ParsePriority(minPriority)) {
+		return nil
+	}
+
+	notifier := r.desktop
+	if notifier == nil {
+		notifier = notify.NewDesktop(notifyCfg.Command)
+	}
+
+	return notifier.Notify(notify.Notification{
+		Title:  title,
+		Body:   msg.Subject,
+		Urgent: msg.Priority == PriorityUrgent,
+	})
+}
+
 // addressToSessionID converts a mail address to a tmux session ID.
 // Returns empty string if address format is not recognized.
 func addressToSessionID(address string) string {
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/notify"
 )
 
 func TestDetectTownRoot(t *testing.T) {
@@ -211,6 +213,42 @@ func TestResolveBeadsDir(t *testing.T) {
 	}
 }
 
+func TestResolveBeadsDirRoutes(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"type":"messaging","version":1,"routes":[
+		{"prefix":"gastown/","path":"gastown/mayor/rig"},
+		{"prefix":"gastown/polecats","path":"gastown/polecats-db"}
+	]}`
+	if err := os.WriteFile(filepath.Join(townRoot, "config", "messaging.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"gastown/witness", filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads")},
+		{"gastown/", filepath.Join(townRoot, "gastown", "mayor", "rig", ".beads")},
+		{"gastown/polecats/Toast", filepath.Join(townRoot, "gastown", "polecats-db", ".beads")},
+		{"gastownish/witness", filepath.Join(townRoot, ".beads")},
+		{"mayor/", filepath.Join(townRoot, ".beads")},
+	}
+	for _, tt := range tests {
+		if got := r.resolveBeadsDir(tt.address); got != tt.want {
+			t.Errorf("resolveBeadsDir(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+
+	dirs := r.mailBeadsDirs()
+	if len(dirs) != 3 || dirs[0] != filepath.Join(townRoot, ".beads") {
+		t.Errorf("mailBeadsDirs = %v, want town database plus 2 routed", dirs)
+	}
+}
+
 func TestNewRouterWithTownRoot(t *testing.T) {
 	r := NewRouterWithTownRoot("/work/rig", "/home/gt")
 	if r.workDir != "/work/rig" {
@@ -575,6 +613,12 @@ func TestIsGroupAddress(t *testing.T) {
 		{"gastown/Toast", false},
 		{"", false},
 		{"rig/gastown", false}, // Missing @
+		{"gastown/*", true},
+		{"all-polecats", true},
+		{"all-crew", true},
+		{"*/witness", false},
+		{"gastown/crew/*", false},
+		{"all-mayors", false},
 	}
 
 	for _, tt := range tests {
@@ -613,6 +657,11 @@ func TestParseGroupAddress(t *testing.T) {
 		{"@crew/gastown", GroupTypeRigRole, "crew", "gastown", false},
 		{"@polecats/gastown", GroupTypeRigRole, "polecat", "gastown", false},
 
+		// Bare aliases
+		{"gastown/*", GroupTypeRig, "", "gastown", false},
+		{"all-polecats", GroupTypeRole, "polecat", "", false},
+		{"all-refineries", GroupTypeRole, "refinery", "", false},
+
 		// Invalid patterns
 		{"mayor/", "", "", "", true},
 		{"@invalid", "", "", "", true},
@@ -813,3 +862,58 @@ func TestExpandAnnounceNoTownRoot(t *testing.T) {
 		t.Errorf("expandAnnounce error = %v, want containing 'no town root'", err)
 	}
 }
+
+type recordingNotifier struct {
+	sent []notify.Notification
+}
+
+func (n *recordingNotifier) Notify(note notify.Notification) error {
+	n.sent = append(n.sent, note)
+	return nil
+}
+
+func TestNotifyRecipientOverseerDesktop(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		priority Priority
+		want     int
+	}{
+		{"default threshold skips normal", "", PriorityNormal, 0},
+		{"default threshold notifies high", "", PriorityHigh, 1},
+		{"configured low", `{"name":"Steve","notifications":{"min_priority":"low"}}`, PriorityLow, 1},
+		{"configured urgent skips high", `{"name":"Steve","notifications":{"min_priority":"urgent"}}`, PriorityHigh, 0},
+		{"off", `{"name":"Steve","notifications":{"min_priority":"off"}}`, PriorityUrgent, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(townRoot, "mayor", "overseer.json")
+			_ = os.Remove(path)
+			if tt.config != "" {
+				if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rec := &recordingNotifier{}
+			r := NewRouterWithTownRoot(townRoot, townRoot)
+			r.desktop = rec
+
+			msg := &Message{From: "gastown/witness", To: "overseer", Subject: "Build failing", Priority: tt.priority}
+			if err := r.notifyRecipient(msg); err != nil {
+				t.Fatalf("notifyRecipient: %v", err)
+			}
+			if len(rec.sent) != tt.want {
+				t.Fatalf("sent %d notifications, want %d", len(rec.sent), tt.want)
+			}
+			if tt.want == 1 && (rec.sent[0].Body != "Build failing" || rec.sent[0].Urgent != (tt.priority == PriorityUrgent)) {
+				t.Errorf("notification = %+v", rec.sent[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Kinds of scheduled mail.
+const (
+	ScheduleSend   = "send"   // a message to send later
+	ScheduleSnooze = "snooze" // a received message to re-surface later
+)
+
+// DefaultWakeHour is the hour a time given as a day alone ("tomorrow",
+// "friday", "2026-03-01") refers to.
+const DefaultWakeHour = 9
+
+// ErrScheduledNotFound indicates no scheduled mail has the given ID.
+var ErrScheduledNotFound = errors.New("scheduled mail not found")
+
+// Scheduled is mail waiting for its time: a message to send (ScheduleSend)
+// or a received message snoozed until it should be re-surfaced as new
+// (ScheduleSnooze). It is kept in the town's scheduled-mail directory until
+// the daemon runs it (see RunDue).
+type Scheduled struct {
+	ID      string    `json:"id"`
+	Kind    string    `json:"kind"`
+	Due     time.Time `json:"due"`
+	Message *Message  `json:"message"`
+
+	// Address is the mailbox a snoozed message is in.
+	Address string `json:"address,omitempty"`
+
+	// Error is why the last attempt to wake a snoozed message failed.
+	Error string `json:"error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduledDir returns the directory a town keeps scheduled and snoozed
+// mail in.
+func ScheduledDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "mail-scheduled")
+}
+
+func scheduledPath(townRoot, id string) string {
+	return filepath.Join(ScheduledDir(townRoot), id+".json")
+}
+
+// Schedule saves msg to be sent at due. The message is routed when it is
+// sent, so addresses are resolved then. Returns the schedule's ID.
+func (r *Router) Schedule(msg *Message, due time.Time) (string, error) {
+	return r.saveScheduled(&Scheduled{Kind: ScheduleSend, Due: due, Message: msg})
+}
+
+// Snooze marks message id in address's mailbox read and schedules it to be
+// marked unread again at until, with a fresh notification to the recipient.
+// Snoozing doesn't send read receipts. Returns the schedule's ID.
+func (r *Router) Snooze(address, id string, until time.Time) (string, error) {
+	mailbox, err := r.GetMailbox(address)
+	if err != nil {
+		return "", err
+	}
+	msg, err := mailbox.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if err := mailbox.MarkReadOnly(id); err != nil {
+		return "", err
+	}
+	return r.saveScheduled(&Scheduled{Kind: ScheduleSnooze, Due: until, Message: msg, Address: address})
+}
+
+func (r *Router) saveScheduled(s *Scheduled) (string, error) {
+	if r.townRoot == "" {
+		return "", fmt.Errorf("no town root for scheduled mail")
+	}
+	if err := os.MkdirAll(ScheduledDir(r.townRoot), 0755); err != nil {
+		return "", fmt.Errorf("creating scheduled mail dir: %w", err)
+	}
+	s.ID = "sched-" + strings.TrimPrefix(generateID(), "msg-")
+	s.Due = s.Due.UTC()
+	s.CreatedAt = timeNow().UTC()
+	if err := util.AtomicWriteJSON(scheduledPath(r.townRoot, s.ID), s); err != nil {
+		return "", fmt.Errorf("writing scheduled mail: %w", err)
+	}
+	return s.ID, nil
+}
+
+// ScheduledMail returns the town's scheduled and snoozed mail, soonest
+// first.
+func (r *Router) ScheduledMail() ([]Scheduled, error) {
+	paths, err := filepath.Glob(filepath.Join(ScheduledDir(r.townRoot), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var all []Scheduled
+	for _, path := range paths {
+		s, err := readScheduled(path)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, *s)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Due.Before(all[j].Due) })
+	return all, nil
+}
+
+func readScheduled(path string) (*Scheduled, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from the town root
+	if err != nil {
+		return nil, err
+	}
+	var s Scheduled
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing scheduled mail %s: %w", filepath.Base(path), err)
+	}
+	return &s, nil
+}
+
+// CancelScheduled drops scheduled mail without running it. A cancelled
+// snooze leaves its message read.
+func (r *Router) CancelScheduled(id string) error {
+	err := os.Remove(scheduledPath(r.townRoot, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrScheduledNotFound, id)
+	}
+	return err
+}
+
+// RunDue runs the scheduled mail due by now and returns what ran. Due
+// messages are sent and leave the schedule whatever the outcome, since a
+// send that fails is dead-lettered. Due snoozes mark their message unread
+// and notify the recipient; a snooze that can't be woken stays scheduled
+// with its error, to be retried on the next run.
+func (r *Router) RunDue(now time.Time) ([]Scheduled, error) {
+	all, err := r.ScheduledMail()
+	if err != nil {
+		return nil, err
+	}
+	var ran []Scheduled
+	var errs []error
+	for _, s := range all {
+		if s.Due.After(now) {
+			break
+		}
+		path := scheduledPath(r.townRoot, s.ID)
+		switch s.Kind {
+		case ScheduleSend:
+			if err := os.Remove(path); err != nil {
+				errs = append(errs, err)
+				continue // leave it for the next run rather than risk a double send
+			}
+			if err := r.Send(s.Message); err != nil {
+				errs = append(errs, fmt.Errorf("sending %s: %w", s.ID, err))
+			}
+		case ScheduleSnooze:
+			if err := r.wake(&s); err != nil {
+				s.Error = err.Error()
+				if werr := util.AtomicWriteJSON(path, s); werr != nil {
+					err = errors.Join(err, werr)
+				}
+				errs = append(errs, fmt.Errorf("waking %s: %w", s.ID, err))
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			errs = append(errs, fmt.Errorf("scheduled mail %s: unknown kind %q", s.ID, s.Kind))
+			continue
+		}
+		logger.Debug("ran scheduled mail", "id", s.ID, "kind", s.Kind, "to", s.Message.To)
+		ran = append(ran, s)
+	}
+	return ran, errors.Join(errs...)
+}
+
+// wake re-surfaces a snoozed message as new mail.
+func (r *Router) wake(s *Scheduled) error {
+	mailbox, err := r.GetMailbox(s.Address)
+	if err != nil {
+		return err
+	}
+	if err := mailbox.MarkUnreadOnly(s.Message.ID); err != nil {
+		return err
+	}
+	msg := *s.Message
+	msg.To = s.Address
+	if err := r.nudgeRecipient(&msg, fmt.Sprintf("Snoozed mail from %s", msg.From),
+		fmt.Sprintf("⏰ Snoozed mail from %s is back. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)); err != nil {
+		logger.Warn("notify failed", "to", msg.To, "id", msg.ID, "err", err)
+	}
+	return nil
+}
+
+// clockPattern matches a time of day: "9am", "9:30pm", "14:00".
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+
+// ParseWhen parses when scheduled mail is due, relative to now:
+//
+//   - a delay: "2h", "30m", "1h30m", "3d"
+//   - a time of day, the next time it comes round: "9am", "14:30", "noon"
+//   - a day, optionally with a time of day: "tomorrow", "tomorrow 9am",
+//     "today 5pm", "friday", "mon 10:30"
+//   - a date or date and time: "2026-03-01", "2026-03-01 14:00", RFC3339
+//
+// Days without a time mean DefaultWakeHour. Weekdays mean the next one
+// after today. Times are local unless RFC3339 says otherwise.
+func ParseWhen(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	bad := fmt.Errorf("invalid time %q: want a delay like 2h, a time like 9am, a day like \"tomorrow 9am\" or a date", s)
+	if s == "" {
+		return time.Time{}, bad
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	s = strings.ToLower(s)
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02t15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t.Add(DefaultWakeHour * time.Hour), nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(d), nil
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day, clock, _ := strings.Cut(s, " ")
+	if offset, ok := dayOffset(day, now.Weekday()); ok {
+		hour, min := DefaultWakeHour, 0
+		if clock = strings.TrimSpace(clock); clock != "" {
+			var ok bool
+			if hour, min, ok = parseClock(clock); !ok {
+				return time.Time{}, bad
+			}
+		}
+		return time.Date(midnight.Year(), midnight.Month(), midnight.Day()+offset, hour, min, 0, 0, now.Location()), nil
+	}
+	if hour, min, ok := parseClock(s); ok {
+		t := time.Date(midnight.Year(), midnight.Month(), midnight.Day(), hour, min, 0, 0, now.Location())
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Time{}, bad
+}
+
+// dayOffset returns how many days after today day names.
+func dayOffset(day string, today time.Weekday) (int, bool) {
+	switch day {
+	case "today":
+		return 0, true
+	case "tomorrow":
+		return 1, true
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if day == name || day == name[:3] {
+			offset := (int(wd) - int(today) + 7) % 7
+			if offset == 0 {
+				offset = 7
+			}
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses a time of day into its hour and minute.
+func parseClock(s string) (hour, min int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false // a bare number is ambiguous
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		min, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || min > 59 {
+		return 0, 0, false
+	}
+	return hour, min, true
+}
@@ -0,0 +1,123 @@
+package mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestParseWhen(t *testing.T) {
+	// A Wednesday afternoon
+	now := time.Date(2026, 3, 4, 15, 0, 0, 0, time.Local)
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 3, day, hour, min, 0, 0, time.Local) }
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2h", now.Add(2 * time.Hour)},
+		{"1h30m", now.Add(90 * time.Minute)},
+		{"3d", now.AddDate(0, 0, 3)},
+		{"tomorrow", at(5, 9, 0)},
+		{"tomorrow 9am", at(5, 9, 0)},
+		{"Tomorrow 2:30pm", at(5, 14, 30)},
+		{"today 5pm", at(4, 17, 0)},
+		{"9am", at(5, 9, 0)}, // already past today
+		{"16:45", at(4, 16, 45)},
+		{"noon", at(5, 12, 0)},
+		{"friday", at(6, 9, 0)},
+		{"wed 10:00", at(11, 10, 0)}, // next week, not today
+		{"2026-03-20", at(20, 9, 0)},
+		{"2026-03-20 07:15", at(20, 7, 15)},
+		{"2026-03-20T07:15:00Z", time.Date(2026, 3, 20, 7, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseWhen(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseWhen(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseWhen(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "9", "later", "tomorrow teatime", "13pm", "-2h"} {
+		if got, err := ParseWhen(bad, now); err == nil {
+			t.Errorf("ParseWhen(%q) = %v, want error", bad, got)
+		}
+	}
+}
+
+func TestSchedule_SendsWhenDue(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+	now := time.Now()
+
+	id, err := r.Schedule(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Standup"}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if ran, err := r.RunDue(now); err != nil || len(ran) != 0 {
+		t.Fatalf("RunDue before due = %v, %v; want nothing", ran, err)
+	}
+	if n := len(transport.Inbox(townBeads, "mayor/")); n != 0 {
+		t.Fatalf("sent early: mayor has %d messages", n)
+	}
+
+	ran, err := r.RunDue(now.Add(2 * time.Hour))
+	if err != nil || len(ran) != 1 || ran[0].ID != id {
+		t.Fatalf("RunDue = %v, %v; want %s", ran, err, id)
+	}
+	if inbox := transport.Inbox(townBeads, "mayor/"); len(inbox) != 1 || inbox[0].Subject != "Standup" {
+		t.Errorf("mayor inbox = %v, want the scheduled message", inbox)
+	}
+	if left, _ := r.ScheduledMail(); len(left) != 0 {
+		t.Errorf("still scheduled after sending: %v", left)
+	}
+}
+
+func TestSnooze_ResurfacesAsNew(t *testing.T) {
+	r, transport, sessions, townBeads := newTestMemoryRouter(t, session.MayorSessionName())
+	if err := r.Send(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Review gt-1", Priority: PriorityHigh}); err != nil {
+		t.Fatal(err)
+	}
+	msg := transport.Inbox(townBeads, "mayor/")[0]
+	now := time.Now()
+
+	if _, err := r.Snooze("mayor/", msg.ID, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Snooze: %v", err)
+	}
+	if n := len(transport.Inbox(townBeads, "mayor/")); n != 0 {
+		t.Fatalf("mayor has %d unread messages after snoozing, want 0", n)
+	}
+
+	if ran, err := r.RunDue(now.Add(3 * time.Hour)); err != nil || len(ran) != 1 || ran[0].Kind != ScheduleSnooze {
+		t.Fatalf("RunDue = %v, %v; want the snooze", ran, err)
+	}
+	if inbox := transport.Inbox(townBeads, "mayor/"); len(inbox) != 1 || inbox[0].ID != msg.ID {
+		t.Errorf("unread after waking = %v, want the snoozed message", inbox)
+	}
+	nudges := sessions.Nudges()
+	if len(nudges) != 2 || !strings.Contains(nudges[1].Message, "Snoozed mail") || !strings.Contains(nudges[1].Message, "Review gt-1") {
+		t.Errorf("nudges = %+v, want a second one about the snoozed mail", nudges)
+	}
+
+	if _, err := r.Snooze("mayor/", "hq-missing", now); err == nil {
+		t.Error("snoozed a missing message")
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	r, _, _, _ := newTestMemoryRouter(t)
+	id, err := r.Schedule(&Message{From: "gastown/Toast", To: "mayor/", Subject: "Later"}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CancelScheduled(id); err != nil {
+		t.Fatalf("CancelScheduled: %v", err)
+	}
+	if err := r.CancelScheduled(id); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("cancelling twice: %v, want ErrScheduledNotFound", err)
+	}
+}
@@ -0,0 +1,245 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ThreadWindow is how far back DetectThread looks for a conversation to
+// join. Older threads are considered finished; a message with a matching
+// subject after that starts a new one.
+const ThreadWindow = 72 * time.Hour
+
+// subjectPrefixRe matches reply/forward markers and bracketed tags at the
+// start of a subject: "Re:", "RE[2]:", "Fwd:", "FW:", "[gastown]".
+var subjectPrefixRe = regexp.MustCompile(`^(?i)(re|fwd?|fw)(\[\d+\])?:\s*|^\[[^\]]*\]\s*`)
+
+// NormalizeSubject reduces a subject to the form used for thread matching:
+// reply and forward prefixes and leading [tags] are stripped, whitespace is
+// collapsed and case is folded. "Re: [gastown] Status  Check" and
+// "status check" normalize to the same string.
+func NormalizeSubject(subject string) string {
+	s := strings.TrimSpace(subject)
+	for {
+		stripped := strings.TrimSpace(subjectPrefixRe.ReplaceAllString(s, ""))
+		if stripped == s {
+			break
+		}
+		s = stripped
+	}
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// participants returns the normalized identities of everyone on a message.
+func participants(msg *Message) map[string]bool {
+	set := make(map[string]bool, 2+len(msg.CC))
+	for _, addr := range append([]string{msg.From, msg.To}, msg.CC...) {
+		if addr != "" {
+			set[addressToIdentity(addr)] = true
+		}
+	}
+	return set
+}
+
+// MatchThread picks the thread an unthreaded message most likely belongs to.
+// A candidate matches when it has a thread ID, was sent within window before
+// msg, has the same normalized subject, and includes the sender and at least
+// one other participant of msg. The most recent match wins. Returns "" if
+// nothing matches or msg's subject normalizes to nothing.
+func MatchThread(msg *Message, candidates []*Message, window time.Duration) string {
+	subject := NormalizeSubject(msg.Subject)
+	if subject == "" {
+		return ""
+	}
+
+	sent := msg.Timestamp
+	if sent.IsZero() {
+		sent = time.Now()
+	}
+	sender := addressToIdentity(msg.From)
+	wanted := participants(msg)
+
+	var best *Message
+	for _, c := range candidates {
+		if c.ThreadID == "" || c.ID == msg.ID {
+			continue
+		}
+		if c.Timestamp.After(sent) || sent.Sub(c.Timestamp) > window {
+			continue
+		}
+		if NormalizeSubject(c.Subject) != subject {
+			continue
+		}
+		have := participants(c)
+		if !have[sender] {
+			continue
+		}
+		shared := false
+		for p := range wanted {
+			if p != sender && have[p] {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			continue
+		}
+		if best == nil || c.Timestamp.After(best.Timestamp) {
+			best = c
+		}
+	}
+
+	if best == nil {
+		return ""
+	}
+	return best.ThreadID
+}
+
+// DetectThread finds an existing thread for a message sent without one, so
+// a conversation stays together when an agent forgets --reply-to. It looks
+// at mail sent within ThreadWindow before msg, read or unread, in the
+// databases that hold the sender's and recipients' mailboxes, querying each
+// database once. Returns "" if no thread matches.
+func (r *Router) DetectThread(msg *Message) (string, error) {
+	if NormalizeSubject(msg.Subject) == "" {
+		return "", nil
+	}
+
+	sent := msg.Timestamp
+	if sent.IsZero() {
+		sent = time.Now()
+	}
+	since := sent.Add(-ThreadWindow)
+
+	var candidates []*Message
+	var lastErr error
+	for _, beadsDir := range r.resolveBeadsDirs(append([]string{msg.From, msg.To}, msg.CC...)) {
+		beadsMsgs, err := r.transport.List(beadsDir, Query{CreatedAfter: since})
+		if err != nil {
+			lastErr = fmt.Errorf("querying recent mail: %w", err)
+			continue
+		}
+		candidates = append(candidates, toMessages(beadsMsgs)...)
+	}
+
+	if threadID := MatchThread(msg, candidates, ThreadWindow); threadID != "" {
+		return threadID, nil
+	}
+	return "", lastErr
+}
+
+// Thread is a conversation: messages sharing a thread ID, oldest first.
+type Thread struct {
+	ID           string     `json:"id"`
+	Subject      string     `json:"subject"` // subject of the first message
+	Messages     []*Message `json:"messages"`
+	Participants []string   `json:"participants"`
+	Unread       int        `json:"unread"`
+	LastActivity time.Time  `json:"last_activity"`
+}
+
+// GroupThreads groups messages into conversations. Messages with no thread
+// ID join their reply-to message's thread when it is present, and otherwise
+// form a thread of their own keyed by message ID. Threads are returned most
+// recently active first.
+func GroupThreads(messages []*Message) []*Thread {
+	byMsgID := make(map[string]*Message, len(messages))
+	for _, msg := range messages {
+		byMsgID[msg.ID] = msg
+	}
+	threadOf := func(msg *Message) string {
+		seen := make(map[string]bool)
+		for cur := msg; cur != nil && !seen[cur.ID]; cur = byMsgID[cur.ReplyTo] {
+			if cur.ThreadID != "" {
+				return cur.ThreadID
+			}
+			seen[cur.ID] = true
+			if cur.ReplyTo == "" {
+				return cur.ID
+			}
+		}
+		return msg.ID
+	}
+
+	byID := make(map[string]*Thread)
+	var threads []*Thread
+	for _, msg := range messages {
+		id := threadOf(msg)
+		th := byID[id]
+		if th == nil {
+			th = &Thread{ID: id}
+			byID[id] = th
+			threads = append(threads, th)
+		}
+		th.Messages = append(th.Messages, msg)
+	}
+
+	for _, th := range threads {
+		sort.SliceStable(th.Messages, func(i, j int) bool {
+			return th.Messages[i].Timestamp.Before(th.Messages[j].Timestamp)
+		})
+		th.Subject = th.Messages[0].Subject
+		seen := make(map[string]bool)
+		for _, msg := range th.Messages {
+			if !msg.Read {
+				th.Unread++
+			}
+			if msg.Timestamp.After(th.LastActivity) {
+				th.LastActivity = msg.Timestamp
+			}
+			for _, addr := range append([]string{msg.From, msg.To}, msg.CC...) {
+				if addr == "" {
+					continue
+				}
+				if id := addressToIdentity(addr); !seen[id] {
+					seen[id] = true
+					th.Participants = append(th.Participants, id)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].LastActivity.After(threads[j].LastActivity)
+	})
+	return threads
+}
+
+// Threads groups the mailbox's messages into conversations, most recently
+// active first.
+func (m *Mailbox) Threads() ([]*Thread, error) {
+	messages, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	return GroupThreads(messages), nil
+}
+
+// Reply sends msg as a reply to original. Thread, reply-to and type are
+// always set from original; recipient and subject default to original's
+// sender and "Re: <subject>" when msg leaves them empty. An original with
+// no thread ID starts a new thread.
+func (r *Router) Reply(original, msg *Message) error {
+	msg.ReplyTo = original.ID
+	msg.ThreadID = original.ThreadID
+	if msg.ThreadID == "" {
+		msg.ThreadID = generateThreadID()
+	}
+	msg.Type = TypeReply
+	if msg.To == "" {
+		msg.To = original.From
+	}
+	if msg.Subject == "" {
+		msg.Subject = original.Subject
+		if !strings.HasPrefix(msg.Subject, "Re: ") {
+			msg.Subject = "Re: " + msg.Subject
+		}
+	}
+	if msg.Priority == "" {
+		msg.Priority = PriorityNormal
+	}
+	return r.Send(msg)
+}
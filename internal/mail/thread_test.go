@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{"Status check", "status check"},
+		{"Re: Status check", "status check"},
+		{"RE: re: Fwd: Status check", "status check"},
+		{"Re[2]: Status check", "status check"},
+		{"[gastown] Re:  Status   check ", "status check"},
+		{"Regarding the merge", "regarding the merge"},
+		{"Re:", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeSubject(tt.subject); got != tt.want {
+			t.Errorf("NormalizeSubject(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestMatchThread(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	msg := &Message{
+		From:      "gastown/Toast",
+		To:        "mayor/",
+		Subject:   "Re: Build broken",
+		Timestamp: now,
+	}
+
+	tests := []struct {
+		name       string
+		candidates []*Message
+		want       string
+	}{
+		{
+			name: "reply from recipient",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/polecats/Toast", Subject: "Build broken", ThreadID: "thread-a", Timestamp: now.Add(-time.Hour)},
+			},
+			want: "thread-a",
+		},
+		{
+			name: "most recent match wins",
+			candidates: []*Message{
+				{ID: "m1", From: "gastown/Toast", To: "mayor", Subject: "build broken", ThreadID: "thread-old", Timestamp: now.Add(-48 * time.Hour)},
+				{ID: "m2", From: "mayor/", To: "gastown/Toast", Subject: "Build broken", ThreadID: "thread-new", Timestamp: now.Add(-2 * time.Hour)},
+			},
+			want: "thread-new",
+		},
+		{
+			name: "sender only on cc",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/witness", CC: []string{"gastown/Toast"}, Subject: "Build broken", ThreadID: "thread-cc", Timestamp: now.Add(-time.Hour)},
+			},
+			want: "thread-cc",
+		},
+		{
+			name: "outside window",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/Toast", Subject: "Build broken", ThreadID: "thread-a", Timestamp: now.Add(-ThreadWindow - time.Minute)},
+			},
+		},
+		{
+			name: "different subject",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/Toast", Subject: "Build fixed", ThreadID: "thread-a", Timestamp: now.Add(-time.Hour)},
+			},
+		},
+		{
+			name: "no participant overlap",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/Nux", Subject: "Build broken", ThreadID: "thread-a", Timestamp: now.Add(-time.Hour)},
+			},
+		},
+		{
+			name: "sender not involved",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "deacon/", Subject: "Build broken", ThreadID: "thread-a", Timestamp: now.Add(-time.Hour)},
+			},
+		},
+		{
+			name: "candidate without thread",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/Toast", Subject: "Build broken", Timestamp: now.Add(-time.Hour)},
+			},
+		},
+		{
+			name: "sent after message",
+			candidates: []*Message{
+				{ID: "m1", From: "mayor/", To: "gastown/Toast", Subject: "Build broken", ThreadID: "thread-a", Timestamp: now.Add(time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchThread(msg, tt.candidates, ThreadWindow); got != tt.want {
+				t.Errorf("MatchThread() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchThread_EmptySubject(t *testing.T) {
+	msg := &Message{From: "gastown/Toast", To: "mayor/", Subject: "Re:", Timestamp: time.Now()}
+	candidates := []*Message{
+		{ID: "m1", From: "mayor/", To: "gastown/Toast", Subject: "", ThreadID: "thread-a", Timestamp: time.Now().Add(-time.Minute)},
+	}
+	if got := MatchThread(msg, candidates, ThreadWindow); got != "" {
+		t.Errorf("MatchThread() with empty subject = %q, want \"\"", got)
+	}
+}
+
+func TestGroupThreads(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	msgs := []*Message{
+		{ID: "m3", From: "mayor/", To: "gastown/Toast", Subject: "Re: Build broken", ThreadID: "thread-a", ReplyTo: "m1", Timestamp: now.Add(-time.Hour), Read: true},
+		{ID: "m1", From: "gastown/Toast", To: "mayor/", Subject: "Build broken", ThreadID: "thread-a", Timestamp: now.Add(-3 * time.Hour)},
+		{ID: "m2", From: "gastown/Nux", To: "mayor/", Subject: "Done", Timestamp: now.Add(-2 * time.Hour)},
+		{ID: "m4", From: "mayor/", To: "gastown/Nux", Subject: "Re: Done", ReplyTo: "m2", Timestamp: now.Add(-30 * time.Minute), Read: true},
+		{ID: "m5", From: "deacon/", To: "mayor/", Subject: "Patrol", Timestamp: now.Add(-5 * time.Hour)},
+	}
+
+	threads := GroupThreads(msgs)
+	if len(threads) != 3 {
+		t.Fatalf("got %d threads, want 3", len(threads))
+	}
+
+	// Most recently active first.
+	if threads[0].ID != "m2" || threads[1].ID != "thread-a" || threads[2].ID != "m5" {
+		t.Errorf("thread order = %s, %s, %s; want m2, thread-a, m5", threads[0].ID, threads[1].ID, threads[2].ID)
+	}
+
+	a := threads[1]
+	if len(a.Messages) != 2 || a.Messages[0].ID != "m1" || a.Messages[1].ID != "m3" {
+		t.Errorf("thread-a messages not oldest first: %v", a.Messages)
+	}
+	if a.Subject != "Build broken" || a.Unread != 1 {
+		t.Errorf("thread-a subject/unread = %q/%d, want Build broken/1", a.Subject, a.Unread)
+	}
+	if len(a.Participants) != 2 || a.Participants[0] != "gastown/Toast" || a.Participants[1] != "mayor/" {
+		t.Errorf("thread-a participants = %v", a.Participants)
+	}
+	if !a.LastActivity.Equal(now.Add(-time.Hour)) {
+		t.Errorf("thread-a last activity = %v", a.LastActivity)
+	}
+
+	if len(threads[0].Messages) != 2 {
+		t.Errorf("unthreaded reply did not join its original: %v", threads[0].Messages)
+	}
+}
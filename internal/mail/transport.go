@@ -0,0 +1,235 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Transport stores and queries the message beads a Router delivers. The
+// default transport runs the bd CLI; FileTransport keeps messages in a JSONL
+// file so towns without beads installed still get mail, and MemoryTransport
+// keeps them in memory so code that sends mail can be tested without either.
+// Towns choose between beads and file in config/messaging.json (see
+// TransportForTown).
+//
+// Every method takes the beads directory of the database to act on, as
+// resolved by the Router from the recipient's address.
+type Transport interface {
+	// Create stores a new message and returns its ID. The message's
+	// Title, Description, Assignee, Priority, Labels and Wisp fields are
+	// used; actor is recorded as its creator.
+	Create(beadsDir string, bead *BeadsMessage, actor string) (string, error)
+
+	// List returns the messages matching q, oldest first.
+	List(beadsDir string, q Query) ([]BeadsMessage, error)
+
+	// Get returns a single message, or ErrMessageNotFound.
+	Get(beadsDir, id string) (*BeadsMessage, error)
+
+	// SetPriority changes a message's beads priority (0=urgent .. 4=backlog).
+	SetPriority(beadsDir, id string, priority int) error
+
+	// AddLabels adds labels to a message.
+	AddLabels(beadsDir, id string, labels ...string) error
+
+	// RemoveLabels removes labels from a message. Removing a label the
+	// message doesn't carry is not an error.
+	RemoveLabels(beadsDir, id string, labels ...string) error
+
+	// Close closes a message, recording reason.
+	Close(beadsDir, id, reason string) error
+
+	// Reopen reopens a closed message.
+	Reopen(beadsDir, id string) error
+
+	// Delete removes a message for good, or returns ErrMessageNotFound.
+	Delete(beadsDir, id string) error
+}
+
+// Query selects messages from a Transport. Empty fields match everything.
+type Query struct {
+	// Assignee selects messages addressed to this identity.
+	Assignee string
+
+	// Label selects messages carrying this label (e.g. "thread:abc").
+	Label string
+
+	// DescContains selects messages whose body contains this string.
+	DescContains string
+
+	// Status selects "open" or "closed" messages; empty selects both.
+	Status string
+
+	// CreatedAfter selects messages created after this time; zero selects
+	// all.
+	CreatedAfter time.Time
+}
+
+// Sessions is how a Router reaches live agent sessions to nudge them about
+// new mail. *tmux.Tmux implements it.
+type Sessions interface {
+	HasSession(name string) (bool, error)
+	NudgeSession(session, message string) error
+}
+
+// bdTransport is the default Transport, backed by the bd CLI.
+type bdTransport struct{}
+
+func (bdTransport) Create(beadsDir string, bead *BeadsMessage, actor string) (string, error) {
+	stdout, err := runBdCommand(createArgs(bead, actor), filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// createArgs builds the bd create command for a message.
+func createArgs(bead *BeadsMessage, actor string) []string {
+	// bd create <subject> --type=message --assignee=<recipient> -d <body>
+	args := []string{"create", bead.Title,
+		"--type", "message",
+		"--assignee", bead.Assignee,
+		"-d", bead.Description,
+		"--priority", fmt.Sprintf("%d", bead.Priority),
+	}
+	if len(bead.Labels) > 0 {
+		args = append(args, "--labels", strings.Join(bead.Labels, ","))
+	}
+	if actor != "" {
+		args = append(args, "--actor", actor)
+	}
+	// Ephemeral messages are stored in the same DB, filtered from JSONL export
+	if bead.Wisp {
+		args = append(args, "--ephemeral")
+	}
+	// Only output the ID, which Create returns (as 'gt handoff' does)
+	return append(args, "--silent")
+}
+
+func (bdTransport) List(beadsDir string, q Query) ([]BeadsMessage, error) {
+	stdout, err := runBdCommand(listArgs(q), filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var beadsMsgs []BeadsMessage
+	if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+		if len(stdout) == 0 || string(stdout) == "null" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return beadsMsgs, nil
+}
+
+// listArgs builds the bd list command for a query, oldest first.
+func listArgs(q Query) []string {
+	args := []string{"list", "--type", "message", "--json", "--limit=0", "--sort=created", "--asc"}
+	if q.Assignee != "" {
+		args = append(args, "--assignee", q.Assignee)
+	}
+	// bd list filters on --label; --labels is create's flag for setting them
+	if q.Label != "" {
+		args = append(args, "--label="+q.Label)
+	}
+	if q.DescContains != "" {
+		args = append(args, "--desc-contains="+q.DescContains)
+	}
+	if q.Status != "" {
+		args = append(args, "--status", q.Status)
+	} else {
+		args = append(args, "--all")
+	}
+	if !q.CreatedAfter.IsZero() {
+		args = append(args, "--created-after="+q.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	return args
+}
+
+func (bdTransport) Get(beadsDir, id string) (*BeadsMessage, error) {
+	stdout, err := runBdCommand([]string{"show", id, "--json"}, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	// bd show --json returns an array
+	var bms []BeadsMessage
+	if err := json.Unmarshal(stdout, &bms); err != nil {
+		return nil, err
+	}
+	if len(bms) == 0 {
+		return nil, ErrMessageNotFound
+	}
+	return &bms[0], nil
+}
+
+func (bdTransport) SetPriority(beadsDir, id string, priority int) error {
+	_, err := runBdCommand([]string{"update", id, fmt.Sprintf("--priority=%d", priority)}, filepath.Dir(beadsDir), beadsDir)
+	return err
+}
+
+func (bdTransport) AddLabels(beadsDir, id string, labels ...string) error {
+	_, err := runBdCommand(labelArgs(id, labels, nil), filepath.Dir(beadsDir), beadsDir)
+	return notFound(err)
+}
+
+func (bdTransport) RemoveLabels(beadsDir, id string, labels ...string) error {
+	_, err := runBdCommand(labelArgs(id, nil, labels), filepath.Dir(beadsDir), beadsDir)
+	if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("does not have label") {
+		return nil
+	}
+	return notFound(err)
+}
+
+// labelArgs returns the bd arguments adding and removing labels on a
+// message. bd label add/remove take one label a call; bd update takes any
+// number, so a message's labels change in a single round-trip.
+func labelArgs(id string, add, remove []string) []string {
+	args := []string{"update", id}
+	for _, label := range add {
+		args = append(args, "--add-label="+label)
+	}
+	for _, label := range remove {
+		args = append(args, "--remove-label="+label)
+	}
+	return args
+}
+
+func (bdTransport) Close(beadsDir, id, reason string) error {
+	args := []string{"close", id}
+	if reason != "" {
+		args = append(args, "--reason="+reason)
+	}
+	_, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	return err
+}
+
+func (bdTransport) Reopen(beadsDir, id string) error {
+	_, err := runBdCommand([]string{"reopen", id}, filepath.Dir(beadsDir), beadsDir)
+	return notFound(err)
+}
+
+func (bdTransport) Delete(beadsDir, id string) error {
+	_, err := runBdCommand([]string{"delete", id, "--hard", "--force"}, filepath.Dir(beadsDir), beadsDir)
+	return notFound(err)
+}
+
+// notFound maps a bd "not found" error to ErrMessageNotFound.
+func notFound(err error) error {
+	if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// toMessages converts transport results to Gas Town messages.
+func toMessages(beadsMsgs []BeadsMessage) []*Message {
+	messages := make([]*Message, 0, len(beadsMsgs))
+	for i := range beadsMsgs {
+		messages = append(messages, beadsMsgs[i].ToMessage())
+	}
+	return messages
+}
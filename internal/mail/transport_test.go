@@ -0,0 +1,85 @@
+package mail
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateArgs(t *testing.T) {
+	got := createArgs(&BeadsMessage{
+		Title:       "Hello",
+		Description: "Body",
+		Assignee:    "mayor/",
+		Priority:    1,
+		Labels:      []string{"from:gastown/Toast", "thread:t1"},
+		Wisp:        true,
+	}, "gastown/Toast")
+	want := []string{"create", "Hello",
+		"--type", "message",
+		"--assignee", "mayor/",
+		"-d", "Body",
+		"--priority", "1",
+		"--labels", "from:gastown/Toast,thread:t1",
+		"--actor", "gastown/Toast",
+		"--ephemeral",
+		"--silent",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("createArgs = %q\nwant %q", got, want)
+	}
+}
+
+func TestListArgs(t *testing.T) {
+	base := []string{"list", "--type", "message", "--json", "--limit=0", "--sort=created", "--asc"}
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"every status", Query{Label: "announce:alerts"}, append(append([]string{}, base...), "--label=announce:alerts", "--all")},
+		{"open only", Query{Assignee: "mayor/", Status: "open"}, append(append([]string{}, base...), "--assignee", "mayor/", "--status", "open")},
+		{"created after", Query{CreatedAfter: time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600))},
+			append(append([]string{}, base...), "--all", "--created-after=2026-01-02T08:04:05Z")},
+	}
+	for _, tt := range tests {
+		if got := listArgs(tt.q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: listArgs = %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLabelArgs(t *testing.T) {
+	got := labelArgs("hq-1", []string{"read", "read-by:mayor"}, []string{"unread"})
+	want := []string{"update", "hq-1", "--add-label=read", "--add-label=read-by:mayor", "--remove-label=unread"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("labelArgs = %q\nwant %q", got, want)
+	}
+}
+
+func TestPruneAnnounce_AllStatuses(t *testing.T) {
+	r, transport, _, townBeads := newTestMemoryRouter(t)
+	var ids []string
+	for _, subject := range []string{"one", "two", "three"} {
+		id, err := transport.Create(townBeads, &BeadsMessage{Title: subject, Assignee: "announce:alerts", Labels: []string{"announce:alerts"}}, "mayor/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := transport.Close(townBeads, ids[0], "read"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closed messages count toward retention, as they always have
+	if err := r.pruneAnnounce("alerts", 2); err != nil {
+		t.Fatalf("pruneAnnounce: %v", err)
+	}
+	open, err := transport.List(townBeads, Query{Label: "announce:alerts", Status: "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].ID != ids[2] {
+		t.Errorf("open after pruning = %+v, want only the newest", open)
+	}
+}
@@ -81,6 +81,19 @@ type Message struct {
 	// Read indicates if the message has been read (closed in beads).
 	Read bool `json:"read"`
 
+	// ReadBy is the session (or identity, outside an agent session) that
+	// marked the message read. Read state lives with the message rather than
+	// the session, so a successor can see what its predecessor processed.
+	ReadBy string `json:"read_by,omitempty"`
+
+	// ReadAt is when the message was marked read.
+	ReadAt *time.Time `json:"read_at,omitempty"`
+
+	// RequestReceipt asks for an acknowledgment: when the recipient first
+	// marks the message read, a receipt is mailed back to the sender (see
+	// Router.MarkRead).
+	RequestReceipt bool `json:"request_receipt,omitempty"`
+
 	// Priority is the message priority.
 	Priority Priority `json:"priority"`
 
@@ -108,6 +121,11 @@ type Message struct {
 	// CC'd recipients see the message in their inbox but are not the primary recipient.
 	CC []string `json:"cc,omitempty"`
 
+	// Broadcast is the group address a fanned-out message was sent to (e.g.
+	// "all-polecats", "gastown/*" or "list:oncall"). Every copy of a
+	// broadcast shares one ThreadID.
+	Broadcast string `json:"broadcast,omitempty"`
+
 	// Queue is the queue name for queue-routed messages.
 	// Mutually exclusive with To and Channel - a message is either direct, queued, or broadcast.
 	Queue string `json:"queue,omitempty"`
@@ -158,6 +176,32 @@ func NewReplyMessage(from, to, subject, body string, original *Message) *Message
 	}
 }
 
+// NewReceiptMessage creates the acknowledgment mailed back to the sender of
+// a message that requested one. It joins the original's thread.
+func NewReceiptMessage(original *Message) *Message {
+	readAt := time.Now()
+	if original.ReadAt != nil {
+		readAt = *original.ReadAt
+	}
+	body := fmt.Sprintf("%s read your message %s at %s.", original.To, original.ID, readAt.Format(time.RFC3339))
+	if original.ReadBy != "" {
+		body = fmt.Sprintf("%s read your message %s at %s (session %s).", original.To, original.ID, readAt.Format(time.RFC3339), original.ReadBy)
+	}
+	return &Message{
+		ID:        generateID(),
+		From:      original.To,
+		To:        original.From,
+		Subject:   "Read: " + original.Subject,
+		Body:      body,
+		Timestamp: time.Now(),
+		Priority:  PriorityLow,
+		Type:      TypeNotification,
+		ThreadID:  original.ThreadID,
+		ReplyTo:   original.ID,
+		Wisp:      true,
+	}
+}
+
 // NewQueueMessage creates a message destined for a queue.
 // Queue messages have no direct recipient - they are claimed by eligible agents.
 func NewQueueMessage(from, queue, subject, body string) *Message {
@@ -276,7 +320,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, broadcast:X, request-receipt, queue:X, channel:X, claimed-by:X, claimed-at:X, read-by:X, read-at:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
@@ -286,10 +330,13 @@ type BeadsMessage struct {
 	replyTo   string
 	msgType   string
 	cc        []string   // CC recipients
+	broadcast string     // Group address a fanned-out copy was sent to
 	queue     string     // Queue name (for queue messages)
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+	readBy    string     // Session that marked the message read
+	readAt    *time.Time // When the message was marked read
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -305,6 +352,8 @@ func (bm *BeadsMessage) ParseLabels() {
 			bm.msgType = strings.TrimPrefix(label, "msg-type:")
 		} else if strings.HasPrefix(label, "cc:") {
 			bm.cc = append(bm.cc, strings.TrimPrefix(label, "cc:"))
+		} else if strings.HasPrefix(label, "broadcast:") {
+			bm.broadcast = strings.TrimPrefix(label, "broadcast:")
 		} else if strings.HasPrefix(label, "queue:") {
 			bm.queue = strings.TrimPrefix(label, "queue:")
 		} else if strings.HasPrefix(label, "channel:") {
@@ -316,6 +365,13 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, "read-by:") {
+			bm.readBy = strings.TrimPrefix(label, "read-by:")
+		} else if strings.HasPrefix(label, "read-at:") {
+			ts := strings.TrimPrefix(label, "read-at:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				bm.readAt = &t
+			}
 		}
 	}
 }
@@ -374,16 +430,21 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Body:      bm.Description,
 		Timestamp: bm.CreatedAt,
 		Read:      bm.Status == "closed" || bm.HasLabel("read"),
+		ReadBy:    bm.readBy,
+		ReadAt:    bm.readAt,
 		Priority:  priority,
 		Type:      msgType,
 		ThreadID:  bm.threadID,
 		ReplyTo:   bm.replyTo,
 		Wisp:      bm.Wisp,
 		CC:        ccAddrs,
+		Broadcast: bm.broadcast,
 		Queue:     bm.queue,
 		Channel:   bm.channel,
 		ClaimedBy: bm.claimedBy,
 		ClaimedAt: bm.claimedAt,
+
+		RequestReceipt: bm.HasLabel("request-receipt"),
 	}
 }
 
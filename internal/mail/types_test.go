@@ -281,6 +281,29 @@ func TestBeadsMessageToMessageWithReplyTo(t *testing.T) {
 	}
 }
 
+func TestBeadsMessageToMessageWithReadReceipt(t *testing.T) {
+	bm := BeadsMessage{
+		ID:        "hq-read",
+		Status:    "open",
+		Assignee:  "gastown/Toast",
+		Labels:    []string{"from:mayor/", "read", "read-by:session-abc", "read-at:2026-01-02T03:04:05Z"},
+		CreatedAt: time.Now(),
+	}
+
+	msg := bm.ToMessage()
+
+	if !msg.Read {
+		t.Error("Read = false, want true")
+	}
+	if msg.ReadBy != "session-abc" {
+		t.Errorf("ReadBy = %q, want 'session-abc'", msg.ReadBy)
+	}
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if msg.ReadAt == nil || !msg.ReadAt.Equal(want) {
+		t.Errorf("ReadAt = %v, want %v", msg.ReadAt, want)
+	}
+}
+
 func TestBeadsMessageToMessagePriorities(t *testing.T) {
 	tests := []struct {
 		priority int
@@ -3,13 +3,11 @@ package mayor
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/steveyegge/gastown/internal/claude"
-	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -20,6 +18,9 @@ var (
 	ErrAlreadyRunning = errors.New("mayor already running")
 )
 
+// logger records session starts and stops.
+var logger = gtlog.For("mayor")
+
 // Manager handles mayor lifecycle operations.
 type Manager struct {
 	townRoot string
@@ -51,78 +52,48 @@ func (m *Manager) mayorDir() string {
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	mux := tmux.NewMultiplexer(m.townRoot)
+	t, isTmux := mux.(*tmux.Tmux)
 	sessionID := m.SessionName()
 
 	// Check if session already exists
-	running, _ := t.HasSession(sessionID)
+	running, _ := mux.HasSession(sessionID)
 	if running {
-		// Session exists - check if Claude is actually running (healthy vs zombie)
-		if t.IsClaudeRunning(sessionID) {
+		// Session exists - check if Claude is actually running (healthy vs zombie).
+		// Only tmux can inspect the pane, so other multiplexers trust the session.
+		if !isTmux || t.IsClaudeRunning(sessionID) {
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
+		logger.Warn("killing zombie session", "session", sessionID)
 		if err := t.KillSession(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
 
-	// Ensure mayor directory exists (for Claude settings)
-	mayorDir := m.mayorDir()
-	if err := os.MkdirAll(mayorDir, 0755); err != nil {
-		return fmt.Errorf("creating mayor directory: %w", err)
-	}
-
-	// Ensure Claude settings exist
-	if err := claude.EnsureSettingsForRole(mayorDir, "mayor"); err != nil {
-		return fmt.Errorf("ensuring Claude settings: %w", err)
-	}
-
-	// Build startup beacon with explicit instructions (matches gt handoff behavior)
-	// This ensures the agent has clear context immediately, not after nudges arrive
-	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{
-		Recipient: "mayor",
-		Sender:    "human",
-		Topic:     "cold-start",
-	})
-
-	// Build startup command WITH the beacon prompt - the startup hook handles 'gt prime' automatically
-	// Export GT_ROLE and BD_ACTOR in the command since tmux SetEnvironment only affects new panes
-	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("mayor", "", m.townRoot, "", beacon, agentOverride)
-	if err != nil {
-		return fmt.Errorf("building startup command: %w", err)
-	}
-
-	// Create session in townRoot (not mayorDir) to match gt handoff behavior
-	// This ensures Mayor works from the town root where all tools work correctly
-	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, m.townRoot, startupCmd); err != nil {
-		return fmt.Errorf("creating tmux session: %w", err)
+	// Create the session in townRoot (not mayorDir) to match gt handoff behavior.
+	// This ensures Mayor works from the town root where all tools work correctly.
+	// The startup beacon goes in as the first prompt (matches gt handoff behavior),
+	// so the agent has clear context immediately, not after nudges arrive.
+	if err := session.RunStartup(session.StartupSpec{
+		Role:          "mayor",
+		TownRoot:      m.townRoot,
+		Dir:           m.mayorDir(),
+		WorkDir:       m.townRoot,
+		Session:       sessionID,
+		AgentOverride: agentOverride,
+		Theme:         tmux.MayorTheme(),
+		DisplayName:   "Mayor",
+		Focus:         "coordinator",
+		Nudge: session.StartupNudgeConfig{
+			Recipient: "mayor",
+			Sender:    "human",
+			Topic:     "cold-start",
+		},
+	}); err != nil {
+		return err
 	}
-
-	// Set environment variables (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:     "mayor",
-		TownRoot: m.townRoot,
-	})
-	for k, v := range envVars {
-		_ = t.SetEnvironment(sessionID, k, v)
-	}
-
-	// Apply Mayor theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.MayorTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Mayor", "coordinator")
-
-	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Kill the zombie session before returning error
-		_ = t.KillSessionWithProcesses(sessionID)
-		return fmt.Errorf("waiting for mayor to start: %w", err)
-	}
-
-	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+	logger.Info("session started", "session", sessionID, "agent", agentOverride)
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
@@ -134,11 +105,11 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the mayor session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	mux := tmux.NewMultiplexer(m.townRoot)
 	sessionID := m.SessionName()
 
 	// Check if session exists
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
@@ -147,29 +118,32 @@ func (m *Manager) Stop() error {
 	}
 
 	// Try graceful shutdown first (best-effort interrupt)
-	_ = t.SendKeysRaw(sessionID, "C-c")
-	time.Sleep(100 * time.Millisecond)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		_ = t.SendKeysRaw(sessionID, "C-c")
+		time.Sleep(100 * time.Millisecond)
+	}
 
 	// Kill the session
-	if err := t.KillSession(sessionID); err != nil {
+	if err := mux.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	logger.Info("session stopped", "session", sessionID)
 
 	return nil
 }
 
 // IsRunning checks if the mayor session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	return t.HasSession(m.SessionName())
+	return tmux.NewMultiplexer(m.townRoot).HasSession(m.SessionName())
 }
 
 // Status returns information about the mayor session.
+// Multiplexers other than tmux report only the session name.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	mux := tmux.NewMultiplexer(m.townRoot)
 	sessionID := m.SessionName()
 
-	running, err := t.HasSession(sessionID)
+	running, err := mux.HasSession(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("checking session: %w", err)
 	}
@@ -177,5 +151,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		return t.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID}, nil
 }
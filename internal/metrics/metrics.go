@@ -0,0 +1,228 @@
+// Package metrics exposes town health in the Prometheus text exposition
+// format, so towns can be monitored with standard tooling.
+//
+// Metrics are computed from the town's state on every scrape: counters come
+// from the events log, gauges from tmux, keepalive files and rig health.
+// Counters drop when the events log is pruned or rotated, which Prometheus
+// treats as a counter reset.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+// Metric types.
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+)
+
+// Sample is one labelled value of a metric.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Family is a named metric and its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Sessions lists tmux sessions. *tmux.Tmux implements it.
+type Sessions interface {
+	ListSessions() ([]string, error)
+}
+
+// Collector computes a town's metrics.
+type Collector struct {
+	TownRoot string
+	Sessions Sessions // nil omits gt_sessions_running
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Collect returns the town's metric families, sorted by name.
+func (c *Collector) Collect() ([]Family, error) {
+	now := time.Now()
+	if c.Now != nil {
+		now = c.Now()
+	}
+
+	records, err := events.ReadAll(c.TownRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]float64)
+	var mail, merged, mergeFailed float64
+	for _, r := range records {
+		byType[r.Type]++
+		switch r.Type {
+		case events.TypeMail:
+			mail++
+		case events.TypeMerged:
+			merged++
+		case events.TypeMergeFailed:
+			mergeFailed++
+		}
+	}
+
+	fams := []Family{
+		{
+			Name:    "gt_events_total",
+			Help:    "Events in the town's events log, by type.",
+			Type:    TypeCounter,
+			Samples: labelled("type", byType),
+		},
+		{
+			Name:    "gt_mail_sent_total",
+			Help:    "Mail messages sent.",
+			Type:    TypeCounter,
+			Samples: []Sample{{Value: mail}},
+		},
+		{
+			Name: "gt_merges_total",
+			Help: "Merge queue outcomes, by result.",
+			Type: TypeCounter,
+			Samples: []Sample{
+				{Labels: map[string]string{"result": "failed"}, Value: mergeFailed},
+				{Labels: map[string]string{"result": "succeeded"}, Value: merged},
+			},
+		},
+	}
+
+	if c.Sessions != nil {
+		sessions, err := c.Sessions.ListSessions()
+		if err != nil {
+			return nil, fmt.Errorf("listing sessions: %w", err)
+		}
+		running := 0
+		for _, s := range sessions {
+			if strings.HasPrefix(s, "gt-") || strings.HasPrefix(s, "hq-") {
+				running++
+			}
+		}
+		fams = append(fams, Family{
+			Name:    "gt_sessions_running",
+			Help:    "Gas Town tmux sessions running.",
+			Type:    TypeGauge,
+			Samples: []Sample{{Value: float64(running)}},
+		})
+	}
+
+	fams = append(fams, Family{
+		Name:    "gt_keepalive_age_seconds",
+		Help:    "Seconds since each agent workspace last ran a gt command.",
+		Type:    TypeGauge,
+		Samples: labelled("agent", c.keepaliveAges(now)),
+	})
+
+	scores := make(map[string]float64)
+	for rig, s := range health.Compute(records, now) {
+		scores[rig] = float64(s.Score)
+	}
+	fams = append(fams, Family{
+		Name:    "gt_rig_health_score",
+		Help:    "Rig health score from 0 (critical) to 100.",
+		Type:    TypeGauge,
+		Samples: labelled("rig", scores),
+	})
+
+	sort.Slice(fams, func(i, j int) bool { return fams[i].Name < fams[j].Name })
+	return fams, nil
+}
+
+// keepaliveAges returns the keepalive age of every agent workspace, keyed by
+// its path relative to the town root.
+func (c *Collector) keepaliveAges(now time.Time) map[string]float64 {
+	ages := make(map[string]float64)
+	for _, workspace := range keepalive.Workspaces(c.TownRoot) {
+		state := keepalive.Read(workspace)
+		if state == nil {
+			continue
+		}
+		agent, err := filepath.Rel(c.TownRoot, workspace)
+		if err != nil {
+			continue
+		}
+		ages[filepath.ToSlash(agent)] = now.Sub(state.Timestamp).Seconds()
+	}
+	return ages
+}
+
+// labelled turns a map into samples with one label, sorted by label value.
+func labelled(label string, values map[string]float64) []Sample {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	samples := make([]Sample, 0, len(keys))
+	for _, k := range keys {
+		samples = append(samples, Sample{Labels: map[string]string{label: k}, Value: values[k]})
+	}
+	return samples
+}
+
+// Write writes metric families in the Prometheus text exposition format.
+func Write(w io.Writer, fams []Family) error {
+	var b strings.Builder
+	for _, f := range fams {
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.Name, f.Help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, s := range f.Samples {
+			b.WriteString(f.Name)
+			if len(s.Labels) > 0 {
+				names := make([]string, 0, len(s.Labels))
+				for name := range s.Labels {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				b.WriteByte('{')
+				for i, name := range names {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", name, labelEscaper.Replace(s.Labels[name]))
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(s.Value, 'g', -1, 64))
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelEscaper escapes label values as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Handler serves the collector's metrics on every request.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fams, err := c.Collect()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = Write(w, fams)
+	})
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+type fakeSessions []string
+
+func (f fakeSessions) ListSessions() ([]string, error) { return f, nil }
+
+func TestCollect(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	ts := now.Add(-time.Hour).UTC().Format(time.RFC3339)
+	for _, ev := range []events.Event{
+		{Timestamp: ts, Type: events.TypeMail, Actor: "mayor", Payload: events.MailPayload("gastown/witness", "hi")},
+		{Timestamp: ts, Type: events.TypeMail, Actor: "mayor", Payload: events.MailPayload("gastown/witness", "again")},
+		{Timestamp: ts, Type: events.TypeMerged, Actor: "gastown/refinery", Payload: map[string]interface{}{"rig": "gastown"}},
+		{Timestamp: ts, Type: events.TypeMergeFailed, Actor: "gastown/refinery", Payload: map[string]interface{}{"rig": "gastown"}},
+	} {
+		if err := events.Append(town, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keepalive.TouchInWorkspace(town+"/gastown/polecats/nux", "gt prime")
+
+	c := &Collector{
+		TownRoot: town,
+		Sessions: fakeSessions{"gt-gastown-nux", "hq-mayor", "scratch"},
+		Now:      func() time.Time { return now },
+	}
+	fams, err := c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Write(&b, fams); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE gt_events_total counter\n",
+		`gt_events_total{type="mail"} 2` + "\n",
+		"gt_mail_sent_total 2\n",
+		`gt_merges_total{result="failed"} 1` + "\n",
+		`gt_merges_total{result="succeeded"} 1` + "\n",
+		"gt_sessions_running 2\n",
+		`gt_keepalive_age_seconds{agent="gastown/polecats/nux"}`,
+		`gt_rig_health_score{rig="gastown"}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteEscapesLabels(t *testing.T) {
+	var b strings.Builder
+	fams := []Family{{
+		Name:    "gt_test",
+		Help:    "Test.",
+		Type:    TypeGauge,
+		Samples: []Sample{{Labels: map[string]string{"agent": "a\"b\\c"}, Value: 1.5}},
+	}}
+	if err := Write(&b, fams); err != nil {
+		t.Fatal(err)
+	}
+	if want := `gt_test{agent="a\"b\\c"} 1.5`; !strings.Contains(b.String(), want) {
+		t.Errorf("got %q, want line %q", b.String(), want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	c := &Collector{TownRoot: t.TempDir()}
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != 200 {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "gt_mail_sent_total 0") {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+}
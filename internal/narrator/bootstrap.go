@@ -0,0 +1,422 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// DefaultPrologueCommits caps the commits of each rig a prologue is told
+// from, keeping the newest.
+const DefaultPrologueCommits = 200
+
+var (
+	// ErrStoryBegun indicates a prologue was asked of a town whose story
+	// already has chapters.
+	ErrStoryBegun = errors.New("the story has already begun; a prologue comes before the first chapter")
+
+	// ErrNoHistory indicates the rigs had no git history to tell.
+	ErrNoHistory = errors.New("no git history to narrate")
+)
+
+// PrologueOptions selects the git history a prologue is told from and how.
+type PrologueOptions struct {
+	Rigs       []string  // "" or empty for every rig
+	Since      time.Time // zero for the whole history
+	MaxCommits int       // per rig; 0 uses DefaultPrologueCommits
+	Style      string    // "" uses DefaultStyle
+	Agent      string    // overrides role_agents["narrator"] and the default agent
+	Output     string    // chapter path; "" writes where chapters go
+}
+
+// ProloguePrompt is a prologue request ready to hand to an agent.
+type ProloguePrompt struct {
+	Prompt
+	Rigs       []string
+	Commits    int
+	Since      time.Time // oldest commit told
+	Storylines []*Thread // retroactive storylines found in the history
+}
+
+// rigHistory is one rig's git history as a prologue tells it.
+type rigHistory struct {
+	rig     string
+	commits []git.LogEntry // oldest first
+}
+
+// mergeRe matches the subjects git and forges give merges, capturing the
+// branch merged.
+var mergeRe = regexp.MustCompile(`^Merge (?:branch '([^']+)'|pull request #\d+ from \S+?/(\S+))`)
+
+// scopeRe matches a Conventional Commits subject, capturing its type and
+// scope.
+var scopeRe = regexp.MustCompile(`^(\w+)(?:\(([^)]+)\))?!?: `)
+
+// dashesRe matches runs of dashes, which threadID collapses.
+var dashesRe = regexp.MustCompile(`-+`)
+
+// rigRepo returns a Git for a rig's repo: its shared bare repo, else the
+// mayor's clone, or nil if it has neither.
+func rigRepo(townRoot, rig string) *git.Git {
+	bare := filepath.Join(townRoot, rig, ".repo.git")
+	if info, err := os.Stat(bare); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bare, "")
+	}
+	clone := filepath.Join(townRoot, rig, "mayor", "rig")
+	if g := git.NewGit(clone); g.IsRepo() {
+		return g
+	}
+	return nil
+}
+
+// rigHistories reads the git logs of the rigs asked for, or every rig in
+// mayor/rigs.json, by name.
+func rigHistories(townRoot string, opts PrologueOptions) ([]rigHistory, error) {
+	rigs := opts.Rigs
+	if len(rigs) == 0 {
+		rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading rigs: %w", err)
+		}
+		for name := range rigsConfig.Rigs {
+			rigs = append(rigs, name)
+		}
+	}
+	sort.Strings(rigs)
+	limit := opts.MaxCommits
+	if limit <= 0 {
+		limit = DefaultPrologueCommits
+	}
+	var histories []rigHistory
+	for _, rig := range rigs {
+		g := rigRepo(townRoot, rig)
+		if g == nil {
+			if len(opts.Rigs) > 0 {
+				return nil, fmt.Errorf("rig %s has no repo", rig)
+			}
+			logger.Warn("rig has no repo, left out of the prologue", "rig", rig)
+			continue
+		}
+		entries, err := g.Log("", opts.Since, limit)
+		if err != nil {
+			// A repo with no commits yet has nothing to tell
+			logger.Warn("reading rig's git log", "rig", rig, "err", err)
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		h := rigHistory{rig: rig, commits: make([]git.LogEntry, len(entries))}
+		for i, e := range entries {
+			h.commits[len(entries)-1-i] = e
+		}
+		histories = append(histories, h)
+	}
+	return histories, nil
+}
+
+// storylines finds the storylines running through a rig's history, most
+// commits first: the branches merged into it, and the scopes of its
+// Conventional Commits seen more than once, each as a thread to open.
+func (h rigHistory) storylines() []*Thread {
+	type line struct {
+		thread      *Thread
+		commits     int
+		merged      bool
+		first, last time.Time
+	}
+	lines := make(map[string]*line)
+	var order []string
+	add := func(id, title string, merged bool, e git.LogEntry) {
+		l := lines[id]
+		if l == nil {
+			l = &line{thread: &Thread{ID: id, Title: title, Rig: h.rig}, first: e.Time}
+			lines[id] = l
+			order = append(order, id)
+		}
+		l.commits++
+		l.merged = l.merged || merged
+		l.last = e.Time
+	}
+	for _, e := range h.commits {
+		if m := mergeRe.FindStringSubmatch(e.Subject); m != nil {
+			branch := m[1] + m[2]
+			add(h.rig+"-"+threadID(branch), "The "+branch+" work in "+h.rig, true, e)
+			continue
+		}
+		if m := scopeRe.FindStringSubmatch(e.Subject); m != nil && m[2] != "" {
+			add(h.rig+"-"+threadID(m[2]), "Work on "+m[2]+" in "+h.rig, false, e)
+		}
+	}
+	var found []*line
+	for _, id := range order {
+		if l := lines[id]; l.merged || l.commits > 1 {
+			found = append(found, l)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].commits > found[j].commits })
+	threads := make([]*Thread, 0, len(found))
+	for _, l := range found {
+		l.thread.Title = fmt.Sprintf("%s (%d commits, %s to %s)", l.thread.Title, l.commits,
+			l.first.Local().Format("Jan 2, 2006"), l.last.Local().Format("Jan 2, 2006"))
+		threads = append(threads, l.thread)
+	}
+	return threads
+}
+
+// threadID makes a short thread handle of s.
+func threadID(s string) string {
+	id := strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, s), "-")
+	return dashesRe.ReplaceAllString(id, "-")
+}
+
+// BuildPrologue reads the rigs' git logs (commits, merges and tags),
+// finds the storylines running through them, and renders the prompt for
+// a prologue: the town's first chapter, telling how its rigs came to
+// be. It returns ErrStoryBegun once the story has chapters, and
+// ErrNoHistory if the rigs have no commits.
+func BuildPrologue(townRoot string, opts PrologueOptions) (*ProloguePrompt, error) {
+	story, err := LoadStory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if story.Chapter > 0 {
+		return nil, ErrStoryBegun
+	}
+	st, err := LoadStyle(townRoot, opts.Style)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := rigHistories(townRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		return nil, ErrNoHistory
+	}
+	glossary, err := LoadGlossary(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ProloguePrompt{Prompt: Prompt{Style: st.Name, style: st, story: story}}
+	for _, h := range histories {
+		p.Rigs = append(p.Rigs, h.rig)
+		p.Commits += len(h.commits)
+		if first := h.commits[0].Time; p.Since.IsZero() || first.Before(p.Since) {
+			p.Since = first
+		}
+		p.Storylines = append(p.Storylines, h.storylines()...)
+	}
+	if len(p.Storylines) > maxThreads {
+		p.Storylines = p.Storylines[:maxThreads]
+	}
+	p.Events = p.Commits
+	tone := LoadTone(townRoot)
+	guide, err := st.Guide(StyleData{Chapter: 1, Since: p.Since, Events: p.Events, Tone: tone.Name, Intensity: tone.Intensity})
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("You are the narrator of a Gas Town: a town of AI agents working on code. ")
+	b.WriteString("The town has little history of its own yet, so write its prologue: how its rigs came to be, told from their git history below, ")
+	fmt.Fprintf(&b, "in %s, following the style guide. ", st.describeFormat())
+	b.WriteString("The commits were written before the town's agents kept a record; tell them as the legend the town's story grows out of, and set up the storylines below for the chapters to come. ")
+	b.WriteString("Reply with the chapter only.\n\n")
+	b.WriteString("## Style guide\n\n")
+	b.WriteString(guide)
+	b.WriteString("\n\n")
+	if target := st.lengthTarget(); target != "" {
+		b.WriteString(target + "\n\n")
+	}
+	writeTone(&b, tone)
+	writeGlossary(&b, glossary)
+	writeStory(&b, story)
+
+	if len(p.Storylines) > 0 {
+		b.WriteString("## Storylines\n\n")
+		b.WriteString("Storylines running through the history. Open the ones the prologue sets up in your story block, with these IDs.\n\n")
+		for _, t := range p.Storylines {
+			fmt.Fprintf(&b, "- [%s] %s\n", t.ID, t.Title)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Git history\n\n")
+	for _, h := range histories {
+		writeRigHistory(&b, h)
+	}
+	p.Text = b.String()
+	return p, nil
+}
+
+// writeRigHistory adds a rig's history to a prologue prompt: who wrote it,
+// its milestones, and its commits, oldest first.
+func writeRigHistory(b *strings.Builder, h rigHistory) {
+	authors := make(map[string]int)
+	merges := 0
+	var tags []string
+	for _, e := range h.commits {
+		authors[e.Author]++
+		if e.Parents > 1 {
+			merges++
+		}
+		for _, tag := range e.Tags {
+			tags = append(tags, fmt.Sprintf("%s (%s)", tag, e.Time.Local().Format("Jan 2, 2006")))
+		}
+	}
+	names := make([]string, 0, len(authors))
+	for a := range authors {
+		names = append(names, a)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		if authors[names[i]] != authors[names[j]] {
+			return authors[names[i]] > authors[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	first, last := h.commits[0].Time, h.commits[len(h.commits)-1].Time
+	fmt.Fprintf(b, "### %s\n\n", h.rig)
+	fmt.Fprintf(b, "%d commit(s) by %d author(s), %s to %s; %d merge(s).\n", len(h.commits), len(authors),
+		first.Local().Format("Jan 2, 2006"), last.Local().Format("Jan 2, 2006"), merges)
+	if len(tags) > 0 {
+		fmt.Fprintf(b, "Milestones (tags): %s.\n", strings.Join(tags, ", "))
+	}
+	credits := make([]string, 0, 5)
+	for _, a := range names {
+		if len(credits) == 5 {
+			break
+		}
+		credits = append(credits, fmt.Sprintf("%s (%d)", a, authors[a]))
+	}
+	fmt.Fprintf(b, "Main authors: %s.\n\n", strings.Join(credits, ", "))
+	for _, e := range h.commits {
+		fmt.Fprintf(b, "- %s %s: %s", e.Time.UTC().Format(time.RFC3339), e.Author, e.Subject)
+		if e.Parents > 1 {
+			b.WriteString(" (merge)")
+		}
+		if len(e.Tags) > 0 {
+			fmt.Fprintf(b, " [tag %s]", strings.Join(e.Tags, ", "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// Prologue tells the town's prologue from its rigs' git history (see
+// BuildPrologue) and writes it as the first chapter, so a new town's
+// chronicle starts with a story instead of nothing. The storylines the
+// agent opens, or failing that those found in the history, become the
+// story's first threads for the chapters after it. Like a chapter, the
+// prologue is added to the usage ledger (not written over narrator.quota,
+// a *QuotaError), the chapter index and the narrative branch, and with
+// narrator.publish.auto set the site is republished.
+func Prologue(ctx context.Context, townRoot string, opts PrologueOptions) (*Chapter, error) {
+	lock, err := lockGeneration(ctx, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	prompt, err := BuildPrologue(townRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := LoadUsage(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if reason, retry := checkQuota(quotaSettings(townRoot), usage, estimateTokens(prompt.Text), time.Now()); reason != "" {
+		return nil, &QuotaError{Reason: reason, RetryAt: retry}
+	}
+	r, err := narrate(ctx, townRoot, opts.Agent, &prompt.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now().UTC()
+	rendered, ext, err := renderChapter(townRoot, r.text, prompt.style.Format, "Prologue", until)
+	if err != nil {
+		return nil, err
+	}
+	path := opts.Output
+	if path == "" {
+		if path, err = chapterPath(townRoot, OutputPathData{N: 1, Style: prompt.Style}, until, ext); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating chapter dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(rendered+"\n"), 0644); err != nil { //nolint:gosec // G306: chapters are non-sensitive
+		return nil, fmt.Errorf("writing prologue: %w", err)
+	}
+	usage.add(UsageEntry{At: until, Chapter: 1, Agent: r.agent, Tokens: estimateTokens(prompt.Text) + estimateTokens(r.text)})
+	if err := usage.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("prologue written to %s, but saving usage ledger: %w", path, err)
+	}
+
+	update := r.update
+	if update == nil || len(update.Opened) == 0 {
+		if update == nil {
+			update = &StoryUpdate{}
+		}
+		update.Opened = prompt.Storylines
+	}
+	story := prompt.story
+	threads := update.threads(story)
+	story.Advance(path, nil, update)
+	if err := story.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("prologue written to %s, but saving story state: %w", path, err)
+	}
+	chapter := &Chapter{
+		Number:  story.Chapter,
+		Path:    path,
+		Style:   prompt.Style,
+		Agent:   r.agent,
+		Since:   prompt.Since,
+		Until:   until,
+		Events:  prompt.Commits,
+		Words:   len(strings.Fields(r.text)),
+		Issues:  r.issues,
+		Rigs:    prompt.Rigs,
+		Recap:   strings.TrimSpace(update.Recap),
+		Threads: threads,
+	}
+	if chapter.Length = prompt.style.CheckLength(chapter.Words); chapter.Length != "" {
+		logger.Warn("prologue misses the style's length target", "style", chapter.Style, "length", chapter.Length)
+	}
+	if chapter.Commit, err = commitChapter(townRoot, path, chapter.commitMessage()); err != nil {
+		return nil, fmt.Errorf("prologue written to %s, but %w", path, err)
+	}
+	logger.Info("prologue written", "path", path, "agent", chapter.Agent, "rigs", chapter.Rigs, "commits", chapter.Events)
+	if err := recordChapter(townRoot, chapter.record()); err != nil {
+		return chapter, fmt.Errorf("prologue written to %s, but recording it: %w", path, err)
+	}
+	if err := updateRecallIndex(townRoot); err != nil {
+		logger.Warn("updating recall index", "err", err)
+	}
+	if chapter.Site, err = autoPublish(townRoot); err != nil {
+		return chapter, fmt.Errorf("prologue written to %s, but publishing the site: %w", path, err)
+	}
+	return chapter, nil
+}
@@ -0,0 +1,128 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// gitRig makes a rig in townRoot whose mayor's clone has a short history:
+// two commits in one scope, a merged branch and a tag.
+func gitRig(t *testing.T, townRoot, rig string) {
+	t.Helper()
+	dir := filepath.Join(townRoot, rig, "mayor", "rig")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	run("commit", "-q", "--allow-empty", "-m", "feat(refinery): merge queue")
+	run("commit", "-q", "--allow-empty", "-m", "fix(refinery): retry conflicts")
+	run("checkout", "-q", "-b", "witness-patrol")
+	run("commit", "-q", "--allow-empty", "-m", "Add witness patrol")
+	run("checkout", "-q", "main")
+	run("merge", "-q", "--no-ff", "-m", "Merge branch 'witness-patrol'", "witness-patrol")
+	run("tag", "v0.1.0")
+}
+
+func TestPrologue(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	gitRig(t, townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(townRoot, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gastown": {}, "empty": {}}}
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(townRoot), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	prompt, err := BuildPrologue(townRoot, PrologueOptions{})
+	if err != nil {
+		t.Fatalf("BuildPrologue: %v", err)
+	}
+	if strings.Join(prompt.Rigs, ",") != "gastown" || prompt.Commits != 5 {
+		t.Errorf("prologue tells %d commits of %v, want gastown's 5 (empty has no repo)", prompt.Commits, prompt.Rigs)
+	}
+	var ids []string
+	for _, s := range prompt.Storylines {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, ",") != "gastown-refinery,gastown-witness-patrol" {
+		t.Errorf("storylines = %v, want the refinery scope and the merged branch", ids)
+	}
+	for _, want := range []string{"This is chapter 1.", "5 commit(s) by 1 author(s)", "Milestones (tags): v0.1.0", "Ada: fix(refinery): retry conflicts", "Merge branch 'witness-patrol' (merge) [tag v0.1.0]", "[gastown-refinery] Work on refinery in gastown (2 commits"} {
+		if !strings.Contains(prompt.Text, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt.Text)
+		}
+	}
+	if _, err := BuildPrologue(townRoot, PrologueOptions{Rigs: []string{"empty"}}); err == nil {
+		t.Error("prologue of a rig without a repo succeeded")
+	}
+
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("In the beginning there was an empty commit.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+	chapter, err := Prologue(context.Background(), townRoot, PrologueOptions{Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Prologue: %v", err)
+	}
+	if chapter.Number != 1 || chapter.Events != 5 || !strings.Contains(readFile(t, chapter.Path), "In the beginning") {
+		t.Errorf("chapter = %+v", chapter)
+	}
+	story, err := LoadStory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if story.Chapter != 1 || len(story.Threads) != 2 || story.Threads[0].ID != "gastown-refinery" {
+		t.Errorf("story = %+v, want the history's storylines as its threads", story)
+	}
+	if records, err := LoadChapters(townRoot); err != nil || len(records) != 1 || records[0].Path != chapter.Path {
+		t.Errorf("chapter index = %+v, %v; want the prologue", records, err)
+	}
+	if _, err := Prologue(context.Background(), townRoot, PrologueOptions{Agent: "claude"}); !errors.Is(err, ErrStoryBegun) {
+		t.Errorf("second prologue error = %v, want ErrStoryBegun", err)
+	}
+}
+
+func TestPrologue_NoHistory(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(townRoot), &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gastown": {}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BuildPrologue(townRoot, PrologueOptions{}); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("error = %v, want ErrNoHistory", err)
+	}
+}
+
+func TestThreadID(t *testing.T) {
+	for in, want := range map[string]string{
+		"witness-patrol":    "witness-patrol",
+		"feature/Big Thing": "feature-big-thing",
+		"--x__y--":          "x-y",
+	} {
+		if got := threadID(in); got != want {
+			t.Errorf("threadID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
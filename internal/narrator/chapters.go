@@ -0,0 +1,156 @@
+package narrator
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// ChaptersPath returns the path to the index of written chapters.
+func ChaptersPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "chapters.jsonl")
+}
+
+// ChapterRecord is a written chapter as the chapter index records it, for
+// publishing: where it is, what it covers and its illustrations.
+type ChapterRecord struct {
+	Number  int       `json:"number,omitempty"` // 0 for catch-up chapters
+	Path    string    `json:"path"`
+	Style   string    `json:"style,omitempty"`
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	Rigs    []string  `json:"rigs,omitempty"` // rigs whose events it narrates
+	Events  int       `json:"events"`
+	CatchUp bool      `json:"catch_up,omitempty"`
+	Digest  bool      `json:"digest,omitempty"`
+	Recap   string    `json:"recap,omitempty"`   // the narrator's recap of it
+	Threads []string  `json:"threads,omitempty"` // storylines it opened, continued or concluded
+
+	// With narrator.review: whether the chapter awaits review, was
+	// approved or was rejected, and by whom
+	Review        string         `json:"review,omitempty"`
+	ReviewedBy    string         `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time     `json:"reviewed_at,omitempty"`
+	ReviewNote    string         `json:"review_note,omitempty"` // why it was rejected
+	Illustrations []Illustration `json:"illustrations,omitempty"`
+}
+
+// recordChapter appends a chapter to the chapter index. With
+// narrator.review set, a new chapter is recorded as awaiting review.
+func recordChapter(townRoot string, rec ChapterRecord) error {
+	holdForReview(townRoot, &rec)
+	if abs, err := filepath.Abs(rec.Path); err == nil {
+		rec.Path = abs
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	f, err := os.OpenFile(ChaptersPath(townRoot), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: the index is non-sensitive
+	if err != nil {
+		return fmt.Errorf("opening chapter index: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing chapter index: %w", err)
+	}
+	return f.Close()
+}
+
+// record returns the chapter's entry in the chapter index.
+func (c *Chapter) record() ChapterRecord {
+	return ChapterRecord{
+		Number:        c.Number,
+		Path:          c.Path,
+		Style:         c.Style,
+		Since:         c.Since,
+		Until:         c.Until,
+		Rigs:          c.Rigs,
+		Events:        c.Events,
+		Recap:         c.Recap,
+		Threads:       c.Threads,
+		Illustrations: c.Illustrations,
+	}
+}
+
+// chapterRigs returns the rigs whose events are in evts, sorted.
+func chapterRigs(evts []events.Event) []string {
+	seen := make(map[string]bool)
+	var rigs []string
+	for _, e := range evts {
+		if rig := events.EventRig(e); rig != "" && !seen[rig] {
+			seen[rig] = true
+			rigs = append(rigs, rig)
+		}
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// LoadChapters returns the town's chapters, oldest first: those in the
+// chapter index, then any chapter-* or catch-up-* file in narrator/ written
+// before the index was kept, dated by its file name. A chapter recorded
+// twice (regenerated to the same path) keeps its latest record.
+func LoadChapters(townRoot string) ([]ChapterRecord, error) {
+	var records []ChapterRecord
+	byPath := make(map[string]int)
+
+	f, err := os.Open(ChaptersPath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading chapter index: %w", err)
+	}
+	if f != nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			var rec ChapterRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Path == "" {
+				continue
+			}
+			if i, ok := byPath[rec.Path]; ok {
+				records[i] = rec
+				continue
+			}
+			byPath[rec.Path] = len(records)
+			records = append(records, rec)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading chapter index: %w", err)
+		}
+	}
+
+	for _, pattern := range []string{"chapter-*", "catch-up-*"} {
+		paths, _ := filepath.Glob(filepath.Join(Dir(townRoot), pattern))
+		for _, path := range paths {
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			if _, ok := byPath[path]; ok {
+				continue
+			}
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			stamp := strings.TrimPrefix(strings.TrimPrefix(name, "chapter-"), "catch-up-")
+			until, err := time.Parse("20060102-150405", stamp)
+			if err != nil {
+				continue
+			}
+			byPath[path] = len(records)
+			records = append(records, ChapterRecord{Path: path, Until: until, CatchUp: strings.HasPrefix(name, "catch-up-")})
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Until.Before(records[j].Until) })
+	return records, nil
+}
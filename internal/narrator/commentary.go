@@ -0,0 +1,188 @@
+package narrator
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// commentaryPaneLines is the height of the commentary pane.
+const commentaryPaneLines = 8
+
+// Comment is one line of live commentary on an event.
+type Comment struct {
+	At    time.Time `json:"at"`
+	Level string    `json:"level"` // the event's significance; high for incidents
+	Type  string    `json:"type"`
+	Text  string    `json:"text"`
+}
+
+var (
+	// openers lead a comment, by significance, so the big moments sound
+	// like them
+	openers = map[string][]string{
+		events.SignificanceHigh:   {"OH! ", "Would you look at that: ", "Big moment here! ", "Hold on to your hats: "},
+		events.SignificanceMedium: {"", "And now ", "Meanwhile, ", "On the board: "},
+		events.SignificanceLow:    {"", "Quietly, ", "In the background, "},
+	}
+
+	// closers are the color an event type gets after the call
+	closers = map[string][]string{
+		events.TypeMerged:         {" And it's in!", " Clean into the main line!", " That one counts!"},
+		events.TypeMergeFailed:    {" Oh, that's going to sting.", " Back to the drawing board.", " The refinery says no!"},
+		events.TypeDone:           {" Over the line!", " Job done.", " Another one wrapped."},
+		events.TypeSling:          {" Here we go!", " The work is in play.", " Let's see what they make of it."},
+		events.TypeSpawn:          {" Fresh legs on the field.", " A new polecat takes the field."},
+		events.TypeSessionDeath:   {" They're down!", " That's a blow."},
+		events.TypeMassDeath:      {" Carnage out there!", " Bodies everywhere!"},
+		events.TypeEscalationSent: {" The humans are being called in!", " This one's going upstairs."},
+		events.TypeHandoff:        {" Passing the baton.", " Fresh session, same job."},
+	}
+)
+
+// Commentate returns a line of sportscaster commentary on an event: what
+// happened, as the feed says it, with the actor's persona name, led in and
+// colored by its significance and type. The same event always gets the
+// same line.
+func Commentate(e events.Event, level string, personas *Personas) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(events.EventHash(e)))
+	pick := func(options []string) string {
+		if len(options) == 0 {
+			return ""
+		}
+		return options[int(h.Sum32()%uint32(len(options)))] //nolint:gosec // G115: the index is below len(options)
+	}
+
+	call := strings.TrimSpace(feed.Summary(&e))
+	if e.Type == events.TypeIncident {
+		call = "Pileup! " + incidentCall(e)
+	}
+	opener := pick(openers[level])
+	if strings.HasSuffix(opener, " ") && !strings.HasSuffix(opener, ": ") && !strings.HasSuffix(opener, "! ") {
+		// Mid-sentence openers run into the call, which the feed starts
+		// with a capital or an actor's address
+		call = lowerFirst(call)
+	}
+	if e.Actor != "" && personas != nil {
+		if p, err := personas.Get(e.Actor); err == nil && p.Name != "" {
+			call = strings.ReplaceAll(call, strings.TrimSuffix(e.Actor, "/"), p.Name)
+		}
+	}
+	line := opener + call
+	if !strings.ContainsAny(line[len(line)-1:], ".!?") {
+		line += "."
+	}
+	return line + pick(closers[e.Type])
+}
+
+// incidentCall describes an incident event, a burst the scorer noticed.
+func incidentCall(e events.Event) string {
+	of, _ := e.Payload["type"].(string)
+	count := e.Payload["count"]
+	window, _ := e.Payload["window"].(string)
+	if of == "" {
+		return "something's happening again and again"
+	}
+	call := fmt.Sprintf("%v %s events", count, of)
+	if window != "" {
+		call += " in " + window
+	}
+	return call
+}
+
+// lowerFirst lowercases the first letter of s, unless it starts an
+// acronym.
+func lowerFirst(s string) string {
+	if s == "" || len(s) > 1 && unicode.IsUpper(rune(s[1])) {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// Commentary delivers a line of commentary for each event at or above the
+// significance level as it is written, and for each incident the scorer
+// raises (see events.Scorer), until ctx is done. Events come from Watch, so
+// the commentary is live and keeps no checkpoint: it is for watching, not
+// for the record.
+func Commentary(ctx context.Context, townRoot, level string) (<-chan Comment, error) {
+	if level == "" {
+		level = events.SignificanceMedium
+	}
+	if err := events.ValidSignificance(level); err != nil {
+		return nil, err
+	}
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	personas, err := LoadPersonas(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	records, err := Watch(ctx, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	scorer := events.NewScorer(rules)
+	out := make(chan Comment)
+	emit := func(e events.Event, level string) bool {
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			at = time.Now()
+		}
+		select {
+		case out <- Comment{At: at, Level: level, Type: e.Type, Text: Commentate(e, level, personas)}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		defer close(out)
+		for r := range records {
+			got, incident := scorer.Score(r.Event)
+			if events.SignificanceAtLeast(got, level) && !emit(r.Event, got) {
+				return
+			}
+			if incident != nil && !emit(*incident, events.SignificanceHigh) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// OpenCommentary opens a pane below target (a tmux session, window or
+// pane; the narrator's session if empty) running 'gt narrator commentary'
+// at the significance level, and returns the pane's ID.
+func (m *Manager) OpenCommentary(target, level string) (string, error) {
+	if target == "" {
+		target = m.SessionName()
+	}
+	t := tmux.NewTmux()
+	if has, err := t.HasSession(strings.SplitN(target, ":", 2)[0]); err != nil || !has {
+		return "", fmt.Errorf("no tmux session %q to open the commentary in", target)
+	}
+	gt, err := os.Executable()
+	if err != nil {
+		gt = "gt"
+	}
+	command := fmt.Sprintf("%q narrator commentary", gt)
+	if level != "" {
+		command += " --significance " + level
+	}
+	pane, err := t.SplitWindow(target, m.townRoot, command, commentaryPaneLines)
+	if err != nil {
+		return "", fmt.Errorf("opening commentary pane: %w", err)
+	}
+	return pane, nil
+}
@@ -0,0 +1,79 @@
+package narrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestCommentate(t *testing.T) {
+	personas, err := LoadPersonas(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := personas.Add(Persona{Actor: "mayor", Name: "Mayor Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	sling := events.Event{Timestamp: "2026-03-01T12:00:00Z", Type: events.TypeSling, Actor: "mayor",
+		Payload: map[string]interface{}{"bead": "gt-1", "target": "gastown/Toast"}}
+	line := Commentate(sling, events.SignificanceMedium, personas)
+	if !strings.Contains(line, "Mayor Ada assigned gt-1 to gastown/Toast") {
+		t.Errorf("comment = %q, want the feed's summary with the persona's name", line)
+	}
+	if again := Commentate(sling, events.SignificanceMedium, personas); again != line {
+		t.Errorf("comments on one event differ: %q, %q", line, again)
+	}
+
+	failed := events.Event{Timestamp: "2026-03-01T12:00:00Z", Type: events.TypeMergeFailed, Actor: "gastown/refinery",
+		Payload: map[string]interface{}{"reason": "tests failed"}}
+	line = Commentate(failed, events.SignificanceHigh, nil)
+	var opened bool
+	for _, o := range openers[events.SignificanceHigh] {
+		opened = opened || strings.HasPrefix(line, o)
+	}
+	if !opened || !strings.Contains(line, "erge failed: tests failed.") {
+		t.Errorf("comment = %q, want a high-significance call of the failure", line)
+	}
+
+	incident := events.Event{Timestamp: "2026-03-01T12:00:00Z", Type: events.TypeIncident, Actor: "gastown/refinery",
+		Payload: events.IncidentPayload(events.TypeMergeFailed, "gastown", 5, "10m", "2026-03-01T11:55:00Z")}
+	if line := Commentate(incident, events.SignificanceHigh, nil); !strings.Contains(line, "Pileup! 5 merge_failed events in 10m") {
+		t.Errorf("comment = %q, want the incident called", line)
+	}
+}
+
+func TestCommentary(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEvents(t, townRoot, time.Now(), `"type":"boot","actor":"mayor"}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comments, err := Commentary(ctx, townRoot, events.SignificanceMedium)
+	if err != nil {
+		t.Fatalf("Commentary: %v", err)
+	}
+	for _, e := range []events.Event{
+		{Type: events.TypePolecatChecked, Actor: "gastown/witness"},
+		{Type: events.TypeMerged, Actor: "gastown/refinery", Payload: map[string]interface{}{"worker": "Toast"}},
+	} {
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		e.Source = "gt"
+		if err := events.Append(townRoot, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case c := <-comments:
+		if c.Type != events.TypeMerged || !strings.Contains(c.Text, "erged work from Toast") {
+			t.Errorf("comment = %+v, want the merge called and the routine check skipped", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no comment delivered")
+	}
+	if _, err := Commentary(ctx, townRoot, "loud"); err == nil {
+		t.Error("Commentary at an unknown significance succeeded")
+	}
+}
@@ -0,0 +1,261 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultDigestInterval is how often digests are written when
+// narrator.digest doesn't set an interval.
+const DefaultDigestInterval = 24 * time.Hour
+
+// townRig stands for the rig of events that belong to none, in digests
+// and chapter paths.
+const townRig = "town"
+
+// DigestOptions selects the events a digest run covers.
+type DigestOptions struct {
+	Since time.Time // zero means since the last digest
+	Until time.Time // zero means now
+	Rig   string    // "" digests every rig
+}
+
+// Digest is one rig's digest: the routine events of a stretch, counted and
+// listed rather than narrated.
+type Digest struct {
+	Rig    string    `json:"rig"` // "town" for events of no rig
+	Path   string    `json:"path"`
+	Since  time.Time `json:"since"`
+	Until  time.Time `json:"until"`
+	Events int       `json:"events"`
+	Commit string    `json:"commit,omitempty"` // narrative branch commit, with narrator.git_output
+}
+
+// digestSettings returns the town's narrator.digest setting, or nil.
+func digestSettings(townRoot string) *config.NarratorDigest {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.Digest
+}
+
+// digestInterval returns how often digests are written.
+func digestInterval(d *config.NarratorDigest) (time.Duration, error) {
+	if d == nil || d.Interval == "" {
+		return DefaultDigestInterval, nil
+	}
+	interval, err := time.ParseDuration(d.Interval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("narrator.digest: invalid interval %q (want a Go duration like 24h)", d.Interval)
+	}
+	return interval, nil
+}
+
+// WriteDigests writes a digest per rig of the low- and medium-significance
+// events in range: counts by type and the medium-significance events
+// listed, without running the agent. With Since zero the digests pick up
+// where the last ones stopped, or an interval back. Digests are recorded
+// as chapters, so they are committed and published like them. It returns
+// ErrNoEvents if there was nothing to digest.
+func WriteDigests(ctx context.Context, townRoot string, opts DigestOptions) ([]*Digest, error) {
+	lock, err := lockGeneration(ctx, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+	return writeDigests(townRoot, opts)
+}
+
+// dueDigests writes the digests if narrator.digest is set and an interval
+// has passed since the last ones. The caller holds the generation lock.
+func dueDigests(townRoot string, now time.Time) ([]*Digest, error) {
+	cfg := digestSettings(townRoot)
+	if cfg == nil {
+		return nil, nil
+	}
+	interval, err := digestInterval(cfg)
+	if err != nil {
+		return nil, err
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if state.DigestedThrough.IsZero() {
+		// Nothing to go on yet; start the first interval now
+		state.DigestedThrough = now
+		return nil, state.Save(townRoot)
+	}
+	if now.Sub(state.DigestedThrough) < interval {
+		return nil, nil
+	}
+	digests, err := writeDigests(townRoot, DigestOptions{Until: now})
+	if errors.Is(err, ErrNoEvents) {
+		return nil, nil
+	}
+	return digests, err
+}
+
+func writeDigests(townRoot string, opts DigestOptions) ([]*Digest, error) {
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now().UTC()
+	}
+	since := opts.Since
+	if since.IsZero() {
+		since = state.DigestedThrough
+		if since.IsZero() {
+			interval, err := digestInterval(digestSettings(townRoot))
+			if err != nil {
+				return nil, err
+			}
+			since = until.Add(-interval)
+		}
+	}
+
+	records, err := events.Query(townRoot, events.Filter{Since: since, Until: until, Rig: opts.Rig})
+	if err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Score in context, as narration does, so the events a burst makes
+	// an incident are left to the narrative
+	scorer := events.NewScorer(rules)
+	byRig := make(map[string]*rigEvents)
+	for _, r := range records {
+		level, _ := scorer.Score(r.Event)
+		if level == events.SignificanceHigh {
+			continue
+		}
+		e, visible := policy.Expose(r.Event, events.AudienceNarrator)
+		if !visible {
+			continue
+		}
+		rig := events.EventRig(e)
+		if rig == "" {
+			rig = townRig
+		}
+		if byRig[rig] == nil {
+			byRig[rig] = &rigEvents{byType: make(map[string]int)}
+		}
+		byRig[rig].add(e, level)
+	}
+	if len(byRig) == 0 {
+		return nil, ErrNoEvents
+	}
+	rigs := make([]string, 0, len(byRig))
+	for rig := range byRig {
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+
+	personas, err := LoadPersonas(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var digests []*Digest
+	for _, rig := range rigs {
+		d := &Digest{Rig: rig, Since: since, Until: until, Events: byRig[rig].count}
+		if err := d.write(townRoot, byRig[rig], personas); err != nil {
+			return digests, err
+		}
+		digests = append(digests, d)
+	}
+
+	if opts.Rig == "" && until.After(state.DigestedThrough) {
+		state.DigestedThrough = until
+		if err := state.Save(townRoot); err != nil {
+			return digests, fmt.Errorf("digests written, but saving narrator state: %w", err)
+		}
+	}
+	if _, err := autoPublish(townRoot); err != nil {
+		return digests, fmt.Errorf("digests written, but publishing the site: %w", err)
+	}
+	return digests, nil
+}
+
+// rigEvents are the events of one rig's digest.
+type rigEvents struct {
+	count  int
+	byType map[string]int
+	listed []events.Record // the medium-significance ones, the latest maxHighlights
+}
+
+func (r *rigEvents) add(e events.Event, level string) {
+	r.count++
+	r.byType[e.Type]++
+	if level == events.SignificanceMedium {
+		r.listed = append(r.listed, events.Record{Event: e})
+		if len(r.listed) > maxHighlights {
+			r.listed = r.listed[1:]
+		}
+	}
+}
+
+// write writes, commits and records the digest of a rig's events.
+func (d *Digest) write(townRoot string, evts *rigEvents, personas *Personas) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Digest: %s, %s to %s\n\n", d.Rig, d.Since.Local().Format("2006-01-02 15:04"), d.Until.Local().Format("2006-01-02 15:04"))
+	tone := LoadTone(townRoot)
+	intro := fmt.Sprintf("The routine business of %s: %d low- and medium-significance events, counted rather than told.", d.Rig, d.Events)
+	fmt.Fprintf(&b, "%s\n\n", tone.summaryIntro(intro, d.Events))
+	writeTally(&b, evts.byType, evts.listed, personas, tone)
+
+	rendered, ext, err := renderChapter(townRoot, b.String(), FormatMarkdown, "Digest: "+d.Rig, d.Until)
+	if err != nil {
+		return err
+	}
+	d.Path = filepath.Join(Dir(townRoot), "digest-"+d.Rig+"-"+d.Until.Format("20060102-150405")+ext)
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	if err := os.WriteFile(d.Path, []byte(rendered), 0644); err != nil { //nolint:gosec // G306: chapters are non-sensitive
+		return fmt.Errorf("writing digest: %w", err)
+	}
+	if d.Commit, err = commitChapter(townRoot, d.Path, d.commitMessage()); err != nil {
+		return fmt.Errorf("digest written to %s, but %w", d.Path, err)
+	}
+	logger.Info("digest written", "rig", d.Rig, "path", d.Path, "events", d.Events, "commit", d.Commit)
+	var rigs []string
+	if d.Rig != townRig {
+		rigs = []string{d.Rig}
+	}
+	rec := ChapterRecord{Path: d.Path, Since: d.Since, Until: d.Until, Rigs: rigs, Events: d.Events, Digest: true}
+	if err := recordChapter(townRoot, rec); err != nil {
+		return fmt.Errorf("digest written to %s, but recording it: %w", d.Path, err)
+	}
+	return nil
+}
+
+// commitMessage renders a digest commit message in the same form as
+// Chapter's.
+func (d *Digest) commitMessage() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Digest: %s, %d event(s)\n\n", d.Rig, d.Events)
+	fmt.Fprintf(&b, "Digest: %s\n", d.Rig)
+	fmt.Fprintf(&b, "Since: %s\n", d.Since.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Until: %s\n", d.Until.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Events: %d\n", d.Events)
+	return b.String()
+}
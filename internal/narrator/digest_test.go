@@ -0,0 +1,111 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestWriteDigests(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEvents(t, townRoot, time.Now().Add(-time.Hour),
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/Toast","rig":"gastown"}}`,
+		`"type":"nudge","actor":"gastown/witness","payload":{"rig":"gastown"}}`,
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`"type":"done","actor":"beads/nux","payload":{"bead":"bd-2","rig":"beads"}}`,
+		`"type":"boot","actor":"mayor"}`,
+	)
+
+	digests, err := WriteDigests(context.Background(), townRoot, DigestOptions{Since: time.Now().Add(-2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("WriteDigests: %v", err)
+	}
+	var rigs []string
+	for _, d := range digests {
+		rigs = append(rigs, d.Rig)
+	}
+	if strings.Join(rigs, ",") != "beads,gastown,town" {
+		t.Fatalf("digests for %v, want beads, gastown and town", rigs)
+	}
+	gastown := digests[1]
+	if gastown.Events != 2 {
+		t.Errorf("gastown digest has %d events, want the sling and nudge", gastown.Events)
+	}
+	text := readFile(t, gastown.Path)
+	for _, want := range []string{"# Digest: gastown", "- sling: 1", "- nudge: 1", "mayor, sling"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("gastown digest lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "merge_failed") || strings.Contains(text, "gastown/witness, nudge") {
+		t.Errorf("gastown digest lists a high or low event:\n%s", text)
+	}
+
+	chapters, err := LoadChapters(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chapters) != 3 || !chapters[0].Digest {
+		t.Errorf("chapter index = %+v, want the 3 digests", chapters)
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.DigestedThrough.IsZero() {
+		t.Error("DigestedThrough not advanced")
+	}
+	// The next run starts where this one stopped
+	if _, err := WriteDigests(context.Background(), townRoot, DigestOptions{}); !errors.Is(err, ErrNoEvents) {
+		t.Errorf("second WriteDigests error = %v, want ErrNoEvents", err)
+	}
+}
+
+func TestGenerate_DigestMode(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Digest: &config.NarratorDigest{Interval: "1h"}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	state := &State{DigestedThrough: time.Now().Add(-2 * time.Hour)}
+	if err := state.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now().Add(-time.Minute),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/Toast","rig":"gastown"}}`,
+	)
+	var prompt string
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompt = strings.Join(argv, " ")
+		return []byte("# A Failed Merge\n\nThe merge failed.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if chapter.Events != 1 || strings.Contains(prompt, "gt-1") {
+		t.Errorf("chapter narrates %d events, want only the failed merge", chapter.Events)
+	}
+	if len(chapter.Digests) != 1 || !strings.Contains(readFile(t, chapter.Digests[0]), "- sling: 1") {
+		t.Errorf("Digests = %v, want the due gastown digest with the sling", chapter.Digests)
+	}
+
+	// Not due again until another interval has passed
+	chapter, err = Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("second Generate: %v", err)
+	}
+	if len(chapter.Digests) != 0 {
+		t.Errorf("second chapter wrote digests %v", chapter.Digests)
+	}
+}
@@ -0,0 +1,164 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Edition is a chapter written again in another of narrator.styles, from
+// the same events.
+type Edition struct {
+	Style     string      `json:"style"`
+	Path      string      `json:"path,omitempty"` // "" if it wasn't written
+	Agent     string      `json:"agent,omitempty"`
+	Words     int         `json:"words,omitempty"`
+	Length    string      `json:"length,omitempty"`     // how it misses the style's length target
+	Issues    []LintIssue `json:"issues,omitempty"`     // terminology issues found in it
+	OverQuota string      `json:"over_quota,omitempty"` // the limit it was over, and so not written
+}
+
+// editionStyles returns the town's narrator.styles.
+func editionStyles(townRoot string) []config.NarratorStyle {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.Styles
+}
+
+// parseStyles parses a narrator.styles setting, "name[=output_dir],...".
+func parseStyles(townRoot, v string) ([]config.NarratorStyle, error) {
+	var styles []config.NarratorStyle
+	for _, field := range strings.Split(v, ",") {
+		name, dir, _ := strings.Cut(strings.TrimSpace(field), "=")
+		name, dir = strings.TrimSpace(name), strings.TrimSpace(dir)
+		if name == "" {
+			continue
+		}
+		if _, err := LoadStyle(townRoot, name); err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			if _, err := parseOutputDir(dir); err != nil {
+				return nil, err
+			}
+		}
+		styles = append(styles, config.NarratorStyle{Name: name, OutputDir: dir})
+	}
+	return styles, nil
+}
+
+// formatStyles renders narrator.styles as parseStyles reads it.
+func formatStyles(styles []config.NarratorStyle) string {
+	fields := make([]string, len(styles))
+	for i, s := range styles {
+		fields[i] = s.Name
+		if s.OutputDir != "" {
+			fields[i] += "=" + s.OutputDir
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// editionPath returns where a chapter's edition in style s is written: its
+// output_dir rendered for the chapter, beside an explicitly placed chapter
+// as <name>-<style><ext>, or in narrator/<style>/.
+func editionPath(townRoot string, s config.NarratorStyle, n int, until time.Time, output, rig, ext string) (string, error) {
+	if s.OutputDir == "" && output != "" {
+		return strings.TrimSuffix(output, filepath.Ext(output)) + "-" + fileSafe(s.Name) + ext, nil
+	}
+	data := OutputPathData{Rig: rig, N: n, Style: s.Name}
+	return outputPath(townRoot, s.OutputDir, filepath.Join(Dir(townRoot), fileSafe(s.Name)), data, until, ext)
+}
+
+// writeEditions writes the editions of chapter n, ending at until, in
+// narrator.styles other than its own: each from the prompt's events
+// rendered in that style, so the events are selected and scored once. It
+// is called before the story state moves on, so each edition is prompted
+// with the story as the chapter was. An edition is an agent run like the
+// chapter: it is added to the usage ledger and, over narrator.quota, not
+// written. Editions aren't chapters of record: the story state, chapter
+// index, narrative branch and site follow the chapter alone. A failed
+// edition doesn't keep the others from being written; strict, one with
+// terminology issues isn't written (a *LintError).
+func writeEditions(ctx context.Context, townRoot string, n int, until time.Time, prompt *Prompt, usage *Usage, opts GenerateOptions) ([]Edition, error) {
+	styles := editionStyles(townRoot)
+	if len(styles) == 0 || prompt.batch == nil {
+		return nil, nil
+	}
+	var editions []Edition
+	var errs []error
+	for _, s := range styles {
+		if s.Name == prompt.Style {
+			continue
+		}
+		edition, err := writeEdition(ctx, townRoot, s, n, until, prompt.batch, usage, opts)
+		if edition != nil {
+			editions = append(editions, *edition)
+		}
+		if err != nil {
+			logger.Warn("writing edition", "chapter", n, "style", s.Name, "err", err)
+			errs = append(errs, fmt.Errorf("%s edition: %w", s.Name, err))
+		}
+	}
+	return editions, errors.Join(errs...)
+}
+
+func writeEdition(ctx context.Context, townRoot string, s config.NarratorStyle, n int, until time.Time, bt *batch, usage *Usage, opts GenerateOptions) (*Edition, error) {
+	st, err := LoadStyle(townRoot, s.Name)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := bt.prompt(townRoot, st)
+	if err != nil {
+		return nil, err
+	}
+	edition := &Edition{Style: st.Name}
+	if reason, _ := checkQuota(quotaSettings(townRoot), usage, estimateTokens(prompt.Text), time.Now()); reason != "" {
+		logger.Warn("edition over quota, not written", "chapter", n, "style", st.Name, "reason", reason)
+		edition.OverQuota = reason
+		return edition, nil
+	}
+	r, err := narrate(ctx, townRoot, opts.Agent, prompt)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Strict && len(r.issues) > 0 {
+		return nil, &LintError{Issues: r.issues}
+	}
+	rendered, ext, err := renderChapter(townRoot, r.text, st.Format, fmt.Sprintf("Chapter %d", n), until)
+	if err != nil {
+		return nil, err
+	}
+	path, err := editionPath(townRoot, s, n, until, opts.Output, opts.Rig, ext)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating edition dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(rendered+"\n"), 0644); err != nil { //nolint:gosec // G306: chapters are non-sensitive
+		return nil, fmt.Errorf("writing edition: %w", err)
+	}
+	usage.add(UsageEntry{At: time.Now().UTC(), Chapter: n, Agent: r.agent,
+		Tokens: estimateTokens(prompt.Text) + estimateTokens(r.text)})
+	if err := usage.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("edition written to %s, but saving usage ledger: %w", path, err)
+	}
+	edition.Path = path
+	edition.Agent = r.agent
+	edition.Words = len(strings.Fields(r.text))
+	edition.Issues = r.issues
+	if edition.Length = st.CheckLength(edition.Words); edition.Length != "" {
+		logger.Warn("edition misses the style's length target", "chapter", n, "style", st.Name, "length", edition.Length)
+	}
+	logger.Info("edition written", "chapter", n, "style", st.Name, "path", path, "agent", r.agent)
+	return edition, nil
+}
@@ -0,0 +1,122 @@
+package narrator
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestGenerate_Editions(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Write a book chapter.")
+	writeStyleDef(t, townRoot, "short", `
+format: text
+prompt: |
+  Write short {{.Chapter}} as a video script.
+`)
+	writeStyle(t, townRoot, "ballad", "Write a ballad.")
+	if err := Set(townRoot, "styles", "short,nope"); err == nil {
+		t.Error("Set styles with an unknown style succeeded")
+	}
+	if err := Set(townRoot, "styles", "short, ballad=history/ballads/{{.N}}.md,default"); err != nil {
+		t.Fatalf("Set styles: %v", err)
+	}
+	if got := formatStyles(editionStyles(townRoot)); got != "short,ballad=history/ballads/{{.N}}.md,default" {
+		t.Errorf("styles = %q", got)
+	}
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+
+	var prompts []string
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompt := argv[len(argv)-1]
+		prompts = append(prompts, prompt)
+		switch {
+		case strings.Contains(prompt, "video script"):
+			return []byte("SHORT: the merge fails.\n"), nil
+		case strings.Contains(prompt, "ballad"):
+			return []byte("Oh the merge, it failed.\n```story\n{\"recap\":\"A ballad.\"}\n```\n"), nil
+		}
+		return []byte("The merge failed.\n```story\n{\"recap\":\"It failed.\"}\n```\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(prompts) != 3 {
+		t.Fatalf("agent ran %d times, want the chapter and two editions", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "Write short 1 as a video script.") || !strings.Contains(prompts[1], "merge_failed gastown/refinery") {
+		t.Errorf("short edition prompt:\n%s", prompts[1])
+	}
+	if len(chapter.Editions) != 2 {
+		t.Fatalf("Editions = %+v, want short and ballad", chapter.Editions)
+	}
+	short, ballad := chapter.Editions[0], chapter.Editions[1]
+	if short.Style != "short" || filepath.Dir(short.Path) != filepath.Join(Dir(townRoot), "short") || filepath.Ext(short.Path) != ".txt" {
+		t.Errorf("short edition = %+v", short)
+	}
+	if got := readFile(t, short.Path); !strings.Contains(got, "SHORT: the merge fails.") {
+		t.Errorf("short edition reads %q", got)
+	}
+	if want := filepath.Join(townRoot, "history", "ballads", "1.md"); ballad.Path != want {
+		t.Errorf("ballad edition at %s, want %s", ballad.Path, want)
+	}
+	if got := readFile(t, ballad.Path); strings.Contains(got, "```story") {
+		t.Errorf("ballad edition keeps its story block: %q", got)
+	}
+
+	// The chapter is the chapter of record
+	story, err := LoadStory(townRoot)
+	if err != nil || story.Chapter != 1 || story.Recap != "It failed." {
+		t.Errorf("story = %+v, %v; want the chapter's recap", story, err)
+	}
+	if records, err := LoadChapters(townRoot); err != nil || len(records) != 1 || records[0].Path != chapter.Path {
+		t.Errorf("chapter index = %+v, %v; want the chapter alone", records, err)
+	}
+	if usage, err := LoadUsage(townRoot); err != nil || len(usage.Entries) != 3 {
+		t.Errorf("usage = %+v, %v; want three agent runs", usage, err)
+	}
+}
+
+func TestGenerate_EditionOverQuota(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Write a book chapter.")
+	writeStyle(t, townRoot, "ballad", "Write a ballad.")
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{
+		Styles: []config.NarratorStyle{{Name: "ballad"}},
+		Quota:  &config.NarratorQuota{MaxPerHour: 1},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	runs := 0
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, _ []string) ([]byte, error) {
+		runs++
+		return []byte("The merge failed.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	out := filepath.Join(t.TempDir(), "release.md")
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude", Output: out})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if runs != 1 || len(chapter.Editions) != 1 || chapter.Editions[0].Path != "" || chapter.Editions[0].OverQuota == "" {
+		t.Errorf("runs = %d, Editions = %+v; want the ballad edition left unwritten over quota", runs, chapter.Editions)
+	}
+	if p, err := editionPath(townRoot, config.NarratorStyle{Name: "ballad"}, 1, time.Now(), out, "", ".md"); err != nil || p != strings.TrimSuffix(out, ".md")+"-ballad.md" {
+		t.Errorf("edition of an explicitly placed chapter = %q, %v", p, err)
+	}
+}
@@ -0,0 +1,134 @@
+package narrator
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// feedEntries is how many chapters the Atom feed carries.
+const feedEntries = 20
+
+// atomFeed is an Atom feed document (RFC 4287).
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+// atomTime formats t as an Atom date.
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// buildFeed renders the Atom feed of the newest chapters, newest first.
+// Each entry carries the chapter's excerpt and rendered body. Every date in
+// it comes from the chapters, so the feed only changes when they do.
+func buildFeed(cfg *config.NarratorFeed, town string, newestFirst []*siteChapter) ([]byte, error) {
+	if cfg == nil {
+		cfg = &config.NarratorFeed{}
+	}
+	base := strings.TrimSuffix(cfg.URL, "/")
+	link := func(file string) string {
+		if base == "" {
+			return file
+		}
+		return base + "/" + file
+	}
+	id := func(file string) string {
+		if base != "" {
+			return link(file)
+		}
+		return "urn:gastown:" + url.PathEscape(town) + ":" + file
+	}
+
+	feed := atomFeed{
+		ID:      id("feed.xml"),
+		Title:   cfg.Title,
+		Updated: atomTime(time.Unix(0, 0)),
+		Author:  atomPerson{Name: cfg.Author},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: link("feed.xml")},
+			{Rel: "alternate", Type: "text/html", Href: link("index.html")},
+		},
+	}
+	if feed.Title == "" {
+		feed.Title = "The history of " + town
+	}
+	if feed.Author.Name == "" {
+		feed.Author.Name = town
+	}
+
+	chapters := newestFirst
+	if len(chapters) > feedEntries {
+		chapters = chapters[:feedEntries]
+	}
+	for _, c := range chapters {
+		if c.Body == "" {
+			body, err := renderChapterBody(c)
+			if err != nil {
+				return nil, err
+			}
+			c.Body = body
+		}
+		entry := atomEntry{
+			ID:      id(c.URL),
+			Title:   c.Title,
+			Updated: atomTime(c.Until),
+			Links:   []atomLink{{Rel: "alternate", Type: "text/html", Href: link(c.URL)}},
+			Content: &atomText{Type: "html", Body: string(c.Body)},
+		}
+		if c.Excerpt != "" {
+			entry.Summary = &atomText{Body: c.Excerpt}
+		}
+		for _, rig := range c.Rigs {
+			entry.Categories = append(entry.Categories, atomCategory{Term: rig})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	if len(chapters) > 0 {
+		feed.Updated = atomTime(chapters[0].Until)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
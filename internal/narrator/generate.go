@@ -0,0 +1,748 @@
+package narrator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+)
+
+// DefaultStyle is the style narration uses unless another is chosen.
+const DefaultStyle = "default"
+
+// AgentRole is the role_agents key that picks the agent narrating.
+const AgentRole = "narrator"
+
+// maxPromptEvents caps the events in a prompt, keeping the newest, so it
+// stays within command-line argument limits.
+const maxPromptEvents = 400
+
+// logger records the narrator's progress through a chapter.
+var logger = gtlog.For("narrator")
+
+// ErrNoEvents indicates there was nothing to narrate.
+var ErrNoEvents = errors.New("no events to narrate")
+
+// GenerateOptions selects the events to narrate and how.
+type GenerateOptions struct {
+	Since        time.Time
+	Until        time.Time // zero means now
+	Rig          string
+	Style        string // "" uses DefaultStyle
+	Significance string // minimum scored significance; "" means medium
+	Agent        string // overrides role_agents["narrator"] and the default agent
+	Output       string // chapter path; "" writes to narrator.output_dir or narrator/chapter-<time>, with the format's extension
+	Strict       bool   // fail with a *LintError instead of writing a chapter with terminology issues
+	From         string // events file to narrate instead of the town's logs (see Replay)
+	Instance     string // named narrator generating; "" for the town's narrator (see LoadInstance)
+}
+
+// Chapter describes a generated narrative.
+type Chapter struct {
+	Number  int         `json:"number"` // chapter number in the town's story
+	Path    string      `json:"path"`
+	Style   string      `json:"style"`
+	Agent   string      `json:"agent"`
+	Since   time.Time   `json:"since"`
+	Until   time.Time   `json:"until"`
+	Events  int         `json:"events"`            // events (and incidents) in the prompt
+	Omitted int         `json:"omitted,omitempty"` // older events left out to fit the prompt
+	Words   int         `json:"words"`
+	Length  string      `json:"length,omitempty"` // how the chapter misses the style's length target
+	Issues  []LintIssue `json:"issues,omitempty"` // terminology issues found in the narrative
+	Commit  string      `json:"commit,omitempty"` // narrative branch commit, with narrator.git_output
+
+	Illustrations []Illustration `json:"illustrations,omitempty"` // with narrator.illustrations
+	Rigs          []string       `json:"rigs,omitempty"`          // rigs whose events it narrates
+	Site          string         `json:"site,omitempty"`          // site republished, with narrator.publish.auto
+	Digests       []string       `json:"digests,omitempty"`       // digests that fell due, with narrator.digest
+	Recap         string         `json:"recap,omitempty"`         // the narrator's recap of it
+	Threads       []string       `json:"threads,omitempty"`       // storylines it opened, continued or concluded
+	Editions      []Edition      `json:"editions,omitempty"`      // with narrator.styles
+
+	// With narrator.quota: the limit the chapter was over, if any, and how
+	// that was handled
+	OverQuota string `json:"over_quota,omitempty"`
+	Summary   bool   `json:"summary,omitempty"` // summarized without the agent
+	Dropped   int    `json:"dropped,omitempty"` // events below high significance left out
+}
+
+// Prompt is a narration request ready to hand to an agent.
+type Prompt struct {
+	Text    string
+	Style   string
+	Events  int
+	Omitted int
+
+	style    *Style
+	batch    *batch         // events the prompt was rendered from
+	story    *Story         // story state the prompt continues
+	personas *Personas      // cast the prompt describes
+	selected []events.Event // events in the prompt
+}
+
+// BuildPrompt selects and scores the events in range and renders the
+// prompt: the style, the personas of the actors involved, the glossary, the
+// story so far, the arcs of the work items involved, and the events. A
+// named narrator's settings fill in what opts leaves unset.
+func BuildPrompt(townRoot string, opts GenerateOptions) (*Prompt, error) {
+	if _, err := opts.ApplyInstance(townRoot); err != nil {
+		return nil, err
+	}
+	st, err := LoadStyle(townRoot, opts.Style)
+	if err != nil {
+		return nil, err
+	}
+	bt, err := selectBatch(townRoot, opts)
+	if err != nil {
+		return nil, err
+	}
+	return bt.prompt(townRoot, st)
+}
+
+// batch is the events a prompt narrates, selected and scored once so that
+// a chapter's editions in other styles (see writeEditions) narrate the same
+// events without scoring them again.
+type batch struct {
+	opts     GenerateOptions
+	records  []events.Record
+	works    []string // work item of each record (see events.Works)
+	involved []string // work items of the selected events
+	policy   *events.Policy
+	selected []events.Event
+	notes    map[string][]events.Annotation // operators' annotations of the selected events, by EventHash
+}
+
+// selectBatch selects the events in range at or above opts.Significance,
+// as the redaction policy lets the narrator see them, with the incidents
+// their bursts raise. It returns ErrNoEvents if there are none.
+func selectBatch(townRoot string, opts GenerateOptions) (*batch, error) {
+	level := opts.Significance
+	if level == "" {
+		level = events.SignificanceMedium
+	}
+	if err := events.ValidSignificance(level); err != nil {
+		return nil, err
+	}
+
+	filter := events.Filter{Since: opts.Since, Until: opts.Until, Rig: opts.Rig}
+	var records []events.Record
+	var err error
+	if opts.From != "" {
+		records, err = events.QueryFile(townRoot, opts.From, filter)
+	} else {
+		records, err = events.Query(townRoot, filter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Score every event so bursts are seen whole, but only show the
+	// narrator what the redaction policy lets it see
+	scorer := events.NewScorer(rules)
+	bt := &batch{opts: opts, records: records, works: events.Works(records), policy: policy, notes: make(map[string][]events.Annotation)}
+	for i, r := range records {
+		significant, incident := scorer.AtLeast(r.Event, level)
+		if e, visible := policy.Expose(r.Event, events.AudienceNarrator); significant && visible {
+			bt.selected = append(bt.selected, e)
+			if len(r.Annotations) > 0 {
+				bt.notes[events.EventHash(e)] = r.Annotations
+			}
+			if bt.works[i] != "" {
+				bt.involved = append(bt.involved, bt.works[i])
+			}
+		}
+		if incident != nil {
+			bt.selected = append(bt.selected, *incident)
+		}
+	}
+	if len(bt.selected) == 0 {
+		return nil, ErrNoEvents
+	}
+	return bt, nil
+}
+
+// prompt renders the batch's prompt in style st.
+func (bt *batch) prompt(townRoot string, st *Style) (*Prompt, error) {
+	opts := bt.opts
+	selected := bt.selected
+	p := &Prompt{Style: st.Name, style: st, batch: bt}
+	if limit := st.maxEvents(); len(selected) > limit {
+		p.Omitted = len(selected) - limit
+		selected = selected[p.Omitted:]
+	}
+	p.Events = len(selected)
+
+	personas, err := LoadPersonas(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	glossary, err := LoadGlossary(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	story, err := LoadStory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Earlier chapters the events recall, beyond the last one the story
+	// already recaps
+	idx, _, err := loadRecallIndex(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var recalled []Recollection
+	for _, r := range idx.search(recallQuery(selected), maxRecalled+1) {
+		if r.Path != story.LastChapter && len(recalled) < maxRecalled {
+			recalled = append(recalled, r)
+		}
+	}
+	p.story = story
+	p.personas = personas
+	p.selected = selected
+	tone := LoadTone(townRoot)
+	guide, err := st.Guide(StyleData{
+		Chapter: story.Chapter + 1,
+		Rig:     opts.Rig,
+		Since:   opts.Since,
+		Until:   opts.Until,
+		Events:  p.Events,
+
+		Tone:      tone.Name,
+		Intensity: tone.Intensity,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("You are the narrator of a Gas Town: a town of AI agents working on code. ")
+	fmt.Fprintf(&b, "Turn the events below into a chapter of the town's history, in %s, following the style guide. ", st.describeFormat())
+	b.WriteString("Reply with the chapter only.\n\n")
+	b.WriteString("## Style guide\n\n")
+	b.WriteString(guide)
+	b.WriteString("\n\n")
+	if target := st.lengthTarget(); target != "" {
+		b.WriteString(target + "\n\n")
+	}
+
+	writeTone(&b, tone)
+	writeCast(&b, castOf(personas, selected))
+	writeGlossary(&b, glossary)
+	writeStory(&b, story)
+	writeRecollections(&b, recalled)
+	writeArcs(&b, workArcs(bt.records, bt.works, bt.involved, bt.policy))
+
+	b.WriteString("## Events\n\n")
+	if len(bt.notes) > 0 {
+		b.WriteString("Notes under an event are annotations from the town's operators; take them as the truth about what it meant.\n\n")
+	}
+	if p.Omitted > 0 {
+		fmt.Fprintf(&b, "(%d earlier events omitted.)\n", p.Omitted)
+	}
+	for i, g := range groupEvents(selected, st.Chunking.GroupBy) {
+		if g.label != "" {
+			if i > 0 || p.Omitted > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "### %s\n\n", g.label)
+		}
+		for _, e := range g.events {
+			fmt.Fprintf(&b, "- %s %s %s", e.Timestamp, e.Type, e.Actor)
+			if payload := formatPayload(e.Payload); payload != "" {
+				b.WriteString(" " + payload)
+			}
+			b.WriteString("\n")
+			for _, a := range bt.notes[events.EventHash(e)] {
+				if a.Author != "" {
+					fmt.Fprintf(&b, "  - note from %s: %s\n", a.Author, a.Note)
+				} else {
+					fmt.Fprintf(&b, "  - note: %s\n", a.Note)
+				}
+			}
+		}
+	}
+	p.Text = b.String()
+	return p, nil
+}
+
+// writeCast adds the personas of the actors involved to a prompt.
+func writeCast(b *strings.Builder, cast []Persona) {
+	if len(cast) == 0 {
+		return
+	}
+	b.WriteString("## Cast\n\n")
+	for _, persona := range cast {
+		fmt.Fprintf(b, "- %s is %s", persona.Actor, persona.Name)
+		if persona.Pronouns != "" {
+			fmt.Fprintf(b, " (%s)", persona.Pronouns)
+		}
+		if len(persona.Traits) > 0 {
+			fmt.Fprintf(b, "; traits: %s", strings.Join(persona.Traits, ", "))
+		}
+		if len(persona.Gags) > 0 {
+			fmt.Fprintf(b, "; running gags: %s", strings.Join(persona.Gags, "; "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// writeGlossary adds the glossary's spellings and banned words to a prompt.
+func writeGlossary(b *strings.Builder, g *Glossary) {
+	if len(g.Terms) == 0 && len(g.Banned) == 0 {
+		return
+	}
+	b.WriteString("## Glossary\n\n")
+	for _, t := range g.Terms {
+		fmt.Fprintf(b, "- Spell it %q", t.Term)
+		if len(t.Variants) > 0 {
+			fmt.Fprintf(b, ", never %s", strings.Join(t.Variants, ", "))
+		}
+		if t.Note != "" {
+			fmt.Fprintf(b, " (%s)", t.Note)
+		}
+		b.WriteString("\n")
+	}
+	for _, w := range g.Banned {
+		fmt.Fprintf(b, "- Never use %q", w.Word)
+		if w.Prefer != "" {
+			fmt.Fprintf(b, "; say %q instead", w.Prefer)
+		}
+		if w.Reason != "" {
+			fmt.Fprintf(b, " (%s)", w.Reason)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// arc is the lifecycle of one work item: its sling, hook, done, merge and
+// whatever else shares its work ID (see events.ChainOf).
+type arc struct {
+	work  string
+	steps []events.Event
+}
+
+// workArcs returns the arcs of the work items involved (in order, possibly
+// repeated) that took more than one step, with steps as the narrator may
+// see them. works assigns records to work items (see events.Works). Steps
+// are drawn from all of records, so routine events left out of the prompt
+// still show how the work got where it did.
+func workArcs(records []events.Record, works, involved []string, policy *events.Policy) []arc {
+	var arcs []arc
+	index := make(map[string]int)
+	for _, work := range involved {
+		if _, ok := index[work]; !ok {
+			index[work] = len(arcs)
+			arcs = append(arcs, arc{work: work})
+		}
+	}
+	for i, r := range records {
+		n, ok := index[works[i]]
+		if !ok {
+			continue
+		}
+		if step, visible := policy.Expose(r.Event, events.AudienceNarrator); visible {
+			arcs[n].steps = append(arcs[n].steps, step)
+		}
+	}
+	multi := arcs[:0]
+	for _, a := range arcs {
+		if len(a.steps) > 1 {
+			multi = append(multi, a)
+		}
+	}
+	return multi
+}
+
+// writeArcs adds the work arcs to a prompt, so the chapter can follow each
+// piece of work from start to finish.
+func writeArcs(b *strings.Builder, arcs []arc) {
+	if len(arcs) == 0 {
+		return
+	}
+	b.WriteString("## Work arcs\n\n")
+	b.WriteString("Each line follows one piece of work from start to finish. Tell each as one story, not as separate happenings.\n\n")
+	for _, a := range arcs {
+		steps := make([]string, len(a.steps))
+		for i, e := range a.steps {
+			steps[i] = e.Type + " (" + e.Actor + ")"
+		}
+		fmt.Fprintf(b, "- %s: %s\n", a.work, strings.Join(steps, " → "))
+	}
+	b.WriteString("\n")
+}
+
+// castOf returns the personas of the actors in evts, sorted by actor.
+func castOf(personas *Personas, evts []events.Event) []Persona {
+	seen := make(map[string]bool)
+	var cast []Persona
+	for _, e := range evts {
+		if seen[e.Actor] {
+			continue
+		}
+		seen[e.Actor] = true
+		if persona, err := personas.Get(e.Actor); err == nil {
+			cast = append(cast, *persona)
+		}
+	}
+	sort.Slice(cast, func(i, j int) bool { return cast[i].Actor < cast[j].Actor })
+	return cast
+}
+
+// formatPayload renders a payload as sorted key=value pairs.
+func formatPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, payload[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ResolveAgent returns the name and runtime of the agent that narrates:
+// override if set, else role_agents["narrator"], else the default agent.
+func ResolveAgent(townRoot, override string) (string, *config.RuntimeConfig, error) {
+	rc, err := config.ResolveRoleAgentConfigWithOverride(AgentRole, townRoot, "", override)
+	if err != nil {
+		return "", nil, err
+	}
+	name := override
+	if name == "" {
+		name, _ = config.ResolveRoleAgentName(AgentRole, townRoot, "")
+	}
+	return name, rc, nil
+}
+
+// AgentCommand returns the command line that runs an agent once,
+// non-interactively, on prompt.
+func AgentCommand(name string, rc *config.RuntimeConfig, prompt string) ([]string, error) {
+	argv := append([]string{rc.Command}, rc.Args...)
+	preset := config.GetAgentPresetByName(name)
+	switch {
+	case preset != nil && preset.NonInteractive != nil:
+		ni := preset.NonInteractive
+		if ni.Subcommand != "" {
+			argv = append([]string{rc.Command, ni.Subcommand}, rc.Args...)
+		}
+		if ni.PromptFlag != "" {
+			argv = append(argv, ni.PromptFlag)
+		}
+		return append(argv, prompt), nil
+	case rc.Provider == "claude" || filepath.Base(rc.Command) == "claude":
+		// Claude is natively non-interactive with --print
+		return append(argv, "--print", prompt), nil
+	}
+	return nil, fmt.Errorf("agent %q has no non-interactive mode; choose another with --agent or role_agents.%s", name, AgentRole)
+}
+
+// runAgent runs an agent command in dir and returns its output.
+var runAgent = func(ctx context.Context, dir string, argv []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // G204: command comes from the town's agent config
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// Generate narrates the selected events in one shot, without a narrator
+// session: it builds the prompt, runs the narrating agent
+// non-interactively, checks its reply against the glossary, writes it as
+// a chapter, and carries the story state forward to the next one. With
+// narrator.git_output set the chapter is also committed to the narrative
+// branch, and with narrator.illustrations set its significant scenes are
+// written out as image prompts (see illustrate). The chapter is added to
+// the chapter index, and with narrator.publish.auto set the site is
+// republished (see Publish). A paused narrator generates nothing. With
+// narrator.digest set only high-significance events are narrated; a
+// town-wide chapter also writes the digests of the rest once they fall due
+// (see WriteDigests). With narrator.styles set the chapter is also written
+// in each of those styles from the same events (see writeEditions).
+//
+// Each agent run is added to the usage ledger. With narrator.quota set, a
+// chapter over quota is queued (a *QuotaError; the next town-wide chapter
+// starts from its events), narrowed to its high-significance events, or
+// summarized without the agent, as quota.over_quota says. Generations in
+// the same town run one at a time (see lockGeneration), so each sees the
+// usage and story the previous one left.
+func Generate(ctx context.Context, townRoot string, opts GenerateOptions) (*Chapter, error) {
+	lock, err := lockGeneration(ctx, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	inst, err := opts.ApplyInstance(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	state, err := LoadInstanceState(townRoot, opts.Instance)
+	if err != nil {
+		return nil, err
+	}
+	if state.Paused() {
+		return nil, pausedError(opts.Instance)
+	}
+	// A named narrator's chapters leave the town's queue and digests to
+	// the town's narrator
+	townWide := opts.Rig == "" && opts.From == "" && opts.Instance == ""
+	if townWide && !state.QueuedSince.IsZero() && state.QueuedSince.Before(opts.Since) {
+		logger.Info("narrating queued events", "since", state.QueuedSince)
+		opts.Since = state.QueuedSince
+	}
+
+	digestMode := digestSettings(townRoot) != nil
+	if digestMode {
+		// The rest goes into the digests
+		opts.Significance = events.SignificanceHigh
+	}
+
+	prompt, err := BuildPrompt(townRoot, opts)
+	if errors.Is(err, ErrNoEvents) && digestMode && townWide {
+		// Nothing to narrate, but the digests may be due
+		if _, err := dueDigests(townRoot, time.Now().UTC()); err != nil {
+			return nil, fmt.Errorf("writing digests: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("prompt built", "style", prompt.Style, "events", prompt.Events, "omitted", prompt.Omitted)
+	usage, err := LoadUsage(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var overQuota string
+	dropped := 0
+	if quota := quotaSettings(townRoot); quota != nil {
+		mode, err := overQuotaMode(quota)
+		if err != nil {
+			return nil, err
+		}
+		reason, retry := checkQuota(quota, usage, estimateTokens(prompt.Text), time.Now())
+		if reason != "" {
+			logger.Warn("narrator over quota", "reason", reason, "over_quota", mode)
+			switch mode {
+			case config.NarratorQuotaQueue:
+				if opts.Instance != "" {
+					// The town's queue is the town's narrator's
+					return nil, &QuotaError{Reason: reason, RetryAt: retry}
+				}
+				if err := queueSince(townRoot, opts.Since); err != nil {
+					return nil, err
+				}
+				return nil, &QuotaError{Reason: reason, RetryAt: retry, Queued: true}
+			case config.NarratorQuotaSummarize:
+				return summarize(townRoot, opts, prompt, reason)
+			}
+			// Narrate what matters most, if that fits the quota
+			high := opts
+			high.Significance = events.SignificanceHigh
+			narrowed, err := BuildPrompt(townRoot, high)
+			if errors.Is(err, ErrNoEvents) {
+				return nil, &QuotaError{Reason: reason, RetryAt: retry}
+			}
+			if err != nil {
+				return nil, err
+			}
+			if reason, retry := checkQuota(quota, usage, estimateTokens(narrowed.Text), time.Now()); reason != "" {
+				return nil, &QuotaError{Reason: reason, RetryAt: retry}
+			}
+			overQuota, dropped = reason, prompt.Events-narrowed.Events
+			prompt = narrowed
+		}
+	}
+	r, err := narrate(ctx, townRoot, opts.Agent, prompt)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Strict && len(r.issues) > 0 {
+		return nil, &LintError{Issues: r.issues}
+	}
+	text := r.text
+
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now().UTC()
+	}
+	rendered, ext, err := renderChapter(townRoot, text, prompt.style.Format, fmt.Sprintf("Chapter %d", prompt.story.Chapter+1), until)
+	if err != nil {
+		return nil, err
+	}
+	path := opts.Output
+	if path == "" {
+		data := OutputPathData{Rig: opts.Rig, N: prompt.story.Chapter + 1, Style: prompt.Style}
+		if opts.Instance != "" {
+			path, err = outputPath(townRoot, inst.OutputDir, InstanceDir(townRoot, opts.Instance), data, until, ext)
+		} else {
+			path, err = chapterPath(townRoot, data, until, ext)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating chapter dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(rendered+"\n"), 0644); err != nil { //nolint:gosec // G306: chapters are non-sensitive
+		return nil, fmt.Errorf("writing chapter: %w", err)
+	}
+	usage.add(UsageEntry{At: time.Now().UTC(), Chapter: prompt.story.Chapter + 1, Agent: r.agent,
+		Tokens: estimateTokens(prompt.Text) + estimateTokens(text)})
+	if err := usage.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("chapter written to %s, but saving usage ledger: %w", path, err)
+	}
+	if townWide {
+		if err := clearQueue(townRoot); err != nil {
+			return nil, fmt.Errorf("chapter written to %s, but %w", path, err)
+		}
+	}
+	// Editions are prompted with the story as it was for this chapter
+	editions, editionErr := writeEditions(ctx, townRoot, prompt.story.Chapter+1, until, prompt, usage, opts)
+	story := prompt.story
+	threads := r.update.threads(story)
+	story.Advance(path, prompt.selected, r.update)
+	if err := story.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("chapter written to %s, but saving story state: %w", path, err)
+	}
+	chapter := &Chapter{
+		Number:  story.Chapter,
+		Path:    path,
+		Style:   prompt.Style,
+		Agent:   r.agent,
+		Since:   opts.Since,
+		Until:   until,
+		Events:  prompt.Events,
+		Omitted: prompt.Omitted,
+		Words:   len(strings.Fields(text)),
+		Issues:  r.issues,
+		Rigs:    chapterRigs(prompt.selected),
+		Threads: threads,
+
+		Editions:  editions,
+		OverQuota: overQuota,
+		Dropped:   dropped,
+	}
+	if r.update != nil {
+		chapter.Recap = strings.TrimSpace(r.update.Recap)
+	}
+	if chapter.Length = prompt.style.CheckLength(chapter.Words); chapter.Length != "" {
+		logger.Warn("chapter misses the style's length target", "chapter", chapter.Number, "style", chapter.Style, "length", chapter.Length)
+	}
+	if chapter.Commit, err = commitChapter(townRoot, path, chapter.commitMessage()); err != nil {
+		return nil, fmt.Errorf("chapter written to %s, but %w", path, err)
+	}
+	logger.Info("chapter written", "chapter", chapter.Number, "path", path, "agent", chapter.Agent, "events", chapter.Events, "commit", chapter.Commit)
+	chapter.Illustrations, err = illustrate(ctx, townRoot, chapter, prompt)
+	if recErr := recordChapter(townRoot, chapter.record()); recErr != nil {
+		return chapter, fmt.Errorf("chapter written to %s, but recording it: %w", path, recErr)
+	}
+	if err := updateRecallIndex(townRoot); err != nil {
+		logger.Warn("updating recall index", "err", err)
+	}
+	if err != nil {
+		return chapter, fmt.Errorf("chapter written to %s, but illustrating it: %w", path, err)
+	}
+	if editionErr != nil {
+		return chapter, fmt.Errorf("chapter written to %s, but writing its editions: %w", path, editionErr)
+	}
+	if chapter.Site, err = autoPublish(townRoot); err != nil {
+		return chapter, fmt.Errorf("chapter written to %s, but publishing the site: %w", path, err)
+	}
+	if townWide {
+		digests, err := dueDigests(townRoot, time.Now().UTC())
+		for _, d := range digests {
+			chapter.Digests = append(chapter.Digests, d.Path)
+		}
+		if err != nil {
+			return chapter, fmt.Errorf("chapter written to %s, but writing digests: %w", path, err)
+		}
+	}
+	return chapter, nil
+}
+
+// Replay narrates like Generate but leaves the town untouched: the chapter
+// is returned instead of written and the story state is not advanced. It is
+// meant for tuning styles and significance rules against a saved events
+// file (opts.From), so a paused narrator still replays and Strict is
+// ignored.
+func Replay(ctx context.Context, townRoot string, opts GenerateOptions) (string, []LintIssue, error) {
+	prompt, err := BuildPrompt(townRoot, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	r, err := narrate(ctx, townRoot, opts.Agent, prompt)
+	if err != nil {
+		return "", nil, err
+	}
+	return r.text, r.issues, nil
+}
+
+// reply is a narrating agent's answer to a prompt.
+type reply struct {
+	agent  string
+	text   string       // the chapter, without the story block
+	update *StoryUpdate // nil if the agent gave none
+	issues []LintIssue
+}
+
+// narrate runs the narrating agent (agent, or the configured one) on
+// prompt and lints the chapter it returns.
+func narrate(ctx context.Context, townRoot, agent string, prompt *Prompt) (*reply, error) {
+	name, rc, err := ResolveAgent(townRoot, agent)
+	if err != nil {
+		return nil, err
+	}
+	argv, err := AgentCommand(name, rc, prompt.Text)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("running agent", "agent", name, "command", argv[0])
+	start := time.Now()
+	out, err := runAgent(ctx, townRoot, argv)
+	if err != nil {
+		return nil, fmt.Errorf("running %s: %w", name, err)
+	}
+	logger.Debug("agent replied", "agent", name, "bytes", len(out), "took", time.Since(start).Round(time.Millisecond))
+	text, update := SplitStoryUpdate(string(out))
+	if text == "" {
+		return nil, fmt.Errorf("%s returned no narrative", name)
+	}
+	issues, err := LintText(townRoot, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		logger.Info("terminology issues in narrative", "agent", name, "issues", len(issues))
+	}
+	return &reply{agent: name, text: text, update: update, issues: issues}, nil
+}
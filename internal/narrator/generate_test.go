@@ -0,0 +1,219 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func writeStyle(t *testing.T, townRoot, name, text string) {
+	t.Helper()
+	if err := os.MkdirAll(StylesDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(StylesDir(townRoot), name+".md"), []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildPrompt_Annotations(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`"type":"merge_failed","actor":"beads/refinery","payload":{"rig":"beads"}}`)
+	if _, err := events.Annotate(townRoot, "", 1, "root cause of the outage", "overseer"); err != nil {
+		t.Fatal(err)
+	}
+
+	prompt, err := BuildPrompt(townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	want := "merge_failed gastown/refinery rig=gastown\n  - note from overseer: root cause of the outage\n"
+	if !strings.Contains(prompt.Text, want) {
+		t.Errorf("prompt lacks the annotation under its event:\n%s", prompt.Text)
+	}
+	if strings.Count(prompt.Text, "note from") != 1 {
+		t.Errorf("annotation attached to the wrong events:\n%s", prompt.Text)
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, "tv-script", "Write it as a TV script.")
+	p := NewPersonas()
+	_ = p.Add(Persona{Actor: "gastown/refinery", Name: "The Refiner", Pronouns: "they/them"})
+	if err := p.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown","branch":"polecat/Toast"}}`,
+		`"type":"nudge","actor":"gastown/witness","payload":{"rig":"gastown"}}`,
+		`"type":"merged","actor":"beads/refinery","payload":{"rig":"beads"}}`)
+
+	prompt, err := BuildPrompt(townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Rig: "gastown", Style: "tv-script"})
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	if prompt.Events != 1 {
+		t.Errorf("prompt has %d events, want 1 (low significance and other rigs left out)", prompt.Events)
+	}
+	for _, want := range []string{"Write it as a TV script.", "gastown/refinery is The Refiner (they/them)", "merge_failed gastown/refinery branch=polecat/Toast rig=gastown"} {
+		if !strings.Contains(prompt.Text, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt.Text)
+		}
+	}
+	if strings.Contains(prompt.Text, "beads/refinery") || strings.Contains(prompt.Text, "nudge") {
+		t.Errorf("prompt includes filtered events:\n%s", prompt.Text)
+	}
+
+	if _, err := BuildPrompt(townRoot, GenerateOptions{Style: "sonnet"}); err == nil || !strings.Contains(err.Error(), "tv-script") {
+		t.Errorf("unknown style error = %v, want one listing the available styles", err)
+	}
+	if _, err := BuildPrompt(townRoot, GenerateOptions{Style: "../personas"}); err == nil {
+		t.Error("style outside the styles dir was accepted")
+	}
+	if _, err := BuildPrompt(townRoot, GenerateOptions{Style: "tv-script", Since: time.Now().Add(time.Hour)}); !errors.Is(err, ErrNoEvents) {
+		t.Errorf("empty range error = %v, want ErrNoEvents", err)
+	}
+}
+
+func TestBuildPrompt_WorkArcs(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/toast"}}`,
+		`"type":"hook","actor":"gastown/toast","payload":{"bead":"gt-1"}}`,
+		`"type":"done","actor":"gastown/toast","payload":{"bead":"gt-1","branch":"polecat/toast/gt-1"}}`,
+		`"type":"merged","actor":"gastown/refinery","payload":{"mr":"mr-1","branch":"polecat/toast/gt-1"}}`,
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-2","target":"gastown/nux"}}`)
+
+	prompt, err := BuildPrompt(townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Significance: events.SignificanceMedium})
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	want := "- gt-1: sling (mayor) → hook (gastown/toast) → done (gastown/toast) → merged (gastown/refinery)"
+	if !strings.Contains(prompt.Text, want) {
+		t.Errorf("prompt missing the gt-1 arc %q:\n%s", want, prompt.Text)
+	}
+	if strings.Contains(prompt.Text, "- gt-2:") {
+		t.Errorf("prompt has an arc for gt-2, which took one step:\n%s", prompt.Text)
+	}
+}
+
+func TestAgentCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   *config.RuntimeConfig
+		want []string
+	}{
+		{"claude", &config.RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}},
+			[]string{"claude", "--dangerously-skip-permissions", "--print", "P"}},
+		{"gemini", &config.RuntimeConfig{Command: "gemini", Args: []string{"--approval-mode", "yolo"}},
+			[]string{"gemini", "--approval-mode", "yolo", "-p", "P"}},
+		{"codex", &config.RuntimeConfig{Command: "codex", Args: []string{"--yolo"}},
+			[]string{"codex", "exec", "--yolo", "P"}},
+		{"claude-opus", &config.RuntimeConfig{Command: "claude", Args: []string{"--model", "opus"}},
+			[]string{"claude", "--model", "opus", "--print", "P"}},
+	}
+	for _, tt := range tests {
+		got, err := AgentCommand(tt.name, tt.rc, "P")
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AgentCommand(%s) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := AgentCommand("amp", &config.RuntimeConfig{Command: "amp"}, "P"); err == nil {
+		t.Error("AgentCommand(amp) succeeded, want an error for an agent without a non-interactive mode")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"sling","actor":"mayor","payload":{"bead":"gt-1"}}`)
+
+	var gotArgv []string
+	orig := runAgent
+	runAgent = func(_ context.Context, dir string, argv []string) ([]byte, error) {
+		gotArgv = argv
+		return []byte("The mayor slung gt-1.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	opts := GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"}
+	chapter, err := Generate(context.Background(), townRoot, opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(gotArgv) < 3 || gotArgv[0] != "claude" || gotArgv[len(gotArgv)-2] != "--print" ||
+		!strings.Contains(gotArgv[len(gotArgv)-1], "sling mayor bead=gt-1") {
+		t.Errorf("agent argv = %q", gotArgv)
+	}
+	data, err := os.ReadFile(chapter.Path)
+	if err != nil {
+		t.Fatalf("reading chapter: %v", err)
+	}
+	if string(data) != "The mayor slung gt-1.\n" || filepath.Dir(chapter.Path) != Dir(townRoot) || chapter.Events != 1 {
+		t.Errorf("chapter = %+v, contents %q", chapter, data)
+	}
+
+	if err := Pause(townRoot, "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(context.Background(), townRoot, opts); err == nil || !strings.Contains(err.Error(), "paused") {
+		t.Errorf("Generate while paused error = %v, want one saying the narrator is paused", err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	if err := Pause(townRoot, "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	from := filepath.Join(t.TempDir(), "incident.jsonl")
+	old := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	data := `{"ts":"` + old + `","source":"gt","type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}` + "\n" +
+		`{"ts":"` + old + `","source":"gt","type":"merge_failed","actor":"wyvern/refinery","payload":{"rig":"wyvern"}}` + "\n"
+	if err := os.WriteFile(from, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := GenerateOptions{From: from, Rig: "gastown", Agent: "claude"}
+	prompt, err := BuildPrompt(townRoot, opts)
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	if prompt.Events != 1 || !strings.Contains(prompt.Text, "merge_failed gastown/refinery") {
+		t.Errorf("prompt has %d events, want the gastown merge:\n%s", prompt.Events, prompt.Text)
+	}
+
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, _ []string) ([]byte, error) {
+		return []byte("The merge failed.\n```story\n{\"recap\":\"It failed.\"}\n```\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	text, _, err := Replay(context.Background(), townRoot, opts)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if text != "The merge failed." {
+		t.Errorf("text = %q", text)
+	}
+	if story, err := LoadStory(townRoot); err != nil || story.Chapter != 0 {
+		t.Errorf("story = %+v, %v; want replay to leave it alone", story, err)
+	}
+	if _, err := os.Stat(StoryPath(townRoot)); !os.IsNotExist(err) {
+		t.Errorf("replay wrote story state")
+	}
+}
@@ -0,0 +1,90 @@
+package narrator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// gitOutput returns the town's narrator.git_output setting, or nil if
+// chapters aren't committed.
+func gitOutput(townRoot string) *config.NarratorGitOutput {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.GitOutput
+}
+
+// commitChapter commits the chapter file at file to the town's narrative
+// branch, if narrator.git_output is set, and returns the commit ("" if
+// not configured).
+func commitChapter(townRoot, file, message string) (string, error) {
+	out := gitOutput(townRoot)
+	if out == nil {
+		return "", nil
+	}
+	branch := out.Branch
+	if branch == "" {
+		branch = config.DefaultNarrativeBranch
+	}
+	g := git.NewGit(townRoot)
+	if !g.IsRepo() {
+		return "", fmt.Errorf("narrator.git_output is set but %s is not a git repo", townRoot)
+	}
+	name := path.Join(filepath.ToSlash(out.Dir), filepath.Base(file))
+	commit, err := g.CommitFileToBranch(branch, name, file, message)
+	if err != nil {
+		return "", fmt.Errorf("committing chapter to %s: %w", branch, err)
+	}
+	return commit, nil
+}
+
+// commitMessage renders a chapter commit message: a subject line, then
+// key: value trailers describing what was narrated.
+func (c *Chapter) commitMessage() string {
+	var b strings.Builder
+	if c.Summary {
+		fmt.Fprintf(&b, "Summary: %d event(s) over quota\n\n", c.Events)
+	} else {
+		fmt.Fprintf(&b, "Chapter %d: %d event(s), %s style\n\n", c.Number, c.Events, c.Style)
+		fmt.Fprintf(&b, "Chapter: %d\n", c.Number)
+		fmt.Fprintf(&b, "Style: %s\n", c.Style)
+		fmt.Fprintf(&b, "Agent: %s\n", c.Agent)
+	}
+	if !c.Since.IsZero() {
+		fmt.Fprintf(&b, "Since: %s\n", c.Since.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "Until: %s\n", c.Until.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Events: %d\n", c.Events)
+	if c.Omitted > 0 {
+		fmt.Fprintf(&b, "Omitted: %d\n", c.Omitted)
+	}
+	if len(c.Issues) > 0 {
+		fmt.Fprintf(&b, "Lint-Issues: %d\n", len(c.Issues))
+	}
+	if c.OverQuota != "" {
+		fmt.Fprintf(&b, "Over-Quota: %s\n", c.OverQuota)
+	}
+	if c.Dropped > 0 {
+		fmt.Fprintf(&b, "Dropped: %d\n", c.Dropped)
+	}
+	return b.String()
+}
+
+// commitMessage renders a catch-up chapter commit message in the same
+// form as Chapter's.
+func (c *CatchUp) commitMessage() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Catch-up: %d event(s) while paused\n\n", c.Events)
+	fmt.Fprintf(&b, "Catch-up: %s\n", c.Mode)
+	fmt.Fprintf(&b, "Since: %s\n", c.Since.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Until: %s\n", c.Until.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Events: %d\n", c.Events)
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package narrator
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestGenerate_GitOutput(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{GitOutput: &config.NarratorGitOutput{Dir: "chapters"}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"sling","actor":"mayor","payload":{"bead":"gt-1"}}`)
+
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, _ []string) ([]byte, error) {
+		return []byte("The mayor slung gt-1.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if chapter.Commit == "" {
+		t.Fatal("chapter wasn't committed")
+	}
+
+	show := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = townRoot
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	if tip := show("rev-parse", config.DefaultNarrativeBranch); tip != chapter.Commit {
+		t.Errorf("narrative branch at %s, want the chapter commit %s", tip, chapter.Commit)
+	}
+	msg := show("log", "-1", "--format=%B", config.DefaultNarrativeBranch)
+	for _, want := range []string{"Chapter 1: 1 event(s), default style", "Chapter: 1", "Agent: claude", "Events: 1"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("commit message missing %q:\n%s", want, msg)
+		}
+	}
+	if got := show("show", config.DefaultNarrativeBranch+":chapters/"+filepath.Base(chapter.Path)); got != "The mayor slung gt-1." {
+		t.Errorf("committed chapter = %q", got)
+	}
+}
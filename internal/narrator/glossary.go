@@ -0,0 +1,414 @@
+package narrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GlossaryPath returns the path to a town's narrator glossary.
+func GlossaryPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "glossary.json")
+}
+
+// Term is a canonical spelling, with the misspellings to correct.
+type Term struct {
+	Term     string   `json:"term"`               // canonical spelling, e.g. "Gas Town"
+	Variants []string `json:"variants,omitempty"` // wrong spellings, matched exactly, e.g. "Gastown"
+	Note     string   `json:"note,omitempty"`     // what the term means, for the narrator
+}
+
+// BannedWord is a word or phrase narration must not use.
+type BannedWord struct {
+	Word   string `json:"word"`
+	Reason string `json:"reason,omitempty"`
+	Prefer string `json:"prefer,omitempty"` // what to say instead
+}
+
+// Glossary is a town's narration vocabulary: canonical spellings and banned
+// words. Persona names count as canonical spellings too.
+type Glossary struct {
+	Type    string       `json:"type"`    // "glossary"
+	Version int          `json:"version"` // schema version
+	Terms   []Term       `json:"terms,omitempty"`
+	Banned  []BannedWord `json:"banned,omitempty"`
+}
+
+// NewGlossary returns an empty glossary.
+func NewGlossary() *Glossary {
+	return &Glossary{Type: "glossary", Version: 1}
+}
+
+// LoadGlossary loads a town's glossary. A town without
+// narrator/glossary.json has an empty one.
+func LoadGlossary(townRoot string) (*Glossary, error) {
+	data, err := os.ReadFile(GlossaryPath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return NewGlossary(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading glossary: %w", err)
+	}
+	g := NewGlossary()
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, fmt.Errorf("parsing glossary: %w", err)
+	}
+	for _, t := range g.Terms {
+		if normalize(t.Term) == "" {
+			return nil, fmt.Errorf("parsing glossary: term %q has no letters", t.Term)
+		}
+	}
+	for _, b := range g.Banned {
+		if normalize(b.Word) == "" {
+			return nil, fmt.Errorf("parsing glossary: banned word %q has no letters", b.Word)
+		}
+	}
+	return g, nil
+}
+
+// LintText checks narration against the town's glossary and personas.
+func LintText(townRoot, text string) ([]LintIssue, error) {
+	glossary, err := LoadGlossary(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	personas, err := LoadPersonas(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return glossary.Lint(text, personas), nil
+}
+
+// Lint issue kinds.
+const (
+	IssueVariant      = "variant"      // a listed misspelling of a term
+	IssueSpelling     = "spelling"     // a term spelled differently, e.g. "GasTown"
+	IssueBanned       = "banned"       // a banned word
+	IssueInconsistent = "inconsistent" // a name spelled two ways
+)
+
+// LintIssue is a terminology problem in narration.
+type LintIssue struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`
+	Found   string `json:"found"`
+	Want    string `json:"want,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s", i.Line, i.Column, i.Message)
+}
+
+// LintError is returned when narration fails its terminology check.
+type LintError struct {
+	Issues []LintIssue
+}
+
+func (e *LintError) Error() string {
+	return fmt.Sprintf("narrative has %d terminology issue(s)", len(e.Issues))
+}
+
+// maxTermWords is the most words a term or banned phrase can span.
+const maxTermWords = 3
+
+// minNameLen is the shortest word checked for inconsistent spelling; shorter
+// names are too easily one letter from an ordinary word.
+const minNameLen = 4
+
+// Lint checks narration against the glossary and persona names: listed
+// misspellings, terms spelled another way ("GasTown" for "Gas Town"),
+// banned words, and names spelled two ways ("Furiosa" and "Furiossa").
+// Inline code is not checked. Issues are in text order.
+func (g *Glossary) Lint(text string, personas *Personas) []LintIssue {
+	terms := make(map[string]string)    // normalized spelling -> canonical
+	variants := make(map[string]string) // misspelling -> canonical
+	banned := make(map[string]BannedWord)
+	known := make(map[string]bool) // words of capitalized terms and names
+	addTerm := func(term string) {
+		if _, ok := terms[normalize(term)]; !ok {
+			terms[normalize(term)] = term
+		}
+		for _, w := range splitWords(term) {
+			if isCapitalized(w) {
+				known[w] = true
+			}
+		}
+	}
+	for _, t := range g.Terms {
+		addTerm(t.Term)
+		for _, v := range t.Variants {
+			variants[v] = t.Term
+		}
+	}
+	if personas != nil {
+		for _, p := range personas.List() {
+			addTerm(p.Name)
+		}
+	}
+	for _, b := range g.Banned {
+		banned[normalize(b.Word)] = b
+	}
+
+	var issues []LintIssue
+	tokens := tokenize(text)
+	matched := make([]bool, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		for n := maxTermWords; n >= 1; n-- {
+			literal, ok := span(tokens, i, n)
+			if !ok {
+				continue
+			}
+			key := normalize(literal)
+			issue := LintIssue{Line: tokens[i].line, Column: tokens[i].col, Found: literal}
+			if b, ok := banned[key]; ok {
+				issue.Kind, issue.Want = IssueBanned, b.Prefer
+				issue.Message = fmt.Sprintf("%q is banned", literal)
+				if b.Reason != "" {
+					issue.Message += " (" + b.Reason + ")"
+				}
+				if b.Prefer != "" {
+					issue.Message += fmt.Sprintf("; use %q", b.Prefer)
+				}
+				issues = append(issues, issue)
+			} else if want, ok := variants[literal]; ok {
+				issue.Kind, issue.Want = IssueVariant, want
+				issue.Message = fmt.Sprintf("%q should be %q", literal, want)
+				issues = append(issues, issue)
+			} else if want, ok := terms[key]; ok {
+				if !acceptableSpelling(literal, want) {
+					issue.Kind, issue.Want = IssueSpelling, want
+					issue.Message = fmt.Sprintf("%q should be spelled %q", literal, want)
+					issues = append(issues, issue)
+				}
+			} else {
+				continue
+			}
+			for j := i; j < i+n; j++ {
+				matched[j] = true
+			}
+			i += n - 1
+			break
+		}
+	}
+
+	issues = append(issues, inconsistentNames(tokens, matched, known)...)
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+// acceptableSpelling reports whether literal is a fine way to write the
+// term want: exactly, capitalized at the start of a sentence, or in lower
+// case for a proper noun (where it is usually an identifier, like the rig
+// "gastown").
+func acceptableSpelling(literal, want string) bool {
+	if literal == want {
+		return true
+	}
+	if strings.ToLower(literal) == literal && strings.ToLower(want) != want {
+		return true
+	}
+	return literal == upperFirst(want)
+}
+
+// inconsistentNames flags names that are one edit from a known name, or
+// from another name used in the text more often. Only words capitalized
+// mid-sentence count as names.
+func inconsistentNames(tokens []token, matched []bool, known map[string]bool) []LintIssue {
+	counts := make(map[string]int)
+	first := make(map[string]token)
+	var names []string
+	for i, t := range tokens {
+		w := t.word
+		if matched[i] || !t.midSentence || !isCapitalized(w) || utf8.RuneCountInString(w) < minNameLen {
+			continue
+		}
+		if counts[w] == 0 {
+			first[w] = t
+			names = append(names, w)
+		}
+		counts[w]++
+	}
+
+	var issues []LintIssue
+	for _, w := range names {
+		if known[w] {
+			continue
+		}
+		var want string
+		for k := range known {
+			if utf8.RuneCountInString(k) >= minNameLen && oneEditApart(w, k) && (want == "" || k < want) {
+				want = k
+			}
+		}
+		if want == "" {
+			// Compare with the other unknown names; the rarer spelling is
+			// the odd one out
+			for _, other := range names {
+				if other == w || known[other] || !oneEditApart(w, other) {
+					continue
+				}
+				if counts[other] > counts[w] || (counts[other] == counts[w] && first[other].before(first[w])) {
+					want = other
+					break
+				}
+			}
+		}
+		if want == "" {
+			continue
+		}
+		t := first[w]
+		issues = append(issues, LintIssue{
+			Line: t.line, Column: t.col, Kind: IssueInconsistent, Found: w, Want: want,
+			Message: fmt.Sprintf("%q is also spelled %q", want, w),
+		})
+	}
+	return issues
+}
+
+// oneEditApart reports whether a and b differ by one insertion, deletion,
+// substitution or transposition, ignoring plurals.
+func oneEditApart(a, b string) bool {
+	if a == b || a+"s" == b || b+"s" == a {
+		return false
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb)-len(ra) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+	if len(ra) == len(rb) {
+		if string(ra[i+1:]) == string(rb[i+1:]) {
+			return true // substitution
+		}
+		return i+1 < len(ra) && ra[i] == rb[i+1] && ra[i+1] == rb[i] && string(ra[i+2:]) == string(rb[i+2:])
+	}
+	return string(ra[i:]) == string(rb[i+1:]) // insertion
+}
+
+// token is a word in narration.
+type token struct {
+	word        string // without a trailing possessive 's
+	line, col   int    // 1-based; col counts runes
+	start, end  int    // byte offsets in the line
+	lineText    string
+	midSentence bool // not the first word of a sentence, heading or list item
+}
+
+func (t token) before(o token) bool {
+	return t.line < o.line || (t.line == o.line && t.col < o.col)
+}
+
+var (
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}'’]*`)
+	codeSpan    = regexp.MustCompile("`[^`]*`")
+)
+
+// tokenize splits text into words, skipping inline code and fenced code
+// blocks.
+func tokenize(text string) []token {
+	var tokens []token
+	inFence := false
+	for n, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = codeSpan.ReplaceAllStringFunc(line, func(s string) string { return strings.Repeat(" ", len(s)) })
+		for _, loc := range wordPattern.FindAllStringIndex(line, -1) {
+			word := strings.TrimRight(line[loc[0]:loc[1]], "'’")
+			word = strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s")
+			tokens = append(tokens, token{
+				word:        word,
+				line:        n + 1,
+				col:         utf8.RuneCountInString(line[:loc[0]]) + 1,
+				start:       loc[0],
+				end:         loc[0] + len(word),
+				lineText:    line,
+				midSentence: midSentence(line[:loc[0]]),
+			})
+		}
+	}
+	return tokens
+}
+
+// midSentence reports whether a word preceded by before on its line is
+// inside a sentence rather than starting one.
+func midSentence(before string) bool {
+	trimmed := strings.TrimRightFunc(before, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`"'“‘(*_`, r)
+	})
+	if trimmed == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(trimmed)
+	return !strings.ContainsRune(".!?:#>-", last)
+}
+
+// span returns the text of n tokens from i if they are joined only by
+// single spaces or hyphens on one line.
+func span(tokens []token, i, n int) (string, bool) {
+	if i+n > len(tokens) {
+		return "", false
+	}
+	for j := i + 1; j < i+n; j++ {
+		prev, cur := tokens[j-1], tokens[j]
+		if cur.line != prev.line {
+			return "", false
+		}
+		sep := cur.lineText[prev.end:cur.start]
+		if sep != " " && sep != "-" {
+			return "", false
+		}
+	}
+	return tokens[i].lineText[tokens[i].start:tokens[i+n-1].end], true
+}
+
+// normalize reduces a spelling to its lower-case letters and digits, so
+// "Gas Town", "GasTown" and "gas-town" compare equal.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// splitWords splits a term into its words.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+func isCapitalized(w string) bool {
+	r, _ := utf8.DecodeRuneInString(w)
+	return unicode.IsUpper(r)
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
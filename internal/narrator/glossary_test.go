@@ -0,0 +1,108 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testGlossary() *Glossary {
+	g := NewGlossary()
+	g.Terms = []Term{
+		{Term: "Gas Town", Variants: []string{"Gastown"}},
+		{Term: "polecat"},
+	}
+	g.Banned = []BannedWord{{Word: "bot", Reason: "agents are characters", Prefer: "agent"}}
+	return g
+}
+
+func TestGlossaryLint(t *testing.T) {
+	personas := NewPersonas()
+	_ = personas.Add(Persona{Actor: "gastown/polecats/furiosa", Name: "Furiosa"})
+
+	text := "# Chapter 1\n" +
+		"Night fell on Gastown. The refinery in GasTown hummed.\n" +
+		"Polecats came and went; a pole-cat named Furiosa took gt-12.\n" +
+		"Later Furiossa pushed, and the bot in the gastown rig merged it.\n" +
+		"Then Ricktus and Capable argued while Rictus watched Rictus's branch, and Rictus won.\n" +
+		"Nobody mentioned `GasTown` in code.\n"
+
+	var got []string
+	for _, issue := range testGlossary().Lint(text, personas) {
+		got = append(got, fmt.Sprintf("%s %s@%d:%d", issue.Kind, issue.Found, issue.Line, issue.Column))
+	}
+	want := []string{
+		"variant Gastown@2:15",
+		"spelling GasTown@2:40",
+		"spelling pole-cat@3:27",
+		"inconsistent Furiossa@4:7",
+		"banned bot@4:32",
+		"inconsistent Ricktus@5:6",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(want, "\n  "))
+	}
+}
+
+func TestLoadGlossary(t *testing.T) {
+	townRoot := t.TempDir()
+	g, err := LoadGlossary(townRoot)
+	if err != nil || len(g.Terms) != 0 {
+		t.Fatalf("LoadGlossary without a file = %+v, %v; want empty", g, err)
+	}
+
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GlossaryPath(townRoot), []byte(`{"terms":[{"term":"--"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGlossary(townRoot); err == nil {
+		t.Error("LoadGlossary accepted a term without letters")
+	}
+}
+
+func TestGenerate_Glossary(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"sling","actor":"mayor","payload":{"bead":"gt-1"}}`)
+	if err := os.WriteFile(GlossaryPath(townRoot), []byte(`{"terms":[{"term":"Gas Town","variants":["Gastown"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var prompt string
+	orig := runAgent
+	runAgent = func(_ context.Context, dir string, argv []string) ([]byte, error) {
+		prompt = argv[len(argv)-1]
+		return []byte("The mayor of Gastown slung gt-1.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	opts := GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"}
+	chapter, err := Generate(context.Background(), townRoot, opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(prompt, `Spell it "Gas Town", never Gastown`) {
+		t.Errorf("prompt has no glossary:\n%s", prompt)
+	}
+	if len(chapter.Issues) != 1 || chapter.Issues[0].Want != "Gas Town" {
+		t.Errorf("chapter issues = %+v, want the Gastown variant", chapter.Issues)
+	}
+
+	opts.Strict = true
+	opts.Output = Dir(townRoot) + "/strict.md"
+	_, err = Generate(context.Background(), townRoot, opts)
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) != 1 {
+		t.Errorf("strict Generate error = %v, want a LintError", err)
+	}
+	if _, err := os.Stat(opts.Output); !os.IsNotExist(err) {
+		t.Error("strict Generate wrote a chapter with terminology issues")
+	}
+}
@@ -0,0 +1,191 @@
+package narrator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// defaultIllustrationTimeout bounds a render command when
+// narrator.illustrations.timeout isn't set.
+const defaultIllustrationTimeout = 5 * time.Minute
+
+// Illustration is one illustrated scene of a chapter: an event, the
+// image-generation prompt written for it and, if the render command made
+// one, the image.
+type Illustration struct {
+	Scene  int    `json:"scene"`
+	Type   string `json:"type"`  // event depicted
+	Actor  string `json:"actor"` // its actor
+	Prompt string `json:"prompt"`
+	Image  string `json:"image,omitempty"`
+	Error  string `json:"error,omitempty"` // why rendering failed
+}
+
+// IllustrationsDir returns the directory a chapter's illustrations are
+// written to, beside the chapter.
+func IllustrationsDir(chapterPath string) string {
+	return filepath.Join(filepath.Dir(chapterPath), "illustrations")
+}
+
+// illustrations returns the town's narrator.illustrations setting, or nil
+// if chapters aren't illustrated.
+func illustrations(townRoot string) *config.NarratorIllustrations {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.Illustrations
+}
+
+// illustrate writes a prompt for each significant scene of a chapter to
+// its illustrations directory and, with a render command configured, runs
+// it per scene. A scene that fails to render is reported in its
+// Illustration rather than failing the rest. Returns nil if
+// narrator.illustrations isn't set.
+func illustrate(ctx context.Context, townRoot string, chapter *Chapter, prompt *Prompt) ([]Illustration, error) {
+	cfg := illustrations(townRoot)
+	if cfg == nil {
+		return nil, nil
+	}
+	level := cfg.Significance
+	if level == "" {
+		level = events.SignificanceHigh
+	}
+	if err := events.ValidSignificance(level); err != nil {
+		return nil, fmt.Errorf("narrator.illustrations: %w", err)
+	}
+	timeout := defaultIllustrationTimeout
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("narrator.illustrations: invalid timeout %q", cfg.Timeout)
+		}
+		timeout = d
+	}
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenes []events.Event
+	for _, e := range prompt.selected {
+		if rules.AtLeast(e, level) {
+			scenes = append(scenes, e)
+		}
+	}
+	limit := cfg.MaxScenes
+	if limit <= 0 {
+		limit = config.DefaultIllustrationScenes
+	}
+	if len(scenes) > limit {
+		scenes = scenes[len(scenes)-limit:]
+	}
+	if len(scenes) == 0 {
+		return nil, nil
+	}
+
+	dir := IllustrationsDir(chapter.Path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating illustrations dir: %w", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(chapter.Path), filepath.Ext(chapter.Path))
+	var out []Illustration
+	for i, e := range scenes {
+		name := fmt.Sprintf("%s-scene-%02d", base, i+1)
+		ill := Illustration{Scene: i + 1, Type: e.Type, Actor: e.Actor, Prompt: filepath.Join(dir, name+".txt")}
+		text := scenePrompt(chapter, prompt, e)
+		if err := os.WriteFile(ill.Prompt, []byte(text), 0644); err != nil { //nolint:gosec // G306: prompts are non-sensitive
+			return out, fmt.Errorf("writing illustration prompt: %w", err)
+		}
+		if cfg.Command != "" {
+			image := filepath.Join(dir, name+".png")
+			if err := renderScene(ctx, cfg.Command, timeout, chapter, ill, image, text); err != nil {
+				ill.Error = err.Error()
+				logger.Warn("illustration failed", "chapter", chapter.Number, "scene", ill.Scene, "err", err)
+			} else {
+				ill.Image = image
+			}
+		}
+		out = append(out, ill)
+	}
+	logger.Info("chapter illustrated", "chapter", chapter.Number, "scenes", len(out), "dir", dir)
+	return out, nil
+}
+
+// scenePrompt describes an event as a scene for an image generator: the
+// town, the chapter's style, who is involved and what happened.
+func scenePrompt(chapter *Chapter, prompt *Prompt, e events.Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "An illustration for chapter %d of the history of a Gas Town: a town of AI agents working on code, pictured as a frontier town.\n", chapter.Number)
+	if prompt.style != nil && prompt.style.Description != "" {
+		fmt.Fprintf(&b, "The chapter is written as %s; match its mood.\n", prompt.style.Description)
+	}
+	b.WriteString("\nScene: ")
+	who := e.Actor
+	if prompt.personas != nil {
+		if persona, err := prompt.personas.Get(e.Actor); err == nil {
+			who = persona.Name
+			if len(persona.Traits) > 0 {
+				who += " (" + strings.Join(persona.Traits, ", ") + ")"
+			}
+		}
+	}
+	fmt.Fprintf(&b, "%s, %s", who, strings.ReplaceAll(e.Type, "_", " "))
+	if rig, _ := e.Payload["rig"].(string); rig != "" {
+		fmt.Fprintf(&b, ", in the %s rig", rig)
+	}
+	b.WriteString(".\n")
+	if payload := formatPayload(e.Payload); payload != "" {
+		fmt.Fprintf(&b, "Details: %s\n", payload)
+	}
+	b.WriteString("\nNo text or lettering in the image.\n")
+	return b.String()
+}
+
+// renderScene runs the render command for one scene, in the illustrations
+// directory, and checks it wrote the image.
+func renderScene(ctx context.Context, command string, timeout time.Duration, chapter *Chapter, ill Illustration, image, text string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command comes from town settings
+	cmd.Dir = filepath.Dir(ill.Prompt)
+	cmd.Env = append(os.Environ(),
+		"GT_ILLUSTRATION_PROMPT="+ill.Prompt,
+		"GT_ILLUSTRATION_IMAGE="+image,
+		"GT_ILLUSTRATION_SCENE="+strconv.Itoa(ill.Scene),
+		"GT_NARRATOR_CHAPTER="+strconv.Itoa(chapter.Number),
+		"GT_NARRATOR_STYLE="+chapter.Style,
+	)
+	cmd.Stdin = strings.NewReader(text)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Don't wait on children that outlive a killed command and hold its output open
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		return err
+	}
+	if _, err := os.Stat(image); err != nil {
+		return errors.New("render command wrote no image to $GT_ILLUSTRATION_IMAGE")
+	}
+	return nil
+}
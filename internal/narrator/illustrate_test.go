@@ -0,0 +1,89 @@
+package narrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestGenerate_Illustrations(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Illustrations: &config.NarratorIllustrations{
+		Significance: "medium",
+		// Render the first scene; fail the second
+		Command: `test "$GT_ILLUSTRATION_SCENE" = 1 && cat > "$GT_ILLUSTRATION_IMAGE"`,
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`"type":"merge_failed","actor":"beads/refinery","payload":{"rig":"beads"}}`)
+	personas := NewPersonas()
+	if err := personas.Add(Persona{Actor: "gastown/refinery", Name: "Old Gus", Traits: []string{"gruff"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := personas.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("Two merges failed.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(chapter.Illustrations) != 2 {
+		t.Fatalf("illustrations = %+v, want 2 scenes", chapter.Illustrations)
+	}
+	first, second := chapter.Illustrations[0], chapter.Illustrations[1]
+	if filepath.Dir(first.Prompt) != IllustrationsDir(chapter.Path) {
+		t.Errorf("prompt written to %s, want %s", first.Prompt, IllustrationsDir(chapter.Path))
+	}
+	prompt, err := os.ReadFile(first.Prompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(prompt), "Old Gus (gruff), merge failed, in the gastown rig") {
+		t.Errorf("scene prompt doesn't describe the event:\n%s", prompt)
+	}
+	if image, err := os.ReadFile(first.Image); err != nil || string(image) != string(prompt) {
+		t.Errorf("rendered image = %q, %v; want the prompt piped to the command", image, err)
+	}
+	if second.Image != "" || second.Error == "" {
+		t.Errorf("second scene = %+v, want its render failure reported", second)
+	}
+}
+
+func TestGenerate_NoIllustrations(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("A merge failed.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(chapter.Illustrations) != 0 {
+		t.Errorf("illustrated without narrator.illustrations: %+v", chapter.Illustrations)
+	}
+	if _, err := os.Stat(IllustrationsDir(chapter.Path)); !os.IsNotExist(err) {
+		t.Error("illustrations dir created without narrator.illustrations")
+	}
+}
@@ -0,0 +1,138 @@
+package narrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// instanceNameRe is what a narrator instance may be called: its session
+// and state file are named after it.
+var instanceNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// CheckInstanceName checks that name can name a narrator instance.
+func CheckInstanceName(name string) error {
+	if !instanceNameRe.MatchString(name) {
+		return fmt.Errorf("invalid narrator name %q (want lowercase letters, digits and dashes)", name)
+	}
+	return nil
+}
+
+// InstanceStatePath returns the path to a named narrator's run state, or
+// the town's narrator's for "".
+func InstanceStatePath(townRoot, name string) string {
+	if name == "" {
+		return filepath.Join(Dir(townRoot), "state.json")
+	}
+	return filepath.Join(Dir(townRoot), "narrator-"+name+".json")
+}
+
+// InstanceDir returns where a named narrator's chapters are written
+// unless its output_dir says otherwise.
+func InstanceDir(townRoot, name string) string {
+	return filepath.Join(Dir(townRoot), name)
+}
+
+// LoadInstance returns a named narrator's settings: its entry in
+// narrator.instances, or the defaults if it has none.
+func LoadInstance(townRoot, name string) (config.NarratorInstance, error) {
+	if err := CheckInstanceName(name); err != nil {
+		return config.NarratorInstance{}, err
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return config.NarratorInstance{}, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Narrator == nil {
+		return config.NarratorInstance{}, nil
+	}
+	return settings.Narrator.Instances[name], nil
+}
+
+// Instances returns the names of the town's configured narrator
+// instances, sorted.
+func Instances(townRoot string) []string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	names := make([]string, 0, len(settings.Narrator.Instances))
+	for name := range settings.Narrator.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyInstance fills in what o leaves unset from the settings of its
+// named narrator, and returns them. Generate and BuildPrompt apply them
+// themselves.
+func (o *GenerateOptions) ApplyInstance(townRoot string) (config.NarratorInstance, error) {
+	if o.Instance == "" {
+		return config.NarratorInstance{}, nil
+	}
+	inst, err := LoadInstance(townRoot, o.Instance)
+	if err != nil {
+		return inst, err
+	}
+	if o.Rig == "" {
+		o.Rig = inst.Rig
+	}
+	if o.Style == "" {
+		o.Style = inst.Style
+	}
+	if o.Agent == "" {
+		o.Agent = inst.Agent
+	}
+	return inst, nil
+}
+
+// pausedError is the error narrating with a paused narrator.
+func pausedError(name string) error {
+	if name == "" {
+		return fmt.Errorf("narrator is paused; run 'gt narrator resume' first")
+	}
+	return fmt.Errorf("narrator %s is paused; run 'gt narrator resume --name %s' first", name, name)
+}
+
+// InstanceBacklog returns the events written since a named narrator was
+// paused, or nil when it is running.
+func InstanceBacklog(townRoot, name string) ([]events.Record, error) {
+	s, err := LoadInstanceState(townRoot, name)
+	if err != nil || !s.Paused() {
+		return nil, err
+	}
+	return events.Query(townRoot, events.Filter{Since: s.PausedAt})
+}
+
+// ResumeInstance restarts a named narrator. Its backlog is skipped:
+// catch-up chapters are the town's narrator's to write, and the instance
+// carries on from its next chapter.
+func ResumeInstance(townRoot, name string) error {
+	if err := CheckInstanceName(name); err != nil {
+		return err
+	}
+	s, err := LoadInstanceState(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if !s.Paused() {
+		return ErrNotPaused
+	}
+	s.State = agent.StateRunning
+	s.PausedAt = time.Time{}
+	s.PausedBy = ""
+	s.Reason = ""
+	s.NarratedThrough = time.Now().UTC()
+	if err := s.SaveInstance(townRoot, name); err != nil {
+		return err
+	}
+	logger.Info("narrator resumed", "name", name, "mode", config.NarratorResumeSkip)
+	return nil
+}
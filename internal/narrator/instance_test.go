@@ -0,0 +1,125 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestInstanceState(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := PauseInstance(townRoot, "changelog", "quiet week", "human"); err != nil {
+		t.Fatalf("PauseInstance: %v", err)
+	}
+	if got := InstanceStatePath(townRoot, "changelog"); got != filepath.Join(townRoot, "narrator", "narrator-changelog.json") {
+		t.Errorf("InstanceStatePath = %s", got)
+	}
+	if paused, err := IsPaused(townRoot); err != nil || paused {
+		t.Errorf("town narrator paused = %v, %v; want it left running", paused, err)
+	}
+	s, err := LoadInstanceState(townRoot, "changelog")
+	if err != nil || !s.Paused() || s.Reason != "quiet week" {
+		t.Fatalf("instance state = %+v, %v", s, err)
+	}
+	if err := PauseInstance(townRoot, "changelog", "", "human"); !errors.Is(err, ErrAlreadyPaused) {
+		t.Errorf("second pause error = %v, want ErrAlreadyPaused", err)
+	}
+	if err := ResumeInstance(townRoot, "changelog"); err != nil {
+		t.Fatalf("ResumeInstance: %v", err)
+	}
+	if s, err := LoadInstanceState(townRoot, "changelog"); err != nil || s.Paused() || s.NarratedThrough.IsZero() {
+		t.Errorf("resumed state = %+v, %v", s, err)
+	}
+	if err := ResumeInstance(townRoot, "changelog"); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("second resume error = %v, want ErrNotPaused", err)
+	}
+	if err := PauseInstance(townRoot, "../x", "", "human"); err == nil {
+		t.Error("PauseInstance with an invalid name succeeded")
+	}
+	for _, name := range []string{"", "Change Log", "-x", "../x"} {
+		if err := CheckInstanceName(name); err == nil {
+			t.Errorf("CheckInstanceName(%q) succeeded", name)
+		}
+	}
+}
+
+func TestGenerate_Instance(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Write a book chapter.")
+	writeStyle(t, townRoot, "changelog", "Write a changelog.")
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Instances: map[string]config.NarratorInstance{
+		"changelog": {Rig: "gastown", Style: "changelog"},
+		"beads":     {Rig: "beads"},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(Instances(townRoot), ","); got != "beads,changelog" {
+		t.Errorf("Instances = %q", got)
+	}
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`"type":"merge_failed","actor":"beads/refinery","payload":{"rig":"beads"}}`)
+	var prompt string
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompt = argv[len(argv)-1]
+		return []byte("Fixed: the merge.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	// The town's narrator's pause is its own
+	if err := Pause(townRoot, "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	opts := GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude", Instance: "changelog"}
+	chapter, err := Generate(context.Background(), townRoot, opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !strings.Contains(prompt, "Write a changelog.") || !strings.Contains(prompt, "gastown/refinery") || strings.Contains(prompt, "beads/refinery") {
+		t.Errorf("prompt isn't the changelog narrator's:\n%s", prompt)
+	}
+	if chapter.Style != "changelog" || filepath.Dir(chapter.Path) != InstanceDir(townRoot, "changelog") {
+		t.Errorf("chapter = %+v, want a changelog chapter in narrator/changelog/", chapter)
+	}
+
+	if err := PauseInstance(townRoot, "changelog", "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(context.Background(), townRoot, opts); err == nil || !strings.Contains(err.Error(), "resume --name changelog") {
+		t.Errorf("Generate with the instance paused: %v", err)
+	}
+	if _, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Instance: "Bad Name"}); err == nil {
+		t.Error("Generate for an invalid instance name succeeded")
+	}
+}
+
+func TestInstanceManager(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := NewManager(townRoot).SessionName(); got != "hq-narrator" {
+		t.Errorf("town narrator session = %q", got)
+	}
+	mgr, err := NewInstanceManager(townRoot, "changelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mgr.SessionName() != "hq-narrator-changelog" || mgr.Name() != "changelog" {
+		t.Errorf("instance session = %q, name %q", mgr.SessionName(), mgr.Name())
+	}
+	if _, err := NewInstanceManager(townRoot, "no/slashes"); err == nil {
+		t.Error("NewInstanceManager with an invalid name succeeded")
+	}
+	if err := PauseInstance(townRoot, "changelog", "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Start(""); err == nil || !strings.Contains(err.Error(), "paused") {
+		t.Errorf("Start of a paused instance: %v", err)
+	}
+}
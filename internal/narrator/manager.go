@@ -0,0 +1,157 @@
+package narrator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Common errors
+var (
+	ErrNotRunning     = errors.New("narrator not running")
+	ErrAlreadyRunning = errors.New("narrator already running")
+)
+
+// Manager handles the narrator session's lifecycle. It is the one place
+// the session is started and stopped: 'gt narrator start' and 'stop'
+// delegate to it.
+type Manager struct {
+	townRoot string
+	name     string // named instance; "" for the town's narrator
+}
+
+// NewManager creates a new narrator manager for a town.
+func NewManager(townRoot string) *Manager {
+	return &Manager{
+		townRoot: townRoot,
+	}
+}
+
+// NewInstanceManager creates a manager for a named narrator instance, or
+// the town's narrator for "".
+func NewInstanceManager(townRoot, name string) (*Manager, error) {
+	if name != "" {
+		if err := CheckInstanceName(name); err != nil {
+			return nil, err
+		}
+	}
+	return &Manager{
+		townRoot: townRoot,
+		name:     name,
+	}, nil
+}
+
+// Name returns the instance the manager handles, "" for the town's
+// narrator.
+func (m *Manager) Name() string {
+	return m.name
+}
+
+// SessionName returns the tmux session name for the narrator.
+func (m *Manager) SessionName() string {
+	if m.name != "" {
+		return session.NarratorInstanceSessionName(m.name)
+	}
+	return session.NarratorSessionName()
+}
+
+// Start starts the narrator session, running from the narrator directory
+// so the agent works on its state. A paused narrator isn't started.
+// agentOverride allows specifying an alternate agent alias (e.g., for
+// testing); a named instance otherwise runs its configured agent, and its
+// session has GT_NARRATOR set to its name.
+func (m *Manager) Start(agentOverride string) error {
+	state, err := LoadInstanceState(m.townRoot, m.name)
+	if err != nil {
+		return err
+	}
+	if state.Paused() {
+		return pausedError(m.name)
+	}
+	displayName := "Narrator"
+	if m.name != "" {
+		inst, err := LoadInstance(m.townRoot, m.name)
+		if err != nil {
+			return err
+		}
+		if agentOverride == "" {
+			agentOverride = inst.Agent
+		}
+		displayName += " (" + m.name + ")"
+	}
+
+	mux := tmux.NewMultiplexer(m.townRoot)
+	t, isTmux := mux.(*tmux.Tmux)
+	sessionID := m.SessionName()
+
+	// Check if session already exists
+	running, _ := mux.HasSession(sessionID)
+	if running {
+		// Session exists - check if the agent is actually running (healthy
+		// vs zombie). Only tmux can inspect the pane, so other
+		// multiplexers trust the session.
+		if !isTmux || t.IsClaudeRunning(sessionID) {
+			return ErrAlreadyRunning
+		}
+		// Zombie - tmux alive but the agent dead. Kill and recreate.
+		logger.Warn("killing zombie session", "session", sessionID)
+		if err := t.KillSession(sessionID); err != nil {
+			return fmt.Errorf("killing zombie session: %w", err)
+		}
+	}
+
+	if err := session.RunStartup(session.StartupSpec{
+		Role:          AgentRole,
+		TownRoot:      m.townRoot,
+		Dir:           Dir(m.townRoot),
+		Session:       sessionID,
+		AgentOverride: agentOverride,
+		AgentName:     m.name,
+		Theme:         tmux.NarratorTheme(),
+		DisplayName:   displayName,
+		Focus:         "history",
+		Nudge: session.StartupNudgeConfig{
+			Recipient: "narrator",
+			Sender:    "human",
+			Topic:     "narrate",
+		},
+	}); err != nil {
+		return err
+	}
+	logger.Info("session started", "session", sessionID, "name", m.name, "agent", agentOverride)
+	return nil
+}
+
+// Stop stops the narrator session.
+func (m *Manager) Stop() error {
+	mux := tmux.NewMultiplexer(m.townRoot)
+	sessionID := m.SessionName()
+
+	running, err := mux.HasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !running {
+		return ErrNotRunning
+	}
+
+	// Try graceful shutdown first (best-effort interrupt)
+	if t, ok := mux.(*tmux.Tmux); ok {
+		_ = t.SendKeysRaw(sessionID, "C-c")
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if err := mux.KillSession(sessionID); err != nil {
+		return fmt.Errorf("killing session: %w", err)
+	}
+	logger.Info("session stopped", "session", sessionID)
+	return nil
+}
+
+// IsRunning checks if the narrator session is active.
+func (m *Manager) IsRunning() (bool, error) {
+	return tmux.NewMultiplexer(m.townRoot).HasSession(m.SessionName())
+}
@@ -0,0 +1,94 @@
+package narrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// OutputPathData is what a narrator.output_dir template is rendered with.
+type OutputPathData struct {
+	Date  string // chapter's end, 2006-01-02 local time
+	Time  string // chapter's end, 150405 local time
+	Rig   string // the rig narrated, or "town" for a town-wide chapter
+	N     int    // chapter number
+	Style string
+}
+
+// CheckRigName checks that rig can name a rig in a chapter's path: the
+// {{.Rig}} of an output_dir template mustn't lead out of the directory it
+// names.
+func CheckRigName(rig string) error {
+	if strings.ContainsAny(rig, `/\`) || strings.HasPrefix(rig, ".") {
+		return fmt.Errorf("invalid rig name %q", rig)
+	}
+	return nil
+}
+
+// outputDir returns the town's narrator.output_dir, or "".
+func outputDir(townRoot string) string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return ""
+	}
+	return settings.Narrator.OutputDir
+}
+
+// parseOutputDir parses a narrator.output_dir template and checks that it
+// renders.
+func parseOutputDir(tmplText string) (*template.Template, error) {
+	tmpl, err := template.New("output_dir").Option("missingkey=error").Parse(tmplText)
+	if err != nil {
+		return nil, fmt.Errorf("narrator.output_dir: %w", err)
+	}
+	if err := tmpl.Execute(&strings.Builder{}, OutputPathData{}); err != nil {
+		return nil, fmt.Errorf("narrator.output_dir: %w", err)
+	}
+	return tmpl, nil
+}
+
+// chapterPath returns where a chapter is written: narrator.output_dir
+// rendered for it (relative to the town root unless absolute), or
+// narrator/chapter-<time><ext> without one. A rendered path with an
+// extension names the chapter file, which gets ext in place of its own;
+// one without names the directory it goes in.
+func chapterPath(townRoot string, data OutputPathData, until time.Time, ext string) (string, error) {
+	return outputPath(townRoot, outputDir(townRoot), Dir(townRoot), data, until, ext)
+}
+
+// outputPath renders the path template tmplText for a chapter as
+// chapterPath does, with dir as the chapter's directory if tmplText is "".
+func outputPath(townRoot, tmplText, dir string, data OutputPathData, until time.Time, ext string) (string, error) {
+	if err := CheckRigName(data.Rig); err != nil {
+		return "", err
+	}
+	name := "chapter-" + until.Format("20060102-150405") + ext
+	if tmplText == "" {
+		return filepath.Join(dir, name), nil
+	}
+	tmpl, err := parseOutputDir(tmplText)
+	if err != nil {
+		return "", err
+	}
+	local := until.Local()
+	data.Date, data.Time = local.Format("2006-01-02"), local.Format("150405")
+	if data.Rig == "" {
+		data.Rig = townRig
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("narrator.output_dir: %w", err)
+	}
+	path := filepath.FromSlash(strings.TrimSpace(b.String()))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(townRoot, path)
+	}
+	if old := filepath.Ext(path); old != "" {
+		return strings.TrimSuffix(path, old) + ext, nil
+	}
+	return filepath.Join(path, name), nil
+}
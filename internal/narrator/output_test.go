@@ -0,0 +1,82 @@
+package narrator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestChapterPath(t *testing.T) {
+	townRoot := t.TempDir()
+	until := time.Date(2026, 3, 1, 12, 30, 0, 0, time.Local)
+	data := OutputPathData{Rig: "gastown", N: 7, Style: "saga"}
+	tests := []struct {
+		name, tmpl, ext, want string
+	}{
+		{"default", "", ".md", filepath.Join(Dir(townRoot), "chapter-"+until.Format("20060102-150405")+".md")},
+		{"file", "history/{{.Date}}/{{.Rig}}/chapter-{{.N}}.md", ".md", filepath.Join(townRoot, "history/2026-03-01/gastown/chapter-7.md")},
+		{"extension follows the format", "history/{{.Style}}-{{.N}}.md", ".html", filepath.Join(townRoot, "history/saga-7.html")},
+		{"directory", "history/{{.Date}}", ".txt", filepath.Join(townRoot, "history/2026-03-01/chapter-20260301-123000.txt")},
+		{"absolute", "/srv/story/{{.Time}}.md", ".md", "/srv/story/123000.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Set(townRoot, "output_dir", tt.tmpl); err != nil {
+				t.Fatalf("Set output_dir: %v", err)
+			}
+			got, err := chapterPath(townRoot, data, until, tt.ext)
+			if err != nil {
+				t.Fatalf("chapterPath: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("chapterPath = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"history/{{.Date", "history/{{.Month}}"} {
+		if err := Set(townRoot, "output_dir", bad); err == nil {
+			t.Errorf("Set output_dir %q succeeded", bad)
+		}
+	}
+
+	if err := Set(townRoot, "output_dir", "history/{{.Rig}}/chapter-{{.N}}.md"); err != nil {
+		t.Fatal(err)
+	}
+	for _, rig := range []string{"../../..", "..", ".hidden", "a/b", `a\b`} {
+		if got, err := chapterPath(townRoot, OutputPathData{Rig: rig, N: 1}, until, ".md"); err == nil {
+			t.Errorf("chapterPath for rig %q = %s, want an error", rig, got)
+		}
+	}
+}
+
+func TestGenerate_OutputDir(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{OutputDir: "history/{{.Rig}}/chapter-{{.N}}.md"}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("# A Failed Merge\n\nThe merge failed.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	for i, rig := range []string{"", "gastown"} {
+		chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Rig: rig, Agent: "claude"})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		want := filepath.Join(townRoot, "history", []string{"town", "gastown"}[i], []string{"chapter-1.md", "chapter-2.md"}[i])
+		if chapter.Path != want {
+			t.Errorf("chapter written to %s, want %s", chapter.Path, want)
+		}
+		readFile(t, chapter.Path)
+	}
+}
@@ -0,0 +1,132 @@
+// Package narrator holds state the narrator keeps in the town's narrator/
+// directory.
+package narrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ErrPersonaNotFound indicates no persona is registered for an actor.
+var ErrPersonaNotFound = errors.New("persona not found")
+
+// Dir returns the town's narrator directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "narrator")
+}
+
+// PersonasPath returns the path to a town's persona registry.
+func PersonasPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "personas.json")
+}
+
+// Persona is how the narrator portrays an actor, so the same agent reads as
+// the same character from one chapter to the next.
+type Persona struct {
+	Actor    string   `json:"actor"`              // event actor, e.g. "gastown/polecats/Toast"
+	Name     string   `json:"name"`               // name used in prose
+	Pronouns string   `json:"pronouns,omitempty"` // e.g. "she/her"
+	Traits   []string `json:"traits,omitempty"`   // personality traits
+	Gags     []string `json:"gags,omitempty"`     // running gags
+}
+
+// Personas is a town's persona registry.
+type Personas struct {
+	Type     string              `json:"type"`    // "personas"
+	Version  int                 `json:"version"` // schema version
+	Personas map[string]*Persona `json:"personas"`
+}
+
+// NewPersonas returns an empty registry.
+func NewPersonas() *Personas {
+	return &Personas{Type: "personas", Version: 1, Personas: make(map[string]*Persona)}
+}
+
+// LoadPersonas loads a town's persona registry. A town without
+// narrator/personas.json has an empty registry.
+func LoadPersonas(townRoot string) (*Personas, error) {
+	data, err := os.ReadFile(PersonasPath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return NewPersonas(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading personas: %w", err)
+	}
+	p := NewPersonas()
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("parsing personas: %w", err)
+	}
+	if p.Personas == nil {
+		p.Personas = make(map[string]*Persona)
+	}
+	for actor, persona := range p.Personas {
+		persona.Actor = actor
+	}
+	return p, nil
+}
+
+// Save writes the registry to the town's narrator directory.
+func (p *Personas) Save(townRoot string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	return util.AtomicWriteJSON(PersonasPath(townRoot), p)
+}
+
+// Add registers a persona. Fails if the actor already has one.
+func (p *Personas) Add(persona Persona) error {
+	persona.Actor = strings.TrimSuffix(persona.Actor, "/")
+	if persona.Actor == "" || strings.TrimSpace(persona.Name) == "" {
+		return fmt.Errorf("persona needs an actor and a name")
+	}
+	if _, ok := p.Personas[persona.Actor]; ok {
+		return fmt.Errorf("%s already has a persona (use edit)", persona.Actor)
+	}
+	p.Personas[persona.Actor] = &persona
+	return nil
+}
+
+// Get returns an actor's persona, or ErrPersonaNotFound.
+func (p *Personas) Get(actor string) (*Persona, error) {
+	persona, ok := p.Personas[strings.TrimSuffix(actor, "/")]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPersonaNotFound, actor)
+	}
+	return persona, nil
+}
+
+// Remove deletes an actor's persona.
+func (p *Personas) Remove(actor string) error {
+	actor = strings.TrimSuffix(actor, "/")
+	if _, ok := p.Personas[actor]; !ok {
+		return fmt.Errorf("%w: %s", ErrPersonaNotFound, actor)
+	}
+	delete(p.Personas, actor)
+	return nil
+}
+
+// List returns the personas sorted by actor.
+func (p *Personas) List() []*Persona {
+	list := make([]*Persona, 0, len(p.Personas))
+	for _, persona := range p.Personas {
+		list = append(list, persona)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Actor < list[j].Actor })
+	return list
+}
+
+// NameFor returns the name to use for an actor in prose: its persona's name,
+// or the actor itself when it has no persona.
+func (p *Personas) NameFor(actor string) string {
+	if persona, err := p.Get(actor); err == nil {
+		return persona.Name
+	}
+	return actor
+}
@@ -0,0 +1,58 @@
+package narrator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPersonas_RoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+
+	p, err := LoadPersonas(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPersonas without file: %v", err)
+	}
+	if len(p.List()) != 0 {
+		t.Fatalf("new registry has %d personas, want 0", len(p.List()))
+	}
+
+	toast := Persona{Actor: "gastown/polecats/Toast/", Name: "Toast", Pronouns: "they/them", Traits: []string{"eager"}}
+	if err := p.Add(toast); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := p.Add(Persona{Actor: "gastown/polecats/Toast", Name: "Other"}); err == nil {
+		t.Error("Add of duplicate actor succeeded, want error")
+	}
+	if err := p.Add(Persona{Actor: "mayor"}); err == nil {
+		t.Error("Add without a name succeeded, want error")
+	}
+	if err := p.Save(townRoot); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadPersonas(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPersonas: %v", err)
+	}
+	got, err := loaded.Get("gastown/polecats/Toast")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Name != "Toast" || got.Pronouns != "they/them" || len(got.Traits) != 1 {
+		t.Errorf("loaded persona = %+v", got)
+	}
+
+	if name := loaded.NameFor("gastown/polecats/Toast"); name != "Toast" {
+		t.Errorf("NameFor(known) = %q, want Toast", name)
+	}
+	if name := loaded.NameFor("deacon"); name != "deacon" {
+		t.Errorf("NameFor(unknown) = %q, want the actor", name)
+	}
+
+	if err := loaded.Remove("gastown/polecats/Toast"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := loaded.Get("gastown/polecats/Toast"); !errors.Is(err, ErrPersonaNotFound) {
+		t.Errorf("Get after Remove: %v, want ErrPersonaNotFound", err)
+	}
+}
@@ -0,0 +1,530 @@
+package narrator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+//go:embed site
+var siteFS embed.FS
+
+const (
+	// siteManifest records what each generated file was built from, so a
+	// publish only rewrites what changed.
+	siteManifest = ".publish.json"
+
+	// siteLatest is how many chapters the index page lists.
+	siteLatest = 10
+
+	// excerptLength caps a chapter's excerpt, in bytes.
+	excerptLength = 280
+)
+
+// SiteDir returns where the town's site is published: narrator.publish.dir
+// (relative to the town root unless absolute), else narrator/site.
+func SiteDir(townRoot string) string {
+	if cfg := publishing(townRoot); cfg != nil && cfg.Dir != "" {
+		if filepath.IsAbs(cfg.Dir) {
+			return cfg.Dir
+		}
+		return filepath.Join(townRoot, cfg.Dir)
+	}
+	return filepath.Join(Dir(townRoot), "site")
+}
+
+// SiteTemplatesDir returns the directory whose files replace the built-in
+// site templates of the same name (layout.html, index.html, chapter.html,
+// rig.html, archive.html, search.html and style.css).
+func SiteTemplatesDir(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "site-templates")
+}
+
+// publishing returns the town's narrator.publish setting, or nil.
+func publishing(townRoot string) *config.NarratorPublish {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.Publish
+}
+
+// PublishResult describes a site publish.
+type PublishResult struct {
+	Dir      string   `json:"dir"`
+	Chapters int      `json:"chapters"`
+	Rendered int      `json:"rendered"`           // chapter pages rendered, the rest were current
+	Written  int      `json:"written"`            // files written because they changed
+	Removed  int      `json:"removed"`            // pages of chapters no longer in the index
+	Missing  []string `json:"missing,omitempty"`  // indexed chapters whose file is gone
+	Withheld int      `json:"withheld,omitempty"` // chapters awaiting review or rejected, with narrator.review
+}
+
+// siteChapter is a chapter as the site templates see it.
+type siteChapter struct {
+	ChapterRecord
+	Slug    string
+	Title   string
+	URL     string // page path from the site root
+	Date    string
+	Excerpt string
+	Text    string        // plain text, for search
+	Body    template.HTML // rendered only when its page is
+	Images  []string      // from the site root
+
+	source []byte
+}
+
+// sitePage is the data a site template renders. Root is the path from the
+// page back to the site root, for links.
+type sitePage struct {
+	Town     string
+	Title    string
+	Root     string
+	Rigs     []string
+	Total    int
+	Chapters []*siteChapter
+	Chapter  *siteChapter
+	Prev     *siteChapter
+	Next     *siteChapter
+	Rig      string
+	Months   []siteMonth
+}
+
+type siteMonth struct {
+	Name     string
+	Root     string
+	Chapters []*siteChapter
+}
+
+// Publish builds the town's chapters into a static site in out (SiteDir if
+// empty): an index of the latest chapters, a page per chapter with its
+// illustrations, a page per rig, a chronological archive, a search page
+// that works offline, and an Atom feed (feed.xml) of the latest chapters.
+// Chapters awaiting review or rejected (see Approve) are left out.
+// Only chapter pages whose chapter, neighbours or
+// templates changed are rendered again, and only files whose content
+// changed are written, so publishing after each chapter stays cheap; force
+// renders every page.
+func Publish(townRoot, out string, force bool) (*PublishResult, error) {
+	if out == "" {
+		out = SiteDir(townRoot)
+	}
+	result := &PublishResult{Dir: out}
+	tmpl, static, tmplHash, err := loadSiteTemplates(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	records, err := LoadChapters(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var chapters []*siteChapter
+	slugs := make(map[string]bool)
+	rigSet := make(map[string]bool)
+	for _, rec := range records {
+		if !rec.Published() {
+			result.Withheld++
+			continue
+		}
+		source, err := os.ReadFile(rec.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			result.Missing = append(result.Missing, rec.Path)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading chapter: %w", err)
+		}
+		c := newSiteChapter(rec, source)
+		for n := 2; slugs[c.Slug]; n++ {
+			c.Slug = fmt.Sprintf("%s-%d", strings.TrimSuffix(filepath.Base(rec.Path), filepath.Ext(rec.Path)), n)
+		}
+		slugs[c.Slug] = true
+		c.URL = "chapters/" + c.Slug + ".html"
+		for _, rig := range c.Rigs {
+			rigSet[rig] = true
+		}
+		chapters = append(chapters, c)
+	}
+	result.Chapters = len(chapters)
+	rigs := make([]string, 0, len(rigSet))
+	for rig := range rigSet {
+		rigs = append(rigs, rig)
+	}
+	sort.Strings(rigs)
+	town := townName(townRoot)
+
+	old := loadSiteManifest(out)
+	manifest := make(map[string]string)
+	w := &siteWriter{dir: out, result: result, manifest: manifest}
+
+	for _, c := range chapters {
+		for _, ill := range c.Illustrations {
+			if ill.Image == "" {
+				continue
+			}
+			name := "images/" + filepath.Base(ill.Image)
+			if err := w.copyFile(name, ill.Image); err != nil {
+				logger.Warn("illustration not published", "image", ill.Image, "err", err)
+				continue
+			}
+			c.Images = append(c.Images, name)
+		}
+	}
+
+	for i, c := range chapters {
+		page := sitePage{Town: town, Title: c.Title, Root: "../", Rigs: rigs, Chapter: c}
+		if i > 0 {
+			page.Prev = chapters[i-1]
+		}
+		if i < len(chapters)-1 {
+			page.Next = chapters[i+1]
+		}
+		key := chapterPageKey(tmplHash, page)
+		manifest[c.URL] = key
+		if _, err := os.Stat(filepath.Join(out, c.URL)); err == nil && !force && old[c.URL] == key {
+			continue
+		}
+		if c.Body, err = renderChapterBody(c); err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", c.Path, err)
+		}
+		if err := w.render(tmpl, "chapter.html", c.URL, page); err != nil {
+			return nil, err
+		}
+		result.Rendered++
+	}
+
+	newestFirst := make([]*siteChapter, len(chapters))
+	for i, c := range chapters {
+		newestFirst[len(chapters)-1-i] = c
+	}
+	latest := newestFirst
+	if len(latest) > siteLatest {
+		latest = latest[:siteLatest]
+	}
+	if err := w.render(tmpl, "index.html", "index.html", sitePage{Town: town, Rigs: rigs, Total: len(chapters), Chapters: latest}); err != nil {
+		return nil, err
+	}
+	for _, rig := range rigs {
+		var list []*siteChapter
+		for _, c := range newestFirst {
+			for _, r := range c.Rigs {
+				if r == rig {
+					list = append(list, c)
+				}
+			}
+		}
+		page := sitePage{Town: town, Title: rig, Root: "../", Rigs: rigs, Rig: rig, Chapters: list}
+		if err := w.render(tmpl, "rig.html", "rigs/"+rig+".html", page); err != nil {
+			return nil, err
+		}
+	}
+	var months []siteMonth
+	for _, c := range chapters {
+		name := c.Until.Local().Format("January 2006")
+		if len(months) == 0 || months[len(months)-1].Name != name {
+			months = append(months, siteMonth{Name: name})
+		}
+		months[len(months)-1].Chapters = append(months[len(months)-1].Chapters, c)
+	}
+	if err := w.render(tmpl, "archive.html", "archive.html", sitePage{Town: town, Title: "Archive", Rigs: rigs, Months: months}); err != nil {
+		return nil, err
+	}
+	if err := w.render(tmpl, "search.html", "search.html", sitePage{Town: town, Title: "Search", Rigs: rigs}); err != nil {
+		return nil, err
+	}
+	if err := w.write("search-index.js", searchIndex(chapters)); err != nil {
+		return nil, err
+	}
+	var feedCfg *config.NarratorFeed
+	if cfg := publishing(townRoot); cfg != nil {
+		feedCfg = cfg.Feed
+	}
+	feed, err := buildFeed(feedCfg, town, newestFirst)
+	if err != nil {
+		return nil, fmt.Errorf("building feed: %w", err)
+	}
+	if err := w.write("feed.xml", feed); err != nil {
+		return nil, err
+	}
+	if err := w.write("style.css", static); err != nil {
+		return nil, err
+	}
+
+	// Drop the pages of chapters and rigs that are gone
+	for name := range old {
+		if _, ok := manifest[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(out, filepath.FromSlash(name))); err == nil {
+			result.Removed++
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteFile(filepath.Join(out, siteManifest), data, 0644); err != nil {
+		return nil, fmt.Errorf("writing site manifest: %w", err)
+	}
+	logger.Info("site published", "dir", out, "chapters", result.Chapters, "rendered", result.Rendered, "written", result.Written)
+	return result, nil
+}
+
+// autoPublish rebuilds the site if narrator.publish.auto is set, returning
+// its directory, or "" if it isn't.
+func autoPublish(townRoot string) (string, error) {
+	if cfg := publishing(townRoot); cfg == nil || !cfg.Auto {
+		return "", nil
+	}
+	result, err := Publish(townRoot, "", false)
+	if err != nil {
+		return "", err
+	}
+	return result.Dir, nil
+}
+
+func newSiteChapter(rec ChapterRecord, source []byte) *siteChapter {
+	c := &siteChapter{
+		ChapterRecord: rec,
+		Slug:          strings.TrimSuffix(filepath.Base(rec.Path), filepath.Ext(rec.Path)),
+		Date:          rec.Until.Local().Format("Jan 2, 2006 15:04"),
+		source:        source,
+	}
+	text := string(source)
+	if strings.EqualFold(filepath.Ext(rec.Path), ".html") {
+		text = string(chapterFragment(source))
+		if heading := chapterHeading(text, FormatHTML); heading != "" {
+			c.Title = heading
+		}
+		text = htmlText(text)
+	}
+	var paragraph []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") && c.Title == "" {
+			c.Title = plainText(line)
+			continue
+		}
+		switch {
+		case c.Excerpt != "":
+		case line == "" || strings.HasPrefix(line, "#"):
+			if len(paragraph) > 0 {
+				c.Excerpt = excerpt(strings.Join(paragraph, " "))
+			}
+		case len(paragraph) == 0 && line == c.Title:
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	if c.Excerpt == "" && len(paragraph) > 0 {
+		c.Excerpt = excerpt(strings.Join(paragraph, " "))
+	}
+	if c.Title == "" {
+		switch {
+		case rec.CatchUp:
+			c.Title = "Catch-up, " + c.Date
+		case rec.Number > 0:
+			c.Title = fmt.Sprintf("Chapter %d", rec.Number)
+		default:
+			c.Title = c.Date
+		}
+	}
+	c.Text = plainText(text)
+	return c
+}
+
+// htmlTag matches the tags of an HTML chapter, stripped for its excerpt
+// and search text.
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// plainText strips Markdown punctuation and collapses whitespace.
+func plainText(s string) string {
+	s = strings.NewReplacer("#", " ", "*", "", "_", " ", "`", "", ">", " ", "|", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func excerpt(s string) string {
+	s = plainText(s)
+	if len(s) <= excerptLength {
+		return s
+	}
+	cut := strings.LastIndex(s[:excerptLength], " ")
+	if cut <= 0 {
+		cut = excerptLength
+	}
+	return s[:cut] + "…"
+}
+
+// chapterPolicy sanitizes HTML chapters. A chapter is agent output that
+// may quote event text, so only formatting markup is kept: no scripts,
+// event handlers, styles or javascript: links.
+var chapterPolicy = bluemonday.UGCPolicy()
+
+// renderChapterBody renders a Markdown chapter to HTML. HTML chapters are
+// fragments written for the page, or standalone pages whose <main> is
+// taken, sanitized; chapters in other formats are shown as written.
+func renderChapterBody(c *siteChapter) (template.HTML, error) {
+	switch strings.ToLower(filepath.Ext(c.Path)) {
+	case ".md", ".markdown":
+		return htmlBody(c.source, FormatMarkdown)
+	case ".html":
+		return htmlBody(chapterFragment(c.source), FormatHTML)
+	default:
+		return htmlBody(c.source, FormatText)
+	}
+}
+
+// chapterPageKey hashes everything a chapter page is rendered from.
+func chapterPageKey(tmplHash string, page sitePage) string {
+	h := sha256.New()
+	h.Write([]byte(tmplHash))
+	h.Write(page.Chapter.source)
+	for _, c := range []*siteChapter{page.Prev, page.Next} {
+		if c != nil {
+			fmt.Fprintf(h, "\x00%s\x00%s", c.URL, c.Title)
+		}
+	}
+	fmt.Fprintf(h, "\x00%s\x00%v\x00%v\x00%s\x00%d", page.Town, page.Rigs, page.Chapter.Images, page.Chapter.Date, page.Chapter.Number)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// searchIndex returns the script the search page loads. It is a script
+// rather than JSON so the page works opened from disk.
+func searchIndex(chapters []*siteChapter) []byte {
+	type entry struct {
+		Title string   `json:"title"`
+		URL   string   `json:"url"`
+		Date  string   `json:"date"`
+		Rigs  []string `json:"rigs"`
+		Text  string   `json:"text"`
+	}
+	entries := make([]entry, 0, len(chapters))
+	for i := len(chapters) - 1; i >= 0; i-- {
+		c := chapters[i]
+		rigs := c.Rigs
+		if rigs == nil {
+			rigs = []string{}
+		}
+		entries = append(entries, entry{Title: c.Title, URL: c.URL, Date: c.Date, Rigs: rigs, Text: c.Text})
+	}
+	data, _ := json.Marshal(entries)
+	return []byte("var SEARCH_INDEX = " + string(data) + ";\n")
+}
+
+// loadSiteTemplates parses the site templates, each taken from the town's
+// site-templates directory if it has one of that name, and returns them
+// with the stylesheet and a hash of both.
+func loadSiteTemplates(townRoot string) (*template.Template, []byte, string, error) {
+	entries, err := fs.ReadDir(siteFS, "site")
+	if err != nil {
+		return nil, nil, "", err
+	}
+	tmpl := template.New("site")
+	h := sha256.New()
+	var static []byte
+	for _, entry := range entries {
+		name := entry.Name()
+		data, err := os.ReadFile(filepath.Join(SiteTemplatesDir(townRoot), name)) //nolint:gosec // G304: name is a built-in template
+		if err != nil {
+			if data, err = siteFS.ReadFile(path.Join("site", name)); err != nil {
+				return nil, nil, "", err
+			}
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", name, data)
+		if name == "style.css" {
+			static = data
+			continue
+		}
+		if _, err := tmpl.New(name).Parse(string(data)); err != nil {
+			return nil, nil, "", fmt.Errorf("parsing site template %s: %w", name, err)
+		}
+	}
+	return tmpl, static, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func loadSiteManifest(dir string) map[string]string {
+	manifest := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(dir, siteManifest)) //nolint:gosec // G304: path is the site directory
+	if err == nil {
+		_ = json.Unmarshal(data, &manifest)
+	}
+	return manifest
+}
+
+// siteWriter writes a site's files, skipping those already up to date.
+type siteWriter struct {
+	dir      string
+	result   *PublishResult
+	manifest map[string]string // generated file -> what it was built from
+}
+
+func (w *siteWriter) render(tmpl *template.Template, name, file string, page sitePage) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, page); err != nil {
+		return fmt.Errorf("rendering %s: %w", file, err)
+	}
+	return w.write(file, buf.Bytes())
+}
+
+// write writes a file of the site unless it already has data.
+func (w *siteWriter) write(file string, data []byte) error {
+	if _, ok := w.manifest[file]; !ok {
+		w.manifest[file] = ""
+	}
+	dest := filepath.Join(w.dir, filepath.FromSlash(file))
+	if existing, err := os.ReadFile(dest); err == nil && bytes.Equal(existing, data) { //nolint:gosec // G304: path is in the site directory
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("creating site dir: %w", err)
+	}
+	if err := util.AtomicWriteFile(dest, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+	w.result.Written++
+	return nil
+}
+
+// copyFile copies src into the site unless a file of the same size is
+// already there.
+func (w *siteWriter) copyFile(file, src string) error {
+	w.manifest[file] = ""
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join(w.dir, filepath.FromSlash(file))
+	if have, err := os.Stat(dest); err == nil && have.Size() == info.Size() {
+		return nil
+	}
+	data, err := os.ReadFile(src) //nolint:gosec // G304: src is an illustration the narrator wrote
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("creating site dir: %w", err)
+	}
+	if err := util.AtomicWriteFile(dest, data, 0644); err != nil {
+		return err
+	}
+	w.result.Written++
+	return nil
+}
@@ -0,0 +1,231 @@
+package narrator
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPublish(t *testing.T) {
+	townRoot := t.TempDir()
+	writeChapter := func(name, text string, rec ChapterRecord) {
+		t.Helper()
+		rec.Path = filepath.Join(Dir(townRoot), name)
+		if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(rec.Path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := recordChapter(townRoot, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	writeChapter("chapter-20260301-120000.md", "# The Refinery Jams\n\nOld Gus *cursed* the merge queue.\n",
+		ChapterRecord{Number: 1, Until: day, Rigs: []string{"gastown"}})
+	// Written before the index was kept
+	if err := os.WriteFile(filepath.Join(Dir(townRoot), "catch-up-20260215-090000.md"), []byte("# Catch-up\n\nQuiet weeks.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "site")
+	result, err := Publish(townRoot, out, false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Chapters != 2 || result.Rendered != 2 {
+		t.Errorf("result = %+v, want 2 chapters rendered", result)
+	}
+	for _, file := range []string{"index.html", "archive.html", "search.html", "search-index.js", "style.css", "feed.xml", "rigs/gastown.html",
+		"chapters/chapter-20260301-120000.html", "chapters/catch-up-20260215-090000.html"} {
+		if _, err := os.Stat(filepath.Join(out, file)); err != nil {
+			t.Errorf("site has no %s", file)
+		}
+	}
+	page := readFile(t, filepath.Join(out, "chapters/chapter-20260301-120000.html"))
+	if !strings.Contains(page, "<em>cursed</em>") || !strings.Contains(page, "Chapter 1") {
+		t.Errorf("chapter page not rendered from Markdown:\n%s", page)
+	}
+	if !strings.Contains(page, `href="../chapters/catch-up-20260215-090000.html"`) {
+		t.Errorf("chapter page doesn't link to the previous chapter:\n%s", page)
+	}
+	if index := readFile(t, filepath.Join(out, "search-index.js")); !strings.Contains(index, "Old Gus cursed the merge queue.") {
+		t.Errorf("search index lacks the chapter text:\n%s", index)
+	}
+	if rig := readFile(t, filepath.Join(out, "rigs/gastown.html")); strings.Contains(rig, "Catch-up") || !strings.Contains(rig, "The Refinery Jams") {
+		t.Errorf("rig page should list only the rig's chapters:\n%s", rig)
+	}
+
+	// Nothing changed: nothing is rendered or written
+	result, err = Publish(townRoot, out, false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Rendered != 0 || result.Written != 0 {
+		t.Errorf("republish = %+v, want nothing rendered or written", result)
+	}
+
+	// A new chapter renders itself and the page that now links to it
+	writeChapter("chapter-20260302-120000.md", "# Second\n\nMore.\n", ChapterRecord{Number: 2, Until: day.Add(24 * time.Hour)})
+	result, err = Publish(townRoot, out, false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Rendered != 2 {
+		t.Errorf("Rendered = %d after a new chapter, want 2", result.Rendered)
+	}
+}
+
+func TestPublish_TemplateOverride(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(SiteTemplatesDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(SiteTemplatesDir(townRoot), "index.html"), []byte(`custom {{.Total}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Publish(townRoot, "", false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Dir != filepath.Join(Dir(townRoot), "site") {
+		t.Errorf("Dir = %s, want narrator/site", result.Dir)
+	}
+	if got := readFile(t, filepath.Join(result.Dir, "index.html")); got != "custom 0" {
+		t.Errorf("index.html = %q, want the town's template", got)
+	}
+}
+
+func TestGenerate_AutoPublish(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Publish: &config.NarratorPublish{Dir: "public", Auto: true}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("# A Failed Merge\n\nThe merge failed.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if chapter.Site != filepath.Join(townRoot, "public") {
+		t.Errorf("Site = %q, want the configured dir", chapter.Site)
+	}
+	if len(chapter.Rigs) != 1 || chapter.Rigs[0] != "gastown" {
+		t.Errorf("Rigs = %v, want [gastown]", chapter.Rigs)
+	}
+	if _, err := os.Stat(filepath.Join(chapter.Site, "rigs", "gastown.html")); err != nil {
+		t.Errorf("site not published: %v", err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestPublish_SanitizesHTMLChapters(t *testing.T) {
+	townRoot := t.TempDir()
+	rec := ChapterRecord{Number: 1, Until: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Path: filepath.Join(Dir(townRoot), "chapter-20260301-120000.html")}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	chapter := `<h1>The Quiet Night</h1>
+<p onclick="steal()">Nothing <em>happened</em>.</p>
+<script>steal()</script>
+<a href="javascript:steal()">look</a>
+<img src="x.png" onerror="steal()">`
+	if err := os.WriteFile(rec.Path, []byte(chapter), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := recordChapter(townRoot, rec); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "site")
+	if _, err := Publish(townRoot, out, false); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	page := readFile(t, filepath.Join(out, "chapters/chapter-20260301-120000.html"))
+	if !strings.Contains(page, "<em>happened</em>") {
+		t.Errorf("chapter markup dropped:\n%s", page)
+	}
+	for _, bad := range []string{"<script>steal", "onclick", "onerror", "javascript:"} {
+		if strings.Contains(page, bad) {
+			t.Errorf("chapter page keeps %q:\n%s", bad, page)
+		}
+	}
+}
+
+func TestPublish_Feed(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Publish: &config.NarratorPublish{
+		Feed: &config.NarratorFeed{Title: "Gas Town Chronicle", Author: "The Narrator", URL: "https://example.com/town/"},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, text := range []string{"# First\n\nThe rig woke up.\n", "# Second\n\nThe refinery *jammed*.\n"} {
+		rec := ChapterRecord{Number: i + 1, Until: day.Add(time.Duration(i) * 24 * time.Hour), Rigs: []string{"gastown"},
+			Path: filepath.Join(Dir(townRoot), fmt.Sprintf("chapter-%d.md", i+1))}
+		if err := os.WriteFile(rec.Path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := recordChapter(townRoot, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := Publish(townRoot, "", false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal([]byte(readFile(t, filepath.Join(result.Dir, "feed.xml"))), &feed); err != nil {
+		t.Fatalf("feed.xml: %v", err)
+	}
+	if feed.Title != "Gas Town Chronicle" || feed.Author.Name != "The Narrator" || feed.Updated != "2026-03-02T12:00:00Z" {
+		t.Errorf("feed = %q by %q updated %s", feed.Title, feed.Author.Name, feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("feed has %d entries, want 2", len(feed.Entries))
+	}
+	newest := feed.Entries[0]
+	if newest.Title != "Second" || newest.ID != "https://example.com/town/chapters/chapter-2.html" {
+		t.Errorf("newest entry = %q %s, want Second at its page URL", newest.Title, newest.ID)
+	}
+	if newest.Content == nil || !strings.Contains(newest.Content.Body, "<em>jammed</em>") {
+		t.Errorf("newest entry content = %+v, want the rendered chapter", newest.Content)
+	}
+	if len(newest.Categories) != 1 || newest.Categories[0].Term != "gastown" {
+		t.Errorf("categories = %+v, want the rig", newest.Categories)
+	}
+	if !strings.Contains(readFile(t, filepath.Join(result.Dir, "index.html")), `href="feed.xml"`) {
+		t.Error("index page doesn't link the feed")
+	}
+}
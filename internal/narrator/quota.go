@@ -0,0 +1,334 @@
+package narrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// usageRetention is how long the usage ledger keeps an entry. Quotas only
+// look back a day; the rest is history for 'gt narrator status'.
+const usageRetention = 30 * 24 * time.Hour
+
+// UsagePath returns the path to the narrator's usage ledger.
+func UsagePath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "usage.json")
+}
+
+// UsageEntry is one run of the narrating agent.
+type UsageEntry struct {
+	At      time.Time `json:"at"`
+	Chapter int       `json:"chapter"`
+	Agent   string    `json:"agent"`
+	Tokens  int       `json:"tokens"` // prompt and reply, estimated
+}
+
+// Usage is the narrator's ledger of agent runs, which quotas are counted
+// against.
+type Usage struct {
+	Entries []UsageEntry `json:"entries"`
+}
+
+// LoadUsage loads the usage ledger. A town without narrator/usage.json has
+// used nothing.
+func LoadUsage(townRoot string) (*Usage, error) {
+	data, err := os.ReadFile(UsagePath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return &Usage{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading usage ledger: %w", err)
+	}
+	var u Usage
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, fmt.Errorf("parsing usage ledger: %w", err)
+	}
+	return &u, nil
+}
+
+// Save writes the usage ledger to the town's narrator directory.
+func (u *Usage) Save(townRoot string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	return util.AtomicWriteJSON(UsagePath(townRoot), u)
+}
+
+// add records an agent run, forgetting entries past retention.
+func (u *Usage) add(e UsageEntry) {
+	kept := u.Entries[:0]
+	for _, old := range u.Entries {
+		if e.At.Sub(old.At) < usageRetention {
+			kept = append(kept, old)
+		}
+	}
+	u.Entries = append(kept, e)
+}
+
+// Since returns the agent runs and tokens recorded at or after t.
+func (u *Usage) Since(t time.Time) (runs, tokens int) {
+	for _, e := range u.Entries {
+		if !e.At.Before(t) {
+			runs++
+			tokens += e.Tokens
+		}
+	}
+	return runs, tokens
+}
+
+// estimateTokens estimates the tokens in text at about four bytes a token.
+// The agent runs as a CLI and doesn't report what it used.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// QuotaError reports a chapter that wasn't narrated because the narrator
+// is over quota.
+type QuotaError struct {
+	Reason  string    // the limit reached
+	RetryAt time.Time // when the quota next allows a chapter; zero if never
+	Queued  bool      // its events are kept for the next chapter
+}
+
+func (e *QuotaError) Error() string {
+	msg := "narrator over quota: " + e.Reason
+	if e.Queued {
+		msg += "; events queued for the next chapter"
+	} else {
+		msg += "; chapter dropped"
+	}
+	if !e.RetryAt.IsZero() {
+		msg += ", quota frees up at " + e.RetryAt.Local().Format("2006-01-02 15:04")
+	}
+	return msg
+}
+
+// quotaSettings returns the town's narrator.quota setting, or nil.
+func quotaSettings(townRoot string) *config.NarratorQuota {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.Quota
+}
+
+// overQuotaMode returns how a quota handles a chapter over it.
+func overQuotaMode(q *config.NarratorQuota) (string, error) {
+	switch q.OverQuota {
+	case "", config.NarratorQuotaQueue:
+		return config.NarratorQuotaQueue, nil
+	case config.NarratorQuotaDropLow, config.NarratorQuotaSummarize:
+		return q.OverQuota, nil
+	default:
+		return "", fmt.Errorf("narrator.quota: invalid over_quota %q (want %s, %s or %s)",
+			q.OverQuota, config.NarratorQuotaQueue, config.NarratorQuotaDropLow, config.NarratorQuotaSummarize)
+	}
+}
+
+// checkQuota reports the limit, if any, that a chapter whose prompt is
+// promptTokens would exceed at now, and when that limit next allows it.
+func checkQuota(q *config.NarratorQuota, u *Usage, promptTokens int, now time.Time) (string, time.Time) {
+	if q == nil {
+		return "", time.Time{}
+	}
+	for _, w := range []struct {
+		max    int
+		window time.Duration
+		name   string
+	}{{q.MaxPerHour, time.Hour, "hour"}, {q.MaxPerDay, 24 * time.Hour, "24 hours"}} {
+		if w.max <= 0 {
+			continue
+		}
+		recent := u.within(now, w.window)
+		if len(recent) >= w.max {
+			// The oldest runs must age out until one more fits
+			retry := recent[len(recent)-w.max].At.Add(w.window)
+			return fmt.Sprintf("%d chapters in the last %s (max %d)", len(recent), w.name, w.max), retry
+		}
+	}
+	if q.MaxTokensPerDay > 0 {
+		recent := u.within(now, 24*time.Hour)
+		used := 0
+		for _, e := range recent {
+			used += e.Tokens
+		}
+		if used+promptTokens > q.MaxTokensPerDay {
+			reason := fmt.Sprintf("~%d tokens used in the last 24 hours, ~%d more needed (max %d)", used, promptTokens, q.MaxTokensPerDay)
+			if promptTokens > q.MaxTokensPerDay {
+				return reason, time.Time{}
+			}
+			for _, e := range recent {
+				used -= e.Tokens
+				if used+promptTokens <= q.MaxTokensPerDay {
+					return reason, e.At.Add(24 * time.Hour)
+				}
+			}
+		}
+	}
+	return "", time.Time{}
+}
+
+// within returns the entries in the window before now, oldest first.
+func (u *Usage) within(now time.Time, window time.Duration) []UsageEntry {
+	var recent []UsageEntry
+	for _, e := range u.Entries {
+		if now.Sub(e.At) < window {
+			recent = append(recent, e)
+		}
+	}
+	sort.Slice(recent, func(i, j int) bool { return recent[i].At.Before(recent[j].At) })
+	return recent
+}
+
+// generationLockTimeout bounds how long a generation waits for another in
+// the same town. Agent runs take minutes, so it is generous.
+const generationLockTimeout = 30 * time.Minute
+
+// lockGeneration takes the narrator directory's generation lock
+// (narrator/.generate.lock), waiting for a generation already running.
+// Holding it from the quota check through recording the run keeps
+// concurrent generations from overrunning the quota, losing usage entries
+// or writing the same chapter number.
+func lockGeneration(ctx context.Context, townRoot string) (*flock.Flock, error) {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating narrator dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(Dir(townRoot), ".generate.lock"))
+	if locked, err := lock.TryLock(); err != nil {
+		return nil, fmt.Errorf("locking narrator: %w", err)
+	} else if locked {
+		return lock, nil
+	}
+	logger.Info("waiting for another generation to finish")
+	ctx, cancel := context.WithTimeout(ctx, generationLockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 500*time.Millisecond)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for another narrator generation: %w", err)
+	}
+	if !locked {
+		return nil, errors.New("waiting for another narrator generation: timed out")
+	}
+	return lock, nil
+}
+
+// queueSince holds events from since back for the next town-wide chapter.
+func queueSince(townRoot string, since time.Time) error {
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	if !s.QueuedSince.IsZero() && !since.Before(s.QueuedSince) {
+		return nil
+	}
+	s.QueuedSince = since
+	if err := s.Save(townRoot); err != nil {
+		return fmt.Errorf("queueing events: %w", err)
+	}
+	return nil
+}
+
+// clearQueue forgets queued events once a chapter has covered them.
+func clearQueue(townRoot string) error {
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	if s.QueuedSince.IsZero() {
+		return nil
+	}
+	s.QueuedSince = time.Time{}
+	if err := s.Save(townRoot); err != nil {
+		return fmt.Errorf("clearing queued events: %w", err)
+	}
+	return nil
+}
+
+// summarize writes the events of a chapter over quota up as a summary, as
+// Resume does a backlog: counts by type and the high-significance events,
+// without running the agent. The story state isn't advanced.
+func summarize(townRoot string, opts GenerateOptions, prompt *Prompt, reason string) (*Chapter, error) {
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now().UTC()
+	}
+	byType := make(map[string]int)
+	for _, e := range prompt.selected {
+		byType[e.Type]++
+	}
+	var highlights []events.Record
+	high := opts
+	high.Significance = events.SignificanceHigh
+	if narrowed, err := BuildPrompt(townRoot, high); err == nil {
+		for _, e := range narrowed.selected {
+			highlights = append(highlights, events.Record{Event: e})
+		}
+	} else if !errors.Is(err, ErrNoEvents) {
+		return nil, err
+	}
+	if len(highlights) > maxHighlights {
+		highlights = highlights[len(highlights)-maxHighlights:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Summary: %s to %s\n\n", opts.Since.Local().Format("2006-01-02 15:04"), until.Local().Format("2006-01-02 15:04"))
+	tone := LoadTone(townRoot)
+	intro := fmt.Sprintf("The narrator was over quota (%s), so these %d events are summarized rather than told.", reason, prompt.Events)
+	fmt.Fprintf(&b, "%s\n\n", tone.summaryIntro(intro, prompt.Events))
+	writeTally(&b, byType, highlights, prompt.personas, tone)
+
+	rendered, ext, err := renderChapter(townRoot, b.String(), FormatMarkdown, "Summary", until)
+	if err != nil {
+		return nil, err
+	}
+	path := opts.Output
+	if path == "" {
+		path = filepath.Join(Dir(townRoot), "summary-"+until.Format("20060102-150405")+ext)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating chapter dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(rendered), 0644); err != nil { //nolint:gosec // G306: chapters are non-sensitive
+		return nil, fmt.Errorf("writing summary: %w", err)
+	}
+	if opts.Rig == "" && opts.From == "" && opts.Instance == "" {
+		if err := clearQueue(townRoot); err != nil {
+			return nil, fmt.Errorf("summary written to %s, but %w", path, err)
+		}
+	}
+	chapter := &Chapter{
+		Path:      path,
+		Style:     "summary",
+		Since:     opts.Since,
+		Until:     until,
+		Events:    prompt.Events,
+		Omitted:   prompt.Omitted,
+		Words:     len(strings.Fields(b.String())),
+		Rigs:      chapterRigs(prompt.selected),
+		OverQuota: reason,
+		Summary:   true,
+	}
+	if chapter.Commit, err = commitChapter(townRoot, path, chapter.commitMessage()); err != nil {
+		return nil, fmt.Errorf("summary written to %s, but %w", path, err)
+	}
+	logger.Info("summary written", "path", path, "events", chapter.Events, "commit", chapter.Commit)
+	if err := recordChapter(townRoot, chapter.record()); err != nil {
+		return chapter, fmt.Errorf("summary written to %s, but recording it: %w", path, err)
+	}
+	if chapter.Site, err = autoPublish(townRoot); err != nil {
+		return chapter, fmt.Errorf("summary written to %s, but publishing the site: %w", path, err)
+	}
+	return chapter, nil
+}
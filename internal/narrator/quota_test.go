@@ -0,0 +1,213 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestCheckQuota(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	u := &Usage{}
+	for _, ago := range []time.Duration{20 * time.Hour, 50 * time.Minute, 10 * time.Minute} {
+		u.add(UsageEntry{At: now.Add(-ago), Tokens: 1000})
+	}
+
+	if reason, _ := checkQuota(nil, u, 1000, now); reason != "" {
+		t.Errorf("no quota: reason = %q, want none", reason)
+	}
+	reason, retry := checkQuota(&config.NarratorQuota{MaxPerHour: 2}, u, 100, now)
+	if !strings.Contains(reason, "last hour") || !retry.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("per hour = %q, %v; want over until the 50-minute-old run ages out", reason, retry)
+	}
+	if reason, _ := checkQuota(&config.NarratorQuota{MaxPerDay: 4}, u, 100, now); reason != "" {
+		t.Errorf("per day under quota: reason = %q", reason)
+	}
+	reason, retry = checkQuota(&config.NarratorQuota{MaxTokensPerDay: 3500}, u, 1000, now)
+	if !strings.Contains(reason, "tokens") || !retry.Equal(now.Add(4*time.Hour)) {
+		t.Errorf("tokens = %q, %v; want over until the 20-hour-old run ages out", reason, retry)
+	}
+	if _, retry := checkQuota(&config.NarratorQuota{MaxTokensPerDay: 500}, u, 1000, now); !retry.IsZero() {
+		t.Errorf("prompt over the whole budget: retry = %v, want never", retry)
+	}
+}
+
+// quotaTown sets up a town whose narrator has used its hourly quota.
+func quotaTown(t *testing.T, overQuota string) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Quota: &config.NarratorQuota{MaxPerHour: 1, OverQuota: overQuota}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	u := &Usage{}
+	u.add(UsageEntry{At: time.Now().UTC().Add(-time.Minute), Chapter: 1, Agent: "claude", Tokens: 100})
+	if err := u.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-1"}}`,
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	return townRoot
+}
+
+func TestGenerate_QuotaQueue(t *testing.T) {
+	townRoot := quotaTown(t, "")
+	ran := 0
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		ran++
+		return []byte("# Later\n\nIt happened.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	since := time.Now().Add(-time.Hour)
+	_, err := Generate(context.Background(), townRoot, GenerateOptions{Since: since, Agent: "claude"})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || !quotaErr.Queued {
+		t.Fatalf("Generate over quota error = %v, want a queued *QuotaError", err)
+	}
+	if ran != 0 {
+		t.Errorf("agent ran %d times over quota", ran)
+	}
+	state, _ := LoadState(townRoot)
+	if !state.QueuedSince.Equal(since) {
+		t.Errorf("QueuedSince = %v, want %v", state.QueuedSince, since)
+	}
+
+	// Once the quota frees up, the next chapter starts from the queue
+	if err := (&Usage{}).Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Minute), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !chapter.Since.Equal(since) {
+		t.Errorf("chapter Since = %v, want the queued %v", chapter.Since, since)
+	}
+	state, _ = LoadState(townRoot)
+	if !state.QueuedSince.IsZero() {
+		t.Errorf("QueuedSince = %v after narrating the queue, want cleared", state.QueuedSince)
+	}
+	usage, _ := LoadUsage(townRoot)
+	if runs, tokens := usage.Since(time.Now().Add(-time.Hour)); runs != 1 || tokens == 0 {
+		t.Errorf("usage = %d runs, %d tokens; want the chapter recorded", runs, tokens)
+	}
+}
+
+func TestGenerate_QuotaDropLow(t *testing.T) {
+	townRoot := quotaTown(t, config.NarratorQuotaDropLow)
+	since := time.Now().Add(-time.Hour)
+
+	// Over a chapter count, narrowing the chapter doesn't help
+	orig := runAgent
+	t.Cleanup(func() { runAgent = orig })
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		t.Fatal("agent ran over max_per_hour")
+		return nil, nil
+	}
+	_, err := Generate(context.Background(), townRoot, GenerateOptions{Since: since, Agent: "claude"})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) || quotaErr.Queued || !strings.Contains(quotaErr.Reason, "last hour") {
+		t.Fatalf("Generate over max_per_hour error = %v, want a dropped *QuotaError", err)
+	}
+
+	// Over the token budget, the high-significance events fit
+	full, err := BuildPrompt(townRoot, GenerateOptions{Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	high, err := BuildPrompt(townRoot, GenerateOptions{Since: since, Significance: events.SignificanceHigh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	budget := 100 + estimateTokens(high.Text)
+	if budget >= 100+estimateTokens(full.Text) {
+		t.Fatalf("narrowed prompt isn't smaller: %d >= %d tokens", estimateTokens(high.Text), estimateTokens(full.Text))
+	}
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Quota: &config.NarratorQuota{MaxTokensPerDay: budget, OverQuota: config.NarratorQuotaDropLow}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	var prompt string
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompt = strings.Join(argv, " ")
+		return []byte("# A Failed Merge\n\nThe merge failed.\n"), nil
+	}
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: since, Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if chapter.OverQuota == "" || chapter.Dropped != 1 || chapter.Events != 1 {
+		t.Errorf("chapter = %+v, want the sling dropped over quota", chapter)
+	}
+	if strings.Contains(prompt, "sling") || !strings.Contains(prompt, "merge_failed") {
+		t.Errorf("prompt should narrate only the merge failure:\n%s", prompt)
+	}
+}
+
+func TestGenerate_QuotaSummarize(t *testing.T) {
+	townRoot := quotaTown(t, config.NarratorQuotaSummarize)
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		t.Fatal("agent ran over quota")
+		return nil, nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if !chapter.Summary || chapter.Events != 2 {
+		t.Errorf("chapter = %+v, want a summary of 2 events", chapter)
+	}
+	text := readFile(t, chapter.Path)
+	for _, want := range []string{"- sling: 1", "- merge_failed: 1", "## Highlights", "over quota"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary lacks %q:\n%s", want, text)
+		}
+	}
+	story, _ := LoadStory(townRoot)
+	if story.Chapter != 0 {
+		t.Errorf("story advanced to chapter %d by a summary", story.Chapter)
+	}
+	records, err := LoadChapters(townRoot)
+	if err != nil || len(records) != 1 || records[0].Style != "summary" {
+		t.Errorf("chapter index = %+v, %v; want the summary", records, err)
+	}
+	usage, _ := LoadUsage(townRoot)
+	if runs, _ := usage.Since(time.Now().Add(-time.Hour)); runs != 1 {
+		t.Errorf("summary recorded as an agent run: %d runs", runs)
+	}
+}
+
+func TestGenerate_WaitsForLock(t *testing.T) {
+	townRoot := quotaTown(t, "")
+	held, err := lockGeneration(context.Background(), townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = held.Unlock() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := Generate(ctx, townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"}); err == nil || !strings.Contains(err.Error(), "another narrator generation") {
+		t.Errorf("Generate while locked error = %v, want it to wait on the lock", err)
+	}
+	state, _ := LoadState(townRoot)
+	if !state.QueuedSince.IsZero() {
+		t.Errorf("Generate ran its quota check without the lock")
+	}
+}
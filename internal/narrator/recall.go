@@ -0,0 +1,309 @@
+package narrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxRecalled caps the earlier chapters a prompt is reminded of.
+const maxRecalled = 3
+
+// RecallPath returns the path to the index of past chapters that Recall
+// searches.
+func RecallPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "recall.json")
+}
+
+// Recollection is a past chapter Recall found, best match first.
+type Recollection struct {
+	Number  int       `json:"number,omitempty"` // 0 for catch-ups, digests and summaries
+	Path    string    `json:"path"`
+	Title   string    `json:"title"`
+	Until   time.Time `json:"until"`
+	Rigs    []string  `json:"rigs,omitempty"`
+	Recap   string    `json:"recap,omitempty"`   // the narrator's recap, else the chapter's opening
+	Threads []string  `json:"threads,omitempty"` // storylines it opened, continued or concluded
+	Score   float64   `json:"score"`
+}
+
+// recallIndex is the index Recall searches: each chapter's text as a term
+// vector, kept with what's needed to tell whether the file has changed. The
+// vectors are weighted by inverse document frequency when searched, so the
+// index is a cache of the chapter files and the chapter index and can be
+// rebuilt from them at any time.
+type recallIndex struct {
+	Type    string         `json:"type"`    // "recall"
+	Version int            `json:"version"` // schema version
+	Entries []*recallEntry `json:"entries"`
+}
+
+type recallEntry struct {
+	Recollection
+	Size    int64          `json:"size"`
+	ModTime time.Time      `json:"mod_time"`
+	Terms   map[string]int `json:"terms"`
+}
+
+// Recall searches the town's past chapters and storyline summaries for
+// query and returns the best matches, at most limit of them. Chapters are
+// ranked by the cosine similarity of their TF-IDF term vectors to the
+// query's, so words the whole history uses count for little and the rare
+// ones ("refinery", "deadlock") for a lot. The index is brought up to date
+// with the chapter files first.
+func Recall(townRoot, query string, limit int) ([]Recollection, error) {
+	idx, changed, err := loadRecallIndex(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := idx.save(townRoot); err != nil {
+			logger.Warn("saving recall index", "err", err)
+		}
+	}
+	return idx.search(query, limit), nil
+}
+
+// updateRecallIndex brings the recall index up to date with the chapter
+// files and saves it.
+func updateRecallIndex(townRoot string) error {
+	idx, changed, err := loadRecallIndex(townRoot)
+	if err != nil || !changed {
+		return err
+	}
+	return idx.save(townRoot)
+}
+
+// loadRecallIndex loads the recall index and re-indexes the chapters that
+// are new or have changed since, reporting whether any were.
+func loadRecallIndex(townRoot string) (*recallIndex, bool, error) {
+	idx := &recallIndex{Type: "recall", Version: 1}
+	data, err := os.ReadFile(RecallPath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, false, fmt.Errorf("reading recall index: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, idx); err != nil {
+			// A cache; rebuild it
+			logger.Warn("recall index unreadable, rebuilding", "err", err)
+			idx = &recallIndex{Type: "recall", Version: 1}
+		}
+	}
+	indexed := make(map[string]*recallEntry, len(idx.Entries))
+	for _, e := range idx.Entries {
+		indexed[e.Path] = e
+	}
+
+	records, err := LoadChapters(townRoot)
+	if err != nil {
+		return nil, false, err
+	}
+	changed := len(records) != len(idx.Entries)
+	entries := make([]*recallEntry, 0, len(records))
+	for _, rec := range records {
+		info, err := os.Stat(rec.Path)
+		if err != nil {
+			changed = true
+			continue
+		}
+		e := indexed[rec.Path]
+		if e == nil || e.Size != info.Size() || !e.ModTime.Equal(info.ModTime()) || (rec.Recap != "" && e.Recap != rec.Recap) {
+			if e, err = indexChapter(rec, info); err != nil {
+				logger.Warn("indexing chapter for recall", "path", rec.Path, "err", err)
+				changed = true
+				continue
+			}
+			changed = true
+		}
+		entries = append(entries, e)
+	}
+	idx.Entries = entries
+	return idx, changed, nil
+}
+
+// indexChapter reads a chapter and its record into a recall entry.
+func indexChapter(rec ChapterRecord, info os.FileInfo) (*recallEntry, error) {
+	source, err := os.ReadFile(rec.Path) //nolint:gosec // G304: path is from the chapter index
+	if err != nil {
+		return nil, err
+	}
+	c := newSiteChapter(rec, source)
+	e := &recallEntry{
+		Recollection: Recollection{
+			Number:  rec.Number,
+			Path:    rec.Path,
+			Title:   c.Title,
+			Until:   rec.Until,
+			Rigs:    rec.Rigs,
+			Recap:   rec.Recap,
+			Threads: rec.Threads,
+		},
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Terms:   make(map[string]int),
+	}
+	if e.Recap == "" {
+		e.Recap = c.Excerpt
+	}
+	// The title, recap and storylines say what the chapter is about;
+	// they count double
+	for _, s := range append([]string{c.Title, rec.Recap}, rec.Threads...) {
+		for _, t := range terms(s) {
+			e.Terms[t] += 2
+		}
+	}
+	for _, t := range terms(c.Text) {
+		e.Terms[t]++
+	}
+	return e, nil
+}
+
+func (idx *recallIndex) save(townRoot string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	return util.AtomicWriteJSON(RecallPath(townRoot), idx)
+}
+
+// search ranks the index's chapters by similarity to query.
+func (idx *recallIndex) search(query string, limit int) []Recollection {
+	q := make(map[string]int)
+	for _, t := range terms(query) {
+		q[t]++
+	}
+	if len(q) == 0 || len(idx.Entries) == 0 {
+		return nil
+	}
+	df := make(map[string]int)
+	for _, e := range idx.Entries {
+		for t := range e.Terms {
+			df[t]++
+		}
+	}
+	n := float64(len(idx.Entries))
+	idf := func(t string) float64 { return math.Log(1 + n/float64(df[t])) }
+
+	var qNorm float64
+	qVec := make(map[string]float64, len(q))
+	for t, c := range q {
+		if df[t] == 0 {
+			continue
+		}
+		qVec[t] = float64(c) * idf(t)
+		qNorm += qVec[t] * qVec[t]
+	}
+	if qNorm == 0 {
+		return nil
+	}
+	var found []Recollection
+	for _, e := range idx.Entries {
+		var dot, norm float64
+		for t, c := range e.Terms {
+			w := (1 + math.Log(float64(c))) * idf(t)
+			norm += w * w
+			dot += w * qVec[t]
+		}
+		if dot == 0 {
+			continue
+		}
+		r := e.Recollection
+		r.Score = math.Round(dot/math.Sqrt(norm*qNorm)*1000) / 1000
+		found = append(found, r)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Score != found[j].Score {
+			return found[i].Score > found[j].Score
+		}
+		return found[i].Until.After(found[j].Until)
+	})
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// stopWords are left out of the recall index: they say nothing about what
+// a chapter is about.
+var stopWords = make(map[string]bool)
+
+func init() {
+	for _, w := range strings.Fields(`about after again all also and any are back been before but
+		can could did does each from had has have her his into its just more most not now off once
+		only other our out over own same she some such than that the their them then there these they
+		this those through time too under until very was were what when where which while who why will
+		with would you your`) {
+		stopWords[w] = true
+	}
+}
+
+// terms splits text into the lowercase words a chapter is indexed by,
+// without stop words, numbers or the endings of plurals and tenses, so
+// "merges", "merged" and "merging" are one term.
+func terms(text string) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) < 3 || stopWords[w] || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		out = append(out, stem(w))
+	}
+	return out
+}
+
+// stem strips common English endings from a word.
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 4 && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, suffix)
+			break
+		}
+	}
+	return strings.TrimSuffix(w, "e")
+}
+
+// recallQuery is the query a prompt's events recall earlier chapters by:
+// their types, actors and payloads.
+func recallQuery(evts []events.Event) string {
+	var b strings.Builder
+	for _, e := range evts {
+		fmt.Fprintf(&b, "%s %s %s\n", strings.ReplaceAll(e.Type, "_", " "), e.Actor, formatPayload(e.Payload))
+	}
+	return b.String()
+}
+
+// writeRecollections adds the earlier chapters the events recall to a
+// prompt, for continuity beyond the last chapter.
+func writeRecollections(b *strings.Builder, recalled []Recollection) {
+	if len(recalled) == 0 {
+		return
+	}
+	b.WriteString("## Earlier chapters\n\n")
+	b.WriteString("Earlier chapters these events recall. Refer back to them where the events continue their story.\n\n")
+	for _, r := range recalled {
+		b.WriteString("- ")
+		if r.Number > 0 {
+			fmt.Fprintf(b, "Chapter %d, ", r.Number)
+		}
+		fmt.Fprintf(b, "%q (%s)", r.Title, r.Until.Local().Format("Jan 2, 2006"))
+		if r.Recap != "" {
+			b.WriteString(": " + r.Recap)
+		}
+		if len(r.Threads) > 0 {
+			fmt.Fprintf(b, " Storylines: %s.", strings.Join(r.Threads, "; "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
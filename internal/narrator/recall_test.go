@@ -0,0 +1,103 @@
+package narrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecall(t *testing.T) {
+	townRoot := t.TempDir()
+	writeChapter := func(name, text string, rec ChapterRecord) {
+		t.Helper()
+		rec.Path = filepath.Join(Dir(townRoot), name)
+		if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(rec.Path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := recordChapter(townRoot, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	writeChapter("chapter-1.md", "# The Refinery Jams\n\nThe refinery broke down and the merge queue stalled for hours.\n",
+		ChapterRecord{Number: 1, Until: day, Recap: "The refinery broke.", Threads: []string{"The broken refinery"}})
+	writeChapter("chapter-2.md", "# Quiet Polecats\n\nThe polecats finished their beads and went home.\n",
+		ChapterRecord{Number: 2, Until: day.Add(24 * time.Hour)})
+	writeChapter("chapter-3.md", "# The Convoy\n\nA convoy of beads landed while the refinery hummed along.\n",
+		ChapterRecord{Number: 3, Until: day.Add(48 * time.Hour)})
+
+	found, err := Recall(townRoot, "the time the refinery broke", 5)
+	if err != nil {
+		t.Fatalf("Recall: %v", err)
+	}
+	if len(found) != 2 || found[0].Number != 1 || found[1].Number != 3 {
+		t.Fatalf("Recall = %+v, want chapters 1 and 3", found)
+	}
+	if found[0].Title != "The Refinery Jams" || found[0].Recap != "The refinery broke." || len(found[0].Threads) != 1 {
+		t.Errorf("recollection = %+v, want the chapter's title, recap and storyline", found[0])
+	}
+	if found[1].Recap == "" {
+		t.Error("a chapter without a recap isn't recalled by its opening")
+	}
+	if _, err := os.Stat(RecallPath(townRoot)); err != nil {
+		t.Errorf("recall index not saved: %v", err)
+	}
+
+	// Storylines are searched, and a changed chapter is re-indexed
+	if found, _ := Recall(townRoot, "broken", 5); len(found) != 1 || found[0].Number != 1 {
+		t.Errorf("Recall by storyline = %+v, want chapter 1", found)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(townRoot), "chapter-2.md"), []byte("# Deadlock\n\nThe deacon and the witness deadlocked.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := Recall(townRoot, "deadlocks", 5); len(found) != 1 || found[0].Number != 2 {
+		t.Errorf("Recall after rewrite = %+v, want chapter 2", found)
+	}
+	if found, _ := Recall(townRoot, "the and", 5); len(found) != 0 {
+		t.Errorf("Recall of stop words = %+v, want nothing", found)
+	}
+}
+
+func TestGenerate_Recall(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	var prompts []string
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompts = append(prompts, argv[len(argv)-1])
+		return []byte("# The Refinery Fails\n\nThe refinery failed a merge.\n```story\n" +
+			`{"recap":"The refinery failed a merge.","opened":[{"id":"refinery-woes","title":"The refinery's woes"}]}` +
+			"\n```\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	for i := 0; i < 3; i++ {
+		if _, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude",
+			Output: filepath.Join(Dir(townRoot), fmt.Sprintf("chapter-%d.md", i+1))}); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+	}
+	if strings.Contains(prompts[0], "## Earlier chapters") || strings.Contains(prompts[1], "## Earlier chapters") {
+		t.Error("first chapters recall earlier chapters beyond the last one")
+	}
+	if !strings.Contains(prompts[2], "## Earlier chapters") || !strings.Contains(prompts[2], `Chapter 1, "The Refinery Fails"`) ||
+		!strings.Contains(prompts[2], "The refinery failed a merge. Storylines: The refinery's woes.") {
+		t.Errorf("third prompt doesn't recall chapter 1:\n%s", prompts[2])
+	}
+
+	records, err := LoadChapters(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := records[0]; rec.Recap != "The refinery failed a merge." || len(rec.Threads) != 1 {
+		t.Errorf("chapter record = %+v, want its recap and storyline", rec)
+	}
+}
@@ -0,0 +1,296 @@
+package narrator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"github.com/yuin/goldmark"
+)
+
+//go:embed themes
+var themesFS embed.FS
+
+// DefaultTheme is the theme of HTML chapters when narrator.theme doesn't
+// name one.
+const DefaultTheme = "default"
+
+// themeFiles are the files of a theme. Those a theme doesn't have are
+// taken from the default theme.
+var themeFiles = []string{"page.html", "style.css"}
+
+// OutputFormats are the formats chapters can be rendered to, for
+// narrator.output_format.
+var OutputFormats = []string{FormatMarkdown, FormatHTML, FormatText}
+
+// ThemesDir returns the directory of a town's themes for HTML chapters,
+// one subdirectory per theme.
+func ThemesDir(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "themes")
+}
+
+// CheckOutputFormat reports an error if chapters can't be rendered to
+// format.
+func CheckOutputFormat(format string) error {
+	for _, f := range OutputFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q (want %s)", format, strings.Join(OutputFormats, ", "))
+}
+
+// ListThemes returns the names of the built-in themes and the town's own.
+func ListThemes(townRoot string) []string {
+	seen := make(map[string]bool)
+	if entries, err := fs.ReadDir(themesFS, "themes"); err == nil {
+		for _, e := range entries {
+			seen[e.Name()] = true
+		}
+	}
+	if entries, err := os.ReadDir(ThemesDir(townRoot)); err == nil {
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				seen[e.Name()] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckTheme reports an error if name isn't a built-in or town theme.
+func CheckTheme(townRoot, name string) error {
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid theme name %q", name)
+	}
+	for _, t := range ListThemes(townRoot) {
+		if t == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ListThemes(townRoot), ", "))
+}
+
+// rendering returns the town's narrator.output_format and narrator.theme.
+func rendering(townRoot string) (format, theme string) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return "", DefaultTheme
+	}
+	theme = settings.Narrator.Theme
+	if theme == "" {
+		theme = DefaultTheme
+	}
+	return settings.Narrator.OutputFormat, theme
+}
+
+// loadTheme parses a theme's page template and returns it with the
+// theme's stylesheet. Each file is taken from the town's theme of that
+// name, else the built-in one, else the default theme.
+func loadTheme(townRoot, name string) (*template.Template, []byte, error) {
+	if err := CheckTheme(townRoot, name); err != nil {
+		return nil, nil, err
+	}
+	files := make(map[string][]byte)
+	for _, file := range themeFiles {
+		data, err := os.ReadFile(filepath.Join(ThemesDir(townRoot), name, file)) //nolint:gosec // G304: name is checked to stay in the themes dir
+		if err != nil {
+			if data, err = themesFS.ReadFile(path.Join("themes", name, file)); err != nil {
+				data, err = themesFS.ReadFile(path.Join("themes", DefaultTheme, file))
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("theme %s: reading %s: %w", name, file, err)
+		}
+		files[file] = data
+	}
+	tmpl, err := template.New("page.html").Parse(string(files["page.html"]))
+	if err != nil {
+		return nil, nil, fmt.Errorf("theme %s: parsing page.html: %w", name, err)
+	}
+	return tmpl, files["style.css"], nil
+}
+
+// themePage is the data a theme's page template renders.
+type themePage struct {
+	Town  string
+	Title string
+	Date  string
+	Body  template.HTML
+	CSS   template.CSS
+}
+
+// renderChapter renders a chapter written in format from to the town's
+// narrator.output_format, returning it and the extension of its file.
+// Without an output format the chapter is kept as written. title names the
+// chapter if it has no heading of its own.
+func renderChapter(townRoot, text, from, title string, at time.Time) (string, string, error) {
+	to, theme := rendering(townRoot)
+	if to == "" {
+		if f, ok := formats[from]; ok {
+			return text, f.ext, nil
+		}
+		return text, ".md", nil
+	}
+	if err := CheckOutputFormat(to); err != nil {
+		return "", "", fmt.Errorf("narrator.output_format: %w", err)
+	}
+	out, err := render(townRoot, text, from, to, theme, title, at)
+	if err != nil {
+		return "", "", err
+	}
+	return out, formats[to].ext, nil
+}
+
+// render converts a chapter written in format from to format to. HTML is
+// rendered as a standalone page in theme; Markdown and plain text are
+// rendered as text, since a chapter in plain text reads as Markdown.
+func render(townRoot, text, from, to, theme, title string, at time.Time) (string, error) {
+	switch to {
+	case FormatHTML:
+		tmpl, css, err := loadTheme(townRoot, theme)
+		if err != nil {
+			return "", err
+		}
+		body, err := htmlBody([]byte(text), from)
+		if err != nil {
+			return "", fmt.Errorf("rendering chapter: %w", err)
+		}
+		if heading := chapterHeading(text, from); heading != "" {
+			title = heading
+		}
+		var buf bytes.Buffer
+		page := themePage{
+			Town:  townName(townRoot),
+			Title: title,
+			Date:  at.Local().Format("Jan 2, 2006 15:04"),
+			Body:  body,
+			CSS:   template.CSS(css), //nolint:gosec // G203: the theme's own stylesheet
+		}
+		if err := tmpl.Execute(&buf, page); err != nil {
+			return "", fmt.Errorf("theme %s: rendering page: %w", theme, err)
+		}
+		return buf.String(), nil
+	case FormatText:
+		switch from {
+		case FormatMarkdown:
+			var buf bytes.Buffer
+			if err := goldmark.Convert([]byte(text), &buf); err != nil {
+				return "", fmt.Errorf("rendering chapter: %w", err)
+			}
+			return htmlText(buf.String()), nil
+		case FormatHTML:
+			return htmlText(text), nil
+		}
+		return text, nil
+	case FormatMarkdown:
+		if from == FormatHTML {
+			return htmlText(text), nil
+		}
+		return text, nil
+	}
+	return "", CheckOutputFormat(to)
+}
+
+// htmlBody renders a chapter written in format to HTML. Markdown is
+// converted, HTML fragments are sanitized and anything else is shown as
+// written.
+func htmlBody(source []byte, format string) (template.HTML, error) {
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := goldmark.Convert(source, &buf); err != nil {
+			return "", err
+		}
+		return template.HTML(buf.String()), nil //nolint:gosec // G203: goldmark escapes raw HTML by default
+	case FormatHTML:
+		return template.HTML(chapterPolicy.SanitizeBytes(source)), nil //nolint:gosec // G203: sanitized
+	default:
+		return template.HTML("<pre>" + template.HTMLEscapeString(string(source)) + "</pre>"), nil //nolint:gosec // G203: escaped
+	}
+}
+
+var (
+	// lineEnd and paragraphEnd match the tags that end a line or a
+	// paragraph of an HTML chapter rendered as text.
+	lineEnd      = regexp.MustCompile(`(?i)(</li>|<br\s*/?>)\s*`)
+	paragraphEnd = regexp.MustCompile(`(?i)(</(p|h[1-6]|ul|ol|blockquote|pre|div|table)>|<hr\s*/?>)\s*`)
+
+	// headingTag matches an HTML chapter's title.
+	headingTag = regexp.MustCompile(`(?is)<h1[^>]*>(.*?)</h1>`)
+
+	// mainTag matches the chapter in a standalone HTML page.
+	mainTag = regexp.MustCompile(`(?is)<main[^>]*>(.*)</main>`)
+)
+
+// chapterFragment returns the chapter of an HTML chapter file: the <main>
+// element of a standalone page, else the whole file.
+func chapterFragment(source []byte) []byte {
+	if m := mainTag.FindSubmatch(source); m != nil {
+		return m[1]
+	}
+	return source
+}
+
+// htmlText renders HTML as plain text, a paragraph to a block. Scripts and
+// styles are dropped with their content.
+func htmlText(s string) string {
+	s = string(chapterPolicy.SanitizeBytes([]byte(s)))
+	s = lineEnd.ReplaceAllString(s, "\n")
+	s = paragraphEnd.ReplaceAllString(s, "\n\n")
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+	var lines []string
+	blank := true
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && blank {
+			continue
+		}
+		blank = line == ""
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// chapterHeading returns the title a chapter gives itself, or "".
+func chapterHeading(text, format string) string {
+	switch format {
+	case FormatMarkdown:
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); strings.HasPrefix(line, "# ") {
+				return plainText(line)
+			}
+		}
+	case FormatHTML:
+		if m := headingTag.FindStringSubmatch(text); m != nil {
+			return htmlText(m[1])
+		}
+	}
+	return ""
+}
+
+// townName returns the town's name, or its directory's if it has none.
+func townName(townRoot string) string {
+	town, err := workspace.GetTownName(townRoot)
+	if err != nil || town == "" {
+		return filepath.Base(townRoot)
+	}
+	return town
+}
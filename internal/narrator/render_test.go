@@ -0,0 +1,129 @@
+package narrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	townRoot := t.TempDir()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	md := "# The Long Night\n\nThe *refinery* merged three branches.\n\n- one\n- two\n"
+
+	text, err := render(townRoot, md, FormatMarkdown, FormatText, DefaultTheme, "Chapter 1", at)
+	if err != nil {
+		t.Fatalf("render text: %v", err)
+	}
+	if want := "The Long Night\n\nThe refinery merged three branches.\n\none\ntwo"; text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+
+	page, err := render(townRoot, md, FormatMarkdown, FormatHTML, DefaultTheme, "Chapter 1", at)
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	for _, want := range []string{"<!DOCTYPE html>", "<title>The Long Night · ", "<em>refinery</em>", "background: #fdfbf7"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html page lacks %q:\n%s", want, page)
+		}
+	}
+
+	fragment := `<p>Toast <b>shipped</b> it.</p><script>steal()</script>`
+	back, err := render(townRoot, fragment, FormatHTML, FormatMarkdown, DefaultTheme, "", at)
+	if err != nil {
+		t.Fatalf("render markdown: %v", err)
+	}
+	if back != "Toast shipped it." {
+		t.Errorf("markdown = %q, want the fragment's text", back)
+	}
+}
+
+func TestRender_Themes(t *testing.T) {
+	townRoot := t.TempDir()
+	at := time.Now()
+
+	page, err := render(townRoot, "Quiet.", FormatText, FormatHTML, "dark", "Chapter 2", at)
+	if err != nil {
+		t.Fatalf("render dark: %v", err)
+	}
+	if !strings.Contains(page, "#1b1b1f") || !strings.Contains(page, "<title>Chapter 2 · ") {
+		t.Errorf("dark theme not applied, or title missing:\n%s", page)
+	}
+
+	// A town theme with only a page falls back to the default stylesheet
+	dir := filepath.Join(ThemesDir(townRoot), "plain")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(`<h1>{{.Title}}</h1><main>{{.Body}}</main><style>{{.CSS}}</style>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	page, err = render(townRoot, "Quiet.", FormatText, FormatHTML, "plain", "Chapter 3", at)
+	if err != nil {
+		t.Fatalf("render plain: %v", err)
+	}
+	if !strings.HasPrefix(page, "<h1>Chapter 3</h1><main><pre>Quiet.</pre></main>") || !strings.Contains(page, "#fdfbf7") {
+		t.Errorf("town theme not used:\n%s", page)
+	}
+
+	if _, err := render(townRoot, "Quiet.", FormatText, FormatHTML, "neon", "", at); err == nil {
+		t.Error("render with an unknown theme succeeded")
+	}
+}
+
+func TestGenerate_OutputFormat(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Set(townRoot, "format", "html"); err != nil {
+		t.Fatalf("Set format: %v", err)
+	}
+	if err := Set(townRoot, "format", "pdf"); err == nil {
+		t.Error("Set format pdf succeeded")
+	}
+	if err := Set(townRoot, "colour", "red"); err == nil {
+		t.Error("Set of an unknown key succeeded")
+	}
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("# A Failed Merge\n\nThe merge **failed**.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if filepath.Ext(chapter.Path) != ".html" {
+		t.Fatalf("chapter written to %s, want an .html page", chapter.Path)
+	}
+	if got := readFile(t, chapter.Path); !strings.Contains(got, "<strong>failed</strong>") || !strings.Contains(got, "<title>A Failed Merge") {
+		t.Errorf("chapter not rendered as a page:\n%s", got)
+	}
+
+	// The site shows the page's chapter, not the page around it
+	out := filepath.Join(t.TempDir(), "site")
+	if _, err := Publish(townRoot, out, false); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	index := readFile(t, filepath.Join(out, "index.html"))
+	if !strings.Contains(index, "A Failed Merge") || !strings.Contains(index, "The merge failed.") {
+		t.Errorf("index lacks the chapter's title and excerpt:\n%s", index)
+	}
+
+	settings, err := Settings(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]string)
+	for _, s := range settings {
+		values[s.Key] = s.Value
+	}
+	if values["format"] != "html" || values["theme"] != "" {
+		t.Errorf("Settings = %+v, want format html and the default theme", settings)
+	}
+}
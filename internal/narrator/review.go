@@ -0,0 +1,158 @@
+package narrator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Review states of a chapter under narrator.review. A chapter written
+// without review has none and is published as it is.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// DefaultReviewNotify is who is mailed about chapters awaiting review when
+// narrator.review.notify doesn't say.
+const DefaultReviewNotify = "overseer"
+
+// ErrNotPending indicates approve or reject was asked of a chapter that
+// isn't awaiting review.
+var ErrNotPending = errors.New("chapter is not awaiting review")
+
+// reviewSettings returns the town's narrator.review setting, or nil.
+func reviewSettings(townRoot string) *config.NarratorReview {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil {
+		return nil
+	}
+	return settings.Narrator.Review
+}
+
+// ChapterID returns the ID a chapter is approved or rejected by: its file
+// name without the extension.
+func ChapterID(rec ChapterRecord) string {
+	return strings.TrimSuffix(filepath.Base(rec.Path), filepath.Ext(rec.Path))
+}
+
+// Published reports whether a chapter may be published: it was written
+// without review, or approved.
+func (rec ChapterRecord) Published() bool {
+	return rec.Review == "" || rec.Review == ReviewApproved
+}
+
+// notifyReview mails the human that a chapter awaits review. A variable so
+// tests can stand in for the mail router.
+var notifyReview = func(townRoot, to string, rec ChapterRecord) error {
+	id := ChapterID(rec)
+	var b strings.Builder
+	fmt.Fprintf(&b, "A narrative awaits your review before it is published.\n\n")
+	fmt.Fprintf(&b, "Chapter: %s\n", rec.Path)
+	fmt.Fprintf(&b, "Covers: %s to %s, %d event(s)\n\n", rec.Since.Local().Format("2006-01-02 15:04"), rec.Until.Local().Format("2006-01-02 15:04"), rec.Events)
+	fmt.Fprintf(&b, "Publish it:  gt narrator approve %s\n", id)
+	fmt.Fprintf(&b, "Withhold it: gt narrator reject %s --reason \"...\"\n", id)
+	msg := mail.NewMessage("narrator", to, "NARRATIVE_PENDING "+id, b.String())
+	msg.Type = mail.TypeNotification
+	return mail.NewRouter(townRoot).Send(msg)
+}
+
+// holdForReview marks a chapter about to be recorded as awaiting review,
+// if narrator.review is set, and notifies the human. A failed notification
+// is logged: the chapter still waits in the queue.
+func holdForReview(townRoot string, rec *ChapterRecord) {
+	review := reviewSettings(townRoot)
+	if review == nil || rec.Review != "" {
+		return
+	}
+	rec.Review = ReviewPending
+	to := review.Notify
+	if to == "" {
+		to = DefaultReviewNotify
+	}
+	if err := notifyReview(townRoot, to, *rec); err != nil {
+		logger.Warn("notifying reviewer", "to", to, "chapter", rec.Path, "err", err)
+	}
+}
+
+// Pending returns the chapters awaiting review, oldest first.
+func Pending(townRoot string) ([]ChapterRecord, error) {
+	records, err := LoadChapters(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var pending []ChapterRecord
+	for _, rec := range records {
+		if rec.Review == ReviewPending {
+			pending = append(pending, rec)
+		}
+	}
+	return pending, nil
+}
+
+// Approve publishes a chapter awaiting review: it is recorded as approved
+// by reviewer, and with narrator.publish.auto set the site is republished.
+// id is the chapter's ID (see ChapterID) or path. It returns the chapter
+// and the site's directory, if it was republished.
+func Approve(townRoot, id, reviewer string) (*ChapterRecord, string, error) {
+	rec, err := review(townRoot, id, reviewer, ReviewApproved, "")
+	if err != nil {
+		return nil, "", err
+	}
+	site, err := autoPublish(townRoot)
+	if err != nil {
+		return rec, "", fmt.Errorf("chapter approved, but publishing the site: %w", err)
+	}
+	return rec, site, nil
+}
+
+// Reject withholds a chapter awaiting review from publishing for good. The
+// chapter file is kept.
+func Reject(townRoot, id, reviewer, reason string) (*ChapterRecord, error) {
+	return review(townRoot, id, reviewer, ReviewRejected, reason)
+}
+
+func review(townRoot, id, reviewer, verdict, note string) (*ChapterRecord, error) {
+	records, err := LoadChapters(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	abs, _ := filepath.Abs(id)
+	var matches []ChapterRecord
+	for _, rec := range records {
+		if ChapterID(rec) == id || rec.Path == abs {
+			matches = append(matches, rec)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no chapter %q", id)
+	case 1:
+	default:
+		paths := make([]string, len(matches))
+		for i, rec := range matches {
+			paths[i] = rec.Path
+		}
+		return nil, fmt.Errorf("chapter ID %q is ambiguous; give its path (%s)", id, strings.Join(paths, ", "))
+	}
+	rec := matches[0]
+	if rec.Review != ReviewPending {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotPending)
+	}
+	now := time.Now().UTC()
+	rec.Review = verdict
+	rec.ReviewedBy = reviewer
+	rec.ReviewedAt = &now
+	rec.ReviewNote = note
+	if err := recordChapter(townRoot, rec); err != nil {
+		return nil, fmt.Errorf("recording review: %w", err)
+	}
+	logger.Info("chapter reviewed", "chapter", rec.Path, "review", verdict, "by", reviewer)
+	return &rec, nil
+}
@@ -0,0 +1,91 @@
+package narrator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReview(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Set(townRoot, "review", "maybe"); err == nil {
+		t.Error("Set review maybe succeeded")
+	}
+	if err := Set(townRoot, "review", "on"); err != nil {
+		t.Fatalf("Set review: %v", err)
+	}
+	var notified []string
+	orig := notifyReview
+	notifyReview = func(_, to string, rec ChapterRecord) error {
+		notified = append(notified, to+" "+ChapterID(rec))
+		return nil
+	}
+	t.Cleanup(func() { notifyReview = orig })
+
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"chapter-20260301-120000.md", "chapter-20260302-120000.md"} {
+		rec := ChapterRecord{Number: i + 1, Path: filepath.Join(Dir(townRoot), name), Until: day.Add(time.Duration(i) * 24 * time.Hour)}
+		if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(rec.Path, []byte("# Chapter\n\nThe refinery merged.\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := recordChapter(townRoot, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Join(notified, ", ") != "overseer chapter-20260301-120000, overseer chapter-20260302-120000" {
+		t.Errorf("notified %v, want the overseer about each chapter", notified)
+	}
+	pending, err := Pending(townRoot)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Pending = %+v, %v; want both chapters", pending, err)
+	}
+
+	out := filepath.Join(t.TempDir(), "site")
+	result, err := Publish(townRoot, out, false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Chapters != 0 || result.Withheld != 2 {
+		t.Errorf("result = %+v, want both chapters withheld", result)
+	}
+
+	rec, _, err := Approve(townRoot, "chapter-20260301-120000", "human")
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if rec.Review != ReviewApproved || rec.ReviewedBy != "human" || rec.ReviewedAt == nil {
+		t.Errorf("approved record = %+v", rec)
+	}
+	if _, err := Reject(townRoot, "chapter-20260302-120000", "human", "names a customer"); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if _, _, err := Approve(townRoot, "chapter-20260302-120000", "human"); !errors.Is(err, ErrNotPending) {
+		t.Errorf("Approve of a rejected chapter error = %v, want ErrNotPending", err)
+	}
+	if _, _, err := Approve(townRoot, "chapter-nope", "human"); err == nil {
+		t.Error("Approve of an unknown chapter succeeded")
+	}
+	if pending, _ := Pending(townRoot); len(pending) != 0 {
+		t.Errorf("Pending after review = %+v, want none", pending)
+	}
+
+	result, err = Publish(townRoot, out, false)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Chapters != 1 || result.Withheld != 1 {
+		t.Errorf("result = %+v, want the approved chapter published and the rejected one withheld", result)
+	}
+	if _, err := os.Stat(filepath.Join(out, "chapters/chapter-20260301-120000.html")); err != nil {
+		t.Error("approved chapter not published")
+	}
+	if feed := readFile(t, filepath.Join(out, "feed.xml")); strings.Contains(feed, "chapter-20260302-120000") {
+		t.Errorf("rejected chapter in the feed:\n%s", feed)
+	}
+}
@@ -0,0 +1,196 @@
+package narrator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Setting is a narrator setting 'gt narrator config' shows and sets.
+type Setting struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`   // "" if unset
+	Default string `json:"default"` // what an unset value means
+	Help    string `json:"help"`
+}
+
+// settingKey is a narrator setting: where it lives in NarratorConfig and
+// how a new value is checked.
+type settingKey struct {
+	key, def, help string
+	get            func(*config.NarratorConfig) string
+	set            func(townRoot string, cfg *config.NarratorConfig, value string) error
+}
+
+var settingKeys = []settingKey{
+	{
+		key:  "format",
+		def:  "as the style writes it",
+		help: "Format chapters are rendered to (" + strings.Join(OutputFormats, ", ") + ")",
+		get:  func(c *config.NarratorConfig) string { return c.OutputFormat },
+		set: func(_ string, c *config.NarratorConfig, v string) error {
+			if v != "" {
+				if err := CheckOutputFormat(v); err != nil {
+					return err
+				}
+			}
+			c.OutputFormat = v
+			return nil
+		},
+	},
+	{
+		key:  "output_dir",
+		def:  "narrator/",
+		help: "Where chapters are written, a path template (e.g. history/{{.Date}}/{{.Rig}}/chapter-{{.N}}.md)",
+		get:  func(c *config.NarratorConfig) string { return c.OutputDir },
+		set: func(_ string, c *config.NarratorConfig, v string) error {
+			if v != "" {
+				if _, err := parseOutputDir(v); err != nil {
+					return err
+				}
+			}
+			c.OutputDir = v
+			return nil
+		},
+	},
+	{
+		key:  "intensity",
+		def:  "3",
+		help: "How strongly the tone is laid on, 1 (a hint) to 5 (never breaking character)",
+		get: func(c *config.NarratorConfig) string {
+			if c.Intensity == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.Intensity)
+		},
+		set: func(_ string, c *config.NarratorConfig, v string) error {
+			n := 0
+			if v != "" {
+				var err error
+				if n, err = parseIntensity(v); err != nil {
+					return err
+				}
+			}
+			c.Intensity = n
+			return nil
+		},
+	},
+	{
+		key:  "review",
+		def:  "off",
+		help: "Hold chapters for approval before they are published (on, off)",
+		get: func(c *config.NarratorConfig) string {
+			if c.Review != nil {
+				return "on"
+			}
+			return ""
+		},
+		set: func(_ string, c *config.NarratorConfig, v string) error {
+			switch v {
+			case "on":
+				if c.Review == nil {
+					c.Review = &config.NarratorReview{}
+				}
+			case "off", "":
+				c.Review = nil
+			default:
+				return fmt.Errorf("invalid review setting %q (want on or off)", v)
+			}
+			return nil
+		},
+	},
+	{
+		key:  "styles",
+		def:  "none",
+		help: "Further styles each chapter is also written in, as name[=output_dir],...",
+		get:  func(c *config.NarratorConfig) string { return formatStyles(c.Styles) },
+		set: func(townRoot string, c *config.NarratorConfig, v string) error {
+			styles, err := parseStyles(townRoot, v)
+			if err != nil {
+				return err
+			}
+			c.Styles = styles
+			return nil
+		},
+	},
+	{
+		key:  "theme",
+		def:  DefaultTheme,
+		help: "Theme of HTML chapters (built in, or a directory in narrator/themes/)",
+		get:  func(c *config.NarratorConfig) string { return c.Theme },
+		set: func(townRoot string, c *config.NarratorConfig, v string) error {
+			if v != "" {
+				if err := CheckTheme(townRoot, v); err != nil {
+					return err
+				}
+			}
+			c.Theme = v
+			return nil
+		},
+	},
+	{
+		key:  "tone",
+		def:  "as the style writes it",
+		help: "Register chapters and summaries are written in (" + strings.Join(Tones, ", ") + ")",
+		get:  func(c *config.NarratorConfig) string { return c.Tone },
+		set: func(_ string, c *config.NarratorConfig, v string) error {
+			if v != "" {
+				if err := CheckTone(v); err != nil {
+					return err
+				}
+			}
+			c.Tone = v
+			return nil
+		},
+	},
+}
+
+// Settings returns the narrator's settings and their current values.
+func Settings(townRoot string) ([]Setting, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	cfg := settings.Narrator
+	if cfg == nil {
+		cfg = &config.NarratorConfig{}
+	}
+	out := make([]Setting, len(settingKeys))
+	for i, k := range settingKeys {
+		out[i] = Setting{Key: k.key, Value: k.get(cfg), Default: k.def, Help: k.help}
+	}
+	return out, nil
+}
+
+// Set changes a narrator setting in the town's settings/config.json. An
+// empty value restores the default.
+func Set(townRoot, key, value string) error {
+	var k *settingKey
+	keys := make([]string, len(settingKeys))
+	for i := range settingKeys {
+		keys[i] = settingKeys[i].key
+		if settingKeys[i].key == key {
+			k = &settingKeys[i]
+		}
+	}
+	if k == nil {
+		return fmt.Errorf("unknown narrator setting %q (want %s)", key, strings.Join(keys, ", "))
+	}
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Narrator == nil {
+		settings.Narrator = &config.NarratorConfig{}
+	}
+	if err := k.set(townRoot, settings.Narrator, value); err != nil {
+		return err
+	}
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	return nil
+}
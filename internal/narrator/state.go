@@ -0,0 +1,318 @@
+package narrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agent"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var (
+	// ErrAlreadyPaused indicates pause was asked of a paused narrator.
+	ErrAlreadyPaused = errors.New("narrator is already paused")
+
+	// ErrNotPaused indicates resume was asked of a running narrator.
+	ErrNotPaused = errors.New("narrator is not paused")
+)
+
+// maxHighlights caps the events listed in a catch-up chapter.
+const maxHighlights = 20
+
+// StatePath returns the path to the narrator's run state.
+func StatePath(townRoot string) string {
+	return InstanceStatePath(townRoot, "")
+}
+
+// State is whether the narrator is running and how far it has got.
+type State struct {
+	State    agent.State `json:"state"` // running or paused
+	PausedAt time.Time   `json:"paused_at"`
+	PausedBy string      `json:"paused_by,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+
+	// NarratedThrough is when the narrative was last brought up to date:
+	// events before it have been narrated, summarized or skipped.
+	NarratedThrough time.Time `json:"narrated_through"`
+
+	// QueuedSince is the start of the earliest chapter held back by
+	// narrator.quota; the next town-wide chapter narrates from it.
+	QueuedSince time.Time `json:"queued_since,omitempty"`
+
+	// DigestedThrough is when digests were last written: the next ones
+	// start from it (see WriteDigests).
+	DigestedThrough time.Time `json:"digested_through,omitempty"`
+
+	// Checkpoint is how far WatchFromCheckpoint has delivered each events
+	// log, by shard ("" for the town-wide log).
+	Checkpoint map[string]events.Offset `json:"checkpoint,omitempty"`
+}
+
+// Paused reports whether narration is suspended.
+func (s *State) Paused() bool {
+	return s.State == agent.StatePaused
+}
+
+// LoadState loads the narrator's run state. A town without
+// narrator/state.json has a running narrator.
+func LoadState(townRoot string) (*State, error) {
+	return LoadInstanceState(townRoot, "")
+}
+
+// LoadInstanceState loads a named narrator's run state, or the town's
+// narrator's for "". A narrator without a state file is running.
+func LoadInstanceState(townRoot, name string) (*State, error) {
+	data, err := os.ReadFile(InstanceStatePath(townRoot, name)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{State: agent.StateRunning}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading narrator state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing narrator state: %w", err)
+	}
+	if s.State == "" {
+		s.State = agent.StateRunning
+	}
+	return &s, nil
+}
+
+// Save writes the run state to the town's narrator directory.
+func (s *State) Save(townRoot string) error {
+	return s.SaveInstance(townRoot, "")
+}
+
+// SaveInstance writes the run state as a named narrator's, or the town's
+// narrator's for "".
+func (s *State) SaveInstance(townRoot, name string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	return util.AtomicWriteJSON(InstanceStatePath(townRoot, name), s)
+}
+
+// IsPaused reports whether the town's narrator is paused. Narration must
+// not be generated while it is; events keep accumulating as the backlog.
+func IsPaused(townRoot string) (bool, error) {
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return false, err
+	}
+	return s.Paused(), nil
+}
+
+// Pause suspends narration. Events written from now on form the backlog
+// handled by Resume.
+func Pause(townRoot, reason, pausedBy string) error {
+	return PauseInstance(townRoot, "", reason, pausedBy)
+}
+
+// PauseInstance suspends a named narrator, or the town's narrator for "".
+func PauseInstance(townRoot, name, reason, pausedBy string) error {
+	if name != "" {
+		if err := CheckInstanceName(name); err != nil {
+			return err
+		}
+	}
+	s, err := LoadInstanceState(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if s.Paused() {
+		return fmt.Errorf("%w (since %s)", ErrAlreadyPaused, s.PausedAt.Local().Format("2006-01-02 15:04"))
+	}
+	s.State = agent.StatePaused
+	// Events carry second-resolution timestamps; truncate so events from
+	// the pausing second are in the backlog
+	s.PausedAt = time.Now().UTC().Truncate(time.Second)
+	s.PausedBy = pausedBy
+	s.Reason = reason
+	if err := s.SaveInstance(townRoot, name); err != nil {
+		return err
+	}
+	logger.Info("narrator paused", "name", name, "by", pausedBy, "reason", reason)
+	return nil
+}
+
+// Backlog returns the events written since the narrator was paused, or nil
+// when it is running.
+func Backlog(townRoot string) ([]events.Record, error) {
+	return InstanceBacklog(townRoot, "")
+}
+
+// CatchUp describes how a resume dealt with the backlog.
+type CatchUp struct {
+	Mode       string          `json:"mode"` // config.NarratorResumeSummarize or config.NarratorResumeSkip
+	Since      time.Time       `json:"since"`
+	Until      time.Time       `json:"until"`
+	Events     int             `json:"events"`
+	ByType     map[string]int  `json:"by_type"`
+	Highlights []events.Record `json:"highlights,omitempty"` // high-significance events and incidents, oldest first
+	Path       string          `json:"path,omitempty"`       // catch-up chapter, when one was written
+	Commit     string          `json:"commit,omitempty"`     // narrative branch commit, with narrator.git_output
+	Site       string          `json:"site,omitempty"`       // site republished, with narrator.publish.auto
+}
+
+// Resume restarts narration. mode is config.NarratorResumeSummarize, which
+// writes the backlog up as a catch-up chapter, or config.NarratorResumeSkip,
+// which leaves it out of the narrative; "" uses the town's narrator.on_resume
+// setting. Either way the narrative is up to date afterwards.
+func Resume(townRoot, mode string) (*CatchUp, error) {
+	if mode == "" {
+		mode = resumeMode(townRoot)
+	}
+	if mode != config.NarratorResumeSummarize && mode != config.NarratorResumeSkip {
+		return nil, fmt.Errorf("invalid resume mode %q (want %s or %s)", mode, config.NarratorResumeSummarize, config.NarratorResumeSkip)
+	}
+
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Paused() {
+		return nil, ErrNotPaused
+	}
+	backlog, err := Backlog(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading backlog: %w", err)
+	}
+
+	now := time.Now().UTC()
+	c := &CatchUp{Mode: mode, Since: s.PausedAt, Until: now, Events: len(backlog), ByType: make(map[string]int)}
+	rules, err := events.LoadSignificanceRules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Score in context so a burst of failures reads as one incident and a
+	// repetitive routine event doesn't crowd out the rest
+	scorer := events.NewScorer(rules)
+	for _, r := range backlog {
+		c.ByType[r.Type]++
+		significant, incident := scorer.AtLeast(r.Event, events.SignificanceHigh)
+		if e, visible := policy.Expose(r.Event, events.AudienceNarrator); significant && visible {
+			r.Event = e
+			c.Highlights = append(c.Highlights, r)
+		}
+		if incident != nil {
+			c.Highlights = append(c.Highlights, events.Record{Event: *incident})
+		}
+	}
+	if len(c.Highlights) > maxHighlights {
+		c.Highlights = c.Highlights[len(c.Highlights)-maxHighlights:]
+	}
+
+	if mode == config.NarratorResumeSummarize && c.Events > 0 {
+		personas, err := LoadPersonas(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		rendered, ext, err := renderChapter(townRoot, c.Markdown(personas, LoadTone(townRoot)), FormatMarkdown, "Catch-up", now)
+		if err != nil {
+			return nil, err
+		}
+		c.Path = filepath.Join(Dir(townRoot), "catch-up-"+now.Format("20060102-150405")+ext)
+		if err := os.WriteFile(c.Path, []byte(rendered), 0644); err != nil { //nolint:gosec // G306: chapters are non-sensitive
+			return nil, fmt.Errorf("writing catch-up chapter: %w", err)
+		}
+	}
+
+	s.State = agent.StateRunning
+	s.PausedAt = time.Time{}
+	s.PausedBy = ""
+	s.Reason = ""
+	s.NarratedThrough = now
+	if err := s.Save(townRoot); err != nil {
+		return nil, err
+	}
+	if c.Path != "" {
+		if c.Commit, err = commitChapter(townRoot, c.Path, c.commitMessage()); err != nil {
+			return nil, fmt.Errorf("narrator resumed and catch-up written to %s, but %w", c.Path, err)
+		}
+		evts := make([]events.Event, len(backlog))
+		for i, r := range backlog {
+			evts[i] = r.Event
+		}
+		rec := ChapterRecord{Path: c.Path, Since: c.Since, Until: c.Until, Rigs: chapterRigs(evts), Events: c.Events, CatchUp: true}
+		if err := recordChapter(townRoot, rec); err != nil {
+			return nil, fmt.Errorf("narrator resumed and catch-up written to %s, but recording it: %w", c.Path, err)
+		}
+		if c.Site, err = autoPublish(townRoot); err != nil {
+			return nil, fmt.Errorf("narrator resumed and catch-up written to %s, but publishing the site: %w", c.Path, err)
+		}
+	}
+	logger.Info("narrator resumed", "mode", mode, "backlog", c.Events, "path", c.Path)
+	return c, nil
+}
+
+// resumeMode returns the town's configured backlog handling.
+func resumeMode(townRoot string) string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil || settings.Narrator.OnResume == "" {
+		return config.NarratorResumeSummarize
+	}
+	return settings.Narrator.OnResume
+}
+
+// Markdown renders the catch-up as a chapter in tone, naming actors by
+// persona.
+func (c *CatchUp) Markdown(personas *Personas, tone Tone) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Catch-up: %s to %s\n\n",
+		c.Since.Local().Format("2006-01-02 15:04"), c.Until.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "%s\n\n", tone.summaryIntro(fmt.Sprintf("While the narrator was away, %d events happened.", c.Events), c.Events))
+	writeTally(&b, c.ByType, c.Highlights, personas, tone)
+	return b.String()
+}
+
+// writeTally writes a summary's counts of events by type, most frequent
+// first, and its highlights, headed in the tone.
+func writeTally(b *strings.Builder, byType map[string]int, highlights []events.Record, personas *Personas, tone Tone) {
+	happened, highlighted := tone.headings()
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if byType[types[i]] != byType[types[j]] {
+			return byType[types[i]] > byType[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	fmt.Fprintf(b, "## %s\n\n", happened)
+	for _, t := range types {
+		fmt.Fprintf(b, "- %s: %d\n", t, byType[t])
+	}
+
+	if len(highlights) > 0 {
+		fmt.Fprintf(b, "\n## %s\n\n", highlighted)
+		for _, r := range highlights {
+			ts := r.Timestamp
+			if t, err := time.Parse(time.RFC3339, r.Timestamp); err == nil {
+				ts = t.Local().Format("Jan 2 15:04")
+			}
+			if r.Type == events.TypeIncident {
+				scope := ""
+				if sc, ok := r.Payload["scope"].(string); ok {
+					scope = " for " + personas.NameFor(sc)
+				}
+				fmt.Fprintf(b, "- %s: incident, %v %v events%s within %v\n", ts, r.Payload["count"], r.Payload["type"], scope, r.Payload["window"])
+				continue
+			}
+			fmt.Fprintf(b, "- %s: %s, %s\n", ts, personas.NameFor(r.Actor), r.Type)
+		}
+	}
+}
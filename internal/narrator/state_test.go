@@ -0,0 +1,106 @@
+package narrator
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// writeEvents writes the town's events log, stamping each line with ts.
+func writeEvents(t *testing.T, townRoot string, ts time.Time, lines ...string) {
+	t.Helper()
+	var data []byte
+	for _, line := range lines {
+		data = append(data, `{"ts":"`+ts.UTC().Format(time.RFC3339)+`",`+line+"\n"...)
+	}
+	if err := os.WriteFile(events.LogPath(townRoot, ""), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPauseResume_Summarize(t *testing.T) {
+	townRoot := t.TempDir()
+	if paused, err := IsPaused(townRoot); err != nil || paused {
+		t.Fatalf("IsPaused on new town = %v, %v; want false", paused, err)
+	}
+	if _, err := Resume(townRoot, ""); !errors.Is(err, ErrNotPaused) {
+		t.Fatalf("Resume while running error = %v, want ErrNotPaused", err)
+	}
+
+	if err := Pause(townRoot, "quiet night", "human"); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if err := Pause(townRoot, "", "human"); !errors.Is(err, ErrAlreadyPaused) {
+		t.Fatalf("second Pause error = %v, want ErrAlreadyPaused", err)
+	}
+	if paused, _ := IsPaused(townRoot); !paused {
+		t.Fatal("IsPaused after Pause = false")
+	}
+
+	p := NewPersonas()
+	_ = p.Add(Persona{Actor: "gastown/refinery", Name: "The Refiner"})
+	if err := p.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"sling","actor":"mayor"}`,
+		`"type":"sling","actor":"mayor"}`,
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	backlog, err := Backlog(townRoot)
+	if err != nil || len(backlog) != 3 {
+		t.Fatalf("Backlog = %d events, %v; want 3", len(backlog), err)
+	}
+
+	c, err := Resume(townRoot, "")
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if c.Mode != config.NarratorResumeSummarize || c.Events != 3 || c.ByType["sling"] != 2 || len(c.Highlights) != 1 {
+		t.Errorf("catch-up = %+v", c)
+	}
+	chapter, err := os.ReadFile(c.Path)
+	if err != nil {
+		t.Fatalf("reading catch-up chapter: %v", err)
+	}
+	if !strings.Contains(string(chapter), "The Refiner, merge_failed") {
+		t.Errorf("chapter does not name the refinery by persona:\n%s", chapter)
+	}
+
+	s, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Paused() || s.NarratedThrough.IsZero() {
+		t.Errorf("state after Resume = %+v", s)
+	}
+}
+
+func TestResume_SkipFromConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{OnResume: config.NarratorResumeSkip}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := Pause(townRoot, "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	writeEvents(t, townRoot, time.Now(), `"type":"sling","actor":"mayor"}`)
+
+	c, err := Resume(townRoot, "")
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if c.Mode != config.NarratorResumeSkip || c.Events != 1 || c.Path != "" {
+		t.Errorf("catch-up = %+v, want skipped backlog without a chapter", c)
+	}
+
+	if _, err := Resume(townRoot, "rewind"); err == nil {
+		t.Error("Resume with an unknown mode succeeded")
+	}
+}
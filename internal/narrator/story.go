@@ -0,0 +1,325 @@
+package narrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	// maxThreads caps the open plot threads carried between chapters; the
+	// least recently seen are dropped first.
+	maxThreads = 12
+
+	// threadTTL is how many chapters a thread survives without being
+	// mentioned before it is dropped as forgotten.
+	threadTTL = 5
+
+	// conflictTTL is how many chapters a rig's conflict is remembered
+	// after it last flared up.
+	conflictTTL = 10
+)
+
+// conflictTypes are the events that count as conflict in a rig's story.
+// Incidents count as their burst's event type.
+var conflictTypes = map[string]bool{
+	events.TypeSessionDeath:   true,
+	events.TypeMassDeath:      true,
+	events.TypeMergeFailed:    true,
+	events.TypeEscalationSent: true,
+}
+
+// StoryPath returns the path to the narrator's story state.
+func StoryPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "story.json")
+}
+
+// Story is what the narrator remembers between chapters, so each one
+// picks up where the last left off instead of reading as an isolated
+// report.
+type Story struct {
+	Type        string                 `json:"type"`    // "story"
+	Version     int                    `json:"version"` // schema version
+	Chapter     int                    `json:"chapter"` // number of the last chapter written
+	LastChapter string                 `json:"last_chapter,omitempty"`
+	Recap       string                 `json:"recap,omitempty"` // the narrator's recap of the last chapter
+	Threads     []*Thread              `json:"threads,omitempty"`
+	Conflicts   map[string][]*Conflict `json:"conflicts,omitempty"` // by rig ("" for the town)
+}
+
+// Thread is an open plot thread: a storyline the narrator started and has
+// not yet resolved.
+type Thread struct {
+	ID       string `json:"id"` // short handle, e.g. "refinery-feud"
+	Title    string `json:"title"`
+	Rig      string `json:"rig,omitempty"`
+	Opened   int    `json:"opened"`    // chapter it was opened in
+	LastSeen int    `json:"last_seen"` // chapter it last appeared in
+}
+
+// Conflict is a kind of trouble recurring in a rig.
+type Conflict struct {
+	Type     string `json:"type"`     // event type, e.g. "merge_failed"
+	Chapters int    `json:"chapters"` // chapters it appeared in
+	Events   int    `json:"events"`   // events across those chapters
+	LastSeen int    `json:"last_seen"`
+}
+
+// NewStory returns the story of a town with no chapters yet.
+func NewStory() *Story {
+	return &Story{Type: "story", Version: 1, Conflicts: make(map[string][]*Conflict)}
+}
+
+// LoadStory loads the narrator's story state. A town without
+// narrator/story.json has no chapters yet.
+func LoadStory(townRoot string) (*Story, error) {
+	data, err := os.ReadFile(StoryPath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return NewStory(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading story state: %w", err)
+	}
+	s := NewStory()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing story state: %w", err)
+	}
+	if s.Conflicts == nil {
+		s.Conflicts = make(map[string][]*Conflict)
+	}
+	return s, nil
+}
+
+// Save writes the story state to the town's narrator directory.
+func (s *Story) Save(townRoot string) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating narrator dir: %w", err)
+	}
+	return util.AtomicWriteJSON(StoryPath(townRoot), s)
+}
+
+// Recurring returns a rig's conflicts seen in more than one chapter, most
+// frequent first.
+func (s *Story) Recurring(rig string) []*Conflict {
+	var list []*Conflict
+	for _, c := range s.Conflicts[rig] {
+		if c.Chapters > 1 {
+			list = append(list, c)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Chapters > list[j].Chapters })
+	return list
+}
+
+// StoryUpdate is the narrator's account of how a chapter moved the story
+// along, given at the end of its reply.
+type StoryUpdate struct {
+	Recap    string    `json:"recap"`
+	Opened   []*Thread `json:"opened,omitempty"`   // new threads (id, title, rig)
+	Advanced []string  `json:"advanced,omitempty"` // IDs of threads the chapter continued
+	Resolved []string  `json:"resolved,omitempty"` // IDs of threads the chapter closed
+}
+
+// storyBlockRe matches the fenced story block ending a narrator's reply.
+var storyBlockRe = regexp.MustCompile("(?s)\n?```story[ \t]*\n(.*?)\n```[ \t]*$")
+
+// SplitStoryUpdate separates a reply into the chapter text and the story
+// block at its end. A missing or malformed block yields a nil update; the
+// block is removed from the text either way.
+func SplitStoryUpdate(reply string) (string, *StoryUpdate) {
+	reply = strings.TrimSpace(reply)
+	m := storyBlockRe.FindStringSubmatchIndex(reply)
+	if m == nil {
+		return reply, nil
+	}
+	text := strings.TrimSpace(reply[:m[0]])
+	var u StoryUpdate
+	if err := json.Unmarshal([]byte(reply[m[2]:m[3]]), &u); err != nil {
+		return text, nil
+	}
+	return text, &u
+}
+
+// threads returns the titles of the threads an update opened, advanced or
+// resolved in s, before it is applied. A nil update touches none.
+func (u *StoryUpdate) threads(s *Story) []string {
+	if u == nil {
+		return nil
+	}
+	byID := make(map[string]*Thread, len(s.Threads))
+	for _, t := range s.Threads {
+		byID[t.ID] = t
+	}
+	var titles []string
+	for _, id := range append(append([]string{}, u.Advanced...), u.Resolved...) {
+		if t, ok := byID[id]; ok {
+			titles = append(titles, t.Title)
+		}
+	}
+	for _, t := range u.Opened {
+		if t != nil && t.Title != "" && byID[t.ID] == nil {
+			titles = append(titles, t.Title)
+		}
+	}
+	return titles
+}
+
+// Advance records a chapter written from evts at path: it numbers the
+// chapter, applies the narrator's update (which may be nil), tallies the
+// rigs' conflicts, and forgets threads and conflicts gone quiet.
+func (s *Story) Advance(path string, evts []events.Event, u *StoryUpdate) {
+	s.Chapter++
+	s.LastChapter = path
+	n := s.Chapter
+
+	if u != nil {
+		s.Recap = strings.TrimSpace(u.Recap)
+		byID := make(map[string]*Thread, len(s.Threads))
+		for _, t := range s.Threads {
+			byID[t.ID] = t
+		}
+		for _, id := range u.Advanced {
+			if t, ok := byID[id]; ok {
+				t.LastSeen = n
+			}
+		}
+		for _, t := range u.Opened {
+			if t == nil || t.ID == "" || t.Title == "" || byID[t.ID] != nil {
+				continue
+			}
+			thread := &Thread{ID: t.ID, Title: t.Title, Rig: t.Rig, Opened: n, LastSeen: n}
+			byID[t.ID] = thread
+			s.Threads = append(s.Threads, thread)
+		}
+		resolved := make(map[string]bool, len(u.Resolved))
+		for _, id := range u.Resolved {
+			resolved[id] = true
+		}
+		kept := s.Threads[:0]
+		for _, t := range s.Threads {
+			if !resolved[t.ID] {
+				kept = append(kept, t)
+			}
+		}
+		s.Threads = kept
+	}
+
+	kept := s.Threads[:0]
+	for _, t := range s.Threads {
+		if n-t.LastSeen < threadTTL {
+			kept = append(kept, t)
+		}
+	}
+	s.Threads = kept
+	if len(s.Threads) > maxThreads {
+		sort.SliceStable(s.Threads, func(i, j int) bool { return s.Threads[i].LastSeen > s.Threads[j].LastSeen })
+		s.Threads = s.Threads[:maxThreads]
+	}
+
+	s.tallyConflicts(n, evts)
+}
+
+func (s *Story) tallyConflicts(n int, evts []events.Event) {
+	counts := make(map[string]map[string]int)
+	for _, e := range evts {
+		typ := e.Type
+		if typ == events.TypeIncident {
+			typ, _ = e.Payload["type"].(string)
+		}
+		if !conflictTypes[typ] {
+			continue
+		}
+		rig := events.EventRig(e)
+		if counts[rig] == nil {
+			counts[rig] = make(map[string]int)
+		}
+		counts[rig][typ]++
+	}
+	for rig, byType := range counts {
+		for typ, count := range byType {
+			var c *Conflict
+			for _, existing := range s.Conflicts[rig] {
+				if existing.Type == typ {
+					c = existing
+					break
+				}
+			}
+			if c == nil {
+				c = &Conflict{Type: typ}
+				s.Conflicts[rig] = append(s.Conflicts[rig], c)
+			}
+			c.Chapters++
+			c.Events += count
+			c.LastSeen = n
+		}
+	}
+	for rig, list := range s.Conflicts {
+		kept := list[:0]
+		for _, c := range list {
+			if n-c.LastSeen < conflictTTL {
+				kept = append(kept, c)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.Conflicts, rig)
+		} else {
+			s.Conflicts[rig] = kept
+		}
+	}
+}
+
+// writeStory adds the story so far to a prompt, and asks for the story
+// block that updates it.
+func writeStory(b *strings.Builder, s *Story) {
+	b.WriteString("## Story so far\n\n")
+	fmt.Fprintf(b, "This is chapter %d.", s.Chapter+1)
+	if s.Chapter > 0 {
+		b.WriteString(" Refer back to earlier chapters where events continue them.")
+	}
+	b.WriteString("\n")
+	if s.Recap != "" {
+		fmt.Fprintf(b, "\nLast chapter: %s\n", s.Recap)
+	}
+	if len(s.Threads) > 0 {
+		b.WriteString("\nOpen plot threads:\n")
+		for _, t := range s.Threads {
+			fmt.Fprintf(b, "- [%s] %s", t.ID, t.Title)
+			if t.Rig != "" {
+				fmt.Fprintf(b, " (%s)", t.Rig)
+			}
+			fmt.Fprintf(b, ", since chapter %d\n", t.Opened)
+		}
+	}
+	rigs := make([]string, 0, len(s.Conflicts))
+	for rig := range s.Conflicts {
+		if len(s.Recurring(rig)) > 0 {
+			rigs = append(rigs, rig)
+		}
+	}
+	sort.Strings(rigs)
+	if len(rigs) > 0 {
+		b.WriteString("\nRecurring conflicts:\n")
+		for _, rig := range rigs {
+			name := rig
+			if name == "" {
+				name = "town"
+			}
+			var parts []string
+			for _, c := range s.Recurring(rig) {
+				parts = append(parts, fmt.Sprintf("%s in %d chapters", c.Type, c.Chapters))
+			}
+			fmt.Fprintf(b, "- %s: %s\n", name, strings.Join(parts, ", "))
+		}
+	}
+	b.WriteString("\nAfter the chapter, end your reply with a fenced code block tagged `story` holding JSON: ")
+	b.WriteString(`{"recap": "<one or two sentences>", "opened": [{"id": "<short-handle>", "title": "...", "rig": "..."}], "advanced": ["<id>"], "resolved": ["<id>"]}`)
+	b.WriteString(". List threads this chapter started, continued and concluded. It is removed from the chapter.\n\n")
+}
@@ -0,0 +1,119 @@
+package narrator
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestSplitStoryUpdate(t *testing.T) {
+	reply := "# Chapter 2\nThe refinery sulked.\n\n```story\n" +
+		`{"recap":"The refinery sulked.","opened":[{"id":"sulk","title":"The sulking refinery","rig":"gastown"}],"resolved":["feud"]}` +
+		"\n```\n"
+	text, u := SplitStoryUpdate(reply)
+	if text != "# Chapter 2\nThe refinery sulked." {
+		t.Errorf("text = %q", text)
+	}
+	if u == nil || u.Recap != "The refinery sulked." || len(u.Opened) != 1 || u.Resolved[0] != "feud" {
+		t.Errorf("update = %+v", u)
+	}
+
+	if text, u := SplitStoryUpdate("Plain chapter.\n"); text != "Plain chapter." || u != nil {
+		t.Errorf("without a block = %q, %+v", text, u)
+	}
+	if text, u := SplitStoryUpdate("Chapter.\n```story\nnot json\n```"); text != "Chapter." || u != nil {
+		t.Errorf("malformed block = %q, %+v; want it stripped and ignored", text, u)
+	}
+}
+
+func TestStoryAdvance(t *testing.T) {
+	s := NewStory()
+	failed := events.Event{Type: events.TypeMergeFailed, Actor: "gastown/refinery"}
+
+	s.Advance("c1.md", []events.Event{failed, failed}, &StoryUpdate{
+		Recap:  "A feud began.",
+		Opened: []*Thread{{ID: "feud", Title: "Refinery vs. Toast", Rig: "gastown"}, {ID: "", Title: "no id"}},
+	})
+	if s.Chapter != 1 || s.Recap != "A feud began." || len(s.Threads) != 1 || s.Threads[0].Opened != 1 {
+		t.Fatalf("after chapter 1 = %+v", s)
+	}
+	if len(s.Recurring("gastown")) != 0 {
+		t.Error("a conflict from one chapter counted as recurring")
+	}
+
+	s.Advance("c2.md", []events.Event{failed}, &StoryUpdate{Advanced: []string{"feud"}})
+	if got := s.Recurring("gastown"); len(got) != 1 || got[0].Chapters != 2 || got[0].Events != 3 {
+		t.Errorf("recurring = %+v, want merge_failed in 2 chapters, 3 events", got)
+	}
+	if s.Threads[0].LastSeen != 2 {
+		t.Errorf("advanced thread last seen in %d, want 2", s.Threads[0].LastSeen)
+	}
+
+	// Threads unmentioned for threadTTL chapters are forgotten
+	for i := 0; i < threadTTL; i++ {
+		s.Advance("c.md", nil, nil)
+	}
+	if len(s.Threads) != 0 {
+		t.Errorf("threads = %+v, want the quiet thread dropped", s.Threads)
+	}
+
+	s.Threads = []*Thread{{ID: "a", Title: "A", LastSeen: s.Chapter}}
+	s.Advance("c.md", nil, &StoryUpdate{Resolved: []string{"a"}})
+	if len(s.Threads) != 0 {
+		t.Errorf("resolved thread kept: %+v", s.Threads)
+	}
+}
+
+func TestGenerate_Story(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now(), `"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+
+	var prompts []string
+	orig := runAgent
+	runAgent = func(_ context.Context, dir string, argv []string) ([]byte, error) {
+		prompts = append(prompts, argv[len(argv)-1])
+		return []byte("The merge failed.\n```story\n" +
+			`{"recap":"The merge failed.","opened":[{"id":"broken-merge","title":"The broken merge"}]}` +
+			"\n```\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	opts := GenerateOptions{Since: time.Now().Add(-time.Hour), Agent: "claude"}
+	for i := 1; i <= 2; i++ {
+		opts.Output = Dir(townRoot) + "/chapter.md"
+		chapter, err := Generate(context.Background(), townRoot, opts)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if chapter.Number != i {
+			t.Errorf("chapter number = %d, want %d", chapter.Number, i)
+		}
+	}
+	if !strings.Contains(prompts[0], "This is chapter 1.") {
+		t.Errorf("first prompt:\n%s", prompts[0])
+	}
+	for _, want := range []string{"This is chapter 2.", "Last chapter: The merge failed.", "[broken-merge] The broken merge, since chapter 1"} {
+		if !strings.Contains(prompts[1], want) {
+			t.Errorf("second prompt missing %q:\n%s", want, prompts[1])
+		}
+	}
+	data, err := os.ReadFile(opts.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "```story") {
+		t.Errorf("chapter kept the story block:\n%s", data)
+	}
+	story, err := LoadStory(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := story.Recurring("gastown"); len(got) != 1 || got[0].Type != events.TypeMergeFailed {
+		t.Errorf("recurring conflicts = %+v, want merge_failed", got)
+	}
+}
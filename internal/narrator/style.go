@@ -0,0 +1,312 @@
+package narrator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Chapter formats a style can ask for.
+const (
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+	FormatHTML     = "html"
+	FormatFountain = "fountain" // screenplay markup
+)
+
+// formats describes each chapter format to the agent and names the
+// extension of the chapter file.
+var formats = map[string]struct{ describe, ext string }{
+	FormatMarkdown: {"Markdown", ".md"},
+	FormatText:     {"plain text", ".txt"},
+	FormatHTML:     {"an HTML fragment", ".html"},
+	FormatFountain: {"Fountain screenplay markup", ".fountain"},
+}
+
+// Ways a style can group the events in its prompt.
+const (
+	GroupByRig   = "rig"
+	GroupByDay   = "day"
+	GroupByActor = "actor"
+)
+
+// Style is a narration style: the style guide handed to the agent and the
+// shape of the chapter it asks for. A style is either a template,
+// narrator/styles/<name>.md, used as the style guide as it is, or a
+// definition, narrator/styles/<name>.yaml, which can set the rest too:
+//
+//	description: Two hosts talk through the day in the town
+//	format: text
+//	prompt: |
+//	  Write chapter {{.Chapter}} as a podcast transcript...
+//	chunking:
+//	  max_events: 150
+//	  group_by: day
+//	length:
+//	  min_words: 800
+//	  max_words: 1500
+//
+// A definition's prompt is a Go template over StyleData.
+type Style struct {
+	Name        string        `yaml:"-" json:"name"`
+	Path        string        `yaml:"-" json:"path"`
+	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
+	Prompt      string        `yaml:"prompt" json:"prompt"`
+	Format      string        `yaml:"format,omitempty" json:"format"` // "" means markdown
+	Chunking    StyleChunking `yaml:"chunking,omitempty" json:"chunking,omitempty"`
+	Length      StyleLength   `yaml:"length,omitempty" json:"length,omitempty"`
+
+	tmpl *template.Template // nil for a plain template
+}
+
+// StyleChunking shapes the events section of the prompt.
+type StyleChunking struct {
+	// MaxEvents caps the events in a chapter, keeping the newest; 0 means
+	// the narrator's own cap.
+	MaxEvents int `yaml:"max_events,omitempty" json:"max_events,omitempty"`
+	// GroupBy lists the events under a heading per rig, day or actor, for
+	// styles told in scenes; "" lists them in one run.
+	GroupBy string `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+}
+
+// StyleLength is a chapter's target length in words; 0 leaves a bound open.
+type StyleLength struct {
+	MinWords int `yaml:"min_words,omitempty" json:"min_words,omitempty"`
+	MaxWords int `yaml:"max_words,omitempty" json:"max_words,omitempty"`
+}
+
+// StyleData is what a style definition's prompt template can refer to.
+type StyleData struct {
+	Chapter int       // number of the chapter being written
+	Rig     string    // "" when narrating the whole town
+	Since   time.Time // start of the narrated range
+	Until   time.Time // end of the narrated range; zero means now
+	Events  int       // events in the prompt
+
+	// Tone and Intensity are narrator.tone and narrator.intensity ("" and
+	// 0 if unset), for a style that words itself by them
+	Tone      string
+	Intensity int
+}
+
+// StylesDir returns the directory of a town's styles.
+func StylesDir(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "styles")
+}
+
+// ListStyles returns the names of a town's styles, templates and
+// definitions alike.
+func ListStyles(townRoot string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, ext := range []string{".md", ".yaml"} {
+		matches, _ := filepath.Glob(filepath.Join(StylesDir(townRoot), "*"+ext))
+		for _, m := range matches {
+			name := strings.TrimSuffix(filepath.Base(m), ext)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadStyles loads all of a town's styles, sorted by name.
+func LoadStyles(townRoot string) ([]*Style, error) {
+	var styles []*Style
+	for _, name := range ListStyles(townRoot) {
+		s, err := LoadStyle(townRoot, name)
+		if err != nil {
+			return nil, err
+		}
+		styles = append(styles, s)
+	}
+	return styles, nil
+}
+
+// LoadStyle loads the style narrator/styles/<name>.yaml, or else the
+// template narrator/styles/<name>.md.
+func LoadStyle(townRoot, name string) (*Style, error) {
+	if name == "" {
+		name = DefaultStyle
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid style name %q", name)
+	}
+	base := filepath.Join(StylesDir(townRoot), name)
+	data, err := os.ReadFile(base + ".yaml") //nolint:gosec // G304: name is checked to stay in the styles dir
+	if err == nil {
+		s, err := parseStyle(data)
+		if err != nil {
+			return nil, fmt.Errorf("style %s: %w", name, err)
+		}
+		s.Name, s.Path = name, base+".yaml"
+		return s, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading style: %w", err)
+	}
+
+	data, err = os.ReadFile(base + ".md") //nolint:gosec // G304: name is checked to stay in the styles dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unknown style %q (available: %s)", name, strings.Join(ListStyles(townRoot), ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading style: %w", err)
+	}
+	return &Style{Name: name, Path: base + ".md", Prompt: string(data), Format: FormatMarkdown}, nil
+}
+
+// parseStyle parses and checks a style definition.
+func parseStyle(data []byte) (*Style, error) {
+	var s Style
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	if strings.TrimSpace(s.Prompt) == "" {
+		return nil, errors.New("no prompt")
+	}
+	if s.Format == "" {
+		s.Format = FormatMarkdown
+	}
+	if _, ok := formats[s.Format]; !ok {
+		return nil, fmt.Errorf("unknown format %q (want markdown, text, html or fountain)", s.Format)
+	}
+	switch s.Chunking.GroupBy {
+	case "", GroupByRig, GroupByDay, GroupByActor:
+	default:
+		return nil, fmt.Errorf("unknown chunking.group_by %q (want rig, day or actor)", s.Chunking.GroupBy)
+	}
+	if s.Chunking.MaxEvents < 0 {
+		return nil, errors.New("chunking.max_events is negative")
+	}
+	if s.Length.MinWords < 0 || s.Length.MaxWords < 0 {
+		return nil, errors.New("length targets are negative")
+	}
+	if s.Length.MaxWords > 0 && s.Length.MinWords > s.Length.MaxWords {
+		return nil, fmt.Errorf("length.min_words %d is over length.max_words %d", s.Length.MinWords, s.Length.MaxWords)
+	}
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(s.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt: %w", err)
+	}
+	s.tmpl = tmpl
+	return &s, nil
+}
+
+// Guide renders the style guide for a chapter.
+func (s *Style) Guide(data StyleData) (string, error) {
+	if s.tmpl == nil {
+		return strings.TrimSpace(s.Prompt), nil
+	}
+	var b strings.Builder
+	if err := s.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("style %s: rendering prompt: %w", s.Name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Ext returns the extension of the style's chapter files.
+func (s *Style) Ext() string {
+	if f, ok := formats[s.Format]; ok {
+		return f.ext
+	}
+	return ".md"
+}
+
+// describeFormat names the style's chapter format for the agent.
+func (s *Style) describeFormat() string {
+	if f, ok := formats[s.Format]; ok {
+		return f.describe
+	}
+	return "Markdown"
+}
+
+// maxEvents returns the most events a chapter in the style can hold.
+func (s *Style) maxEvents() int {
+	if s.Chunking.MaxEvents > 0 && s.Chunking.MaxEvents < maxPromptEvents {
+		return s.Chunking.MaxEvents
+	}
+	return maxPromptEvents
+}
+
+// lengthTarget tells the agent the style's target length, or "" if it
+// has none.
+func (s *Style) lengthTarget() string {
+	lo, hi := s.Length.MinWords, s.Length.MaxWords
+	switch {
+	case lo > 0 && hi > 0:
+		return fmt.Sprintf("Aim for %d to %d words.", lo, hi)
+	case lo > 0:
+		return fmt.Sprintf("Write at least %d words.", lo)
+	case hi > 0:
+		return fmt.Sprintf("Keep it under %d words.", hi)
+	}
+	return ""
+}
+
+// CheckLength reports how a chapter of the given word count misses the
+// style's length target, or "" if it doesn't.
+func (s *Style) CheckLength(words int) string {
+	switch {
+	case s.Length.MinWords > 0 && words < s.Length.MinWords:
+		return fmt.Sprintf("%d words, under the style's %d-word minimum", words, s.Length.MinWords)
+	case s.Length.MaxWords > 0 && words > s.Length.MaxWords:
+		return fmt.Sprintf("%d words, over the style's %d-word maximum", words, s.Length.MaxWords)
+	}
+	return ""
+}
+
+// eventGroup is a run of events listed under one heading in a prompt.
+type eventGroup struct {
+	label  string
+	events []events.Event
+}
+
+// groupEvents splits evts into groups by rig, day or actor, in order of
+// first appearance. With by "" it returns one unlabeled group.
+func groupEvents(evts []events.Event, by string) []eventGroup {
+	if by == "" {
+		return []eventGroup{{events: evts}}
+	}
+	var groups []eventGroup
+	index := make(map[string]int)
+	for _, e := range evts {
+		var label string
+		switch by {
+		case GroupByRig:
+			if label = events.EventRig(e); label == "" {
+				label = "town"
+			}
+		case GroupByDay:
+			label = e.Timestamp
+			if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+				label = ts.UTC().Format("2006-01-02")
+			}
+		case GroupByActor:
+			label = e.Actor
+		}
+		n, ok := index[label]
+		if !ok {
+			n = len(groups)
+			index[label] = n
+			groups = append(groups, eventGroup{label: label})
+		}
+		groups[n].events = append(groups[n].events, e)
+	}
+	return groups
+}
@@ -0,0 +1,99 @@
+package narrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeStyleDef(t *testing.T, townRoot, name, def string) {
+	t.Helper()
+	if err := os.MkdirAll(StylesDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(StylesDir(townRoot), name+".yaml"), []byte(def), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStyleDefinition(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeStyleDef(t, townRoot, "podcast", `
+description: Two hosts talk through the day
+format: text
+prompt: |
+  Write episode {{.Chapter}} as a podcast transcript{{if .Rig}} about {{.Rig}}{{end}}.
+chunking:
+  max_events: 2
+  group_by: rig
+length:
+  min_words: 5
+  max_words: 10
+`)
+	if got := ListStyles(townRoot); strings.Join(got, ",") != "default,podcast" {
+		t.Errorf("ListStyles = %v", got)
+	}
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`,
+		`"type":"merge_failed","actor":"beads/refinery","payload":{"rig":"beads"}}`,
+		`"type":"merged","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+
+	prompt, err := BuildPrompt(townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Style: "podcast"})
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	if prompt.Events != 2 || prompt.Omitted != 1 {
+		t.Errorf("prompt has %d events, %d omitted; want chunking.max_events to keep the newest 2", prompt.Events, prompt.Omitted)
+	}
+	for _, want := range []string{"in plain text", "Write episode 1 as a podcast transcript.", "Aim for 5 to 10 words.", "### beads\n", "### gastown\n"} {
+		if !strings.Contains(prompt.Text, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt.Text)
+		}
+	}
+
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) {
+		return []byte("Welcome back to the show.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+	chapter, err := Generate(context.Background(), townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Style: "podcast", Agent: "claude"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if filepath.Ext(chapter.Path) != ".txt" || chapter.Words != 5 || chapter.Length != "" {
+		t.Errorf("chapter = %+v, want a 5-word .txt chapter within the target", chapter)
+	}
+}
+
+func TestStyleDefinition_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no prompt":      "format: text\n",
+		"bad format":     "prompt: x\nformat: pdf\n",
+		"bad group":      "prompt: x\nchunking: {group_by: week}\n",
+		"unknown field":  "prompt: x\nlenght: {max_words: 5}\n",
+		"bad template":   "prompt: '{{.Chapter'\n",
+		"inverted range": "prompt: x\nlength: {min_words: 10, max_words: 5}\n",
+	}
+	for name, def := range tests {
+		if _, err := parseStyle([]byte(def)); err == nil {
+			t.Errorf("%s: parsed %q", name, def)
+		}
+	}
+}
+
+func TestStyle_CheckLength(t *testing.T) {
+	s := &Style{Length: StyleLength{MinWords: 100, MaxWords: 200}}
+	if got := s.CheckLength(150); got != "" {
+		t.Errorf("CheckLength(150) = %q, want none", got)
+	}
+	if got := s.CheckLength(50); !strings.Contains(got, "under") {
+		t.Errorf("CheckLength(50) = %q", got)
+	}
+	if got := s.CheckLength(250); !strings.Contains(got, "over") {
+		t.Errorf("CheckLength(250) = %q", got)
+	}
+}
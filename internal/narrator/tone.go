@@ -0,0 +1,144 @@
+package narrator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Tones narration can be set to, for narrator.tone.
+const (
+	ToneNeutral  = "corporate-neutral"
+	ToneGrimdark = "grimdark"
+	ToneComedic  = "comedic"
+	ToneEpic     = "epic"
+)
+
+// Tones are the tones narration can be set to.
+var Tones = []string{ToneNeutral, ToneGrimdark, ToneComedic, ToneEpic}
+
+// Intensity bounds how strongly a tone is laid on, for narrator.intensity.
+const (
+	MinIntensity     = 1
+	MaxIntensity     = 5
+	DefaultIntensity = 3
+)
+
+// toneWords is how a tone reads: the instruction the agent is given, and
+// the wording of the summaries written without it.
+type toneWords struct {
+	guide      string
+	aside      string // closes a summary's opening line, given the event count
+	happened   string // heading of a summary's counts
+	highlights string // heading of a summary's highlights
+}
+
+var tones = map[string]toneWords{
+	ToneNeutral: {
+		guide:      "Write in a neutral, professional register, as for a standup or a status report: plain statements of what was done, what is blocked and what needs attention. No jokes, drama or figurative language.",
+		happened:   "Activity",
+		highlights: "Items of note",
+	},
+	ToneGrimdark: {
+		guide:      "Write it grimdark: a bleak, doom-laden chronicle where every merge is a pyrrhic victory, every failure an omen, and the agents toil on against a hostile codebase.",
+		aside:      "Another %d entries in the ledger of the damned.",
+		happened:   "What befell the town",
+		highlights: "Omens",
+	},
+	ToneComedic: {
+		guide:      "Write it as comedy: wry, affectionate and absurd, finding the joke in the agents' mishaps and small triumphs without mocking the people behind them.",
+		aside:      "%d events, and somehow the refinery is still standing.",
+		happened:   "The damage report",
+		highlights: "Best bits",
+	},
+	ToneEpic: {
+		guide:      "Write it as an epic: sweeping and heroic, the agents as legendary figures and their work as great deeds worthy of song.",
+		aside:      "Let the bards remember these %d deeds.",
+		happened:   "The deeds",
+		highlights: "Great moments",
+	},
+}
+
+// intensityWords says how strongly to lay a tone on, by intensity.
+var intensityWords = map[int]string{
+	1: "Keep it to a light touch: mostly plain reporting, with only a hint of the tone.",
+	2: "Lay it on lightly; the facts come first.",
+	3: "Commit to the tone, but keep every fact clear.",
+	4: "Lay it on thick.",
+	5: "Go all the way and never break character, though every fact must still be there.",
+}
+
+// Tone is the town's narration tone and how strongly it is laid on. The
+// zero Tone leaves the register to the style.
+type Tone struct {
+	Name      string `json:"name,omitempty"`
+	Intensity int    `json:"intensity,omitempty"`
+}
+
+// CheckTone reports an error if name isn't a known tone.
+func CheckTone(name string) error {
+	if _, ok := tones[name]; !ok {
+		return fmt.Errorf("unknown tone %q (want %s)", name, strings.Join(Tones, ", "))
+	}
+	return nil
+}
+
+// parseIntensity parses a narrator.intensity setting.
+func parseIntensity(v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < MinIntensity || n > MaxIntensity {
+		return 0, fmt.Errorf("invalid intensity %q (want %d to %d)", v, MinIntensity, MaxIntensity)
+	}
+	return n, nil
+}
+
+// LoadTone returns the town's narrator.tone and narrator.intensity. An
+// unknown tone is ignored, as if unset.
+func LoadTone(townRoot string) Tone {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil || CheckTone(settings.Narrator.Tone) != nil {
+		return Tone{}
+	}
+	t := Tone{Name: settings.Narrator.Tone, Intensity: settings.Narrator.Intensity}
+	if t.Intensity < MinIntensity || t.Intensity > MaxIntensity {
+		t.Intensity = DefaultIntensity
+	}
+	return t
+}
+
+// Guide returns the tone's instruction to the narrating agent, or "" for
+// the zero Tone.
+func (t Tone) Guide() string {
+	words, ok := tones[t.Name]
+	if !ok {
+		return ""
+	}
+	return words.guide + " " + intensityWords[t.Intensity]
+}
+
+// writeTone adds the tone to a prompt.
+func writeTone(b *strings.Builder, t Tone) {
+	if guide := t.Guide(); guide != "" {
+		fmt.Fprintf(b, "## Tone\n\n%s\n\n", guide)
+	}
+}
+
+// summaryIntro returns a summary's opening line in the tone: the plain
+// line, with the tone's aside from intensity 2 on.
+func (t Tone) summaryIntro(line string, events int) string {
+	if words, ok := tones[t.Name]; ok && words.aside != "" && t.Intensity >= 2 {
+		return line + " " + fmt.Sprintf(words.aside, events)
+	}
+	return line
+}
+
+// headings returns the headings of a summary's counts and highlights in
+// the tone, which a tone laid on lightly keeps plain.
+func (t Tone) headings() (happened, highlights string) {
+	if words, ok := tones[t.Name]; ok && (t.Intensity >= DefaultIntensity || t.Name == ToneNeutral) {
+		return words.happened, words.highlights
+	}
+	return "What happened", "Highlights"
+}
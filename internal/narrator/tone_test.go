@@ -0,0 +1,89 @@
+package narrator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestTone(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := LoadTone(townRoot); got != (Tone{}) {
+		t.Errorf("LoadTone unset = %+v, want the zero Tone", got)
+	}
+	if err := Set(townRoot, "tone", "whimsical"); err == nil {
+		t.Error("Set tone whimsical succeeded")
+	}
+	for _, bad := range []string{"0", "6", "loud"} {
+		if err := Set(townRoot, "intensity", bad); err == nil {
+			t.Errorf("Set intensity %s succeeded", bad)
+		}
+	}
+	if err := Set(townRoot, "tone", ToneEpic); err != nil {
+		t.Fatalf("Set tone: %v", err)
+	}
+	if got := LoadTone(townRoot); got != (Tone{Name: ToneEpic, Intensity: DefaultIntensity}) {
+		t.Errorf("LoadTone = %+v, want epic at the default intensity", got)
+	}
+	if err := Set(townRoot, "intensity", "5"); err != nil {
+		t.Fatalf("Set intensity: %v", err)
+	}
+	if got := LoadTone(townRoot); got.Intensity != 5 {
+		t.Errorf("LoadTone intensity = %d, want 5", got.Intensity)
+	}
+
+	writeStyleDef(t, townRoot, "bard", `
+prompt: |
+  Tell it{{if eq .Tone "epic"}} as a saga, at full pitch {{.Intensity}}{{end}}.
+`)
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	prompt, err := BuildPrompt(townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Style: "bard"})
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	for _, want := range []string{"Tell it as a saga, at full pitch 5.", "## Tone\n\nWrite it as an epic", "never break character"} {
+		if !strings.Contains(prompt.Text, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt.Text)
+		}
+	}
+
+	if err := Set(townRoot, "tone", ""); err != nil {
+		t.Fatal(err)
+	}
+	prompt, err = BuildPrompt(townRoot, GenerateOptions{Since: time.Now().Add(-time.Hour), Style: "bard"})
+	if err != nil {
+		t.Fatalf("BuildPrompt: %v", err)
+	}
+	if strings.Contains(prompt.Text, "## Tone") || !strings.Contains(prompt.Text, "Tell it.") {
+		t.Errorf("prompt without a tone:\n%s", prompt.Text)
+	}
+}
+
+func TestTone_Summaries(t *testing.T) {
+	c := &CatchUp{
+		Events: 2,
+		ByType: map[string]int{events.TypeMerged: 2},
+	}
+	tests := []struct {
+		tone             Tone
+		heading, without string
+	}{
+		{Tone{}, "## What happened", "ledger"},
+		{Tone{Name: ToneNeutral, Intensity: 5}, "## Activity", "What happened"},
+		{Tone{Name: ToneGrimdark, Intensity: 3}, "## What befell the town", "What happened"},
+		{Tone{Name: ToneComedic, Intensity: 1}, "## What happened", "refinery is still standing"},
+		{Tone{Name: ToneComedic, Intensity: 4}, "## The damage report", "What happened"},
+	}
+	for _, tt := range tests {
+		got := c.Markdown(nil, tt.tone)
+		if !strings.Contains(got, tt.heading) || strings.Contains(got, tt.without) {
+			t.Errorf("%+v: catch-up lacks %q or has %q:\n%s", tt.tone, tt.heading, tt.without, got)
+		}
+	}
+	if got := c.Markdown(nil, Tone{Name: ToneGrimdark, Intensity: 2}); !strings.Contains(got, "events happened. Another 2 entries in the ledger of the damned.") {
+		t.Errorf("grimdark catch-up lacks its aside:\n%s", got)
+	}
+}
@@ -0,0 +1,211 @@
+package narrator
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultTriggerAddr is where 'gt narrator serve' listens unless
+// narrator.trigger.addr or --addr says otherwise: this host only, since an
+// unauthenticated endpoint spends agent runs.
+const DefaultTriggerAddr = "127.0.0.1:9466"
+
+// DefaultTriggerHours is how far back a trigger narrates when it doesn't
+// say.
+const DefaultTriggerHours = 24
+
+// maxTriggerBody caps a trigger request's body.
+const maxTriggerBody = 64 << 10
+
+// TriggerRequest asks for a chapter of the last Hours of a rig's events,
+// or the town's if Rig is "".
+type TriggerRequest struct {
+	Rig          string  `json:"rig,omitempty"`
+	Hours        float64 `json:"hours,omitempty"` // default DefaultTriggerHours
+	Style        string  `json:"style,omitempty"`
+	Significance string  `json:"significance,omitempty"`
+}
+
+// TriggerResponse is the chapter a trigger wrote and where to fetch it.
+type TriggerResponse struct {
+	URL     string   `json:"url"`
+	Review  string   `json:"review,omitempty"` // ReviewPending if it awaits review, and isn't served until approved
+	Chapter *Chapter `json:"chapter"`
+}
+
+// triggerSettings returns the town's narrator.trigger setting, or an empty
+// one.
+func triggerSettings(townRoot string) *config.NarratorTrigger {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Narrator == nil || settings.Narrator.Trigger == nil {
+		return &config.NarratorTrigger{}
+	}
+	return settings.Narrator.Trigger
+}
+
+// TriggerAddr returns the address 'gt narrator serve' listens on.
+func TriggerAddr(townRoot string) string {
+	if addr := triggerSettings(townRoot).Addr; addr != "" {
+		return addr
+	}
+	return DefaultTriggerAddr
+}
+
+// TriggerHandler returns the narrator's on-demand HTTP endpoint:
+//
+//	POST /narrate        narrate now (a TriggerRequest) and reply with a TriggerResponse
+//	GET  /chapters/{id}  a chapter, by ChapterID
+//
+// With narrator.trigger.secret set, a POST must be signed as event
+// webhooks are (see events.Sign). Narration runs within the request, like
+// 'gt narrator generate', so a caller should allow for the agent's time.
+func TriggerHandler(townRoot string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /narrate", func(w http.ResponseWriter, r *http.Request) {
+		serveNarrate(townRoot, w, r)
+	})
+	mux.HandleFunc("GET /chapters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		serveChapter(townRoot, w, r)
+	})
+	return mux
+}
+
+func serveNarrate(townRoot string, w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTriggerBody))
+	if err != nil {
+		triggerError(w, http.StatusBadRequest, fmt.Errorf("reading request: %w", err))
+		return
+	}
+	settings := triggerSettings(townRoot)
+	secret := settings.Secret
+	if settings.SecretEnv != "" {
+		secret = os.Getenv(settings.SecretEnv)
+	}
+	if secret != "" {
+		got := strings.TrimPrefix(r.Header.Get(events.HeaderSignature), "sha256=")
+		if !hmac.Equal([]byte(got), []byte(events.Sign(secret, body))) {
+			triggerError(w, http.StatusUnauthorized, errors.New("bad or missing "+events.HeaderSignature))
+			return
+		}
+	}
+	var req TriggerRequest
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			triggerError(w, http.StatusBadRequest, fmt.Errorf("parsing request: %w", err))
+			return
+		}
+	}
+	if err := CheckRigName(req.Rig); err != nil {
+		triggerError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Hours < 0 {
+		triggerError(w, http.StatusBadRequest, fmt.Errorf("invalid hours %v", req.Hours))
+		return
+	}
+	if req.Hours == 0 {
+		req.Hours = DefaultTriggerHours
+	}
+	if req.Significance != "" {
+		if err := events.ValidSignificance(req.Significance); err != nil {
+			triggerError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	opts := GenerateOptions{
+		Since:        time.Now().UTC().Add(-time.Duration(req.Hours * float64(time.Hour))),
+		Rig:          req.Rig,
+		Style:        req.Style,
+		Significance: req.Significance,
+	}
+	logger.Info("narration triggered", "rig", req.Rig, "hours", req.Hours, "from", r.RemoteAddr)
+
+	chapter, err := Generate(r.Context(), townRoot, opts)
+	var quotaErr *QuotaError
+	switch {
+	case errors.Is(err, ErrNoEvents):
+		triggerError(w, http.StatusNotFound, err)
+		return
+	case errors.As(err, &quotaErr):
+		if !quotaErr.RetryAt.IsZero() {
+			w.Header().Set("Retry-After", fmt.Sprint(int(time.Until(quotaErr.RetryAt).Seconds())+1))
+		}
+		triggerError(w, http.StatusTooManyRequests, err)
+		return
+	case chapter == nil && err != nil:
+		triggerError(w, http.StatusInternalServerError, err)
+		return
+	case err != nil:
+		// Written, but something after it failed; the chapter is still
+		// there to fetch
+		logger.Warn("triggered chapter", "path", chapter.Path, "err", err)
+	}
+
+	rec := chapter.record()
+	resp := TriggerResponse{URL: triggerBaseURL(settings, r) + "/chapters/" + ChapterID(rec), Chapter: chapter}
+	if records, err := LoadChapters(townRoot); err == nil {
+		for _, c := range records {
+			if c.Path == chapter.Path {
+				resp.Review = c.Review
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", resp.URL)
+	w.WriteHeader(http.StatusCreated)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(resp)
+}
+
+// triggerBaseURL returns the endpoint's URL as a caller reaches it.
+func triggerBaseURL(settings *config.NarratorTrigger, r *http.Request) string {
+	if settings.URL != "" {
+		return strings.TrimSuffix(settings.URL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// serveChapter serves a chapter from the chapter index. A chapter awaiting
+// review, or rejected, isn't served.
+func serveChapter(townRoot string, w http.ResponseWriter, r *http.Request) {
+	records, err := LoadChapters(townRoot)
+	if err != nil {
+		triggerError(w, http.StatusInternalServerError, err)
+		return
+	}
+	id := r.PathValue("id")
+	for _, rec := range records {
+		if ChapterID(rec) != id {
+			continue
+		}
+		if !rec.Published() {
+			triggerError(w, http.StatusForbidden, fmt.Errorf("chapter %s is %s", id, rec.Review))
+			return
+		}
+		http.ServeFile(w, r, rec.Path)
+		return
+	}
+	triggerError(w, http.StatusNotFound, fmt.Errorf("no chapter %q", id))
+}
+
+// triggerError replies with err as JSON.
+func triggerError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,143 @@
+package narrator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestTriggerHandler(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	settings := config.NewTownSettings()
+	settings.Narrator = &config.NarratorConfig{Trigger: &config.NarratorTrigger{Secret: "s3cret"}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeEvents(t, townRoot, time.Now().Add(-2*time.Hour),
+		`"type":"merge_failed","actor":"beads/refinery","payload":{"rig":"beads"}}`)
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	var prompt string
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompt = argv[len(argv)-1]
+		return []byte("The release went out.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	srv := httptest.NewServer(TriggerHandler(townRoot))
+	defer srv.Close()
+	post := func(body, signature string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/narrate", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signature != "" {
+			req.Header.Set(events.HeaderSignature, "sha256="+signature)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	body := `{"rig":"gastown","hours":1}`
+	if resp := post(body, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unsigned POST status = %d, want 401", resp.StatusCode)
+	}
+	if resp := post(body, events.Sign("wrong", []byte(body))); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("badly signed POST status = %d, want 401", resp.StatusCode)
+	}
+	if resp := post(`{"hours":-1}`, events.Sign("s3cret", []byte(`{"hours":-1}`))); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("negative hours status = %d, want 400", resp.StatusCode)
+	}
+	escape := `{"rig":"../../.."}`
+	if resp := post(escape, events.Sign("s3cret", []byte(escape))); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("rig leading out of the town status = %d, want 400", resp.StatusCode)
+	}
+	if resp := post(`{"rig":"nope"}`, events.Sign("s3cret", []byte(`{"rig":"nope"}`))); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("no events status = %d, want 404", resp.StatusCode)
+	}
+
+	resp := post(body, events.Sign("s3cret", []byte(body)))
+	if resp.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(resp.Body)
+		t.Fatalf("POST status = %d: %s", resp.StatusCode, data)
+	}
+	var got TriggerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, "gastown/refinery") || strings.Contains(prompt, "beads/refinery") {
+		t.Errorf("prompt narrates the wrong events:\n%s", prompt)
+	}
+	if want := srv.URL + "/chapters/" + ChapterID(got.Chapter.record()); got.URL != want || resp.Header.Get("Location") != want {
+		t.Errorf("URL = %q, Location = %q; want %q", got.URL, resp.Header.Get("Location"), want)
+	}
+
+	chapter, err := http.Get(got.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = chapter.Body.Close() }()
+	data, _ := io.ReadAll(chapter.Body)
+	if chapter.StatusCode != http.StatusOK || !strings.Contains(string(data), "The release went out.") {
+		t.Errorf("GET %s = %d %q", got.URL, chapter.StatusCode, data)
+	}
+	if missing, err := http.Get(srv.URL + "/chapters/chapter-nope"); err != nil || missing.StatusCode != http.StatusNotFound {
+		t.Errorf("GET of an unknown chapter = %v, %v; want 404", missing, err)
+	} else {
+		_ = missing.Body.Close()
+	}
+}
+
+func TestTriggerHandler_Review(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	if err := Set(townRoot, "review", "on"); err != nil {
+		t.Fatal(err)
+	}
+	origNotify := notifyReview
+	notifyReview = func(string, string, ChapterRecord) error { return nil }
+	t.Cleanup(func() { notifyReview = origNotify })
+	writeEvents(t, townRoot, time.Now(),
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"rig":"gastown"}}`)
+	orig := runAgent
+	runAgent = func(context.Context, string, []string) ([]byte, error) { return []byte("Held back.\n"), nil }
+	t.Cleanup(func() { runAgent = orig })
+
+	srv := httptest.NewServer(TriggerHandler(townRoot))
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/narrate", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var got TriggerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusCreated || got.Review != ReviewPending {
+		t.Fatalf("POST = %d, review %q; want a chapter awaiting review", resp.StatusCode, got.Review)
+	}
+	chapter, err := http.Get(got.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = chapter.Body.Close()
+	if chapter.StatusCode != http.StatusForbidden {
+		t.Errorf("GET of a pending chapter = %d, want 403", chapter.StatusCode)
+	}
+}
@@ -0,0 +1,159 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// watchInterval is how often Watch reads the logs when no event broker is
+// running.
+const watchInterval = 200 * time.Millisecond
+
+// Watch delivers the town's events as they are written, as the narrator may
+// see them under the redaction policy, so callers needn't poll. With the
+// town's event broker running the events come from a subscription to it;
+// otherwise the logs are tailed (see events.Tailer). Either way logs that
+// are rotated, pruned, compacted or truncated are followed. The channel is
+// closed when ctx is done or the subscription ends.
+func Watch(ctx context.Context, townRoot string) (<-chan events.Record, error) {
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan events.Record)
+	sub, err := events.Subscribe(townRoot, events.SubscribeRequest{From: events.FromNow})
+	if err == nil {
+		go watchBroker(ctx, sub, policy, out)
+		return out, nil
+	}
+	if !errors.Is(err, events.ErrNoBroker) {
+		return nil, err
+	}
+	tailer, err := events.NewTailer(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	go watchTailer(ctx, townRoot, tailer, policy, out, nil)
+	return out, nil
+}
+
+// WatchFromCheckpoint is Watch for a consumer that must not miss events
+// across restarts. It always tails the logs, resuming from the checkpoint
+// in the narrator's state (see events.NewTailerAt), and saves the
+// checkpoint each time the events read are all delivered, so a restart
+// re-reads at most the batch in hand. Without a checkpoint it starts from
+// now.
+func WatchFromCheckpoint(ctx context.Context, townRoot string) (<-chan events.Record, error) {
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var tailer *events.Tailer
+	if state.Checkpoint != nil {
+		tailer, err = events.NewTailerAt(townRoot, state.Checkpoint)
+	} else {
+		tailer, err = events.NewTailer(townRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := saveCheckpoint(townRoot, tailer.Offsets()); err != nil {
+		_ = tailer.Close()
+		return nil, err
+	}
+	out := make(chan events.Record)
+	go watchTailer(ctx, townRoot, tailer, policy, out, func() {
+		if err := saveCheckpoint(townRoot, tailer.Offsets()); err != nil {
+			logger.Warn("saving watch checkpoint", "err", err)
+		}
+	})
+	return out, nil
+}
+
+// saveCheckpoint records how far the events logs have been delivered.
+func saveCheckpoint(townRoot string, offsets map[string]events.Offset) error {
+	s, err := LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	s.Checkpoint = offsets
+	if err := s.Save(townRoot); err != nil {
+		return fmt.Errorf("saving checkpoint: %w", err)
+	}
+	return nil
+}
+
+// watchBroker sends out the events of a broker subscription.
+func watchBroker(ctx context.Context, sub *events.Subscription, policy *events.Policy, out chan<- events.Record) {
+	defer close(out)
+	stop := context.AfterFunc(ctx, func() { _ = sub.Close() })
+	defer stop()
+	defer func() { _ = sub.Close() }()
+	for {
+		r, err := sub.Next()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("event subscription ended", "err", err)
+			}
+			return
+		}
+		if !send(ctx, policy, r, out) {
+			return
+		}
+	}
+}
+
+// watchTailer sends out the events appended to the logs, reading them
+// every watchInterval, and calls delivered (if set) once everything read
+// has been sent.
+func watchTailer(ctx context.Context, townRoot string, tailer *events.Tailer, policy *events.Policy, out chan<- events.Record, delivered func()) {
+	defer close(out)
+	defer func() { _ = tailer.Close() }()
+	dec := events.NewDecoder(townRoot)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lines := tailer.Poll()
+			for _, line := range lines {
+				event, err := dec.Parse([]byte(line))
+				if err != nil {
+					continue
+				}
+				if !send(ctx, policy, events.Record{Event: event}, out) {
+					return
+				}
+			}
+			if len(lines) > 0 && delivered != nil {
+				delivered()
+			}
+		}
+	}
+}
+
+// send delivers r as the narrator may see it, if it may see it at all.
+// Returns false once ctx is done.
+func send(ctx context.Context, policy *events.Policy, r events.Record, out chan<- events.Record) bool {
+	e, visible := policy.Expose(r.Event, events.AudienceNarrator)
+	if !visible {
+		return true
+	}
+	r.Event = e
+	select {
+	case out <- r:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
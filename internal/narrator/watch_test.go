@@ -0,0 +1,113 @@
+package narrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestWatch_Tails(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Events = &config.EventsConfig{Redact: []config.EventRedactRule{{Keys: []string{"token"}, Audiences: []string{events.AudienceNarrator}}}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	writeEvents(t, townRoot, time.Now(), `"type":"boot","actor":"mayor"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched, err := Watch(ctx, townRoot)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	if err := events.Append(townRoot, events.Event{Timestamp: time.Now().UTC().Format(time.RFC3339), Source: "gt",
+		Type: events.TypeSling, Actor: "mayor", Payload: map[string]interface{}{"bead": "gt-1", "target": "gastown/Toast", "token": "secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r := <-watched:
+		if r.Type != events.TypeSling {
+			t.Errorf("watched %s, want only the sling written after Watch", r.Type)
+		}
+		if _, ok := r.Payload["token"]; ok {
+			t.Errorf("payload = %v, want the token redacted for the narrator", r.Payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event delivered")
+	}
+
+	cancel()
+	select {
+	case _, open := <-watched:
+		if open {
+			t.Error("channel delivered after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestWatchFromCheckpoint(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEvents(t, townRoot, time.Now(), `"type":"boot","actor":"mayor"}`)
+	appendEvent := func(bead string) {
+		t.Helper()
+		if err := events.Append(townRoot, events.Event{Timestamp: time.Now().UTC().Format(time.RFC3339), Source: "gt",
+			Type: events.TypeSling, Actor: "mayor", Payload: events.SlingPayload(bead, "gastown/Toast")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	watchOne := func(want string) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		watched, err := WatchFromCheckpoint(ctx, townRoot)
+		if err != nil {
+			t.Fatalf("WatchFromCheckpoint: %v", err)
+		}
+		appendEvent(want)
+		select {
+		case r := <-watched:
+			if r.Payload["bead"] != want {
+				t.Errorf("watched %v, want %s", r.Payload["bead"], want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event delivered")
+		}
+		// Let the delivered batch be checkpointed before stopping
+		time.Sleep(2 * watchInterval)
+		cancel()
+		for range watched {
+		}
+	}
+
+	watchOne("gt-1")
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Checkpoint[""].Lines; got != 2 {
+		t.Errorf("checkpoint = %d lines, want 2", got)
+	}
+
+	// Written while nobody watched: delivered on restart, before the new one
+	appendEvent("gt-2")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched, err := WatchFromCheckpoint(ctx, townRoot)
+	if err != nil {
+		t.Fatalf("WatchFromCheckpoint: %v", err)
+	}
+	select {
+	case r := <-watched:
+		if r.Payload["bead"] != "gt-2" {
+			t.Errorf("first event after restart = %v, want gt-2", r.Payload["bead"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("missed event not delivered after restart")
+	}
+}
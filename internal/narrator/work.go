@@ -0,0 +1,282 @@
+package narrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// maxMailBody caps the text of each mail message in a work prompt.
+const maxMailBody = 600
+
+// WorkOptions selects how a work item's story is told.
+type WorkOptions struct {
+	Style  string // "" uses DefaultStyle
+	Agent  string // overrides role_agents["narrator"] and the default agent
+	Output string // story path; "" writes to narrator/work-<bead>-<time>, with the format's extension
+}
+
+// WorkStory describes the written story of one work item.
+type WorkStory struct {
+	Bead   string      `json:"bead"`
+	Path   string      `json:"path"`
+	Style  string      `json:"style"`
+	Agent  string      `json:"agent"`
+	Since  time.Time   `json:"since"` // the work item's first event
+	Until  time.Time   `json:"until"` // its last event or mail
+	Events int         `json:"events"`
+	Mail   int         `json:"mail"`
+	Merges int         `json:"merges"` // merge attempts, successful or not
+	Words  int         `json:"words"`
+	Issues []LintIssue `json:"issues,omitempty"` // terminology issues found in the story
+}
+
+// WorkPrompt is a work item's story request ready to hand to an agent.
+type WorkPrompt struct {
+	Prompt
+	Bead   string
+	Mail   int
+	Merges int
+	Since  time.Time
+	Until  time.Time
+}
+
+// workMail returns the mail about a work item: the threads of the messages
+// that name it in the mailboxes of those involved, oldest first. A variable
+// so tests can stand in for the mail store.
+var workMail = func(townRoot, bead string, addresses []string) ([]*mail.Message, error) {
+	seen := make(map[string]bool)
+	var found []*mail.Message
+	for _, address := range addresses {
+		mb := mail.NewMailboxFromAddress(address, townRoot)
+		hits, err := mb.Search(mail.SearchOptions{Query: bead})
+		if err != nil {
+			return found, fmt.Errorf("searching %s's mail: %w", address, err)
+		}
+		for _, msg := range hits {
+			thread := []*mail.Message{msg}
+			if msg.ThreadID != "" {
+				if t, err := mb.ListByThread(msg.ThreadID); err == nil && len(t) > 0 {
+					thread = t
+				}
+			}
+			for _, m := range thread {
+				if !seen[m.ID] {
+					seen[m.ID] = true
+					found = append(found, m)
+				}
+			}
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Timestamp.Before(found[j].Timestamp) })
+	return found, nil
+}
+
+// BuildWorkPrompt collects a work item's events (its chain, as
+// events.ChainOf links them, and the mail events that name it), the mail
+// threads about it and its merges, and renders the prompt for its story.
+// It returns ErrNoEvents if the town's logs have nothing about the bead.
+func BuildWorkPrompt(townRoot, bead string, opts WorkOptions) (*WorkPrompt, error) {
+	st, err := LoadStyle(townRoot, opts.Style)
+	if err != nil {
+		return nil, err
+	}
+	records, err := events.ReadAll(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	policy, err := events.LoadPolicy(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	p := &WorkPrompt{Prompt: Prompt{Style: st.Name, style: st}, Bead: bead}
+	addresses := make(map[string]bool)
+	for i, work := range events.Works(records) {
+		r := records[i]
+		if work != bead && !(r.Type == events.TypeMail && mentions(r.Payload, bead)) {
+			continue
+		}
+		e, visible := policy.Expose(r.Event, events.AudienceNarrator)
+		if !visible {
+			continue
+		}
+		p.selected = append(p.selected, e)
+		addresses[e.Actor] = true
+		if to, ok := e.Payload["to"].(string); ok && to != "" {
+			addresses[to] = true
+		}
+		switch e.Type {
+		case events.TypeMergeStarted, events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
+			p.Merges++
+		}
+	}
+	if len(p.selected) == 0 {
+		return nil, fmt.Errorf("%w about %s", ErrNoEvents, bead)
+	}
+	p.Events = len(p.selected)
+	p.Since, _ = time.Parse(time.RFC3339, p.selected[0].Timestamp)
+	p.Until, _ = time.Parse(time.RFC3339, p.selected[len(p.selected)-1].Timestamp)
+
+	list := make([]string, 0, len(addresses))
+	for a := range addresses {
+		list = append(list, a)
+	}
+	sort.Strings(list)
+	// Mail is part of the story, not all of it: tell what the logs show
+	// if the mail store can't be read
+	messages, err := workMail(townRoot, bead, list)
+	if err != nil {
+		logger.Warn("reading work item's mail", "bead", bead, "err", err)
+	}
+	p.Mail = len(messages)
+	if len(messages) > 0 && messages[len(messages)-1].Timestamp.After(p.Until) {
+		p.Until = messages[len(messages)-1].Timestamp
+	}
+
+	personas, err := LoadPersonas(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	glossary, err := LoadGlossary(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	p.personas = personas
+	tone := LoadTone(townRoot)
+	guide, err := st.Guide(StyleData{Rig: events.EventRig(p.selected[0]), Since: p.Since, Until: p.Until, Events: p.Events,
+		Tone: tone.Name, Intensity: tone.Intensity})
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("You are the narrator of a Gas Town: a town of AI agents working on code. ")
+	fmt.Fprintf(&b, "Tell the story of one piece of work, %s, from the events and mail below, in %s, following the style guide. ", bead, st.describeFormat())
+	b.WriteString("Follow it from start to finish: who took it on, what went wrong and how it was put right, and how it merged, or why it didn't. ")
+	b.WriteString("Make it self-contained, for a reader who knows nothing else of the town, such as a pull request description or a retrospective. ")
+	b.WriteString("Reply with the story only.\n\n")
+	b.WriteString("## Style guide\n\n")
+	b.WriteString(guide)
+	b.WriteString("\n\n")
+
+	writeTone(&b, tone)
+	writeCast(&b, castOf(personas, p.selected))
+	writeGlossary(&b, glossary)
+
+	b.WriteString("## Events\n\n")
+	for _, e := range p.selected {
+		fmt.Fprintf(&b, "- %s %s %s", e.Timestamp, e.Type, e.Actor)
+		if payload := formatPayload(e.Payload); payload != "" {
+			b.WriteString(" " + payload)
+		}
+		b.WriteString("\n")
+	}
+	if len(messages) > 0 {
+		b.WriteString("\n## Mail\n\n")
+		for _, m := range messages {
+			fmt.Fprintf(&b, "### %s, from %s to %s: %s\n\n", m.Timestamp.UTC().Format(time.RFC3339), m.From, m.To, m.Subject)
+			body := strings.TrimSpace(m.Body)
+			if len(body) > maxMailBody {
+				body = body[:maxMailBody] + "…"
+			}
+			if body != "" {
+				b.WriteString(body + "\n\n")
+			}
+		}
+	}
+	p.Text = b.String()
+	return p, nil
+}
+
+// mentions reports whether a mail event's subject names bead.
+func mentions(payload map[string]interface{}, bead string) bool {
+	subject, _ := payload["subject"].(string)
+	for _, w := range strings.FieldsFunc(subject, func(r rune) bool { return r == ' ' || r == ':' || r == ',' || r == '(' || r == ')' }) {
+		if w == bead {
+			return true
+		}
+	}
+	return false
+}
+
+// SummarizeWork tells the story of one work item (see BuildWorkPrompt) and
+// writes it, rendered to narrator.output_format. The story stands alone: it
+// isn't a chapter of the town's history, so the story state, chapter index
+// and site are left as they are. The agent run is added to the usage
+// ledger; with narrator.quota set, a story over quota is not written (a
+// *QuotaError).
+func SummarizeWork(ctx context.Context, townRoot, bead string, opts WorkOptions) (*WorkStory, error) {
+	lock, err := lockGeneration(ctx, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	prompt, err := BuildWorkPrompt(townRoot, bead, opts)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := LoadUsage(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if reason, retry := checkQuota(quotaSettings(townRoot), usage, estimateTokens(prompt.Text), time.Now()); reason != "" {
+		return nil, &QuotaError{Reason: reason, RetryAt: retry}
+	}
+	r, err := narrate(ctx, townRoot, opts.Agent, &prompt.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	rendered, ext, err := renderChapter(townRoot, r.text, prompt.style.Format, "The story of "+bead, now)
+	if err != nil {
+		return nil, err
+	}
+	path := opts.Output
+	if path == "" {
+		path = filepath.Join(Dir(townRoot), "work-"+fileSafe(bead)+"-"+now.Format("20060102-150405")+ext)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating story dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(rendered+"\n"), 0644); err != nil { //nolint:gosec // G306: stories are non-sensitive
+		return nil, fmt.Errorf("writing story: %w", err)
+	}
+	usage.add(UsageEntry{At: now, Agent: r.agent, Tokens: estimateTokens(prompt.Text) + estimateTokens(r.text)})
+	if err := usage.Save(townRoot); err != nil {
+		return nil, fmt.Errorf("story written to %s, but saving usage ledger: %w", path, err)
+	}
+	story := &WorkStory{
+		Bead:   bead,
+		Path:   path,
+		Style:  prompt.Style,
+		Agent:  r.agent,
+		Since:  prompt.Since,
+		Until:  prompt.Until,
+		Events: prompt.Events,
+		Mail:   prompt.Mail,
+		Merges: prompt.Merges,
+		Words:  len(strings.Fields(r.text)),
+		Issues: r.issues,
+	}
+	logger.Info("work story written", "bead", bead, "path", path, "agent", story.Agent, "events", story.Events, "mail", story.Mail)
+	return story, nil
+}
+
+// fileSafe replaces the characters of s that don't belong in a file name.
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,74 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestSummarizeWork(t *testing.T) {
+	townRoot := t.TempDir()
+	writeStyle(t, townRoot, DefaultStyle, "Plain and factual.")
+	writeEvents(t, townRoot, time.Now().Add(-time.Hour),
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-abc","target":"gastown/Toast"}}`,
+		`"type":"done","actor":"gastown/Toast","payload":{"bead":"gt-abc","branch":"polecat/Toast-gt-abc"}}`,
+		`"type":"merge_failed","actor":"gastown/refinery","payload":{"branch":"polecat/Toast-gt-abc","reason":"tests failed"}}`,
+		`"type":"mail","actor":"gastown/refinery","payload":{"to":"gastown/witness","subject":"MERGE_FAILED gt-abc"}}`,
+		`"type":"merged","actor":"gastown/refinery","payload":{"branch":"polecat/Toast-gt-abc"}}`,
+		`"type":"sling","actor":"mayor","payload":{"bead":"gt-xyz","target":"gastown/Nux"}}`)
+
+	var asked []string
+	origMail := workMail
+	workMail = func(_, bead string, addresses []string) ([]*mail.Message, error) {
+		asked = addresses
+		return []*mail.Message{{ID: "m1", From: "gastown/witness", To: "gastown/Toast", Subject: "Fix " + bead,
+			Body: "The tests fail on main; rebase and retry.", Timestamp: time.Now()}}, nil
+	}
+	t.Cleanup(func() { workMail = origMail })
+	var prompt string
+	orig := runAgent
+	runAgent = func(_ context.Context, _ string, argv []string) ([]byte, error) {
+		prompt = argv[len(argv)-1]
+		return []byte("# Toast and gt-abc\n\nToast took the bead, failed once and merged.\n"), nil
+	}
+	t.Cleanup(func() { runAgent = orig })
+
+	story, err := SummarizeWork(context.Background(), townRoot, "gt-abc", WorkOptions{Agent: "claude"})
+	if err != nil {
+		t.Fatalf("SummarizeWork: %v", err)
+	}
+	if story.Events != 5 || story.Merges != 2 || story.Mail != 1 {
+		t.Errorf("story = %+v, want 5 events, 2 merges and 1 mail", story)
+	}
+	for _, want := range []string{"one piece of work, gt-abc", "merge_failed gastown/refinery", "MERGE_FAILED gt-abc", "## Mail", "rebase and retry"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt lacks %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "gt-xyz") {
+		t.Errorf("prompt has another work item's events:\n%s", prompt)
+	}
+	if strings.Join(asked, " ") != "gastown/Toast gastown/refinery gastown/witness mayor" {
+		t.Errorf("mail searched in %v, want the mailboxes of those involved", asked)
+	}
+	if !strings.HasPrefix(filepath.Base(story.Path), "work-gt-abc-") || !strings.Contains(readFile(t, story.Path), "failed once and merged") {
+		t.Errorf("story not written to narrator/work-gt-abc-*: %s", story.Path)
+	}
+	// A story stands alone: the town's history is untouched
+	if _, err := os.Stat(StoryPath(townRoot)); !os.IsNotExist(err) {
+		t.Error("story state written for a work item's story")
+	}
+	if records, _ := LoadChapters(townRoot); len(records) != 0 {
+		t.Errorf("chapter index = %+v, want none", records)
+	}
+
+	if _, err := SummarizeWork(context.Background(), townRoot, "gt-nope", WorkOptions{Agent: "claude"}); !errors.Is(err, ErrNoEvents) {
+		t.Errorf("SummarizeWork of an unknown bead error = %v, want ErrNoEvents", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Package notify delivers native desktop notifications to the human
+// operator, so escalations are noticed without keeping a terminal visible.
+//
+// Backends are the platform's own tools: terminal-notifier or osascript on
+// macOS, notify-send on Linux.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnsupported indicates no desktop notification backend is available.
+var ErrUnsupported = errors.New("desktop notifications not supported on this system")
+
+// Notification is a single desktop notification.
+type Notification struct {
+	Title string
+	Body  string
+	// Urgent asks the backend to keep the notification visible, where the
+	// backend supports it.
+	Urgent bool
+}
+
+// Notifier delivers notifications.
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Desktop sends notifications through the platform's notification tool.
+type Desktop struct {
+	// Command forces a backend ("terminal-notifier", "osascript" or
+	// "notify-send"). Empty picks the first available for the platform.
+	Command string
+
+	goos     string
+	lookPath func(string) (string, error)
+	run      func(name string, args ...string) error
+}
+
+// NewDesktop creates a desktop notifier for the current platform.
+func NewDesktop(command string) *Desktop {
+	return &Desktop{
+		Command:  command,
+		goos:     runtime.GOOS,
+		lookPath: exec.LookPath,
+		run: func(name string, args ...string) error {
+			return exec.Command(name, args...).Run() //nolint:gosec // G204: name is a fixed notification tool
+		},
+	}
+}
+
+// Notify shows the notification.
+func (d *Desktop) Notify(n Notification) error {
+	name, args, err := d.command(n)
+	if err != nil {
+		return err
+	}
+	if err := d.run(name, args...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// command returns the backend invocation for a notification.
+func (d *Desktop) command(n Notification) (string, []string, error) {
+	candidates := d.backends()
+	if d.Command != "" {
+		candidates = []string{d.Command}
+	}
+	for _, name := range candidates {
+		if _, err := d.lookPath(name); err != nil {
+			continue
+		}
+		switch name {
+		case "terminal-notifier":
+			args := []string{"-title", n.Title, "-message", n.Body, "-group", "gastown"}
+			if n.Urgent {
+				args = append(args, "-sound", "default")
+			}
+			return name, args, nil
+		case "osascript":
+			script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Body), appleScriptString(n.Title))
+			return name, []string{"-e", script}, nil
+		case "notify-send":
+			urgency := "normal"
+			if n.Urgent {
+				urgency = "critical"
+			}
+			return name, []string{"--app-name=Gas Town", "--urgency=" + urgency, n.Title, n.Body}, nil
+		default:
+			return "", nil, fmt.Errorf("unknown notification command %q", name)
+		}
+	}
+	return "", nil, ErrUnsupported
+}
+
+// backends lists the notification tools to try on this platform, in order.
+func (d *Desktop) backends() []string {
+	switch d.goos {
+	case "darwin":
+		return []string{"terminal-notifier", "osascript"}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"notify-send"}
+	default:
+		return nil
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func fakeDesktop(goos string, available ...string) *Desktop {
+	d := NewDesktop("")
+	d.goos = goos
+	d.lookPath = func(name string) (string, error) {
+		for _, a := range available {
+			if a == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+	return d
+}
+
+func TestDesktopCommand(t *testing.T) {
+	n := Notification{Title: "Escalation", Body: `Build "main" failing`, Urgent: true}
+
+	tests := []struct {
+		name     string
+		desktop  *Desktop
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "linux notify-send",
+			desktop:  fakeDesktop("linux", "notify-send"),
+			wantName: "notify-send",
+			wantArgs: []string{"--app-name=Gas Town", "--urgency=critical", "Escalation", `Build "main" failing`},
+		},
+		{
+			name:     "macOS prefers terminal-notifier",
+			desktop:  fakeDesktop("darwin", "terminal-notifier", "osascript"),
+			wantName: "terminal-notifier",
+			wantArgs: []string{"-title", "Escalation", "-message", `Build "main" failing`, "-group", "gastown", "-sound", "default"},
+		},
+		{
+			name:     "macOS falls back to osascript",
+			desktop:  fakeDesktop("darwin", "osascript"),
+			wantName: "osascript",
+			wantArgs: []string{"-e", `display notification "Build \"main\" failing" with title "Escalation"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, err := tt.desktop.command(n)
+			if err != nil {
+				t.Fatalf("command: %v", err)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("command = %s %q, want %s %q", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestDesktopNotify_Unsupported(t *testing.T) {
+	for _, d := range []*Desktop{fakeDesktop("linux"), fakeDesktop("windows", "notify-send")} {
+		if err := d.Notify(Notification{Title: "x"}); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Notify on %s = %v, want ErrUnsupported", d.goos, err)
+		}
+	}
+}
+
+func TestDesktopNotify_Runs(t *testing.T) {
+	d := fakeDesktop("linux", "notify-send")
+	var ran string
+	d.run = func(name string, args ...string) error {
+		ran = name
+		return nil
+	}
+	if err := d.Notify(Notification{Title: "x", Body: "y"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if ran != "notify-send" {
+		t.Errorf("ran %q, want notify-send", ran)
+	}
+}
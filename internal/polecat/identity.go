@@ -0,0 +1,164 @@
+package polecat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// MaxIdentityHistory caps the completed-work history kept per identity.
+const MaxIdentityHistory = 100
+
+// Identity is the persistent record behind a polecat name. Polecats are
+// spawned fresh and nuked when done, but a name drawn from the pool again
+// inherits its identity, so the cast stays stable across spawns and the
+// audit trail can follow one polecat's history.
+type Identity struct {
+	Name        string            `json:"name"`
+	Theme       string            `json:"theme,omitempty"` // name pool theme the name came from
+	FirstSpawn  time.Time         `json:"first_spawn"`
+	LastSpawn   time.Time         `json:"last_spawn"`
+	Spawns      int               `json:"spawns"`
+	Completed   []CompletedWork   `json:"completed,omitempty"`   // oldest first
+	Bible       string            `json:"bible,omitempty"`       // character-bible entry
+	Preferences map[string]string `json:"preferences,omitempty"` // e.g. "editor": "vim"
+}
+
+// CompletedWork is a bead a polecat finished with gt done.
+type CompletedWork struct {
+	Issue       string    `json:"issue"`
+	Exit        string    `json:"exit,omitempty"` // COMPLETED, ESCALATED, DEFERRED
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// IdentitiesPath returns the file holding a rig's polecat identities. It
+// lives beside the polecat directories but outlives them.
+func IdentitiesPath(rigPath string) string {
+	return filepath.Join(rigPath, "polecats", ".identities.json")
+}
+
+// LoadIdentities returns a rig's polecat identities keyed by name.
+func LoadIdentities(rigPath string) (map[string]*Identity, error) {
+	data, err := os.ReadFile(IdentitiesPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]*Identity), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	identities := make(map[string]*Identity)
+	if err := json.Unmarshal(data, &identities); err != nil {
+		return nil, fmt.Errorf("parsing polecat identities: %w", err)
+	}
+	return identities, nil
+}
+
+// ListIdentities returns a rig's polecat identities sorted by name.
+func ListIdentities(rigPath string) ([]*Identity, error) {
+	identities, err := LoadIdentities(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Identity, 0, len(identities))
+	for _, id := range identities {
+		list = append(list, id)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// GetIdentity returns the identity for name, or nil if it has never spawned.
+func GetIdentity(rigPath, name string) (*Identity, error) {
+	identities, err := LoadIdentities(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	return identities[name], nil
+}
+
+// UpdateIdentity applies fn to the identity for name, creating it if needed,
+// and saves the result.
+func UpdateIdentity(rigPath, name string, fn func(*Identity)) (*Identity, error) {
+	identities, err := LoadIdentities(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	id := identities[name]
+	if id == nil {
+		id = &Identity{Name: name}
+		identities[name] = id
+	}
+	fn(id)
+
+	if err := os.MkdirAll(filepath.Dir(IdentitiesPath(rigPath)), 0755); err != nil {
+		return nil, fmt.Errorf("creating polecats dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(IdentitiesPath(rigPath), identities); err != nil {
+		return nil, fmt.Errorf("writing polecat identities: %w", err)
+	}
+	return id, nil
+}
+
+// RecordSpawn notes that name was spawned, returning its identity with any
+// history inherited from earlier spawns.
+func RecordSpawn(rigPath, name, theme string, now time.Time) (*Identity, error) {
+	return UpdateIdentity(rigPath, name, func(id *Identity) {
+		now = now.UTC()
+		if id.FirstSpawn.IsZero() {
+			id.FirstSpawn = now
+		}
+		id.LastSpawn = now
+		id.Spawns++
+		if theme != "" {
+			id.Theme = theme
+		}
+	})
+}
+
+// RecordCompletion adds a finished bead to name's history.
+func RecordCompletion(rigPath, name, issue, exit string, now time.Time) (*Identity, error) {
+	return UpdateIdentity(rigPath, name, func(id *Identity) {
+		id.Completed = append(id.Completed, CompletedWork{Issue: issue, Exit: exit, CompletedAt: now.UTC()})
+		if len(id.Completed) > MaxIdentityHistory {
+			id.Completed = id.Completed[len(id.Completed)-MaxIdentityHistory:]
+		}
+	})
+}
+
+// CompletedCount returns how many beads the identity finished with exit
+// COMPLETED (or no recorded exit type).
+func (id *Identity) CompletedCount() int {
+	n := 0
+	for _, w := range id.Completed {
+		if w.Exit == "" || w.Exit == "COMPLETED" {
+			n++
+		}
+	}
+	return n
+}
+
+// RenameIdentity moves oldName's identity to newName. A missing identity is
+// not an error; an existing identity under newName is.
+func RenameIdentity(rigPath, oldName, newName string) error {
+	identities, err := LoadIdentities(rigPath)
+	if err != nil {
+		return err
+	}
+	id := identities[oldName]
+	if id == nil {
+		return nil
+	}
+	if identities[newName] != nil {
+		return fmt.Errorf("identity %s already exists", newName)
+	}
+	delete(identities, oldName)
+	id.Name = newName
+	identities[newName] = id
+	return util.AtomicWriteJSON(IdentitiesPath(rigPath), identities)
+}
@@ -0,0 +1,117 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestIdentity_InheritedAcrossSpawns(t *testing.T) {
+	rigPath := t.TempDir()
+	first := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	id, err := RecordSpawn(rigPath, "furiosa", "mad-max", first)
+	if err != nil {
+		t.Fatalf("RecordSpawn: %v", err)
+	}
+	if id.Spawns != 1 || !id.FirstSpawn.Equal(first) {
+		t.Fatalf("first spawn = %+v", id)
+	}
+
+	if _, err := RecordCompletion(rigPath, "furiosa", "gt-abc", "COMPLETED", first.Add(time.Hour)); err != nil {
+		t.Fatalf("RecordCompletion: %v", err)
+	}
+	if _, err := RecordCompletion(rigPath, "furiosa", "gt-def", "ESCALATED", first.Add(2*time.Hour)); err != nil {
+		t.Fatalf("RecordCompletion: %v", err)
+	}
+	if _, err := UpdateIdentity(rigPath, "furiosa", func(id *Identity) {
+		id.Bible = "Drives the war rig"
+	}); err != nil {
+		t.Fatalf("UpdateIdentity: %v", err)
+	}
+
+	// Nuking the polecat's directory must not lose its identity
+	if err := os.RemoveAll(filepath.Join(rigPath, "polecats", "furiosa")); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err = RecordSpawn(rigPath, "furiosa", "mad-max", first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("RecordSpawn: %v", err)
+	}
+	if id.Spawns != 2 {
+		t.Errorf("Spawns = %d, want 2", id.Spawns)
+	}
+	if !id.FirstSpawn.Equal(first) {
+		t.Errorf("FirstSpawn = %v, want %v", id.FirstSpawn, first)
+	}
+	if len(id.Completed) != 2 || id.CompletedCount() != 1 {
+		t.Errorf("Completed = %+v, CompletedCount = %d", id.Completed, id.CompletedCount())
+	}
+	if id.Bible != "Drives the war rig" {
+		t.Errorf("Bible = %q", id.Bible)
+	}
+}
+
+func TestRenameIdentity(t *testing.T) {
+	rigPath := t.TempDir()
+	if _, err := RecordSpawn(rigPath, "nux", "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecordSpawn(rigPath, "slit", "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RenameIdentity(rigPath, "nux", "slit"); err == nil {
+		t.Error("expected error renaming onto an existing identity")
+	}
+	if err := RenameIdentity(rigPath, "nux", "toast"); err != nil {
+		t.Fatalf("RenameIdentity: %v", err)
+	}
+
+	list, err := ListIdentities(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "slit" || list[1].Name != "toast" {
+		t.Errorf("identities after rename = %+v", list)
+	}
+	if id, _ := GetIdentity(rigPath, "nux"); id != nil {
+		t.Errorf("old identity still present: %+v", id)
+	}
+}
+
+func TestNamepoolConfigFor_TownFallback(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rigPath := filepath.Join(townRoot, "testrig")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	town := config.NewTownSettings()
+	town.Namepool = &config.NamepoolConfig{Style: "minerals"}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+	if np := NamepoolConfigFor(rigPath); np == nil || np.Style != "minerals" {
+		t.Errorf("town fallback = %+v, want minerals", np)
+	}
+
+	rigSettings := config.NewRigSettings()
+	rigSettings.Namepool = &config.NamepoolConfig{Style: "wasteland"}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatal(err)
+	}
+	if np := NamepoolConfigFor(rigPath); np == nil || np.Style != "wasteland" {
+		t.Errorf("rig override = %+v, want wasteland", np)
+	}
+}
@@ -59,19 +59,17 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 	resolvedBeads := beads.ResolveBeadsDir(r.Path)
 	beadsPath := filepath.Dir(resolvedBeads) // Get the directory containing .beads
 
-	// Try to load rig settings for namepool config
-	settingsPath := filepath.Join(r.Path, "settings", "config.json")
+	// Load namepool config from rig settings, falling back to the town's
 	var pool *NamePool
 
-	settings, err := config.LoadRigSettings(settingsPath)
-	if err == nil && settings.Namepool != nil {
+	if np := NamepoolConfigFor(r.Path); np != nil {
 		// Use configured namepool settings
 		pool = NewNamePoolWithConfig(
 			r.Path,
 			r.Name,
-			settings.Namepool.Style,
-			settings.Namepool.Names,
-			settings.Namepool.MaxBeforeNumbering,
+			np.Style,
+			np.Names,
+			np.MaxBeforeNumbering,
 		)
 	} else {
 		// Use defaults
@@ -88,6 +86,25 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 	}
 }
 
+// NamepoolConfigFor returns the name pool configuration for a rig: the rig's
+// own settings if they configure one, otherwise the town's. Returns nil if
+// neither does.
+func NamepoolConfigFor(rigPath string) *config.NamepoolConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err == nil && settings.Namepool != nil {
+		return settings.Namepool
+	}
+	townRoot, err := workspace.Find(rigPath)
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	townSettings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return townSettings.Namepool
+}
+
 // assigneeID returns the beads assignee identifier for a polecat.
 // Format: "rig/polecatName" (e.g., "gastown/Toast")
 func (m *Manager) assigneeID(name string) string {
@@ -510,6 +527,16 @@ func (m *Manager) AllocateName() (string, error) {
 	return name, nil
 }
 
+// RecordSpawn records a spawn of name in the rig's identity registry and
+// returns the identity, carrying any history from earlier spawns.
+func (m *Manager) RecordSpawn(name string) (*Identity, error) {
+	theme := ""
+	if m.namePool.IsPoolName(name) && len(m.namePool.CustomNames) == 0 {
+		theme = m.namePool.GetTheme()
+	}
+	return RecordSpawn(m.rig.Path, name, theme, time.Now())
+}
+
 // ReleaseName releases a name back to the pool.
 // This is called when a polecat is removed.
 func (m *Manager) ReleaseName(name string) {
@@ -13,10 +13,12 @@ import (
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/lifecycle"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -190,15 +192,24 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
 
+	// Run pre-spawn lifecycle hooks; a failure aborts the start
+	townRoot := filepath.Dir(m.rig.Path)
+	hookAgent := m.lifecycleAgent(polecat, workDir)
+	if err := lifecycle.Run(lifecycle.PreSpawn, hookAgent); err != nil {
+		return fmt.Errorf("pre-spawn hook: %w", err)
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
+	// Record the session's output for the transcript archive (non-fatal)
+	debugSession("RecordTranscript", transcript.Record(m.tmux, townRoot, sessionID, m.rig.Name+"/"+polecat))
+
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
@@ -230,8 +241,8 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Wait for Claude to start (non-fatal)
 	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
-	// Accept bypass permissions warning dialog if it appears
-	debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+	// Accept runtime startup dialogs (e.g., bypass permissions warning) if they appear
+	debugSession("AcceptStartupPrompts", runtime.AcceptStartupPrompts(m.tmux, sessionID, runtimeConfig))
 
 	// Wait for runtime to be fully ready at the prompt (not just started)
 	runtime.SleepForReadyDelay(runtimeConfig)
@@ -260,9 +271,23 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("session %s died during startup (agent command may have failed)", sessionID)
 	}
 
+	lifecycle.Warn(lifecycle.PostStart, hookAgent)
 	return nil
 }
 
+// lifecycleAgent describes a polecat for lifecycle hooks.
+func (m *SessionManager) lifecycleAgent(polecat, workDir string) lifecycle.Agent {
+	return lifecycle.Agent{
+		TownRoot: filepath.Dir(m.rig.Path),
+		RigPath:  m.rig.Path,
+		Role:     "polecat",
+		Rig:      m.rig.Name,
+		Name:     polecat,
+		Session:  m.SessionName(polecat),
+		WorkDir:  workDir,
+	}
+}
+
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
@@ -275,6 +300,8 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 		return ErrSessionNotFound
 	}
 
+	lifecycle.Warn(lifecycle.PreKill, m.lifecycleAgent(polecat, m.clonePath(polecat)))
+
 	// Sync beads before shutdown (non-fatal)
 	if !force {
 		polecatDir := m.polecatDir(polecat)
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
@@ -249,6 +250,9 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
+	e.logMergeEvent(events.TypeMergeStarted, events.MergeInfo{
+		MR: mr.ID, Branch: mrFields.Branch, Target: mrFields.Target, SourceIssue: mrFields.SourceIssue, Worker: mrFields.Worker,
+	})
 	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
 }
 
@@ -478,6 +482,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 
 	// 5. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	e.logMergeEvent(events.TypeMerged, e.mergedPayload(mr.ID, mrFields.Branch, mrFields.Target, mrFields.SourceIssue, mrFields.Worker, result))
 }
 
 // handleFailure handles a failed merge request.
@@ -491,6 +496,11 @@ func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
 
 	// Log the failure
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
+	p := events.MergeInfo{MR: mr.ID, FailureType: failureType(result), Reason: result.Error}
+	if mrFields := beads.ParseMRFields(mr); mrFields != nil {
+		p.Branch, p.Target, p.SourceIssue, p.Worker = mrFields.Branch, mrFields.Target, mrFields.SourceIssue, mrFields.Worker
+	}
+	e.logMergeEvent(events.TypeMergeFailed, p)
 }
 
 // ProcessMRInfo processes a merge request from MRInfo.
@@ -502,6 +512,10 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	e.logMergeEvent(events.TypeMergeStarted, events.MergeInfo{
+		MR: mr.ID, Branch: mr.Branch, Target: mr.Target, SourceIssue: mr.SourceIssue, Worker: mr.Worker,
+	})
+
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
@@ -578,6 +592,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 
 	// 3. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	e.logMergeEvent(events.TypeMerged, e.mergedPayload(mr.ID, mr.Branch, mr.Target, mr.SourceIssue, mr.Worker, result))
 }
 
 // HandleMRInfoFailure handles a failed merge from MRInfo.
@@ -586,12 +601,11 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
-	failureType := "build"
-	if result.Conflict {
-		failureType = "conflict"
-	} else if result.TestsFailed {
-		failureType = "tests"
-	}
+	failureType := failureType(result)
+	e.logMergeEvent(events.TypeMergeFailed, events.MergeInfo{
+		MR: mr.ID, Branch: mr.Branch, Target: mr.Target, SourceIssue: mr.SourceIssue, Worker: mr.Worker,
+		FailureType: failureType, Reason: result.Error,
+	})
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
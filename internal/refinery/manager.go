@@ -15,11 +15,14 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/lifecycle"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -30,6 +33,9 @@ var (
 	ErrNoQueue        = errors.New("no items in queue")
 )
 
+// logger records session starts and stops.
+var logger = gtlog.For("refinery")
+
 // Manager handles refinery lifecycle and queue operations.
 type Manager struct {
 	rig     *rig.Rig
@@ -147,6 +153,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
 		_, _ = fmt.Fprintln(m.output, "⚠ Detected zombie session (tmux alive, agent dead). Recreating...")
+		logger.Warn("killing zombie session", "rig", m.rig.Name, "session", sessionID)
 		if err := t.KillSession(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
@@ -186,12 +193,21 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		command = config.BuildAgentStartupCommand("refinery", m.rig.Name, townRoot, m.rig.Path, "")
 	}
 
+	// Run pre-spawn lifecycle hooks; a failure aborts the start
+	hookAgent := m.lifecycleAgent(refineryRigDir)
+	if err := lifecycle.Run(lifecycle.PreSpawn, hookAgent); err != nil {
+		return fmt.Errorf("pre-spawn hook: %w", err)
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, refineryRigDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Record the session's output for the transcript archive (non-fatal)
+	_ = transcript.Record(t, townRoot, sessionID, m.rig.Name+"/refinery")
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
@@ -231,8 +247,8 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		return fmt.Errorf("waiting for refinery to start: %w", err)
 	}
 
-	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+	// Accept runtime startup dialogs (e.g., bypass permissions warning) if they appear.
+	_ = runtime.AcceptStartupPrompts(t, sessionID, runtimeConfig)
 
 	// Wait for runtime to be fully ready
 	runtime.SleepForReadyDelay(runtimeConfig)
@@ -252,9 +268,23 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	time.Sleep(2 * time.Second)
 	_ = t.NudgeSession(sessionID, session.PropulsionNudgeForRole("refinery", refineryRigDir)) // Non-fatal
 
+	lifecycle.Warn(lifecycle.PostStart, hookAgent)
+	logger.Info("session started", "rig", m.rig.Name, "session", sessionID, "agent", agentOverride)
 	return nil
 }
 
+// lifecycleAgent describes the refinery for lifecycle hooks.
+func (m *Manager) lifecycleAgent(workDir string) lifecycle.Agent {
+	return lifecycle.Agent{
+		TownRoot: filepath.Dir(m.rig.Path),
+		RigPath:  m.rig.Path,
+		Role:     "refinery",
+		Rig:      m.rig.Name,
+		Session:  m.SessionName(),
+		WorkDir:  workDir,
+	}
+}
+
 // Stop stops the refinery.
 func (m *Manager) Stop() error {
 	ref, err := m.loadState()
@@ -274,7 +304,9 @@ func (m *Manager) Stop() error {
 
 	// Kill tmux session if it exists (best-effort: may already be dead)
 	if sessionRunning {
+		lifecycle.Warn(lifecycle.PreKill, m.lifecycleAgent(m.rig.Path))
 		_ = t.KillSession(sessionID)
+		logger.Info("session stopped", "rig", m.rig.Name, "session", sessionID)
 	}
 
 	// Note: No PID-based stop per ZFC - tmux session kill is sufficient
@@ -0,0 +1,64 @@
+package refinery
+
+import (
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// DescribeMerge fills p's commit and diffstat fields from p.MergeCommit:
+// the diff against its first parent (the target before the merge) and the
+// branch commits it brought in. A fast-forward or squash commit with one
+// parent reports just itself. Best-effort: fields stay empty if git fails.
+func DescribeMerge(g *git.Git, p *events.MergeInfo) {
+	if p.MergeCommit == "" {
+		return
+	}
+	base := p.MergeCommit + "^1"
+
+	if stat, err := g.DiffStat(base, p.MergeCommit); err == nil {
+		p.FilesChanged = len(stat.Files)
+		p.Insertions = stat.Insertions
+		p.Deletions = stat.Deletions
+		p.Files = nil
+		for _, f := range stat.Files {
+			p.Files = append(p.Files, f.Path)
+		}
+	}
+
+	if commits, err := g.CommitsBetween(base, p.MergeCommit+"^2"); err == nil {
+		p.Commits = commits
+	} else {
+		p.Commits = []string{p.MergeCommit}
+	}
+}
+
+// logMergeEvent records a merge outcome in the town's event log.
+func (e *Engineer) logMergeEvent(eventType string, p events.MergeInfo) {
+	p.Rig = e.rig.Name
+	_ = events.LogFeed(eventType, e.rig.Name+"/refinery", p.Map())
+}
+
+// mergedPayload builds a merged event payload for a successful merge.
+func (e *Engineer) mergedPayload(mrID, branch, target, sourceIssue, worker string, result ProcessResult) events.MergeInfo {
+	p := events.MergeInfo{
+		MR:          mrID,
+		Branch:      branch,
+		Target:      target,
+		SourceIssue: sourceIssue,
+		Worker:      worker,
+		MergeCommit: result.MergeCommit,
+	}
+	DescribeMerge(e.git, &p)
+	return p
+}
+
+// failureType classifies a failed merge as conflict, tests or build.
+func failureType(result ProcessResult) string {
+	switch {
+	case result.Conflict:
+		return "conflict"
+	case result.TestsFailed:
+		return "tests"
+	}
+	return "build"
+}
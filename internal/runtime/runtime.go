@@ -84,6 +84,38 @@ func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.Runtime
 	return nil
 }
 
+// AcceptStartupPrompts dismisses the interactive dialogs a runtime shows at
+// startup according to its prompt policy. Detection patterns come from the
+// runtime config's prompt rules, defaulting to the agent preset's known dialogs
+// (e.g., Claude's bypass permissions warning). With auto_accept "never" the
+// dialogs are left for a human to answer.
+func AcceptStartupPrompts(t *tmux.Tmux, sessionID string, rc *config.RuntimeConfig) error {
+	rules := StartupPromptRules(rc)
+	if len(rules) == 0 {
+		return nil
+	}
+	_, err := t.AcceptStartupPrompts(sessionID, rules)
+	return err
+}
+
+// StartupPromptRules returns the startup dialogs that should be auto-accepted
+// for a runtime, or nil when the policy disables auto-accept.
+func StartupPromptRules(rc *config.RuntimeConfig) []config.StartupPromptRule {
+	if rc == nil {
+		rc = config.DefaultRuntimeConfig()
+	}
+	if rc.Prompts == nil {
+		return config.DefaultStartupPrompts(rc.Provider, rc.Command)
+	}
+	if rc.Prompts.AutoAccept == config.PromptPolicyNever {
+		return nil
+	}
+	if rc.Prompts.Rules == nil {
+		return config.DefaultStartupPrompts(rc.Provider, rc.Command)
+	}
+	return rc.Prompts.Rules
+}
+
 // isAutonomousRole returns true if the given role should automatically
 // inject mail check on startup. Autonomous roles (polecat, witness,
 // refinery, deacon) operate without human prompting and need mail injection
@@ -305,3 +305,59 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestStartupPromptRules_DefaultsToClaudeBypassWarning(t *testing.T) {
+	rules := StartupPromptRules(nil)
+	if len(rules) != 1 {
+		t.Fatalf("StartupPromptRules(nil) returned %d rules, want 1", len(rules))
+	}
+	if rules[0].Name != "bypass-permissions" {
+		t.Errorf("rule name = %q, want %q", rules[0].Name, "bypass-permissions")
+	}
+	if rules[0].Match != "Bypass Permissions mode" {
+		t.Errorf("rule match = %q, want %q", rules[0].Match, "Bypass Permissions mode")
+	}
+}
+
+func TestStartupPromptRules_PresetByCommand(t *testing.T) {
+	// Resolved agent configs carry only Command/Args; the preset is found by command.
+	rc := &config.RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}}
+	if rules := StartupPromptRules(rc); len(rules) != 1 {
+		t.Errorf("StartupPromptRules(claude command) returned %d rules, want 1", len(rules))
+	}
+
+	rc = &config.RuntimeConfig{Command: "codex"}
+	if rules := StartupPromptRules(rc); len(rules) != 0 {
+		t.Errorf("StartupPromptRules(codex) returned %v, want none", rules)
+	}
+}
+
+func TestStartupPromptRules_PolicyNever(t *testing.T) {
+	rc := &config.RuntimeConfig{
+		Provider: "claude",
+		Prompts:  &config.RuntimePromptsConfig{AutoAccept: config.PromptPolicyNever},
+	}
+	if rules := StartupPromptRules(rc); rules != nil {
+		t.Errorf("StartupPromptRules with policy never = %v, want nil", rules)
+	}
+}
+
+func TestStartupPromptRules_CustomRules(t *testing.T) {
+	custom := []config.StartupPromptRule{
+		{Name: "trust-folder", Match: "Do you trust the files in this folder?", Keys: []string{"Enter"}},
+	}
+	rc := &config.RuntimeConfig{
+		Command: "my-agent",
+		Prompts: &config.RuntimePromptsConfig{Rules: custom},
+	}
+	rules := StartupPromptRules(rc)
+	if len(rules) != 1 || rules[0].Name != "trust-folder" {
+		t.Errorf("StartupPromptRules = %v, want custom trust-folder rule", rules)
+	}
+
+	// An explicit empty list disables detection.
+	rc.Prompts.Rules = []config.StartupPromptRule{}
+	if rules := StartupPromptRules(rc); len(rules) != 0 {
+		t.Errorf("StartupPromptRules with empty rules = %v, want none", rules)
+	}
+}
@@ -11,7 +11,7 @@ import (
 // Prefix is the common prefix for rig-level Gas Town tmux sessions.
 const Prefix = "gt-"
 
-// HQPrefix is the prefix for town-level services (Mayor, Deacon).
+// HQPrefix is the prefix for town-level services (Mayor, Deacon, narrator).
 const HQPrefix = "hq-"
 
 // MayorSessionName returns the session name for the Mayor agent.
@@ -26,6 +26,18 @@ func DeaconSessionName() string {
 	return HQPrefix + "deacon"
 }
 
+// NarratorSessionName returns the session name for the narrator agent.
+// One narrator per machine, like the deacon.
+func NarratorSessionName() string {
+	return HQPrefix + "narrator"
+}
+
+// NarratorInstanceSessionName returns the session name for a named
+// narrator instance, run alongside the town's narrator.
+func NarratorInstanceSessionName(name string) string {
+	return HQPrefix + "narrator-" + name
+}
+
 // WitnessSessionName returns the session name for a rig's Witness agent.
 func WitnessSessionName(rig string) string {
 	return fmt.Sprintf("%s%s-witness", Prefix, rig)
@@ -59,6 +71,7 @@ func PropulsionNudge() string {
 // - polecat/crew: Check hook for slung work
 // - witness/refinery: Start patrol cycle
 // - deacon: Start heartbeat patrol
+// - narrator: Check the backlog of events to narrate
 // - mayor: Check mail for coordination work
 //
 // The workDir parameter is used to locate .runtime/session_id for including
@@ -76,6 +89,8 @@ func PropulsionNudgeForRole(role, workDir string) string {
 		msg = "Run `gt prime` to check patrol status and begin heartbeat cycle."
 	case "mayor":
 		msg = "Run `gt prime` to check mail and begin coordination."
+	case "narrator":
+		msg = "Run `gt narrator status` to check the backlog and begin narrating."
 	default:
 		msg = PropulsionNudge()
 	}
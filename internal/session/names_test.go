@@ -25,6 +25,22 @@ func TestDeaconSessionName(t *testing.T) {
 	}
 }
 
+func TestNarratorSessionName(t *testing.T) {
+	want := "hq-narrator"
+	got := NarratorSessionName()
+	if got != want {
+		t.Errorf("NarratorSessionName() = %q, want %q", got, want)
+	}
+}
+
+func TestNarratorInstanceSessionName(t *testing.T) {
+	want := "hq-narrator-changelog"
+	got := NarratorInstanceSessionName("changelog")
+	if got != want {
+		t.Errorf("NarratorInstanceSessionName() = %q, want %q", got, want)
+	}
+}
+
 func TestWitnessSessionName(t *testing.T) {
 	tests := []struct {
 		rig  string
@@ -157,6 +173,7 @@ func TestPropulsionNudgeForRole_AllRoles(t *testing.T) {
 		{"refinery", "gt prime"},
 		{"deacon", "gt prime"},
 		{"mayor", "gt prime"},
+		{"narrator", "gt narrator status"},
 		{"unknown", "gt hook"},
 	}
 
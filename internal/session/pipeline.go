@@ -0,0 +1,273 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/claude"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+// defaultStepTimeout bounds a startup step that doesn't set its own timeout
+// (wait-for-claude defaults to constants.ClaudeStartTimeout).
+const defaultStepTimeout = 30 * time.Second
+
+// errStepTimeout is returned for a step that outlived its timeout.
+var errStepTimeout = errors.New("timed out")
+
+// StartupSpec describes a role session for RunStartup.
+type StartupSpec struct {
+	// Role is the agent role, e.g. "mayor"; it selects the startup steps.
+	Role string
+
+	// TownRoot is the town the session belongs to.
+	TownRoot string
+
+	// Dir is the role's directory, where settings are written.
+	Dir string
+
+	// WorkDir is where the session runs. Default: Dir.
+	WorkDir string
+
+	// Session is the session name.
+	Session string
+
+	// AgentOverride optionally names a different agent alias to run.
+	AgentOverride string
+
+	// AgentName names one of several sessions of a role, e.g. a named
+	// narrator instance; it is passed to the agent's environment.
+	AgentName string
+
+	// Theme, DisplayName and Focus style the tmux status bar.
+	Theme       tmux.Theme
+	DisplayName string
+	Focus       string
+
+	// Nudge is the startup beacon, sent as the first prompt or by
+	// startup-nudge.
+	Nudge StartupNudgeConfig
+}
+
+// DefaultStartupSteps returns the built-in startup sequence for a role,
+// used unless the role's runtime config sets its own.
+func DefaultStartupSteps(role string) []config.StartupStep {
+	steps := []config.StartupStep{
+		{Step: config.StepEnsureSettings},
+		{Step: config.StepCreateSession},
+		{Step: config.StepEnv},
+		{Step: config.StepTheme},
+		{Step: config.StepWaitForClaude},
+		{Step: config.StepAcceptDialog},
+	}
+	switch role {
+	case "mayor":
+		// The mayor starts with its beacon as the first prompt, so it has
+		// its instructions before any nudge could arrive.
+		steps[1].Beacon = true
+	case "deacon", "narrator":
+		// The propulsion nudge must arrive as a separate prompt once the
+		// beacon has been taken in.
+		steps = append(steps,
+			config.StartupStep{Step: config.StepStartupNudge, Delay: constants.ShutdownNotifyDelay.String()},
+			config.StartupStep{Step: config.StepPropulsionNudge, Delay: "2s"},
+		)
+	}
+	return steps
+}
+
+// ValidateStartupSteps checks that steps name known steps with parseable
+// durations, and that a session is created before any step that needs one.
+func ValidateStartupSteps(steps []config.StartupStep) error {
+	created := false
+	for i, s := range steps {
+		switch s.Step {
+		case config.StepEnsureSettings:
+		case config.StepCreateSession:
+			if created {
+				return fmt.Errorf("step %d: session already created", i+1)
+			}
+			created = true
+		case config.StepEnv, config.StepTheme, config.StepWaitForClaude, config.StepAcceptDialog,
+			config.StepStartupNudge, config.StepPropulsionNudge:
+			if !created {
+				return fmt.Errorf("step %d: %s before %s", i+1, s.Step, config.StepCreateSession)
+			}
+		default:
+			return fmt.Errorf("step %d: unknown step %q", i+1, s.Step)
+		}
+		if s.Beacon && s.Step != config.StepCreateSession {
+			return fmt.Errorf("step %d: beacon only applies to %s", i+1, config.StepCreateSession)
+		}
+		if s.Delay != "" {
+			if v, err := time.ParseDuration(s.Delay); err != nil || v < 0 {
+				return fmt.Errorf("step %d: invalid delay %q", i+1, s.Delay)
+			}
+		}
+		// A zero timeout would give up on the step before it starts
+		if s.Timeout != "" {
+			if v, err := time.ParseDuration(s.Timeout); err != nil || v <= 0 {
+				return fmt.Errorf("step %d: invalid timeout %q (must be positive)", i+1, s.Timeout)
+			}
+		}
+	}
+	if !created {
+		return fmt.Errorf("no %s step", config.StepCreateSession)
+	}
+	return nil
+}
+
+// RunStartup brings up a role's session by running its startup steps: the
+// role's entry in the agent's runtime config "startup" map, or
+// DefaultStartupSteps. Steps that need pane access (theme, wait-for-claude,
+// accept-dialog) are skipped on multiplexers other than tmux.
+//
+// The caller is responsible for checking that the session isn't already
+// running.
+func RunStartup(spec StartupSpec) error {
+	rc, err := config.ResolveRoleAgentConfigWithOverride(spec.Role, spec.TownRoot, "", spec.AgentOverride)
+	if err != nil {
+		rc = nil
+	}
+	steps := DefaultStartupSteps(spec.Role)
+	if rc != nil && len(rc.Startup[spec.Role]) > 0 {
+		steps = rc.Startup[spec.Role]
+	}
+	if err := ValidateStartupSteps(steps); err != nil {
+		return fmt.Errorf("%s startup: %w", spec.Role, err)
+	}
+	if spec.WorkDir == "" {
+		spec.WorkDir = spec.Dir
+	}
+
+	envVars := config.AgentEnv(config.AgentEnvConfig{
+		Role:      spec.Role,
+		TownRoot:  spec.TownRoot,
+		AgentName: spec.AgentName,
+	})
+	mux := tmux.NewMultiplexer(spec.TownRoot)
+	t, isTmux := mux.(*tmux.Tmux)
+	for _, step := range steps {
+		if step.Delay != "" {
+			d, _ := time.ParseDuration(step.Delay)
+			time.Sleep(d)
+		}
+		timeout := defaultStepTimeout
+		if step.Step == config.StepWaitForClaude {
+			timeout = constants.ClaudeStartTimeout
+		}
+		if step.Timeout != "" {
+			timeout, _ = time.ParseDuration(step.Timeout)
+		}
+
+		switch step.Step {
+		case config.StepEnsureSettings:
+			if err := os.MkdirAll(spec.Dir, 0755); err != nil {
+				return fmt.Errorf("creating %s directory: %w", spec.Role, err)
+			}
+			if err := claude.EnsureSettingsForRole(spec.Dir, spec.Role); err != nil {
+				return fmt.Errorf("ensuring Claude settings: %w", err)
+			}
+
+		case config.StepCreateSession:
+			// Export GT_ROLE and BD_ACTOR in the command since tmux
+			// SetEnvironment only affects new panes
+			var beacon string
+			if step.Beacon {
+				beacon = FormatStartupNudge(spec.Nudge)
+			}
+			startupCmd, err := config.BuildStartupCommandWithAgentOverride(envVars, "", beacon, spec.AgentOverride)
+			if err != nil {
+				return fmt.Errorf("building startup command: %w", err)
+			}
+			// Create session with command directly to avoid send-keys race condition.
+			// See: https://github.com/anthropics/gastown/issues/280
+			// A session created after the timeout is killed rather than
+			// left running unmanaged
+			if err := withTimeout(timeout, func() error {
+				return mux.NewSessionWithCommand(spec.Session, spec.WorkDir, startupCmd)
+			}, func() { _ = mux.KillSession(spec.Session) }); err != nil {
+				return fmt.Errorf("creating session: %w", err)
+			}
+			// Record the session's output for the transcript archive (non-fatal)
+			if isTmux {
+				_ = transcript.RecordWithReplay(t, spec.TownRoot, spec.Session, spec.Role)
+			}
+
+		case config.StepEnv:
+			// Non-fatal: session works without these
+			_ = withTimeout(timeout, func() error {
+				for k, v := range envVars {
+					_ = mux.SetEnvironment(spec.Session, k, v)
+				}
+				return nil
+			}, nil)
+
+		case config.StepTheme:
+			// Non-fatal: theming failure doesn't affect operation
+			if isTmux {
+				_ = withTimeout(timeout, func() error {
+					return t.ConfigureGasTownSession(spec.Session, spec.Theme, "", spec.DisplayName, spec.Focus)
+				}, nil)
+			}
+
+		case config.StepWaitForClaude:
+			// Fatal if the agent fails to launch
+			if isTmux {
+				if err := t.WaitForCommand(spec.Session, constants.SupportedShells, timeout); err != nil {
+					// Kill the zombie session before returning error
+					_ = t.KillSessionWithProcesses(spec.Session)
+					return fmt.Errorf("waiting for %s to start: %w", spec.Role, err)
+				}
+			}
+
+		case config.StepAcceptDialog:
+			// Accept runtime startup dialogs (e.g., bypass permissions warning) if they appear.
+			if isTmux && rc != nil {
+				_ = withTimeout(timeout, func() error {
+					return runtime.AcceptStartupPrompts(t, spec.Session, rc)
+				}, nil)
+			}
+
+		case config.StepStartupNudge:
+			// Names the session for predecessor discovery via /resume (non-fatal)
+			_ = withTimeout(timeout, func() error {
+				return mux.NudgeSession(spec.Session, FormatStartupNudge(spec.Nudge))
+			}, nil)
+
+		case config.StepPropulsionNudge:
+			// GUPP: Gas Town Universal Propulsion Principle (non-fatal)
+			_ = withTimeout(timeout, func() error {
+				return mux.NudgeSession(spec.Session, PropulsionNudgeForRole(spec.Role, spec.Dir))
+			}, nil)
+		}
+	}
+	return nil
+}
+
+// withTimeout runs fn, giving up on it after timeout. fn keeps running in
+// the background if it overruns; if it then succeeds, undo (when set) runs
+// to reverse what it did.
+func withTimeout(timeout time.Duration, fn func() error, undo func()) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		if undo != nil {
+			go func() {
+				if err := <-done; err == nil {
+					undo()
+				}
+			}()
+		}
+		return errStepTimeout
+	}
+}
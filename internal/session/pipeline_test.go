@@ -0,0 +1,88 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestDefaultStartupSteps(t *testing.T) {
+	for _, role := range []string{"mayor", "deacon", "narrator"} {
+		if err := ValidateStartupSteps(DefaultStartupSteps(role)); err != nil {
+			t.Errorf("%s defaults invalid: %v", role, err)
+		}
+	}
+	mayor := DefaultStartupSteps("mayor")
+	if !mayor[1].Beacon || mayor[len(mayor)-1].Step != config.StepAcceptDialog {
+		t.Errorf("mayor steps = %+v, want the beacon on create-session and no nudges", mayor)
+	}
+	deacon := DefaultStartupSteps("deacon")
+	if last := deacon[len(deacon)-1]; last.Step != config.StepPropulsionNudge || last.Delay != "2s" {
+		t.Errorf("deacon ends with %+v, want a delayed propulsion nudge", last)
+	}
+}
+
+func TestValidateStartupSteps(t *testing.T) {
+	tests := []struct {
+		steps []config.StartupStep
+		want  string // error substring, "" for valid
+	}{
+		{[]config.StartupStep{{Step: "create-session", Beacon: true}, {Step: "propulsion-nudge", Delay: "1s", Timeout: "5s"}}, ""},
+		{[]config.StartupStep{{Step: "ensure-settings"}}, "no create-session"},
+		{[]config.StartupStep{{Step: "theme"}, {Step: "create-session"}}, "theme before create-session"},
+		{[]config.StartupStep{{Step: "create-session"}, {Step: "create-session"}}, "already created"},
+		{[]config.StartupStep{{Step: "create-session"}, {Step: "dance"}}, `unknown step "dance"`},
+		{[]config.StartupStep{{Step: "create-session"}, {Step: "env", Beacon: true}}, "beacon only applies"},
+		{[]config.StartupStep{{Step: "create-session", Timeout: "soon"}}, `invalid timeout "soon"`},
+		{[]config.StartupStep{{Step: "create-session", Timeout: "0s"}}, `invalid timeout "0s"`},
+		{[]config.StartupStep{{Step: "create-session", Delay: "-1s"}}, `invalid delay "-1s"`},
+		{[]config.StartupStep{{Step: "create-session", Delay: "0s"}}, ""},
+	}
+	for _, tt := range tests {
+		err := ValidateStartupSteps(tt.steps)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%+v: unexpected error %v", tt.steps, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%+v: error = %v, want %q", tt.steps, err, tt.want)
+		}
+	}
+}
+
+func TestWithTimeout_UndoesLateSuccess(t *testing.T) {
+	release := make(chan struct{})
+	undone := make(chan struct{})
+	err := withTimeout(10*time.Millisecond, func() error {
+		<-release
+		return nil
+	}, func() { close(undone) })
+	if !errors.Is(err, errStepTimeout) {
+		t.Fatalf("err = %v, want errStepTimeout", err)
+	}
+
+	close(release)
+	select {
+	case <-undone:
+	case <-time.After(time.Second):
+		t.Fatal("step that finished after its timeout wasn't undone")
+	}
+}
+
+func TestWithTimeout_NoUndoOnLateFailure(t *testing.T) {
+	release := make(chan struct{})
+	undone := make(chan struct{})
+	_ = withTimeout(time.Millisecond, func() error {
+		<-release
+		return errors.New("failed")
+	}, func() { close(undone) })
+
+	close(release)
+	select {
+	case <-undone:
+		t.Error("failed step was undone")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,194 @@
+// Package simulate replays an event stream through a sandbox town without
+// tmux or live agents.
+//
+// Events come from a recorded fixture (JSONL in the events log format) or
+// are generated synthetically. The runner writes each one to the sandbox's
+// events log, where the real feed curator picks it up, classifies its
+// significance, delivers mail events to in-memory inboxes, and finally
+// scores rig health from what was written. Replayed
+// events are stamped with the wall-clock time they were written, so the
+// curator's and health score's time windows see the compressed timeline.
+package simulate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/health"
+)
+
+// drainWait gives the feed curator time to process the last events before
+// it is stopped. It polls every 100ms.
+const drainWait = 300 * time.Millisecond
+
+// LoadFixture reads events from a JSONL file in the events log format.
+// Blank lines are skipped; a malformed line is an error.
+func LoadFixture(path string) ([]events.Event, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: fixture path is user-provided by design
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var evs []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if _, err := time.Parse(time.RFC3339, e.Timestamp); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid timestamp %q", path, line, e.Timestamp)
+		}
+		evs = append(evs, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// Report summarizes a simulation run.
+type Report struct {
+	Events       int                      `json:"events"`
+	Significance map[string]int           `json:"significance"` // events per level
+	Mail         map[string][]string      `json:"mail"`         // subjects delivered per recipient
+	FeedLines    int                      `json:"feed_lines"`   // curated feed entries written
+	Health       map[string]*health.Score `json:"health"`       // by rig
+}
+
+// Runner replays events into a sandbox town.
+type Runner struct {
+	TownRoot string    // Sandbox town the events are written to
+	Speed    float64   // Replay speed multiplier; 0 replays without delays
+	Out      io.Writer // Receives one line per replayed event; nil for none
+
+	// sleep waits between events; tests replace it.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Run replays evs in order, honoring the gaps between their timestamps
+// divided by Speed, and returns what happened. It stops early with the
+// context's error if ctx is cancelled.
+func (r *Runner) Run(ctx context.Context, evs []events.Event) (*Report, error) {
+	sleep := r.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	rules, err := events.LoadSignificanceRules(r.TownRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	curator := feed.NewCurator(r.TownRoot)
+	if err := curator.Start(); err != nil {
+		return nil, fmt.Errorf("starting feed curator: %w", err)
+	}
+
+	report := &Report{
+		Significance: make(map[string]int),
+		Mail:         make(map[string][]string),
+	}
+	var first, prev time.Time
+	var runErr error
+	for i, e := range evs {
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		if i == 0 {
+			first = ts
+		} else if gap := ts.Sub(prev); r.Speed > 0 && gap > 0 {
+			if runErr = sleep(ctx, time.Duration(float64(gap)/r.Speed)); runErr != nil {
+				break
+			}
+		}
+		prev = ts
+
+		// Hash the event as recorded, not as replayed: replays compress
+		// time, and distinct events restamped within the same second
+		// would otherwise be dropped by readers as repeats
+		if e.Hash == "" {
+			e.Hash = events.EventHash(e)
+		}
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		if e.Visibility == "" {
+			e.Visibility = events.VisibilityFeed
+		}
+		if runErr = events.Append(r.TownRoot, e); runErr != nil {
+			break
+		}
+
+		report.Events++
+		level := rules.Classify(e)
+		report.Significance[level]++
+		if e.Type == events.TypeMail {
+			to, _ := e.Payload["to"].(string)
+			subject, _ := e.Payload["subject"].(string)
+			report.Mail[to] = append(report.Mail[to], subject)
+		}
+		if r.Out != nil {
+			fmt.Fprintf(r.Out, "+%-8s %-6s %s\n", offset(ts.Sub(first)), level, feed.Summary(&e))
+		}
+	}
+
+	// Let the curator catch up before stopping it
+	_ = sleepContext(context.Background(), drainWait)
+	curator.Stop()
+
+	report.FeedLines = countLines(feedPath(r.TownRoot))
+	records, err := events.ReadAll(r.TownRoot)
+	if err != nil && runErr == nil {
+		runErr = err
+	}
+	report.Health = health.Compute(records, time.Now())
+	return report, runErr
+}
+
+// offset formats a position in the original timeline.
+func offset(d time.Duration) string {
+	return d.Truncate(time.Second).String()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func feedPath(townRoot string) string {
+	return filepath.Join(townRoot, feed.FeedFile)
+}
+
+// countLines counts the lines in a file, or 0 if it can't be read.
+func countLines(path string) int {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from the sandbox root
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	n := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		n++
+	}
+	return n
+}
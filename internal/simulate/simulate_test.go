@@ -0,0 +1,120 @@
+package simulate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestLoadFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.jsonl")
+	data := `{"ts":"2026-01-01T00:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/nux"}}
+
+{"ts":"2026-01-01T00:05:00Z","type":"merged","actor":"gastown/refinery"}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	evs, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	if len(evs) != 2 || evs[1].Type != events.TypeMerged {
+		t.Errorf("LoadFixture = %+v, want sling and merged", evs)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"ts":"yesterday","type":"sling"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixture(path); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("bad timestamp error = %v, want one naming line 1", err)
+	}
+}
+
+func TestSyntheticDeterministic(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := Synthetic([]string{"gastown", "beads"}, 20, start, 7)
+	b := Synthetic([]string{"gastown", "beads"}, 20, start, 7)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("same seed produced different streams")
+	}
+	slings := 0
+	for i, e := range a {
+		if i > 0 && e.Timestamp < a[i-1].Timestamp {
+			t.Fatalf("event %d out of order", i)
+		}
+		if e.Type == events.TypeSling {
+			slings++
+		}
+	}
+	if slings != 20 {
+		t.Errorf("got %d slings, want one per work item", slings)
+	}
+}
+
+func TestRun(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	evs := Synthetic([]string{"gastown"}, 5, start, 3)
+
+	var slept time.Duration
+	var out bytes.Buffer
+	r := &Runner{TownRoot: townRoot, Speed: 10, Out: &out}
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	}
+
+	report, err := r.Run(context.Background(), evs)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if report.Events != len(evs) {
+		t.Errorf("replayed %d events, want %d", report.Events, len(evs))
+	}
+
+	first, _ := time.Parse(time.RFC3339, evs[0].Timestamp)
+	last, _ := time.Parse(time.RFC3339, evs[len(evs)-1].Timestamp)
+	if want := last.Sub(first) / 10; slept != want {
+		t.Errorf("slept %v, want %v at 10x", slept, want)
+	}
+
+	records, err := events.ReadAll(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(evs) {
+		t.Errorf("sandbox log has %d events, want %d", len(records), len(evs))
+	}
+	if report.Mail["gastown/witness"] == nil {
+		t.Error("mail to the witness was not delivered")
+	}
+	if report.Health["gastown"] == nil {
+		t.Error("gastown health not scored")
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != len(evs) {
+		t.Errorf("printed %d lines, want one per event", lines)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	evs := Synthetic([]string{"gastown"}, 3, time.Now().Add(-time.Hour), 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &Runner{TownRoot: t.TempDir(), Speed: 1}
+	report, err := r.Run(ctx, evs)
+	if err != context.Canceled {
+		t.Errorf("Run error = %v, want context.Canceled", err)
+	}
+	if report == nil || report.Events != 1 {
+		t.Errorf("report = %+v, want only the first event replayed", report)
+	}
+}
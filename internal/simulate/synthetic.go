@@ -0,0 +1,70 @@
+package simulate
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// syntheticPolecats name the workers in a synthetic stream.
+var syntheticPolecats = []string{"nux", "toast", "dag", "furiosa", "slit", "capable"}
+
+// Synthetic generates a plausible event stream: count work items spread
+// across rigs, each slung by the mayor, worked by a polecat and merged by
+// the refinery, with occasional merge failures, session deaths and
+// escalations. The same seed always produces the same stream.
+func Synthetic(rigs []string, count int, start time.Time, seed int64) []events.Event {
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // G404: deterministic fixtures, not security
+	var evs []events.Event
+	add := func(at time.Time, typ, actor string, payload map[string]interface{}) {
+		evs = append(evs, events.Event{
+			Timestamp:  at.UTC().Format(time.RFC3339),
+			Source:     "simulate",
+			Type:       typ,
+			Actor:      actor,
+			Payload:    payload,
+			Visibility: events.VisibilityFeed,
+		})
+	}
+
+	for i := 0; i < count && len(rigs) > 0; i++ {
+		rig := rigs[i%len(rigs)]
+		polecat := syntheticPolecats[rng.Intn(len(syntheticPolecats))]
+		worker := rig + "/" + polecat
+		bead := fmt.Sprintf("sim-%03d", i+1)
+		branch := "polecat/" + polecat + "/" + bead
+		at := start.Add(time.Duration(i) * 5 * time.Minute)
+
+		add(at, events.TypeSling, "mayor", events.SlingPayload(bead, worker))
+		at = at.Add(30 * time.Second)
+		add(at, events.TypeSpawn, rig+"/witness", events.WithWork(events.SpawnPayload(rig, polecat), bead))
+
+		at = at.Add(time.Duration(10+rng.Intn(40)) * time.Minute)
+		if rng.Intn(10) == 0 {
+			add(at, events.TypeSessionDeath, "daemon",
+				events.SessionDeathPayload("gt-"+rig+"-"+polecat, worker, "simulated crash", "daemon"))
+			if rng.Intn(2) == 0 {
+				add(at.Add(time.Minute), events.TypeEscalationSent, rig+"/witness",
+					events.EscalationPayload(rig, worker, "mayor", "polecat died mid-task"))
+			}
+			continue
+		}
+		add(at, events.TypeDone, worker, events.DonePayload(bead, branch))
+		add(at, events.TypeMail, worker, events.MailPayload(rig+"/witness", "POLECAT_DONE "+polecat))
+
+		at = at.Add(time.Duration(1+rng.Intn(5)) * time.Minute)
+		mr := fmt.Sprintf("mr-%03d", i+1)
+		if rng.Intn(5) == 0 {
+			add(at, events.TypeMergeFailed, rig+"/refinery", events.MergePayload(mr, polecat, branch, "tests failed"))
+			add(at, events.TypeMail, rig+"/refinery", events.MailPayload(rig+"/witness", "MERGE_FAILED "+bead))
+		} else {
+			add(at, events.TypeMerged, rig+"/refinery", events.MergePayload(mr, polecat, branch, ""))
+		}
+	}
+
+	sort.SliceStable(evs, func(i, j int) bool { return evs[i].Timestamp < evs[j].Timestamp })
+	return evs
+}
@@ -0,0 +1,181 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mail template priorities and types, as written in front-matter.
+var (
+	mailPriorities = []string{"urgent", "high", "normal", "low", "backlog", "0", "1", "2", "3", "4"}
+	mailTypes      = []string{"task", "scavenge", "notification", "reply"}
+)
+
+// MailTemplatesDir returns the directory holding a town's mail templates. A
+// template there overrides the built-in template of the same name.
+func MailTemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "mail", "templates")
+}
+
+// MailTemplate is a canned message: a Markdown file whose YAML front-matter
+// gives the subject, priority and type, followed by the body. Subject and
+// body are Go text/templates over the variables the sender passes.
+type MailTemplate struct {
+	Name     string            `yaml:"-" json:"name"`
+	Subject  string            `yaml:"subject" json:"subject"`
+	Priority string            `yaml:"priority,omitempty" json:"priority,omitempty"` // urgent, high, normal, low, backlog or 0-4
+	Type     string            `yaml:"type,omitempty" json:"type,omitempty"`         // task, scavenge, notification or reply
+	Vars     map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`         // optional variables and their defaults
+	Body     string            `yaml:"-" json:"body"`
+}
+
+// Mail is a rendered mail template.
+type Mail struct {
+	Subject  string
+	Body     string
+	Priority string
+	Type     string
+}
+
+// MailTemplateNames returns the names of the built-in mail templates and
+// any in the town's mail/templates/ directory, sorted.
+func MailTemplateNames(townRoot string) ([]string, error) {
+	seen := make(map[string]bool)
+	builtin, err := fs.Glob(townFS, "town/mail/templates/*.md")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range builtin {
+		seen[strings.TrimSuffix(filepath.Base(path), ".md")] = true
+	}
+	if townRoot != "" {
+		local, _ := filepath.Glob(filepath.Join(MailTemplatesDir(townRoot), "*.md"))
+		for _, path := range local {
+			seen[strings.TrimSuffix(filepath.Base(path), ".md")] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadMailTemplate returns the named mail template, preferring the town's
+// copy over the built-in one.
+func LoadMailTemplate(townRoot, name string) (*MailTemplate, error) {
+	if strings.ContainsAny(name, `/\`) || name == "" {
+		return nil, fmt.Errorf("invalid mail template name %q", name)
+	}
+	var data []byte
+	if townRoot != "" {
+		local, err := os.ReadFile(filepath.Join(MailTemplatesDir(townRoot), name+".md")) //nolint:gosec // G304: name has no path separators
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		data = local
+	}
+	if data == nil {
+		builtin, err := townFS.ReadFile("town/mail/templates/" + name + ".md")
+		if err != nil {
+			return nil, fmt.Errorf("mail template %q not found", name)
+		}
+		data = builtin
+	}
+	t, err := parseMailTemplate(data)
+	if err != nil {
+		return nil, fmt.Errorf("mail template %s: %w", name, err)
+	}
+	t.Name = name
+	return t, nil
+}
+
+// parseMailTemplate splits a template into front-matter and body and
+// checks both.
+func parseMailTemplate(data []byte) (*MailTemplate, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		return nil, errors.New("missing front-matter (start the file with a --- line)")
+	}
+	front, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		if front, ok = strings.CutSuffix(rest, "\n---"); !ok {
+			return nil, errors.New("front-matter is not closed with a --- line")
+		}
+	}
+
+	var t MailTemplate
+	dec := yaml.NewDecoder(strings.NewReader(front))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("parsing front-matter: %w", err)
+	}
+	t.Body = strings.TrimSpace(body)
+	if strings.TrimSpace(t.Subject) == "" {
+		return nil, errors.New("front-matter has no subject")
+	}
+	if t.Priority != "" && !slices.Contains(mailPriorities, t.Priority) {
+		return nil, fmt.Errorf("unknown priority %q (want urgent, high, normal, low, backlog or 0-4)", t.Priority)
+	}
+	if t.Type != "" && !slices.Contains(mailTypes, t.Type) {
+		return nil, fmt.Errorf("unknown type %q (want %s)", t.Type, strings.Join(mailTypes, ", "))
+	}
+	for field, src := range map[string]string{"subject": t.Subject, "body": t.Body} {
+		if _, err := template.New(field).Parse(src); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", field, err)
+		}
+	}
+	return &t, nil
+}
+
+// Render renders the template with vars, which add to or override its
+// optional variables. A variable the template uses but neither gives is an
+// error, so messages never go out half-filled.
+func (t *MailTemplate) Render(vars map[string]string) (*Mail, error) {
+	data := make(map[string]string, len(t.Vars)+len(vars))
+	for k, v := range t.Vars {
+		data[k] = v
+	}
+	for k, v := range vars {
+		data[k] = v
+	}
+	render := func(field, src string) (string, error) {
+		tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(src)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("rendering mail template %s %s: %w", t.Name, field, err)
+		}
+		return buf.String(), nil
+	}
+	subject, err := render("subject", t.Subject)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render("body", t.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Mail{
+		// Subjects are one line
+		Subject:  strings.Join(strings.Fields(subject), " "),
+		Body:     strings.TrimSpace(body),
+		Priority: t.Priority,
+		Type:     t.Type,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed nudges/*.tmpl
+var nudgesFS embed.FS
+
+// NudgesDir returns the directory holding a town's nudge templates. A
+// template there overrides the built-in template of the same name.
+func NudgesDir(townRoot string) string {
+	return filepath.Join(townRoot, "nudges")
+}
+
+// NudgeTemplateNames returns the names of the built-in nudge templates and
+// any in the town's nudges/ directory, sorted.
+func NudgeTemplateNames(townRoot string) ([]string, error) {
+	seen := make(map[string]bool)
+	builtin, err := fs.Glob(nudgesFS, "nudges/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range builtin {
+		seen[strings.TrimSuffix(filepath.Base(path), ".tmpl")] = true
+	}
+	if townRoot != "" {
+		local, _ := filepath.Glob(filepath.Join(NudgesDir(townRoot), "*.tmpl"))
+		for _, path := range local {
+			seen[strings.TrimSuffix(filepath.Base(path), ".tmpl")] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// loadNudgeTemplate returns the source of a nudge template, preferring the
+// town's copy over the built-in one.
+func loadNudgeTemplate(townRoot, name string) (string, error) {
+	if strings.ContainsAny(name, `/\`) || name == "" {
+		return "", fmt.Errorf("invalid nudge template name %q", name)
+	}
+	if townRoot != "" {
+		data, err := os.ReadFile(filepath.Join(NudgesDir(townRoot), name+".tmpl")) //nolint:gosec // G304: name has no path separators
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	data, err := nudgesFS.ReadFile("nudges/" + name + ".tmpl")
+	if err != nil {
+		return "", fmt.Errorf("nudge template %q not found", name)
+	}
+	return string(data), nil
+}
+
+// RenderNudge renders the named nudge template with vars. Templates refer to
+// variables as {{.bead}}, {{.reason}}, etc. A variable the template uses but
+// vars doesn't contain is an error, so nudges never go out half-filled;
+// callers pass optional variables as "" so templates can guard them with
+// {{with .reason}}...{{end}}.
+func RenderNudge(townRoot, name string, vars map[string]string) (string, error) {
+	src, err := loadNudgeTemplate(townRoot, name)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("parsing nudge template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("rendering nudge template %s: %w", name, err)
+	}
+	// Nudges are typed into a session as one line
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"text/template"
@@ -16,6 +17,9 @@ var templateFS embed.FS
 //go:embed commands/*.md
 var commandsFS embed.FS
 
+//go:embed town
+var townFS embed.FS
+
 // Templates manages role and message templates.
 type Templates struct {
 	roleTemplates    *template.Template
@@ -225,6 +229,47 @@ func ProvisionCommands(workspacePath string) error {
 	return nil
 }
 
+// ProvisionTown scaffolds the town-level files the narrator and mail
+// subsystems expect: narrator/ with its style templates, mail/templates/,
+// and a commented town.yaml. Existing files are kept (no overwrite).
+// Returns the paths created, relative to townRoot.
+func ProvisionTown(townRoot string) ([]string, error) {
+	var created []string
+	err := fs.WalkDir(townFS, "town", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel("town", path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(townRoot, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(destPath, 0755)
+		}
+
+		// Skip if the file already exists (don't overwrite user customizations)
+		if _, err := os.Stat(destPath); err == nil {
+			return nil
+		}
+
+		content, err := townFS.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		if err := os.WriteFile(destPath, content, 0644); err != nil { //nolint:gosec // G306: template files are non-sensitive
+			return fmt.Errorf("writing %s: %w", rel, err)
+		}
+		created = append(created, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return created, fmt.Errorf("provisioning town files: %w", err)
+	}
+	return created, nil
+}
+
 // CommandNames returns the list of embedded slash commands.
 func CommandNames() ([]string, error) {
 	entries, err := commandsFS.ReadDir("commands")
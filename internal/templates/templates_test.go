@@ -1,6 +1,8 @@
 package templates
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -295,3 +297,171 @@ func TestGetAllRoleTemplates_ContentValidity(t *testing.T) {
 		}
 	}
 }
+
+func TestProvisionTown(t *testing.T) {
+	townRoot := t.TempDir()
+
+	// A customized file must survive provisioning.
+	if err := os.MkdirAll(filepath.Join(townRoot, "narrator", "styles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := filepath.Join(townRoot, "narrator", "styles", "default.md")
+	if err := os.WriteFile(custom, []byte("my style"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := ProvisionTown(townRoot)
+	if err != nil {
+		t.Fatalf("ProvisionTown() error = %v", err)
+	}
+
+	for _, rel := range []string{"town.yaml", "narrator/README.md", "mail/templates/status.md"} {
+		if _, err := os.Stat(filepath.Join(townRoot, rel)); err != nil {
+			t.Errorf("%s not created: %v", rel, err)
+		}
+	}
+	for _, rel := range created {
+		if rel == "narrator/styles/default.md" {
+			t.Error("existing style template was reported as created")
+		}
+	}
+	if got, _ := os.ReadFile(custom); string(got) != "my style" {
+		t.Errorf("existing style template overwritten: %q", got)
+	}
+
+	again, err := ProvisionTown(townRoot)
+	if err != nil {
+		t.Fatalf("second ProvisionTown() error = %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("second ProvisionTown() created %v, want nothing", again)
+	}
+}
+
+func TestRenderNudge(t *testing.T) {
+	vars := map[string]string{"target": "greenplace/Toast", "role": "polecat", "rig": "greenplace", "reason": "", "bead": "gt-42"}
+	msg, err := RenderNudge("", "stuck-merge", vars)
+	if err != nil {
+		t.Fatalf("RenderNudge() error = %v", err)
+	}
+	if !strings.HasPrefix(msg, "greenplace/Toast: your merge of gt-42") || strings.Contains(msg, "\n") {
+		t.Errorf("RenderNudge() = %q", msg)
+	}
+
+	delete(vars, "bead")
+	if _, err := RenderNudge("", "stuck-merge", vars); err == nil {
+		t.Error("RenderNudge() with missing bead should fail")
+	}
+	if _, err := RenderNudge("", "no-such-template", vars); err == nil {
+		t.Error("RenderNudge() with unknown template should fail")
+	}
+}
+
+func TestRenderNudge_TownOverride(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(NudgesDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(NudgesDir(townRoot), "check-mail.tmpl"), []byte("{{.target}}, mail!\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(NudgesDir(townRoot), "wrap-up.tmpl"), []byte("{{.target}}, wrap up\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := RenderNudge(townRoot, "check-mail", map[string]string{"target": "mayor"})
+	if err != nil {
+		t.Fatalf("RenderNudge() error = %v", err)
+	}
+	if msg != "mayor, mail!" {
+		t.Errorf("town override not used: %q", msg)
+	}
+
+	names, err := NudgeTemplateNames(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := strings.Join(names, ",")
+	if !strings.Contains(joined, "wrap-up") || !strings.Contains(joined, "stuck-merge") {
+		t.Errorf("NudgeTemplateNames() = %v, want built-in and town templates", names)
+	}
+}
+
+func TestMailTemplates_BuiltinsParse(t *testing.T) {
+	names, err := MailTemplateNames("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(names, ","), "merge-failed") {
+		t.Errorf("MailTemplateNames() = %v, want merge-failed", names)
+	}
+	for _, name := range names {
+		if _, err := LoadMailTemplate("", name); err != nil {
+			t.Errorf("built-in %s: %v", name, err)
+		}
+	}
+}
+
+func TestMailTemplate_Render(t *testing.T) {
+	tmpl, err := LoadMailTemplate("", "merge-failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mail, err := tmpl.Render(map[string]string{"worker": "Toast", "bead": "gt-42", "from": "gastown/refinery", "reason": "tests failed"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if mail.Subject != "Merge failed: gt-42" || mail.Priority != "high" || mail.Type != "task" {
+		t.Errorf("mail = %+v", mail)
+	}
+	for _, want := range []string{"Toast: the refinery could not merge gt-42.", "Reason: tests failed", "— gastown/refinery"} {
+		if !strings.Contains(mail.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, mail.Body)
+		}
+	}
+
+	// worker is required: it has no default in the front-matter
+	if _, err := tmpl.Render(map[string]string{"bead": "gt-42", "from": "x"}); err == nil {
+		t.Error("Render() without a required variable should fail")
+	}
+}
+
+func TestMailTemplate_TownOverrideAndInvalid(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := MailTemplatesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, src string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("merged", "---\nsubject: \"Landed {{.bead}}\"\n---\nShipped.\n")
+	tmpl, err := LoadMailTemplate(townRoot, "merged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mail, err := tmpl.Render(map[string]string{"bead": "gt-1"}); err != nil || mail.Subject != "Landed gt-1" || mail.Body != "Shipped." {
+		t.Errorf("town override = %+v, %v", mail, err)
+	}
+
+	for name, src := range map[string]string{
+		"no-front-matter": "Hello\n",
+		"unclosed":        "---\nsubject: x\n",
+		"no-subject":      "---\ntype: task\n---\nbody\n",
+		"bad-priority":    "---\nsubject: x\npriority: soon\n---\n",
+		"bad-type":        "---\nsubject: x\ntype: memo\n---\n",
+		"unknown-field":   "---\nsubject: x\npinned: true\n---\n",
+		"bad-template":    "---\nsubject: x\n---\n{{.worker\n",
+	} {
+		write(name, src)
+		if _, err := LoadMailTemplate(townRoot, name); err == nil {
+			t.Errorf("%s: loaded %q", name, src)
+		}
+	}
+	if _, err := LoadMailTemplate(townRoot, "../secrets"); err == nil {
+		t.Error("template name with a path should be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package tmux
+
+import (
+	"os"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Multiplexer is the session operations agent managers need from a terminal
+// multiplexer. *Tmux implements it, as does *Zellij for towns that run
+// zellij. Anything beyond these (theming, pane inspection, transcripts)
+// remains tmux-only; callers type-assert *Tmux for it.
+type Multiplexer interface {
+	NewSessionWithCommand(name, workDir, command string) error
+	HasSession(name string) (bool, error)
+	KillSession(name string) error
+	SendKeys(session, keys string) error
+	SetEnvironment(session, key, value string) error
+	NudgeSession(session, message string) error
+}
+
+var (
+	_ Multiplexer = (*Tmux)(nil)
+	_ Multiplexer = (*Zellij)(nil)
+)
+
+// MuxEnvVar overrides the town's configured multiplexer.
+const MuxEnvVar = "GT_MUX"
+
+// MultiplexerName returns the multiplexer a town uses: $GT_MUX if set,
+// otherwise the multiplexer in settings/config.json, defaulting to tmux.
+func MultiplexerName(townRoot string) string {
+	if name := os.Getenv(MuxEnvVar); name != "" {
+		return name
+	}
+	if townRoot != "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err == nil && settings.Multiplexer != "" {
+			return settings.Multiplexer
+		}
+	}
+	return config.MultiplexerTmux
+}
+
+// NewMultiplexer returns the multiplexer a town is configured to use.
+// Unrecognized names fall back to tmux.
+func NewMultiplexer(townRoot string) Multiplexer {
+	if MultiplexerName(townRoot) == config.MultiplexerZellij {
+		return NewZellij()
+	}
+	return NewTmux()
+}
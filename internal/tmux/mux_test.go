@@ -0,0 +1,54 @@
+package tmux
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMultiplexerName(t *testing.T) {
+	t.Setenv(MuxEnvVar, "")
+	town := t.TempDir()
+
+	if got := MultiplexerName(town); got != config.MultiplexerTmux {
+		t.Errorf("default = %q, want tmux", got)
+	}
+	if _, ok := NewMultiplexer(town).(*Tmux); !ok {
+		t.Error("default multiplexer is not *Tmux")
+	}
+
+	if err := os.MkdirAll(filepath.Join(town, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Multiplexer = config.MultiplexerZellij
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := MultiplexerName(town); got != config.MultiplexerZellij {
+		t.Errorf("configured = %q, want zellij", got)
+	}
+	if _, ok := NewMultiplexer(town).(*Zellij); !ok {
+		t.Error("configured multiplexer is not *Zellij")
+	}
+
+	t.Setenv(MuxEnvVar, config.MultiplexerTmux)
+	if got := MultiplexerName(town); got != config.MultiplexerTmux {
+		t.Errorf("GT_MUX override = %q, want tmux", got)
+	}
+}
+
+func TestParseZellijSessions(t *testing.T) {
+	out := `hq-mayor [Created 2h ago]
+gt-gastown-witness [Created 10m ago] (current)
+hq-deacon [Created 1d ago] (EXITED - attach to resurrect)
+
+`
+	want := []string{"hq-mayor", "gt-gastown-witness"}
+	if got := parseZellijSessions(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseZellijSessions = %v, want %v", got, want)
+	}
+}
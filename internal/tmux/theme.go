@@ -40,6 +40,12 @@ func DeaconTheme() Theme {
 	return Theme{Name: "deacon", BG: "#2d1f3d", FG: "#c0b0d0"}
 }
 
+// NarratorTheme returns the special theme for the narrator session.
+// Ink on parchment, distinct from the Mayor and Deacon.
+func NarratorTheme() Theme {
+	return Theme{Name: "narrator", BG: "#3b2f24", FG: "#efe4cf"}
+}
+
 // GetThemeByName finds a theme by name from the default palette.
 // Returns nil if not found.
 func GetThemeByName(name string) *Theme {
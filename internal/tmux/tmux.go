@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -106,6 +107,21 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 	return err
 }
 
+// SplitWindow splits a pane of target (a session, window or pane) running
+// command, below it and lines tall, without switching to it. It returns
+// the new pane's ID (e.g., "%3").
+func (t *Tmux) SplitWindow(target, workDir, command string, lines int) (string, error) {
+	args := []string{"split-window", "-d", "-v", "-t", target, "-P", "-F", "#{pane_id}"}
+	if lines > 0 {
+		args = append(args, "-l", strconv.Itoa(lines))
+	}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	args = append(args, command)
+	return t.run(args...)
+}
+
 // EnsureSessionFresh ensures a session is available and healthy.
 // If the session exists but is a zombie (Claude not running), it kills the session first.
 // This prevents "session already exists" errors when trying to restart dead agents.
@@ -278,6 +294,30 @@ func (t *Tmux) ListSessions() ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ListSessionActivity returns the last activity time of every session, in
+// one tmux call. Sessions whose activity tmux doesn't report are omitted.
+func (t *Tmux) ListSessionActivity() (map[string]time.Time, error) {
+	out, err := t.run("list-sessions", "-F", "#{session_name}|#{session_activity}")
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return map[string]time.Time{}, nil
+		}
+		return nil, err
+	}
+
+	activity := make(map[string]time.Time)
+	for _, line := range strings.Split(out, "\n") {
+		name, secs, ok := strings.Cut(line, "|")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(strings.TrimSpace(secs), 10, 64); err == nil && n > 0 {
+			activity[name] = time.Unix(n, 0)
+		}
+	}
+	return activity, nil
+}
+
 // SessionSet provides O(1) session existence checks by caching session names.
 // Use this when you need to check multiple sessions to avoid N+1 subprocess calls.
 type SessionSet struct {
@@ -535,44 +575,45 @@ func (t *Tmux) NudgePane(pane, message string) error {
 	return fmt.Errorf("failed to send Enter after 3 attempts: %w", lastErr)
 }
 
-// AcceptBypassPermissionsWarning dismisses the Claude Code bypass permissions warning dialog.
-// When Claude starts with --dangerously-skip-permissions, it shows a warning dialog that
-// requires pressing Down arrow to select "Yes, I accept" and then Enter to confirm.
-// This function checks if the warning is present before sending keys to avoid interfering
-// with sessions that don't show the warning (e.g., already accepted or different config).
+// AcceptStartupPrompts dismisses runtime startup dialogs matching the given rules,
+// such as the Claude Code bypass permissions warning shown with
+// --dangerously-skip-permissions. The pane is checked for each rule's Match text
+// before sending its Keys, so sessions that don't show a dialog are not disturbed.
+// Returns the names of the dialogs that were accepted.
 //
-// Call this after starting Claude and waiting for it to initialize (WaitForCommand),
-// but before sending any prompts.
-func (t *Tmux) AcceptBypassPermissionsWarning(session string) error {
+// Call this after starting the runtime and waiting for it to initialize
+// (WaitForCommand), but before sending any prompts.
+func (t *Tmux) AcceptStartupPrompts(session string, rules []config.StartupPromptRule) ([]string, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
 	// Wait for the dialog to potentially render
 	time.Sleep(1 * time.Second)
 
-	// Check if the bypass permissions warning is present
 	content, err := t.CapturePane(session, 30)
 	if err != nil {
-		return err
-	}
-
-	// Look for the characteristic warning text
-	if !strings.Contains(content, "Bypass Permissions mode") {
-		// Warning not present, nothing to do
-		return nil
-	}
-
-	// Press Down to select "Yes, I accept" (option 2)
-	if _, err := t.run("send-keys", "-t", session, "Down"); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Small delay to let selection update
-	time.Sleep(200 * time.Millisecond)
-
-	// Press Enter to confirm
-	if _, err := t.run("send-keys", "-t", session, "Enter"); err != nil {
-		return err
+	var accepted []string
+	for _, rule := range rules {
+		if rule.Match == "" || !strings.Contains(content, rule.Match) {
+			continue
+		}
+		for i, key := range rule.Keys {
+			if i > 0 {
+				// Small delay to let the selection update between keys
+				time.Sleep(200 * time.Millisecond)
+			}
+			if _, err := t.run("send-keys", "-t", session, key); err != nil {
+				return accepted, err
+			}
+		}
+		accepted = append(accepted, rule.Name)
 	}
 
-	return nil
+	return accepted, nil
 }
 
 // GetPaneCommand returns the current command running in a pane.
@@ -1268,3 +1309,17 @@ func (t *Tmux) SetPaneDiedHook(session, agentID string) error {
 	_, err := t.run("set-hook", "-t", session, "pane-died", hookCmd)
 	return err
 }
+
+// PipePaneToFile appends everything the session's pane outputs to path, for
+// transcript recording. Replaces any existing pipe on the pane.
+func (t *Tmux) PipePaneToFile(session, path string) error {
+	path = strings.ReplaceAll(path, "'", "'\\''")
+	return t.PipePane(session, fmt.Sprintf("cat >> '%s'", path))
+}
+
+// PipePane pipes everything the session's pane outputs to a shell command.
+// Replaces any existing pipe on the pane.
+func (t *Tmux) PipePane(session, command string) error {
+	_, err := t.run("pipe-pane", "-t", session, command)
+	return err
+}
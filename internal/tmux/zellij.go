@@ -0,0 +1,148 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Zellij runs agent sessions in zellij. Each session gets a single shell
+// pane and commands are typed into it, the way tmux send-keys drives a
+// pane.
+type Zellij struct{}
+
+// NewZellij creates a new zellij wrapper.
+func NewZellij() *Zellij {
+	return &Zellij{}
+}
+
+// run executes a zellij command and returns stdout.
+func (z *Zellij) run(args ...string) (string, error) {
+	cmd := exec.Command("zellij", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("zellij %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("zellij %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// action runs a zellij action against a session.
+func (z *Zellij) action(session string, args ...string) error {
+	_, err := z.run(append([]string{"--session", session, "action"}, args...)...)
+	return err
+}
+
+// NewSessionWithCommand creates a background session in workDir and starts
+// command in its shell.
+func (z *Zellij) NewSessionWithCommand(name, workDir, command string) error {
+	if exists, err := z.HasSession(name); err != nil {
+		return err
+	} else if exists {
+		return ErrSessionExists
+	}
+
+	args := []string{"attach", "--create-background", name}
+	if workDir != "" {
+		args = append(args, "options", "--default-cwd", workDir)
+	}
+	if _, err := z.run(args...); err != nil {
+		return err
+	}
+	return z.SendKeys(name, command)
+}
+
+// HasSession reports whether a live session exists. Exited sessions that
+// zellij keeps for resurrection don't count.
+func (z *Zellij) HasSession(name string) (bool, error) {
+	out, err := z.run("list-sessions", "--no-formatting")
+	if err != nil {
+		// zellij exits non-zero when there are no sessions at all.
+		if strings.Contains(err.Error(), "No active zellij sessions") {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, s := range parseZellijSessions(out) {
+		if s == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseZellijSessions extracts live session names from list-sessions
+// output, one "name [Created ...] (EXITED ...)" line per session.
+func parseZellijSessions(out string) []string {
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.Contains(line, "EXITED") {
+			continue
+		}
+		names = append(names, fields[0])
+	}
+	return names
+}
+
+// KillSession terminates a session.
+func (z *Zellij) KillSession(name string) error {
+	_, err := z.run("kill-session", name)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return ErrSessionNotFound
+	}
+	return err
+}
+
+// SendKeys types text into the session's focused pane and presses Enter.
+func (z *Zellij) SendKeys(session, keys string) error {
+	if err := z.action(session, "write-chars", keys); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(constants.DefaultDebounceMs) * time.Millisecond)
+	return z.action(session, "write", "13")
+}
+
+// SetEnvironment is a no-op: zellij has no per-session environment table.
+// Agent startup commands export the variables they need, so sessions work
+// without it.
+func (z *Zellij) SetEnvironment(session, key, value string) error {
+	return nil
+}
+
+// NudgeSession types a message into the session and submits it. Nudges to
+// the same session are serialized, as with tmux.
+func (z *Zellij) NudgeSession(session, message string) error {
+	lock := getSessionNudgeLock(session)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := z.action(session, "write-chars", message); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	// Escape leaves vim INSERT mode if enabled (harmless in normal mode).
+	_ = z.action(session, "write", "27")
+	time.Sleep(100 * time.Millisecond)
+
+	var lastErr error
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if lastErr = z.action(session, "write", "13"); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to send Enter after 3 attempts: %w", lastErr)
+}
@@ -0,0 +1,348 @@
+package town
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// ArchiveVersion is the current state archive format version.
+const ArchiveVersion = 1
+
+// manifestName is the archive's first entry; files follow under filesPrefix.
+const (
+	manifestName = "manifest.json"
+	filesPrefix  = "files/"
+)
+
+// Manifest describes a state archive.
+type Manifest struct {
+	Type      string    `json:"type"` // "town-archive"
+	Version   int       `json:"version"`
+	Town      string    `json:"town,omitempty"` // name from mayor/town.json
+	Host      string    `json:"host,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Files are the archived paths, relative to the town root.
+	Files []string `json:"files"`
+
+	// Events is where each events log ended when the archive was taken, by
+	// shard ("" for the town-wide log): the state in the archive has seen
+	// the events up to here.
+	Events map[string]events.Offset `json:"events"`
+
+	// IncludesEvents reports whether the logs themselves are archived.
+	IncludesEvents bool `json:"includes_events,omitempty"`
+
+	// Topology is the town's declared topology at the time.
+	Topology *Snapshot `json:"topology,omitempty"`
+}
+
+// ArchiveOptions control what a state archive holds.
+type ArchiveOptions struct {
+	// Events archives the events logs, annotations and remap history too,
+	// for moving a town to another machine.
+	Events bool
+
+	// IncludeKey archives the town's events key with the logs, as
+	// .events.key in the town root. Anyone holding the archive can then
+	// read the encrypted events, so it is never included by default.
+	IncludeKey bool
+}
+
+// statePaths are the town's state files, as globs relative to the town
+// root. Directories matched by a walk pattern are archived whole; other
+// patterns only archive the files they match.
+var statePaths = []struct {
+	pattern string
+	walk    bool
+}{
+	{"mayor/*", false},  // town.json, rigs.json, accounts, overseer, patrols, snapshot
+	{"deacon/*", false}, // heartbeat and health-check state
+	{"daemon/*", false}, // daemon state, supervisor, suspended agents, escalations
+	{"settings", true},
+	{"config", true},
+	{"narrator", true},
+	{constants.DirRuntime, true}, // offsets, subscriptions, scheduled mail, notices
+	{"logs/usage", true},
+	{".beads/mail-index", true},
+	{"*/.beads/mail-index", true},
+	{"*/mayor/rig/.beads/mail-index", true},
+	{"*/config.json", false}, // rig config
+	{"*/settings", true},     // rig settings
+}
+
+// eventPaths are the events files archived with ArchiveOptions.Events.
+var eventPaths = []struct {
+	pattern string
+	walk    bool
+}{
+	{events.EventsFile, false},
+	{events.ShardDir, true},
+	{events.AnnotationsFile, false},
+	{events.RemapFile, false},
+}
+
+// skipState reports whether a file under a state directory is process
+// bookkeeping rather than state: pid files, locks, sockets and logs.
+func skipState(name string) bool {
+	for _, ext := range []string{".pid", ".lock", ".sock", ".log"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// stateFiles returns the town-relative paths of the files to archive,
+// sorted.
+func stateFiles(townRoot string, opts ArchiveOptions) ([]string, error) {
+	patterns := statePaths
+	if opts.Events {
+		patterns = append(patterns[:len(patterns):len(patterns)], eventPaths...)
+	}
+	seen := make(map[string]bool)
+	add := func(abs string) {
+		if rel, err := filepath.Rel(townRoot, abs); err == nil {
+			seen[filepath.ToSlash(rel)] = true
+		}
+	}
+	for _, p := range patterns {
+		matches, err := filepath.Glob(filepath.Join(townRoot, filepath.FromSlash(p.pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			info, err := os.Lstat(m)
+			if err != nil {
+				continue
+			}
+			if info.Mode().IsRegular() && !skipState(info.Name()) {
+				add(m)
+				continue
+			}
+			if !info.IsDir() || !p.walk {
+				continue
+			}
+			err = filepath.WalkDir(m, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.Type().IsRegular() && !skipState(d.Name()) {
+					add(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// WriteArchive writes a gzipped tar of the town's state to w: mayor,
+// deacon and daemon state, settings and config, narrator state, runtime
+// offsets and queues, usage, mail indexes and rig settings, with a manifest
+// recording where the events logs ended. Stop the daemon first for a
+// consistent archive.
+func WriteArchive(townRoot string, w io.Writer, opts ArchiveOptions) (*Manifest, error) {
+	if opts.IncludeKey && !opts.Events {
+		return nil, errors.New("the events key is only archived with the events logs")
+	}
+	files, err := stateFiles(townRoot, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing state files: %w", err)
+	}
+	// The key may be kept outside the town; it is restored into the town
+	// root, where it is still found
+	var key []byte
+	if opts.IncludeKey {
+		if key, err = os.ReadFile(events.KeyPath(townRoot)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading events key: %w", err)
+		}
+		if key != nil {
+			files = append(files, events.KeyFile)
+			sort.Strings(files)
+		}
+	}
+	pos, err := events.Position(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading events position: %w", err)
+	}
+	m := &Manifest{
+		Type:           "town-archive",
+		Version:        ArchiveVersion,
+		CreatedAt:      time.Now().UTC(),
+		Files:          files,
+		Events:         pos,
+		IncludesEvents: opts.Events,
+	}
+	m.Host, _ = os.Hostname()
+	if tc, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil {
+		m.Town = tc.Name
+	}
+	if snap, err := Declared(townRoot); err == nil {
+		m.Topology = snap
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, manifestName, data, 0644, m.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		if rel == events.KeyFile && key != nil {
+			if err := writeTarFile(tw, filesPrefix+rel, key, 0600, m.CreatedAt); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		abs := filepath.Join(townRoot, filepath.FromSlash(rel))
+		data, err := os.ReadFile(abs) //nolint:gosec // G304: path is under the town root
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, filesPrefix+rel, data, info.Mode().Perm(), info.ModTime()); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, mode os.FileMode, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     int64(mode),
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadManifest reads an archive's manifest without restoring it.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a town archive: %w", err)
+	}
+	defer gz.Close()
+	return readManifest(tar.NewReader(gz))
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, errors.New("not a town archive: no manifest")
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if m.Type != "town-archive" {
+		return nil, fmt.Errorf("not a town archive: type %q", m.Type)
+	}
+	if m.Version > ArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is newer than supported (%d)", m.Version, ArchiveVersion)
+	}
+	return &m, nil
+}
+
+// RestoreArchive writes an archive's files into townRoot, replacing the
+// files there of the same name. Files the archive doesn't hold are left
+// alone. Returns the archive's manifest.
+func RestoreArchive(townRoot string, r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a town archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	m, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !strings.HasPrefix(hdr.Name, filesPrefix) {
+			continue
+		}
+		rel := path.Clean(strings.TrimPrefix(hdr.Name, filesPrefix))
+		if path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+			return nil, fmt.Errorf("archive entry %q is outside the town", hdr.Name)
+		}
+		dest := filepath.Join(townRoot, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return nil, err
+		}
+		if err := restoreFile(dest, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+			return nil, fmt.Errorf("restoring %s: %w", rel, err)
+		}
+	}
+}
+
+// restoreFile writes r to dest through a temp file and rename.
+func restoreFile(dest string, r io.Reader, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".restore-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := io.Copy(tmp, r); err != nil { //nolint:gosec // G110: archives are written by gt town snapshot
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
@@ -0,0 +1,136 @@
+package town
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	townRoot := setupTown(t, "gastown")
+	files := map[string]string{
+		"narrator/state.json":                           `{"offset": 2}`,
+		"daemon/state.json":                             `{"running": true}`,
+		".runtime/event-subscriptions/feed.json":        `{"name": "feed"}`,
+		"settings/config.json":                          `{}`,
+		"gastown/settings/config.json":                  `{}`,
+		".beads/mail-index/mayor.json":                  `{}`,
+		events.EventsFile:                               "{\"type\":\"sling\"}\n{\"type\":\"done\"}\n",
+		"daemon/daemon.pid":                             "4242",
+		"gastown/polecats/Toast/src/main.go":            "package main",
+		filepath.Join(events.ShardDir, "gastown.jsonl"): "{\"type\":\"spawn\"}\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(townRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	m, err := WriteArchive(townRoot, &buf, ArchiveOptions{})
+	if err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+	want := []string{
+		".beads/mail-index/mayor.json",
+		".runtime/event-subscriptions/feed.json",
+		"daemon/state.json",
+		"gastown/settings/config.json",
+		"mayor/rigs.json",
+		"narrator/state.json",
+		"settings/config.json",
+	}
+	if !reflect.DeepEqual(m.Files, want) {
+		t.Errorf("archived %v\nwant %v", m.Files, want)
+	}
+	if m.Events[""].Lines != 2 || m.Events["gastown"].Lines != 1 {
+		t.Errorf("events position = %+v, want 2 town lines and 1 gastown line", m.Events)
+	}
+	if m.Topology == nil || len(m.Topology.Rigs) != 1 {
+		t.Errorf("topology = %+v", m.Topology)
+	}
+
+	// Restore onto a fresh machine
+	dest := t.TempDir()
+	restored, err := RestoreArchive(dest, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("RestoreArchive: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Files, want) {
+		t.Errorf("restored manifest files = %v", restored.Files)
+	}
+	got, err := os.ReadFile(filepath.Join(dest, "narrator", "state.json"))
+	if err != nil || string(got) != `{"offset": 2}` {
+		t.Errorf("narrator state = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, events.EventsFile)); !os.IsNotExist(err) {
+		t.Error("events log restored without --events")
+	}
+
+	// With the logs, for a migration
+	buf.Reset()
+	if _, err := WriteArchive(townRoot, &buf, ArchiveOptions{Events: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreArchive(dest, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if pos, _ := events.Position(dest); !reflect.DeepEqual(pos, m.Events) {
+		t.Errorf("restored events position = %+v, want %+v", pos, m.Events)
+	}
+
+	if _, err := RestoreArchive(dest, bytes.NewReader([]byte("not an archive"))); err == nil {
+		t.Error("restored garbage")
+	}
+}
+
+func TestArchive_EventsKey(t *testing.T) {
+	townRoot := setupTown(t, "gastown")
+	key := filepath.Join(t.TempDir(), "town.key")
+	if err := os.WriteFile(key, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"),
+		[]byte(`{"type":"town-settings","version":1,"events":{"encrypt":true,"key_file":"`+key+`"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	m, err := WriteArchive(townRoot, &buf, ArchiveOptions{Events: true})
+	if err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+	for _, f := range m.Files {
+		if f == events.KeyFile {
+			t.Fatal("--events archived the events key")
+		}
+	}
+
+	buf.Reset()
+	if _, err := WriteArchive(townRoot, &buf, ArchiveOptions{Events: true, IncludeKey: true}); err != nil {
+		t.Fatalf("WriteArchive: %v", err)
+	}
+	dest := t.TempDir()
+	if _, err := RestoreArchive(dest, &buf); err != nil {
+		t.Fatalf("RestoreArchive: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dest, events.KeyFile))
+	if err != nil || string(got) != "secret\n" {
+		t.Errorf("restored key = %q, %v; want the configured key in the town root", got, err)
+	}
+
+	if _, err := WriteArchive(townRoot, &buf, ArchiveOptions{IncludeKey: true}); err == nil {
+		t.Error("archived the key without the events logs")
+	}
+}
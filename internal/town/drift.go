@@ -0,0 +1,162 @@
+package town
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Drift kinds.
+const (
+	// DriftUndeclaredSession: a Gas Town session is running that no declared
+	// agent owns.
+	DriftUndeclaredSession = "undeclared-session"
+
+	// DriftRigNoSessions: an active declared rig has no sessions running.
+	DriftRigNoSessions = "rig-no-sessions"
+
+	// DriftMissingWorkDir: a declared agent's working directory is missing.
+	DriftMissingWorkDir = "missing-workdir"
+
+	// DriftOrphanState: a rig state file exists for a rig that is not
+	// registered.
+	DriftOrphanState = "orphan-state"
+
+	// Changes in the declared topology since the saved snapshot.
+	DriftRigAdded     = "rig-added"
+	DriftRigRemoved   = "rig-removed"
+	DriftAgentAdded   = "agent-added"
+	DriftAgentRemoved = "agent-removed"
+)
+
+// Drift is one difference between the declared town and reality, or
+// between the saved snapshot and the current declaration.
+type Drift struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Detail  string `json:"detail"`
+}
+
+// CompareLive reports where the running town differs from declared:
+// undeclared sessions, active rigs with nothing running, missing working
+// directories and state files for unregistered rigs. sessions is the list
+// of live tmux session names.
+func CompareLive(townRoot string, declared *Snapshot, sessions []string) []Drift {
+	var drifts []Drift
+
+	owned := make(map[string]bool, len(declared.Agents))
+	for _, agent := range declared.Agents {
+		owned[agent.Session] = true
+	}
+
+	live := make(map[string]bool, len(sessions))
+	sortedSessions := append([]string(nil), sessions...)
+	sort.Strings(sortedSessions)
+	for _, name := range sortedSessions {
+		live[name] = true
+		if owned[name] || !isGasTownSession(name) {
+			continue
+		}
+		detail := "no declared agent owns this session"
+		if id, err := session.ParseSessionName(name); err == nil && id.Rig != "" && !declared.HasRig(id.Rig) {
+			detail = fmt.Sprintf("looks like %s for rig %q, which is not registered", id.Role, id.Rig)
+		}
+		drifts = append(drifts, Drift{Kind: DriftUndeclaredSession, Subject: name, Detail: detail})
+	}
+
+	for _, rig := range declared.Rigs {
+		if rig.Status != "" {
+			continue // parked and docked rigs are expected to be quiet
+		}
+		running := 0
+		for _, agent := range declared.Agents {
+			if agent.Rig == rig.Name && live[agent.Session] {
+				running++
+			}
+		}
+		if running == 0 {
+			drifts = append(drifts, Drift{Kind: DriftRigNoSessions, Subject: rig.Name,
+				Detail: "rig is registered and active but none of its agents are running"})
+		}
+	}
+
+	for _, agent := range declared.Agents {
+		if _, err := os.Stat(filepath.Join(townRoot, agent.WorkDir)); os.IsNotExist(err) {
+			drifts = append(drifts, Drift{Kind: DriftMissingWorkDir, Subject: agent.Address,
+				Detail: fmt.Sprintf("%s does not exist", agent.WorkDir)})
+		}
+	}
+
+	stateFiles, _ := filepath.Glob(filepath.Join(townRoot, wisp.WispConfigDir, wisp.ConfigSubdir, "*.json"))
+	sort.Strings(stateFiles)
+	for _, path := range stateFiles {
+		rigName := strings.TrimSuffix(filepath.Base(path), ".json")
+		if !declared.HasRig(rigName) {
+			rel, _ := filepath.Rel(townRoot, path)
+			drifts = append(drifts, Drift{Kind: DriftOrphanState, Subject: rigName,
+				Detail: fmt.Sprintf("%s belongs to a rig that is not registered", filepath.ToSlash(rel))})
+		}
+	}
+
+	return drifts
+}
+
+// CompareSnapshots reports rigs and agents added or removed between a saved
+// snapshot and the current declaration.
+func CompareSnapshots(saved, current *Snapshot) []Drift {
+	var drifts []Drift
+
+	for _, rig := range current.Rigs {
+		if !saved.HasRig(rig.Name) {
+			drifts = append(drifts, Drift{Kind: DriftRigAdded, Subject: rig.Name, Detail: "registered since the snapshot"})
+		}
+	}
+	for _, rig := range saved.Rigs {
+		if !current.HasRig(rig.Name) {
+			drifts = append(drifts, Drift{Kind: DriftRigRemoved, Subject: rig.Name, Detail: "no longer registered"})
+		}
+	}
+
+	savedAgents := saved.agentSet()
+	currentAgents := current.agentSet()
+	for _, agent := range current.Agents {
+		if !savedAgents[agent.Address] {
+			drifts = append(drifts, Drift{Kind: DriftAgentAdded, Subject: agent.Address, Detail: "declared since the snapshot"})
+		}
+	}
+	for _, agent := range saved.Agents {
+		if !currentAgents[agent.Address] {
+			drifts = append(drifts, Drift{Kind: DriftAgentRemoved, Subject: agent.Address, Detail: "no longer declared"})
+		}
+	}
+
+	return drifts
+}
+
+// HasRig reports whether the snapshot includes the named rig.
+func (s *Snapshot) HasRig(name string) bool {
+	for _, rig := range s.Rigs {
+		if rig.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Snapshot) agentSet() map[string]bool {
+	set := make(map[string]bool, len(s.Agents))
+	for _, agent := range s.Agents {
+		set[agent.Address] = true
+	}
+	return set
+}
+
+// isGasTownSession reports whether a tmux session name uses a Gas Town prefix.
+func isGasTownSession(name string) bool {
+	return strings.HasPrefix(name, session.Prefix) || strings.HasPrefix(name, session.HQPrefix)
+}
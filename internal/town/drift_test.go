@@ -0,0 +1,166 @@
+package town
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+func setupTown(t *testing.T, rigs ...string) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "deacon"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigsJSON := `{"version": 1, "rigs": {`
+	for i, rig := range rigs {
+		if i > 0 {
+			rigsJSON += ","
+		}
+		rigsJSON += `"` + rig + `": {"git_url": "https://example.com/` + rig + `.git"}`
+		for _, dir := range []string{"witness", "refinery/rig"} {
+			if err := os.MkdirAll(filepath.Join(townRoot, rig, dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	rigsJSON += `}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func driftKinds(drifts []Drift) map[string]string {
+	kinds := make(map[string]string)
+	for _, d := range drifts {
+		kinds[d.Subject] = d.Kind
+	}
+	return kinds
+}
+
+func TestDeclared(t *testing.T) {
+	townRoot := setupTown(t, "gastown")
+	for _, dir := range []string{"gastown/crew/max", "gastown/polecats/Toast"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := Declared(townRoot)
+	if err != nil {
+		t.Fatalf("Declared: %v", err)
+	}
+	if len(snap.Rigs) != 1 || snap.Rigs[0].GitURL != "https://example.com/gastown.git" {
+		t.Errorf("Rigs = %+v", snap.Rigs)
+	}
+
+	want := map[string]string{
+		"mayor/":           session.MayorSessionName(),
+		"deacon/":          session.DeaconSessionName(),
+		"gastown/witness":  session.WitnessSessionName("gastown"),
+		"gastown/refinery": session.RefinerySessionName("gastown"),
+		"gastown/crew/max": session.CrewSessionName("gastown", "max"),
+		"gastown/Toast":    session.PolecatSessionName("gastown", "Toast"),
+	}
+	if len(snap.Agents) != len(want) {
+		t.Fatalf("Agents = %+v, want %d", snap.Agents, len(want))
+	}
+	for _, agent := range snap.Agents {
+		if want[agent.Address] != agent.Session {
+			t.Errorf("%s session = %q, want %q", agent.Address, agent.Session, want[agent.Address])
+		}
+	}
+}
+
+func TestCompareLive(t *testing.T) {
+	townRoot := setupTown(t, "gastown", "beads", "parked")
+	if err := wisp.NewConfig(townRoot, "parked").Set("status", "parked"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wisp.NewConfig(townRoot, "oldrig").Set("status", "docked"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(townRoot, "beads", "witness")); err != nil {
+		t.Fatal(err)
+	}
+
+	declared, err := Declared(townRoot)
+	if err != nil {
+		t.Fatalf("Declared: %v", err)
+	}
+	sessions := []string{
+		session.MayorSessionName(),
+		session.WitnessSessionName("gastown"),
+		session.PolecatSessionName("ghost", "Nux"),
+		"scratch", // not a Gas Town session
+	}
+
+	kinds := driftKinds(CompareLive(townRoot, declared, sessions))
+	want := map[string]string{
+		session.PolecatSessionName("ghost", "Nux"): DriftUndeclaredSession,
+		"beads":         DriftRigNoSessions,
+		"beads/witness": DriftMissingWorkDir,
+		"oldrig":        DriftOrphanState,
+	}
+	for subject, kind := range want {
+		if kinds[subject] != kind {
+			t.Errorf("%s drift = %q, want %q", subject, kinds[subject], kind)
+		}
+	}
+	if len(kinds) != len(want) {
+		t.Errorf("drift = %v, want only %v", kinds, want)
+	}
+}
+
+func TestCompareSnapshots(t *testing.T) {
+	townRoot := setupTown(t, "gastown")
+	saved, err := Declared(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSnapshot(townRoot, saved); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	loaded, err := LoadSnapshot(townRoot)
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadSnapshot = %v, %v", loaded, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "crew", "max"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	current, err := Declared(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Rigs = append(current.Rigs, RigSnapshot{Name: "beads"})
+	loaded.Rigs = append(loaded.Rigs, RigSnapshot{Name: "legacy"})
+
+	kinds := driftKinds(CompareSnapshots(loaded, current))
+	want := map[string]string{
+		"beads":            DriftRigAdded,
+		"legacy":           DriftRigRemoved,
+		"gastown/crew/max": DriftAgentAdded,
+	}
+	for subject, kind := range want {
+		if kinds[subject] != kind {
+			t.Errorf("%s drift = %q, want %q", subject, kinds[subject], kind)
+		}
+	}
+	if len(kinds) != len(want) {
+		t.Errorf("drift = %v, want only %v", kinds, want)
+	}
+}
+
+func TestLoadSnapshot_Missing(t *testing.T) {
+	snap, err := LoadSnapshot(t.TempDir())
+	if err != nil || snap != nil {
+		t.Errorf("LoadSnapshot = %v, %v, want nil, nil", snap, err)
+	}
+}
@@ -0,0 +1,159 @@
+// Package town captures the declared topology of a Gas Town HQ and compares
+// it with what is actually running, and archives and restores its state.
+package town
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// SnapshotVersion is the current snapshot schema version.
+const SnapshotVersion = 1
+
+// Snapshot is the declared topology of a town: the rigs registered in
+// mayor/rigs.json and the agents their directories declare.
+type Snapshot struct {
+	Type    string          `json:"type"` // "town-snapshot"
+	Version int             `json:"version"`
+	TakenAt time.Time       `json:"taken_at"`
+	Rigs    []RigSnapshot   `json:"rigs"`
+	Agents  []AgentSnapshot `json:"agents"`
+}
+
+// RigSnapshot is one registered rig.
+type RigSnapshot struct {
+	Name   string `json:"name"`
+	GitURL string `json:"git_url,omitempty"`
+
+	// Status is the rig's wisp status ("parked", "docked"), or "" if active.
+	Status string `json:"status,omitempty"`
+}
+
+// AgentSnapshot is one declared agent.
+type AgentSnapshot struct {
+	Address string       `json:"address"` // mail address, e.g. "gastown/Toast"
+	Role    session.Role `json:"role"`
+	Rig     string       `json:"rig,omitempty"`
+	Name    string       `json:"name,omitempty"`
+	Session string       `json:"session"`
+	WorkDir string       `json:"work_dir"` // relative to the town root
+}
+
+// SnapshotFile returns the path of the saved snapshot.
+func SnapshotFile(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "snapshot.json")
+}
+
+// Declared builds a snapshot of the town's declared topology from
+// mayor/rigs.json and the rig directories on disk.
+func Declared(townRoot string) (*Snapshot, error) {
+	snap := &Snapshot{
+		Type:    "town-snapshot",
+		Version: SnapshotVersion,
+		TakenAt: time.Now().UTC(),
+		Rigs:    []RigSnapshot{},
+		Agents: []AgentSnapshot{
+			{Address: "mayor/", Role: session.RoleMayor, Session: session.MayorSessionName(), WorkDir: "mayor"},
+			{Address: "deacon/", Role: session.RoleDeacon, Session: session.DeaconSessionName(), WorkDir: "deacon"},
+		},
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return snap, nil
+		}
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		snap.Rigs = append(snap.Rigs, RigSnapshot{
+			Name:   name,
+			GitURL: rigsConfig.Rigs[name].GitURL,
+			Status: wisp.NewConfig(townRoot, name).GetString("status"),
+		})
+		snap.Agents = append(snap.Agents, rigAgents(townRoot, name)...)
+	}
+	return snap, nil
+}
+
+// rigAgents lists the agents a rig declares: its witness and refinery, and
+// one agent per crew/ and polecats/ directory.
+func rigAgents(townRoot, rigName string) []AgentSnapshot {
+	agents := []AgentSnapshot{
+		{Address: rigName + "/witness", Role: session.RoleWitness, Rig: rigName,
+			Session: session.WitnessSessionName(rigName), WorkDir: filepath.Join(rigName, "witness")},
+		{Address: rigName + "/refinery", Role: session.RoleRefinery, Rig: rigName,
+			Session: session.RefinerySessionName(rigName), WorkDir: filepath.Join(rigName, "refinery", "rig")},
+	}
+	for _, name := range subdirNames(filepath.Join(townRoot, rigName, "crew")) {
+		agents = append(agents, AgentSnapshot{
+			Address: rigName + "/crew/" + name, Role: session.RoleCrew, Rig: rigName, Name: name,
+			Session: session.CrewSessionName(rigName, name), WorkDir: filepath.Join(rigName, "crew", name),
+		})
+	}
+	for _, name := range subdirNames(filepath.Join(townRoot, rigName, "polecats")) {
+		agents = append(agents, AgentSnapshot{
+			Address: rigName + "/" + name, Role: session.RolePolecat, Rig: rigName, Name: name,
+			Session: session.PolecatSessionName(rigName, name), WorkDir: filepath.Join(rigName, "polecats", name),
+		})
+	}
+	return agents
+}
+
+// SaveSnapshot writes snap to the town's snapshot file.
+func SaveSnapshot(townRoot string, snap *Snapshot) error {
+	path := SnapshotFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, snap)
+}
+
+// LoadSnapshot reads the saved snapshot. Returns nil, nil if none was saved.
+func LoadSnapshot(townRoot string) (*Snapshot, error) {
+	data, err := os.ReadFile(SnapshotFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parsing snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// subdirNames returns the sorted names of dir's visible subdirectories.
+func subdirNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
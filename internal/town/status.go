@@ -0,0 +1,251 @@
+package town
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/keepalive"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/narrator"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// StatusVersion is the current status schema version. It is bumped when a
+// field is removed or changes meaning; new fields may appear at any time.
+const StatusVersion = 1
+
+// Overview is a machine-readable picture of a town at one moment, gathered
+// from its state files, for dashboards and scripts.
+type Overview struct {
+	Type        string         `json:"type"` // "town-status"
+	Version     int            `json:"version"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Name        string         `json:"name"`
+	Root        string         `json:"root"`
+	Daemon      DaemonStatus   `json:"daemon"`
+	Deacon      DeaconStatus   `json:"deacon"`
+	Narrator    NarratorStatus `json:"narrator"`
+	Agents      []AgentStatus  `json:"agents"`
+	Mail        map[string]int `json:"unread_mail,omitempty"` // address -> unread count
+	Events      EventsStatus   `json:"events"`
+	Rigs        []RigSnapshot  `json:"rigs"`
+}
+
+// DaemonStatus is the daemon's process and heartbeat state.
+type DaemonStatus struct {
+	Running        bool       `json:"running"`
+	PID            int        `json:"pid,omitempty"`
+	LastHeartbeat  *time.Time `json:"last_heartbeat,omitempty"`
+	HeartbeatCount int64      `json:"heartbeat_count"`
+}
+
+// DeaconStatus is the deacon's pause file and heartbeat.
+type DeaconStatus struct {
+	Paused        bool       `json:"paused"`
+	PauseReason   string     `json:"pause_reason,omitempty"`
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
+	Cycle         int64      `json:"cycle,omitempty"`
+	LastAction    string     `json:"last_action,omitempty"`
+}
+
+// NarratorStatus is the narrator's run state and story position.
+type NarratorStatus struct {
+	State           string     `json:"state"`   // running or paused
+	Session         bool       `json:"session"` // its session (session.NarratorSessionName) exists
+	PausedAt        *time.Time `json:"paused_at,omitempty"`
+	NarratedThrough *time.Time `json:"narrated_through,omitempty"`
+	Chapter         int        `json:"chapter"` // last chapter written
+}
+
+// AgentStatus is one declared agent: whether its session is up and when
+// its workspace last showed signs of life.
+type AgentStatus struct {
+	AgentSnapshot
+	Running bool `json:"running"` // session exists
+
+	// Keepalive is the workspace's last gt command; KeepaliveAge is its
+	// age in whole seconds.
+	Keepalive    *time.Time `json:"keepalive,omitempty"`
+	KeepaliveAge int64      `json:"keepalive_age_seconds,omitempty"`
+	LastCommand  string     `json:"last_command,omitempty"`
+
+	// Restarts and Halted come from the daemon's supervisor, for the mayor
+	// and deacon when under a restart policy.
+	Restarts int  `json:"restarts,omitempty"`
+	Halted   bool `json:"halted,omitempty"`
+}
+
+// EventsStatus is when the town, and each rig, last logged an event.
+type EventsStatus struct {
+	Last     *time.Time           `json:"last,omitempty"`
+	LastType string               `json:"last_type,omitempty"`
+	Rigs     map[string]time.Time `json:"rigs,omitempty"`
+}
+
+// StatusOptions controls what Status gathers.
+type StatusOptions struct {
+	// Sessions are the live session names; an agent is running when its
+	// session is among them.
+	Sessions []string
+
+	// Mail counts each agent's unread mail, which queries every mailbox.
+	Mail bool
+}
+
+// Status gathers a town's overview from its declared topology and state
+// files. Unreadable state is left at its zero value rather than failing
+// the whole overview; only an unreadable topology is an error.
+func Status(townRoot string, opts StatusOptions) (*Overview, error) {
+	snap, err := Declared(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	o := &Overview{
+		Type:        "town-status",
+		Version:     StatusVersion,
+		GeneratedAt: now,
+		Name:        filepath.Base(townRoot),
+		Root:        townRoot,
+		Rigs:        snap.Rigs,
+		Agents:      []AgentStatus{},
+	}
+	if cfg, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil && cfg.Name != "" {
+		o.Name = cfg.Name
+	}
+
+	o.Daemon = daemonStatus(townRoot)
+	o.Deacon = deaconStatus(townRoot)
+	o.Narrator = narratorStatus(townRoot)
+
+	live := make(map[string]bool, len(opts.Sessions))
+	for _, s := range opts.Sessions {
+		live[s] = true
+	}
+	o.Narrator.Session = live[session.NarratorSessionName()]
+	supervised, _ := daemon.LoadSupervised(townRoot)
+	for _, a := range snap.Agents {
+		status := AgentStatus{AgentSnapshot: a, Running: live[a.Session]}
+		if ka := keepalive.Read(filepath.Join(townRoot, a.WorkDir)); ka != nil {
+			ts := ka.Timestamp
+			status.Keepalive = &ts
+			status.KeepaliveAge = int64(now.Sub(ts).Seconds())
+			status.LastCommand = ka.LastCommand
+		}
+		if rec, ok := supervised[string(a.Role)]; ok && a.Rig == "" {
+			status.Restarts = rec.Restarts
+			status.Halted = rec.Halted
+		}
+		o.Agents = append(o.Agents, status)
+	}
+
+	if opts.Mail {
+		o.Mail = unreadMail(townRoot, snap.Agents)
+	}
+	o.Events = eventsStatus(townRoot)
+	return o, nil
+}
+
+func daemonStatus(townRoot string) DaemonStatus {
+	var s DaemonStatus
+	if running, pid, err := daemon.IsRunning(townRoot); err == nil && running {
+		s.Running = true
+		s.PID = pid
+	}
+	if state, err := daemon.LoadState(townRoot); err == nil {
+		if !state.LastHeartbeat.IsZero() {
+			last := state.LastHeartbeat
+			s.LastHeartbeat = &last
+		}
+		s.HeartbeatCount = state.HeartbeatCount
+	}
+	return s
+}
+
+func deaconStatus(townRoot string) DeaconStatus {
+	var s DeaconStatus
+	if paused, state, err := deacon.IsPaused(townRoot); err == nil && paused {
+		s.Paused = true
+		if state != nil {
+			s.PauseReason = state.Reason
+		}
+	}
+	if hb := deacon.ReadHeartbeat(townRoot); hb != nil {
+		ts := hb.Timestamp
+		s.LastHeartbeat = &ts
+		s.Cycle = hb.Cycle
+		s.LastAction = hb.LastAction
+	}
+	return s
+}
+
+func narratorStatus(townRoot string) NarratorStatus {
+	s := NarratorStatus{State: "running"}
+	if state, err := narrator.LoadState(townRoot); err == nil {
+		s.State = string(state.State)
+		if state.Paused() {
+			at := state.PausedAt
+			s.PausedAt = &at
+		}
+		if !state.NarratedThrough.IsZero() {
+			through := state.NarratedThrough
+			s.NarratedThrough = &through
+		}
+	}
+	if story, err := narrator.LoadStory(townRoot); err == nil {
+		s.Chapter = story.Chapter
+	}
+	return s
+}
+
+// unreadMail counts unread messages for the overseer and every agent.
+// Mailboxes that can't be read are left out.
+func unreadMail(townRoot string, agents []AgentSnapshot) map[string]int {
+	router := mail.NewRouter(townRoot)
+	counts := make(map[string]int)
+	addresses := []string{"overseer"}
+	for _, a := range agents {
+		addresses = append(addresses, a.Address)
+	}
+	for _, address := range addresses {
+		mailbox, err := router.GetMailbox(address)
+		if err != nil {
+			continue
+		}
+		if _, unread, err := mailbox.Count(); err == nil {
+			counts[address] = unread
+		}
+	}
+	return counts
+}
+
+func eventsStatus(townRoot string) EventsStatus {
+	var s EventsStatus
+	records, err := events.ReadAll(townRoot)
+	if err != nil {
+		return s
+	}
+	for _, r := range records {
+		ts, err := time.Parse(time.RFC3339, r.Timestamp)
+		if err != nil {
+			continue
+		}
+		if s.Last == nil || ts.After(*s.Last) {
+			last := ts
+			s.Last = &last
+			s.LastType = r.Type
+		}
+		if rig := events.EventRig(r.Event); rig != "" && ts.After(s.Rigs[rig]) {
+			if s.Rigs == nil {
+				s.Rigs = make(map[string]time.Time)
+			}
+			s.Rigs[rig] = ts
+		}
+	}
+	return s
+}
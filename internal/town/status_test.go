@@ -0,0 +1,78 @@
+package town
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/narrator"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestStatus(t *testing.T) {
+	townRoot := setupTown(t, "gastown")
+
+	touched := time.Now().Add(-90 * time.Second).UTC().Truncate(time.Second)
+	runtimeDir := filepath.Join(townRoot, "gastown", "witness", ".runtime")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	keepalive := `{"last_command":"gt patrol","timestamp":"` + touched.Format(time.RFC3339) + `"}`
+	if err := os.WriteFile(filepath.Join(runtimeDir, "keepalive.json"), []byte(keepalive), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := deacon.Pause(townRoot, "maintenance", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if err := narrator.Pause(townRoot, "", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.SaveSupervised(townRoot, map[string]daemon.SupervisedAgent{"mayor": {Restarts: 2, Halted: true}}); err != nil {
+		t.Fatal(err)
+	}
+	logged := time.Now().UTC().Truncate(time.Second)
+	if err := events.Append(townRoot, events.Event{
+		Timestamp: logged.Format(time.RFC3339), Source: "gt", Type: events.TypeMerged,
+		Actor: "gastown/refinery", Visibility: events.VisibilityFeed,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := Status(townRoot, StatusOptions{Sessions: []string{session.WitnessSessionName("gastown"), session.NarratorSessionName()}})
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if o.Type != "town-status" || o.Version != StatusVersion || len(o.Rigs) != 1 {
+		t.Errorf("overview header = %+v", o)
+	}
+	agents := make(map[string]AgentStatus)
+	for _, a := range o.Agents {
+		agents[a.Address] = a
+	}
+	witness := agents["gastown/witness"]
+	if !witness.Running || witness.LastCommand != "gt patrol" || witness.KeepaliveAge < 90 || !witness.Keepalive.Equal(touched) {
+		t.Errorf("witness = %+v, want running with a 90s-old keepalive", witness)
+	}
+	if refinery := agents["gastown/refinery"]; refinery.Running || refinery.Keepalive != nil {
+		t.Errorf("refinery = %+v, want not running, no keepalive", refinery)
+	}
+	if mayor := agents["mayor/"]; mayor.Restarts != 2 || !mayor.Halted {
+		t.Errorf("mayor = %+v, want supervisor record", mayor)
+	}
+	if !o.Deacon.Paused || o.Deacon.PauseReason != "maintenance" {
+		t.Errorf("deacon = %+v, want paused", o.Deacon)
+	}
+	if o.Narrator.State != "paused" || o.Narrator.PausedAt == nil || !o.Narrator.Session {
+		t.Errorf("narrator = %+v, want paused with its session up", o.Narrator)
+	}
+	if o.Events.Last == nil || !o.Events.Last.Equal(logged) || o.Events.LastType != events.TypeMerged || !o.Events.Rigs["gastown"].Equal(logged) {
+		t.Errorf("events = %+v, want the merge", o.Events)
+	}
+	if o.Mail != nil {
+		t.Errorf("mail = %v, want none without StatusOptions.Mail", o.Mail)
+	}
+}
@@ -0,0 +1,237 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Recordings keep a session's raw output with timing, so it can be replayed
+// at the pace it happened. Each recording is a JSONL file under
+// .runtime/recordings/<agent>/<start time>.rec: a header line describing the
+// session, then one frame per chunk of output.
+
+// RecordingsDir returns the town's recordings directory.
+func RecordingsDir(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "recordings")
+}
+
+// Recording describes a recorded session. It is the first line of the file.
+type Recording struct {
+	Agent     string    `json:"agent"`
+	Session   string    `json:"session"`
+	StartedAt time.Time `json:"started_at"`
+	Path      string    `json:"-"`
+}
+
+// Frame is a chunk of output, At seconds after the recording started.
+type Frame struct {
+	At   float64 `json:"t"`
+	Data string  `json:"d"`
+}
+
+// RecordWithReplay is Record plus a timed recording for `gt session replay`.
+// tmux allows one pipe per pane, so the pane's output is teed into the
+// transcript log and into the running gt binary's `gt session record`. If
+// that pipe can't be set up, the plain transcript keeps recording.
+func RecordWithReplay(t *tmux.Tmux, townRoot, session, agent string) error {
+	if err := Record(t, townRoot, session, agent); err != nil {
+		return err
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt binary: %w", err)
+	}
+	rec, err := NewRecording(townRoot, agent, session, time.Now())
+	if err != nil {
+		return err
+	}
+	livePath := filepath.Join(liveDir(townRoot), session+".log")
+	return t.PipePane(session, fmt.Sprintf("tee -a %s | %s session record --out %s",
+		shellQuote(livePath), shellQuote(gtPath), shellQuote(rec.Path)))
+}
+
+// NewRecording creates a recording file holding just its header.
+func NewRecording(townRoot, agent, session string, started time.Time) (*Recording, error) {
+	dir := filepath.Join(RecordingsDir(townRoot), filepath.FromSlash(agent))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating recordings dir: %w", err)
+	}
+	rec := &Recording{
+		Agent:     agent,
+		Session:   session,
+		StartedAt: started.UTC(),
+		Path:      filepath.Join(dir, started.UTC().Format("20060102T150405")+".rec"),
+	}
+	header, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(rec.Path, append(header, '\n'), 0644); err != nil { //nolint:gosec // G306: recordings are non-sensitive
+		return nil, fmt.Errorf("writing recording: %w", err)
+	}
+	return rec, nil
+}
+
+// WriteFrames appends a frame to the recording at path for each chunk read
+// from r until EOF, timed from the recording's start. Multi-byte characters
+// split across reads are kept whole.
+func WriteFrames(r io.Reader, path string) error {
+	rec, err := readHeader(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: recordings are non-sensitive
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	writeFrame := func(data []byte) error {
+		line, err := json.Marshal(Frame{At: time.Since(rec.StartedAt).Seconds(), Data: string(data)})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(append(line, '\n'))
+		return err
+	}
+
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			pending = append(pending, buf[:n]...)
+			if cut := completeRunes(pending); cut > 0 {
+				if err := writeFrame(pending[:cut]); err != nil {
+					return err
+				}
+				pending = append(pending[:0], pending[cut:]...)
+			}
+		}
+		if readErr == io.EOF {
+			if len(pending) > 0 {
+				return writeFrame(pending)
+			}
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// completeRunes returns the length of b without a trailing incomplete UTF-8
+// sequence.
+func completeRunes(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
+
+// Recordings returns an agent's recordings, oldest first.
+func Recordings(townRoot, agent string) ([]Recording, error) {
+	paths, err := filepath.Glob(filepath.Join(RecordingsDir(townRoot), filepath.FromSlash(agent), "*.rec"))
+	if err != nil {
+		return nil, err
+	}
+	var recs []Recording
+	for _, path := range paths {
+		rec, err := readHeader(path)
+		if err != nil {
+			continue
+		}
+		recs = append(recs, *rec)
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].StartedAt.Before(recs[j].StartedAt) })
+	return recs, nil
+}
+
+// readHeader reads a recording's header line.
+func readHeader(path string) (*Recording, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	var rec Recording
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, fmt.Errorf("parsing recording %s: %w", path, err)
+	}
+	rec.Path = path
+	return &rec, nil
+}
+
+// sleep is replaced in tests.
+var sleep = time.Sleep
+
+// Replay writes a recording's frames to w, pausing between them as they
+// were recorded divided by speed. Pauses are capped at maxIdle (0 for no
+// cap) so long waits for an API response don't stall playback.
+func Replay(w io.Writer, path string, speed float64, maxIdle time.Duration) error {
+	if speed <= 0 {
+		return fmt.Errorf("invalid speed %v", speed)
+	}
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	if _, err := reader.ReadBytes('\n'); err != nil { // header
+		return fmt.Errorf("reading recording: %w", err)
+	}
+	var last float64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			var frame Frame
+			if jerr := json.Unmarshal(line, &frame); jerr == nil {
+				pause := time.Duration((frame.At - last) / speed * float64(time.Second))
+				if maxIdle > 0 && pause > maxIdle {
+					pause = maxIdle
+				}
+				if pause > 0 {
+					sleep(pause)
+				}
+				last = frame.At
+				if _, werr := io.WriteString(w, frame.Data); werr != nil {
+					return werr
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
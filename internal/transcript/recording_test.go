@@ -0,0 +1,93 @@
+package transcript
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	townRoot := t.TempDir()
+	rec, err := NewRecording(townRoot, "gastown/witness", "gt-gastown-witness", time.Now())
+	if err != nil {
+		t.Fatalf("NewRecording: %v", err)
+	}
+
+	// Reading a byte at a time splits "é"; it must not be mangled
+	output := "patrol start\ncafé done\n"
+	if err := WriteFrames(iotest.OneByteReader(strings.NewReader(output)), rec.Path); err != nil {
+		t.Fatalf("WriteFrames: %v", err)
+	}
+
+	recs, err := Recordings(townRoot, "gastown/witness")
+	if err != nil || len(recs) != 1 {
+		t.Fatalf("Recordings = %v, %v; want 1 recording", recs, err)
+	}
+	if recs[0].Session != "gt-gastown-witness" || recs[0].Path != rec.Path {
+		t.Errorf("Recordings()[0] = %+v", recs[0])
+	}
+
+	var out bytes.Buffer
+	if err := Replay(&out, rec.Path, 1, 0); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if got := out.String(); got != output {
+		t.Errorf("Replay output = %q, want %q", got, output)
+	}
+}
+
+func TestCompleteRunes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"abc", 3},
+		{"caf\xc3", 3},
+		{"café", 5},
+		{"\xe2\x82", 0},
+	}
+	for _, tt := range tests {
+		if got := completeRunes([]byte(tt.in)); got != tt.want {
+			t.Errorf("completeRunes(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReplay_ScalesAndCapsPauses(t *testing.T) {
+	townRoot := t.TempDir()
+	rec, err := NewRecording(townRoot, "mayor", "hq-mayor", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := `{"t":1,"d":"a"}` + "\n" + `{"t":3,"d":"b"}` + "\n" + `{"t":603,"d":"c"}` + "\n"
+	f, err := os.OpenFile(rec.Path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(frames)
+	_ = f.Close()
+
+	var pauses []time.Duration
+	sleep = func(d time.Duration) { pauses = append(pauses, d) }
+	defer func() { sleep = time.Sleep }()
+
+	var out bytes.Buffer
+	if err := Replay(&out, rec.Path, 2, 5*time.Second); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if out.String() != "abc" {
+		t.Errorf("output = %q, want %q", out.String(), "abc")
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second, 5 * time.Second}
+	if len(pauses) != len(want) {
+		t.Fatalf("pauses = %v, want %v", pauses, want)
+	}
+	for i := range want {
+		if pauses[i] != want[i] {
+			t.Errorf("pause %d = %v, want %v", i, pauses[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Package transcript records agent sessions' terminal output and archives it
+// when the session ends, so post-mortems and the narrator can search what an
+// agent actually said and did.
+//
+// While a session runs, tmux pipe-pane appends its output to
+// logs/transcripts/live/<session>.log. Once the session is gone, ArchiveEnded
+// strips terminal escapes, moves the text to logs/transcripts/archive/, and
+// appends an entry to logs/transcripts/index.jsonl.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Dir returns the town's transcript directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "transcripts")
+}
+
+func liveDir(townRoot string) string    { return filepath.Join(Dir(townRoot), "live") }
+func archiveDir(townRoot string) string { return filepath.Join(Dir(townRoot), "archive") }
+func indexPath(townRoot string) string  { return filepath.Join(Dir(townRoot), "index.jsonl") }
+
+// Entry describes an archived transcript.
+type Entry struct {
+	ID        string    `json:"id"`      // <session>-<start time>
+	Agent     string    `json:"agent"`   // e.g. gastown/Toast, gastown/witness, mayor
+	Session   string    `json:"session"` // tmux session name
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Lines     int       `json:"lines"`
+}
+
+// Path returns the archived transcript's text file.
+func (e Entry) Path(townRoot string) string {
+	return filepath.Join(archiveDir(townRoot), e.ID+".log")
+}
+
+// liveMeta is written beside a live recording so the archive knows whose
+// session it was.
+type liveMeta struct {
+	Agent     string    `json:"agent"`
+	Session   string    `json:"session"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Record starts recording a session's output. Any unarchived recording left
+// over from an earlier session with the same name is archived first.
+func Record(t *tmux.Tmux, townRoot, session, agent string) error {
+	if err := os.MkdirAll(liveDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating transcript dir: %w", err)
+	}
+	if _, err := archive(townRoot, session, time.Now()); err != nil {
+		return err
+	}
+	meta := liveMeta{Agent: agent, Session: session, StartedAt: time.Now().UTC()}
+	if err := util.AtomicWriteJSON(filepath.Join(liveDir(townRoot), session+".json"), meta); err != nil {
+		return fmt.Errorf("writing transcript metadata: %w", err)
+	}
+	return t.PipePaneToFile(session, filepath.Join(liveDir(townRoot), session+".log"))
+}
+
+// ArchiveEnded archives the live recordings of sessions for which running
+// reports false. Returns the entries archived.
+func ArchiveEnded(townRoot string, running func(session string) bool) ([]Entry, error) {
+	metas, err := filepath.Glob(filepath.Join(liveDir(townRoot), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var archived []Entry
+	for _, path := range metas {
+		session := strings.TrimSuffix(filepath.Base(path), ".json")
+		if running(session) {
+			continue
+		}
+		entry, err := archive(townRoot, session, time.Now())
+		if err != nil {
+			return archived, err
+		}
+		if entry != nil {
+			archived = append(archived, *entry)
+		}
+	}
+	return archived, nil
+}
+
+// ansiRe matches terminal escape sequences (CSI, OSC, and two-byte escapes).
+var ansiRe = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// Clean strips terminal escapes and carriage returns from recorded output
+// and drops blank lines.
+func Clean(raw string) []string {
+	text := ansiRe.ReplaceAllString(raw, "")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		// A carriage return redraws the line; keep what was drawn last
+		if i := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		line = strings.TrimRight(line, "\r \t")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// archive moves a session's live recording into the archive. Returns nil if
+// the session has no live recording.
+func archive(townRoot, session string, now time.Time) (*Entry, error) {
+	metaPath := filepath.Join(liveDir(townRoot), session+".json")
+	logPath := filepath.Join(liveDir(townRoot), session+".log")
+
+	data, err := os.ReadFile(metaPath) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var meta liveMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parsing transcript metadata %s: %w", session, err)
+	}
+
+	raw, err := os.ReadFile(logPath) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	lines := Clean(string(raw))
+
+	entry := &Entry{
+		ID:        session + "-" + meta.StartedAt.Format("20060102T150405"),
+		Agent:     meta.Agent,
+		Session:   session,
+		StartedAt: meta.StartedAt,
+		EndedAt:   now.UTC(),
+		Lines:     len(lines),
+	}
+	if len(lines) > 0 {
+		if err := os.MkdirAll(archiveDir(townRoot), 0755); err != nil {
+			return nil, fmt.Errorf("creating transcript archive: %w", err)
+		}
+		text := strings.Join(lines, "\n") + "\n"
+		if err := util.AtomicWriteFile(entry.Path(townRoot), []byte(text), 0644); err != nil {
+			return nil, fmt.Errorf("archiving transcript: %w", err)
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.OpenFile(indexPath(townRoot), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: index is non-sensitive
+		if err != nil {
+			return nil, fmt.Errorf("opening transcript index: %w", err)
+		}
+		_, err = f.Write(append(line, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("writing transcript index: %w", err)
+		}
+	}
+
+	_ = os.Remove(logPath)
+	_ = os.Remove(metaPath)
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// List returns archived transcripts, oldest first.
+func List(townRoot string) ([]Entry, error) {
+	f, err := os.Open(indexPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].StartedAt.Before(entries[j].StartedAt) })
+	return entries, nil
+}
+
+// Query selects transcript lines to search for.
+type Query struct {
+	Text  string    // case-insensitive substring; required
+	Agent string    // exact agent address, or "" for all
+	Since time.Time // transcripts that ended at or after Since; zero for all
+	Limit int       // maximum matches; 0 for no limit
+}
+
+// Match is a transcript line matching a query.
+type Match struct {
+	Entry Entry  `json:"transcript"`
+	Line  int    `json:"line"` // 1-based
+	Text  string `json:"text"`
+}
+
+// Search returns archived transcript lines matching q, newest transcript
+// first.
+func Search(townRoot string, q Query) ([]Match, error) {
+	if strings.TrimSpace(q.Text) == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	entries, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(q.Text)
+
+	var matches []Match
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if q.Agent != "" && e.Agent != q.Agent {
+			continue
+		}
+		if !q.Since.IsZero() && e.EndedAt.Before(q.Since) {
+			continue
+		}
+		data, err := os.ReadFile(e.Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			continue
+		}
+		for n, line := range strings.Split(string(data), "\n") {
+			if strings.Contains(strings.ToLower(line), needle) {
+				matches = append(matches, Match{Entry: e, Line: n + 1, Text: line})
+				if q.Limit > 0 && len(matches) >= q.Limit {
+					return matches, nil
+				}
+			}
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,83 @@
+package transcript
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClean(t *testing.T) {
+	raw := "\x1b[1mBold\x1b[0m text\r\n\r\nprogress 10%\rprogress 100%\n\x1b]0;title\x07after osc\n   \n"
+	want := []string{"Bold text", "progress 100%", "after osc"}
+	if got := Clean(raw); !reflect.DeepEqual(got, want) {
+		t.Errorf("Clean() = %q, want %q", got, want)
+	}
+}
+
+// fakeLive sets up a live recording as Record would, without tmux.
+func fakeLive(t *testing.T, townRoot, session, agent, output string, started time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(liveDir(townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	meta, _ := json.Marshal(liveMeta{Agent: agent, Session: session, StartedAt: started})
+	if err := os.WriteFile(filepath.Join(liveDir(townRoot), session+".json"), meta, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(liveDir(townRoot), session+".log"), []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveEndedAndSearch(t *testing.T) {
+	townRoot := t.TempDir()
+	started := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	fakeLive(t, townRoot, "gt-gastown-Toast", "gastown/Toast", "running tests\nFAIL: permission denied\n", started)
+	fakeLive(t, townRoot, "gt-gastown-nux", "gastown/nux", "Permission Denied on push\n", started)
+	fakeLive(t, townRoot, "gt-gastown-slit", "gastown/slit", "still running\n", started)
+
+	archived, err := ArchiveEnded(townRoot, func(session string) bool { return session == "gt-gastown-slit" })
+	if err != nil {
+		t.Fatalf("ArchiveEnded: %v", err)
+	}
+	if len(archived) != 2 {
+		t.Fatalf("archived %d transcripts, want 2", len(archived))
+	}
+	if _, err := os.Stat(filepath.Join(liveDir(townRoot), "gt-gastown-slit.log")); err != nil {
+		t.Errorf("running session's recording should stay live: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(liveDir(townRoot), "gt-gastown-Toast.log")); !os.IsNotExist(err) {
+		t.Errorf("ended session's live recording should be removed")
+	}
+
+	matches, err := Search(townRoot, Query{Text: "permission denied"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("Search matched %d lines, want 2", len(matches))
+	}
+
+	matches, err = Search(townRoot, Query{Text: "permission denied", Agent: "gastown/Toast"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Line != 2 || matches[0].Entry.Agent != "gastown/Toast" {
+		t.Errorf("agent-filtered Search = %+v", matches)
+	}
+
+	matches, err = Search(townRoot, Query{Text: "permission", Since: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Search with future --since matched %d lines", len(matches))
+	}
+
+	if _, err := Search(townRoot, Query{Text: "  "}); err == nil {
+		t.Error("Search with empty query should fail")
+	}
+}
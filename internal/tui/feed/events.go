@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 // EventSource represents a source of events
@@ -212,9 +213,10 @@ func parseBeadContext(beadID string) (actor, rig, role string) {
 	return
 }
 
-// GtEventsSource reads events from ~/gt/.events.jsonl (gt activity log)
+// GtEventsSource reads events from ~/gt/.events.jsonl and any per-rig
+// shards (gt activity log)
 type GtEventsSource struct {
-	file   *os.File
+	tailer *events.Tailer
 	events chan Event
 	cancel context.CancelFunc
 }
@@ -231,8 +233,7 @@ type GtEvent struct {
 
 // NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
 func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
-	file, err := os.Open(eventsPath)
+	tailer, err := events.NewTailer(townRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +241,7 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	source := &GtEventsSource{
-		file:   file,
+		tailer: tailer,
 		events: make(chan Event, 100),
 		cancel: cancel,
 	}
@@ -250,14 +251,11 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 	return source, nil
 }
 
-// tail follows the file and sends events
+// tail follows the log files and sends events
 func (s *GtEventsSource) tail(ctx context.Context) {
 	defer close(s.events)
+	defer s.tailer.Close()
 
-	// Seek to end for live tailing
-	_, _ = s.file.Seek(0, 2)
-
-	scanner := bufio.NewScanner(s.file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -266,8 +264,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			for scanner.Scan() {
-				line := scanner.Text()
+			for _, line := range s.tailer.Poll() {
 				if event := parseGtEventLine(line); event != nil {
 					select {
 					case s.events <- *event:
@@ -287,7 +284,7 @@ func (s *GtEventsSource) Events() <-chan Event {
 // Close stops the source
 func (s *GtEventsSource) Close() error {
 	s.cancel()
-	return s.file.Close()
+	return nil
 }
 
 // parseGtEventLine parses a line from .events.jsonl
@@ -0,0 +1,41 @@
+package top
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the key bindings for the top dashboard.
+type KeyMap struct {
+	Refresh key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to show in the help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Refresh, k.Quit, k.Help}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Refresh},
+		{k.Help, k.Quit},
+	}
+}
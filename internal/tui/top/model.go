@@ -0,0 +1,267 @@
+// Package top is the `gt top` dashboard: a live, read-only view of a town's
+// sessions, keepalives, significant events, merges and unread mail.
+//
+// Events are followed by tailing the log; sessions, keepalives and mail are
+// polled. Nothing is written, so watching a town doesn't disturb its agents.
+package top
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/keepalive"
+)
+
+// Refresh intervals.
+const (
+	eventsInterval = time.Second
+	stateInterval  = 5 * time.Second
+)
+
+// maxRecent is how many significant events are shown.
+const maxRecent = 12
+
+// staleKeepalive is the keepalive age shown as stale.
+const staleKeepalive = 10 * time.Minute
+
+// Sessions lists tmux sessions. *tmux.Tmux implements it.
+type Sessions interface {
+	ListSessions() ([]string, error)
+}
+
+// Mail counts unread mail. *mail.Router implements it.
+type Mail interface {
+	UnreadCounts() (map[string]int, error)
+}
+
+// Keepalive is an agent workspace's last gt activity.
+type Keepalive struct {
+	Agent   string // workspace path relative to the town root
+	Command string
+	Age     time.Duration
+}
+
+// RigMerges summarizes a rig's merge queue outcomes since the dashboard's
+// history began.
+type RigMerges struct {
+	Rig     string
+	Merged  int
+	Failed  int
+	Skipped int
+	Last    events.Record // most recent merge event
+}
+
+// Activity is what the dashboard derives from the events log.
+type Activity struct {
+	Recent []events.Record // significant events and incidents, oldest first
+	Merges map[string]*RigMerges
+	scorer *events.Scorer
+}
+
+// NewActivity returns empty activity scored by rules.
+func NewActivity(rules *events.SignificanceRules) *Activity {
+	return &Activity{Merges: make(map[string]*RigMerges), scorer: events.NewScorer(rules)}
+}
+
+// Add folds an event into the activity.
+func (a *Activity) Add(r events.Record) {
+	significant, incident := a.scorer.AtLeast(r.Event, events.SignificanceHigh)
+	if significant {
+		a.addRecent(r)
+	}
+	if incident != nil {
+		a.addRecent(events.Record{Event: *incident})
+	}
+
+	switch r.Type {
+	case events.TypeMerged, events.TypeMergeFailed, events.TypeMergeSkipped:
+	default:
+		return
+	}
+	rig := events.EventRig(r.Event)
+	if rig == "" {
+		return
+	}
+	m := a.Merges[rig]
+	if m == nil {
+		m = &RigMerges{Rig: rig}
+		a.Merges[rig] = m
+	}
+	switch r.Type {
+	case events.TypeMerged:
+		m.Merged++
+	case events.TypeMergeFailed:
+		m.Failed++
+	case events.TypeMergeSkipped:
+		m.Skipped++
+	}
+	m.Last = r
+}
+
+func (a *Activity) addRecent(r events.Record) {
+	a.Recent = append(a.Recent, r)
+	if len(a.Recent) > maxRecent {
+		a.Recent = a.Recent[len(a.Recent)-maxRecent:]
+	}
+}
+
+// Rigs returns per-rig merge summaries sorted by rig.
+func (a *Activity) Rigs() []*RigMerges {
+	rigs := make([]*RigMerges, 0, len(a.Merges))
+	for _, m := range a.Merges {
+		rigs = append(rigs, m)
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Rig < rigs[j].Rig })
+	return rigs
+}
+
+// State is the polled part of the dashboard.
+type State struct {
+	Sessions   []string
+	Keepalives []Keepalive
+	Unread     map[string]int
+	SessionErr error
+	MailErr    error
+	At         time.Time
+}
+
+// LoadState polls sessions, keepalive files and unread mail. Either source
+// may be nil to skip it.
+func LoadState(townRoot string, sessions Sessions, mail Mail, now time.Time) State {
+	s := State{At: now}
+	if sessions != nil {
+		all, err := sessions.ListSessions()
+		s.SessionErr = err
+		for _, name := range all {
+			if strings.HasPrefix(name, "gt-") || strings.HasPrefix(name, "hq-") {
+				s.Sessions = append(s.Sessions, name)
+			}
+		}
+		sort.Strings(s.Sessions)
+	}
+
+	for _, workspace := range keepalive.Workspaces(townRoot) {
+		state := keepalive.Read(workspace)
+		if state == nil {
+			continue
+		}
+		agent, err := filepath.Rel(townRoot, workspace)
+		if err != nil {
+			continue
+		}
+		s.Keepalives = append(s.Keepalives, Keepalive{
+			Agent:   filepath.ToSlash(agent),
+			Command: state.LastCommand,
+			Age:     now.Sub(state.Timestamp),
+		})
+	}
+	sort.Slice(s.Keepalives, func(i, j int) bool { return s.Keepalives[i].Age < s.Keepalives[j].Age })
+
+	if mail != nil {
+		s.Unread, s.MailErr = mail.UnreadCounts()
+	}
+	return s
+}
+
+// Model is the bubbletea model for gt top.
+type Model struct {
+	townRoot string
+	sessions Sessions
+	mail     Mail
+	tailer   *events.Tailer
+	activity *Activity
+	state    State
+
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+}
+
+// New creates the dashboard. history seeds the activity (typically
+// events.ReadAll); tailer, created before history was read, supplies new
+// events. The caller closes the tailer.
+func New(townRoot string, history []events.Record, tailer *events.Tailer, rules *events.SignificanceRules, sessions Sessions, mail Mail) Model {
+	activity := NewActivity(rules)
+	for _, r := range history {
+		activity.Add(r)
+	}
+	return Model{
+		townRoot: townRoot,
+		sessions: sessions,
+		mail:     mail,
+		tailer:   tailer,
+		activity: activity,
+		keys:     DefaultKeyMap(),
+		help:     help.New(),
+	}
+}
+
+type eventsTickMsg struct{}
+
+type stateMsg State
+
+func eventsTick() tea.Cmd {
+	return tea.Tick(eventsInterval, func(time.Time) tea.Msg { return eventsTickMsg{} })
+}
+
+func (m Model) pollState() tea.Msg {
+	return stateMsg(LoadState(m.townRoot, m.sessions, m.mail, time.Now()))
+}
+
+// Init starts following events and polls state once.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.pollState, eventsTick())
+}
+
+// Update handles messages.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+
+	case eventsTickMsg:
+		if m.tailer != nil {
+			for _, line := range m.tailer.Poll() {
+				var e events.Event
+				if err := json.Unmarshal([]byte(line), &e); err != nil {
+					continue
+				}
+				m.activity.Add(events.Record{Event: e})
+			}
+		}
+		return m, eventsTick()
+
+	case stateMsg:
+		m.state = State(msg)
+		return m, tea.Tick(stateInterval, func(time.Time) tea.Msg { return m.pollState() })
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = !m.showHelp
+			return m, nil
+		case key.Matches(msg, m.keys.Refresh):
+			return m, m.pollState
+		}
+	}
+	return m, nil
+}
+
+// View renders the model.
+func (m Model) View() string {
+	return m.renderView()
+}
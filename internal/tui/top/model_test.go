@@ -0,0 +1,42 @@
+package top
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func record(typ, actor string, payload map[string]interface{}) events.Record {
+	return events.Record{Event: events.Event{Type: typ, Actor: actor, Payload: payload}}
+}
+
+func TestActivity(t *testing.T) {
+	a := NewActivity(events.DefaultSignificanceRules())
+	a.Add(record(events.TypeMerged, "gastown/refinery", map[string]interface{}{"rig": "gastown"}))
+	a.Add(record(events.TypeMergeFailed, "gastown/refinery", map[string]interface{}{"rig": "gastown"}))
+	a.Add(record(events.TypeMerged, "beads/refinery", map[string]interface{}{"rig": "beads"}))
+	a.Add(record(events.TypeMergeStarted, "beads/refinery", map[string]interface{}{"rig": "beads"}))
+
+	rigs := a.Rigs()
+	if len(rigs) != 2 || rigs[0].Rig != "beads" || rigs[1].Rig != "gastown" {
+		t.Fatalf("Rigs() = %+v", rigs)
+	}
+	if g := rigs[1]; g.Merged != 1 || g.Failed != 1 || g.Last.Type != events.TypeMergeFailed {
+		t.Errorf("gastown merges = %+v", g)
+	}
+	if b := rigs[0]; b.Merged != 1 || b.Last.Type != events.TypeMerged {
+		t.Errorf("beads merges = %+v, merge_started should not count", b)
+	}
+
+	for _, r := range a.Recent {
+		if !events.DefaultSignificanceRules().AtLeast(r.Event, events.SignificanceHigh) {
+			t.Errorf("Recent holds %s, below high significance", r.Type)
+		}
+	}
+	for i := 0; i < maxRecent+5; i++ {
+		a.Add(record(events.TypeMergeFailed, "gastown/refinery", map[string]interface{}{"rig": "gastown"}))
+	}
+	if len(a.Recent) > maxRecent {
+		t.Errorf("len(Recent) = %d, want at most %d", len(a.Recent), maxRecent)
+	}
+}
@@ -0,0 +1,206 @@
+package top
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Styles for the top dashboard
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	sectionStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("15"))
+
+	okStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("10")) // green
+
+	warnStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	helpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// renderView renders the entire view.
+func (m Model) renderView() string {
+	var b strings.Builder
+
+	// Title
+	title := titleStyle.Render("Gas Town")
+	if !m.state.At.IsZero() {
+		title += dimStyle.Render("  updated " + m.state.At.Format("15:04:05"))
+	}
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	m.renderSessions(&b)
+	m.renderKeepalives(&b)
+	m.renderMerges(&b)
+	m.renderMail(&b)
+	m.renderEvents(&b)
+
+	// Help footer
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(helpStyle.Render("r:refresh  q:quit  ?:help"))
+	}
+
+	return b.String()
+}
+
+func (m Model) renderSessions(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render(fmt.Sprintf("Sessions (%d)", len(m.state.Sessions))))
+	b.WriteString("\n")
+	if m.state.SessionErr != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("  Error: %v", m.state.SessionErr)))
+		b.WriteString("\n")
+	} else if len(m.state.Sessions) == 0 {
+		b.WriteString(dimStyle.Render("  none running"))
+		b.WriteString("\n")
+	}
+	for _, s := range m.state.Sessions {
+		b.WriteString("  " + okStyle.Render("●") + " " + s + "\n")
+	}
+	b.WriteString("\n")
+}
+
+func (m Model) renderKeepalives(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render("Keepalives"))
+	b.WriteString("\n")
+	if len(m.state.Keepalives) == 0 {
+		b.WriteString(dimStyle.Render("  none recorded"))
+		b.WriteString("\n")
+	}
+	for _, k := range m.state.Keepalives {
+		age := fmt.Sprintf("%8s", formatAge(k.Age))
+		if k.Age > staleKeepalive {
+			age = warnStyle.Render(age)
+		}
+		fmt.Fprintf(b, "  %-32s %s  %s\n", truncate(k.Agent, 32), age, dimStyle.Render(truncate(k.Command, 40)))
+	}
+	b.WriteString("\n")
+}
+
+func (m Model) renderMerges(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render("Merges"))
+	b.WriteString("\n")
+	rigs := m.activity.Rigs()
+	if len(rigs) == 0 {
+		b.WriteString(dimStyle.Render("  no merge activity"))
+		b.WriteString("\n")
+	}
+	for _, r := range rigs {
+		failed := fmt.Sprintf("%d failed", r.Failed)
+		if r.Failed > 0 {
+			failed = errorStyle.Render(failed)
+		}
+		last := r.Last.Type
+		if t, err := time.Parse(time.RFC3339, r.Last.Timestamp); err == nil {
+			last += " " + t.Local().Format("15:04")
+		}
+		fmt.Fprintf(b, "  %-16s %d merged  %s  %d skipped  %s\n",
+			truncate(r.Rig, 16), r.Merged, failed, r.Skipped, dimStyle.Render("last: "+last))
+	}
+	b.WriteString("\n")
+}
+
+func (m Model) renderMail(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render("Unread mail"))
+	b.WriteString("\n")
+	if m.state.MailErr != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("  Error: %v", m.state.MailErr)))
+		b.WriteString("\n")
+	}
+	addresses := make([]string, 0, len(m.state.Unread))
+	for addr := range m.state.Unread {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+	if len(addresses) == 0 && m.state.MailErr == nil {
+		b.WriteString(dimStyle.Render("  inboxes clear"))
+		b.WriteString("\n")
+	}
+	for _, addr := range addresses {
+		fmt.Fprintf(b, "  %-32s %d\n", truncate(addr, 32), m.state.Unread[addr])
+	}
+	b.WriteString("\n")
+}
+
+func (m Model) renderEvents(b *strings.Builder) {
+	b.WriteString(sectionStyle.Render("Significant events"))
+	b.WriteString("\n")
+	if len(m.activity.Recent) == 0 {
+		b.WriteString(dimStyle.Render("  none yet"))
+		b.WriteString("\n")
+	}
+	// Newest first
+	for i := len(m.activity.Recent) - 1; i >= 0; i-- {
+		r := m.activity.Recent[i]
+		ts := r.Timestamp
+		if t, err := time.Parse(time.RFC3339, r.Timestamp); err == nil {
+			ts = t.Local().Format("15:04:05")
+		}
+		typ := fmt.Sprintf("%-16s", r.Type)
+		if r.Type == events.TypeIncident {
+			typ = errorStyle.Render(typ)
+		}
+		fmt.Fprintf(b, "  %s %s %s\n", dimStyle.Render(ts), typ, truncate(r.Actor+" "+summary(r.Event), 60))
+	}
+	b.WriteString("\n")
+}
+
+// summary renders an event's payload as sorted key=value pairs.
+func summary(e events.Event) string {
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, e.Payload[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatAge renders a duration compactly (45s, 12m, 3h, 2d).
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
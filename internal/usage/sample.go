@@ -0,0 +1,341 @@
+package usage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Sessions is what the sampler needs from tmux. *tmux.Tmux implements it.
+type Sessions interface {
+	ListSessions() ([]string, error)
+	GetPanePID(session string) (string, error)
+	GetPaneWorkDir(session string) (string, error)
+	GetEnvironment(session, key string) (string, error)
+}
+
+// Process is one entry of the process table.
+type Process struct {
+	PID, PPID  int
+	CPUSeconds float64 // CPU time used so far
+	RSS        int64   // resident memory in bytes
+}
+
+// listProcesses reads the process table. Tests replace it.
+var listProcesses = func() ([]Process, error) {
+	// time is cumulative CPU time; rss is in KiB on Linux and macOS
+	out, err := exec.Command("ps", "-eo", "pid=,ppid=,rss=,time=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing processes: %w", err)
+	}
+	var procs []Process
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		rss, err3 := strconv.ParseInt(fields[2], 10, 64)
+		cpu, err4 := parseCPUTime(fields[3])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		procs = append(procs, Process{PID: pid, PPID: ppid, CPUSeconds: cpu, RSS: rss * 1024})
+	}
+	return procs, nil
+}
+
+// parseCPUTime parses ps's cumulative CPU time: [DD-]HH:MM:SS on Linux,
+// MM:SS.ss on macOS.
+func parseCPUTime(s string) (float64, error) {
+	var days float64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, fmt.Errorf("invalid CPU time %q", s)
+		}
+		days, s = float64(n), rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid CPU time %q", s)
+	}
+	var secs float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid CPU time %q", s)
+		}
+		secs = secs*60 + v
+	}
+	return days*86400 + secs, nil
+}
+
+// state is what the sampler remembers between samples.
+type state struct {
+	// CPU is each sampled process's CPU time at the last sample, so only
+	// the time used since is counted.
+	CPU map[int]float64 `json:"cpu"`
+	// Transcripts is how far each claude transcript has been read.
+	Transcripts map[string]transcriptState `json:"transcripts"`
+}
+
+type transcriptState struct {
+	Offset      int64  `json:"offset"`
+	LastMessage string `json:"last_message,omitempty"`
+}
+
+func statePath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "state.json")
+}
+
+func loadState(townRoot string) (*state, error) {
+	st := &state{}
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed from the town root
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, fmt.Errorf("parsing usage state: %w", err)
+		}
+	}
+	if st.CPU == nil {
+		st.CPU = make(map[int]float64)
+	}
+	if st.Transcripts == nil {
+		st.Transcripts = make(map[string]transcriptState)
+	}
+	return st, nil
+}
+
+// DefaultClaudeProjects returns where the claude CLI keeps session
+// transcripts when CLAUDE_CONFIG_DIR isn't set.
+func DefaultClaudeProjects() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".claude", "projects")
+}
+
+// Sampler measures the town's agent sessions.
+type Sampler struct {
+	TownRoot string
+	Sessions Sessions
+
+	// ClaudeProjects is where the claude CLI keeps transcripts for sessions
+	// without CLAUDE_CONFIG_DIR. Defaults to DefaultClaudeProjects.
+	ClaudeProjects string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Sample measures every managed session and records the usage since the
+// last sample: CPU time and peak memory on today's date, tokens on the
+// dates the claude CLI reported them. CPU time a process used before it was
+// first sampled counts as used since the last sample. Returns the usage
+// added per agent.
+func (s *Sampler) Sample() ([]Usage, error) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+	projects := s.ClaudeProjects
+	if projects == "" {
+		projects = DefaultClaudeProjects()
+	}
+
+	names, err := s.Sessions.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]Process)
+	byPID := make(map[int]Process)
+	for _, p := range procs {
+		children[p.PPID] = append(children[p.PPID], p)
+		byPID[p.PID] = p
+	}
+
+	st, err := loadState(s.TownRoot)
+	if err != nil {
+		return nil, err
+	}
+	cpuSeen := make(map[int]float64)
+	byDate := make(map[string][]Usage)
+	var sampled []Usage
+	readDirs := make(map[string]bool)
+
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // not a Gas Town session
+		}
+		u := Usage{Agent: id.Address(), Rig: id.Rig, Role: string(id.Role), Samples: 1}
+
+		if pid, err := s.Sessions.GetPanePID(name); err == nil {
+			if root, err := strconv.Atoi(strings.TrimSpace(pid)); err == nil {
+				if p, ok := byPID[root]; ok {
+					for _, p := range tree(p, children) {
+						used := p.CPUSeconds - st.CPU[p.PID]
+						if used < 0 {
+							used = p.CPUSeconds // the PID was reused
+						}
+						u.CPUSeconds += used
+						u.PeakRSS += p.RSS
+						cpuSeen[p.PID] = p.CPUSeconds
+					}
+				}
+			}
+		}
+		day := now.Format(dayLayout)
+		byDate[day] = append(byDate[day], u)
+
+		total := u
+		if dir := s.projectDir(name, projects); dir != "" && !readDirs[dir] {
+			readDirs[dir] = true
+			tokens, err := readTranscripts(dir, st, now)
+			if err != nil {
+				return nil, err
+			}
+			for date, t := range tokens {
+				t.Agent, t.Rig, t.Role = u.Agent, u.Rig, u.Role
+				byDate[date] = append(byDate[date], t)
+				total.Add(t)
+			}
+		}
+		sampled = append(sampled, total)
+	}
+
+	for date, used := range byDate {
+		day, _ := time.ParseInLocation(dayLayout, date, now.Location())
+		if err := Record(s.TownRoot, day, used); err != nil {
+			return nil, err
+		}
+	}
+	st.CPU = cpuSeen
+	if err := os.MkdirAll(Dir(s.TownRoot), 0755); err != nil {
+		return nil, err
+	}
+	if err := util.AtomicWriteJSON(statePath(s.TownRoot), st); err != nil {
+		return nil, err
+	}
+	return sampled, nil
+}
+
+// tree returns p and its descendants.
+func tree(p Process, children map[int][]Process) []Process {
+	all := []Process{p}
+	for i := 0; i < len(all); i++ {
+		all = append(all, children[all[i].PID]...)
+	}
+	return all
+}
+
+// projectNonAlnum is what the claude CLI replaces with "-" to name a
+// working directory's project directory.
+var projectNonAlnum = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// projectDir returns the directory the claude CLI in a session writes its
+// transcripts to, or "" if the session's working directory is unknown.
+func (s *Sampler) projectDir(name, projects string) string {
+	workDir, err := s.Sessions.GetPaneWorkDir(name)
+	if err != nil || strings.TrimSpace(workDir) == "" {
+		return ""
+	}
+	if configDir, err := s.Sessions.GetEnvironment(name, "CLAUDE_CONFIG_DIR"); err == nil && configDir != "" {
+		projects = filepath.Join(configDir, "projects")
+	}
+	return filepath.Join(projects, projectNonAlnum.ReplaceAllString(strings.TrimSpace(workDir), "-"))
+}
+
+// transcriptLine is the part of a claude CLI transcript line that reports
+// usage.
+type transcriptLine struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	CostUSD   float64   `json:"costUSD"`
+	Message   struct {
+		ID    string `json:"id"`
+		Usage *struct {
+			InputTokens         int64 `json:"input_tokens"`
+			OutputTokens        int64 `json:"output_tokens"`
+			CacheCreationTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// readTranscripts reads the usage the claude CLI has written to the
+// transcripts in dir since they were last read, by local date. The CLI
+// writes a line per content block, each repeating its message's usage, so
+// only the first line of a message counts. Lines without a timestamp count
+// on now's date.
+func readTranscripts(dir string, st *state, now time.Time) (map[string]Usage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]Usage)
+	for _, path := range paths {
+		ts := st.Transcripts[path]
+		f, err := os.Open(path) //nolint:gosec // G304: path is under the claude projects dir
+		if err != nil {
+			continue
+		}
+		if info, err := f.Stat(); err == nil && info.Size() < ts.Offset {
+			ts = transcriptState{} // rewritten; start again
+		}
+		if _, err := f.Seek(ts.Offset, io.SeekStart); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		r := bufio.NewReader(f)
+		for {
+			line, err := r.ReadBytes('\n')
+			if err != nil {
+				break // EOF or a partial line, left for next time
+			}
+			ts.Offset += int64(len(line))
+			var tl transcriptLine
+			if json.Unmarshal(bytes.TrimSpace(line), &tl) != nil || tl.Type != "assistant" || tl.Message.Usage == nil {
+				continue
+			}
+			if tl.Message.ID != "" && tl.Message.ID == ts.LastMessage {
+				continue
+			}
+			ts.LastMessage = tl.Message.ID
+			at := tl.Timestamp
+			if at.IsZero() {
+				at = now
+			}
+			date := at.In(now.Location()).Format(dayLayout)
+			u := byDate[date]
+			u.InputTokens += tl.Message.Usage.InputTokens
+			u.OutputTokens += tl.Message.Usage.OutputTokens
+			u.CacheCreationTokens += tl.Message.Usage.CacheCreationTokens
+			u.CacheReadTokens += tl.Message.Usage.CacheReadTokens
+			u.CostUSD += tl.CostUSD
+			byDate[date] = u
+		}
+		_ = f.Close()
+		st.Transcripts[path] = ts
+	}
+	return byDate, nil
+}
@@ -0,0 +1,185 @@
+// Package usage tracks what each agent costs to run: the CPU time and
+// memory of its tmux session's process tree, and the tokens (and cost, where
+// reported) of the claude CLI running in it.
+//
+// The daemon samples every managed session on each heartbeat (see Sampler)
+// and adds the results to a file per day under logs/usage/, one entry per
+// agent. Reports aggregate those days per agent, rig or role.
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// dayLayout names the daily usage files.
+const dayLayout = "2006-01-02"
+
+// Usage is an agent's resource use over a day, or a sum of such.
+type Usage struct {
+	Agent string `json:"agent"`         // address, e.g. gastown/polecats/Toast or mayor
+	Rig   string `json:"rig,omitempty"` // "" for town-level agents
+	Role  string `json:"role"`
+
+	// CPUSeconds is CPU time used by the session's process tree.
+	CPUSeconds float64 `json:"cpu_seconds"`
+	// PeakRSS is the largest resident memory of the process tree sampled,
+	// in bytes.
+	PeakRSS int64 `json:"peak_rss_bytes"`
+	// Samples is how many times the session was sampled.
+	Samples int `json:"samples"`
+
+	// Token counts reported by the claude CLI.
+	InputTokens         int64 `json:"input_tokens"`
+	OutputTokens        int64 `json:"output_tokens"`
+	CacheCreationTokens int64 `json:"cache_creation_tokens"`
+	CacheReadTokens     int64 `json:"cache_read_tokens"`
+
+	// CostUSD is the cost the claude CLI reported, if it did.
+	CostUSD float64 `json:"cost_usd,omitempty"`
+}
+
+// Add adds o's figures to u. Peak memory is the larger of the two.
+func (u *Usage) Add(o Usage) {
+	u.CPUSeconds += o.CPUSeconds
+	if o.PeakRSS > u.PeakRSS {
+		u.PeakRSS = o.PeakRSS
+	}
+	u.Samples += o.Samples
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CacheCreationTokens += o.CacheCreationTokens
+	u.CacheReadTokens += o.CacheReadTokens
+	u.CostUSD += o.CostUSD
+}
+
+// Tokens returns the total tokens used.
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// Day is the usage of each agent on one local date.
+type Day struct {
+	Date   string            `json:"date"` // YYYY-MM-DD
+	Agents map[string]*Usage `json:"agents"`
+}
+
+// Dir returns the town's usage directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "usage")
+}
+
+func dayPath(townRoot, date string) string {
+	return filepath.Join(Dir(townRoot), date+".json")
+}
+
+// LoadDay reads the usage for date (YYYY-MM-DD). A day with no file is
+// empty.
+func LoadDay(townRoot, date string) (*Day, error) {
+	day := &Day{Date: date, Agents: make(map[string]*Usage)}
+	data, err := os.ReadFile(dayPath(townRoot, date)) //nolint:gosec // G304: path is constructed from the town root
+	if errors.Is(err, os.ErrNotExist) {
+		return day, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, day); err != nil {
+		return nil, fmt.Errorf("parsing usage for %s: %w", date, err)
+	}
+	if day.Agents == nil {
+		day.Agents = make(map[string]*Usage)
+	}
+	return day, nil
+}
+
+// Record adds usage to the day of date for each agent in it.
+func Record(townRoot string, date time.Time, used []Usage) error {
+	if len(used) == 0 {
+		return nil
+	}
+	day, err := LoadDay(townRoot, date.Format(dayLayout))
+	if err != nil {
+		return err
+	}
+	for _, u := range used {
+		total, ok := day.Agents[u.Agent]
+		if !ok {
+			total = &Usage{Agent: u.Agent, Rig: u.Rig, Role: u.Role}
+			day.Agents[u.Agent] = total
+		}
+		total.Add(u)
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(dayPath(townRoot, day.Date), day)
+}
+
+// Load returns the recorded days from since to until inclusive, by local
+// date, oldest first.
+func Load(townRoot string, since, until time.Time) ([]Day, error) {
+	paths, err := filepath.Glob(filepath.Join(Dir(townRoot), "????-??-??.json"))
+	if err != nil {
+		return nil, err
+	}
+	first, last := since.Format(dayLayout), until.Format(dayLayout)
+	var days []Day
+	for _, path := range paths {
+		date := filepath.Base(path)[:len(dayLayout)]
+		if date < first || date > last {
+			continue
+		}
+		day, err := LoadDay(townRoot, date)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days, nil
+}
+
+// Grouping keys for Aggregate.
+var (
+	ByAgent = func(u Usage) string { return u.Agent }
+	ByRig   = func(u Usage) string { return u.Rig }
+	ByRole  = func(u Usage) string { return u.Role }
+)
+
+// Aggregate sums days' usage by key, e.g. ByRig. Each result's Agent is its
+// key; Rig and Role are kept where every summed entry agrees. Results are
+// sorted by key.
+func Aggregate(days []Day, key func(Usage) string) []Usage {
+	totals := make(map[string]*Usage)
+	for _, day := range days {
+		for _, u := range day.Agents {
+			k := key(*u)
+			total, ok := totals[k]
+			if !ok {
+				total = &Usage{Agent: k, Rig: u.Rig, Role: u.Role}
+				totals[k] = total
+			}
+			if total.Rig != u.Rig {
+				total.Rig = ""
+			}
+			if total.Role != u.Role {
+				total.Role = ""
+			}
+			total.Add(*u)
+		}
+	}
+	result := make([]Usage, 0, len(totals))
+	for _, u := range totals {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Agent < result[j].Agent })
+	return result
+}
@@ -0,0 +1,173 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeSessions serves fixed tmux answers.
+type fakeSessions struct {
+	pids    map[string]string
+	workDir map[string]string
+}
+
+func (f *fakeSessions) ListSessions() ([]string, error) {
+	var names []string
+	for name := range f.pids {
+		names = append(names, name)
+	}
+	return append(names, "scratch"), nil
+}
+
+func (f *fakeSessions) GetPanePID(session string) (string, error) { return f.pids[session], nil }
+
+func (f *fakeSessions) GetPaneWorkDir(session string) (string, error) {
+	return f.workDir[session], nil
+}
+
+func (f *fakeSessions) GetEnvironment(_, _ string) (string, error) { return "", nil }
+
+func TestParseCPUTime(t *testing.T) {
+	tests := map[string]float64{
+		"00:01:05":   65,
+		"1-02:00:00": 93600,
+		"3:04.50":    184.5,
+	}
+	for in, want := range tests {
+		if got, err := parseCPUTime(in); err != nil || got != want {
+			t.Errorf("parseCPUTime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseCPUTime("soon"); err == nil {
+		t.Error("parsed a bad CPU time")
+	}
+}
+
+func TestSample(t *testing.T) {
+	townRoot := t.TempDir()
+	projects := t.TempDir()
+	workDir := "/home/gt/gastown/polecats/toast"
+	transcripts := filepath.Join(projects, "-home-gt-gastown-polecats-toast")
+	if err := os.MkdirAll(transcripts, 0755); err != nil {
+		t.Fatal(err)
+	}
+	transcript := filepath.Join(transcripts, "abc.jsonl")
+	line := func(id string, in, out int) string {
+		return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-03-04T10:00:00Z","message":{"id":%q,"usage":{"input_tokens":%d,"output_tokens":%d,"cache_read_input_tokens":5}}}`+"\n", id, in, out)
+	}
+	// Two lines of one message, then another message
+	writeFile(t, transcript, `{"type":"user","message":{"content":"go"}}`+"\n"+line("m1", 100, 10)+line("m1", 100, 10)+line("m2", 50, 20))
+
+	procs := []Process{
+		{PID: 10, PPID: 1, CPUSeconds: 1, RSS: 1 << 20},
+		{PID: 11, PPID: 10, CPUSeconds: 30, RSS: 100 << 20}, // claude under the pane shell
+		{PID: 20, PPID: 1, CPUSeconds: 5, RSS: 50 << 20},
+	}
+	orig := listProcesses
+	listProcesses = func() ([]Process, error) { return procs, nil }
+	t.Cleanup(func() { listProcesses = orig })
+
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	s := &Sampler{
+		TownRoot: townRoot,
+		Sessions: &fakeSessions{
+			pids:    map[string]string{"gt-gastown-toast": "10", "hq-mayor": "20"},
+			workDir: map[string]string{"gt-gastown-toast": workDir},
+		},
+		ClaudeProjects: projects,
+		Now:            func() time.Time { return now },
+	}
+	if _, err := s.Sample(); err != nil {
+		t.Fatalf("Sample: %v", err)
+	}
+
+	// Later: claude used 12s more CPU and replied once more
+	procs[1].CPUSeconds = 42
+	procs[1].RSS = 200 << 20
+	f, err := os.OpenFile(transcript, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(line("m3", 7, 3) + `{"type":"assistant","message":{"id":"m4"`) // partial line
+	_ = f.Close()
+	now = now.Add(3 * time.Minute)
+	if _, err := s.Sample(); err != nil {
+		t.Fatalf("second Sample: %v", err)
+	}
+
+	day, err := LoadDay(townRoot, "2026-03-04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	toast := day.Agents["gastown/polecats/toast"]
+	if toast == nil {
+		t.Fatalf("no usage for toast: %v", day.Agents)
+	}
+	if toast.CPUSeconds != 43 || toast.PeakRSS != 201<<20 || toast.Samples != 2 {
+		t.Errorf("toast CPU/memory = %+v, want 43s, 201MiB peak, 2 samples", *toast)
+	}
+	if toast.InputTokens != 157 || toast.OutputTokens != 33 || toast.CacheReadTokens != 15 {
+		t.Errorf("toast tokens = %+v, want 157 in, 33 out, 15 cache read", *toast)
+	}
+	if toast.Rig != "gastown" || toast.Role != "polecat" {
+		t.Errorf("toast rig/role = %s/%s", toast.Rig, toast.Role)
+	}
+	if mayor := day.Agents["mayor"]; mayor == nil || mayor.CPUSeconds != 5 || mayor.Rig != "" {
+		t.Errorf("mayor = %+v, want 5s CPU and no rig", mayor)
+	}
+	if _, ok := day.Agents["scratch"]; ok {
+		t.Error("sampled a session Gas Town doesn't manage")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	townRoot := t.TempDir()
+	d1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	d2 := d1.AddDate(0, 0, 1)
+	record := func(date time.Time, used ...Usage) {
+		t.Helper()
+		if err := Record(townRoot, date, used); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record(d1,
+		Usage{Agent: "gastown/polecats/toast", Rig: "gastown", Role: "polecat", CPUSeconds: 10, OutputTokens: 100},
+		Usage{Agent: "beads/witness", Rig: "beads", Role: "witness", CPUSeconds: 1})
+	record(d2,
+		Usage{Agent: "gastown/witness", Rig: "gastown", Role: "witness", CPUSeconds: 2, CostUSD: 0.5},
+		Usage{Agent: "mayor", Role: "mayor", CPUSeconds: 4})
+	record(d2.AddDate(0, 0, 1), Usage{Agent: "mayor", Role: "mayor", CPUSeconds: 100})
+
+	days, err := Load(townRoot, d1, d2)
+	if err != nil || len(days) != 2 {
+		t.Fatalf("Load = %v, %v; want 2 days", days, err)
+	}
+
+	byRig := Aggregate(days, ByRig)
+	want := map[string]float64{"": 4, "beads": 1, "gastown": 12}
+	if len(byRig) != len(want) {
+		t.Fatalf("by rig = %+v", byRig)
+	}
+	for _, u := range byRig {
+		if u.CPUSeconds != want[u.Agent] {
+			t.Errorf("rig %q CPU = %v, want %v", u.Agent, u.CPUSeconds, want[u.Agent])
+		}
+		if u.Agent == "gastown" && (u.Role != "" || u.OutputTokens != 100 || u.CostUSD != 0.5) {
+			t.Errorf("gastown = %+v, want mixed role, 100 tokens, $0.50", u)
+		}
+	}
+
+	if byRole := Aggregate(days, ByRole); len(byRole) != 3 || byRole[2].Agent != "witness" || byRole[2].CPUSeconds != 3 {
+		t.Errorf("by role = %+v", byRole)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
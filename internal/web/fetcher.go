@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/health"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // LiveConvoyFetcher fetches convoy data from beads.
 type LiveConvoyFetcher struct {
+	townRoot  string
 	townBeads string
 }
 
@@ -26,6 +31,7 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}
 
 	return &LiveConvoyFetcher{
+		townRoot:  townRoot,
 		townBeads: filepath.Join(townRoot, ".beads"),
 	}, nil
 }
@@ -753,3 +759,69 @@ func parseActivityTimestamp(s string) (int64, bool) {
 	}
 	return unix, true
 }
+
+// FetchRigHealth scores every registered rig from the town's recent events.
+func (f *LiveConvoyFetcher) FetchRigHealth() ([]RigHealthRow, error) {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(f.townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil, err
+	}
+	records, err := events.ReadAll(f.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	scores := health.Compute(records, time.Now())
+
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]RigHealthRow, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, rigHealthRow(health.ForRig(scores, name)))
+	}
+	return rows, nil
+}
+
+// rigHealthRow converts a health score into a dashboard row.
+func rigHealthRow(s *health.Score) RigHealthRow {
+	row := RigHealthRow{
+		Rig:     s.Rig,
+		Score:   s.Score,
+		Grade:   s.Grade,
+		Trend:   s.Arrow(),
+		Summary: healthSummary(s.Signals),
+	}
+	switch s.Grade {
+	case health.GradeCritical:
+		row.ColorClass = "mq-red"
+	case health.GradeDegraded:
+		row.ColorClass = "mq-yellow"
+	default:
+		row.ColorClass = "mq-green"
+	}
+	return row
+}
+
+// healthSummary lists the signals that cost a rig points.
+func healthSummary(s health.Signals) string {
+	var parts []string
+	if s.MergeFailures > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d merges failed", s.MergeFailures, s.Merges))
+	}
+	if s.SessionDeaths > 0 {
+		parts = append(parts, fmt.Sprintf("%d session deaths", s.SessionDeaths))
+	}
+	if s.Stuck > 0 {
+		parts = append(parts, fmt.Sprintf("%d stuck", s.Stuck))
+	}
+	if s.Escalations > 0 {
+		parts = append(parts, fmt.Sprintf("%d escalations", s.Escalations))
+	}
+	if len(parts) == 0 {
+		return "no problems in the last 24h"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -10,6 +10,7 @@ type ConvoyFetcher interface {
 	FetchConvoys() ([]ConvoyRow, error)
 	FetchMergeQueue() ([]MergeQueueRow, error)
 	FetchPolecats() ([]PolecatRow, error)
+	FetchRigHealth() ([]RigHealthRow, error)
 }
 
 // ConvoyHandler handles HTTP requests for the convoy dashboard.
@@ -51,10 +52,17 @@ func (h *ConvoyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		polecats = nil
 	}
 
+	rigHealth, err := h.fetcher.FetchRigHealth()
+	if err != nil {
+		// Non-fatal: show convoys even if rig health fails
+		rigHealth = nil
+	}
+
 	data := ConvoyData{
 		Convoys:    convoys,
 		MergeQueue: mergeQueue,
 		Polecats:   polecats,
+		RigHealth:  rigHealth,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
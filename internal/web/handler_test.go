@@ -20,6 +20,7 @@ type MockConvoyFetcher struct {
 	Convoys    []ConvoyRow
 	MergeQueue []MergeQueueRow
 	Polecats   []PolecatRow
+	RigHealth  []RigHealthRow
 	Error      error
 }
 
@@ -35,6 +36,10 @@ func (m *MockConvoyFetcher) FetchPolecats() ([]PolecatRow, error) {
 	return m.Polecats, nil
 }
 
+func (m *MockConvoyFetcher) FetchRigHealth() ([]RigHealthRow, error) {
+	return m.RigHealth, nil
+}
+
 func TestConvoyHandler_RendersTemplate(t *testing.T) {
 	mock := &MockConvoyFetcher{
 		Convoys: []ConvoyRow{
@@ -950,6 +955,7 @@ type MockConvoyFetcherWithErrors struct {
 	Convoys          []ConvoyRow
 	MergeQueueError  error
 	PolecatsError    error
+	RigHealthError   error
 }
 
 func (m *MockConvoyFetcherWithErrors) FetchConvoys() ([]ConvoyRow, error) {
@@ -964,6 +970,10 @@ func (m *MockConvoyFetcherWithErrors) FetchPolecats() ([]PolecatRow, error) {
 	return nil, m.PolecatsError
 }
 
+func (m *MockConvoyFetcherWithErrors) FetchRigHealth() ([]RigHealthRow, error) {
+	return nil, m.RigHealthError
+}
+
 func TestConvoyHandler_NonFatalErrors(t *testing.T) {
 	mock := &MockConvoyFetcherWithErrors{
 		Convoys: []ConvoyRow{
@@ -971,6 +981,7 @@ func TestConvoyHandler_NonFatalErrors(t *testing.T) {
 		},
 		MergeQueueError: errFetchFailed,
 		PolecatsError:   errFetchFailed,
+		RigHealthError:  errFetchFailed,
 	}
 
 	handler, err := NewConvoyHandler(mock)
@@ -995,3 +1006,29 @@ func TestConvoyHandler_NonFatalErrors(t *testing.T) {
 		t.Error("Response should contain convoy data even when other fetches fail")
 	}
 }
+
+func TestConvoyHandler_RigHealthSection(t *testing.T) {
+	mock := &MockConvoyFetcher{
+		Convoys: []ConvoyRow{},
+		RigHealth: []RigHealthRow{
+			{Rig: "gastown", Score: 45, Grade: "critical", Trend: "↓", Summary: "3/4 merges failed", ColorClass: "mq-red"},
+		},
+	}
+
+	handler, err := NewConvoyHandler(mock)
+	if err != nil {
+		t.Fatalf("NewConvoyHandler() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	for _, want := range []string{"Rig Health", "gastown", "45 ↓", "critical", "3/4 merges failed", "mq-red"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Response should contain %q", want)
+		}
+	}
+}
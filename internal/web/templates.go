@@ -17,6 +17,17 @@ type ConvoyData struct {
 	Convoys    []ConvoyRow
 	MergeQueue []MergeQueueRow
 	Polecats   []PolecatRow
+	RigHealth  []RigHealthRow
+}
+
+// RigHealthRow represents a rig's health score in the dashboard.
+type RigHealthRow struct {
+	Rig        string
+	Score      int    // 0-100
+	Grade      string // "healthy", "degraded", "critical"
+	Trend      string // "↑", "↓", "→"
+	Summary    string // e.g., "2/5 merges failed, 1 death"
+	ColorClass string // "mq-green", "mq-yellow", "mq-red"
 }
 
 // PolecatRow represents a polecat worker in the dashboard.
@@ -13,9 +13,13 @@ import (
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/lifecycle"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -25,6 +29,9 @@ var (
 	ErrAlreadyRunning = errors.New("witness already running")
 )
 
+// logger records session starts and stops.
+var logger = gtlog.For("witness")
+
 // Manager handles witness lifecycle and monitoring operations.
 type Manager struct {
 	rig          *rig.Rig
@@ -136,6 +143,7 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
+		logger.Warn("killing zombie session", "rig", m.rig.Name, "session", sessionID)
 		if err := t.KillSession(sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
@@ -169,12 +177,21 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		return err
 	}
 
+	// Run pre-spawn lifecycle hooks; a failure aborts the start
+	hookAgent := m.lifecycleAgent(witnessDir)
+	if err := lifecycle.Run(lifecycle.PreSpawn, hookAgent); err != nil {
+		return fmt.Errorf("pre-spawn hook: %w", err)
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, witnessDir, command); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	// Record the session's output for the transcript archive (non-fatal)
+	_ = transcript.Record(t, townRoot, sessionID, m.rig.Name+"/witness")
+
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	envVars := config.AgentEnv(config.AgentEnvConfig{
@@ -218,8 +235,10 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		return fmt.Errorf("waiting for witness to start: %w", err)
 	}
 
-	// Accept bypass permissions warning dialog if it appears.
-	_ = t.AcceptBypassPermissionsWarning(sessionID)
+	// Accept runtime startup dialogs (e.g., bypass permissions warning) if they appear.
+	if runtimeConfig, err := config.ResolveRoleAgentConfigWithOverride("witness", townRoot, m.rig.Path, agentOverride); err == nil {
+		_ = runtime.AcceptStartupPrompts(t, sessionID, runtimeConfig)
+	}
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
@@ -237,9 +256,23 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	time.Sleep(2 * time.Second)
 	_ = t.NudgeSession(sessionID, session.PropulsionNudgeForRole("witness", witnessDir)) // Non-fatal
 
+	lifecycle.Warn(lifecycle.PostStart, hookAgent)
+	logger.Info("session started", "rig", m.rig.Name, "session", sessionID, "agent", agentOverride)
 	return nil
 }
 
+// lifecycleAgent describes the witness for lifecycle hooks.
+func (m *Manager) lifecycleAgent(workDir string) lifecycle.Agent {
+	return lifecycle.Agent{
+		TownRoot: m.townRoot(),
+		RigPath:  m.rig.Path,
+		Role:     "witness",
+		Rig:      m.rig.Name,
+		Session:  m.SessionName(),
+		WorkDir:  workDir,
+	}
+}
+
 func (m *Manager) roleConfig() (*beads.RoleConfig, error) {
 	// Role beads use hq- prefix and live in town-level beads, not rig beads
 	townRoot := m.townRoot()
@@ -303,7 +336,9 @@ func (m *Manager) Stop() error {
 
 	// Kill tmux session if it exists (best-effort: may already be dead)
 	if sessionRunning {
+		lifecycle.Warn(lifecycle.PreKill, m.lifecycleAgent(m.witnessDir()))
 		_ = t.KillSession(sessionID)
+		logger.Info("session stopped", "rig", m.rig.Name, "session", sessionID)
 	}
 
 	// Note: No PID-based stop per ZFC - tmux session kill is sufficient
@@ -0,0 +1,290 @@
+package witness
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Patrol report files live under <rig>/witness/patrol/.
+const (
+	currentReportFile = "current.json"  // patrol in progress
+	reportsFile       = "reports.jsonl" // completed patrols, oldest first
+)
+
+// MaxPatrolReports is how many completed reports a rig keeps.
+const MaxPatrolReports = 200
+
+// Patrol action kinds.
+const (
+	ActionNudge    = "nudge"
+	ActionEscalate = "escalate"
+)
+
+// PatrolReport is the structured record of one witness patrol cycle: what
+// each polecat was doing, what looked wrong, and what the witness did about
+// it. Consumers summarize patrols from reports instead of replaying the
+// individual patrol events.
+type PatrolReport struct {
+	Rig         string          `json:"rig"`
+	StartedAt   time.Time       `json:"started_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Polecats    []PolecatReport `json:"polecats,omitempty"`
+	Actions     []PatrolAction  `json:"actions,omitempty"`
+	Summary     string          `json:"summary,omitempty"` // witness's own closing message
+}
+
+// PolecatReport is a polecat's state as observed during a patrol.
+type PolecatReport struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`              // working, idle, stuck, ...
+	Issue     string `json:"issue,omitempty"`     // hooked issue
+	Diagnosis string `json:"diagnosis,omitempty"` // why it looks stuck or idle
+}
+
+// PatrolAction is something the witness did during a patrol.
+type PatrolAction struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`         // ActionNudge or ActionEscalate
+	Target string    `json:"target"`       // polecat acted on
+	To     string    `json:"to,omitempty"` // escalation recipient
+	Reason string    `json:"reason,omitempty"`
+}
+
+// PatrolDir returns the directory holding a rig's patrol reports.
+func PatrolDir(rigPath string) string {
+	return filepath.Join(rigPath, "witness", "patrol")
+}
+
+// BeginPatrol starts a new in-progress report, discarding any unfinished one.
+func BeginPatrol(rigPath, rigName string, now time.Time) error {
+	return saveCurrent(rigPath, &PatrolReport{Rig: rigName, StartedAt: now.UTC()})
+}
+
+// RecordPolecat adds or replaces a polecat's entry in the in-progress report.
+// A check with no diagnosis keeps one recorded earlier in the patrol.
+func RecordPolecat(rigPath, rigName string, p PolecatReport) error {
+	report, err := loadCurrent(rigPath, rigName)
+	if err != nil {
+		return err
+	}
+	for i := range report.Polecats {
+		if report.Polecats[i].Name == p.Name {
+			if p.Diagnosis == "" {
+				p.Diagnosis = report.Polecats[i].Diagnosis
+			}
+			report.Polecats[i] = p
+			return saveCurrent(rigPath, report)
+		}
+	}
+	report.Polecats = append(report.Polecats, p)
+	return saveCurrent(rigPath, report)
+}
+
+// RecordAction appends an action to the in-progress report. A nudge or
+// escalation reason becomes the target's diagnosis if it has none.
+func RecordAction(rigPath, rigName string, a PatrolAction) error {
+	report, err := loadCurrent(rigPath, rigName)
+	if err != nil {
+		return err
+	}
+	a.Time = a.Time.UTC()
+	report.Actions = append(report.Actions, a)
+	for i := range report.Polecats {
+		if report.Polecats[i].Name == a.Target && report.Polecats[i].Diagnosis == "" {
+			report.Polecats[i].Diagnosis = a.Reason
+		}
+	}
+	return saveCurrent(rigPath, report)
+}
+
+// CompletePatrol finishes the in-progress report and appends it to the
+// rig's report history, returning the completed report.
+func CompletePatrol(rigPath, rigName, summary string, now time.Time) (*PatrolReport, error) {
+	report, err := loadCurrent(rigPath, rigName)
+	if err != nil {
+		return nil, err
+	}
+	completed := now.UTC()
+	report.CompletedAt = &completed
+	report.Summary = summary
+
+	reports, err := ReadReports(rigPath, 0)
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, *report)
+	if len(reports) > MaxPatrolReports {
+		reports = reports[len(reports)-MaxPatrolReports:]
+	}
+
+	var data []byte
+	for _, r := range reports {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return nil, fmt.Errorf("encoding patrol report: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	if err := os.MkdirAll(PatrolDir(rigPath), 0755); err != nil {
+		return nil, fmt.Errorf("creating patrol dir: %w", err)
+	}
+	if err := util.AtomicWriteFile(filepath.Join(PatrolDir(rigPath), reportsFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("writing patrol reports: %w", err)
+	}
+	if err := os.Remove(filepath.Join(PatrolDir(rigPath), currentReportFile)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// CurrentPatrol returns the in-progress report, or nil if no patrol is running.
+func CurrentPatrol(rigPath string) (*PatrolReport, error) {
+	data, err := os.ReadFile(filepath.Join(PatrolDir(rigPath), currentReportFile)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var report PatrolReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parsing current patrol report: %w", err)
+	}
+	return &report, nil
+}
+
+// ReadReports returns completed reports, newest last. limit > 0 keeps only
+// the most recent limit reports. Malformed lines are skipped.
+func ReadReports(rigPath string, limit int) ([]PatrolReport, error) {
+	f, err := os.Open(filepath.Join(PatrolDir(rigPath), reportsFile)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reports []PatrolReport
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r PatrolReport
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		reports = append(reports, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(reports) > limit {
+		reports = reports[len(reports)-limit:]
+	}
+	return reports, nil
+}
+
+// LatestReport returns the most recent completed report, or nil if the rig
+// has none.
+func LatestReport(rigPath string) (*PatrolReport, error) {
+	reports, err := ReadReports(rigPath, 1)
+	if err != nil || len(reports) == 0 {
+		return nil, err
+	}
+	return &reports[0], nil
+}
+
+// StatusCounts returns how many polecats were seen in each status.
+func (r *PatrolReport) StatusCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, p := range r.Polecats {
+		counts[p.Status]++
+	}
+	return counts
+}
+
+// Stuck returns the polecats the patrol found stuck.
+func (r *PatrolReport) Stuck() []PolecatReport {
+	var stuck []PolecatReport
+	for _, p := range r.Polecats {
+		if p.Status == "stuck" {
+			stuck = append(stuck, p)
+		}
+	}
+	return stuck
+}
+
+// Headline summarizes the patrol in one line, e.g.
+// "3 polecats (2 working, 1 stuck); 1 nudge".
+func (r *PatrolReport) Headline() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d polecat%s", len(r.Polecats), plural(len(r.Polecats)))
+
+	counts := r.StatusCounts()
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	var parts []string
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(parts, ", "))
+	}
+
+	var nudges, escalations int
+	for _, a := range r.Actions {
+		switch a.Kind {
+		case ActionNudge:
+			nudges++
+		case ActionEscalate:
+			escalations++
+		}
+	}
+	if nudges > 0 {
+		fmt.Fprintf(&b, "; %d nudge%s", nudges, plural(nudges))
+	}
+	if escalations > 0 {
+		fmt.Fprintf(&b, "; %d escalation%s", escalations, plural(escalations))
+	}
+	return b.String()
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// loadCurrent returns the in-progress report, starting one if the witness
+// skipped patrol_started.
+func loadCurrent(rigPath, rigName string) (*PatrolReport, error) {
+	report, err := CurrentPatrol(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		report = &PatrolReport{Rig: rigName, StartedAt: time.Now().UTC()}
+	}
+	return report, nil
+}
+
+func saveCurrent(rigPath string, report *PatrolReport) error {
+	if err := os.MkdirAll(PatrolDir(rigPath), 0755); err != nil {
+		return fmt.Errorf("creating patrol dir: %w", err)
+	}
+	return util.AtomicWriteJSON(filepath.Join(PatrolDir(rigPath), currentReportFile), report)
+}
@@ -0,0 +1,84 @@
+package witness
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPatrolReportLifecycle(t *testing.T) {
+	rigPath := t.TempDir()
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	if report, err := LatestReport(rigPath); err != nil || report != nil {
+		t.Fatalf("LatestReport before any patrol = %v, %v", report, err)
+	}
+
+	if err := BeginPatrol(rigPath, "gastown", now); err != nil {
+		t.Fatalf("BeginPatrol: %v", err)
+	}
+	if err := RecordPolecat(rigPath, "gastown", PolecatReport{Name: "Toast", Status: "working", Issue: "gt-abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordPolecat(rigPath, "gastown", PolecatReport{Name: "Nux", Status: "stuck", Issue: "gt-def"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordAction(rigPath, "gastown", PatrolAction{Time: now, Kind: ActionNudge, Target: "Nux", Reason: "no output for 30m"}); err != nil {
+		t.Fatal(err)
+	}
+	// A later check without a diagnosis keeps the earlier one
+	if err := RecordPolecat(rigPath, "gastown", PolecatReport{Name: "Nux", Status: "stuck", Issue: "gt-def"}); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := CurrentPatrol(rigPath)
+	if err != nil || current == nil {
+		t.Fatalf("CurrentPatrol = %v, %v", current, err)
+	}
+	if len(current.Polecats) != 2 {
+		t.Fatalf("Polecats = %+v, want 2", current.Polecats)
+	}
+
+	completed, err := CompletePatrol(rigPath, "gastown", "one stuck", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CompletePatrol: %v", err)
+	}
+	if completed.CompletedAt == nil || !completed.CompletedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("CompletedAt = %v", completed.CompletedAt)
+	}
+	if current, _ := CurrentPatrol(rigPath); current != nil {
+		t.Error("current patrol should be cleared after completion")
+	}
+
+	latest, err := LatestReport(rigPath)
+	if err != nil || latest == nil {
+		t.Fatalf("LatestReport = %v, %v", latest, err)
+	}
+	if got, want := latest.Headline(), "2 polecats (1 stuck, 1 working); 1 nudge"; got != want {
+		t.Errorf("Headline = %q, want %q", got, want)
+	}
+	stuck := latest.Stuck()
+	if len(stuck) != 1 || stuck[0].Name != "Nux" || stuck[0].Diagnosis != "no output for 30m" {
+		t.Errorf("Stuck = %+v", stuck)
+	}
+}
+
+func TestReadReportsLimit(t *testing.T) {
+	rigPath := t.TempDir()
+	start := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if _, err := CompletePatrol(rigPath, "gastown", "", start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := ReadReports(rigPath, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(reports))
+	}
+	if !reports[1].CompletedAt.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("newest report completed %v, want last", reports[1].CompletedAt)
+	}
+}
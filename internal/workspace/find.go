@@ -29,42 +29,14 @@ const (
 // Find locates the town root by walking up from the given directory.
 // It prefers mayor/town.json over mayor/ directory as workspace marker.
 // When in a worktree path (polecats/ or crew/), continues to outermost workspace.
+// GT_TOWN_ROOT takes precedence when it contains startDir; see Resolve.
 // Does not resolve symlinks to stay consistent with os.Getwd().
 func Find(startDir string) (string, error) {
-	absDir, err := filepath.Abs(startDir)
+	res, err := Resolve(startDir)
 	if err != nil {
-		return "", fmt.Errorf("resolving path: %w", err)
-	}
-
-	inWorktree := isInWorktreePath(absDir)
-	var primaryMatch, secondaryMatch string
-
-	current := absDir
-	for {
-		if _, err := os.Stat(filepath.Join(current, PrimaryMarker)); err == nil {
-			if !inWorktree {
-				return current, nil
-			}
-			primaryMatch = current
-		}
-
-		// Always keep updating secondaryMatch to find the outermost mayor/ directory.
-		// This handles nested structures where rigs have their own mayor/ directories
-		// but only the town root should be detected as the workspace.
-		// The primary marker (mayor/town.json) is authoritative and returns early above.
-		if info, err := os.Stat(filepath.Join(current, SecondaryMarker)); err == nil && info.IsDir() {
-			secondaryMatch = current
-		}
-
-		parent := filepath.Dir(current)
-		if parent == current {
-			if primaryMatch != "" {
-				return primaryMatch, nil
-			}
-			return secondaryMatch, nil
-		}
-		current = parent
+		return "", err
 	}
+	return res.Root, nil
 }
 
 func isInWorktreePath(path string) bool {
@@ -85,29 +57,16 @@ func FindOrError(startDir string) (string, error) {
 }
 
 // FindFromCwd locates the town root from the current working directory.
+// If getcwd fails (e.g., worktree deleted), falls back to GT_TOWN_ROOT env var.
 func FindFromCwd() (string, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("getting current directory: %w", err)
-	}
-	return Find(cwd)
+	townRoot, _, err := findFromCwd()
+	return townRoot, err
 }
 
 // FindFromCwdOrError is like FindFromCwd but returns an error if not found.
-// If getcwd fails (e.g., worktree deleted), falls back to GT_TOWN_ROOT env var.
 func FindFromCwdOrError() (string, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		// Fallback: try GT_TOWN_ROOT env var (set by polecat sessions)
-		if townRoot := os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
-			// Verify it's actually a workspace
-			if _, statErr := os.Stat(filepath.Join(townRoot, PrimaryMarker)); statErr == nil {
-				return townRoot, nil
-			}
-		}
-		return "", fmt.Errorf("getting current directory: %w", err)
-	}
-	return FindOrError(cwd)
+	townRoot, _, err := FindFromCwdWithFallback()
+	return townRoot, err
 }
 
 // FindFromCwdWithFallback is like FindFromCwdOrError but returns (townRoot, cwd, error).
@@ -115,23 +74,32 @@ func FindFromCwdOrError() (string, error) {
 // This is useful for commands like `gt done` that need to continue even if the
 // working directory is deleted (e.g., polecat worktree nuked by Witness).
 func FindFromCwdWithFallback() (townRoot string, cwd string, err error) {
-	cwd, err = os.Getwd()
+	townRoot, cwd, err = findFromCwd()
 	if err != nil {
-		// Fallback: try GT_TOWN_ROOT env var
-		if townRoot = os.Getenv("GT_TOWN_ROOT"); townRoot != "" {
-			// Verify it's actually a workspace
-			if _, statErr := os.Stat(filepath.Join(townRoot, PrimaryMarker)); statErr == nil {
-				return townRoot, "", nil // cwd is gone but townRoot is valid
-			}
-		}
-		return "", "", fmt.Errorf("getting current directory: %w", err)
+		return "", "", err
 	}
+	if townRoot == "" {
+		return "", "", ErrNotFound
+	}
+	return townRoot, cwd, nil
+}
 
-	townRoot, err = FindOrError(cwd)
+// findFromCwd resolves the town root for the working directory, returning
+// the directory too ("" if getcwd failed). Getting no root is not an error
+// unless getcwd failed.
+func findFromCwd() (townRoot, cwd string, err error) {
+	cwd, cwdErr := os.Getwd()
+	if cwdErr != nil {
+		cwd = ""
+	}
+	res, err := Resolve(cwd)
 	if err != nil {
 		return "", "", err
 	}
-	return townRoot, cwd, nil
+	if res.Root == "" && cwdErr != nil {
+		return "", "", fmt.Errorf("getting current directory: %w", cwdErr)
+	}
+	return res.Root, cwd, nil
 }
 
 // IsWorkspace checks if the given directory is a Gas Town workspace root.
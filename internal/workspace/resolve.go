@@ -0,0 +1,217 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TownRootEnv names the environment variable that pins the town root.
+// Shell integration and agent sessions set it.
+const TownRootEnv = "GT_TOWN_ROOT"
+
+// Sources of a resolved town root.
+const (
+	SourceEnv  = "env"  // GT_TOWN_ROOT
+	SourceWalk = "walk" // walking up from the start directory
+)
+
+// Candidate is a directory with a workspace marker found while walking up
+// from the start directory.
+type Candidate struct {
+	// Path is the directory containing the marker.
+	Path string `json:"path"`
+
+	// Marker is PrimaryMarker or SecondaryMarker.
+	Marker string `json:"marker"`
+
+	// Chosen is true for the candidate that became the town root.
+	Chosen bool `json:"chosen"`
+}
+
+// Resolution explains how a town root was chosen. Find, FindFromCwd and
+// friends return only Root; 'gt workspace which --explain' shows the rest.
+type Resolution struct {
+	// Root is the chosen town root, or "" if none was found.
+	Root string `json:"root"`
+
+	// Source is SourceEnv or SourceWalk, or "" if no root was found.
+	Source string `json:"source,omitempty"`
+
+	// StartDir is the directory discovery started from. Empty if the
+	// working directory could not be determined.
+	StartDir string `json:"start_dir,omitempty"`
+
+	// EnvRoot is the value of GT_TOWN_ROOT, if set.
+	EnvRoot string `json:"env_root,omitempty"`
+
+	// InWorktree is true if StartDir is inside a polecats/ or crew/ worktree.
+	InWorktree bool `json:"in_worktree"`
+
+	// Nested is true if more than one town (mayor/town.json) encloses StartDir.
+	Nested bool `json:"nested"`
+
+	// Candidates lists every marker found, innermost first.
+	Candidates []Candidate `json:"candidates"`
+
+	// Reasons explains each decision, in order.
+	Reasons []string `json:"reasons"`
+}
+
+func (r *Resolution) explain(format string, args ...interface{}) {
+	r.Reasons = append(r.Reasons, fmt.Sprintf(format, args...))
+}
+
+// Resolve determines the town root for startDir, recording why.
+//
+// The rules, in order:
+//  1. If GT_TOWN_ROOT names a town (has mayor/town.json) and startDir is
+//     inside it, or startDir is empty (cwd unavailable), it wins.
+//  2. Otherwise walk up from startDir collecting markers. Outside a worktree
+//     the innermost mayor/town.json wins, so a town nested inside another
+//     resolves to itself.
+//  3. Inside a polecats/ or crew/ worktree, the outermost mayor/town.json
+//     wins: a rig checkout may carry its own town files, but the agent
+//     belongs to the town that contains the worktree.
+//  4. With no mayor/town.json, the outermost mayor/ directory wins (rigs can
+//     have their own mayor/ directories).
+//
+// Symlinks are not resolved, to stay consistent with os.Getwd().
+func Resolve(startDir string) (*Resolution, error) {
+	res := &Resolution{Candidates: []Candidate{}, Reasons: []string{}}
+
+	if startDir != "" {
+		absDir, err := filepath.Abs(startDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolving path: %w", err)
+		}
+		res.StartDir = absDir
+		res.InWorktree = isInWorktreePath(absDir)
+	}
+
+	if envRoot := os.Getenv(TownRootEnv); envRoot != "" {
+		res.EnvRoot = envRoot
+	}
+
+	if res.StartDir != "" {
+		collectCandidates(res)
+	}
+
+	if res.EnvRoot != "" {
+		switch {
+		case !hasPrimaryMarker(res.EnvRoot):
+			res.explain("%s=%s ignored: no %s there", TownRootEnv, res.EnvRoot, PrimaryMarker)
+		case res.StartDir == "":
+			res.explain("working directory unavailable; using %s", TownRootEnv)
+			return res.choose(res.EnvRoot, SourceEnv), nil
+		case isWithin(res.StartDir, res.EnvRoot):
+			res.explain("%s contains the start directory; using it", TownRootEnv)
+			return res.choose(res.EnvRoot, SourceEnv), nil
+		default:
+			res.explain("%s=%s ignored: start directory is outside it", TownRootEnv, res.EnvRoot)
+		}
+	}
+
+	if res.StartDir == "" {
+		res.explain("working directory unavailable and no usable %s", TownRootEnv)
+		return res, nil
+	}
+
+	var primaries, secondaries []string
+	for _, c := range res.Candidates {
+		if c.Marker == PrimaryMarker {
+			primaries = append(primaries, c.Path)
+		} else {
+			secondaries = append(secondaries, c.Path)
+		}
+	}
+	res.Nested = len(primaries) > 1
+
+	switch {
+	case len(primaries) > 0 && res.InWorktree:
+		root := primaries[len(primaries)-1]
+		if res.Nested {
+			res.explain("inside a polecats/ or crew/ worktree; the outermost town wins over %d nested", len(primaries)-1)
+		} else {
+			res.explain("found %s", PrimaryMarker)
+		}
+		return res.choose(root, SourceWalk), nil
+	case len(primaries) > 0:
+		if res.Nested {
+			res.explain("not in a worktree; the innermost of %d nested towns wins", len(primaries))
+		} else {
+			res.explain("found %s", PrimaryMarker)
+		}
+		return res.choose(primaries[0], SourceWalk), nil
+	case len(secondaries) > 0:
+		res.explain("no %s found; the outermost %s/ directory wins", PrimaryMarker, SecondaryMarker)
+		return res.choose(secondaries[len(secondaries)-1], SourceWalk), nil
+	}
+
+	res.explain("no workspace markers above %s", res.StartDir)
+	return res, nil
+}
+
+// collectCandidates records every workspace marker from StartDir up to the
+// filesystem root, innermost first.
+func collectCandidates(res *Resolution) {
+	current := res.StartDir
+	for {
+		if hasPrimaryMarker(current) {
+			res.Candidates = append(res.Candidates, Candidate{Path: current, Marker: PrimaryMarker})
+		} else if info, err := os.Stat(filepath.Join(current, SecondaryMarker)); err == nil && info.IsDir() {
+			res.Candidates = append(res.Candidates, Candidate{Path: current, Marker: SecondaryMarker})
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return
+		}
+		current = parent
+	}
+}
+
+func (r *Resolution) choose(root, source string) *Resolution {
+	r.Root = root
+	r.Source = source
+	for i := range r.Candidates {
+		r.Candidates[i].Chosen = r.Candidates[i].Path == root
+	}
+	return r
+}
+
+func hasPrimaryMarker(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, PrimaryMarker))
+	return err == nil
+}
+
+// isWithin reports whether path is root or below it. Paths are compared as
+// given and, failing that, with symlinks resolved.
+func isWithin(path, root string) bool {
+	if pathHasPrefix(path, root) {
+		return true
+	}
+	realPath, err1 := filepath.EvalSymlinks(path)
+	realRoot, err2 := filepath.EvalSymlinks(root)
+	return err1 == nil && err2 == nil && pathHasPrefix(realPath, realRoot)
+}
+
+func pathHasPrefix(path, root string) bool {
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// ResolveFromCwd is Resolve for the current working directory. If the
+// working directory cannot be determined (e.g., a deleted worktree),
+// resolution relies on GT_TOWN_ROOT.
+func ResolveFromCwd() (*Resolution, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = ""
+	}
+	return Resolve(cwd)
+}
@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeTown(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, PrimaryMarker), []byte(`{"name":"town"}`), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestResolveNestedTowns(t *testing.T) {
+	t.Setenv(TownRootEnv, "")
+	outer := realPath(t, t.TempDir())
+	makeTown(t, outer)
+	inner := filepath.Join(outer, "projects", "inner")
+	makeTown(t, inner)
+	workDir := filepath.Join(inner, "myrig", "mayor", "rig")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	res, err := Resolve(workDir)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.Root != inner || res.Source != SourceWalk {
+		t.Errorf("Root = %q via %q, want %q via walk", res.Root, res.Source, inner)
+	}
+	if !res.Nested {
+		t.Error("expected nested towns to be detected")
+	}
+	if len(res.Candidates) != 3 {
+		t.Fatalf("Candidates = %+v, want rig mayor/, inner and outer", res.Candidates)
+	}
+	for _, c := range res.Candidates {
+		if c.Chosen != (c.Path == inner) {
+			t.Errorf("candidate %s chosen = %v", c.Path, c.Chosen)
+		}
+	}
+	if len(res.Reasons) == 0 {
+		t.Error("expected reasons to be recorded")
+	}
+}
+
+func TestResolveNestedTownInWorktree(t *testing.T) {
+	t.Setenv(TownRootEnv, "")
+	outer := realPath(t, t.TempDir())
+	makeTown(t, outer)
+	inner := filepath.Join(outer, "projects", "inner")
+	makeTown(t, inner)
+	polecatDir := filepath.Join(inner, "myrig", "polecats", "worker")
+	makeTown(t, polecatDir)
+
+	res, err := Resolve(polecatDir)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.Root != outer || !res.InWorktree || !res.Nested {
+		t.Errorf("Resolve = %+v, want outermost town from worktree", res)
+	}
+}
+
+func TestResolveEnvOverride(t *testing.T) {
+	outer := realPath(t, t.TempDir())
+	makeTown(t, outer)
+	inner := filepath.Join(outer, "inner")
+	makeTown(t, inner)
+
+	t.Setenv(TownRootEnv, outer)
+	root, err := Find(inner)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if root != outer {
+		t.Errorf("Find = %q, want %s=%q to win", root, TownRootEnv, outer)
+	}
+
+	// A stale GT_TOWN_ROOT pointing elsewhere is ignored.
+	other := realPath(t, t.TempDir())
+	makeTown(t, other)
+	t.Setenv(TownRootEnv, other)
+	res, err := Resolve(inner)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.Root != inner || res.Source != SourceWalk {
+		t.Errorf("Root = %q via %q, want %q via walk", res.Root, res.Source, inner)
+	}
+
+	// Without a working directory, a valid GT_TOWN_ROOT is used.
+	res, err = Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.Root != other || res.Source != SourceEnv {
+		t.Errorf("Root = %q via %q, want %q via env", res.Root, res.Source, other)
+	}
+
+	// GT_TOWN_ROOT without mayor/town.json is ignored.
+	t.Setenv(TownRootEnv, t.TempDir())
+	if res, _ := Resolve(""); res.Root != "" {
+		t.Errorf("Root = %q, want none for invalid %s", res.Root, TownRootEnv)
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	sep := string(filepath.Separator)
+	tests := []struct {
+		path, root string
+		want       bool
+	}{
+		{sep + "gt", sep + "gt", true},
+		{sep + filepath.Join("gt", "rig"), sep + "gt", true},
+		{sep + "gtx", sep + "gt", false},
+		{sep + "other", sep + "gt", false},
+	}
+	for _, tt := range tests {
+		if got := isWithin(tt.path, tt.root); got != tt.want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", tt.path, tt.root, got, tt.want)
+		}
+	}
+}